@@ -13,11 +13,16 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
 	"router-sim/internal/analytics"
+	"router-sim/internal/apispec"
 	"router-sim/internal/aviatrix"
 	"router-sim/internal/cloudpods"
 	"router-sim/internal/config"
 	"router-sim/internal/handlers"
+	"router-sim/internal/metrics"
+	"router-sim/internal/partition"
+	routerserver "router-sim/internal/server"
 )
 
 var upgrader = websocket.Upgrader{
@@ -39,6 +44,22 @@ func main() {
 		log.Fatalf("Failed to initialize analytics engine: %v", err)
 	}
 
+	// ClickHouse-backed analytics is optional infrastructure: most
+	// deployments run without it, so a connection failure here is a
+	// warning rather than a fatal error, and /analytics/series answers
+	// 503 until it's configured.
+	var clickhouseClient *analytics.ClickHouseClient
+	if cfg.Analytics.Enabled {
+		ch := cfg.Analytics.ClickHouse
+		dsn := fmt.Sprintf("tcp://%s:%d?database=%s&username=%s&password=%s", ch.Host, ch.Port, ch.Database, ch.Username, ch.Password)
+		clickhouseClient, err = analytics.NewClickHouseClient(dsn, zap.NewNop())
+		if err != nil {
+			log.Printf("ClickHouse-backed analytics disabled: %v", err)
+		} else {
+			defer clickhouseClient.Close()
+		}
+	}
+
 	// Initialize CloudPods client
 	cloudpodsClient, err := cloudpods.NewClient(cfg.CloudPods)
 	if err != nil {
@@ -52,7 +73,10 @@ func main() {
 	}
 
 	// Initialize handlers
-	handlers := handlers.New(analyticsEngine, aviatrixClient, cloudpodsClient)
+	handlers, err := handlers.New(analyticsEngine, clickhouseClient, aviatrixClient, cloudpodsClient, cfg.Server.CaptureDir, cfg.Server.CaptureMaxFileSize, cfg.Server.AuditDir, cfg.Server.AuditMaxFileSize, cfg.Server.AuditRetention)
+	if err != nil {
+		log.Fatalf("Failed to initialize handlers: %v", err)
+	}
 
 	// Setup Gin router
 	if cfg.Server.Environment == "production" {
@@ -61,51 +85,70 @@ func main() {
 
 	router := gin.Default()
 
+	// Every request gets an OTel span (propagating an incoming
+	// traceparent rather than always starting a new trace) and is
+	// counted/timed against the shared Prometheus collectors /metrics
+	// below serves.
+	router.Use(handlers.TracingMiddleware())
+	router.Use(metrics.GinMiddleware())
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
 	// CORS middleware
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
-		
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, "+partition.HeaderName+", "+partition.SubjectHeaderName)
+
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
 		}
-		
+
 		c.Next()
 	})
 
 	// API routes
 	api := router.Group("/api/v1")
 	{
+		// Partitions are created here, before the scoping middleware, so a
+		// brand-new tenant can be admitted without already holding a valid
+		// partition.
+		partitionSpec := apispec.NewBuilder()
+		handlers.RegisterPartitionRoutes(partitionSpec)
+		partitionSpec.Mount(api)
+
+		// Every route below this point is scoped to the partition named by
+		// the X-Router-Partition header (or "default"); unknown partitions
+		// and unauthorized subjects are rejected before reaching a handler.
+		api.Use(partition.Middleware(handlers.Partitions()))
+
 		// Analytics routes
 		api.GET("/analytics/metrics", handlers.GetMetrics)
 		api.GET("/analytics/dashboard", handlers.GetDashboard)
 		api.POST("/analytics/query", handlers.QueryAnalytics)
-		
-		// CloudPods routes
-		api.GET("/cloudpods/status", handlers.GetCloudPodsStatus)
-		api.GET("/cloudpods/resources", handlers.GetCloudPodsResources)
-		api.POST("/cloudpods/deploy", handlers.DeployCloudPods)
-		api.DELETE("/cloudpods/cleanup", handlers.CleanupCloudPods)
-		
-		// Aviatrix routes
-		api.GET("/aviatrix/status", handlers.GetAviatrixStatus)
-		api.GET("/aviatrix/gateways", handlers.GetAviatrixGateways)
-		api.POST("/aviatrix/deploy", handlers.DeployAviatrix)
-		api.DELETE("/aviatrix/cleanup", handlers.CleanupAviatrix)
-		
-		// Router simulation routes
-		api.GET("/router/status", handlers.GetRouterStatus)
-		api.GET("/router/routes", handlers.GetRoutes)
-		api.GET("/router/neighbors", handlers.GetNeighbors)
-		api.POST("/router/scenario", handlers.LoadScenario)
-		api.POST("/router/impairment", handlers.ApplyImpairment)
-		
-		// Testing routes
-		api.POST("/test/capture", handlers.StartCapture)
-		api.POST("/test/compare", handlers.ComparePCAPs)
-		api.GET("/test/results", handlers.GetTestResults)
+
+		// CloudPods, Aviatrix, router-simulation, and testing routes are
+		// registered through apispec so each endpoint's input is a typed
+		// struct and the resulting OpenAPI document always matches what is
+		// actually mounted.
+		spec := apispec.NewBuilder()
+		handlers.RegisterCloudPodsRoutes(spec)
+		handlers.RegisterAviatrixRoutes(spec)
+		handlers.RegisterRouterRoutes(spec)
+		handlers.RegisterTestingRoutes(spec)
+		handlers.RegisterStreamRoutes(spec)
+		handlers.RegisterAnalyticsStreamRoutes(spec)
+		handlers.RegisterDebugzRoutes(spec)
+		handlers.RegisterAnalyticsRoutes(spec)
+		handlers.RegisterEventsRoutes(spec)
+		handlers.RegisterJobRoutes(spec)
+		routerserver.RegisterProtocolRoutes(spec)
+		routerserver.RegisterTrafficRoutes(spec)
+		spec.Mount(api)
+
+		api.GET("/openapi.json", func(c *gin.Context) {
+			c.JSON(http.StatusOK, spec.OpenAPI("router-sim API", "v1"))
+		})
 	}
 
 	// WebSocket endpoint for real-time updates
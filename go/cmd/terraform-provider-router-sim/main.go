@@ -0,0 +1,16 @@
+// Command terraform-provider-router-sim is the Terraform plugin binary for
+// the router-sim provider, serving the schema.Provider built in
+// router-sim/pkg/terraform/routersim over the plugin protocol.
+package main
+
+import (
+	"router-sim/pkg/terraform/routersim"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+)
+
+func main() {
+	plugin.Serve(&plugin.ServeOpts{
+		ProviderFunc: routersim.Provider,
+	})
+}
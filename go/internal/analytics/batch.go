@@ -0,0 +1,300 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DeadLetterFunc is called once a batch has exhausted BatchConfig's
+// retries without succeeding. rows is the concrete []T slice for
+// whichever table failed (RouterMetrics, ProtocolMetrics, ...); it is
+// passed as interface{} since a single callback has to serve every
+// table's batchBuffer[T].
+type DeadLetterFunc func(table string, rows interface{}, err error)
+
+// BatchConfig tunes ClickHouseClient's per-table ingestion pipeline.
+type BatchConfig struct {
+	// BatchSize is how many enqueued rows trigger a flush; a table also
+	// flushes on FlushInterval regardless of how few rows have queued.
+	BatchSize int
+	// FlushInterval bounds how long a row can sit buffered before being
+	// flushed, even if BatchSize hasn't been reached.
+	FlushInterval time.Duration
+	// MaxInFlight bounds how many batches (across every table) may be
+	// mid-flush at once; a table's collector blocks acquiring a slot
+	// once this is saturated, which backpressures into its queue filling
+	// up and Insert* starting to report rows dropped.
+	MaxInFlight int
+	// MaxRetries bounds how many times a batch is retried (with
+	// exponential backoff) on a transient error before it is handed to
+	// DeadLetter and counted as dropped.
+	MaxRetries int
+	// DeadLetter, if set, is called once per batch that exhausts
+	// MaxRetries without succeeding.
+	DeadLetter DeadLetterFunc
+}
+
+func defaultBatchConfig() BatchConfig {
+	return BatchConfig{
+		BatchSize:     500,
+		FlushInterval: 2 * time.Second,
+		MaxInFlight:   4,
+		MaxRetries:    5,
+	}
+}
+
+// ClickHouseOption configures a ClickHouseClient's batching pipeline at
+// construction, the same functional-options shape ControllerClientOption
+// uses in the aviatrix package.
+type ClickHouseOption func(*BatchConfig)
+
+func WithBatchSize(n int) ClickHouseOption {
+	return func(cfg *BatchConfig) { cfg.BatchSize = n }
+}
+
+func WithFlushInterval(d time.Duration) ClickHouseOption {
+	return func(cfg *BatchConfig) { cfg.FlushInterval = d }
+}
+
+func WithMaxInFlight(n int) ClickHouseOption {
+	return func(cfg *BatchConfig) { cfg.MaxInFlight = n }
+}
+
+func WithMaxRetries(n int) ClickHouseOption {
+	return func(cfg *BatchConfig) { cfg.MaxRetries = n }
+}
+
+func WithDeadLetter(fn DeadLetterFunc) ClickHouseOption {
+	return func(cfg *BatchConfig) { cfg.DeadLetter = fn }
+}
+
+var (
+	batchRowsEnqueued = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "analytics_clickhouse_rows_enqueued_total",
+			Help: "Rows successfully enqueued into a ClickHouseClient table buffer.",
+		},
+		[]string{"table"},
+	)
+	batchRowsFlushed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "analytics_clickhouse_rows_flushed_total",
+			Help: "Rows successfully flushed to ClickHouse.",
+		},
+		[]string{"table"},
+	)
+	batchRowsDropped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "analytics_clickhouse_rows_dropped_total",
+			Help: "Rows dropped, either because a table's buffer was full at enqueue time or a batch exhausted its retries.",
+		},
+		[]string{"table"},
+	)
+	batchRowsRetried = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "analytics_clickhouse_rows_retried_total",
+			Help: "Rows re-attempted after a batch flush failed with a transient error.",
+		},
+		[]string{"table"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(batchRowsEnqueued, batchRowsFlushed, batchRowsDropped, batchRowsRetried)
+}
+
+// batchBuffer batches one table's rows for background flushing: enqueue
+// is a non-blocking send into a bounded channel, and run (started once
+// per buffer by NewClickHouseClient) flushes whenever BatchSize rows
+// have queued or FlushInterval has elapsed since the last flush,
+// whichever comes first.
+type batchBuffer[T any] struct {
+	table string
+	db    *sql.DB
+	cfg   BatchConfig
+
+	insertSQL string
+	argsFn    func(T) []interface{}
+	// onFlushed, if set, is called once per row immediately after that
+	// row's batch has been durably written to ClickHouse — the fan-out
+	// hook Hub-backed live streaming publishes from, as opposed to
+	// enqueue time, which only means the row was buffered.
+	onFlushed func(T)
+
+	rows     chan T
+	inFlight chan struct{}
+
+	wg *sync.WaitGroup
+}
+
+func newBatchBuffer[T any](db *sql.DB, cfg BatchConfig, wg *sync.WaitGroup, inFlight chan struct{}, table, insertSQL string, argsFn func(T) []interface{}, onFlushed func(T)) *batchBuffer[T] {
+	return &batchBuffer[T]{
+		table:     table,
+		db:        db,
+		cfg:       cfg,
+		insertSQL: insertSQL,
+		argsFn:    argsFn,
+		onFlushed: onFlushed,
+		rows:      make(chan T, cfg.BatchSize*4),
+		inFlight:  inFlight,
+		wg:        wg,
+	}
+}
+
+// enqueue is Insert*'s non-blocking path: it never waits on the network,
+// only on a channel send, and fails immediately (counting the row as
+// dropped) once the buffer is full rather than applying backpressure to
+// the caller.
+func (b *batchBuffer[T]) enqueue(row T) error {
+	select {
+	case b.rows <- row:
+		batchRowsEnqueued.WithLabelValues(b.table).Inc()
+		return nil
+	default:
+		batchRowsDropped.WithLabelValues(b.table).Inc()
+		return fmt.Errorf("%s batch buffer is full, row dropped", b.table)
+	}
+}
+
+// run collects enqueued rows into batches and dispatches each for
+// flushing, until ctx is canceled, at which point it drains whatever is
+// still buffered (both the in-memory batch and anything left in the
+// rows channel) into one final flush before returning.
+func (b *batchBuffer[T]) run(ctx context.Context) {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(b.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]T, 0, b.cfg.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		toFlush := batch
+		batch = make([]T, 0, b.cfg.BatchSize)
+		// Flushes run on a context of their own: ctx only stops the
+		// collector, and the final drain below happens after it is
+		// canceled, so writing with ctx would fail and drop those rows.
+		b.dispatchFlush(context.Background(), toFlush)
+	}
+
+	for {
+		select {
+		case row, ok := <-b.rows:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, row)
+			if len(batch) >= b.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-ctx.Done():
+			drain := true
+			for drain {
+				select {
+				case row, ok := <-b.rows:
+					if !ok {
+						drain = false
+						continue
+					}
+					batch = append(batch, row)
+				default:
+					drain = false
+				}
+			}
+			flush()
+			return
+		}
+	}
+}
+
+// dispatchFlush hands toFlush to a goroutine bounded by the shared
+// MaxInFlight semaphore, so run's collector loop can keep accepting rows
+// into the next batch while a prior one is still being written.
+func (b *batchBuffer[T]) dispatchFlush(ctx context.Context, toFlush []T) {
+	b.inFlight <- struct{}{}
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		defer func() { <-b.inFlight }()
+		b.flushWithRetry(ctx, toFlush)
+	}()
+}
+
+// flushWithRetry retries execBatch with exponential backoff up to
+// cfg.MaxRetries times before handing toFlush to cfg.DeadLetter and
+// counting it as dropped.
+func (b *batchBuffer[T]) flushWithRetry(ctx context.Context, toFlush []T) {
+	bo := backoff.NewExponentialBackOff()
+
+	var lastErr error
+retryLoop:
+	for attempt := 0; attempt <= b.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(bo.NextBackOff()):
+			case <-ctx.Done():
+				lastErr = ctx.Err()
+				break retryLoop
+			}
+			batchRowsRetried.WithLabelValues(b.table).Add(float64(len(toFlush)))
+		}
+
+		if err := b.execBatch(ctx, toFlush); err != nil {
+			lastErr = err
+			continue
+		}
+		batchRowsFlushed.WithLabelValues(b.table).Add(float64(len(toFlush)))
+		if b.onFlushed != nil {
+			for _, row := range toFlush {
+				b.onFlushed(row)
+			}
+		}
+		return
+	}
+
+	batchRowsDropped.WithLabelValues(b.table).Add(float64(len(toFlush)))
+	if b.cfg.DeadLetter != nil {
+		b.cfg.DeadLetter(b.table, toFlush, lastErr)
+	}
+}
+
+// execBatch writes toFlush inside a single transaction using one
+// prepared statement executed once per row, the pattern the
+// ClickHouse-go driver turns into a single columnar insert block rather
+// than one round trip per row.
+func (b *batchBuffer[T]) execBatch(ctx context.Context, toFlush []T) error {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin %s batch tx: %w", b.table, err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, b.insertSQL)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to prepare %s batch insert: %w", b.table, err)
+	}
+	defer stmt.Close()
+
+	for _, row := range toFlush {
+		if _, err := stmt.ExecContext(ctx, b.argsFn(row)...); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to exec %s batch insert: %w", b.table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit %s batch: %w", b.table, err)
+	}
+	return nil
+}
@@ -3,21 +3,46 @@ package analytics
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	_ "github.com/ClickHouse/clickhouse-go"
 	"go.uber.org/zap"
 )
 
-// ClickHouseClient represents a ClickHouse client
+// ClickHouseClient represents a ClickHouse client. Every Insert* method is
+// a non-blocking enqueue into a per-table batchBuffer; background workers
+// (started by NewClickHouseClient, stopped by Flush) batch rows into
+// ClickHouse on BatchSize/FlushInterval, retrying a transient failure
+// with backoff before handing it to cfg.DeadLetter.
 type ClickHouseClient struct {
 	db     *sql.DB
 	logger *zap.Logger
+	cfg    BatchConfig
+
+	wg       sync.WaitGroup
+	inFlight chan struct{}
+	cancel   context.CancelFunc
+
+	// hub fans out every row once its batch has been durably flushed;
+	// see Stream and batchBuffer.onFlushed.
+	hub *Hub
+
+	routerMetrics     *batchBuffer[RouterMetrics]
+	protocolMetrics   *batchBuffer[ProtocolMetrics]
+	trafficMetrics    *batchBuffer[TrafficMetrics]
+	impairmentMetrics *batchBuffer[ImpairmentMetrics]
+	cloudMetrics      *batchBuffer[CloudMetrics]
+	events            *batchBuffer[Event]
 }
 
-// NewClickHouseClient creates a new ClickHouse client
-func NewClickHouseClient(dsn string, logger *zap.Logger) (*ClickHouseClient, error) {
+// NewClickHouseClient creates a new ClickHouse client and starts its
+// batching pipeline, tuned by opts (see WithBatchSize, WithFlushInterval,
+// WithMaxInFlight, WithMaxRetries, WithDeadLetter); any unset option
+// keeps defaultBatchConfig's value.
+func NewClickHouseClient(dsn string, logger *zap.Logger, opts ...ClickHouseOption) (*ClickHouseClient, error) {
 	db, err := sql.Open("clickhouse", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open ClickHouse connection: %w", err)
@@ -27,31 +52,199 @@ func NewClickHouseClient(dsn string, logger *zap.Logger) (*ClickHouseClient, err
 		return nil, fmt.Errorf("failed to ping ClickHouse: %w", err)
 	}
 
+	cfg := defaultBatchConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
 	client := &ClickHouseClient{
-		db:     db,
-		logger: logger,
+		db:       db,
+		logger:   logger,
+		cfg:      cfg,
+		inFlight: make(chan struct{}, cfg.MaxInFlight),
+		cancel:   cancel,
+		hub:      NewHub(),
 	}
 
 	// Initialize tables
 	if err := client.initializeTables(); err != nil {
+		cancel()
 		return nil, fmt.Errorf("failed to initialize tables: %w", err)
 	}
+	if err := client.createRollups(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to create rollups: %w", err)
+	}
+
+	client.routerMetrics = newBatchBuffer(db, cfg, &client.wg, client.inFlight, "router_metrics",
+		`INSERT INTO router_metrics (
+			timestamp, hostname, partition, uptime_seconds, interfaces_total, interfaces_up,
+			routes_total, neighbors_total, neighbors_up, cpu_usage, memory_usage, disk_usage
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		func(m RouterMetrics) []interface{} {
+			return []interface{}{
+				m.Timestamp, m.Hostname, m.Partition, m.UptimeSeconds, m.InterfacesTotal, m.InterfacesUp,
+				m.RoutesTotal, m.NeighborsTotal, m.NeighborsUp, m.CPUUsage, m.MemoryUsage, m.DiskUsage,
+			}
+		},
+		func(m RouterMetrics) { client.publish("router."+m.Hostname, m.Timestamp, m) })
+
+	client.protocolMetrics = newBatchBuffer(db, cfg, &client.wg, client.inFlight, "protocol_metrics",
+		`INSERT INTO protocol_metrics (
+			timestamp, hostname, partition, protocol, status, neighbors, routes_advertised,
+			routes_received, updates_sent, updates_received, lsa_sent, lsa_received,
+			lsp_sent, lsp_received
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		func(m ProtocolMetrics) []interface{} {
+			return []interface{}{
+				m.Timestamp, m.Hostname, m.Partition, m.Protocol, m.Status, m.Neighbors, m.RoutesAdvertised,
+				m.RoutesReceived, m.UpdatesSent, m.UpdatesReceived, m.LSASent, m.LSAReceived,
+				m.LSPSent, m.LSPReceived,
+			}
+		},
+		func(m ProtocolMetrics) { client.publish("protocol."+m.Hostname, m.Timestamp, m) })
+
+	client.trafficMetrics = newBatchBuffer(db, cfg, &client.wg, client.inFlight, "traffic_metrics",
+		`INSERT INTO traffic_metrics (
+			timestamp, hostname, partition, interface, packets_processed, packets_dropped,
+			bytes_processed, bytes_dropped, throughput_bps, queue_length
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		func(m TrafficMetrics) []interface{} {
+			return []interface{}{
+				m.Timestamp, m.Hostname, m.Partition, m.Interface, m.PacketsProcessed, m.PacketsDropped,
+				m.BytesProcessed, m.BytesDropped, m.ThroughputBps, m.QueueLength,
+			}
+		},
+		func(m TrafficMetrics) { client.publish("traffic."+m.Hostname, m.Timestamp, m) })
+
+	client.impairmentMetrics = newBatchBuffer(db, cfg, &client.wg, client.inFlight, "impairment_metrics",
+		`INSERT INTO impairment_metrics (
+			timestamp, hostname, partition, interface, impairment_type, delay_ms, jitter_ms,
+			loss_percentage, duplicate_percentage, corrupt_percentage, reorder_percentage
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		func(m ImpairmentMetrics) []interface{} {
+			return []interface{}{
+				m.Timestamp, m.Hostname, m.Partition, m.Interface, m.ImpairmentType, m.DelayMs, m.JitterMs,
+				m.LossPercentage, m.DuplicatePercentage, m.CorruptPercentage, m.ReorderPercentage,
+			}
+		},
+		func(m ImpairmentMetrics) { client.publish("impairment."+m.Hostname, m.Timestamp, m) })
+
+	client.cloudMetrics = newBatchBuffer(db, cfg, &client.wg, client.inFlight, "cloud_metrics",
+		`INSERT INTO cloud_metrics (
+			timestamp, hostname, partition, cloud_provider, resources_total, instances_total,
+			networks_total, storages_total, gateways_total, connections_active
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		func(m CloudMetrics) []interface{} {
+			return []interface{}{
+				m.Timestamp, m.Hostname, m.Partition, m.CloudProvider, m.ResourcesTotal, m.InstancesTotal,
+				m.NetworksTotal, m.StoragesTotal, m.GatewaysTotal, m.ConnectionsActive,
+			}
+		},
+		func(m CloudMetrics) { client.publish("cloud."+m.Hostname, m.Timestamp, m) })
+
+	client.events = newBatchBuffer(db, cfg, &client.wg, client.inFlight, "events",
+		`INSERT INTO events (
+			timestamp, hostname, partition, event_type, severity, message, details
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		func(e Event) []interface{} {
+			return []interface{}{
+				e.Timestamp, e.Hostname, e.Partition, e.EventType, e.Severity, e.Message, e.Details,
+			}
+		},
+		func(e Event) { client.publish("events."+e.Severity, e.Timestamp, e) })
+
+	for _, b := range client.buffers() {
+		client.wg.Add(1)
+		go b.run(ctx)
+	}
 
 	return client, nil
 }
 
-// Close closes the ClickHouse connection
+// buffers lists every batchBuffer so NewClickHouseClient can start them
+// uniformly; Go's lack of covariant generic slices means each entry has
+// to be wrapped behind the small runner interface below instead of
+// collected directly.
+type runner interface {
+	run(ctx context.Context)
+}
+
+func (c *ClickHouseClient) buffers() []runner {
+	return []runner{
+		c.routerMetrics, c.protocolMetrics, c.trafficMetrics,
+		c.impairmentMetrics, c.cloudMetrics, c.events,
+	}
+}
+
+// Flush stops the batching pipeline from accepting further background
+// ticks, drains every table's pending batch (including rows already
+// enqueued but not yet flushed), and waits for every in-flight flush to
+// finish or ctx to be done — so a graceful shutdown doesn't lose
+// buffered metrics. Callers should stop calling Insert* before calling
+// Flush.
+func (c *ClickHouseClient) Flush(ctx context.Context) error {
+	c.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close flushes the pipeline with a background context and closes the
+// ClickHouse connection.
 func (c *ClickHouseClient) Close() error {
+	_ = c.Flush(context.Background())
 	return c.db.Close()
 }
 
-// initializeTables creates the necessary tables
+// Ping is a cheap liveness probe for the underlying connection, meant
+// for wiring into a health check registry (e.g.
+// handlers.NewFuncHealthChecker) rather than for the query path.
+func (c *ClickHouseClient) Ping(ctx context.Context) error {
+	return c.db.PingContext(ctx)
+}
+
+// Stream returns the Hub every successfully flushed row is published to,
+// for the handlers package's /analytics/stream SSE/WebSocket endpoint to
+// subscribe against.
+func (c *ClickHouseClient) Stream() *Hub {
+	return c.hub
+}
+
+// publish marshals row and fans it out on c.hub under topic, tagged with
+// ts so a subscriber's Last-Event-ID can later be replayed from
+// ClickHouse. Marshaling failures are dropped rather than surfaced,
+// since a publish is best-effort and must never hold up the flush path
+// that calls it.
+func (c *ClickHouseClient) publish(topic string, ts time.Time, row interface{}) {
+	data, err := json.Marshal(row)
+	if err != nil {
+		return
+	}
+	c.hub.Publish(StreamFrame{ID: ts.Format(time.RFC3339Nano), Topic: topic, Data: data})
+}
+
+// initializeTables creates every raw *_metrics table (kept for only
+// 48 hours; see createRollups for the 1m/5m/1h rollups that back
+// anything longer) plus the events table.
 func (c *ClickHouseClient) initializeTables() error {
 	// Create router metrics table
 	routerMetricsSQL := `
 	CREATE TABLE IF NOT EXISTS router_metrics (
 		timestamp DateTime64(3),
 		hostname String,
+		partition String,
 		uptime_seconds UInt64,
 		interfaces_total UInt32,
 		interfaces_up UInt32,
@@ -62,8 +255,8 @@ func (c *ClickHouseClient) initializeTables() error {
 		memory_usage Float64,
 		disk_usage Float64
 	) ENGINE = MergeTree()
-	ORDER BY (timestamp, hostname)
-	TTL timestamp + INTERVAL 30 DAY
+	ORDER BY (timestamp, hostname, partition)
+	TTL timestamp + INTERVAL 48 HOUR
 	`
 
 	if _, err := c.db.Exec(routerMetricsSQL); err != nil {
@@ -75,6 +268,7 @@ func (c *ClickHouseClient) initializeTables() error {
 	CREATE TABLE IF NOT EXISTS protocol_metrics (
 		timestamp DateTime64(3),
 		hostname String,
+		partition String,
 		protocol String,
 		status String,
 		neighbors UInt32,
@@ -87,8 +281,8 @@ func (c *ClickHouseClient) initializeTables() error {
 		lsp_sent UInt32,
 		lsp_received UInt32
 	) ENGINE = MergeTree()
-	ORDER BY (timestamp, hostname, protocol)
-	TTL timestamp + INTERVAL 30 DAY
+	ORDER BY (timestamp, hostname, partition, protocol)
+	TTL timestamp + INTERVAL 48 HOUR
 	`
 
 	if _, err := c.db.Exec(protocolMetricsSQL); err != nil {
@@ -100,6 +294,7 @@ func (c *ClickHouseClient) initializeTables() error {
 	CREATE TABLE IF NOT EXISTS traffic_metrics (
 		timestamp DateTime64(3),
 		hostname String,
+		partition String,
 		interface String,
 		packets_processed UInt64,
 		packets_dropped UInt64,
@@ -108,8 +303,8 @@ func (c *ClickHouseClient) initializeTables() error {
 		throughput_bps Float64,
 		queue_length UInt32
 	) ENGINE = MergeTree()
-	ORDER BY (timestamp, hostname, interface)
-	TTL timestamp + INTERVAL 30 DAY
+	ORDER BY (timestamp, hostname, partition, interface)
+	TTL timestamp + INTERVAL 48 HOUR
 	`
 
 	if _, err := c.db.Exec(trafficMetricsSQL); err != nil {
@@ -121,6 +316,7 @@ func (c *ClickHouseClient) initializeTables() error {
 	CREATE TABLE IF NOT EXISTS impairment_metrics (
 		timestamp DateTime64(3),
 		hostname String,
+		partition String,
 		interface String,
 		impairment_type String,
 		delay_ms UInt32,
@@ -130,8 +326,8 @@ func (c *ClickHouseClient) initializeTables() error {
 		corrupt_percentage Float64,
 		reorder_percentage Float64
 	) ENGINE = MergeTree()
-	ORDER BY (timestamp, hostname, interface)
-	TTL timestamp + INTERVAL 30 DAY
+	ORDER BY (timestamp, hostname, partition, interface)
+	TTL timestamp + INTERVAL 48 HOUR
 	`
 
 	if _, err := c.db.Exec(impairmentMetricsSQL); err != nil {
@@ -143,6 +339,7 @@ func (c *ClickHouseClient) initializeTables() error {
 	CREATE TABLE IF NOT EXISTS cloud_metrics (
 		timestamp DateTime64(3),
 		hostname String,
+		partition String,
 		cloud_provider String,
 		resources_total UInt32,
 		instances_total UInt32,
@@ -151,8 +348,8 @@ func (c *ClickHouseClient) initializeTables() error {
 		gateways_total UInt32,
 		connections_active UInt32
 	) ENGINE = MergeTree()
-	ORDER BY (timestamp, hostname, cloud_provider)
-	TTL timestamp + INTERVAL 30 DAY
+	ORDER BY (timestamp, hostname, partition, cloud_provider)
+	TTL timestamp + INTERVAL 48 HOUR
 	`
 
 	if _, err := c.db.Exec(cloudMetricsSQL); err != nil {
@@ -164,12 +361,13 @@ func (c *ClickHouseClient) initializeTables() error {
 	CREATE TABLE IF NOT EXISTS events (
 		timestamp DateTime64(3),
 		hostname String,
+		partition String,
 		event_type String,
 		severity String,
 		message String,
 		details String
 	) ENGINE = MergeTree()
-	ORDER BY (timestamp, hostname, event_type)
+	ORDER BY (timestamp, hostname, partition, event_type)
 	TTL timestamp + INTERVAL 90 DAY
 	`
 
@@ -180,171 +378,48 @@ func (c *ClickHouseClient) initializeTables() error {
 	return nil
 }
 
-// InsertRouterMetrics inserts router metrics
+// InsertRouterMetrics enqueues router metrics for background batch
+// insertion; it returns an error only if the router_metrics buffer is
+// full, not for anything ClickHouse itself later rejects (see
+// WithDeadLetter).
 func (c *ClickHouseClient) InsertRouterMetrics(ctx context.Context, metrics RouterMetrics) error {
-	query := `
-	INSERT INTO router_metrics (
-		timestamp, hostname, uptime_seconds, interfaces_total, interfaces_up,
-		routes_total, neighbors_total, neighbors_up, cpu_usage, memory_usage, disk_usage
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-
-	_, err := c.db.ExecContext(ctx, query,
-		metrics.Timestamp,
-		metrics.Hostname,
-		metrics.UptimeSeconds,
-		metrics.InterfacesTotal,
-		metrics.InterfacesUp,
-		metrics.RoutesTotal,
-		metrics.NeighborsTotal,
-		metrics.NeighborsUp,
-		metrics.CPUUsage,
-		metrics.MemoryUsage,
-		metrics.DiskUsage,
-	)
-
-	return err
+	return c.routerMetrics.enqueue(metrics)
 }
 
-// InsertProtocolMetrics inserts protocol metrics
+// InsertProtocolMetrics enqueues protocol metrics for background batch
+// insertion.
 func (c *ClickHouseClient) InsertProtocolMetrics(ctx context.Context, metrics ProtocolMetrics) error {
-	query := `
-	INSERT INTO protocol_metrics (
-		timestamp, hostname, protocol, status, neighbors, routes_advertised,
-		routes_received, updates_sent, updates_received, lsa_sent, lsa_received,
-		lsp_sent, lsp_received
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-
-	_, err := c.db.ExecContext(ctx, query,
-		metrics.Timestamp,
-		metrics.Hostname,
-		metrics.Protocol,
-		metrics.Status,
-		metrics.Neighbors,
-		metrics.RoutesAdvertised,
-		metrics.RoutesReceived,
-		metrics.UpdatesSent,
-		metrics.UpdatesReceived,
-		metrics.LSASent,
-		metrics.LSAReceived,
-		metrics.LSPSent,
-		metrics.LSPReceived,
-	)
-
-	return err
+	return c.protocolMetrics.enqueue(metrics)
 }
 
-// InsertTrafficMetrics inserts traffic metrics
+// InsertTrafficMetrics enqueues traffic metrics for background batch
+// insertion.
 func (c *ClickHouseClient) InsertTrafficMetrics(ctx context.Context, metrics TrafficMetrics) error {
-	query := `
-	INSERT INTO traffic_metrics (
-		timestamp, hostname, interface, packets_processed, packets_dropped,
-		bytes_processed, bytes_dropped, throughput_bps, queue_length
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-
-	_, err := c.db.ExecContext(ctx, query,
-		metrics.Timestamp,
-		metrics.Hostname,
-		metrics.Interface,
-		metrics.PacketsProcessed,
-		metrics.PacketsDropped,
-		metrics.BytesProcessed,
-		metrics.BytesDropped,
-		metrics.ThroughputBps,
-		metrics.QueueLength,
-	)
-
-	return err
+	return c.trafficMetrics.enqueue(metrics)
 }
 
-// InsertImpairmentMetrics inserts impairment metrics
+// InsertImpairmentMetrics enqueues impairment metrics for background
+// batch insertion.
 func (c *ClickHouseClient) InsertImpairmentMetrics(ctx context.Context, metrics ImpairmentMetrics) error {
-	query := `
-	INSERT INTO impairment_metrics (
-		timestamp, hostname, interface, impairment_type, delay_ms, jitter_ms,
-		loss_percentage, duplicate_percentage, corrupt_percentage, reorder_percentage
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-
-	_, err := c.db.ExecContext(ctx, query,
-		metrics.Timestamp,
-		metrics.Hostname,
-		metrics.Interface,
-		metrics.ImpairmentType,
-		metrics.DelayMs,
-		metrics.JitterMs,
-		metrics.LossPercentage,
-		metrics.DuplicatePercentage,
-		metrics.CorruptPercentage,
-		metrics.ReorderPercentage,
-	)
-
-	return err
+	return c.impairmentMetrics.enqueue(metrics)
 }
 
-// InsertCloudMetrics inserts cloud integration metrics
+// InsertCloudMetrics enqueues cloud integration metrics for background
+// batch insertion.
 func (c *ClickHouseClient) InsertCloudMetrics(ctx context.Context, metrics CloudMetrics) error {
-	query := `
-	INSERT INTO cloud_metrics (
-		timestamp, hostname, cloud_provider, resources_total, instances_total,
-		networks_total, storages_total, gateways_total, connections_active
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-
-	_, err := c.db.ExecContext(ctx, query,
-		metrics.Timestamp,
-		metrics.Hostname,
-		metrics.CloudProvider,
-		metrics.ResourcesTotal,
-		metrics.InstancesTotal,
-		metrics.NetworksTotal,
-		metrics.StoragesTotal,
-		metrics.GatewaysTotal,
-		metrics.ConnectionsActive,
-	)
-
-	return err
+	return c.cloudMetrics.enqueue(metrics)
 }
 
-// InsertEvent inserts an event
+// InsertEvent enqueues an event for background batch insertion.
 func (c *ClickHouseClient) InsertEvent(ctx context.Context, event Event) error {
-	query := `
-	INSERT INTO events (
-		timestamp, hostname, event_type, severity, message, details
-	) VALUES (?, ?, ?, ?, ?, ?)
-	`
-
-	_, err := c.db.ExecContext(ctx, query,
-		event.Timestamp,
-		event.Hostname,
-		event.EventType,
-		event.Severity,
-		event.Message,
-		event.Details,
-	)
-
-	return err
-}
-
-// GetMetrics retrieves metrics for a time range
-func (c *ClickHouseClient) GetMetrics(ctx context.Context, startTime, endTime time.Time) (map[string]interface{}, error) {
-	// This would implement complex queries to retrieve aggregated metrics
-	// For now, return a mock response
-	return map[string]interface{}{
-		"router_metrics": []map[string]interface{}{},
-		"protocol_metrics": []map[string]interface{}{},
-		"traffic_metrics": []map[string]interface{}{},
-		"impairment_metrics": []map[string]interface{}{},
-		"cloud_metrics": []map[string]interface{}{},
-	}, nil
+	return c.events.enqueue(event)
 }
 
 // Data structures for ClickHouse
 type RouterMetrics struct {
 	Timestamp      time.Time
 	Hostname       string
+	Partition      string
 	UptimeSeconds  uint64
 	InterfacesTotal uint32
 	InterfacesUp   uint32
@@ -359,6 +434,7 @@ type RouterMetrics struct {
 type ProtocolMetrics struct {
 	Timestamp        time.Time
 	Hostname         string
+	Partition        string
 	Protocol         string
 	Status           string
 	Neighbors        uint32
@@ -375,6 +451,7 @@ type ProtocolMetrics struct {
 type TrafficMetrics struct {
 	Timestamp        time.Time
 	Hostname         string
+	Partition        string
 	Interface        string
 	PacketsProcessed uint64
 	PacketsDropped   uint64
@@ -387,6 +464,7 @@ type TrafficMetrics struct {
 type ImpairmentMetrics struct {
 	Timestamp            time.Time
 	Hostname             string
+	Partition            string
 	Interface            string
 	ImpairmentType       string
 	DelayMs              uint32
@@ -400,6 +478,7 @@ type ImpairmentMetrics struct {
 type CloudMetrics struct {
 	Timestamp          time.Time
 	Hostname           string
+	Partition          string
 	CloudProvider      string
 	ResourcesTotal     uint32
 	InstancesTotal     uint32
@@ -412,6 +491,7 @@ type CloudMetrics struct {
 type Event struct {
 	Timestamp  time.Time
 	Hostname   string
+	Partition  string
 	EventType  string
 	Severity   string
 	Message    string
@@ -2,21 +2,39 @@ package analytics
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
 	"log"
 	"sync"
 	"time"
 
 	"router-sim/internal/config"
+	"router-sim/internal/events"
+	routermetrics "router-sim/internal/metrics"
 )
 
+// recentEventsLimit bounds how many audit events GetDashboardData's
+// "recent_events" replays; older ones are still in the audit log, just
+// not surfaced on the dashboard.
+const recentEventsLimit = 10
+
+// instrumentedProtocols is every protocol collectMetrics reports a
+// per-protocol route/neighbor count for, matching routing.Protocol's
+// known values without importing the routing package (which would
+// otherwise create an import cycle through handlers -> routing and
+// handlers -> analytics).
+var instrumentedProtocols = []string{"static", "bgp", "ospf", "isis"}
+
 // Engine represents the analytics engine
 type Engine struct {
 	config     config.AnalyticsConfig
 	subscribers map[chan MetricUpdate]bool
+	exporters  []Exporter
 	mutex      sync.RWMutex
 	ctx        context.Context
 	cancel     context.CancelFunc
+
+	eventsMu     sync.RWMutex
+	recentEvents []events.Event
 }
 
 // MetricUpdate represents a metric update
@@ -29,7 +47,7 @@ type MetricUpdate struct {
 // NewEngine creates a new analytics engine
 func NewEngine(cfg config.AnalyticsConfig) (*Engine, error) {
 	ctx, cancel := context.WithCancel(context.Background())
-	
+
 	engine := &Engine{
 		config:      cfg,
 		subscribers: make(map[chan MetricUpdate]bool),
@@ -37,6 +55,19 @@ func NewEngine(cfg config.AnalyticsConfig) (*Engine, error) {
 		cancel:      cancel,
 	}
 
+	for _, ec := range cfg.Exporters {
+		exporter, err := newExporter(ec)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		if err := exporter.Start(ctx); err != nil {
+			cancel()
+			return nil, err
+		}
+		engine.exporters = append(engine.exporters, exporter)
+	}
+
 	// Start metric collection if enabled
 	if cfg.Enabled {
 		go engine.collectMetrics()
@@ -45,6 +76,49 @@ func NewEngine(cfg config.AnalyticsConfig) (*Engine, error) {
 	return engine, nil
 }
 
+// ConsumeEvents subscribes to bus and keeps the most recent
+// recentEventsLimit audit events in memory for GetDashboardData's
+// "recent_events" to report, so the dashboard reflects real route and
+// protocol mutations instead of a fixed sample. It runs until the
+// Engine is Closed.
+func (e *Engine) ConsumeEvents(bus *events.Bus) {
+	ch := bus.Subscribe(nil)
+	go func() {
+		for {
+			select {
+			case <-e.ctx.Done():
+				bus.Unsubscribe(ch)
+				return
+			case event, ok := <-ch:
+				if !ok {
+					return
+				}
+				e.recordEvent(event)
+			}
+		}
+	}()
+}
+
+func (e *Engine) recordEvent(event events.Event) {
+	e.eventsMu.Lock()
+	defer e.eventsMu.Unlock()
+
+	e.recentEvents = append(e.recentEvents, event)
+	if len(e.recentEvents) > recentEventsLimit {
+		e.recentEvents = e.recentEvents[len(e.recentEvents)-recentEventsLimit:]
+	}
+}
+
+// newExporter builds the Exporter ec.Type names.
+func newExporter(ec config.ExporterConfig) (Exporter, error) {
+	switch ec.Type {
+	case "ocagent":
+		return NewOCAgentExporter(ec.Endpoint, ec.BufferSize, ec.Insecure), nil
+	default:
+		return nil, fmt.Errorf("analytics: unknown exporter type %q", ec.Type)
+	}
+}
+
 // Subscribe subscribes to metric updates
 func (e *Engine) Subscribe(updates chan MetricUpdate) {
 	e.mutex.Lock()
@@ -59,11 +133,12 @@ func (e *Engine) Unsubscribe(updates chan MetricUpdate) {
 	delete(e.subscribers, updates)
 }
 
-// Publish publishes a metric update to all subscribers
+// Publish publishes a metric update to all subscribers and every
+// configured Exporter.
 func (e *Engine) Publish(update MetricUpdate) {
 	e.mutex.RLock()
 	defer e.mutex.RUnlock()
-	
+
 	for ch := range e.subscribers {
 		select {
 		case ch <- update:
@@ -71,6 +146,12 @@ func (e *Engine) Publish(update MetricUpdate) {
 			// Channel is full, skip this update
 		}
 	}
+
+	for _, exporter := range e.exporters {
+		if err := exporter.Export([]MetricUpdate{update}); err != nil {
+			log.Printf("analytics: exporter export failed: %v", err)
+		}
+	}
 }
 
 // collectMetrics collects metrics periodically
@@ -89,7 +170,7 @@ func (e *Engine) collectMetrics() {
 				"memory_usage": 65.0 + (time.Now().Unix()%15),
 				"network_rx":   1000000 + (time.Now().Unix()%500000),
 				"network_tx":   2000000 + (time.Now().Unix()%1000000),
-				"packet_loss":  0.001 + (time.Now().Unix()%10)/10000.0,
+				"packet_loss":  0.001 + float64(time.Now().Unix()%10)/10000.0,
 				"latency":      10.0 + (time.Now().Unix()%20),
 			}
 
@@ -100,10 +181,34 @@ func (e *Engine) collectMetrics() {
 			}
 
 			e.Publish(update)
+			e.Publish(e.collectRouterMetrics())
 		}
 	}
 }
 
+// collectRouterMetrics builds a MetricUpdate from the real Prometheus
+// collectors in the metrics package, rather than the mock data above, so
+// subscribers and exporters also see actual route/neighbor/packet
+// counts from the routing and capture subsystems.
+func (e *Engine) collectRouterMetrics() MetricUpdate {
+	var routes, neighbors int64
+	for _, protocol := range instrumentedProtocols {
+		routes += routermetrics.RouteCount(protocol)
+		neighbors += routermetrics.NeighborCount(protocol)
+	}
+
+	return MetricUpdate{
+		Timestamp: time.Now(),
+		Metrics: map[string]interface{}{
+			"routes_total":      routes,
+			"neighbors_up":      neighbors,
+			"packets_processed": routermetrics.PacketsProcessed(),
+			"packets_dropped":   routermetrics.PacketsDropped(),
+		},
+		Source: "router-sim.metrics",
+	}
+}
+
 // Query queries analytics data
 func (e *Engine) Query(ctx context.Context, query string, timeRange string) ([]MetricUpdate, error) {
 	// Mock query implementation
@@ -137,9 +242,14 @@ func (e *Engine) Query(ctx context.Context, query string, timeRange string) ([]M
 	return results, nil
 }
 
-// Close closes the analytics engine
+// Close closes the analytics engine and every configured Exporter.
 func (e *Engine) Close() error {
 	e.cancel()
+	for _, exporter := range e.exporters {
+		if err := exporter.Close(); err != nil {
+			log.Printf("analytics: exporter close failed: %v", err)
+		}
+	}
 	return nil
 }
 
@@ -174,24 +284,48 @@ func (e *Engine) GetDashboardData() map[string]interface{} {
 				"resources": 15,
 			},
 		},
-		"recent_events": []map[string]interface{}{
+		"recent_events": e.recentEventsOrSample(),
+	}
+}
+
+// recentEventsOrSample returns the real audit events ConsumeEvents has
+// observed, most recent first, or a fixed sample if none have happened
+// yet - the same "nothing live yet" fallback register_streams.go's
+// syntheticEventSamples uses for /events/stream.
+func (e *Engine) recentEventsOrSample() []map[string]interface{} {
+	e.eventsMu.RLock()
+	defer e.eventsMu.RUnlock()
+
+	if len(e.recentEvents) == 0 {
+		return []map[string]interface{}{
 			{
-				"type": "info",
+				"type":    "info",
 				"message": "BGP session established with neighbor 192.168.1.1",
-				"time": "2 minutes ago",
+				"time":    "2 minutes ago",
 			},
 			{
-				"type": "warning",
+				"type":    "warning",
 				"message": "High CPU usage detected on router-1",
-				"time": "5 minutes ago",
+				"time":    "5 minutes ago",
 			},
 			{
-				"type": "success",
+				"type":    "success",
 				"message": "Aviatrix gateway connection restored",
-				"time": "8 minutes ago",
+				"time":    "8 minutes ago",
 			},
-		},
+		}
+	}
+
+	out := make([]map[string]interface{}, 0, len(e.recentEvents))
+	for i := len(e.recentEvents) - 1; i >= 0; i-- {
+		event := e.recentEvents[i]
+		out = append(out, map[string]interface{}{
+			"type":    event.Type,
+			"message": fmt.Sprintf("%s by %s", event.Type, event.Actor),
+			"time":    event.Timestamp.Format(time.RFC3339),
+		})
 	}
+	return out
 }
 
 // GetMetrics returns current metrics
@@ -0,0 +1,20 @@
+package analytics
+
+import "context"
+
+// Exporter streams MetricUpdate samples somewhere outside this process -
+// a metrics collector, a time-series database, a log sink - in addition
+// to the in-process Subscribe/Publish fan-out Engine already does.
+// Implementations must tolerate Export being called with zero updates
+// and must be safe to Close more than once.
+type Exporter interface {
+	// Start connects the exporter (dialing, handshaking, whatever the
+	// backend requires) and must return before Export is called.
+	Start(ctx context.Context) error
+	// Export ships updates to the backend. Implementations that buffer
+	// while disconnected should return nil here and retry in the
+	// background rather than blocking the caller.
+	Export(updates []MetricUpdate) error
+	// Close releases any connection Start opened.
+	Close() error
+}
@@ -0,0 +1,315 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ocAgentServiceName identifies this process to the collector as the
+// Node on the stream's first message, the same role internal/xds's
+// control-plane server plays on its own gRPC surface.
+const ocAgentServiceName = "router-sim"
+
+// ocAgentMinBackoff and ocAgentMaxBackoff bound OCAgentExporter's
+// reconnect backoff: it starts at ocAgentMinBackoff and doubles on every
+// failed dial/stream up to ocAgentMaxBackoff.
+const (
+	ocAgentMinBackoff = 500 * time.Millisecond
+	ocAgentMaxBackoff = 30 * time.Second
+)
+
+// OCAgentExporter streams MetricUpdate samples to a collector speaking
+// the OpenCensus agent protocol (the same bidi-streaming gRPC service
+// OTel collectors and Grafana Tempo/Prometheus's OpenCensus receiver
+// accept) so router/protocol/traffic metrics can be shipped to a
+// standard observability stack instead of only the mock JSON endpoints.
+type OCAgentExporter struct {
+	endpoint   string
+	insecure   bool
+	bufferSize int
+
+	mu        sync.Mutex
+	buffer    []MetricUpdate
+	conn      *grpc.ClientConn
+	stream    agentmetricspb.MetricsService_ExportClient
+	connected bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewOCAgentExporter builds an exporter that dials endpoint lazily from
+// Start. bufferSize bounds how many MetricUpdates Export holds onto
+// while disconnected, dropping the oldest once full; a bufferSize <= 0
+// falls back to a reasonable default of 256.
+func NewOCAgentExporter(endpoint string, bufferSize int, insecureConn bool) *OCAgentExporter {
+	if bufferSize <= 0 {
+		bufferSize = 256
+	}
+	return &OCAgentExporter{
+		endpoint:   endpoint,
+		insecure:   insecureConn,
+		bufferSize: bufferSize,
+	}
+}
+
+// Start implements Exporter: it launches the connect/reconnect loop in
+// the background and returns immediately, since the collector may not
+// be reachable yet (Export buffers in that case rather than blocking).
+func (e *OCAgentExporter) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	e.cancel = cancel
+	e.done = make(chan struct{})
+
+	go e.run(ctx)
+	return nil
+}
+
+// run dials endpoint and keeps the bidi stream alive, reconnecting with
+// exponential backoff whenever the stream or dial fails, until ctx is
+// canceled.
+func (e *OCAgentExporter) run(ctx context.Context) {
+	defer close(e.done)
+
+	backoff := ocAgentMinBackoff
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		stream, conn, err := e.connect(ctx)
+		if err != nil {
+			if !e.sleep(ctx, backoff) {
+				return
+			}
+			backoff *= 2
+			if backoff > ocAgentMaxBackoff {
+				backoff = ocAgentMaxBackoff
+			}
+			continue
+		}
+
+		e.mu.Lock()
+		e.conn, e.stream, e.connected = conn, stream, true
+		pending := e.buffer
+		e.buffer = nil
+		e.mu.Unlock()
+
+		backoff = ocAgentMinBackoff
+
+		if len(pending) > 0 {
+			if err := e.send(pending); err != nil {
+				e.disconnect()
+				continue
+			}
+		}
+
+		// Block here until the stream breaks (collector hangs up, network
+		// drop, ctx canceled); Export sends on e.stream directly once
+		// connected, so this goroutine's only remaining job is noticing
+		// the stream died and reconnecting.
+		<-stream.Context().Done()
+		e.disconnect()
+
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// connect dials endpoint and sends the stream's first message: a Node
+// identifying this process plus an empty Resource, as the OpenCensus
+// agent protocol requires before any metric batch.
+func (e *OCAgentExporter) connect(ctx context.Context) (agentmetricspb.MetricsService_ExportClient, *grpc.ClientConn, error) {
+	var opts []grpc.DialOption
+	if e.insecure {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	conn, err := grpc.DialContext(ctx, e.endpoint, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("analytics: dialing OC-Agent endpoint %q: %w", e.endpoint, err)
+	}
+
+	client := agentmetricspb.NewMetricsServiceClient(conn)
+	stream, err := client.Export(ctx)
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("analytics: opening OC-Agent export stream: %w", err)
+	}
+
+	first := &agentmetricspb.ExportMetricsServiceRequest{
+		Node: &commonpb.Node{
+			Identifier: &commonpb.ProcessIdentifier{
+				HostName:       ocAgentServiceName,
+				StartTimestamp: nil,
+			},
+			ServiceInfo: &commonpb.ServiceInfo{Name: ocAgentServiceName},
+		},
+		Resource: &resourcepb.Resource{Type: ocAgentServiceName},
+	}
+	if err := stream.Send(first); err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("analytics: sending OC-Agent node identifier: %w", err)
+	}
+
+	return stream, conn, nil
+}
+
+func (e *OCAgentExporter) disconnect() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.conn != nil {
+		e.conn.Close()
+	}
+	e.conn, e.stream, e.connected = nil, nil, false
+}
+
+// sleep waits for d or ctx cancellation, returning false in the latter
+// case so callers can stop retrying.
+func (e *OCAgentExporter) sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// Export implements Exporter. While connected it sends updates on the
+// live stream immediately; while disconnected it appends to the
+// buffer, dropping the oldest entries once bufferSize is exceeded, so a
+// reconnect can flush everything collected in between.
+func (e *OCAgentExporter) Export(updates []MetricUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	e.mu.Lock()
+	connected := e.connected
+	e.mu.Unlock()
+
+	if connected {
+		if err := e.send(updates); err != nil {
+			e.disconnect()
+			e.bufferUpdates(updates)
+		}
+		return nil
+	}
+
+	e.bufferUpdates(updates)
+	return nil
+}
+
+func (e *OCAgentExporter) bufferUpdates(updates []MetricUpdate) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.buffer = append(e.buffer, updates...)
+	if overflow := len(e.buffer) - e.bufferSize; overflow > 0 {
+		e.buffer = e.buffer[overflow:]
+	}
+}
+
+// send marshals updates into a single ExportMetricsServiceRequest batch
+// and writes it to the live stream.
+func (e *OCAgentExporter) send(updates []MetricUpdate) error {
+	e.mu.Lock()
+	stream := e.stream
+	e.mu.Unlock()
+	if stream == nil {
+		return fmt.Errorf("analytics: OC-Agent stream not connected")
+	}
+
+	metrics := make([]*metricspb.Metric, 0, len(updates))
+	for _, update := range updates {
+		metrics = append(metrics, toMetricProtos(update)...)
+	}
+
+	return stream.Send(&agentmetricspb.ExportMetricsServiceRequest{Metrics: metrics})
+}
+
+// timestampProto converts t into the protobuf Timestamp the metrics
+// proto's Point expects.
+func timestampProto(t time.Time) *timestamppb.Timestamp {
+	return timestamppb.New(t)
+}
+
+// toMetricProtos converts one MetricUpdate's numeric fields into gauge
+// double metric protos, labeled with update's Source and timestamped at
+// update.Timestamp. Non-numeric fields have no OpenCensus metric
+// representation and are skipped.
+func toMetricProtos(update MetricUpdate) []*metricspb.Metric {
+	ts := timestampProto(update.Timestamp)
+
+	out := make([]*metricspb.Metric, 0, len(update.Metrics))
+	for name, value := range update.Metrics {
+		point, ok := gaugePoint(value, ts)
+		if !ok {
+			continue
+		}
+
+		out = append(out, &metricspb.Metric{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:      name,
+				Type:      metricspb.MetricDescriptor_GAUGE_DOUBLE,
+				LabelKeys: []*metricspb.LabelKey{{Key: "source"}},
+			},
+			Timeseries: []*metricspb.TimeSeries{
+				{
+					LabelValues: []*metricspb.LabelValue{{Value: update.Source, HasValue: true}},
+					Points:      []*metricspb.Point{point},
+				},
+			},
+		})
+	}
+	return out
+}
+
+// gaugePoint converts value into a gauge double Point timestamped at ts,
+// reporting ok=false for values with no numeric representation.
+func gaugePoint(value interface{}, ts *timestamppb.Timestamp) (*metricspb.Point, bool) {
+	var f float64
+	switch v := value.(type) {
+	case float64:
+		f = v
+	case float32:
+		f = float64(v)
+	case int:
+		f = float64(v)
+	case int64:
+		f = float64(v)
+	default:
+		return nil, false
+	}
+
+	return &metricspb.Point{
+		Timestamp: ts,
+		Value:     &metricspb.Point_DoubleValue{DoubleValue: f},
+	}, true
+}
+
+// Close implements Exporter: it cancels the connect/reconnect loop and
+// closes any live connection. Safe to call more than once.
+func (e *OCAgentExporter) Close() error {
+	if e.cancel != nil {
+		e.cancel()
+	}
+	if e.done != nil {
+		<-e.done
+	}
+	e.disconnect()
+	return nil
+}
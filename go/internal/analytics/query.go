@@ -0,0 +1,375 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// maxSeriesRows bounds how many buckets a single GetMetrics query is
+// willing to return. resolvedBucket widens the caller's requested
+// bucket until the time range divided by it falls under this cap, so a
+// caller that asks for 1s buckets over a 30-day range gets a coarser
+// series back instead of a multi-million-row response.
+const maxSeriesRows = 2000
+
+// MetricQuery selects and aggregates rows for GetMetrics: the range
+// [StartTime, EndTime), the requested toStartOfInterval width before
+// downsampling, and optional per-table filters. Hostname applies to
+// every table; Interface, Protocol, and CloudProvider only apply to
+// the tables that have that column and are ignored otherwise.
+type MetricQuery struct {
+	StartTime     time.Time
+	EndTime       time.Time
+	Bucket        time.Duration
+	Hostname      string
+	Interface     string
+	Protocol      string
+	CloudProvider string
+}
+
+// resolvedBucket widens q.Bucket, doubling it until the number of
+// buckets across [StartTime, EndTime) falls at or under maxSeriesRows.
+func (q MetricQuery) resolvedBucket() time.Duration {
+	bucket := q.Bucket
+	if bucket <= 0 {
+		bucket = time.Minute
+	}
+	span := q.EndTime.Sub(q.StartTime)
+	for span > 0 && int64(span/bucket) > maxSeriesRows {
+		bucket *= 2
+	}
+	return bucket
+}
+
+// MetricsResult is GetMetrics's return value: one aggregated series per
+// table, all bucketed at the same (possibly downsampled) width.
+type MetricsResult struct {
+	Partition         string
+	Bucket            time.Duration
+	RouterMetrics     []RouterMetricsPoint
+	ProtocolMetrics   []ProtocolMetricsPoint
+	TrafficMetrics    []TrafficMetricsPoint
+	ImpairmentMetrics []ImpairmentMetricsPoint
+	CloudMetrics      []CloudMetricsPoint
+}
+
+// RouterMetricsPoint is one bucket of aggregated router_metrics rows.
+type RouterMetricsPoint struct {
+	Bucket           time.Time
+	AvgCPUUsage      float64
+	AvgMemoryUsage   float64
+	AvgDiskUsage     float64
+	MaxUptimeSeconds uint64
+}
+
+// ProtocolMetricsPoint is one bucket of aggregated protocol_metrics rows.
+type ProtocolMetricsPoint struct {
+	Bucket              time.Time
+	AvgNeighbors        float64
+	SumRoutesAdvertised uint64
+	SumRoutesReceived   uint64
+}
+
+// TrafficMetricsPoint is one bucket of aggregated traffic_metrics rows.
+type TrafficMetricsPoint struct {
+	Bucket           time.Time
+	AvgThroughputBps float64
+	P95ThroughputBps float64
+	SumBytesDropped  uint64
+	MaxQueueLength   uint32
+}
+
+// ImpairmentMetricsPoint is one bucket of aggregated impairment_metrics rows.
+type ImpairmentMetricsPoint struct {
+	Bucket            time.Time
+	AvgDelayMs        float64
+	P95DelayMs        float64
+	AvgLossPercentage float64
+}
+
+// CloudMetricsPoint is one bucket of aggregated cloud_metrics rows.
+type CloudMetricsPoint struct {
+	Bucket               time.Time
+	SumResourcesTotal    uint64
+	SumInstancesTotal    uint64
+	MaxConnectionsActive uint32
+}
+
+// GetMetrics aggregates every table across q's time range into
+// bucketed series, scoped to partitionName so one tenant's dashboard
+// can never surface another tenant's rows. The bucket actually used
+// (q.Bucket, possibly widened by downsampling) is reported back on
+// MetricsResult.Bucket. Each table routes to the coarsest of
+// rollups.go's 1m/5m/1h materialized-view rollups that still satisfies
+// that bucket (see selectTier), falling back to the raw table only for
+// a sub-minute bucket; since the raw tables carry only 48 hours of
+// rows, a long-range query would otherwise return nothing for anything
+// older than that.
+func (c *ClickHouseClient) GetMetrics(ctx context.Context, partitionName string, q MetricQuery) (*MetricsResult, error) {
+	bucket := q.resolvedBucket()
+
+	routerPoints, err := c.queryRouterMetrics(ctx, partitionName, q, bucket)
+	if err != nil {
+		return nil, err
+	}
+	protocolPoints, err := c.queryProtocolMetrics(ctx, partitionName, q, bucket)
+	if err != nil {
+		return nil, err
+	}
+	trafficPoints, err := c.queryTrafficMetrics(ctx, partitionName, q, bucket)
+	if err != nil {
+		return nil, err
+	}
+	impairmentPoints, err := c.queryImpairmentMetrics(ctx, partitionName, q, bucket)
+	if err != nil {
+		return nil, err
+	}
+	cloudPoints, err := c.queryCloudMetrics(ctx, partitionName, q, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MetricsResult{
+		Partition:         partitionName,
+		Bucket:            bucket,
+		RouterMetrics:     routerPoints,
+		ProtocolMetrics:   protocolPoints,
+		TrafficMetrics:    trafficPoints,
+		ImpairmentMetrics: impairmentPoints,
+		CloudMetrics:      cloudPoints,
+	}, nil
+}
+
+// filterClause appends "AND column = ?" to where/args when value is
+// non-empty, the shared WHERE-builder every query* method below uses
+// for its optional filter.
+func filterClause(where string, args []interface{}, column, value string) (string, []interface{}) {
+	if value == "" {
+		return where, args
+	}
+	return where + fmt.Sprintf(" AND %s = ?", column), append(args, value)
+}
+
+// metricsTier names which table a query* method reads from: the raw
+// table (bucketed live off its timestamp column) or one of rollups.go's
+// 1m/5m/1h materialized-view rollups (bucketed off their own
+// pre-aggregated bucket column, combined with a *Merge aggregate
+// instead of recomputing from raw rows).
+type metricsTier struct {
+	suffix    string // "" for the raw table, otherwise "1m"/"5m"/"1h"
+	bucketCol string // "timestamp" for raw, "bucket" for a rollup
+}
+
+// selectTier picks the coarsest rollup tier whose native bucket width
+// is still fine enough for bucket, falling all the way back to the raw
+// table for anything finer than the 1-minute rollup.
+func selectTier(bucket time.Duration) metricsTier {
+	switch {
+	case bucket >= time.Hour:
+		return metricsTier{suffix: "1h", bucketCol: "bucket"}
+	case bucket >= 5*time.Minute:
+		return metricsTier{suffix: "5m", bucketCol: "bucket"}
+	case bucket >= time.Minute:
+		return metricsTier{suffix: "1m", bucketCol: "bucket"}
+	default:
+		return metricsTier{bucketCol: "timestamp"}
+	}
+}
+
+// table returns base's name for this tier, e.g. "router_metrics_1h".
+func (t metricsTier) table(base string) string {
+	if t.suffix == "" {
+		return base
+	}
+	return base + "_" + t.suffix
+}
+
+func (c *ClickHouseClient) queryRouterMetrics(ctx context.Context, partitionName string, q MetricQuery, bucket time.Duration) ([]RouterMetricsPoint, error) {
+	tier := selectTier(bucket)
+	where := fmt.Sprintf("%s >= ? AND %s < ? AND partition = ?", tier.bucketCol, tier.bucketCol)
+	args := []interface{}{q.StartTime, q.EndTime, partitionName}
+	where, args = filterClause(where, args, "hostname", q.Hostname)
+
+	cols := "avg(cpu_usage), avg(memory_usage), avg(disk_usage), max(uptime_seconds)"
+	if tier.suffix != "" {
+		cols = "avgMerge(cpu_usage_state), avgMerge(memory_usage_state), avgMerge(disk_usage_state), maxMerge(uptime_seconds_state)"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			toStartOfInterval(%s, INTERVAL ? SECOND) AS bucket,
+			%s
+		FROM %s
+		WHERE %s
+		GROUP BY bucket
+		ORDER BY bucket`, tier.bucketCol, cols, tier.table("router_metrics"), where)
+
+	rows, err := c.db.QueryContext(ctx, query, append([]interface{}{int64(bucket.Seconds())}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query router_metrics: %w", err)
+	}
+	defer rows.Close()
+
+	var points []RouterMetricsPoint
+	for rows.Next() {
+		var p RouterMetricsPoint
+		if err := rows.Scan(&p.Bucket, &p.AvgCPUUsage, &p.AvgMemoryUsage, &p.AvgDiskUsage, &p.MaxUptimeSeconds); err != nil {
+			return nil, fmt.Errorf("failed to scan router_metrics row: %w", err)
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+func (c *ClickHouseClient) queryProtocolMetrics(ctx context.Context, partitionName string, q MetricQuery, bucket time.Duration) ([]ProtocolMetricsPoint, error) {
+	tier := selectTier(bucket)
+	where := fmt.Sprintf("%s >= ? AND %s < ? AND partition = ?", tier.bucketCol, tier.bucketCol)
+	args := []interface{}{q.StartTime, q.EndTime, partitionName}
+	where, args = filterClause(where, args, "hostname", q.Hostname)
+	where, args = filterClause(where, args, "protocol", q.Protocol)
+
+	cols := "avg(neighbors), sum(routes_advertised), sum(routes_received)"
+	if tier.suffix != "" {
+		cols = "avgMerge(neighbors_state), sumMerge(routes_advertised_state), sumMerge(routes_received_state)"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			toStartOfInterval(%s, INTERVAL ? SECOND) AS bucket,
+			%s
+		FROM %s
+		WHERE %s
+		GROUP BY bucket
+		ORDER BY bucket`, tier.bucketCol, cols, tier.table("protocol_metrics"), where)
+
+	rows, err := c.db.QueryContext(ctx, query, append([]interface{}{int64(bucket.Seconds())}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query protocol_metrics: %w", err)
+	}
+	defer rows.Close()
+
+	var points []ProtocolMetricsPoint
+	for rows.Next() {
+		var p ProtocolMetricsPoint
+		if err := rows.Scan(&p.Bucket, &p.AvgNeighbors, &p.SumRoutesAdvertised, &p.SumRoutesReceived); err != nil {
+			return nil, fmt.Errorf("failed to scan protocol_metrics row: %w", err)
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+func (c *ClickHouseClient) queryTrafficMetrics(ctx context.Context, partitionName string, q MetricQuery, bucket time.Duration) ([]TrafficMetricsPoint, error) {
+	tier := selectTier(bucket)
+	where := fmt.Sprintf("%s >= ? AND %s < ? AND partition = ?", tier.bucketCol, tier.bucketCol)
+	args := []interface{}{q.StartTime, q.EndTime, partitionName}
+	where, args = filterClause(where, args, "hostname", q.Hostname)
+	where, args = filterClause(where, args, "interface", q.Interface)
+
+	cols := "avg(throughput_bps), quantile(0.95)(throughput_bps), sum(bytes_dropped), max(queue_length)"
+	if tier.suffix != "" {
+		cols = "avgMerge(throughput_bps_avg_state), quantileMerge(0.95)(throughput_bps_p95_state), sumMerge(bytes_dropped_state), maxMerge(queue_length_state)"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			toStartOfInterval(%s, INTERVAL ? SECOND) AS bucket,
+			%s
+		FROM %s
+		WHERE %s
+		GROUP BY bucket
+		ORDER BY bucket`, tier.bucketCol, cols, tier.table("traffic_metrics"), where)
+
+	rows, err := c.db.QueryContext(ctx, query, append([]interface{}{int64(bucket.Seconds())}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query traffic_metrics: %w", err)
+	}
+	defer rows.Close()
+
+	var points []TrafficMetricsPoint
+	for rows.Next() {
+		var p TrafficMetricsPoint
+		if err := rows.Scan(&p.Bucket, &p.AvgThroughputBps, &p.P95ThroughputBps, &p.SumBytesDropped, &p.MaxQueueLength); err != nil {
+			return nil, fmt.Errorf("failed to scan traffic_metrics row: %w", err)
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+func (c *ClickHouseClient) queryImpairmentMetrics(ctx context.Context, partitionName string, q MetricQuery, bucket time.Duration) ([]ImpairmentMetricsPoint, error) {
+	tier := selectTier(bucket)
+	where := fmt.Sprintf("%s >= ? AND %s < ? AND partition = ?", tier.bucketCol, tier.bucketCol)
+	args := []interface{}{q.StartTime, q.EndTime, partitionName}
+	where, args = filterClause(where, args, "hostname", q.Hostname)
+	where, args = filterClause(where, args, "interface", q.Interface)
+
+	cols := "avg(delay_ms), quantile(0.95)(delay_ms), avg(loss_percentage)"
+	if tier.suffix != "" {
+		cols = "avgMerge(delay_ms_avg_state), quantileMerge(0.95)(delay_ms_p95_state), avgMerge(loss_percentage_state)"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			toStartOfInterval(%s, INTERVAL ? SECOND) AS bucket,
+			%s
+		FROM %s
+		WHERE %s
+		GROUP BY bucket
+		ORDER BY bucket`, tier.bucketCol, cols, tier.table("impairment_metrics"), where)
+
+	rows, err := c.db.QueryContext(ctx, query, append([]interface{}{int64(bucket.Seconds())}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query impairment_metrics: %w", err)
+	}
+	defer rows.Close()
+
+	var points []ImpairmentMetricsPoint
+	for rows.Next() {
+		var p ImpairmentMetricsPoint
+		if err := rows.Scan(&p.Bucket, &p.AvgDelayMs, &p.P95DelayMs, &p.AvgLossPercentage); err != nil {
+			return nil, fmt.Errorf("failed to scan impairment_metrics row: %w", err)
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+func (c *ClickHouseClient) queryCloudMetrics(ctx context.Context, partitionName string, q MetricQuery, bucket time.Duration) ([]CloudMetricsPoint, error) {
+	tier := selectTier(bucket)
+	where := fmt.Sprintf("%s >= ? AND %s < ? AND partition = ?", tier.bucketCol, tier.bucketCol)
+	args := []interface{}{q.StartTime, q.EndTime, partitionName}
+	where, args = filterClause(where, args, "hostname", q.Hostname)
+	where, args = filterClause(where, args, "cloud_provider", q.CloudProvider)
+
+	cols := "sum(resources_total), sum(instances_total), max(connections_active)"
+	if tier.suffix != "" {
+		cols = "sumMerge(resources_total_state), sumMerge(instances_total_state), maxMerge(connections_active_state)"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			toStartOfInterval(%s, INTERVAL ? SECOND) AS bucket,
+			%s
+		FROM %s
+		WHERE %s
+		GROUP BY bucket
+		ORDER BY bucket`, tier.bucketCol, cols, tier.table("cloud_metrics"), where)
+
+	rows, err := c.db.QueryContext(ctx, query, append([]interface{}{int64(bucket.Seconds())}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cloud_metrics: %w", err)
+	}
+	defer rows.Close()
+
+	var points []CloudMetricsPoint
+	for rows.Next() {
+		var p CloudMetricsPoint
+		if err := rows.Scan(&p.Bucket, &p.SumResourcesTotal, &p.SumInstancesTotal, &p.MaxConnectionsActive); err != nil {
+			return nil, fmt.Errorf("failed to scan cloud_metrics row: %w", err)
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
@@ -0,0 +1,185 @@
+package analytics
+
+import (
+	"fmt"
+	"strings"
+)
+
+// rollupWindow is one materialized-view tier: raw rows are continuously
+// rolled up into an AggregatingMergeTree table bucketed at Interval,
+// retained for TTL.
+type rollupWindow struct {
+	suffix   string // table/view name suffix, e.g. "1m"
+	interval string // ClickHouse INTERVAL clause, e.g. "1 MINUTE"
+	ttl      string // ClickHouse INTERVAL clause, e.g. "30 DAY"
+}
+
+// rollupWindows are applied to every rollupTable below, finest first;
+// GetMetrics's query layer picks the coarsest one that still satisfies
+// the requested bucket (see selectTier in query.go).
+var rollupWindows = []rollupWindow{
+	{suffix: "1m", interval: "1 MINUTE", ttl: "30 DAY"},
+	{suffix: "5m", interval: "5 MINUTE", ttl: "90 DAY"},
+	{suffix: "1h", interval: "1 HOUR", ttl: "365 DAY"},
+}
+
+// rollupState is one AggregateFunction column a rollup table carries:
+// selectExpr is how the materialized view derives it from the raw
+// table (an *State aggregate function), columnType is its ClickHouse
+// column type.
+type rollupState struct {
+	alias      string
+	selectExpr string
+	columnType string
+}
+
+// rollupTable describes one raw *_metrics table's rollups. dimension
+// is the extra column (beyond bucket/hostname/partition) the table is
+// grouped and later filtered by, empty if it has none.
+type rollupTable struct {
+	name      string
+	dimension string
+	states    []rollupState
+}
+
+// rollupTables lists every raw metrics table's rollup definition. Each
+// entry's states mirror the aggregations query.go's raw-table queries
+// use, so a rollup table can answer the same query shape by swapping
+// avg/sum/max/quantile for their *Merge equivalents over pre-aggregated
+// state instead of recomputing from raw rows.
+var rollupTables = []rollupTable{
+	{
+		name: "router_metrics",
+		states: []rollupState{
+			{alias: "cpu_usage_state", selectExpr: "avgState(cpu_usage)", columnType: "AggregateFunction(avg, Float64)"},
+			{alias: "memory_usage_state", selectExpr: "avgState(memory_usage)", columnType: "AggregateFunction(avg, Float64)"},
+			{alias: "disk_usage_state", selectExpr: "avgState(disk_usage)", columnType: "AggregateFunction(avg, Float64)"},
+			{alias: "uptime_seconds_state", selectExpr: "maxState(uptime_seconds)", columnType: "AggregateFunction(max, UInt64)"},
+		},
+	},
+	{
+		name:      "protocol_metrics",
+		dimension: "protocol",
+		states: []rollupState{
+			{alias: "neighbors_state", selectExpr: "avgState(neighbors)", columnType: "AggregateFunction(avg, UInt32)"},
+			{alias: "routes_advertised_state", selectExpr: "sumState(routes_advertised)", columnType: "AggregateFunction(sum, UInt32)"},
+			{alias: "routes_received_state", selectExpr: "sumState(routes_received)", columnType: "AggregateFunction(sum, UInt32)"},
+		},
+	},
+	{
+		name:      "traffic_metrics",
+		dimension: "interface",
+		states: []rollupState{
+			{alias: "throughput_bps_avg_state", selectExpr: "avgState(throughput_bps)", columnType: "AggregateFunction(avg, Float64)"},
+			{alias: "throughput_bps_p95_state", selectExpr: "quantileState(0.95)(throughput_bps)", columnType: "AggregateFunction(quantile(0.95), Float64)"},
+			{alias: "bytes_dropped_state", selectExpr: "sumState(bytes_dropped)", columnType: "AggregateFunction(sum, UInt64)"},
+			{alias: "queue_length_state", selectExpr: "maxState(queue_length)", columnType: "AggregateFunction(max, UInt32)"},
+		},
+	},
+	{
+		name:      "impairment_metrics",
+		dimension: "interface",
+		states: []rollupState{
+			{alias: "delay_ms_avg_state", selectExpr: "avgState(delay_ms)", columnType: "AggregateFunction(avg, UInt32)"},
+			{alias: "delay_ms_p95_state", selectExpr: "quantileState(0.95)(delay_ms)", columnType: "AggregateFunction(quantile(0.95), UInt32)"},
+			{alias: "loss_percentage_state", selectExpr: "avgState(loss_percentage)", columnType: "AggregateFunction(avg, Float64)"},
+		},
+	},
+	{
+		name:      "cloud_metrics",
+		dimension: "cloud_provider",
+		states: []rollupState{
+			{alias: "resources_total_state", selectExpr: "sumState(resources_total)", columnType: "AggregateFunction(sum, UInt32)"},
+			{alias: "instances_total_state", selectExpr: "sumState(instances_total)", columnType: "AggregateFunction(sum, UInt32)"},
+			{alias: "connections_active_state", selectExpr: "maxState(connections_active)", columnType: "AggregateFunction(max, UInt32)"},
+		},
+	},
+}
+
+// createRollups creates every rollupTable's 1m/5m/1h AggregatingMergeTree
+// table and the materialized view that continuously feeds it from the
+// corresponding raw table. Rollups vastly outlive their raw rows (see
+// the 48-hour TTL on the raw tables in initializeTables), so a dashboard
+// spanning weeks or months reads a handful of pre-aggregated rows per
+// bucket instead of scanning - and then discarding - everything raw.
+func (c *ClickHouseClient) createRollups() error {
+	for _, t := range rollupTables {
+		for _, w := range rollupWindows {
+			if err := c.createRollupTable(t, w); err != nil {
+				return err
+			}
+			if err := c.createRollupView(t, w); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *ClickHouseClient) createRollupTable(t rollupTable, w rollupWindow) error {
+	tableName := t.name + "_" + w.suffix
+
+	var cols strings.Builder
+	fmt.Fprintf(&cols, "bucket DateTime,\n\t\thostname String,\n\t\tpartition String")
+	if t.dimension != "" {
+		fmt.Fprintf(&cols, ",\n\t\t%s String", t.dimension)
+	}
+	for _, s := range t.states {
+		fmt.Fprintf(&cols, ",\n\t\t%s %s", s.alias, s.columnType)
+	}
+
+	orderBy := rollupOrderBy(t)
+
+	ddl := fmt.Sprintf(`
+	CREATE TABLE IF NOT EXISTS %s (
+		%s
+	) ENGINE = AggregatingMergeTree()
+	ORDER BY %s
+	TTL bucket + INTERVAL %s
+	`, tableName, cols.String(), orderBy, w.ttl)
+
+	if _, err := c.db.Exec(ddl); err != nil {
+		return fmt.Errorf("failed to create %s table: %w", tableName, err)
+	}
+	return nil
+}
+
+func (c *ClickHouseClient) createRollupView(t rollupTable, w rollupWindow) error {
+	viewName := t.name + "_mv_" + w.suffix
+	tableName := t.name + "_" + w.suffix
+
+	var selectCols strings.Builder
+	fmt.Fprintf(&selectCols, "toStartOfInterval(timestamp, INTERVAL %s) AS bucket,\n\t\t\thostname,\n\t\t\tpartition", w.interval)
+	if t.dimension != "" {
+		fmt.Fprintf(&selectCols, ",\n\t\t\t%s", t.dimension)
+	}
+	for _, s := range t.states {
+		fmt.Fprintf(&selectCols, ",\n\t\t\t%s AS %s", s.selectExpr, s.alias)
+	}
+
+	groupBy := "bucket, hostname, partition"
+	if t.dimension != "" {
+		groupBy += ", " + t.dimension
+	}
+
+	ddl := fmt.Sprintf(`
+	CREATE MATERIALIZED VIEW IF NOT EXISTS %s
+	TO %s
+	AS SELECT
+			%s
+		FROM %s
+		GROUP BY %s
+	`, viewName, tableName, selectCols.String(), t.name, groupBy)
+
+	if _, err := c.db.Exec(ddl); err != nil {
+		return fmt.Errorf("failed to create %s materialized view: %w", viewName, err)
+	}
+	return nil
+}
+
+func rollupOrderBy(t rollupTable) string {
+	if t.dimension == "" {
+		return "(bucket, hostname, partition)"
+	}
+	return fmt.Sprintf("(bucket, hostname, partition, %s)", t.dimension)
+}
@@ -1,71 +1,83 @@
 package analytics
 
 import (
-	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+
+	"router-sim/internal/config"
+	routermetrics "router-sim/internal/metrics"
 )
 
 // Service represents the analytics service
 type Service struct {
-	config *config.MonitoringConfig
+	config *config.AnalyticsConfig
 	logger *zap.Logger
+	engine *Engine
 }
 
-// NewService creates a new analytics service
-func NewService(config *config.MonitoringConfig, logger *zap.Logger) (*Service, error) {
+// NewService creates a new analytics service. engine backs StreamMetrics;
+// it may be nil for a Service that only ever serves GetMetrics/GetDashboard/
+// GetReports's mock data.
+func NewService(config *config.AnalyticsConfig, logger *zap.Logger, engine *Engine) (*Service, error) {
 	return &Service{
 		config: config,
 		logger: logger,
+		engine: engine,
 	}, nil
 }
 
 // GetMetrics handles GET /api/v1/analytics/metrics
 func (s *Service) GetMetrics(c *gin.Context) {
-	// Mock metrics data
+	bgpRoutes := routermetrics.RouteCount("bgp")
+	ospfRoutes := routermetrics.RouteCount("ospf")
+	isisRoutes := routermetrics.RouteCount("isis")
+	bgpNeighbors := routermetrics.NeighborCount("bgp")
+	ospfNeighbors := routermetrics.NeighborCount("ospf")
+	isisNeighbors := routermetrics.NeighborCount("isis")
+
+	// Mock metrics data, with the fields the metrics package actually
+	// tracks (routes/neighbors/packets) replaced by its real counters.
 	metrics := map[string]interface{}{
 		"router": map[string]interface{}{
 			"uptime_seconds":    3600,
 			"interfaces_total":  4,
 			"interfaces_up":     3,
-			"routes_total":      150,
+			"routes_total":      bgpRoutes + ospfRoutes + isisRoutes,
 			"neighbors_total":   8,
-			"neighbors_up":      6,
+			"neighbors_up":      bgpNeighbors + ospfNeighbors + isisNeighbors,
 		},
 		"protocols": map[string]interface{}{
 			"bgp": map[string]interface{}{
 				"status":           "up",
-				"neighbors":        3,
+				"neighbors":        bgpNeighbors,
 				"routes_advertised": 50,
-				"routes_received":  45,
+				"routes_received":  bgpRoutes,
 				"updates_sent":     120,
 				"updates_received": 110,
 			},
 			"ospf": map[string]interface{}{
 				"status":           "up",
-				"neighbors":        2,
+				"neighbors":        ospfNeighbors,
 				"routes_advertised": 30,
-				"routes_received":  25,
+				"routes_received":  ospfRoutes,
 				"lsa_sent":         80,
 				"lsa_received":     75,
 			},
 			"isis": map[string]interface{}{
 				"status":           "up",
-				"neighbors":        1,
+				"neighbors":        isisNeighbors,
 				"routes_advertised": 20,
-				"routes_received":  18,
+				"routes_received":  isisRoutes,
 				"lsp_sent":         60,
 				"lsp_received":     55,
 			},
 		},
 		"traffic": map[string]interface{}{
-			"packets_processed":   1000000,
-			"packets_dropped":     5000,
+			"packets_processed":   routermetrics.PacketsProcessed(),
+			"packets_dropped":     routermetrics.PacketsDropped(),
 			"bytes_processed":     500000000,
 			"bytes_dropped":       2500000,
 			"current_throughput":  1000000, // bps
@@ -0,0 +1,115 @@
+package analytics
+
+import "sync"
+
+// StreamFrame is one message published to a Hub topic: ID is the row's
+// own timestamp (RFC3339Nano), so a reconnecting subscriber's
+// Last-Event-ID can be parsed straight back into the time.Time a replay
+// query needs, and Data is the row already marshaled to JSON.
+type StreamFrame struct {
+	ID    string
+	Topic string
+	Data  []byte
+}
+
+// hubQueueSize bounds how many unconsumed frames a subscriber may have
+// buffered before Publish starts dropping its oldest ones, the same
+// drop-oldest-rather-than-block trade-off dropOldestQueue makes for SSE
+// clients in the handlers package.
+const hubQueueSize = 64
+
+// subscription is one Hub.Subscribe call's bookkeeping: topics is the
+// filter the caller asked for (empty means "every topic"), and frames is
+// the channel Publish feeds.
+type subscription struct {
+	topics map[string]bool
+	frames chan StreamFrame
+}
+
+// matches reports whether topic should be delivered to this
+// subscription. A filter entry "kind.value" matches that exact topic; a
+// bare "kind" entry matches any "kind.*" topic, so a subscriber that
+// doesn't care which hostname or severity still only has to ask for
+// "router" or "events" rather than enumerate every value.
+func (s *subscription) matches(topic string) bool {
+	if len(s.topics) == 0 || s.topics[topic] {
+		return true
+	}
+	for i := 0; i < len(topic); i++ {
+		if topic[i] == '.' {
+			return s.topics[topic[:i]]
+		}
+	}
+	return false
+}
+
+// Hub is an in-process fan-out point from ClickHouseClient's batch
+// pipeline to any number of live stream subscribers (the
+// /analytics/stream SSE/WebSocket endpoint). Publish is called once per
+// row as soon as its batch has actually been flushed to ClickHouse — see
+// batchBuffer.onFlushed — so subscribers only ever see rows that are
+// already durable.
+type Hub struct {
+	mu   sync.RWMutex
+	subs map[int64]*subscription
+	seq  int64
+}
+
+// NewHub creates an empty Hub.
+func NewHub() *Hub {
+	return &Hub{subs: make(map[int64]*subscription)}
+}
+
+// Subscribe registers a new subscriber filtered to topics (empty means
+// every topic) and returns the channel Publish feeds plus an unsubscribe
+// func the caller must call (typically deferred) on disconnect.
+func (h *Hub) Subscribe(topics []string, queueSize int) (<-chan StreamFrame, func()) {
+	if queueSize <= 0 {
+		queueSize = hubQueueSize
+	}
+
+	filter := make(map[string]bool, len(topics))
+	for _, t := range topics {
+		filter[t] = true
+	}
+
+	h.mu.Lock()
+	h.seq++
+	id := h.seq
+	sub := &subscription{topics: filter, frames: make(chan StreamFrame, queueSize)}
+	h.subs[id] = sub
+	h.mu.Unlock()
+
+	return sub.frames, func() {
+		h.mu.Lock()
+		delete(h.subs, id)
+		h.mu.Unlock()
+	}
+}
+
+// Publish fans frame out to every subscriber whose filter matches topic.
+// For a subscriber whose queue is full it discards that subscriber's
+// oldest queued frame to make room, rather than blocking the ClickHouse
+// flush path that calls Publish.
+func (h *Hub) Publish(frame StreamFrame) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, sub := range h.subs {
+		if !sub.matches(frame.Topic) {
+			continue
+		}
+		select {
+		case sub.frames <- frame:
+		default:
+			select {
+			case <-sub.frames:
+			default:
+			}
+			select {
+			case sub.frames <- frame:
+			default:
+			}
+		}
+	}
+}
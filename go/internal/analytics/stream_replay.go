@@ -0,0 +1,111 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// replayLimit caps how many rows a single Replay* call returns, so a
+// subscriber reconnecting with a stale Last-Event-ID gets its most
+// recent history rather than however much accumulated since.
+const replayLimit = 500
+
+// replay selects every row from table newer than since, oldest first,
+// optionally narrowed to filterVal in filterCol (skipped when filterVal
+// is empty), and scans each with scan. Every Replay* method below is a
+// thin, type-specific wrapper around this.
+func replay[T any](ctx context.Context, db *sql.DB, table string, since time.Time, filterCol, filterVal string, scan func(*sql.Rows) (T, error)) ([]T, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE timestamp > ?", table)
+	args := []interface{}{since}
+	if filterVal != "" {
+		query += fmt.Sprintf(" AND %s = ?", filterCol)
+		args = append(args, filterVal)
+	}
+	query += " ORDER BY timestamp ASC LIMIT ?"
+	args = append(args, replayLimit)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("replay %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var out []T
+	for rows.Next() {
+		v, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("replay %s: scanning row: %w", table, err)
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+// ReplayRouterMetrics backfills router_metrics rows newer than since, for
+// a given hostname (all hostnames if empty), for a reconnecting
+// /analytics/stream subscriber.
+func (c *ClickHouseClient) ReplayRouterMetrics(ctx context.Context, since time.Time, hostname string) ([]RouterMetrics, error) {
+	return replay(ctx, c.db, "router_metrics", since, "hostname", hostname, func(rows *sql.Rows) (RouterMetrics, error) {
+		var m RouterMetrics
+		err := rows.Scan(&m.Timestamp, &m.Hostname, &m.Partition, &m.UptimeSeconds, &m.InterfacesTotal, &m.InterfacesUp,
+			&m.RoutesTotal, &m.NeighborsTotal, &m.NeighborsUp, &m.CPUUsage, &m.MemoryUsage, &m.DiskUsage)
+		return m, err
+	})
+}
+
+// ReplayProtocolMetrics backfills protocol_metrics rows newer than
+// since, for a given hostname (all hostnames if empty).
+func (c *ClickHouseClient) ReplayProtocolMetrics(ctx context.Context, since time.Time, hostname string) ([]ProtocolMetrics, error) {
+	return replay(ctx, c.db, "protocol_metrics", since, "hostname", hostname, func(rows *sql.Rows) (ProtocolMetrics, error) {
+		var m ProtocolMetrics
+		err := rows.Scan(&m.Timestamp, &m.Hostname, &m.Partition, &m.Protocol, &m.Status, &m.Neighbors, &m.RoutesAdvertised,
+			&m.RoutesReceived, &m.UpdatesSent, &m.UpdatesReceived, &m.LSASent, &m.LSAReceived,
+			&m.LSPSent, &m.LSPReceived)
+		return m, err
+	})
+}
+
+// ReplayTrafficMetrics backfills traffic_metrics rows newer than since,
+// for a given hostname (all hostnames if empty).
+func (c *ClickHouseClient) ReplayTrafficMetrics(ctx context.Context, since time.Time, hostname string) ([]TrafficMetrics, error) {
+	return replay(ctx, c.db, "traffic_metrics", since, "hostname", hostname, func(rows *sql.Rows) (TrafficMetrics, error) {
+		var m TrafficMetrics
+		err := rows.Scan(&m.Timestamp, &m.Hostname, &m.Partition, &m.Interface, &m.PacketsProcessed, &m.PacketsDropped,
+			&m.BytesProcessed, &m.BytesDropped, &m.ThroughputBps, &m.QueueLength)
+		return m, err
+	})
+}
+
+// ReplayImpairmentMetrics backfills impairment_metrics rows newer than
+// since, for a given hostname (all hostnames if empty).
+func (c *ClickHouseClient) ReplayImpairmentMetrics(ctx context.Context, since time.Time, hostname string) ([]ImpairmentMetrics, error) {
+	return replay(ctx, c.db, "impairment_metrics", since, "hostname", hostname, func(rows *sql.Rows) (ImpairmentMetrics, error) {
+		var m ImpairmentMetrics
+		err := rows.Scan(&m.Timestamp, &m.Hostname, &m.Partition, &m.Interface, &m.ImpairmentType, &m.DelayMs, &m.JitterMs,
+			&m.LossPercentage, &m.DuplicatePercentage, &m.CorruptPercentage, &m.ReorderPercentage)
+		return m, err
+	})
+}
+
+// ReplayCloudMetrics backfills cloud_metrics rows newer than since, for a
+// given hostname (all hostnames if empty).
+func (c *ClickHouseClient) ReplayCloudMetrics(ctx context.Context, since time.Time, hostname string) ([]CloudMetrics, error) {
+	return replay(ctx, c.db, "cloud_metrics", since, "hostname", hostname, func(rows *sql.Rows) (CloudMetrics, error) {
+		var m CloudMetrics
+		err := rows.Scan(&m.Timestamp, &m.Hostname, &m.Partition, &m.CloudProvider, &m.ResourcesTotal, &m.InstancesTotal,
+			&m.NetworksTotal, &m.StoragesTotal, &m.GatewaysTotal, &m.ConnectionsActive)
+		return m, err
+	})
+}
+
+// ReplayEvents backfills events rows newer than since, for a given
+// severity (every severity if empty).
+func (c *ClickHouseClient) ReplayEvents(ctx context.Context, since time.Time, severity string) ([]Event, error) {
+	return replay(ctx, c.db, "events", since, "severity", severity, func(rows *sql.Rows) (Event, error) {
+		var e Event
+		err := rows.Scan(&e.Timestamp, &e.Hostname, &e.Partition, &e.EventType, &e.Severity, &e.Message, &e.Details)
+		return e, err
+	})
+}
@@ -0,0 +1,170 @@
+package analytics
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// streamUpgrader upgrades GET /api/v1/analytics/stream the same way
+// main.go's /ws endpoint does: any origin is allowed, since this API has
+// no cookie-based session to protect against cross-site WebSocket
+// hijacking.
+var streamUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// streamSubscribeBuffer is how many MetricUpdates StreamMetrics buffers
+// per client; Engine.Publish skips a client whose buffer is full rather
+// than letting a slow client's backlog grow unbounded.
+const streamSubscribeBuffer = 64
+
+// streamHeartbeatInterval is how often StreamMetrics pings an idle
+// connection so a client (or an intermediate proxy) can tell the stream
+// is still alive between MetricUpdates.
+const streamHeartbeatInterval = 30 * time.Second
+
+// streamFilter is one WebSocket client's server-side filter, parsed from
+// its query parameters: source= matches MetricUpdate.Source exactly,
+// metric= narrows each update down to a comma-separated allow-list of
+// metric keys, and min_interval rate-limits how often a frame is sent.
+type streamFilter struct {
+	source      string
+	metrics     map[string]bool
+	minInterval time.Duration
+}
+
+func parseStreamFilter(c *gin.Context) streamFilter {
+	filter := streamFilter{source: c.Query("source")}
+
+	if raw := c.Query("metric"); raw != "" {
+		filter.metrics = make(map[string]bool)
+		for _, m := range strings.Split(raw, ",") {
+			if m = strings.TrimSpace(m); m != "" {
+				filter.metrics[m] = true
+			}
+		}
+	}
+
+	if raw := c.Query("min_interval"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil {
+			filter.minInterval = d
+		}
+	}
+
+	return filter
+}
+
+// matches reports whether update should be delivered to a client with
+// this filter at all.
+func (f streamFilter) matches(update MetricUpdate) bool {
+	return f.source == "" || update.Source == f.source
+}
+
+// apply narrows update.Metrics down to the keys named by metric=,
+// leaving update unchanged if no metric filter was set.
+func (f streamFilter) apply(update MetricUpdate) MetricUpdate {
+	if f.metrics == nil {
+		return update
+	}
+	filtered := make(map[string]interface{}, len(f.metrics))
+	for k, v := range update.Metrics {
+		if f.metrics[k] {
+			filtered[k] = v
+		}
+	}
+	update.Metrics = filtered
+	return update
+}
+
+// coalesce drains any further updates already queued on ch into the
+// newest one, reporting how many it dropped along the way. It lets
+// StreamMetrics fall behind a fast Engine without its per-client backlog
+// growing without bound: once the client catches up it sees the latest
+// sample plus a dropped count, not a queue of stale ones.
+func coalesce(ch chan MetricUpdate, latest MetricUpdate) (MetricUpdate, int) {
+	dropped := 0
+	for {
+		select {
+		case next := <-ch:
+			latest = next
+			dropped++
+		default:
+			return latest, dropped
+		}
+	}
+}
+
+// StreamMetrics handles GET /api/v1/analytics/stream: it upgrades the
+// connection to a WebSocket, subscribes to s.engine's MetricUpdates, and
+// pushes JSON frames - filtered by the source/metric/min_interval query
+// parameters - until the client disconnects. A slow client never blocks
+// Engine.Publish: updates queue in a bounded per-client buffer, and each
+// time StreamMetrics takes one off it coalesces whatever backlog has
+// queued behind it down to the newest sample, reporting how many it
+// skipped via a {"dropped": N} frame alongside it.
+func (s *Service) StreamMetrics(c *gin.Context) {
+	if s.engine == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "analytics engine not configured"})
+		return
+	}
+
+	conn, err := streamUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	filter := parseStreamFilter(c)
+
+	updates := make(chan MetricUpdate, streamSubscribeBuffer)
+	s.engine.Subscribe(updates)
+	defer s.engine.Unsubscribe(updates)
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	var lastSent time.Time
+	var dropped int
+
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+
+			latest, extra := coalesce(updates, update)
+			dropped += extra
+			update = latest
+
+			if !filter.matches(update) {
+				continue
+			}
+			if filter.minInterval > 0 && time.Since(lastSent) < filter.minInterval {
+				dropped++
+				continue
+			}
+
+			if err := conn.WriteJSON(filter.apply(update)); err != nil {
+				return
+			}
+			lastSent = time.Now()
+
+			if dropped > 0 {
+				if err := conn.WriteJSON(gin.H{"dropped": dropped}); err != nil {
+					return
+				}
+				dropped = 0
+			}
+
+		case <-heartbeat.C:
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
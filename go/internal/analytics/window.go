@@ -0,0 +1,135 @@
+package analytics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// SampleWindow is a ring buffer of MetricUpdate samples bounded by age
+// rather than count: samples older than the window duration are pruned
+// on every Add, so Aggregate always reflects only the trailing window.
+// It backs StreamAnalyticsAggregate's server-side percentile and rate
+// computation, letting a dashboard subscribe to a rolling p50/p95/p99
+// instead of polling a snapshot endpoint and computing it client-side.
+type SampleWindow struct {
+	mu      sync.Mutex
+	dur     time.Duration
+	samples []MetricUpdate
+}
+
+// NewSampleWindow returns a SampleWindow that retains samples no older
+// than dur.
+func NewSampleWindow(dur time.Duration) *SampleWindow {
+	return &SampleWindow{dur: dur}
+}
+
+// Add appends update to the window and prunes samples it has aged out.
+func (w *SampleWindow) Add(update MetricUpdate) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.samples = append(w.samples, update)
+	w.pruneLocked(update.Timestamp)
+}
+
+func (w *SampleWindow) pruneLocked(now time.Time) {
+	cutoff := now.Add(-w.dur)
+	i := 0
+	for ; i < len(w.samples); i++ {
+		if w.samples[i].Timestamp.After(cutoff) {
+			break
+		}
+	}
+	w.samples = w.samples[i:]
+}
+
+// WindowAggregate is what Aggregate computes over a SampleWindow's
+// current contents: percentiles per numeric metric key plus, when
+// requested, a single window-wide sample rate.
+type WindowAggregate struct {
+	Metrics       map[string]map[string]float64 `json:"metrics"`
+	RatePerSecond float64                       `json:"rate_per_second,omitempty"`
+	SampleCount   int                           `json:"sample_count"`
+}
+
+// Aggregate computes aggregations ("p50", "p95", "p99", "rate") over
+// every numeric metric key present in the window's samples. "rate" is a
+// single samples-observed-per-second figure for the whole window rather
+// than a per-metric one, since the engine publishes one MetricUpdate per
+// collection tick rather than a counter per metric.
+func (w *SampleWindow) Aggregate(aggregations []string) WindowAggregate {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	values := map[string][]float64{}
+	for _, s := range w.samples {
+		for key, v := range s.Metrics {
+			if f, ok := toFloat64(v); ok {
+				values[key] = append(values[key], f)
+			}
+		}
+	}
+
+	out := WindowAggregate{
+		Metrics:     make(map[string]map[string]float64, len(values)),
+		SampleCount: len(w.samples),
+	}
+
+	for key, vs := range values {
+		sort.Float64s(vs)
+		metric := make(map[string]float64, len(aggregations))
+		for _, agg := range aggregations {
+			switch agg {
+			case "p50":
+				metric["p50"] = percentile(vs, 0.50)
+			case "p95":
+				metric["p95"] = percentile(vs, 0.95)
+			case "p99":
+				metric["p99"] = percentile(vs, 0.99)
+			}
+		}
+		out.Metrics[key] = metric
+	}
+
+	for _, agg := range aggregations {
+		if agg == "rate" && w.dur > 0 {
+			out.RatePerSecond = float64(len(w.samples)) / w.dur.Seconds()
+		}
+	}
+
+	return out
+}
+
+// percentile returns the p-th percentile (0..1) of sorted, a
+// nearest-rank estimate that needs no interpolation.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p * float64(len(sorted)-1))
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
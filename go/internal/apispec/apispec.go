@@ -0,0 +1,271 @@
+// Package apispec replaces the server's hand-rolled `map[string]interface{}`
+// request binding with typed route registration, borrowed from the split
+// podman uses between its API handler funcs and its libpod business logic:
+// each endpoint declares a Go struct for its input, registers a handler that
+// receives the already-decoded value, and the package derives an OpenAPI 3.0
+// document from the same registration instead of hand-maintained docs.
+//
+// Query-string parameters (GET/DELETE endpoints) are decoded with
+// github.com/gorilla/schema; JSON bodies (POST/PUT/PATCH) are decoded with
+// the standard library decoder in strict mode, so a typo'd or renamed field
+// in a request body is rejected instead of silently ignored. Both paths
+// report failures as a ValidationError with one FieldError per offending
+// field, so every route group in this server answers malformed requests the
+// same way.
+package apispec
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/schema"
+)
+
+var queryDecoder = schema.NewDecoder()
+
+func init() {
+	queryDecoder.IgnoreUnknownKeys(false)
+}
+
+// FieldError describes one invalid request field, named by its JSON (or
+// query-parameter) path, so clients can highlight the offending input
+// instead of parsing a free-form message.
+type FieldError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ValidationError is the uniform error returned when request decoding
+// fails, carrying one FieldError per offending field.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		parts[i] = fmt.Sprintf("%s: %s", fe.Path, fe.Message)
+	}
+	return "invalid request: " + strings.Join(parts, "; ")
+}
+
+// decodeJSONBody decodes r's JSON body into dst, rejecting fields dst does
+// not declare rather than dropping them on the floor.
+func decodeJSONBody(r *http.Request, dst interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return &ValidationError{Errors: []FieldError{{Path: jsonErrorPath(err), Message: err.Error()}}}
+	}
+	return nil
+}
+
+// jsonErrorPath extracts the offending field name from the handful of
+// error shapes encoding/json returns for strict decoding, falling back to
+// "body" when the field can't be determined.
+func jsonErrorPath(err error) string {
+	if te, ok := err.(*json.UnmarshalTypeError); ok && te.Field != "" {
+		return te.Field
+	}
+	if msg := err.Error(); strings.HasPrefix(msg, "json: unknown field ") {
+		return strings.Trim(strings.TrimPrefix(msg, "json: unknown field "), `"`)
+	}
+	return "body"
+}
+
+// decodeQuery decodes url.Values into dst with gorilla/schema, used for
+// GET/DELETE endpoints that accept filter, pagination, or selector
+// parameters.
+func decodeQuery(values map[string][]string, dst interface{}) error {
+	if err := queryDecoder.Decode(dst, values); err != nil {
+		if multi, ok := err.(schema.MultiError); ok {
+			fieldErrs := make([]FieldError, 0, len(multi))
+			for path, fe := range multi {
+				fieldErrs = append(fieldErrs, FieldError{Path: path, Message: fe.Error()})
+			}
+			return &ValidationError{Errors: fieldErrs}
+		}
+		return &ValidationError{Errors: []FieldError{{Path: "query", Message: err.Error()}}}
+	}
+	return nil
+}
+
+// Route describes one typed endpoint: its method and path, a constructor
+// for a fresh request value (nil for endpoints that take no input), the
+// handler that runs once the request has been decoded, and the metadata
+// used to describe it in the generated OpenAPI document.
+type Route struct {
+	Method      string
+	Path        string
+	Summary     string
+	Tags        []string
+	NewRequest  func() interface{}
+	Handler     func(c *gin.Context, req interface{})
+}
+
+// Builder collects the Routes for one or more route groups, mounts them
+// onto a gin router with uniform request decoding, and renders an OpenAPI
+// 3.0 document describing everything it mounted.
+type Builder struct {
+	routes []Route
+}
+
+// NewBuilder returns an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Add registers a Route. Route groups call this once per endpoint from
+// their own register_*.go file.
+func (b *Builder) Add(r Route) {
+	b.routes = append(b.routes, r)
+}
+
+// Mount installs every registered Route on router, decoding each request
+// before invoking its Handler and answering decode failures with a
+// uniform 400 response.
+func (b *Builder) Mount(router gin.IRoutes) {
+	for _, route := range b.routes {
+		route := route
+		router.Handle(route.Method, route.Path, func(c *gin.Context) {
+			if route.NewRequest == nil {
+				route.Handler(c, nil)
+				return
+			}
+			req := route.NewRequest()
+			if err := bindRequest(c, req); err != nil {
+				writeValidationError(c, err)
+				return
+			}
+			route.Handler(c, req)
+		})
+	}
+}
+
+func bindRequest(c *gin.Context, req interface{}) error {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodDelete:
+		return decodeQuery(map[string][]string(c.Request.URL.Query()), req)
+	default:
+		if c.Request.ContentLength == 0 {
+			return nil
+		}
+		return decodeJSONBody(c.Request, req)
+	}
+}
+
+func writeValidationError(c *gin.Context, err error) {
+	if verr, ok := err.(*ValidationError); ok {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": verr.Errors})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// OpenAPI renders every registered Route as an OpenAPI 3.0 document. It is
+// built directly from Route metadata and the reflected shape of each
+// NewRequest value, so the spec can never drift from what is actually
+// mounted.
+func (b *Builder) OpenAPI(title, version string) map[string]interface{} {
+	paths := map[string]interface{}{}
+	for _, route := range b.routes {
+		item, _ := paths[route.Path].(map[string]interface{})
+		if item == nil {
+			item = map[string]interface{}{}
+			paths[route.Path] = item
+		}
+		op := map[string]interface{}{
+			"summary":   route.Summary,
+			"tags":      route.Tags,
+			"responses": map[string]interface{}{"200": map[string]interface{}{"description": "OK"}},
+		}
+		if route.NewRequest != nil {
+			schemaDoc := structSchema(reflect.TypeOf(route.NewRequest()))
+			switch route.Method {
+			case http.MethodGet, http.MethodDelete:
+				op["parameters"] = queryParameters(schemaDoc)
+			default:
+				op["requestBody"] = map[string]interface{}{
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{"schema": schemaDoc},
+					},
+				}
+			}
+		}
+		item[strings.ToLower(route.Method)] = op
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.0",
+		"info":    map[string]interface{}{"title": title, "version": version},
+		"paths":   paths,
+	}
+}
+
+// structSchema derives a minimal OpenAPI schema object from a request
+// struct's exported fields, keyed by their `json` tag.
+func structSchema(t reflect.Type) map[string]interface{} {
+	props := map[string]interface{}{}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return map[string]interface{}{"type": "object"}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			name = strings.Split(tag, ",")[0]
+		} else if tag := field.Tag.Get("schema"); tag != "" {
+			name = strings.Split(tag, ",")[0]
+		}
+		if name == "-" {
+			continue
+		}
+		props[name] = map[string]interface{}{"type": openAPIType(field.Type)}
+	}
+	return map[string]interface{}{"type": "object", "properties": props}
+}
+
+func openAPIType(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return "string"
+	}
+}
+
+// queryParameters flattens a structSchema's properties into OpenAPI
+// "in: query" parameter entries.
+func queryParameters(schemaDoc map[string]interface{}) []map[string]interface{} {
+	props, _ := schemaDoc["properties"].(map[string]interface{})
+	params := make([]map[string]interface{}, 0, len(props))
+	for name, propSchema := range props {
+		params = append(params, map[string]interface{}{
+			"name":   name,
+			"in":     "query",
+			"schema": propSchema,
+		})
+	}
+	return params
+}
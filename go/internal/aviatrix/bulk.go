@@ -0,0 +1,281 @@
+package aviatrix
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// BulkStatus is where one item stands in a bulk run.
+type BulkStatus string
+
+const (
+	BulkRunning BulkStatus = "running"
+	BulkDone    BulkStatus = "done"
+	BulkFailed  BulkStatus = "failed"
+)
+
+// BulkEvent reports one VPN user's progress through a bulk run, the unit
+// a CLI renders as one line of per-user progress — modeled on the Log
+// messages a server-streaming activation RPC emits per step.
+type BulkEvent struct {
+	Index   int
+	User    *VPNUser
+	Status  BulkStatus
+	Err     error
+	Message string
+}
+
+// BulkOptions tunes a bulk run.
+type BulkOptions struct {
+	// Concurrency bounds how many items are in flight at once. Default 4.
+	Concurrency int
+	// MaxRetries bounds retries per item on a 429/5xx response. Default 3.
+	MaxRetries int
+	// CheckpointFile, if set, is read at startup to skip items already
+	// marked BulkDone and written to after every item so an interrupted
+	// run can be resumed by calling again with the same file and the
+	// same (or a superset) input slice.
+	CheckpointFile string
+}
+
+// bulkCheckpoint is CheckpointFile's on-disk shape. RunID seeds every
+// item's idempotency key, and is preserved across a resume so a retried
+// POST for an already-attempted item reuses the same key instead of
+// minting a new one.
+type bulkCheckpoint struct {
+	RunID string          `json:"run_id"`
+	Done  map[string]bool `json:"done"`
+}
+
+func loadCheckpoint(path string) (*bulkCheckpoint, error) {
+	if path == "" {
+		return newCheckpoint()
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return newCheckpoint()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("aviatrix: reading checkpoint %q: %w", path, err)
+	}
+
+	var cp bulkCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("aviatrix: decoding checkpoint %q: %w", path, err)
+	}
+	if cp.Done == nil {
+		cp.Done = make(map[string]bool)
+	}
+	return &cp, nil
+}
+
+func newCheckpoint() (*bulkCheckpoint, error) {
+	runID, err := randomRunID()
+	if err != nil {
+		return nil, err
+	}
+	return &bulkCheckpoint{RunID: runID, Done: make(map[string]bool)}, nil
+}
+
+func randomRunID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("aviatrix: generating bulk run ID: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// bulkRunner drives one bulk run's worker pool, checkpoint, and retry
+// policy; BulkCreateVPNUsers and BulkDeleteVPNUsers each configure one
+// and supply the per-item work function.
+type bulkRunner struct {
+	opts BulkOptions
+
+	cpMu sync.Mutex
+	cp   *bulkCheckpoint
+}
+
+func newBulkRunner(opts BulkOptions) (*bulkRunner, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	if opts.MaxRetries <= 0 {
+		opts.MaxRetries = 3
+	}
+
+	cp, err := loadCheckpoint(opts.CheckpointFile)
+	if err != nil {
+		return nil, err
+	}
+	return &bulkRunner{opts: opts, cp: cp}, nil
+}
+
+func (r *bulkRunner) isDone(key string) bool {
+	r.cpMu.Lock()
+	defer r.cpMu.Unlock()
+	return r.cp.Done[key]
+}
+
+func (r *bulkRunner) markDone(key string) error {
+	r.cpMu.Lock()
+	defer r.cpMu.Unlock()
+
+	r.cp.Done[key] = true
+	if r.opts.CheckpointFile == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(r.cp)
+	if err != nil {
+		return fmt.Errorf("aviatrix: encoding checkpoint: %w", err)
+	}
+	if err := os.WriteFile(r.opts.CheckpointFile, data, 0o644); err != nil {
+		return fmt.Errorf("aviatrix: writing checkpoint %q: %w", r.opts.CheckpointFile, err)
+	}
+	return nil
+}
+
+func (r *bulkRunner) idempotencyKey(index int) string {
+	return r.cp.RunID + "-" + strconv.Itoa(index)
+}
+
+// run drives work(index) for every index in [0, n) across r.opts.Concurrency
+// workers, skipping indices the checkpoint already marked done, retrying a
+// retryable failure up to r.opts.MaxRetries times with exponential backoff,
+// and streaming each index's BulkEvents on the returned channel (a
+// BulkRunning event per retry, then a final BulkDone or BulkFailed), which
+// is closed once every index has been attempted.
+func (r *bulkRunner) run(ctx context.Context, n int, work func(ctx context.Context, index int) (*VPNUser, string, error)) <-chan BulkEvent {
+	events := make(chan BulkEvent, n)
+	indices := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < r.opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indices {
+				r.runOne(ctx, index, work, events)
+			}
+		}()
+	}
+
+	go func() {
+		for i := 0; i < n; i++ {
+			key := r.idempotencyKey(i)
+			if r.isDone(key) {
+				continue
+			}
+			select {
+			case indices <- i:
+			case <-ctx.Done():
+				close(indices)
+				wg.Wait()
+				close(events)
+				return
+			}
+		}
+		close(indices)
+		wg.Wait()
+		close(events)
+	}()
+
+	return events
+}
+
+func (r *bulkRunner) runOne(ctx context.Context, index int, work func(ctx context.Context, index int) (*VPNUser, string, error), events chan<- BulkEvent) {
+	bo := backoff.NewExponentialBackOff()
+
+	var lastErr error
+	for attempt := 0; attempt <= r.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(bo.NextBackOff()):
+			case <-ctx.Done():
+				events <- BulkEvent{Index: index, Status: BulkFailed, Err: ctx.Err(), Message: "canceled while waiting to retry"}
+				return
+			}
+			events <- BulkEvent{Index: index, Status: BulkRunning, Message: fmt.Sprintf("retrying (attempt %d)", attempt+1)}
+		}
+
+		user, message, err := work(ctx, index)
+		if err == nil {
+			events <- BulkEvent{Index: index, User: user, Status: BulkDone, Message: message}
+			if cpErr := r.markDone(r.idempotencyKey(index)); cpErr != nil {
+				events <- BulkEvent{Index: index, User: user, Status: BulkFailed, Err: cpErr, Message: "done but failed to checkpoint"}
+			}
+			return
+		}
+
+		lastErr = err
+		if !isRetryableStatus(err) {
+			break
+		}
+	}
+
+	events <- BulkEvent{Index: index, Status: BulkFailed, Err: lastErr, Message: "giving up after retries"}
+}
+
+func isRetryableStatus(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Retryable
+}
+
+// BulkCreateVPNUsers creates every user in users, one idempotency-keyed
+// POST per user (so a retried attempt can't create a duplicate), up to
+// opts.Concurrency at a time, retrying a 429/5xx response with
+// exponential backoff up to opts.MaxRetries times. If opts.CheckpointFile
+// is set, a previously interrupted run resumes by skipping any user
+// already marked BulkDone in that file instead of recreating it.
+func (c *EnhancedClient) BulkCreateVPNUsers(ctx context.Context, users []*VPNUser, opts BulkOptions) (<-chan BulkEvent, error) {
+	runner, err := newBulkRunner(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return runner.run(ctx, len(users), func(ctx context.Context, index int) (*VPNUser, string, error) {
+		user := users[index]
+		headers := map[string]string{"Idempotency-Key": runner.idempotencyKey(index)}
+
+		var created VPNUser
+		if err := c.DoWithHeaders(ctx, http.MethodPost, "/vpn-users", nil, user, headers, &created, http.StatusCreated); err != nil {
+			return nil, "", fmt.Errorf("failed to create VPN user %s: %w", user.Username, err)
+		}
+		return &created, "created", nil
+	}), nil
+}
+
+// BulkDeleteVPNUsers deletes every user ID in ids, the same
+// concurrency/retry/checkpoint/idempotency-key behavior as
+// BulkCreateVPNUsers.
+func (c *EnhancedClient) BulkDeleteVPNUsers(ctx context.Context, ids []string, opts BulkOptions) (<-chan BulkEvent, error) {
+	runner, err := newBulkRunner(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return runner.run(ctx, len(ids), func(ctx context.Context, index int) (*VPNUser, string, error) {
+		id := ids[index]
+		headers := map[string]string{"Idempotency-Key": runner.idempotencyKey(index)}
+
+		if err := c.DoWithHeaders(ctx, http.MethodDelete, "/vpn-users/"+id, nil, nil, headers, nil, http.StatusNoContent); err != nil {
+			return nil, "", fmt.Errorf("failed to delete VPN user %s: %w", id, err)
+		}
+		return &VPNUser{ID: id}, "deleted", nil
+	}), nil
+}
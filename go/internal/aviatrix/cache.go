@@ -0,0 +1,475 @@
+package aviatrix
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/hashstructure/v2"
+)
+
+// ResourceKind identifies one of the object kinds Cache indexes.
+type ResourceKind string
+
+const (
+	KindGateway        ResourceKind = "gateway"
+	KindTransitGateway ResourceKind = "transit_gateway"
+	KindSpokeGateway   ResourceKind = "spoke_gateway"
+	KindVPNGateway     ResourceKind = "vpn_gateway"
+	KindRoute          ResourceKind = "route"
+	KindBGPNeighbor    ResourceKind = "bgp_neighbor"
+)
+
+// DefaultCacheTTL is how long a cached object is trusted before a cache-aware
+// getter falls back to the API, when the caller doesn't supply its own TTL.
+const DefaultCacheTTL = 30 * time.Second
+
+// EventType is the kind of change Cache's Sync loop detected.
+type EventType string
+
+const (
+	EventAdded   EventType = "added"
+	EventUpdated EventType = "updated"
+	EventDeleted EventType = "deleted"
+)
+
+// Event is one change Sync diffed out of a List* resync, published to every
+// subscriber registered through Subscribe.
+type Event struct {
+	Kind   ResourceKind
+	ID     string
+	Object interface{}
+	Type   EventType
+}
+
+// secondaryIndexes extracts the {name: value} secondary keys a given object
+// is looked up by, a much smaller surface than go-memdb's full schema but
+// enough to serve GetGatewayCached-style lookups by CloudType/Region/VPC/
+// TransitGW the way this package actually queries.
+func secondaryIndexes(obj interface{}) map[string]string {
+	switch v := obj.(type) {
+	case Gateway:
+		return map[string]string{"cloud_type": v.CloudType, "region": v.Region, "vpc": v.VPC}
+	case TransitGateway:
+		return map[string]string{"cloud_type": v.CloudType, "region": v.Region, "vpc": v.VPC}
+	case SpokeGateway:
+		return map[string]string{"cloud_type": v.CloudType, "region": v.Region, "vpc": v.VPC, "transit_gw": v.TransitGW}
+	case VPNGateway:
+		return map[string]string{"cloud_type": v.CloudType, "region": v.Region, "vpc": v.VPC}
+	default:
+		return nil
+	}
+}
+
+func idOf(obj interface{}) string {
+	switch v := obj.(type) {
+	case Gateway:
+		return v.ID
+	case TransitGateway:
+		return v.ID
+	case SpokeGateway:
+		return v.ID
+	case VPNGateway:
+		return v.ID
+	case Route:
+		return v.ID
+	case BGPNeighbor:
+		return v.ID
+	default:
+		return ""
+	}
+}
+
+func hashOf(obj interface{}) uint64 {
+	h, err := hashstructure.Hash(obj, hashstructure.FormatV2, nil)
+	if err != nil {
+		return 0
+	}
+	return h
+}
+
+// cacheEntry is one object a kindStore holds, alongside the hash Sync last
+// saw it at and the time it stops being trusted by a cache-aware getter.
+type cacheEntry struct {
+	object    interface{}
+	hash      uint64
+	expiresAt time.Time
+}
+
+// kindStore is a thread-safe, ID-keyed cache of one ResourceKind with
+// secondary indices built from secondaryIndexes, the per-kind building
+// block Cache's Sync loop and Invalidate both operate on.
+type kindStore struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	objects map[string]*cacheEntry
+	indexes map[string]map[string]map[string]struct{} // index name -> value -> set of IDs
+}
+
+func newKindStore(ttl time.Duration) *kindStore {
+	return &kindStore{
+		ttl:     ttl,
+		objects: make(map[string]*cacheEntry),
+		indexes: make(map[string]map[string]map[string]struct{}),
+	}
+}
+
+func (s *kindStore) indexInto(id string, obj interface{}) {
+	for name, value := range secondaryIndexes(obj) {
+		if value == "" {
+			continue
+		}
+		byValue, ok := s.indexes[name]
+		if !ok {
+			byValue = make(map[string]map[string]struct{})
+			s.indexes[name] = byValue
+		}
+		ids, ok := byValue[value]
+		if !ok {
+			ids = make(map[string]struct{})
+			byValue[value] = ids
+		}
+		ids[id] = struct{}{}
+	}
+}
+
+func (s *kindStore) deindex(id string, obj interface{}) {
+	for name, value := range secondaryIndexes(obj) {
+		if ids, ok := s.indexes[name][value]; ok {
+			delete(ids, id)
+		}
+	}
+}
+
+// put inserts or replaces id's entry, re-indexing it and resetting its TTL.
+// It reports whether the object actually changed, so Sync only emits an
+// Updated event when the hash moved.
+func (s *kindStore) put(id string, obj interface{}) (changed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash := hashOf(obj)
+	if existing, ok := s.objects[id]; ok {
+		if existing.hash == hash {
+			existing.expiresAt = time.Now().Add(s.ttl)
+			return false
+		}
+		s.deindex(id, existing.object)
+	}
+
+	s.objects[id] = &cacheEntry{object: obj, hash: hash, expiresAt: time.Now().Add(s.ttl)}
+	s.indexInto(id, obj)
+	return true
+}
+
+func (s *kindStore) delete(id string) (obj interface{}, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.objects[id]
+	if !ok {
+		return nil, false
+	}
+	s.deindex(id, existing.object)
+	delete(s.objects, id)
+	return existing.object, true
+}
+
+// get returns id's cached object if present and not expired.
+func (s *kindStore) get(id string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.objects[id]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.object, true
+}
+
+func (s *kindStore) list() []interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]interface{}, 0, len(s.objects))
+	for _, entry := range s.objects {
+		out = append(out, entry.object)
+	}
+	return out
+}
+
+func (s *kindStore) listIDs() map[string]struct{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make(map[string]struct{}, len(s.objects))
+	for id := range s.objects {
+		ids[id] = struct{}{}
+	}
+	return ids
+}
+
+// byIndex returns every cached object whose indexName secondary index
+// equals value.
+func (s *kindStore) byIndex(indexName, value string) []interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := s.indexes[indexName][value]
+	out := make([]interface{}, 0, len(ids))
+	for id := range ids {
+		if entry, ok := s.objects[id]; ok {
+			out = append(out, entry.object)
+		}
+	}
+	return out
+}
+
+// Cache layers a memdb-style, ID-and-secondary-index-keyed cache over
+// Client, the same list-then-diff design pkg/gateway/k8s's InformerFactory
+// uses against the Gateway API, applied here to cut repeat List* calls and
+// fan change notifications out to subscribers instead.
+type Cache struct {
+	client *Client
+	stores map[ResourceKind]*kindStore
+
+	subMu sync.Mutex
+	subs  map[chan Event]struct{}
+}
+
+// NewCache builds a Cache over client. ttl is how long an entry is trusted
+// by GetGatewayCached/ListSpokeGatewaysCached before they fall back to the
+// API; DefaultCacheTTL is used if ttl is zero.
+func NewCache(client *Client, ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	return &Cache{
+		client: client,
+		stores: map[ResourceKind]*kindStore{
+			KindGateway:        newKindStore(ttl),
+			KindTransitGateway: newKindStore(ttl),
+			KindSpokeGateway:   newKindStore(ttl),
+			KindVPNGateway:     newKindStore(ttl),
+			KindRoute:          newKindStore(ttl),
+			KindBGPNeighbor:    newKindStore(ttl),
+		},
+		subs: make(map[chan Event]struct{}),
+	}
+}
+
+// Subscribe registers a new Event subscriber. The returned channel is
+// buffered; a subscriber that doesn't keep up has events dropped rather
+// than blocking Sync. Call the returned func to unsubscribe.
+func (c *Cache) Subscribe(buffer int) (<-chan Event, func()) {
+	if buffer <= 0 {
+		buffer = 32
+	}
+	ch := make(chan Event, buffer)
+
+	c.subMu.Lock()
+	c.subs[ch] = struct{}{}
+	c.subMu.Unlock()
+
+	unsubscribe := func() {
+		c.subMu.Lock()
+		delete(c.subs, ch)
+		c.subMu.Unlock()
+	}
+	return ch, unsubscribe
+}
+
+func (c *Cache) publish(e Event) {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	for ch := range c.subs {
+		select {
+		case ch <- e:
+		default:
+			// Slow consumer: drop rather than block the Sync loop.
+		}
+	}
+}
+
+// Invalidate evicts kind's id from the cache, forcing the next
+// cache-aware getter to hit the API.
+func (c *Cache) Invalidate(kind ResourceKind, id string) {
+	store, ok := c.stores[kind]
+	if !ok {
+		return
+	}
+	if obj, ok := store.delete(id); ok {
+		c.publish(Event{Kind: kind, ID: id, Object: obj, Type: EventDeleted})
+	}
+}
+
+// Sync periodically calls Client's List* methods and diffs the results
+// against the cache, publishing Added/Updated/Deleted events, until ctx is
+// canceled. interval is clamped to DefaultCacheTTL if zero or negative.
+func (c *Cache) Sync(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCacheTTL
+	}
+
+	c.syncOnce(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.syncOnce(ctx)
+		}
+	}
+}
+
+func (c *Cache) syncOnce(ctx context.Context) {
+	if gateways, err := c.client.ListGateways(ctx); err == nil {
+		objs := make([]interface{}, len(gateways))
+		for i, g := range gateways {
+			objs[i] = g
+		}
+		c.reconcile(KindGateway, objs)
+	}
+
+	if tgws, err := c.client.ListTransitGateways(ctx); err == nil {
+		objs := make([]interface{}, len(tgws))
+		for i, g := range tgws {
+			objs[i] = g
+		}
+		c.reconcile(KindTransitGateway, objs)
+	}
+
+	if sgws, err := c.client.ListSpokeGateways(ctx); err == nil {
+		objs := make([]interface{}, len(sgws))
+		for i, g := range sgws {
+			objs[i] = g
+		}
+		c.reconcile(KindSpokeGateway, objs)
+	}
+
+	if vgws, err := c.client.ListVPNGateways(ctx); err == nil {
+		objs := make([]interface{}, len(vgws))
+		for i, g := range vgws {
+			objs[i] = g
+		}
+		c.reconcile(KindVPNGateway, objs)
+	}
+
+	if routes, err := c.client.ListRoutes(ctx); err == nil {
+		objs := make([]interface{}, len(routes))
+		for i, r := range routes {
+			objs[i] = r
+		}
+		c.reconcile(KindRoute, objs)
+	}
+
+	if neighbors, err := c.client.ListBGPNeighbors(ctx); err == nil {
+		objs := make([]interface{}, len(neighbors))
+		for i, n := range neighbors {
+			objs[i] = n
+		}
+		c.reconcile(KindBGPNeighbor, objs)
+	}
+}
+
+// reconcile diffs objects (a fresh LIST of kind) against the store,
+// publishing one event per object added, changed, or no longer present.
+func (c *Cache) reconcile(kind ResourceKind, objects []interface{}) {
+	store := c.stores[kind]
+	seen := store.listIDs()
+
+	for _, obj := range objects {
+		id := idOf(obj)
+		if id == "" {
+			continue
+		}
+		_, existed := seen[id]
+		delete(seen, id)
+
+		if store.put(id, obj) {
+			eventType := EventAdded
+			if existed {
+				eventType = EventUpdated
+			}
+			c.publish(Event{Kind: kind, ID: id, Object: obj, Type: eventType})
+		}
+	}
+
+	for id := range seen {
+		if obj, ok := store.delete(id); ok {
+			c.publish(Event{Kind: kind, ID: id, Object: obj, Type: EventDeleted})
+		}
+	}
+}
+
+// GetGatewayCached returns id's gateway from the cache, falling back to
+// Client.GetGateway on a miss or expired entry and re-populating the cache
+// with whatever it finds.
+func (c *Cache) GetGatewayCached(ctx context.Context, id string) (*Gateway, error) {
+	store := c.stores[KindGateway]
+	if obj, ok := store.get(id); ok {
+		gw := obj.(Gateway)
+		return &gw, nil
+	}
+
+	gw, err := c.client.GetGateway(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("aviatrix: cache miss for gateway %s: %w", id, err)
+	}
+	if store.put(id, *gw) {
+		c.publish(Event{Kind: KindGateway, ID: id, Object: *gw, Type: EventAdded})
+	}
+	return gw, nil
+}
+
+// ListSpokeGatewaysCached returns every cached spoke gateway, falling back
+// to Client.ListSpokeGateways when the cache has never been populated.
+func (c *Cache) ListSpokeGatewaysCached(ctx context.Context) ([]SpokeGateway, error) {
+	store := c.stores[KindSpokeGateway]
+	if cached := store.list(); len(cached) > 0 {
+		out := make([]SpokeGateway, len(cached))
+		for i, obj := range cached {
+			out[i] = obj.(SpokeGateway)
+		}
+		return out, nil
+	}
+
+	sgws, err := c.client.ListSpokeGateways(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("aviatrix: cache miss for spoke gateways: %w", err)
+	}
+	objs := make([]interface{}, len(sgws))
+	for i, g := range sgws {
+		objs[i] = g
+	}
+	c.reconcile(KindSpokeGateway, objs)
+	return sgws, nil
+}
+
+// SpokeGatewaysByTransitGW returns every cached spoke gateway attached to
+// transitGW, using the transit_gw secondary index instead of a linear scan.
+func (c *Cache) SpokeGatewaysByTransitGW(transitGW string) []SpokeGateway {
+	objs := c.stores[KindSpokeGateway].byIndex("transit_gw", transitGW)
+	out := make([]SpokeGateway, len(objs))
+	for i, obj := range objs {
+		out[i] = obj.(SpokeGateway)
+	}
+	return out
+}
+
+// GatewaysByRegion returns every cached gateway in region, using the
+// region secondary index instead of a linear scan.
+func (c *Cache) GatewaysByRegion(region string) []Gateway {
+	objs := c.stores[KindGateway].byIndex("region", region)
+	out := make([]Gateway, len(objs))
+	for i, obj := range objs {
+		out[i] = obj.(Gateway)
+	}
+	return out
+}
@@ -7,14 +7,21 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
 // Client represents an Aviatrix API client
 type Client struct {
-	baseURL    string
-	apiKey     string
-	httpClient *http.Client
+	baseURL        string
+	apiKey         string
+	httpClient     *http.Client
+	defaultTimeout time.Duration
+
+	// partition, when set, is sent as X-Router-Partition on every
+	// request this Client makes, scoping it to one tenant.
+	partition string
 }
 
 // Config represents Aviatrix configuration
@@ -22,6 +29,14 @@ type Config struct {
 	BaseURL string `json:"base_url"`
 	APIKey  string `json:"api_key"`
 	Timeout int    `json:"timeout_seconds"`
+
+	// Transport tunes retries, the in-flight limiter and the circuit
+	// breaker NewClient builds into the client. The zero value is usable.
+	Transport TransportConfig `json:"-"`
+
+	// Partition is set on the Client this Config builds; see
+	// Client.partition.
+	Partition string `json:"partition"`
 }
 
 // Gateway represents an Aviatrix gateway
@@ -122,9 +137,16 @@ type APIResponse struct {
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
 	Message string      `json:"message,omitempty"`
+
+	// NextCursor is the opaque pagination cursor a list endpoint returns
+	// when more pages remain; empty once the last page has been read.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
-// NewClient creates a new Aviatrix client
+// NewClient creates a new Aviatrix client. The returned Client has no
+// http.Client-wide Timeout: makeRequest instead derives a per-call deadline
+// from config.Timeout unless the caller's context already carries one, so
+// an individual call's context deadline always takes precedence.
 func NewClient(config Config) (*Client, error) {
 	if config.BaseURL == "" {
 		return nil, fmt.Errorf("base URL is required")
@@ -140,10 +162,12 @@ func NewClient(config Config) (*Client, error) {
 	}
 
 	return &Client{
-		baseURL: config.BaseURL,
-		apiKey:  config.APIKey,
+		baseURL:        config.BaseURL,
+		apiKey:         config.APIKey,
+		defaultTimeout: timeout,
+		partition:      config.Partition,
 		httpClient: &http.Client{
-			Timeout: timeout,
+			Transport: buildTransport(config.Transport, timeout),
 		},
 	}, nil
 }
@@ -153,26 +177,51 @@ func (c *Client) GetStatus(ctx context.Context) (*APIResponse, error) {
 	return c.makeRequest(ctx, "GET", "/status", nil)
 }
 
-// ListGateways returns all gateways
-func (c *Client) ListGateways(ctx context.Context) ([]Gateway, error) {
-	resp, err := c.makeRequest(ctx, "GET", "/gateways", nil)
-	if err != nil {
-		return nil, err
-	}
+// listPaged GETs path, following APIResponse.NextCursor until the API
+// reports no further page, and decodes every item of every page into T.
+// Every List* method below is a one-line call into this, so pagination is
+// handled in one place instead of being reimplemented per resource kind.
+func listPaged[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	var all []T
+	cursor := ""
+
+	for {
+		p := path
+		if cursor != "" {
+			sep := "?"
+			if strings.Contains(path, "?") {
+				sep = "&"
+			}
+			p = path + sep + "cursor=" + url.QueryEscape(cursor)
+		}
+
+		resp, err := c.makeRequest(ctx, "GET", p, nil)
+		if err != nil {
+			return nil, err
+		}
 
-	var gateways []Gateway
-	if data, ok := resp.Data.([]interface{}); ok {
-		for _, item := range data {
-			if gatewayData, err := json.Marshal(item); err == nil {
-				var gateway Gateway
-				if err := json.Unmarshal(gatewayData, &gateway); err == nil {
-					gateways = append(gateways, gateway)
+		if data, ok := resp.Data.([]interface{}); ok {
+			for _, item := range data {
+				if raw, err := json.Marshal(item); err == nil {
+					var v T
+					if err := json.Unmarshal(raw, &v); err == nil {
+						all = append(all, v)
+					}
 				}
 			}
 		}
+
+		if resp.NextCursor == "" {
+			return all, nil
+		}
+		cursor = resp.NextCursor
 	}
+}
 
-	return gateways, nil
+// ListGateways returns all gateways, across as many pages as the API
+// reports via APIResponse.NextCursor.
+func (c *Client) ListGateways(ctx context.Context) ([]Gateway, error) {
+	return listPaged[Gateway](ctx, c, "/gateways")
 }
 
 // GetGateway returns a specific gateway by ID
@@ -194,36 +243,30 @@ func (c *Client) GetGateway(ctx context.Context, id string) (*Gateway, error) {
 	return &gateway, nil
 }
 
-// CreateTransitGateway creates a new transit gateway
-func (c *Client) CreateTransitGateway(ctx context.Context, tgw *TransitGateway) (*APIResponse, error) {
-	data, err := json.Marshal(tgw)
+// CreateGateway creates a new gateway
+func (c *Client) CreateGateway(ctx context.Context, gw *Gateway) (*APIResponse, error) {
+	data, err := json.Marshal(gw)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.makeRequest(ctx, "POST", "/transit-gateways", bytes.NewBuffer(data))
+	return c.makeRequest(ctx, "POST", "/gateways", bytes.NewBuffer(data))
 }
 
-// ListTransitGateways returns all transit gateways
-func (c *Client) ListTransitGateways(ctx context.Context) ([]TransitGateway, error) {
-	resp, err := c.makeRequest(ctx, "GET", "/transit-gateways", nil)
+// CreateTransitGateway creates a new transit gateway
+func (c *Client) CreateTransitGateway(ctx context.Context, tgw *TransitGateway) (*APIResponse, error) {
+	data, err := json.Marshal(tgw)
 	if err != nil {
 		return nil, err
 	}
 
-	var tgws []TransitGateway
-	if data, ok := resp.Data.([]interface{}); ok {
-		for _, item := range data {
-			if tgwData, err := json.Marshal(item); err == nil {
-				var tgw TransitGateway
-				if err := json.Unmarshal(tgwData, &tgw); err == nil {
-					tgws = append(tgws, tgw)
-				}
-			}
-		}
-	}
+	return c.makeRequest(ctx, "POST", "/transit-gateways", bytes.NewBuffer(data))
+}
 
-	return tgws, nil
+// ListTransitGateways returns all transit gateways, across as many pages
+// as the API reports via APIResponse.NextCursor.
+func (c *Client) ListTransitGateways(ctx context.Context) ([]TransitGateway, error) {
+	return listPaged[TransitGateway](ctx, c, "/transit-gateways")
 }
 
 // CreateSpokeGateway creates a new spoke gateway
@@ -236,26 +279,40 @@ func (c *Client) CreateSpokeGateway(ctx context.Context, sgw *SpokeGateway) (*AP
 	return c.makeRequest(ctx, "POST", "/spoke-gateways", bytes.NewBuffer(data))
 }
 
-// ListSpokeGateways returns all spoke gateways
+// ListSpokeGateways returns all spoke gateways, across as many pages as
+// the API reports via APIResponse.NextCursor.
 func (c *Client) ListSpokeGateways(ctx context.Context) ([]SpokeGateway, error) {
-	resp, err := c.makeRequest(ctx, "GET", "/spoke-gateways", nil)
+	return listPaged[SpokeGateway](ctx, c, "/spoke-gateways")
+}
+
+// UpdateTransitGateway updates a transit gateway configuration
+func (c *Client) UpdateTransitGateway(ctx context.Context, tgw *TransitGateway) (*APIResponse, error) {
+	data, err := json.Marshal(tgw)
 	if err != nil {
 		return nil, err
 	}
 
-	var sgws []SpokeGateway
-	if data, ok := resp.Data.([]interface{}); ok {
-		for _, item := range data {
-			if sgwData, err := json.Marshal(item); err == nil {
-				var sgw SpokeGateway
-				if err := json.Unmarshal(sgwData, &sgw); err == nil {
-					sgws = append(sgws, sgw)
-				}
-			}
-		}
+	return c.makeRequest(ctx, "PUT", fmt.Sprintf("/transit-gateways/%s", tgw.ID), bytes.NewBuffer(data))
+}
+
+// DeleteTransitGateway deletes a transit gateway
+func (c *Client) DeleteTransitGateway(ctx context.Context, id string) (*APIResponse, error) {
+	return c.makeRequest(ctx, "DELETE", fmt.Sprintf("/transit-gateways/%s", id), nil)
+}
+
+// UpdateSpokeGateway updates a spoke gateway configuration
+func (c *Client) UpdateSpokeGateway(ctx context.Context, sgw *SpokeGateway) (*APIResponse, error) {
+	data, err := json.Marshal(sgw)
+	if err != nil {
+		return nil, err
 	}
 
-	return sgws, nil
+	return c.makeRequest(ctx, "PUT", fmt.Sprintf("/spoke-gateways/%s", sgw.ID), bytes.NewBuffer(data))
+}
+
+// DeleteSpokeGateway deletes a spoke gateway
+func (c *Client) DeleteSpokeGateway(ctx context.Context, id string) (*APIResponse, error) {
+	return c.makeRequest(ctx, "DELETE", fmt.Sprintf("/spoke-gateways/%s", id), nil)
 }
 
 // CreateVPNGateway creates a new VPN gateway
@@ -268,73 +325,93 @@ func (c *Client) CreateVPNGateway(ctx context.Context, vgw *VPNGateway) (*APIRes
 	return c.makeRequest(ctx, "POST", "/vpn-gateways", bytes.NewBuffer(data))
 }
 
-// ListVPNGateways returns all VPN gateways
+// ListVPNGateways returns all VPN gateways, across as many pages as the
+// API reports via APIResponse.NextCursor.
 func (c *Client) ListVPNGateways(ctx context.Context) ([]VPNGateway, error) {
-	resp, err := c.makeRequest(ctx, "GET", "/vpn-gateways", nil)
+	return listPaged[VPNGateway](ctx, c, "/vpn-gateways")
+}
+
+// UpdateVPNGateway updates a VPN gateway's attachment configuration
+func (c *Client) UpdateVPNGateway(ctx context.Context, vgw *VPNGateway) (*APIResponse, error) {
+	data, err := json.Marshal(vgw)
 	if err != nil {
 		return nil, err
 	}
 
-	var vgws []VPNGateway
-	if data, ok := resp.Data.([]interface{}); ok {
-		for _, item := range data {
-			if vgwData, err := json.Marshal(item); err == nil {
-				var vgw VPNGateway
-				if err := json.Unmarshal(vgwData, &vgw); err == nil {
-					vgws = append(vgws, vgw)
-				}
-			}
-		}
+	return c.makeRequest(ctx, "PUT", fmt.Sprintf("/vpn-gateways/%s", vgw.ID), bytes.NewBuffer(data))
+}
+
+// DeleteVPNGateway deletes a VPN gateway
+func (c *Client) DeleteVPNGateway(ctx context.Context, id string) (*APIResponse, error) {
+	return c.makeRequest(ctx, "DELETE", fmt.Sprintf("/vpn-gateways/%s", id), nil)
+}
+
+// CreateRoute creates a new route
+func (c *Client) CreateRoute(ctx context.Context, route *Route) (*APIResponse, error) {
+	data, err := json.Marshal(route)
+	if err != nil {
+		return nil, err
 	}
 
-	return vgws, nil
+	return c.makeRequest(ctx, "POST", "/routes", bytes.NewBuffer(data))
 }
 
-// ListRoutes returns all routes
+// ListRoutes returns all routes, across as many pages as the API reports
+// via APIResponse.NextCursor.
 func (c *Client) ListRoutes(ctx context.Context) ([]Route, error) {
-	resp, err := c.makeRequest(ctx, "GET", "/routes", nil)
+	return listPaged[Route](ctx, c, "/routes")
+}
+
+// UpdateRoute updates a route
+func (c *Client) UpdateRoute(ctx context.Context, route *Route) (*APIResponse, error) {
+	data, err := json.Marshal(route)
 	if err != nil {
 		return nil, err
 	}
 
-	var routes []Route
-	if data, ok := resp.Data.([]interface{}); ok {
-		for _, item := range data {
-			if routeData, err := json.Marshal(item); err == nil {
-				var route Route
-				if err := json.Unmarshal(routeData, &route); err == nil {
-					routes = append(routes, route)
-				}
-			}
-		}
+	return c.makeRequest(ctx, "PUT", fmt.Sprintf("/routes/%s", route.ID), bytes.NewBuffer(data))
+}
+
+// DeleteRoute deletes a route
+func (c *Client) DeleteRoute(ctx context.Context, id string) (*APIResponse, error) {
+	return c.makeRequest(ctx, "DELETE", fmt.Sprintf("/routes/%s", id), nil)
+}
+
+// CreateBGPNeighbor creates a new BGP peering
+func (c *Client) CreateBGPNeighbor(ctx context.Context, neighbor *BGPNeighbor) (*APIResponse, error) {
+	data, err := json.Marshal(neighbor)
+	if err != nil {
+		return nil, err
 	}
 
-	return routes, nil
+	return c.makeRequest(ctx, "POST", "/bgp/neighbors", bytes.NewBuffer(data))
 }
 
-// ListBGPNeighbors returns all BGP neighbors
+// ListBGPNeighbors returns all BGP neighbors, across as many pages as the
+// API reports via APIResponse.NextCursor.
 func (c *Client) ListBGPNeighbors(ctx context.Context) ([]BGPNeighbor, error) {
-	resp, err := c.makeRequest(ctx, "GET", "/bgp/neighbors", nil)
+	return listPaged[BGPNeighbor](ctx, c, "/bgp/neighbors")
+}
+
+// UpdateBGPNeighbor updates a BGP peering
+func (c *Client) UpdateBGPNeighbor(ctx context.Context, neighbor *BGPNeighbor) (*APIResponse, error) {
+	data, err := json.Marshal(neighbor)
 	if err != nil {
 		return nil, err
 	}
 
-	var neighbors []BGPNeighbor
-	if data, ok := resp.Data.([]interface{}); ok {
-		for _, item := range data {
-			if neighborData, err := json.Marshal(item); err == nil {
-				var neighbor BGPNeighbor
-				if err := json.Unmarshal(neighborData, &neighbor); err == nil {
-					neighbors = append(neighbors, neighbor)
-				}
-			}
-		}
-	}
+	return c.makeRequest(ctx, "PUT", fmt.Sprintf("/bgp/neighbors/%s", neighbor.ID), bytes.NewBuffer(data))
+}
 
-	return neighbors, nil
+// DeleteBGPNeighbor deletes a BGP peering
+func (c *Client) DeleteBGPNeighbor(ctx context.Context, id string) (*APIResponse, error) {
+	return c.makeRequest(ctx, "DELETE", fmt.Sprintf("/bgp/neighbors/%s", id), nil)
 }
 
-// DeployTransitNetwork deploys a complete transit network
+// DeployTransitNetwork deploys a complete transit network in one
+// fire-and-forget call. For a reconciled deployment whose progress can be
+// observed and rolled back on failure, build a NetworkPlan and use
+// Plan/Apply instead.
 func (c *Client) DeployTransitNetwork(ctx context.Context, config map[string]interface{}) (*APIResponse, error) {
 	data, err := json.Marshal(config)
 	if err != nil {
@@ -344,7 +421,9 @@ func (c *Client) DeployTransitNetwork(ctx context.Context, config map[string]int
 	return c.makeRequest(ctx, "POST", "/deploy/transit-network", bytes.NewBuffer(data))
 }
 
-// DeploySpokeNetwork deploys a spoke network
+// DeploySpokeNetwork deploys a spoke network in one fire-and-forget call.
+// For a reconciled deployment whose progress can be observed and rolled
+// back on failure, build a NetworkPlan and use Plan/Apply instead.
 func (c *Client) DeploySpokeNetwork(ctx context.Context, config map[string]interface{}) (*APIResponse, error) {
 	data, err := json.Marshal(config)
 	if err != nil {
@@ -378,10 +457,18 @@ func (c *Client) GetMetrics(ctx context.Context, gatewayID string) (map[string]i
 	return nil, fmt.Errorf("invalid metrics response format")
 }
 
-// makeRequest makes an HTTP request to the Aviatrix API
+// makeRequest makes an HTTP request to the Aviatrix API. If ctx has no
+// deadline of its own, one derived from the client's configured Timeout is
+// applied for this call only; an explicit deadline on ctx always wins.
 func (c *Client) makeRequest(ctx context.Context, method, path string, body io.Reader) (*APIResponse, error) {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline && c.defaultTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.defaultTimeout)
+		defer cancel()
+	}
+
 	url := c.baseURL + path
-	
+
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
@@ -391,6 +478,9 @@ func (c *Client) makeRequest(ctx context.Context, method, path string, body io.R
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	req.Header.Set("User-Agent", "router-sim-aviatrix-client/1.0")
+	if c.partition != "" {
+		req.Header.Set("X-Router-Partition", c.partition)
+	}
 
 	// Make request
 	resp, err := c.httpClient.Do(req)
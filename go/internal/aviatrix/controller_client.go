@@ -0,0 +1,599 @@
+package aviatrix
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// cidExpiredReason is the controller's "reason" string for an expired or
+// invalid session, the one error ControllerClient retries itself (by
+// re-logging in) rather than surfacing to the caller.
+const cidExpiredReason = "CID is invalid or expired"
+
+// controllerEnvelope is every Aviatrix controller REST API response's
+// shape: Return reports success, Reason carries the error when it
+// doesn't, and Results holds the payload, whose concrete type depends on
+// the action that was called.
+type controllerEnvelope struct {
+	Return  bool            `json:"return"`
+	Reason  string          `json:"reason"`
+	Results json.RawMessage `json:"results"`
+}
+
+// ControllerClient is a REST client for the Aviatrix controller itself
+// (github.com/AviatrixSystems/terraform-provider-aviatrix's goaviatrix
+// SDK shape), as distinct from Client, which talks to this repo's own
+// simulated Aviatrix API. It performs the controller's two-step CID
+// login (action=login captures a CID, every later call includes it) and
+// decodes every response's {return, reason, results} envelope.
+type ControllerClient struct {
+	baseURL       string
+	username      string
+	password      string
+	peeringSecret string
+	httpClient    *http.Client
+
+	mu      sync.Mutex
+	cid     string
+	peerSeq uint64
+}
+
+// ControllerClientOption configures a ControllerClient at construction.
+type ControllerClientOption func(*ControllerClient)
+
+// WithInsecureSkipVerify disables TLS certificate verification, an opt-in
+// for controllers running with a self-signed certificate.
+func WithInsecureSkipVerify() ControllerClientOption {
+	return func(c *ControllerClient) {
+		c.httpClient.Transport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+}
+
+// NewControllerClient creates a ControllerClient for the controller at
+// cfg.ControllerIP, authenticating with cfg.Username/cfg.Password on first
+// use. The returned client pools connections via a shared http.Transport
+// rather than dialing fresh per call.
+func NewControllerClient(cfg AviatrixConfig, opts ...ControllerClientOption) (*ControllerClient, error) {
+	if cfg.ControllerIP == "" {
+		return nil, fmt.Errorf("aviatrix: controller IP is required")
+	}
+	if cfg.Username == "" || cfg.Password == "" {
+		return nil, fmt.Errorf("aviatrix: username and password are required")
+	}
+
+	c := &ControllerClient{
+		baseURL:       fmt.Sprintf("https://%s/v1/api", cfg.ControllerIP),
+		username:      cfg.Username,
+		password:      cfg.Password,
+		peeringSecret: cfg.PeeringSecret,
+		httpClient: &http.Client{
+			Timeout:   60 * time.Second,
+			Transport: &http.Transport{MaxIdleConnsPerHost: 10},
+		},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// login performs the controller's action=login call and stores the CID it
+// returns for use by subsequent calls.
+func (c *ControllerClient) login(ctx context.Context) error {
+	form := url.Values{
+		"action":   {"login"},
+		"username": {c.username},
+		"password": {c.password},
+	}
+
+	var body struct {
+		CID string `json:"CID"`
+	}
+	env, err := c.post(ctx, form)
+	if err != nil {
+		return fmt.Errorf("aviatrix: login failed: %w", err)
+	}
+	if err := json.Unmarshal(env.Results, &body); err != nil {
+		return fmt.Errorf("aviatrix: login response decode failed: %w", err)
+	}
+
+	c.mu.Lock()
+	c.cid = body.CID
+	c.mu.Unlock()
+	return nil
+}
+
+// post issues one form-encoded POST and decodes its envelope, without any
+// CID handling or retry; call does that.
+func (c *ControllerClient) post(ctx context.Context, form url.Values) (*controllerEnvelope, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var env controllerEnvelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return nil, fmt.Errorf("aviatrix: malformed controller response: %w", err)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &env, fmt.Errorf("aviatrix: rate limited")
+	}
+	return &env, nil
+}
+
+// call performs action with params, attaching the current CID, retrying
+// once after a fresh login if the controller reports the CID expired, and
+// retrying rate-limit responses with exponential backoff. The decoded
+// Results are returned on success; !Return is surfaced as an error
+// carrying the controller's Reason.
+func (c *ControllerClient) call(ctx context.Context, action string, params url.Values) (json.RawMessage, error) {
+	c.mu.Lock()
+	if c.cid == "" {
+		c.mu.Unlock()
+		if err := c.login(ctx); err != nil {
+			return nil, err
+		}
+	} else {
+		c.mu.Unlock()
+	}
+
+	form := url.Values{}
+	for k, v := range params {
+		form[k] = v
+	}
+	form.Set("action", action)
+
+	reloggedIn := false
+	bo := backoff.NewExponentialBackOff()
+	bo.MaxElapsedTime = 60 * time.Second
+	policy := backoff.WithContext(bo, ctx)
+
+	var results json.RawMessage
+	err := backoff.Retry(func() error {
+		c.mu.Lock()
+		form.Set("CID", c.cid)
+		c.mu.Unlock()
+
+		env, err := c.post(ctx, form)
+		if err != nil {
+			return err // rate-limited or network error: keep retrying
+		}
+
+		if !env.Return {
+			if !reloggedIn && strings.Contains(env.Reason, cidExpiredReason) {
+				reloggedIn = true
+				if err := c.login(ctx); err != nil {
+					return backoff.Permanent(err)
+				}
+				return fmt.Errorf("aviatrix: retrying %s after CID re-login", action)
+			}
+			return backoff.Permanent(fmt.Errorf("aviatrix: %s failed: %s", action, env.Reason))
+		}
+
+		results = env.Results
+		return nil
+	}, policy)
+
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetGateways lists every gateway known to the controller.
+func (c *ControllerClient) GetGateways(ctx context.Context) ([]ControllerGateway, error) {
+	results, err := c.call(ctx, "list_vpcs_summary", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	var gateways []ControllerGateway
+	if err := json.Unmarshal(results, &gateways); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode gateways: %w", err)
+	}
+	return gateways, nil
+}
+
+// GetTransitGateways lists every transit gateway.
+func (c *ControllerClient) GetTransitGateways(ctx context.Context) ([]ControllerTransitGateway, error) {
+	results, err := c.call(ctx, "list_transit_gateways", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	var gateways []ControllerTransitGateway
+	if err := json.Unmarshal(results, &gateways); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode transit gateways: %w", err)
+	}
+	return gateways, nil
+}
+
+// GetSpokeGateways lists every spoke gateway.
+func (c *ControllerClient) GetSpokeGateways(ctx context.Context) ([]ControllerSpokeGateway, error) {
+	results, err := c.call(ctx, "list_spoke_gateways", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	var gateways []ControllerSpokeGateway
+	if err := json.Unmarshal(results, &gateways); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode spoke gateways: %w", err)
+	}
+	return gateways, nil
+}
+
+// GetTransitGatewayPeering lists every transit gateway peering.
+func (c *ControllerClient) GetTransitGatewayPeering(ctx context.Context) ([]TransitGatewayPeering, error) {
+	results, err := c.call(ctx, "list_transit_peerings", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	var peerings []TransitGatewayPeering
+	if err := json.Unmarshal(results, &peerings); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode transit gateway peerings: %w", err)
+	}
+	return peerings, nil
+}
+
+// GetSecurityDomains lists every security domain.
+func (c *ControllerClient) GetSecurityDomains(ctx context.Context) ([]SecurityDomain, error) {
+	results, err := c.call(ctx, "list_security_domains", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	var domains []SecurityDomain
+	if err := json.Unmarshal(results, &domains); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode security domains: %w", err)
+	}
+	return domains, nil
+}
+
+// GetFirewallPolicies lists every firewall policy.
+func (c *ControllerClient) GetFirewallPolicies(ctx context.Context) ([]FirewallPolicy, error) {
+	results, err := c.call(ctx, "list_firewall_policies", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	var policies []FirewallPolicy
+	if err := json.Unmarshal(results, &policies); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode firewall policies: %w", err)
+	}
+	return policies, nil
+}
+
+// GetMetrics returns gatewayName's metrics over timeRange.
+func (c *ControllerClient) GetMetrics(ctx context.Context, gatewayName, timeRange string) (*Metrics, error) {
+	results, err := c.call(ctx, "get_gateway_metrics", url.Values{
+		"gateway_name": {gatewayName},
+		"time_range":   {timeRange},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var metrics Metrics
+	if err := json.Unmarshal(results, &metrics); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode metrics: %w", err)
+	}
+	return &metrics, nil
+}
+
+// CreateTransitGateway creates a transit gateway from req.
+func (c *ControllerClient) CreateTransitGateway(ctx context.Context, req *CreateTransitGatewayRequest) (*ControllerTransitGateway, error) {
+	results, err := c.call(ctx, "create_transit_gw", requestToForm(req))
+	if err != nil {
+		return nil, err
+	}
+	var gateway ControllerTransitGateway
+	if err := json.Unmarshal(results, &gateway); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode created transit gateway: %w", err)
+	}
+	return &gateway, nil
+}
+
+// UpdateTransitGateway updates gatewayName's configuration from req.
+func (c *ControllerClient) UpdateTransitGateway(ctx context.Context, gatewayName string, req *CreateTransitGatewayRequest) (*ControllerTransitGateway, error) {
+	form := requestToForm(req)
+	form.Set("gw_name", gatewayName)
+
+	results, err := c.call(ctx, "update_transit_gw", form)
+	if err != nil {
+		return nil, err
+	}
+	var gateway ControllerTransitGateway
+	if err := json.Unmarshal(results, &gateway); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode updated transit gateway: %w", err)
+	}
+	return &gateway, nil
+}
+
+// DeleteGateway deletes gatewayName, transit or spoke.
+func (c *ControllerClient) DeleteGateway(ctx context.Context, gatewayName string) error {
+	_, err := c.call(ctx, "delete_gw", url.Values{"gateway_name": {gatewayName}})
+	return err
+}
+
+// CreateSpokeGateway creates a spoke gateway from req.
+func (c *ControllerClient) CreateSpokeGateway(ctx context.Context, req *CreateTransitGatewayRequest) (*ControllerSpokeGateway, error) {
+	results, err := c.call(ctx, "create_spoke_gw", requestToForm(req))
+	if err != nil {
+		return nil, err
+	}
+	var gateway ControllerSpokeGateway
+	if err := json.Unmarshal(results, &gateway); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode created spoke gateway: %w", err)
+	}
+	return &gateway, nil
+}
+
+// AttachSpokeToTransit attaches spokeGateway to transitGateway.
+func (c *ControllerClient) AttachSpokeToTransit(ctx context.Context, spokeGateway, transitGateway string) error {
+	_, err := c.call(ctx, "spoke_join_transit", url.Values{
+		"spoke_gw":   {spokeGateway},
+		"transit_gw": {transitGateway},
+	})
+	return err
+}
+
+// CreateTransitGatewayPeering peers sourceGateway with destinationGateway.
+func (c *ControllerClient) CreateTransitGatewayPeering(ctx context.Context, sourceGateway, destinationGateway string) (*TransitGatewayPeering, error) {
+	results, err := c.call(ctx, "create_transit_gateway_peering", url.Values{
+		"transit_gateway_name1": {sourceGateway},
+		"transit_gateway_name2": {destinationGateway},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var peering TransitGatewayPeering
+	if err := json.Unmarshal(results, &peering); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode transit gateway peering: %w", err)
+	}
+	return &peering, nil
+}
+
+// findTransitGateway returns the transit gateway named name, fetching the
+// current list first since ControllerClient does not cache gateways.
+func (c *ControllerClient) findTransitGateway(ctx context.Context, name string) (*ControllerTransitGateway, error) {
+	gateways, err := c.GetTransitGateways(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("aviatrix: list transit gateways: %w", err)
+	}
+	for i := range gateways {
+		if gateways[i].GatewayName == name {
+			return &gateways[i], nil
+		}
+	}
+	return nil, fmt.Errorf("aviatrix: transit gateway %q not found", name)
+}
+
+// GeneratePeeringToken issues a signed PeeringToken for localGateway, the
+// handshake a remote controller's EstablishPeering call consumes to peer
+// with it cross-controller, the same out-of-band exchange Consul cluster
+// peering uses.
+func (c *ControllerClient) GeneratePeeringToken(ctx context.Context, localGateway, remoteName string) (string, error) {
+	if c.peeringSecret == "" {
+		return "", fmt.Errorf("aviatrix: peering secret is not configured")
+	}
+
+	gw, err := c.findTransitGateway(ctx, localGateway)
+	if err != nil {
+		return "", err
+	}
+
+	psk, err := newPeeringPSK()
+	if err != nil {
+		return "", err
+	}
+
+	pt := PeeringToken{
+		Version:       1,
+		Endpoint:      c.baseURL,
+		LocalASN:      gw.LocalASNumber,
+		LocalPublicIP: gw.PublicIP,
+		PSK:           psk,
+		Sequence:      atomic.AddUint64(&c.peerSeq, 1),
+	}
+	return signPeeringToken(pt, c.peeringSecret)
+}
+
+// EstablishPeering verifies token and negotiates a transit gateway
+// peering from localGateway to the controller and ASN/IP the token names,
+// honoring localGateway's EnableEncryptPeering,
+// EnablePeeringOverPrivateNetwork, and LocalASNumber the same way
+// CreateTransitGatewayPeering does for a same-controller peering.
+func (c *ControllerClient) EstablishPeering(ctx context.Context, token, localGateway string) (*TransitGatewayPeering, error) {
+	if c.peeringSecret == "" {
+		return nil, fmt.Errorf("aviatrix: peering secret is not configured")
+	}
+
+	pt, err := verifyPeeringToken(token, c.peeringSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	gw, err := c.findTransitGateway(ctx, localGateway)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := c.call(ctx, "establish_multi_cluster_peering", url.Values{
+		"local_gw_name":                {localGateway},
+		"remote_endpoint":              {pt.Endpoint},
+		"remote_as_number":             {pt.LocalASN},
+		"remote_public_ip":             {pt.LocalPublicIP},
+		"psk":                          {pt.PSK},
+		"sequence":                     {strconv.FormatUint(pt.Sequence, 10)},
+		"local_as_number":              {gw.LocalASNumber},
+		"enable_encrypt_peering":       {strconv.FormatBool(gw.EnableEncryptPeering)},
+		"peering_over_private_network": {strconv.FormatBool(gw.EnablePeeringOverPrivateNetwork)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var peering TransitGatewayPeering
+	if err := json.Unmarshal(results, &peering); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode established peering: %w", err)
+	}
+	return &peering, nil
+}
+
+// TeardownPeering removes peeringName on this controller. The other side
+// of a cross-controller peering must be torn down separately, since once
+// the token handshake completes there is no direct channel back to it.
+func (c *ControllerClient) TeardownPeering(ctx context.Context, peeringName string) error {
+	_, err := c.call(ctx, "delete_transit_gateway_peering", url.Values{"peering_name": {peeringName}})
+	return err
+}
+
+// ListLearnedCIDRs lists gatewayName's learned CIDRs, optionally filtered
+// to stateFilter (pass "" for every state).
+func (c *ControllerClient) ListLearnedCIDRs(ctx context.Context, gatewayName string, stateFilter CIDRState) ([]LearnedCIDR, error) {
+	form := url.Values{"gw_name": {gatewayName}}
+	if stateFilter != "" {
+		form.Set("state", string(stateFilter))
+	}
+	results, err := c.call(ctx, "list_learned_cidrs", form)
+	if err != nil {
+		return nil, err
+	}
+	var cidrs []LearnedCIDR
+	if err := json.Unmarshal(results, &cidrs); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode learned CIDRs: %w", err)
+	}
+	return cidrs, nil
+}
+
+// ApproveLearnedCIDR approves cidr on gatewayName, recording actor and
+// reason in the controller's decision.
+func (c *ControllerClient) ApproveLearnedCIDR(ctx context.Context, gatewayName, cidr, actor, reason string) (*LearnedCIDR, error) {
+	results, err := c.call(ctx, "approve_learned_cidr", url.Values{
+		"gw_name": {gatewayName},
+		"cidr":    {cidr},
+		"actor":   {actor},
+		"reason":  {reason},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var decided LearnedCIDR
+	if err := json.Unmarshal(results, &decided); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode approved learned CIDR: %w", err)
+	}
+	return &decided, nil
+}
+
+// RejectLearnedCIDR rejects cidr on gatewayName, recording actor and
+// reason in the controller's decision.
+func (c *ControllerClient) RejectLearnedCIDR(ctx context.Context, gatewayName, cidr, actor, reason string) (*LearnedCIDR, error) {
+	results, err := c.call(ctx, "reject_learned_cidr", url.Values{
+		"gw_name": {gatewayName},
+		"cidr":    {cidr},
+		"actor":   {actor},
+		"reason":  {reason},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var decided LearnedCIDR
+	if err := json.Unmarshal(results, &decided); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode rejected learned CIDR: %w", err)
+	}
+	return &decided, nil
+}
+
+// BulkDecide applies state to every CIDR in cidrs on gatewayName in one
+// controller call, recording actor and reason on each.
+func (c *ControllerClient) BulkDecide(ctx context.Context, gatewayName string, cidrs []string, state CIDRState, actor, reason string) ([]LearnedCIDR, error) {
+	results, err := c.call(ctx, "bulk_decide_learned_cidrs", url.Values{
+		"gw_name": {gatewayName},
+		"cidrs":   {strings.Join(cidrs, ",")},
+		"state":   {string(state)},
+		"actor":   {actor},
+		"reason":  {reason},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var decided []LearnedCIDR
+	if err := json.Unmarshal(results, &decided); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode bulk-decided learned CIDRs: %w", err)
+	}
+	return decided, nil
+}
+
+// CreateFirewallPolicy creates policy.
+func (c *ControllerClient) CreateFirewallPolicy(ctx context.Context, policy *FirewallPolicy) (*FirewallPolicy, error) {
+	results, err := c.call(ctx, "add_firewall_policy", url.Values{
+		"policy_name": {policy.PolicyName},
+		"source":      {policy.Source},
+		"destination": {policy.Destination},
+		"protocol":    {policy.Protocol},
+		"port":        {policy.Port},
+		"action":      {policy.Action},
+		"log_enabled": {strconv.FormatBool(policy.LogEnabled)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var created FirewallPolicy
+	if err := json.Unmarshal(results, &created); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode created firewall policy: %w", err)
+	}
+	return &created, nil
+}
+
+// CreateSecurityDomain creates domain.
+func (c *ControllerClient) CreateSecurityDomain(ctx context.Context, domain *SecurityDomain) (*SecurityDomain, error) {
+	results, err := c.call(ctx, "create_security_domain", url.Values{
+		"domain_name": {domain.DomainName},
+		"description": {domain.Description},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var created SecurityDomain
+	if err := json.Unmarshal(results, &created); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode created security domain: %w", err)
+	}
+	return &created, nil
+}
+
+// requestToForm flattens a CreateTransitGatewayRequest into the
+// form-encoded params the controller API expects, keyed by each field's
+// json tag the way the controller itself names them.
+func requestToForm(req *CreateTransitGatewayRequest) url.Values {
+	raw, _ := json.Marshal(req)
+	var fields map[string]interface{}
+	_ = json.Unmarshal(raw, &fields)
+
+	form := url.Values{}
+	for k, v := range fields {
+		switch val := v.(type) {
+		case string:
+			if val != "" {
+				form.Set(k, val)
+			}
+		case bool:
+			form.Set(k, strconv.FormatBool(val))
+		case float64:
+			form.Set(k, strconv.FormatFloat(val, 'f', -1, 64))
+		}
+	}
+	return form
+}
+
+var _ AviatrixService = (*ControllerClient)(nil)
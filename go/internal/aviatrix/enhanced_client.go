@@ -3,11 +3,19 @@ package aviatrix
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"sync"
 	"time"
 
+	"go.uber.org/zap"
+
 	"router-sim/internal/config"
 )
 
@@ -16,13 +24,81 @@ type EnhancedClient struct {
 	config     *config.AviatrixConfig
 	httpClient *http.Client
 	baseURL    string
-	apiKey     string
-	username   string
-	password   string
+
+	// authenticator attaches credentials to every request doOnce sends,
+	// and is given one chance to refresh them if a request comes back
+	// 401. Defaults to a username/password login flow against
+	// /auth/login; set via NewEnhancedClientWithAuthenticator for a
+	// deployment that can't use static API keys.
+	authenticator Authenticator
+
+	// deadline bounds every call this client makes, independent of
+	// httpClient.Timeout, set through SetDeadline.
+	deadline *deadlineTimer
+
+	// logger receives one structured log line per request (method, path,
+	// status, latency, request ID, retry count). Defaults to a no-op
+	// logger; set via WithLogger.
+	logger *zap.Logger
+
+	// requestIDGenerator mints the X-Request-ID sent with every request,
+	// so client-side log lines can be correlated with server-side ones.
+	// Defaults to a random hex string; set via WithRequestIDGenerator to
+	// plug in a caller's own correlation ID scheme (e.g. one threaded
+	// down from an inbound gRPC request).
+	requestIDGenerator func() string
+
+	// respCache and cacheTTL back GetNetworkTopology/GetStats's
+	// conditional-GET support; nil respCache (the default) means every
+	// call issues a plain unconditional GET through Do, exactly as
+	// before WithResponseCache existed.
+	respCache ResponseCache
+	cacheTTL  time.Duration
+	inflight  *inflightGroup
+}
+
+// EnhancedClientOption configures an EnhancedClient at construction, the
+// same functional-options shape ControllerClientOption uses.
+type EnhancedClientOption func(*EnhancedClient)
+
+// WithLogger sets the structured logger EnhancedClient uses to emit one
+// log line per request.
+func WithLogger(logger *zap.Logger) EnhancedClientOption {
+	return func(c *EnhancedClient) { c.logger = logger }
+}
+
+// WithRequestIDGenerator overrides how EnhancedClient mints the
+// X-Request-ID sent with every request.
+func WithRequestIDGenerator(generator func() string) EnhancedClientOption {
+	return func(c *EnhancedClient) { c.requestIDGenerator = generator }
+}
+
+// WithResponseCache enables conditional-GET caching for
+// GetNetworkTopology and GetStats: a cached entry younger than ttl is
+// returned without a network call at all; an older one is revalidated
+// with If-None-Match/If-Modified-Since, and a 304 response refreshes the
+// entry's age without re-downloading the body. Pass NewMemoryResponseCache
+// for an in-process cache or NewBoltResponseCache for one that survives a
+// restart.
+func WithResponseCache(cache ResponseCache, ttl time.Duration) EnhancedClientOption {
+	return func(c *EnhancedClient) {
+		c.respCache = cache
+		c.cacheTTL = ttl
+	}
+}
+
+// defaultRequestID returns a random 16-character hex string, used unless
+// WithRequestIDGenerator overrides it.
+func defaultRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
 }
 
 // Aviatrix gateway types
-type Gateway struct {
+type EnhancedGateway struct {
 	ID           string            `json:"id"`
 	Name         string            `json:"name"`
 	Type         string            `json:"type"` // transit, spoke, vpn, etc.
@@ -41,29 +117,29 @@ type Gateway struct {
 	Properties   map[string]interface{} `json:"properties"`
 }
 
-type TransitGateway struct {
-	Gateway
+type EnhancedTransitGateway struct {
+	EnhancedGateway
 	EnableActiveMesh bool     `json:"enable_active_mesh"`
 	EnableSegmentation bool   `json:"enable_segmentation"`
 	ConnectedTransitGWs []string `json:"connected_transit_gws"`
 	ConnectedSpokeGWs  []string  `json:"connected_spoke_gws"`
 	BGPEnabled        bool      `json:"bgp_enabled"`
 	BGPASN            int       `json:"bgp_asn"`
-	BGPNeighbors      []BGPNeighbor `json:"bgp_neighbors"`
+	BGPNeighbors      []EnhancedBGPNeighbor `json:"bgp_neighbors"`
 }
 
-type SpokeGateway struct {
-	Gateway
-	TransitGateway string   `json:"transit_gateway"`
+type EnhancedSpokeGateway struct {
+	EnhancedGateway
+	TransitGatewayName string   `json:"transit_gateway"`
 	BGPEnabled     bool     `json:"bgp_enabled"`
 	BGPASN         int      `json:"bgp_asn"`
-	BGPNeighbors   []BGPNeighbor `json:"bgp_neighbors"`
+	BGPNeighbors   []EnhancedBGPNeighbor `json:"bgp_neighbors"`
 	LearnedCIDRs   []string `json:"learned_cidrs"`
 	AdvertisedCIDRs []string `json:"advertised_cidrs"`
 }
 
-type VPNGateway struct {
-	Gateway
+type EnhancedVPNGateway struct {
+	EnhancedGateway
 	VPNType        string   `json:"vpn_type"` // user, geo
 	VPNProtocol    string   `json:"vpn_protocol"` // openvpn, ipsec
 	VPNPort        int      `json:"vpn_port"`
@@ -73,7 +149,7 @@ type VPNGateway struct {
 	EnableDualStack bool    `json:"enable_dual_stack"`
 }
 
-type BGPNeighbor struct {
+type EnhancedBGPNeighbor struct {
 	ID          string `json:"id"`
 	IP          string `json:"ip"`
 	ASN         int    `json:"asn"`
@@ -101,7 +177,7 @@ type TransitNetwork struct {
 	Cloud       string `json:"cloud"`
 	Region      string `json:"region"`
 	CIDR        string `json:"cidr"`
-	Gateway     string `json:"gateway"`
+	EnhancedGateway     string `json:"gateway"`
 	Status      string `json:"status"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
@@ -113,7 +189,7 @@ type SpokeNetwork struct {
 	Cloud       string `json:"cloud"`
 	Region      string `json:"region"`
 	CIDR        string `json:"cidr"`
-	Gateway     string `json:"gateway"`
+	EnhancedGateway     string `json:"gateway"`
 	TransitGW   string `json:"transit_gw"`
 	Status      string `json:"status"`
 	CreatedAt   time.Time `json:"created_at"`
@@ -133,7 +209,7 @@ type Connection struct {
 }
 
 // Routing and policies
-type Route struct {
+type EnhancedRoute struct {
 	ID          string `json:"id"`
 	Destination string `json:"destination"`
 	NextHop     string `json:"next_hop"`
@@ -250,40 +326,57 @@ type Position struct {
 
 // API Response structures
 type GatewayListResponse struct {
-	Gateways []Gateway `json:"gateways"`
+	Gateways []EnhancedGateway `json:"gateways"`
 	Total    int       `json:"total"`
 	Page     int       `json:"page"`
 	PageSize int       `json:"page_size"`
 }
 
 type TransitGatewayListResponse struct {
-	TransitGateways []TransitGateway `json:"transit_gateways"`
+	TransitGateways []EnhancedTransitGateway `json:"transit_gateways"`
 	Total           int              `json:"total"`
 	Page            int              `json:"page"`
 	PageSize        int              `json:"page_size"`
 }
 
 type SpokeGatewayListResponse struct {
-	SpokeGateways []SpokeGateway `json:"spoke_gateways"`
+	SpokeGateways []EnhancedSpokeGateway `json:"spoke_gateways"`
 	Total         int            `json:"total"`
 	Page          int            `json:"page"`
 	PageSize      int            `json:"page_size"`
 }
 
 type VPNGatewayListResponse struct {
-	VPNGateways []VPNGateway `json:"vpn_gateways"`
+	VPNGateways []EnhancedVPNGateway `json:"vpn_gateways"`
 	Total       int          `json:"total"`
 	Page        int          `json:"page"`
 	PageSize    int          `json:"page_size"`
 }
 
 type RouteListResponse struct {
-	Routes []Route `json:"routes"`
+	Routes []EnhancedRoute `json:"routes"`
 	Total  int     `json:"total"`
 	Page   int     `json:"page"`
 	PageSize int   `json:"page_size"`
 }
 
+type VPNUserListResponse struct {
+	VPNUsers []VPNUser `json:"vpn_users"`
+	Total    int       `json:"total"`
+	Page     int       `json:"page"`
+	PageSize int       `json:"page_size"`
+}
+
+// VPNUserConfig is a VPN user's ready-to-use client configuration: a
+// base64-encoded zip (mirroring 1and1's VPN API) containing the .ovpn
+// profile, CA cert, client cert/key, and TLS-auth key for openvpn, or a
+// single wg-quick conf for wireguard.
+type VPNUserConfig struct {
+	UserID string `json:"user_id"`
+	Format string `json:"format"`
+	Bundle string `json:"bundle"` // base64
+}
+
 // Statistics
 type AviatrixStats struct {
 	TotalGateways       int `json:"total_gateways"`
@@ -302,275 +395,116 @@ type AviatrixStats struct {
 	ActiveVPNUsers      int `json:"active_vpn_users"`
 }
 
-// NewEnhancedClient creates a new enhanced Aviatrix client
-func NewEnhancedClient(config *config.AviatrixConfig) *EnhancedClient {
-	return &EnhancedClient{
-		config: config,
-		httpClient: &http.Client{
-			Timeout: config.Timeout,
-		},
-		baseURL:  config.BaseURL,
-		apiKey:   config.APIKey,
-		username: config.Username,
-		password: config.Password,
+// NewEnhancedClient creates a new enhanced Aviatrix client, authenticating
+// with the username/password login flow cached as an API key.
+func NewEnhancedClient(config *config.AviatrixConfig, opts ...EnhancedClientOption) *EnhancedClient {
+	httpClient := &http.Client{
+		Timeout: config.Timeout,
 	}
+	return NewEnhancedClientWithAuthenticator(config, httpClient,
+		newLoginAuthenticator(httpClient, config.BaseURL, config.Username, config.Password, config.APIKey), opts...)
 }
 
-// Authentication
-func (c *EnhancedClient) Authenticate(ctx context.Context) error {
-	authData := map[string]string{
-		"username": c.username,
-		"password": c.password,
-	}
-
-	jsonData, err := json.Marshal(authData)
-	if err != nil {
-		return fmt.Errorf("failed to marshal auth data: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/auth/login", 
-		bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create auth request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to authenticate: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("authentication failed with status: %d", resp.StatusCode)
-	}
-
-	// Extract API key from response
-	var authResponse struct {
-		APIKey string `json:"api_key"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&authResponse); err != nil {
-		return fmt.Errorf("failed to decode auth response: %w", err)
-	}
+// NewEnhancedClientWithAuthenticator creates an enhanced Aviatrix client
+// that authenticates through authenticator instead of the default
+// username/password login flow, for a deployment using a static token,
+// HMAC-signed requests, mTLS, or an OIDC token source.
+func NewEnhancedClientWithAuthenticator(config *config.AviatrixConfig, httpClient *http.Client, authenticator Authenticator, opts ...EnhancedClientOption) *EnhancedClient {
+	c := &EnhancedClient{
+		config:             config,
+		httpClient:         httpClient,
+		baseURL:            config.BaseURL,
+		authenticator:      authenticator,
+		deadline:           newDeadlineTimer(),
+		logger:             zap.NewNop(),
+		requestIDGenerator: defaultRequestID,
+		inflight:           newInflightGroup(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
 
-	c.apiKey = authResponse.APIKey
-	return nil
+// SetDeadline bounds every future call this client makes to t, the way
+// net.Conn.SetDeadline bounds socket I/O, independent of httpClient's own
+// Timeout. Calling it again before t elapses resets the bound instead of
+// stacking, and any call already in flight observes the change. A zero
+// t clears the deadline.
+func (c *EnhancedClient) SetDeadline(t time.Time) {
+	c.deadline.setDeadline(t)
 }
 
-// Gateway management
-func (c *EnhancedClient) ListGateways(ctx context.Context, filters map[string]string) ([]Gateway, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/gateways", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+// Authenticate refreshes the client's credentials through its
+// Authenticator. It is kept as a public method for callers that
+// previously called it directly after constructing a client; DoWithHeaders
+// now calls the same underlying Authenticator.Refresh itself after a 401.
+func (c *EnhancedClient) Authenticate(ctx context.Context) error {
+	return c.authenticator.Refresh(ctx)
+}
 
-	q := req.URL.Query()
+// EnhancedGateway management
+func (c *EnhancedClient) ListGateways(ctx context.Context, filters map[string]string) ([]EnhancedGateway, error) {
+	query := url.Values{}
 	for key, value := range filters {
-		q.Add(key, value)
-	}
-	req.URL.RawQuery = q.Encode()
-
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list gateways: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to list gateways with status: %d", resp.StatusCode)
+		query.Set(key, value)
 	}
 
 	var response GatewayListResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.Do(ctx, http.MethodGet, "/gateways", query, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to list gateways: %w", err)
 	}
-
 	return response.Gateways, nil
 }
 
-func (c *EnhancedClient) GetGateway(ctx context.Context, id string) (*Gateway, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/gateways/"+id, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+func (c *EnhancedClient) GetGateway(ctx context.Context, id string) (*EnhancedGateway, error) {
+	var gateway EnhancedGateway
+	if err := c.Do(ctx, http.MethodGet, "/gateways/"+id, nil, nil, &gateway); err != nil {
 		return nil, fmt.Errorf("failed to get gateway: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get gateway with status: %d", resp.StatusCode)
-	}
-
-	var gateway Gateway
-	if err := json.NewDecoder(resp.Body).Decode(&gateway); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
 	return &gateway, nil
 }
 
-func (c *EnhancedClient) CreateTransitGateway(ctx context.Context, gw *TransitGateway) (*TransitGateway, error) {
-	jsonData, err := json.Marshal(gw)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal gateway: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/transit-gateways", 
-		bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+func (c *EnhancedClient) CreateTransitGateway(ctx context.Context, gw *EnhancedTransitGateway) (*EnhancedTransitGateway, error) {
+	var created EnhancedTransitGateway
+	if err := c.Do(ctx, http.MethodPost, "/transit-gateways", nil, gw, &created, http.StatusCreated); err != nil {
 		return nil, fmt.Errorf("failed to create transit gateway: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("failed to create transit gateway with status: %d", resp.StatusCode)
-	}
-
-	var createdGW TransitGateway
-	if err := json.NewDecoder(resp.Body).Decode(&createdGW); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	return &createdGW, nil
+	return &created, nil
 }
 
-func (c *EnhancedClient) CreateSpokeGateway(ctx context.Context, gw *SpokeGateway) (*SpokeGateway, error) {
-	jsonData, err := json.Marshal(gw)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal gateway: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/spoke-gateways", 
-		bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+func (c *EnhancedClient) CreateSpokeGateway(ctx context.Context, gw *EnhancedSpokeGateway) (*EnhancedSpokeGateway, error) {
+	var created EnhancedSpokeGateway
+	if err := c.Do(ctx, http.MethodPost, "/spoke-gateways", nil, gw, &created, http.StatusCreated); err != nil {
 		return nil, fmt.Errorf("failed to create spoke gateway: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("failed to create spoke gateway with status: %d", resp.StatusCode)
-	}
-
-	var createdGW SpokeGateway
-	if err := json.NewDecoder(resp.Body).Decode(&createdGW); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	return &createdGW, nil
+	return &created, nil
 }
 
-func (c *EnhancedClient) CreateVPNGateway(ctx context.Context, gw *VPNGateway) (*VPNGateway, error) {
-	jsonData, err := json.Marshal(gw)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal gateway: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/vpn-gateways", 
-		bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+func (c *EnhancedClient) CreateVPNGateway(ctx context.Context, gw *EnhancedVPNGateway) (*EnhancedVPNGateway, error) {
+	var created EnhancedVPNGateway
+	if err := c.Do(ctx, http.MethodPost, "/vpn-gateways", nil, gw, &created, http.StatusCreated); err != nil {
 		return nil, fmt.Errorf("failed to create VPN gateway: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("failed to create VPN gateway with status: %d", resp.StatusCode)
-	}
-
-	var createdGW VPNGateway
-	if err := json.NewDecoder(resp.Body).Decode(&createdGW); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	return &createdGW, nil
+	return &created, nil
 }
 
-func (c *EnhancedClient) UpdateGateway(ctx context.Context, id string, gw *Gateway) (*Gateway, error) {
-	jsonData, err := json.Marshal(gw)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal gateway: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "PUT", c.baseURL+"/gateways/"+id, 
-		bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+func (c *EnhancedClient) UpdateGateway(ctx context.Context, id string, gw *EnhancedGateway) (*EnhancedGateway, error) {
+	var updated EnhancedGateway
+	if err := c.Do(ctx, http.MethodPut, "/gateways/"+id, nil, gw, &updated); err != nil {
 		return nil, fmt.Errorf("failed to update gateway: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to update gateway with status: %d", resp.StatusCode)
-	}
-
-	var updatedGW Gateway
-	if err := json.NewDecoder(resp.Body).Decode(&updatedGW); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	return &updatedGW, nil
+	return &updated, nil
 }
 
 func (c *EnhancedClient) DeleteGateway(ctx context.Context, id string) error {
-	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+"/gateways/"+id, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+	if err := c.Do(ctx, http.MethodDelete, "/gateways/"+id, nil, nil, nil, http.StatusNoContent); err != nil {
 		return fmt.Errorf("failed to delete gateway: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("failed to delete gateway with status: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
-// Gateway actions
+// EnhancedGateway actions
 func (c *EnhancedClient) StartGateway(ctx context.Context, id string) error {
 	return c.gatewayAction(ctx, id, "start")
 }
@@ -584,315 +518,558 @@ func (c *EnhancedClient) RestartGateway(ctx context.Context, id string) error {
 }
 
 func (c *EnhancedClient) ResizeGateway(ctx context.Context, id string, newSize string) error {
-	actionData := map[string]string{
-		"instance_size": newSize,
+	action := map[string]string{"instance_size": newSize}
+	if err := c.Do(ctx, http.MethodPost, "/gateways/"+id+"/resize", nil, action, nil); err != nil {
+		return fmt.Errorf("failed to resize gateway: %w", err)
 	}
+	return nil
+}
 
-	jsonData, err := json.Marshal(actionData)
-	if err != nil {
-		return fmt.Errorf("failed to marshal action data: %w", err)
+func (c *EnhancedClient) gatewayAction(ctx context.Context, id, action string) error {
+	if err := c.Do(ctx, http.MethodPost, "/gateways/"+id+"/"+action, nil, nil, nil); err != nil {
+		return fmt.Errorf("failed to %s gateway: %w", action, err)
 	}
+	return nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/gateways/"+id+"/resize", 
-		bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+// Connection management
+func (c *EnhancedClient) CreateConnection(ctx context.Context, conn *Connection) (*Connection, error) {
+	var created Connection
+	if err := c.Do(ctx, http.MethodPost, "/connections", nil, conn, &created, http.StatusCreated); err != nil {
+		return nil, fmt.Errorf("failed to create connection: %w", err)
 	}
+	return &created, nil
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+func (c *EnhancedClient) DeleteConnection(ctx context.Context, id string) error {
+	if err := c.Do(ctx, http.MethodDelete, "/connections/"+id, nil, nil, nil, http.StatusNoContent); err != nil {
+		return fmt.Errorf("failed to delete connection: %w", err)
+	}
+	return nil
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to resize gateway: %w", err)
+// Routing management
+func (c *EnhancedClient) ListRoutes(ctx context.Context, gatewayID string) ([]EnhancedRoute, error) {
+	query := url.Values{}
+	if gatewayID != "" {
+		query.Set("gateway_id", gatewayID)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to resize gateway with status: %d", resp.StatusCode)
+	var response RouteListResponse
+	if err := c.Do(ctx, http.MethodGet, "/routes", query, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to list routes: %w", err)
 	}
+	return response.Routes, nil
+}
 
-	return nil
+// ListBGPNeighbors returns gatewayID's BGP neighbors. Neither ListGateways
+// nor GetGateway populate EnhancedTransitGateway/EnhancedSpokeGateway's BGPNeighbors field
+// (both decode into the base EnhancedGateway type), so this is the only way to read
+// neighbor-level state — IP, ASN, routes received/advertised — through
+// EnhancedClient.
+func (c *EnhancedClient) ListBGPNeighbors(ctx context.Context, gatewayID string) ([]EnhancedBGPNeighbor, error) {
+	var neighbors []EnhancedBGPNeighbor
+	if err := c.Do(ctx, http.MethodGet, "/gateways/"+gatewayID+"/bgp-neighbors", nil, nil, &neighbors); err != nil {
+		return nil, fmt.Errorf("failed to list BGP neighbors: %w", err)
+	}
+	return neighbors, nil
 }
 
-func (c *EnhancedClient) gatewayAction(ctx context.Context, id, action string) error {
-	req, err := http.NewRequestWithContext(ctx, "POST", 
-		c.baseURL+"/gateways/"+id+"/"+action, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+func (c *EnhancedClient) CreateRoutingPolicy(ctx context.Context, policy *RoutingPolicy) (*RoutingPolicy, error) {
+	var created RoutingPolicy
+	if err := c.Do(ctx, http.MethodPost, "/routing-policies", nil, policy, &created, http.StatusCreated); err != nil {
+		return nil, fmt.Errorf("failed to create routing policy: %w", err)
 	}
+	return &created, nil
+}
 
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+// Monitoring and analytics
+func (c *EnhancedClient) GetGatewayMetrics(ctx context.Context, gatewayID string, duration string) ([]GatewayMetrics, error) {
+	query := url.Values{"duration": {duration}}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to %s gateway: %w", action, err)
+	var metrics []GatewayMetrics
+	if err := c.Do(ctx, http.MethodGet, "/gateways/"+gatewayID+"/metrics", query, nil, &metrics); err != nil {
+		return nil, fmt.Errorf("failed to get gateway metrics: %w", err)
 	}
-	defer resp.Body.Close()
+	return metrics, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to %s gateway with status: %d", action, resp.StatusCode)
+// GetNetworkTopology fetches the current topology, going through the
+// response cache (see WithResponseCache) when one is configured.
+func (c *EnhancedClient) GetNetworkTopology(ctx context.Context) (*NetworkTopology, error) {
+	var topology NetworkTopology
+	if err := c.getCached(ctx, "/topology", &topology); err != nil {
+		return nil, fmt.Errorf("failed to get network topology: %w", err)
 	}
-
-	return nil
+	return &topology, nil
 }
 
-// Connection management
-func (c *EnhancedClient) CreateConnection(ctx context.Context, conn *Connection) (*Connection, error) {
-	jsonData, err := json.Marshal(conn)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal connection: %w", err)
-	}
+// Statistics
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/connections", 
-		bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// GetStats fetches account-wide stats, going through the response cache
+// (see WithResponseCache) when one is configured.
+func (c *EnhancedClient) GetStats(ctx context.Context) (*AviatrixStats, error) {
+	var stats AviatrixStats
+	if err := c.getCached(ctx, "/stats", &stats); err != nil {
+		return nil, fmt.Errorf("failed to get stats: %w", err)
 	}
+	return &stats, nil
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create connection: %w", err)
+// VPN user management
+func (c *EnhancedClient) CreateVPNUser(ctx context.Context, user *VPNUser) (*VPNUser, error) {
+	var created VPNUser
+	if err := c.Do(ctx, http.MethodPost, "/vpn-users", nil, user, &created, http.StatusCreated); err != nil {
+		return nil, fmt.Errorf("failed to create VPN user: %w", err)
 	}
-	defer resp.Body.Close()
+	return &created, nil
+}
 
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("failed to create connection with status: %d", resp.StatusCode)
+func (c *EnhancedClient) DeleteVPNUser(ctx context.Context, id string) error {
+	if err := c.Do(ctx, http.MethodDelete, "/vpn-users/"+id, nil, nil, nil, http.StatusNoContent); err != nil {
+		return fmt.Errorf("failed to delete VPN user: %w", err)
 	}
+	return nil
+}
 
-	var createdConn Connection
-	if err := json.NewDecoder(resp.Body).Decode(&createdConn); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// ListVPNUsers returns every VPN user on the account.
+func (c *EnhancedClient) ListVPNUsers(ctx context.Context) ([]VPNUser, error) {
+	var response VPNUserListResponse
+	if err := c.Do(ctx, http.MethodGet, "/vpn-users", nil, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to list VPN users: %w", err)
 	}
+	return response.VPNUsers, nil
+}
 
-	return &createdConn, nil
+// GetVPNUserConfig fetches userID's ready-to-use client configuration in
+// format ("openvpn" or "wireguard") as the base64-encoded bundle the
+// server returns. Use VPNUserConfig.Decode (or ExportVPNUserBundle) to
+// turn it into usable files.
+func (c *EnhancedClient) GetVPNUserConfig(ctx context.Context, userID, format string) (*VPNUserConfig, error) {
+	var config VPNUserConfig
+	query := url.Values{"format": {format}}
+	if err := c.Do(ctx, http.MethodGet, "/vpn-users/"+userID+"/config", query, nil, &config); err != nil {
+		return nil, fmt.Errorf("failed to get VPN user config: %w", err)
+	}
+	return &config, nil
 }
 
-func (c *EnhancedClient) DeleteConnection(ctx context.Context, id string) error {
-	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+"/connections/"+id, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+// Do centralizes request construction, deadline handling, auth-token
+// refresh on 401, and status-code checking for every verb method above,
+// so each one is a handful of lines instead of the ~30 repeated before.
+// body is marshaled as the request's JSON body when non-nil; out is
+// decoded from the response body when non-nil. okStatus lists the status
+// codes this call accepts as success, defaulting to [http.StatusOK].
+func (c *EnhancedClient) Do(ctx context.Context, method, path string, query url.Values, body, out interface{}, okStatus ...int) error {
+	return c.DoWithHeaders(ctx, method, path, query, body, nil, out, okStatus...)
+}
 
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+// DoWithHeaders is Do plus extra request headers, the escape hatch
+// callers that need something beyond Authorization/Content-Type use
+// (e.g. bulk operations setting an Idempotency-Key per request). Every
+// call mints an X-Request-ID (see WithRequestIDGenerator) and logs one
+// structured line through c.logger once the request (including the
+// automatic 401 retry, if any) has finished.
+func (c *EnhancedClient) DoWithHeaders(ctx context.Context, method, path string, query url.Values, body interface{}, headers map[string]string, out interface{}, okStatus ...int) error {
+	if len(okStatus) == 0 {
+		okStatus = []int{http.StatusOK}
+	}
 
-	resp, err := c.httpClient.Do(req)
+	rr, err := c.doWithRetry(ctx, method, path, query, body, headers)
 	if err != nil {
-		return fmt.Errorf("failed to delete connection: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
+	defer rr.cancel()
+	defer rr.resp.Body.Close()
 
-	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("failed to delete connection with status: %d", resp.StatusCode)
+	if !statusOK(rr.resp.StatusCode, okStatus) {
+		apiErr := newAPIError(method, path, rr.requestID, rr.resp)
+		c.logRequest(method, path, rr.resp.StatusCode, time.Since(rr.start), rr.requestID, rr.retries, apiErr)
+		return apiErr
 	}
+	c.logRequest(method, path, rr.resp.StatusCode, time.Since(rr.start), rr.requestID, rr.retries, nil)
 
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(rr.resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
 	return nil
 }
 
-// Routing management
-func (c *EnhancedClient) ListRoutes(ctx context.Context, gatewayID string) ([]Route, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/routes", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+// retriedRequest is doWithRetry's result: a response that has already
+// gone through the one-retry-after-401 dance, plus everything
+// DoWithHeaders and fetchCached need to finish the call themselves
+// (status check, decode, and/or logging) without re-deriving it.
+type retriedRequest struct {
+	resp      *http.Response
+	cancel    context.CancelFunc
+	requestID string
+	retries   int
+	start     time.Time
+}
 
-	q := req.URL.Query()
-	if gatewayID != "" {
-		q.Add("gateway_id", gatewayID)
+// doWithRetry issues one request, refreshing the authenticator and
+// retrying exactly once on a 401, the shared core DoWithHeaders and
+// fetchCached (the response-cache path) both build on. The caller owns
+// the returned resp and must call cancel and close resp.Body once done;
+// a non-nil error means no resp was obtained and there is nothing to
+// close.
+func (c *EnhancedClient) doWithRetry(ctx context.Context, method, path string, query url.Values, body interface{}, headers map[string]string) (*retriedRequest, error) {
+	requestID := c.requestIDGenerator()
+	reqHeaders := make(map[string]string, len(headers)+1)
+	for k, v := range headers {
+		reqHeaders[k] = v
 	}
-	req.URL.RawQuery = q.Encode()
+	reqHeaders["X-Request-ID"] = requestID
 
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	start := time.Now()
+	retries := 0
 
-	resp, err := c.httpClient.Do(req)
+	resp, cancel, err := c.doOnce(ctx, method, path, query, body, reqHeaders)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list routes: %w", err)
+		c.logRequest(method, path, 0, time.Since(start), requestID, retries, err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to list routes with status: %d", resp.StatusCode)
-	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+		cancel()
 
-	var response RouteListResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		if authErr := c.authenticator.Refresh(ctx); authErr != nil {
+			c.logRequest(method, path, http.StatusUnauthorized, time.Since(start), requestID, retries, authErr)
+			return nil, fmt.Errorf("failed to refresh auth token after 401: %w", authErr)
+		}
+		retries++
+
+		resp, cancel, err = c.doOnce(ctx, method, path, query, body, reqHeaders)
+		if err != nil {
+			c.logRequest(method, path, 0, time.Since(start), requestID, retries, err)
+			return nil, err
+		}
 	}
 
-	return response.Routes, nil
+	return &retriedRequest{resp: resp, cancel: cancel, requestID: requestID, retries: retries, start: start}, nil
 }
 
-func (c *EnhancedClient) CreateRoutingPolicy(ctx context.Context, policy *RoutingPolicy) (*RoutingPolicy, error) {
-	jsonData, err := json.Marshal(policy)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal policy: %w", err)
+// getCached is Do restricted to a GET, routed through the response
+// cache when WithResponseCache configured one; with no cache configured
+// it is exactly Do. Concurrent calls for the same path while a miss is
+// in flight share the one fetch via c.inflight.
+func (c *EnhancedClient) getCached(ctx context.Context, path string, out interface{}) error {
+	if c.respCache == nil {
+		return c.Do(ctx, http.MethodGet, path, nil, nil, out)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/routing-policies", 
-		bytes.NewBuffer(jsonData))
+	v, err := c.inflight.Do(path, func() (interface{}, error) {
+		return c.fetchCached(ctx, path)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return err
 	}
+	if err := json.Unmarshal(v.([]byte), out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create routing policy: %w", err)
+// fetchCached implements getCached's cache lookup, conditional-GET
+// revalidation, and cache write-back, returning the response body's raw
+// bytes (cached or freshly fetched) for getCached to decode.
+func (c *EnhancedClient) fetchCached(ctx context.Context, path string) ([]byte, error) {
+	entry, ok := c.respCache.Get(path)
+	if ok && time.Since(entry.StoredAt) < c.cacheTTL {
+		cacheHits.WithLabelValues(path).Inc()
+		return entry.Body, nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("failed to create routing policy with status: %d", resp.StatusCode)
+	headers := map[string]string{}
+	if ok {
+		if entry.ETag != "" {
+			headers["If-None-Match"] = entry.ETag
+		}
+		if entry.LastModified != "" {
+			headers["If-Modified-Since"] = entry.LastModified
+		}
 	}
 
-	var createdPolicy RoutingPolicy
-	if err := json.NewDecoder(resp.Body).Decode(&createdPolicy); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	rr, err := c.doWithRetry(ctx, http.MethodGet, path, nil, nil, headers)
+	if err != nil {
+		cacheMisses.WithLabelValues(path).Inc()
+		return nil, err
 	}
+	defer rr.cancel()
+	defer rr.resp.Body.Close()
 
-	return &createdPolicy, nil
-}
+	if ok && rr.resp.StatusCode == http.StatusNotModified {
+		cacheRevalidations.WithLabelValues(path).Inc()
+		c.logRequest(http.MethodGet, path, rr.resp.StatusCode, time.Since(rr.start), rr.requestID, rr.retries, nil)
 
-// Monitoring and analytics
-func (c *EnhancedClient) GetGatewayMetrics(ctx context.Context, gatewayID string, duration string) ([]GatewayMetrics, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/gateways/"+gatewayID+"/metrics", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		entry.StoredAt = time.Now()
+		if err := c.respCache.Set(path, entry); err != nil {
+			c.logger.Error("aviatrix: failed to refresh response cache entry", zap.String("path", path), zap.Error(err))
+		}
+		return entry.Body, nil
 	}
 
-	q := req.URL.Query()
-	q.Add("duration", duration)
-	req.URL.RawQuery = q.Encode()
+	cacheMisses.WithLabelValues(path).Inc()
 
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if !statusOK(rr.resp.StatusCode, []int{http.StatusOK}) {
+		apiErr := newAPIError(http.MethodGet, path, rr.requestID, rr.resp)
+		c.logRequest(http.MethodGet, path, rr.resp.StatusCode, time.Since(rr.start), rr.requestID, rr.retries, apiErr)
+		return nil, apiErr
+	}
 
-	resp, err := c.httpClient.Do(req)
+	body, err := io.ReadAll(rr.resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get gateway metrics: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
-	defer resp.Body.Close()
+	c.logRequest(http.MethodGet, path, rr.resp.StatusCode, time.Since(rr.start), rr.requestID, rr.retries, nil)
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get gateway metrics with status: %d", resp.StatusCode)
+	newEntry := &CachedResponse{
+		ETag:         rr.resp.Header.Get("ETag"),
+		LastModified: rr.resp.Header.Get("Last-Modified"),
+		Body:         body,
+		StoredAt:     time.Now(),
 	}
-
-	var metrics []GatewayMetrics
-	if err := json.NewDecoder(resp.Body).Decode(&metrics); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.respCache.Set(path, newEntry); err != nil {
+		c.logger.Error("aviatrix: failed to write response cache entry", zap.String("path", path), zap.Error(err))
 	}
-
-	return metrics, nil
+	return body, nil
 }
 
-func (c *EnhancedClient) GetNetworkTopology(ctx context.Context) (*NetworkTopology, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/topology", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// logRequest emits one structured log line per DoWithHeaders call: an
+// Info line on success, an Error line (with the triggering error) on
+// failure, following the same injected-*zap.Logger shape Service already
+// uses.
+func (c *EnhancedClient) logRequest(method, path string, status int, latency time.Duration, requestID string, retries int, err error) {
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("path", path),
+		zap.Int("status", status),
+		zap.Duration("latency", latency),
+		zap.String("request_id", requestID),
+		zap.Int("retries", retries),
 	}
-
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get network topology: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get network topology with status: %d", resp.StatusCode)
+		c.logger.Error("aviatrix: request failed", append(fields, zap.Error(err))...)
+		return
 	}
+	c.logger.Info("aviatrix: request", fields...)
+}
 
-	var topology NetworkTopology
-	if err := json.NewDecoder(resp.Body).Decode(&topology); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
+// apiErrorEnvelope is the JSON shape a failed response's body is parsed
+// as, best-effort: a response that isn't in this shape (or isn't JSON at
+// all) still yields an APIError, just with Code/Message left empty.
+type apiErrorEnvelope struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id"`
+}
 
-	return &topology, nil
+// APIError is the error DoWithHeaders returns when a response's status
+// isn't one of okStatus. Retryable mirrors the 429/5xx classification
+// bulk.go's retry loop uses, and Is lets a caller write
+// errors.Is(err, aviatrix.ErrNotFound) instead of comparing StatusCode
+// directly.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Code       string
+	Message    string
+	RequestID  string
+	Retryable  bool
+	Body       []byte
 }
 
-// Statistics
-func (c *EnhancedClient) GetStats(ctx context.Context) (*AviatrixStats, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/stats", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s %s failed with status %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
 	}
+	return fmt.Sprintf("%s %s failed with status: %d", e.Method, e.Path, e.StatusCode)
+}
 
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get stats: %w", err)
+// Is matches e against one of the sentinel errors below by status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	default:
+		return false
 	}
-	defer resp.Body.Close()
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get stats with status: %d", resp.StatusCode)
-	}
+// Sentinel errors APIError supports matching through errors.Is, for
+// callers that want to branch on a well-known failure class rather than
+// a raw status code.
+var (
+	ErrNotFound     = errors.New("aviatrix: resource not found")
+	ErrConflict     = errors.New("aviatrix: resource conflict")
+	ErrUnauthorized = errors.New("aviatrix: unauthorized")
+	ErrRateLimited  = errors.New("aviatrix: rate limited")
+)
 
-	var stats AviatrixStats
-	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// newAPIError builds an APIError from a non-OK response, reading and
+// closing the body in the process (callers must not read resp.Body
+// afterward). A response whose body fails to parse as apiErrorEnvelope
+// still yields an APIError with Code/Message left empty and Body holding
+// whatever bytes the server actually sent.
+func newAPIError(method, path, requestID string, resp *http.Response) *APIError {
+	data, _ := io.ReadAll(resp.Body)
+
+	var envelope apiErrorEnvelope
+	_ = json.Unmarshal(data, &envelope)
+	if envelope.RequestID != "" {
+		requestID = envelope.RequestID
+	}
+
+	return &APIError{
+		Method:     method,
+		Path:       path,
+		StatusCode: resp.StatusCode,
+		Code:       envelope.Code,
+		Message:    envelope.Message,
+		RequestID:  requestID,
+		Retryable:  resp.StatusCode == http.StatusTooManyRequests || (resp.StatusCode >= 500 && resp.StatusCode < 600),
+		Body:       data,
 	}
-
-	return &stats, nil
 }
 
-// VPN user management
-func (c *EnhancedClient) CreateVPNUser(ctx context.Context, user *VPNUser) (*VPNUser, error) {
-	jsonData, err := json.Marshal(user)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal user: %w", err)
+// doOnce builds and issues one request, applying the client's deadline
+// (SetDeadline) on top of ctx. The returned cancel must be called once
+// the response body has been fully read or discarded.
+func (c *EnhancedClient) doOnce(ctx context.Context, method, path string, query url.Values, body interface{}, headers map[string]string) (*http.Response, context.CancelFunc, error) {
+	ctx, cancel := c.deadline.context(ctx)
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			cancel()
+			return nil, nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reader = bytes.NewBuffer(data)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/vpn-users", 
-		bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		cancel()
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if len(query) > 0 {
+		req.URL.RawQuery = query.Encode()
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if err := c.authenticator.Apply(req); err != nil {
+		cancel()
+		return nil, nil, fmt.Errorf("aviatrix: applying authenticator: %w", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
 	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create VPN user: %w", err)
+		cancel()
+		return nil, nil, fmt.Errorf("request to %s failed: %w", path, err)
 	}
-	defer resp.Body.Close()
+	return resp, cancel, nil
+}
 
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("failed to create VPN user with status: %d", resp.StatusCode)
+func statusOK(status int, okStatus []int) bool {
+	for _, s := range okStatus {
+		if status == s {
+			return true
+		}
 	}
+	return false
+}
 
-	var createdUser VPNUser
-	if err := json.NewDecoder(resp.Body).Decode(&createdUser); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
+// deadlineTimer mirrors the deadlineTimer netstack's transport endpoints
+// use for SetDeadline: setDeadline resets a time.AfterFunc and closes the
+// previous cancel channel whenever the deadline changes, so every call
+// already waiting on context derived from it observes the new deadline
+// without the client having to be torn down and rebuilt.
+type deadlineTimer struct {
+	mu     sync.Mutex
+	timer  *time.Timer
+	cancel chan struct{}
+}
 
-	return &createdUser, nil
+func newDeadlineTimer() *deadlineTimer {
+	return &deadlineTimer{cancel: make(chan struct{})}
 }
 
-func (c *EnhancedClient) DeleteVPNUser(ctx context.Context, id string) error {
-	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+"/vpn-users/"+id, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+// setDeadline sets t as the new deadline, closing the previous cancel
+// channel and replacing it with a fresh one. A zero t clears the
+// deadline instead of setting one.
+func (d *deadlineTimer) setDeadline(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	close(d.cancel)
+	d.cancel = make(chan struct{})
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to delete VPN user: %w", err)
+	if t.IsZero() {
+		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("failed to delete VPN user with status: %d", resp.StatusCode)
+	cancelCh := d.cancel
+	if until := time.Until(t); until <= 0 {
+		close(cancelCh)
+	} else {
+		d.timer = time.AfterFunc(until, func() { close(cancelCh) })
 	}
+}
 
-	return nil
+// context derives a child of ctx that is also canceled when the
+// deadline set by setDeadline elapses; if the deadline is changed while
+// the context is live, it follows the new deadline instead. The
+// returned cancel must always be called once the caller is done with
+// the context, the same as any context.CancelFunc.
+func (d *deadlineTimer) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	d.mu.Lock()
+	cancelCh := d.cancel
+	d.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-cancelCh:
+				d.mu.Lock()
+				next := d.cancel
+				d.mu.Unlock()
+				if next == cancelCh {
+					// The deadline itself elapsed.
+					cancel()
+					return
+				}
+				// The deadline was changed: follow the new one.
+				cancelCh = next
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return ctx, func() {
+		close(stop)
+		cancel()
+	}
 }
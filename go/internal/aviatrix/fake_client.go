@@ -0,0 +1,216 @@
+package aviatrix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// FakeClient is an in-memory AviatrixService that replays canned JSON
+// envelopes instead of calling a real controller, so handlers built
+// against AviatrixService can be exercised end-to-end without network
+// access. Register a response with Set before the call it answers is
+// made; an unregistered action returns an error instead of a zero value,
+// so a test notices a missing stub rather than silently passing.
+type FakeClient struct {
+	mu        sync.Mutex
+	responses map[string]json.RawMessage
+	errors    map[string]error
+	calls     []FakeCall
+}
+
+// FakeCall records one call FakeClient answered, so a test can assert on
+// what was actually sent as well as what was returned.
+type FakeCall struct {
+	Action string
+	Params map[string]interface{}
+}
+
+// NewFakeClient returns an empty FakeClient; use Set to register the
+// canned response for each action under test.
+func NewFakeClient() *FakeClient {
+	return &FakeClient{
+		responses: make(map[string]json.RawMessage),
+		errors:    make(map[string]error),
+	}
+}
+
+// Set registers result as the JSON-encoded response for action, the
+// payload a real controller would have returned in the envelope's
+// "results" field.
+func (f *FakeClient) Set(action string, result interface{}) {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		panic(fmt.Sprintf("aviatrix: FakeClient.Set(%s): %v", action, err))
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[action] = raw
+	delete(f.errors, action)
+}
+
+// SetError makes action fail with err instead of returning a response,
+// standing in for a controller envelope with return=false.
+func (f *FakeClient) SetError(action string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errors[action] = err
+	delete(f.responses, action)
+}
+
+// Calls returns every call FakeClient has answered so far, in order.
+func (f *FakeClient) Calls() []FakeCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make([]FakeCall, len(f.calls))
+	copy(out, f.calls)
+	return out
+}
+
+func (f *FakeClient) invoke(action string, params map[string]interface{}, out interface{}) error {
+	f.mu.Lock()
+	f.calls = append(f.calls, FakeCall{Action: action, Params: params})
+	err, hasErr := f.errors[action]
+	raw, hasResp := f.responses[action]
+	f.mu.Unlock()
+
+	if hasErr {
+		return err
+	}
+	if !hasResp {
+		return fmt.Errorf("aviatrix: FakeClient has no response registered for action %q", action)
+	}
+	return json.Unmarshal(raw, out)
+}
+
+func (f *FakeClient) GetGateways(ctx context.Context) ([]ControllerGateway, error) {
+	var out []ControllerGateway
+	err := f.invoke("list_vpcs_summary", nil, &out)
+	return out, err
+}
+
+func (f *FakeClient) GetTransitGateways(ctx context.Context) ([]ControllerTransitGateway, error) {
+	var out []ControllerTransitGateway
+	err := f.invoke("list_transit_gateways", nil, &out)
+	return out, err
+}
+
+func (f *FakeClient) GetSpokeGateways(ctx context.Context) ([]ControllerSpokeGateway, error) {
+	var out []ControllerSpokeGateway
+	err := f.invoke("list_spoke_gateways", nil, &out)
+	return out, err
+}
+
+func (f *FakeClient) GetTransitGatewayPeering(ctx context.Context) ([]TransitGatewayPeering, error) {
+	var out []TransitGatewayPeering
+	err := f.invoke("list_transit_peerings", nil, &out)
+	return out, err
+}
+
+func (f *FakeClient) GetSecurityDomains(ctx context.Context) ([]SecurityDomain, error) {
+	var out []SecurityDomain
+	err := f.invoke("list_security_domains", nil, &out)
+	return out, err
+}
+
+func (f *FakeClient) GetFirewallPolicies(ctx context.Context) ([]FirewallPolicy, error) {
+	var out []FirewallPolicy
+	err := f.invoke("list_firewall_policies", nil, &out)
+	return out, err
+}
+
+func (f *FakeClient) GetMetrics(ctx context.Context, gatewayName, timeRange string) (*Metrics, error) {
+	var out Metrics
+	err := f.invoke("get_gateway_metrics", map[string]interface{}{"gateway_name": gatewayName, "time_range": timeRange}, &out)
+	return &out, err
+}
+
+func (f *FakeClient) CreateTransitGateway(ctx context.Context, req *CreateTransitGatewayRequest) (*ControllerTransitGateway, error) {
+	var out ControllerTransitGateway
+	err := f.invoke("create_transit_gw", map[string]interface{}{"request": req}, &out)
+	return &out, err
+}
+
+func (f *FakeClient) UpdateTransitGateway(ctx context.Context, gatewayName string, req *CreateTransitGatewayRequest) (*ControllerTransitGateway, error) {
+	var out ControllerTransitGateway
+	err := f.invoke("update_transit_gw", map[string]interface{}{"gateway_name": gatewayName, "request": req}, &out)
+	return &out, err
+}
+
+func (f *FakeClient) DeleteGateway(ctx context.Context, gatewayName string) error {
+	var out struct{}
+	return f.invoke("delete_gw", map[string]interface{}{"gateway_name": gatewayName}, &out)
+}
+
+func (f *FakeClient) CreateSpokeGateway(ctx context.Context, req *CreateTransitGatewayRequest) (*ControllerSpokeGateway, error) {
+	var out ControllerSpokeGateway
+	err := f.invoke("create_spoke_gw", map[string]interface{}{"request": req}, &out)
+	return &out, err
+}
+
+func (f *FakeClient) AttachSpokeToTransit(ctx context.Context, spokeGateway, transitGateway string) error {
+	var out struct{}
+	return f.invoke("spoke_join_transit", map[string]interface{}{"spoke_gw": spokeGateway, "transit_gw": transitGateway}, &out)
+}
+
+func (f *FakeClient) CreateTransitGatewayPeering(ctx context.Context, sourceGateway, destinationGateway string) (*TransitGatewayPeering, error) {
+	var out TransitGatewayPeering
+	err := f.invoke("create_transit_gateway_peering", map[string]interface{}{"source": sourceGateway, "destination": destinationGateway}, &out)
+	return &out, err
+}
+
+func (f *FakeClient) CreateFirewallPolicy(ctx context.Context, policy *FirewallPolicy) (*FirewallPolicy, error) {
+	var out FirewallPolicy
+	err := f.invoke("add_firewall_policy", map[string]interface{}{"policy": policy}, &out)
+	return &out, err
+}
+
+func (f *FakeClient) CreateSecurityDomain(ctx context.Context, domain *SecurityDomain) (*SecurityDomain, error) {
+	var out SecurityDomain
+	err := f.invoke("create_security_domain", map[string]interface{}{"domain": domain}, &out)
+	return &out, err
+}
+
+func (f *FakeClient) GeneratePeeringToken(ctx context.Context, localGateway, remoteName string) (string, error) {
+	var out string
+	err := f.invoke("generate_peering_token", map[string]interface{}{"local_gateway": localGateway, "remote_name": remoteName}, &out)
+	return out, err
+}
+
+func (f *FakeClient) EstablishPeering(ctx context.Context, token, localGateway string) (*TransitGatewayPeering, error) {
+	var out TransitGatewayPeering
+	err := f.invoke("establish_multi_cluster_peering", map[string]interface{}{"token": token, "local_gateway": localGateway}, &out)
+	return &out, err
+}
+
+func (f *FakeClient) TeardownPeering(ctx context.Context, peeringName string) error {
+	var out struct{}
+	return f.invoke("delete_transit_gateway_peering", map[string]interface{}{"peering_name": peeringName}, &out)
+}
+
+func (f *FakeClient) ListLearnedCIDRs(ctx context.Context, gatewayName string, stateFilter CIDRState) ([]LearnedCIDR, error) {
+	var out []LearnedCIDR
+	err := f.invoke("list_learned_cidrs", map[string]interface{}{"gw_name": gatewayName, "state": stateFilter}, &out)
+	return out, err
+}
+
+func (f *FakeClient) ApproveLearnedCIDR(ctx context.Context, gatewayName, cidr, actor, reason string) (*LearnedCIDR, error) {
+	var out LearnedCIDR
+	err := f.invoke("approve_learned_cidr", map[string]interface{}{"gw_name": gatewayName, "cidr": cidr, "actor": actor, "reason": reason}, &out)
+	return &out, err
+}
+
+func (f *FakeClient) RejectLearnedCIDR(ctx context.Context, gatewayName, cidr, actor, reason string) (*LearnedCIDR, error) {
+	var out LearnedCIDR
+	err := f.invoke("reject_learned_cidr", map[string]interface{}{"gw_name": gatewayName, "cidr": cidr, "actor": actor, "reason": reason}, &out)
+	return &out, err
+}
+
+func (f *FakeClient) BulkDecide(ctx context.Context, gatewayName string, cidrs []string, state CIDRState, actor, reason string) ([]LearnedCIDR, error) {
+	var out []LearnedCIDR
+	err := f.invoke("bulk_decide_learned_cidrs", map[string]interface{}{"gw_name": gatewayName, "cidrs": cidrs, "state": state, "actor": actor, "reason": reason}, &out)
+	return out, err
+}
+
+var _ AviatrixService = (*FakeClient)(nil)
@@ -0,0 +1,215 @@
+package aviatrix
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"router-sim/internal/analytics"
+)
+
+const (
+	// gatewayReconcileAttempts bounds how many times gatewayReconciler
+	// polls Client.GetGatewayStatus for a gateway to report "up" before
+	// giving up and marking it GatewayFailed.
+	gatewayReconcileAttempts = 20
+	// gatewayReconcilePollInterval is the wait between polls.
+	gatewayReconcilePollInterval = 5 * time.Second
+	// gatewayReconcileWorkers bounds how many create/delete jobs
+	// gatewayReconciler drives concurrently.
+	gatewayReconcileWorkers = 4
+)
+
+// gatewayJob is one create-or-delete reconcile queued by
+// Service.CreateGateway/DeleteGateway and drained by gatewayReconciler's
+// worker pool.
+type gatewayJob struct {
+	id     string
+	delete bool
+}
+
+// gatewayReconciler is the async worker pool that drives a GatewayStore
+// record's observed state toward its desired Spec against Client: a
+// create job issues the Client call and then polls GetGatewayStatus
+// until the gateway reports "up" (or the job runs out of attempts), and
+// a delete job issues the Client delete call and removes the record.
+// Every phase transition is also recorded as an Event, so operators can
+// audit gateway churn from ClickHouse instead of only from the current
+// GatewayStore snapshot.
+type gatewayReconciler struct {
+	client     *Client
+	store      *GatewayStore
+	clickhouse *analytics.ClickHouseClient
+	logger     *zap.Logger
+	hostname   string
+
+	jobs chan gatewayJob
+	wg   sync.WaitGroup
+}
+
+func newGatewayReconciler(client *Client, store *GatewayStore, clickhouse *analytics.ClickHouseClient, logger *zap.Logger) *gatewayReconciler {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	r := &gatewayReconciler{
+		client:     client,
+		store:      store,
+		clickhouse: clickhouse,
+		logger:     logger,
+		hostname:   hostname,
+		jobs:       make(chan gatewayJob, 64),
+	}
+	for i := 0; i < gatewayReconcileWorkers; i++ {
+		r.wg.Add(1)
+		go r.worker()
+	}
+	return r
+}
+
+func (r *gatewayReconciler) enqueue(job gatewayJob) {
+	r.jobs <- job
+}
+
+func (r *gatewayReconciler) worker() {
+	defer r.wg.Done()
+	for job := range r.jobs {
+		if job.delete {
+			r.reconcileDelete(job.id)
+		} else {
+			r.reconcileCreate(job.id)
+		}
+	}
+}
+
+func (r *gatewayReconciler) reconcileCreate(id string) {
+	rec, ok := r.store.Get(id)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if err := r.issueCreate(ctx, rec); err != nil {
+		cancel()
+		r.fail(rec, fmt.Errorf("create: %w", err))
+		return
+	}
+	cancel()
+
+	rec.Phase = GatewayCreating
+	rec.ObservedGeneration = rec.Generation
+	rec.UpdatedAt = time.Now()
+	_ = r.store.Put(*rec)
+	r.recordEvent(rec, "gateway_creating", "info", fmt.Sprintf("issued create for %s", rec.ID))
+
+	for attempt := 1; attempt <= gatewayReconcileAttempts; attempt++ {
+		time.Sleep(gatewayReconcilePollInterval)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		status, err := r.client.GetGatewayStatus(ctx, rec.ID)
+		cancel()
+		if err != nil {
+			continue
+		}
+		if state, _ := status["status"].(string); state == "up" {
+			rec.Phase = GatewayUp
+			rec.LastError = ""
+			rec.UpdatedAt = time.Now()
+			_ = r.store.Put(*rec)
+			r.recordEvent(rec, "gateway_up", "info", fmt.Sprintf("%s reached up after %d attempt(s)", rec.ID, attempt))
+			return
+		}
+	}
+
+	r.fail(rec, fmt.Errorf("gave up waiting for %s to reach up after %d attempts", rec.ID, gatewayReconcileAttempts))
+}
+
+// issueCreate dispatches rec.Spec to the Client method matching its Kind.
+func (r *gatewayReconciler) issueCreate(ctx context.Context, rec *GatewayRecord) error {
+	spec := rec.Spec
+	switch spec.Kind {
+	case GatewayKindTransit:
+		_, err := r.client.CreateTransitGateway(ctx, &TransitGateway{
+			ID: rec.ID, Name: spec.Name, CloudType: spec.Cloud, Region: spec.Region,
+		})
+		return err
+	case GatewayKindSpoke:
+		_, err := r.client.CreateSpokeGateway(ctx, &SpokeGateway{
+			ID: rec.ID, Name: spec.Name, CloudType: spec.Cloud, Region: spec.Region, VPC: spec.VPCID,
+		})
+		return err
+	default:
+		_, err := r.client.CreateGateway(ctx, &Gateway{
+			ID: rec.ID, Name: spec.Name, CloudType: spec.Cloud, Region: spec.Region,
+		})
+		return err
+	}
+}
+
+func (r *gatewayReconciler) reconcileDelete(id string) {
+	rec, ok := r.store.Get(id)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	var err error
+	switch rec.Spec.Kind {
+	case GatewayKindTransit:
+		_, err = r.client.DeleteTransitGateway(ctx, rec.ID)
+	case GatewayKindSpoke:
+		_, err = r.client.DeleteSpokeGateway(ctx, rec.ID)
+	default:
+		_, err = r.client.DeleteGateway(ctx, rec.ID)
+	}
+	cancel()
+
+	if err != nil {
+		r.fail(rec, fmt.Errorf("delete: %w", err))
+		return
+	}
+
+	r.recordEvent(rec, "gateway_deleted", "info", fmt.Sprintf("deleted %s", rec.ID))
+	_ = r.store.Delete(rec.ID)
+}
+
+func (r *gatewayReconciler) fail(rec *GatewayRecord, err error) {
+	rec.Phase = GatewayFailed
+	rec.LastError = err.Error()
+	rec.UpdatedAt = time.Now()
+	_ = r.store.Put(*rec)
+	r.logger.Error("aviatrix gateway reconcile failed", zap.String("id", rec.ID), zap.Error(err))
+	r.recordEvent(rec, "gateway_failed", "error", err.Error())
+}
+
+// recordEvent enqueues an audit row for rec's latest transition. A
+// nil clickhouse (the common case until it's configured) skips the row
+// silently and a failed enqueue is logged; neither blocks the reconcile
+// loop — gateway
+// lifecycle management must not depend on the analytics pipeline being
+// up.
+func (r *gatewayReconciler) recordEvent(rec *GatewayRecord, eventType, severity, message string) {
+	if r.clickhouse == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.clickhouse.InsertEvent(ctx, analytics.Event{
+		Timestamp: time.Now(),
+		Hostname:  r.hostname,
+		EventType: eventType,
+		Severity:  severity,
+		Message:   message,
+		Details:   rec.ID,
+	})
+	if err != nil {
+		r.logger.Warn("failed to record gateway lifecycle event", zap.String("id", rec.ID), zap.Error(err))
+	}
+}
@@ -0,0 +1,151 @@
+package aviatrix
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// GatewayPhase is where a GatewayRecord stands in gatewayReconciler's
+// loop, mirroring the phase field a Kubernetes controller exposes on a
+// managed object's status subresource.
+type GatewayPhase string
+
+const (
+	GatewayPending  GatewayPhase = "pending"
+	GatewayCreating GatewayPhase = "creating"
+	GatewayUp       GatewayPhase = "up"
+	GatewayFailed   GatewayPhase = "failed"
+	GatewayDeleting GatewayPhase = "deleting"
+)
+
+// GatewayKind is which Client create/delete call a GatewayRecord's Spec
+// reconciles through.
+type GatewayKind string
+
+const (
+	GatewayKindPlain   GatewayKind = "gateway"
+	GatewayKindTransit GatewayKind = "transit"
+	GatewayKindSpoke   GatewayKind = "spoke"
+)
+
+// GatewaySpec is the desired state CreateGateway, CreateTransitGateway,
+// and CreateSpokeGateway write; Kind tells gatewayReconciler which of
+// the three it is so it knows which Client methods create and delete it.
+type GatewaySpec struct {
+	Kind   GatewayKind `json:"kind"`
+	Name   string      `json:"name"`
+	Cloud  string      `json:"cloud"`
+	Region string      `json:"region"`
+	ASN    int         `json:"asn,omitempty"`
+	VPCID  string      `json:"vpc_id,omitempty"`
+}
+
+// GatewayRecord is one gateway's desired spec plus gatewayReconciler's
+// last observed status, the unit GatewayStore persists and
+// CreateGateway/GetGateway/DeleteGateway read and write.
+// ObservedGeneration trailing Generation means the reconciler hasn't
+// caught up with the latest write yet, the same signal a Kubernetes
+// controller's status.observedGeneration gives a caller polling for
+// completion.
+type GatewayRecord struct {
+	ID                 string       `json:"id"`
+	Spec               GatewaySpec  `json:"spec"`
+	Phase              GatewayPhase `json:"phase"`
+	LastError          string       `json:"last_error,omitempty"`
+	Generation         int64        `json:"generation"`
+	ObservedGeneration int64        `json:"observed_generation"`
+	CreatedAt          time.Time    `json:"created_at"`
+	UpdatedAt          time.Time    `json:"updated_at"`
+}
+
+var gatewayStoreBucket = []byte("aviatrix_gateways")
+
+// GatewayStore persists GatewayRecords across restarts, so GET reads
+// gatewayReconciler's last observed state instead of whatever a single
+// process instance happens to hold in memory.
+type GatewayStore struct {
+	db *bbolt.DB
+}
+
+// NewGatewayStore opens (creating if necessary) a BoltDB file at path
+// and prepares it to store GatewayRecords.
+func NewGatewayStore(path string) (*GatewayStore, error) {
+	db, err := bbolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("aviatrix: opening gateway store %q: %w", path, err)
+	}
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(gatewayStoreBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("aviatrix: creating gateway store bucket: %w", err)
+	}
+	return &GatewayStore{db: db}, nil
+}
+
+// Get returns the record stored for id, if any.
+func (s *GatewayStore) Get(id string) (*GatewayRecord, bool) {
+	var rec GatewayRecord
+	found := false
+	_ = s.db.View(func(tx *bbolt.Tx) error {
+		data := tx.Bucket(gatewayStoreBucket).Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil
+		}
+		found = true
+		return nil
+	})
+	if !found {
+		return nil, false
+	}
+	return &rec, true
+}
+
+// List returns every record in the store, in no particular order.
+func (s *GatewayStore) List() ([]GatewayRecord, error) {
+	var records []GatewayRecord
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(gatewayStoreBucket).ForEach(func(_, v []byte) error {
+			var rec GatewayRecord
+			if err := json.Unmarshal(v, &rec); err != nil {
+				return err
+			}
+			records = append(records, rec)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("aviatrix: listing gateway records: %w", err)
+	}
+	return records, nil
+}
+
+// Put upserts rec.
+func (s *GatewayStore) Put(rec GatewayRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("aviatrix: encoding gateway record: %w", err)
+	}
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(gatewayStoreBucket).Put([]byte(rec.ID), data)
+	})
+}
+
+// Delete removes id's record, if any.
+func (s *GatewayStore) Delete(id string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(gatewayStoreBucket).Delete([]byte(id))
+	})
+}
+
+// Close releases the underlying BoltDB file.
+func (s *GatewayStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,194 @@
+package aviatrix
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.etcd.io/bbolt"
+)
+
+// CachedResponse is one cached GET response: the raw body plus whatever
+// conditional-GET metadata the server sent with it, so a later call can
+// revalidate with If-None-Match/If-Modified-Since instead of refetching
+// the full body.
+type CachedResponse struct {
+	ETag         string
+	LastModified string
+	Body         []byte
+	StoredAt     time.Time
+}
+
+// ResponseCache stores one CachedResponse per request path for
+// GetNetworkTopology and GetStats's conditional-GET support. It is named
+// ResponseCache rather than Cache because Cache already names Client's
+// gateway/connection resource cache in this package — this caches raw
+// HTTP responses for EnhancedClient instead.
+type ResponseCache interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, entry *CachedResponse) error
+	Close() error
+}
+
+// MemoryResponseCache is an in-process ResponseCache backed by a map. It
+// is the simplest option and the right default for a single-instance
+// client, but does not survive a restart or share state across
+// instances.
+type MemoryResponseCache struct {
+	mu      sync.RWMutex
+	entries map[string]*CachedResponse
+}
+
+// NewMemoryResponseCache creates an empty MemoryResponseCache.
+func NewMemoryResponseCache() *MemoryResponseCache {
+	return &MemoryResponseCache{entries: make(map[string]*CachedResponse)}
+}
+
+func (c *MemoryResponseCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+func (c *MemoryResponseCache) Set(key string, entry *CachedResponse) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+	return nil
+}
+
+func (c *MemoryResponseCache) Close() error { return nil }
+
+var responseCacheBucket = []byte("aviatrix_response_cache")
+
+// BoltResponseCache is a ResponseCache backed by a BoltDB file, for a
+// client that wants its cache to survive a restart (or, opened
+// read-only elsewhere, to be inspected out of process).
+type BoltResponseCache struct {
+	db *bbolt.DB
+}
+
+// NewBoltResponseCache opens (creating if necessary) a BoltDB file at
+// path and prepares it to store cached responses.
+func NewBoltResponseCache(path string) (*BoltResponseCache, error) {
+	db, err := bbolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("aviatrix: opening response cache %q: %w", path, err)
+	}
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(responseCacheBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("aviatrix: creating response cache bucket: %w", err)
+	}
+	return &BoltResponseCache{db: db}, nil
+}
+
+func (c *BoltResponseCache) Get(key string) (*CachedResponse, bool) {
+	var entry CachedResponse
+	found := false
+	_ = c.db.View(func(tx *bbolt.Tx) error {
+		data := tx.Bucket(responseCacheBucket).Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil
+		}
+		found = true
+		return nil
+	})
+	if !found {
+		return nil, false
+	}
+	return &entry, true
+}
+
+func (c *BoltResponseCache) Set(key string, entry *CachedResponse) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("aviatrix: encoding response cache entry: %w", err)
+	}
+	return c.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(responseCacheBucket).Put([]byte(key), data)
+	})
+}
+
+func (c *BoltResponseCache) Close() error {
+	return c.db.Close()
+}
+
+// inflightGroup deduplicates concurrent cache misses for the same key —
+// the same one-fetch-many-waiters shape KeystoneV3Authenticator's token
+// fetch uses in the cloudpods package — so a burst of concurrent
+// GetStats calls during a TTL expiry triggers one upstream fetch instead
+// of a thundering herd of identical ones.
+type inflightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*inflightCall
+}
+
+type inflightCall struct {
+	wg  sync.WaitGroup
+	val interface{}
+	err error
+}
+
+func newInflightGroup() *inflightGroup {
+	return &inflightGroup{calls: make(map[string]*inflightCall)}
+}
+
+func (g *inflightGroup) Do(key string, fn func() (interface{}, error)) (interface{}, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.val, call.err
+	}
+
+	call := &inflightCall{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.val, call.err = fn()
+	call.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+
+	return call.val, call.err
+}
+
+var (
+	cacheHits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aviatrix_cache_hits_total",
+			Help: "EnhancedClient response cache hits (served without a network call), by path.",
+		},
+		[]string{"path"},
+	)
+	cacheMisses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aviatrix_cache_misses_total",
+			Help: "EnhancedClient response cache misses requiring a full GET, by path.",
+		},
+		[]string{"path"},
+	)
+	cacheRevalidations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aviatrix_cache_revalidations_total",
+			Help: "EnhancedClient conditional GETs that returned 304 Not Modified, by path.",
+		},
+		[]string{"path"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(cacheHits, cacheMisses, cacheRevalidations)
+}
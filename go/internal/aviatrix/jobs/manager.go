@@ -0,0 +1,187 @@
+// Package jobs runs long-lived Aviatrix control-plane operations
+// (gateway and tunnel creation against the real controller, which can
+// take many minutes) in the background and tracks their progress, so an
+// HTTP handler can answer immediately with a job id instead of holding
+// the request open until the controller responds.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"router-sim/internal/events"
+)
+
+// Manager owns every Job this process has started. A single mutex
+// guards both the job map and every Job's mutable fields, the same
+// convention internal/capture.Manager uses for its Captures.
+type Manager struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+// NewManager returns an empty Manager.
+func NewManager() *Manager {
+	return &Manager{jobs: make(map[string]*Job)}
+}
+
+// Run starts operation as a background Job and returns it immediately
+// in StatusPending. fn is invoked with a context canceled by Cancel and
+// a log func it can call any number of times to record progress; its
+// return value becomes the Job's Result on success, or its error the
+// Job's Error on failure.
+func (m *Manager) Run(operation string, fn func(ctx context.Context, log func(string)) (interface{}, error)) *Job {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	job := &Job{
+		ID:          events.NewID(),
+		Operation:   operation,
+		Status:      StatusPending,
+		CreatedAt:   time.Now(),
+		cancel:      cancel,
+		subscribers: make(map[chan LogEntry]bool),
+	}
+
+	m.mu.Lock()
+	m.jobs[job.ID] = job
+	m.mu.Unlock()
+
+	go m.run(ctx, job, fn)
+	return job
+}
+
+func (m *Manager) run(ctx context.Context, job *Job, fn func(context.Context, func(string)) (interface{}, error)) {
+	now := time.Now()
+	m.mu.Lock()
+	job.Status = StatusRunning
+	job.StartedAt = &now
+	m.mu.Unlock()
+	m.appendLog(job, "job started")
+
+	result, err := fn(ctx, func(message string) { m.appendLog(job, message) })
+
+	completed := time.Now()
+	m.mu.Lock()
+	job.CompletedAt = &completed
+	if err != nil {
+		job.Status = StatusFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = StatusSucceeded
+		job.Result = result
+	}
+	status := job.Status
+	m.mu.Unlock()
+
+	m.appendLog(job, fmt.Sprintf("job %s", status))
+	m.closeSubscribers(job)
+}
+
+// appendLog records message on job and fans it out to every current
+// subscriber, dropping it for any subscriber whose buffer is full
+// rather than blocking the job on a slow stream client.
+func (m *Manager) appendLog(job *Job, message string) {
+	entry := LogEntry{Timestamp: time.Now(), Message: message}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job.Log = append(job.Log, entry)
+	for ch := range job.subscribers {
+		select {
+		case ch <- entry:
+		default:
+		}
+	}
+}
+
+func (m *Manager) closeSubscribers(job *Job) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for ch := range job.subscribers {
+		close(ch)
+	}
+	job.subscribers = nil
+}
+
+// Get returns the Job named id.
+func (m *Manager) Get(id string) (*Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	return job, ok
+}
+
+// List returns every Job this Manager has started, in no particular
+// order.
+func (m *Manager) List() []*Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]*Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		out = append(out, job)
+	}
+	return out
+}
+
+// Logs returns a copy of every LogEntry id has recorded so far.
+func (m *Manager) Logs(id string) ([]LogEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	return append([]LogEntry(nil), job.Log...), true
+}
+
+// Cancel cancels the context id's fn was started with, the same
+// cooperative cancellation internal/capture.Manager.Stop uses; fn is
+// responsible for noticing ctx.Done and returning promptly.
+func (m *Manager) Cancel(id string) error {
+	m.mu.Lock()
+	job, ok := m.jobs[id]
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("jobs: %q not found", id)
+	}
+	job.cancel()
+	return nil
+}
+
+// Watch registers ch to receive every LogEntry id appends to from this
+// call onward, and returns the log recorded before this call so the
+// caller doesn't miss anything that happened before it subscribed. ch
+// is closed once the job reaches a terminal Status (including
+// immediately, if it already had by the time Watch was called), so a
+// caller can simply range over it until the job finishes. It returns
+// false if id is unknown.
+func (m *Manager) Watch(id string, ch chan LogEntry) ([]LogEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, false
+	}
+
+	backlog := append([]LogEntry(nil), job.Log...)
+	if job.subscribers == nil {
+		close(ch)
+		return backlog, true
+	}
+	job.subscribers[ch] = true
+	return backlog, true
+}
+
+// Unwatch removes ch registered with Watch. It is a no-op if the job
+// already finished and closed ch itself.
+func (m *Manager) Unwatch(id string, ch chan LogEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if job, ok := m.jobs[id]; ok && job.subscribers != nil {
+		delete(job.subscribers, ch)
+	}
+}
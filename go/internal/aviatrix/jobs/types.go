@@ -0,0 +1,45 @@
+package jobs
+
+import "time"
+
+// Status is where a Job currently sits in its lifecycle.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// LogEntry is one line of progress output a Job has recorded, replayed
+// in order to both GetJobLogs and a late-connecting stream subscriber.
+type LogEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Message   string    `json:"message"`
+}
+
+// Job is one asynchronous Aviatrix control-plane operation: its current
+// Status, the Result it produced if it Succeeded, and the Error it
+// failed with if it did not. Fields are only ever mutated by the
+// owning Manager under its own mutex, the same convention
+// internal/capture.Capture uses, so Job itself needs none of its own.
+type Job struct {
+	ID          string      `json:"id"`
+	Operation   string      `json:"operation"`
+	Status      Status      `json:"status"`
+	Result      interface{} `json:"result,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	Log         []LogEntry  `json:"-"`
+	CreatedAt   time.Time   `json:"created_at"`
+	StartedAt   *time.Time  `json:"started_at,omitempty"`
+	CompletedAt *time.Time  `json:"completed_at,omitempty"`
+
+	cancel      func()
+	subscribers map[chan LogEntry]bool
+}
+
+// Done reports whether j has reached a terminal Status.
+func (j *Job) Done() bool {
+	return j.Status == StatusSucceeded || j.Status == StatusFailed
+}
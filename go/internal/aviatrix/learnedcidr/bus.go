@@ -0,0 +1,55 @@
+package learnedcidr
+
+import (
+	"sync"
+
+	"router-sim/internal/aviatrix"
+)
+
+// Bus is an in-memory pub/sub of pending LearnedCIDRs, keyed by gateway
+// name, fed by Manager's polling loop and drained by Handler's WebSocket
+// endpoint, mirroring analytics.Engine's subscriber-channel pattern.
+type Bus struct {
+	mu   sync.RWMutex
+	subs map[string]map[chan aviatrix.LearnedCIDR]struct{}
+}
+
+// NewBus creates an empty Bus.
+func NewBus() *Bus {
+	return &Bus{subs: make(map[string]map[chan aviatrix.LearnedCIDR]struct{})}
+}
+
+// Subscribe registers ch to receive every LearnedCIDR published for
+// gatewayName until Unsubscribe is called with the same channel.
+func (b *Bus) Subscribe(gatewayName string, ch chan aviatrix.LearnedCIDR) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs[gatewayName] == nil {
+		b.subs[gatewayName] = make(map[chan aviatrix.LearnedCIDR]struct{})
+	}
+	b.subs[gatewayName][ch] = struct{}{}
+}
+
+// Unsubscribe removes ch from gatewayName's subscriber set.
+func (b *Bus) Unsubscribe(gatewayName string, ch chan aviatrix.LearnedCIDR) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subs[gatewayName], ch)
+	if len(b.subs[gatewayName]) == 0 {
+		delete(b.subs, gatewayName)
+	}
+}
+
+// Publish fans cidr out to every subscriber watching its GatewayName. A
+// subscriber too slow to keep up has the update dropped rather than
+// blocking Publish.
+func (b *Bus) Publish(cidr aviatrix.LearnedCIDR) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch := range b.subs[cidr.GatewayName] {
+		select {
+		case ch <- cidr:
+		default:
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package learnedcidr
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"router-sim/internal/aviatrix"
+)
+
+// upgrader upgrades the watch endpoint to a WebSocket connection, the same
+// permissive CheckOrigin cmd/server's own upgrader uses in development.
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// Handler exposes Manager over HTTP.
+type Handler struct {
+	manager *Manager
+	logger  *zap.Logger
+}
+
+// NewHandler wraps manager for HTTP use.
+func NewHandler(manager *Manager, logger *zap.Logger) *Handler {
+	return &Handler{manager: manager, logger: logger}
+}
+
+// RegisterRoutes mounts Handler's routes onto group, e.g. a router group
+// rooted at /api/v1/aviatrix.
+func (h *Handler) RegisterRoutes(group gin.IRouter) {
+	group.GET("/gateways/:name/learned-cidrs", h.List)
+	group.GET("/gateways/:name/learned-cidrs/watch", h.Watch)
+	group.POST("/gateways/:name/learned-cidrs/approve", h.Approve)
+	group.POST("/gateways/:name/learned-cidrs/reject", h.Reject)
+	group.POST("/gateways/:name/learned-cidrs/bulk-decide", h.BulkDecide)
+}
+
+// List handles GET /api/v1/aviatrix/gateways/:name/learned-cidrs. The
+// optional ?state= query param filters to one CIDRState.
+func (h *Handler) List(c *gin.Context) {
+	name := c.Param("name")
+	state := aviatrix.CIDRState(c.Query("state"))
+
+	h.manager.Watch(name)
+
+	cidrs, err := h.manager.List(c.Request.Context(), name, state)
+	if err != nil {
+		h.logger.Error("Failed to list learned CIDRs", zap.Error(err), zap.String("gw_name", name))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list learned CIDRs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"learned_cidrs": cidrs, "count": len(cidrs)})
+}
+
+type decideRequest struct {
+	CIDR   string `json:"cidr" binding:"required"`
+	Actor  string `json:"actor" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+// Approve handles POST /api/v1/aviatrix/gateways/:name/learned-cidrs/approve.
+func (h *Handler) Approve(c *gin.Context) {
+	name := c.Param("name")
+
+	var request decideRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	decided, err := h.manager.Approve(c.Request.Context(), name, request.CIDR, request.Actor, request.Reason)
+	if err != nil {
+		h.logger.Error("Failed to approve learned CIDR", zap.Error(err), zap.String("gw_name", name), zap.String("cidr", request.CIDR))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to approve learned CIDR"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"learned_cidr": decided})
+}
+
+// Reject handles POST /api/v1/aviatrix/gateways/:name/learned-cidrs/reject.
+func (h *Handler) Reject(c *gin.Context) {
+	name := c.Param("name")
+
+	var request decideRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	decided, err := h.manager.Reject(c.Request.Context(), name, request.CIDR, request.Actor, request.Reason)
+	if err != nil {
+		h.logger.Error("Failed to reject learned CIDR", zap.Error(err), zap.String("gw_name", name), zap.String("cidr", request.CIDR))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reject learned CIDR"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"learned_cidr": decided})
+}
+
+// BulkDecide handles POST /api/v1/aviatrix/gateways/:name/learned-cidrs/bulk-decide.
+func (h *Handler) BulkDecide(c *gin.Context) {
+	name := c.Param("name")
+
+	var request struct {
+		CIDRs  []string           `json:"cidrs" binding:"required"`
+		State  aviatrix.CIDRState `json:"state" binding:"required"`
+		Actor  string             `json:"actor" binding:"required"`
+		Reason string             `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	decided, err := h.manager.BulkDecide(c.Request.Context(), name, request.CIDRs, request.State, request.Actor, request.Reason)
+	if err != nil {
+		h.logger.Error("Failed to bulk-decide learned CIDRs", zap.Error(err), zap.String("gw_name", name))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to bulk-decide learned CIDRs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"learned_cidrs": decided, "count": len(decided)})
+}
+
+// Watch handles GET /api/v1/aviatrix/gateways/:name/learned-cidrs/watch,
+// upgrading to a WebSocket that pushes each newly-pending LearnedCIDR for
+// :name as Manager's polling loop discovers it.
+func (h *Handler) Watch(c *gin.Context) {
+	name := c.Param("name")
+	h.manager.Watch(name)
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("learnedcidr: WebSocket upgrade error: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	updates := make(chan aviatrix.LearnedCIDR, 64)
+	h.manager.bus.Subscribe(name, updates)
+	defer h.manager.bus.Unsubscribe(name, updates)
+
+	for cidr := range updates {
+		if err := conn.WriteJSON(cidr); err != nil {
+			h.logger.Error("learnedcidr: WebSocket write error", zap.Error(err))
+			return
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package learnedcidr
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"router-sim/internal/aviatrix"
+)
+
+// pollInterval is how often Manager's background loop re-lists each
+// watched gateway's pending CIDRs.
+const pollInterval = 10 * time.Second
+
+// Manager orchestrates the learned-CIDR approval workflow: client talks to
+// the controller itself, store keeps the audit trail of who decided what
+// and why, bus pushes newly-pending CIDRs to subscribed operators in real
+// time, and an optional policy auto-decides CIDRs the operator doesn't
+// need to see.
+type Manager struct {
+	client aviatrix.AviatrixService
+	store  Store
+	bus    *Bus
+	policy PolicyFunc
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	watched map[string]struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewManager creates a Manager and starts its background polling loop.
+// Call Watch to add gateways to poll, and Close to stop the loop.
+func NewManager(client aviatrix.AviatrixService, store Store, bus *Bus, policy PolicyFunc, logger *zap.Logger) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		client:  client,
+		store:   store,
+		bus:     bus,
+		policy:  policy,
+		logger:  logger,
+		watched: make(map[string]struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	go m.poll()
+	return m
+}
+
+// Close stops Manager's background polling loop.
+func (m *Manager) Close() {
+	m.cancel()
+}
+
+// Watch adds gatewayName to the set of gateways the polling loop checks
+// for newly-learned pending CIDRs.
+func (m *Manager) Watch(gatewayName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.watched[gatewayName] = struct{}{}
+}
+
+func (m *Manager) poll() {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	// published holds, per gateway, the pending CIDRs already pushed to
+	// the bus, so each poll publishes only the ones that are new.
+	published := make(map[string]map[string]bool)
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.pollOnce(published)
+		}
+	}
+}
+
+func (m *Manager) pollOnce(published map[string]map[string]bool) {
+	m.mu.Lock()
+	gateways := make([]string, 0, len(m.watched))
+	for name := range m.watched {
+		gateways = append(gateways, name)
+	}
+	m.mu.Unlock()
+
+	for _, name := range gateways {
+		pending, err := m.client.ListLearnedCIDRs(m.ctx, name, aviatrix.CIDRPending)
+		if err != nil {
+			m.logger.Error("learnedcidr: failed listing pending CIDRs", zap.Error(err), zap.String("gw_name", name))
+			continue
+		}
+		seen := make(map[string]bool, len(pending))
+		for _, cidr := range pending {
+			if m.autoDecide(cidr) {
+				continue
+			}
+			seen[cidr.CIDR] = true
+			if !published[name][cidr.CIDR] {
+				m.bus.Publish(cidr)
+			}
+		}
+		published[name] = seen
+	}
+}
+
+// autoDecide runs the policy hook on cidr, if one is configured, and
+// applies its Decision. It returns true if the policy decided cidr (so the
+// caller should not also publish it for manual review).
+func (m *Manager) autoDecide(cidr aviatrix.LearnedCIDR) bool {
+	if m.policy == nil {
+		return false
+	}
+
+	decision, err := m.policy(cidr)
+	if err != nil {
+		m.logger.Error("learnedcidr: policy evaluation failed", zap.Error(err), zap.String("gw_name", cidr.GatewayName), zap.String("cidr", cidr.CIDR))
+		return false
+	}
+	if decision.State != aviatrix.CIDRApproved && decision.State != aviatrix.CIDRRejected {
+		return false
+	}
+
+	if _, err := m.decide(m.ctx, cidr.GatewayName, cidr.CIDR, decision.State, decision.Actor, decision.Reason); err != nil {
+		m.logger.Error("learnedcidr: automated decision failed", zap.Error(err), zap.String("gw_name", cidr.GatewayName), zap.String("cidr", cidr.CIDR))
+		return false
+	}
+	return true
+}
+
+// List lists gatewayName's learned CIDRs, optionally filtered to
+// stateFilter (pass "" for every state).
+func (m *Manager) List(ctx context.Context, gatewayName string, stateFilter aviatrix.CIDRState) ([]aviatrix.LearnedCIDR, error) {
+	return m.client.ListLearnedCIDRs(ctx, gatewayName, stateFilter)
+}
+
+// Approve approves cidr on gatewayName, recording actor and reason in both
+// the controller and Manager's audit trail.
+func (m *Manager) Approve(ctx context.Context, gatewayName, cidr, actor, reason string) (*aviatrix.LearnedCIDR, error) {
+	return m.decide(ctx, gatewayName, cidr, aviatrix.CIDRApproved, actor, reason)
+}
+
+// Reject rejects cidr on gatewayName, recording actor and reason in both
+// the controller and Manager's audit trail.
+func (m *Manager) Reject(ctx context.Context, gatewayName, cidr, actor, reason string) (*aviatrix.LearnedCIDR, error) {
+	return m.decide(ctx, gatewayName, cidr, aviatrix.CIDRRejected, actor, reason)
+}
+
+func (m *Manager) decide(ctx context.Context, gatewayName, cidr string, state aviatrix.CIDRState, actor, reason string) (*aviatrix.LearnedCIDR, error) {
+	var (
+		decided *aviatrix.LearnedCIDR
+		err     error
+	)
+	switch state {
+	case aviatrix.CIDRApproved:
+		decided, err = m.client.ApproveLearnedCIDR(ctx, gatewayName, cidr, actor, reason)
+	case aviatrix.CIDRRejected:
+		decided, err = m.client.RejectLearnedCIDR(ctx, gatewayName, cidr, actor, reason)
+	default:
+		return nil, fmt.Errorf("learnedcidr: unsupported decision state %q", state)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.store.SaveDecision(ctx, *decided); err != nil {
+		m.logger.Error("learnedcidr: failed to persist decision", zap.Error(err), zap.String("gw_name", gatewayName), zap.String("cidr", cidr))
+	}
+	return decided, nil
+}
+
+// BulkDecide applies state to every CIDR in cidrs on gatewayName in one
+// controller call, persisting each result to the audit trail.
+func (m *Manager) BulkDecide(ctx context.Context, gatewayName string, cidrs []string, state aviatrix.CIDRState, actor, reason string) ([]aviatrix.LearnedCIDR, error) {
+	decided, err := m.client.BulkDecide(ctx, gatewayName, cidrs, state, actor, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rec := range decided {
+		if err := m.store.SaveDecision(ctx, rec); err != nil {
+			m.logger.Error("learnedcidr: failed to persist bulk decision", zap.Error(err), zap.String("gw_name", gatewayName), zap.String("cidr", rec.CIDR))
+		}
+	}
+	return decided, nil
+}
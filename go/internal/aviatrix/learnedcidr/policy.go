@@ -0,0 +1,16 @@
+package learnedcidr
+
+import "router-sim/internal/aviatrix"
+
+// Decision is what a PolicyFunc returns for one pending CIDR: the state to
+// move it to, and the actor/reason to record for the decision.
+type Decision struct {
+	State  aviatrix.CIDRState
+	Actor  string
+	Reason string
+}
+
+// PolicyFunc automates approval decisions for CIDRs Manager's polling loop
+// finds pending. Returning a Decision with State CIDRPending leaves the
+// CIDR pending for manual review instead of deciding it automatically.
+type PolicyFunc func(aviatrix.LearnedCIDR) (Decision, error)
@@ -0,0 +1,180 @@
+// Package learnedcidr implements the learned-CIDR approval workflow: a
+// gateway learns prefixes over a BGP/peering session, and when
+// EnableLearnedCIDRsApproval is set those prefixes sit pending until an
+// operator (or PolicyFunc) approves or rejects them. Manager orchestrates
+// the controller itself (via aviatrix.AviatrixService), an audit-trail
+// Store, and a Bus that pushes pending CIDRs to subscribers in real time.
+package learnedcidr
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"router-sim/internal/aviatrix"
+)
+
+// Store records every approval/rejection decision made on a learned CIDR,
+// an audit trail the controller's own API has no reason to keep.
+type Store interface {
+	// SaveDecision records rec, keyed by its GatewayName and CIDR.
+	SaveDecision(ctx context.Context, rec aviatrix.LearnedCIDR) error
+	// ListDecisions lists every recorded decision for gatewayName,
+	// optionally filtered to stateFilter (pass "" for every state).
+	ListDecisions(ctx context.Context, gatewayName string, stateFilter aviatrix.CIDRState) ([]aviatrix.LearnedCIDR, error)
+}
+
+// MemoryStore is an in-memory Store, the default when no durable audit
+// trail is configured.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	records map[string]aviatrix.LearnedCIDR
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]aviatrix.LearnedCIDR)}
+}
+
+func memKey(gatewayName, cidr string) string {
+	return gatewayName + "/" + cidr
+}
+
+// SaveDecision implements Store.
+func (m *MemoryStore) SaveDecision(ctx context.Context, rec aviatrix.LearnedCIDR) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records[memKey(rec.GatewayName, rec.CIDR)] = rec
+	return nil
+}
+
+// ListDecisions implements Store.
+func (m *MemoryStore) ListDecisions(ctx context.Context, gatewayName string, stateFilter aviatrix.CIDRState) ([]aviatrix.LearnedCIDR, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var out []aviatrix.LearnedCIDR
+	for _, rec := range m.records {
+		if rec.GatewayName != gatewayName {
+			continue
+		}
+		if stateFilter != "" && rec.State != stateFilter {
+			continue
+		}
+		out = append(out, rec)
+	}
+	return out, nil
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// SQLStore is a database/sql-backed Store, for deployments that want the
+// audit trail to outlive the process, the same shape
+// analytics.NewClickHouseClient uses for its own metrics tables.
+type SQLStore struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewSQLStore opens dsn with driverName and creates the learned_cidr_decisions
+// table if it does not already exist.
+func NewSQLStore(driverName, dsn string, logger *zap.Logger) (*SQLStore, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("learnedcidr: failed to open database connection: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("learnedcidr: failed to ping database: %w", err)
+	}
+
+	store := &SQLStore{db: db, logger: logger}
+	if err := store.initializeTables(); err != nil {
+		return nil, fmt.Errorf("learnedcidr: failed to initialize tables: %w", err)
+	}
+
+	return store, nil
+}
+
+// Close closes the underlying database connection.
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLStore) initializeTables() error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS learned_cidr_decisions (
+		gw_name      VARCHAR(255) NOT NULL,
+		cidr         VARCHAR(64)  NOT NULL,
+		learned_from VARCHAR(255),
+		state        VARCHAR(16)  NOT NULL,
+		decided_by   VARCHAR(255),
+		decided_at   TIMESTAMP,
+		reason       TEXT,
+		PRIMARY KEY (gw_name, cidr)
+	)
+	`
+	if _, err := s.db.Exec(schema); err != nil {
+		return fmt.Errorf("failed to create learned_cidr_decisions table: %w", err)
+	}
+	return nil
+}
+
+// SaveDecision implements Store, upserting rec by (gw_name, cidr).
+func (s *SQLStore) SaveDecision(ctx context.Context, rec aviatrix.LearnedCIDR) error {
+	query := `
+	INSERT INTO learned_cidr_decisions (gw_name, cidr, learned_from, state, decided_by, decided_at, reason)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (gw_name, cidr) DO UPDATE SET
+		learned_from = excluded.learned_from,
+		state        = excluded.state,
+		decided_by   = excluded.decided_by,
+		decided_at   = excluded.decided_at,
+		reason       = excluded.reason
+	`
+	_, err := s.db.ExecContext(ctx, query,
+		rec.GatewayName,
+		rec.CIDR,
+		rec.LearnedFrom,
+		rec.State,
+		rec.DecidedBy,
+		rec.DecidedAt,
+		rec.Reason,
+	)
+	return err
+}
+
+// ListDecisions implements Store.
+func (s *SQLStore) ListDecisions(ctx context.Context, gatewayName string, stateFilter aviatrix.CIDRState) ([]aviatrix.LearnedCIDR, error) {
+	query := `
+	SELECT gw_name, cidr, learned_from, state, decided_by, decided_at, reason
+	FROM learned_cidr_decisions
+	WHERE gw_name = ?
+	`
+	args := []interface{}{gatewayName}
+	if stateFilter != "" {
+		query += " AND state = ?"
+		args = append(args, stateFilter)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []aviatrix.LearnedCIDR
+	for rows.Next() {
+		var rec aviatrix.LearnedCIDR
+		if err := rows.Scan(&rec.GatewayName, &rec.CIDR, &rec.LearnedFrom, &rec.State, &rec.DecidedBy, &rec.DecidedAt, &rec.Reason); err != nil {
+			return nil, err
+		}
+		out = append(out, rec)
+	}
+	return out, rows.Err()
+}
+
+var _ Store = (*SQLStore)(nil)
@@ -0,0 +1,319 @@
+package aviatrix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+
+	routermetrics "router-sim/internal/metrics"
+)
+
+// gatewaySamples holds one poll's worth of observed state, kept around
+// after each Collect so StartOTLP's callback-driven instruments can
+// report the same numbers Prometheus just scraped without polling the
+// API a second time.
+type gatewaySamples struct {
+	metrics     []gatewayMetricSample
+	neighbors   []neighborSample
+	connections []connectionSample
+	stats       *AviatrixStats
+}
+
+type gatewayMetricSample struct {
+	gateway EnhancedGateway
+	metric  GatewayMetrics
+}
+
+type neighborSample struct {
+	gatewayID string
+	neighbor  EnhancedBGPNeighbor
+}
+
+type connectionSample struct {
+	link TopologyLink
+}
+
+var (
+	gatewayCPUUsage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aviatrix_gateway_cpu_usage",
+		Help: "CPU usage percentage reported by GetGatewayMetrics.",
+	}, []string{"gateway_id", "name", "region", "cloud"})
+
+	gatewayMemoryUsage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aviatrix_gateway_memory_usage",
+		Help: "Memory usage percentage reported by GetGatewayMetrics.",
+	}, []string{"gateway_id", "name", "region", "cloud"})
+
+	gatewayPacketsInTotal = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aviatrix_gateway_packets_in_total",
+		Help: "Packets received, as last reported by GetGatewayMetrics.",
+	}, []string{"gateway_id", "name", "region", "cloud"})
+
+	gatewayPacketsOutTotal = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aviatrix_gateway_packets_out_total",
+		Help: "Packets sent, as last reported by GetGatewayMetrics.",
+	}, []string{"gateway_id", "name", "region", "cloud"})
+
+	gatewayLatency = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aviatrix_gateway_latency_ms",
+		Help: "Latency in milliseconds reported by GetGatewayMetrics.",
+	}, []string{"gateway_id", "name", "region", "cloud"})
+
+	gatewayActiveTunnels = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aviatrix_gateway_active_tunnels",
+		Help: "Active tunnel count reported by GetGatewayMetrics.",
+	}, []string{"gateway_id", "name", "region", "cloud"})
+
+	bgpNeighborRoutesReceived = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aviatrix_bgp_neighbor_routes_received",
+		Help: "Routes received from a BGP neighbor.",
+	}, []string{"gateway_id", "neighbor_ip", "asn", "status"})
+
+	bgpNeighborRoutesAdvertised = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aviatrix_bgp_neighbor_routes_advertised",
+		Help: "Routes advertised to a BGP neighbor.",
+	}, []string{"gateway_id", "neighbor_ip", "asn", "status"})
+
+	bgpNeighborUptime = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aviatrix_bgp_neighbor_uptime_seconds",
+		Help: "Seconds a BGP neighbor session has been established.",
+	}, []string{"gateway_id", "neighbor_ip", "asn", "status"})
+
+	connectionBandwidth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aviatrix_connection_bandwidth",
+		Help: "Bandwidth of a connection between two topology nodes.",
+	}, []string{"source", "destination", "type", "status"})
+
+	connectionLatency = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aviatrix_connection_latency_ms",
+		Help: "Latency in milliseconds of a connection between two topology nodes.",
+	}, []string{"source", "destination", "type", "status"})
+
+	statsGauges = map[string]*prometheus.GaugeVec{
+		"total_gateways":     gaugeVec("aviatrix_stats_total_gateways", "Total gateways, from GetStats."),
+		"active_gateways":    gaugeVec("aviatrix_stats_active_gateways", "Active gateways, from GetStats."),
+		"total_connections":  gaugeVec("aviatrix_stats_total_connections", "Total connections, from GetStats."),
+		"active_connections": gaugeVec("aviatrix_stats_active_connections", "Active connections, from GetStats."),
+		"total_routes":       gaugeVec("aviatrix_stats_total_routes", "Total routes, from GetStats."),
+		"bgp_neighbors":      gaugeVec("aviatrix_stats_bgp_neighbors", "Total BGP neighbors, from GetStats."),
+		"established_bgp":    gaugeVec("aviatrix_stats_established_bgp", "Established BGP sessions, from GetStats."),
+		"active_vpn_users":   gaugeVec("aviatrix_stats_active_vpn_users", "Active VPN users, from GetStats."),
+	}
+)
+
+func gaugeVec(name, help string) *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, nil)
+}
+
+func init() {
+	prometheus.MustRegister(
+		gatewayCPUUsage, gatewayMemoryUsage, gatewayPacketsInTotal, gatewayPacketsOutTotal,
+		gatewayLatency, gatewayActiveTunnels,
+		bgpNeighborRoutesReceived, bgpNeighborRoutesAdvertised, bgpNeighborUptime,
+		connectionBandwidth, connectionLatency,
+	)
+	for _, g := range statsGauges {
+		prometheus.MustRegister(g)
+	}
+}
+
+// MetricsExporter periodically polls one EnhancedClient for gateway
+// metrics, BGP neighbor state, connection topology, and aggregate stats,
+// and exposes all of it as Prometheus metrics (PrometheusHandler) and a
+// subset of it as an OTLP push (StartOTLP) — turning GatewayMetrics' pull-per-call
+// JSON into something a standard observability stack can scrape or
+// receive without every caller polling the API itself.
+type MetricsExporter struct {
+	client   *EnhancedClient
+	duration string
+
+	mu   sync.RWMutex
+	last gatewaySamples
+
+	// lastPacketTotal is each gateway's most recently observed
+	// PacketsIn+PacketsOut, so collect can add the delta to the shared
+	// router_packets_processed_total counter instead of double-counting
+	// the cumulative total on every poll.
+	lastPacketTotal map[string]int64
+}
+
+// NewMetricsExporter builds a MetricsExporter polling client. duration is
+// passed through to GetGatewayMetrics unchanged (e.g. "5m", "1h").
+func NewMetricsExporter(client *EnhancedClient, duration string) *MetricsExporter {
+	return &MetricsExporter{client: client, duration: duration, lastPacketTotal: make(map[string]int64)}
+}
+
+// Run polls the client every interval, updating both the Prometheus
+// gauges and the cached samples StartOTLP's callbacks read, until ctx is
+// canceled.
+func (m *MetricsExporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	m.collect(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.collect(ctx)
+		}
+	}
+}
+
+func (m *MetricsExporter) collect(ctx context.Context) {
+	gateways, err := m.client.ListGateways(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	samples := gatewaySamples{}
+
+	for _, gw := range gateways {
+		labels := prometheus.Labels{
+			"gateway_id": gw.ID, "name": gw.Name, "region": gw.Region, "cloud": gw.Cloud,
+		}
+
+		metrics, err := m.client.GetGatewayMetrics(ctx, gw.ID, m.duration)
+		if err == nil && len(metrics) > 0 {
+			latest := metrics[len(metrics)-1]
+			gatewayCPUUsage.With(labels).Set(latest.CPUUsage)
+			gatewayMemoryUsage.With(labels).Set(latest.MemoryUsage)
+			gatewayPacketsInTotal.With(labels).Set(float64(latest.PacketsIn))
+			gatewayPacketsOutTotal.With(labels).Set(float64(latest.PacketsOut))
+			gatewayLatency.With(labels).Set(latest.Latency)
+			gatewayActiveTunnels.With(labels).Set(float64(latest.ActiveTunnels))
+			samples.metrics = append(samples.metrics, gatewayMetricSample{gateway: gw, metric: latest})
+
+			// GetGatewayMetrics reports cumulative totals, so only the
+			// delta since the last poll counts toward the shared
+			// router_packets_processed_total counter.
+			total := latest.PacketsIn + latest.PacketsOut
+			m.mu.Lock()
+			if prev, ok := m.lastPacketTotal[gw.ID]; ok && total > prev {
+				routermetrics.PacketsProcessedTotal.Add(float64(total - prev))
+			}
+			m.lastPacketTotal[gw.ID] = total
+			m.mu.Unlock()
+		}
+
+		neighbors, err := m.client.ListBGPNeighbors(ctx, gw.ID)
+		if err != nil {
+			continue
+		}
+		for _, n := range neighbors {
+			nLabels := prometheus.Labels{
+				"gateway_id": gw.ID, "neighbor_ip": n.IP, "asn": fmt.Sprint(n.ASN), "status": n.Status,
+			}
+			bgpNeighborRoutesReceived.With(nLabels).Set(float64(n.RoutesReceived))
+			bgpNeighborRoutesAdvertised.With(nLabels).Set(float64(n.RoutesAdvertised))
+			bgpNeighborUptime.With(nLabels).Set(float64(n.Uptime))
+			samples.neighbors = append(samples.neighbors, neighborSample{gatewayID: gw.ID, neighbor: n})
+		}
+	}
+
+	// Connections have no list endpoint of their own; topology links are
+	// the closest EnhancedClient gets to a Connection-shaped view of
+	// what's wired to what.
+	if topology, err := m.client.GetNetworkTopology(ctx); err == nil {
+		for _, link := range topology.Links {
+			cLabels := prometheus.Labels{
+				"source": link.Source, "destination": link.Target, "type": link.Type, "status": link.Status,
+			}
+			connectionBandwidth.With(cLabels).Set(float64(link.Bandwidth))
+			connectionLatency.With(cLabels).Set(float64(link.Latency))
+			samples.connections = append(samples.connections, connectionSample{link: link})
+		}
+	}
+
+	if stats, err := m.client.GetStats(ctx); err == nil {
+		statsGauges["total_gateways"].With(nil).Set(float64(stats.TotalGateways))
+		statsGauges["active_gateways"].With(nil).Set(float64(stats.ActiveGateways))
+		statsGauges["total_connections"].With(nil).Set(float64(stats.TotalConnections))
+		statsGauges["active_connections"].With(nil).Set(float64(stats.ActiveConnections))
+		statsGauges["total_routes"].With(nil).Set(float64(stats.TotalRoutes))
+		statsGauges["bgp_neighbors"].With(nil).Set(float64(stats.BGPNeighbors))
+		statsGauges["established_bgp"].With(nil).Set(float64(stats.EstablishedBGP))
+		statsGauges["active_vpn_users"].With(nil).Set(float64(stats.ActiveVPNUsers))
+		samples.stats = stats
+	}
+
+	m.mu.Lock()
+	m.last = samples
+	m.mu.Unlock()
+}
+
+// PrometheusHandler returns an http.Handler serving every metric this
+// exporter and the rest of the process have registered with the default
+// Prometheus registry, ready to mount at e.g. /metrics.
+func (m *MetricsExporter) PrometheusHandler() http.Handler {
+	return promhttp.Handler()
+}
+
+// StartOTLP starts pushing to an OTLP/HTTP collector at endpoint every
+// pushInterval, via observable instruments that read m's cached samples
+// instead of polling the API again. Only three of the samples are
+// pushed: each gateway's CPU usage and packets in, and each BGP
+// neighbor's routes received; the other gauges PrometheusHandler serves
+// are not. The returned func shuts the exporter down; call it once done.
+func (m *MetricsExporter) StartOTLP(ctx context.Context, endpoint string, pushInterval time.Duration) (func(context.Context) error, error) {
+	exporter, err := otlpmetrichttp.New(ctx, otlpmetrichttp.WithEndpoint(endpoint), otlpmetrichttp.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP metric exporter: %w", err)
+	}
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(pushInterval))),
+	)
+	meter := provider.Meter("router-sim/aviatrix")
+
+	cpuGauge, err := meter.Float64ObservableGauge("aviatrix.gateway.cpu_usage")
+	if err != nil {
+		return nil, err
+	}
+	packetsInGauge, err := meter.Float64ObservableGauge("aviatrix.gateway.packets_in")
+	if err != nil {
+		return nil, err
+	}
+	routesReceivedGauge, err := meter.Float64ObservableGauge("aviatrix.bgp_neighbor.routes_received")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+
+		for _, s := range m.last.metrics {
+			attrs := metric.WithAttributes(
+				attribute.String("gateway_id", s.gateway.ID),
+				attribute.String("region", s.gateway.Region),
+				attribute.String("cloud", s.gateway.Cloud),
+			)
+			o.ObserveFloat64(cpuGauge, s.metric.CPUUsage, attrs)
+			o.ObserveFloat64(packetsInGauge, float64(s.metric.PacketsIn), attrs)
+		}
+		for _, s := range m.last.neighbors {
+			o.ObserveFloat64(routesReceivedGauge, float64(s.neighbor.RoutesReceived), metric.WithAttributes(
+				attribute.String("gateway_id", s.gatewayID),
+				attribute.String("neighbor_ip", s.neighbor.IP),
+				attribute.Int("asn", s.neighbor.ASN),
+			))
+		}
+		return nil
+	}, cpuGauge, packetsInGauge, routesReceivedGauge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register OTLP metric callback: %w", err)
+	}
+
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,101 @@
+package aviatrix
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// PeeringToken is the opaque handshake payload GeneratePeeringToken issues
+// and EstablishPeering consumes, the same out-of-band exchange Consul
+// cluster peering uses so two controllers can negotiate a
+// TransitGatewayPeering without either one holding the other's
+// credentials. Sequence increments on every token a controller issues, so
+// a replayed or stale token can be told apart from the latest one.
+type PeeringToken struct {
+	Version       int    `json:"version"`
+	Endpoint      string `json:"endpoint"`
+	LocalASN      string `json:"local_asn"`
+	LocalPublicIP string `json:"local_public_ip"`
+	PSK           string `json:"psk"`
+	Sequence      uint64 `json:"sequence"`
+}
+
+// peeringTokenEnvelope is PeeringToken's signed, base64-encoded wire form:
+// Payload is the base64-encoded JSON PeeringToken, Sig its HMAC-SHA256
+// under the issuing controller's peering secret.
+type peeringTokenEnvelope struct {
+	Payload string `json:"payload"`
+	Sig     string `json:"sig"`
+}
+
+// signPeeringToken encodes pt and signs it with secret, returning the
+// opaque token string GeneratePeeringToken hands back to its caller.
+func signPeeringToken(pt PeeringToken, secret string) (string, error) {
+	payload, err := json.Marshal(pt)
+	if err != nil {
+		return "", fmt.Errorf("aviatrix: encode peering token: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	envelope := peeringTokenEnvelope{
+		Payload: base64.StdEncoding.EncodeToString(payload),
+		Sig:     base64.StdEncoding.EncodeToString(mac.Sum(nil)),
+	}
+	raw, err := json.Marshal(envelope)
+	if err != nil {
+		return "", fmt.Errorf("aviatrix: encode peering token envelope: %w", err)
+	}
+	return base64.URLEncoding.EncodeToString(raw), nil
+}
+
+// verifyPeeringToken decodes token and checks its signature against
+// secret, returning an error if the token is malformed or has been
+// tampered with.
+func verifyPeeringToken(token, secret string) (*PeeringToken, error) {
+	raw, err := base64.URLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("aviatrix: malformed peering token: %w", err)
+	}
+
+	var envelope peeringTokenEnvelope
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, fmt.Errorf("aviatrix: malformed peering token envelope: %w", err)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("aviatrix: malformed peering token payload: %w", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(envelope.Sig)
+	if err != nil {
+		return nil, fmt.Errorf("aviatrix: malformed peering token signature: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return nil, fmt.Errorf("aviatrix: peering token signature is invalid")
+	}
+
+	var pt PeeringToken
+	if err := json.Unmarshal(payload, &pt); err != nil {
+		return nil, fmt.Errorf("aviatrix: decode peering token: %w", err)
+	}
+	return &pt, nil
+}
+
+// newPeeringPSK generates a fresh pre-shared key for one peering
+// handshake.
+func newPeeringPSK() (string, error) {
+	psk := make([]byte, 24)
+	if _, err := rand.Read(psk); err != nil {
+		return "", fmt.Errorf("aviatrix: generate peering PSK: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(psk), nil
+}
@@ -0,0 +1,333 @@
+package aviatrix
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// NetworkPlan is the desired state of a network: the full set of
+// TransitGateways, SpokeGateways, VPN attachments, BGP peerings, and
+// Routes that should exist once Apply finishes. Unlike DeployTransitNetwork
+// and DeploySpokeNetwork's free-form config map, every field is typed so
+// Plan can diff it against the cache instead of firing the request and
+// hoping.
+type NetworkPlan struct {
+	TransitGateways []TransitGateway
+	SpokeGateways   []SpokeGateway
+	VPNGateways     []VPNGateway
+	BGPNeighbors    []BGPNeighbor
+	Routes          []Route
+}
+
+// ResourceAction is the change a PlanItem represents.
+type ResourceAction string
+
+const (
+	ActionCreate ResourceAction = "create"
+	ActionUpdate ResourceAction = "update"
+	ActionDelete ResourceAction = "delete"
+)
+
+// PlanItem is one resource Apply will create, update, or delete. Current is
+// nil for a create, Desired is nil for a delete; CurrentHash/DesiredHash are
+// the hashstructure hashes Plan compared to decide Action.
+type PlanItem struct {
+	Kind        ResourceKind
+	ID          string
+	Action      ResourceAction
+	Current     interface{}
+	Desired     interface{}
+	CurrentHash uint64
+	DesiredHash uint64
+}
+
+// PlanDiff is the full set of changes Plan computed between a NetworkPlan
+// and the cache's current state, in no particular order; Apply imposes the
+// dependency ordering when it executes them.
+type PlanDiff struct {
+	Items []PlanItem
+}
+
+// planOrder is the dependency order Apply creates and updates in: a transit
+// gateway before the spokes attached to it, spokes before the VPN
+// attachments and BGP peerings that reference them, and routes last since
+// they can target any of the above.
+var planOrder = []ResourceKind{
+	KindTransitGateway,
+	KindSpokeGateway,
+	KindVPNGateway,
+	KindBGPNeighbor,
+	KindRoute,
+}
+
+// Plan fetches current state from cache and diffs it against desired,
+// returning the Create/Update/Delete PlanDiff Apply would need to
+// reconcile the two. It does not itself change anything.
+func (c *Client) Plan(ctx context.Context, cache *Cache, desired NetworkPlan) (*PlanDiff, error) {
+	diff := &PlanDiff{}
+
+	desiredByKind := map[ResourceKind][]interface{}{
+		KindTransitGateway: toInterfaces(desired.TransitGateways),
+		KindSpokeGateway:   toInterfaces(desired.SpokeGateways),
+		KindVPNGateway:     toInterfaces(desired.VPNGateways),
+		KindBGPNeighbor:    toInterfaces(desired.BGPNeighbors),
+		KindRoute:          toInterfaces(desired.Routes),
+	}
+
+	for _, kind := range planOrder {
+		store, ok := cache.stores[kind]
+		if !ok {
+			return nil, fmt.Errorf("aviatrix: no cache store for kind %s", kind)
+		}
+
+		current := make(map[string]interface{})
+		for _, obj := range store.list() {
+			current[idOf(obj)] = obj
+		}
+
+		seen := make(map[string]struct{}, len(desiredByKind[kind]))
+		for _, obj := range desiredByKind[kind] {
+			id := idOf(obj)
+			seen[id] = struct{}{}
+
+			existing, ok := current[id]
+			if !ok {
+				diff.Items = append(diff.Items, PlanItem{
+					Kind: kind, ID: id, Action: ActionCreate,
+					Desired: obj, DesiredHash: hashOf(obj),
+				})
+				continue
+			}
+
+			currentHash, desiredHash := hashOf(existing), hashOf(obj)
+			if currentHash != desiredHash {
+				diff.Items = append(diff.Items, PlanItem{
+					Kind: kind, ID: id, Action: ActionUpdate,
+					Current: existing, Desired: obj,
+					CurrentHash: currentHash, DesiredHash: desiredHash,
+				})
+			}
+		}
+
+		for id, obj := range current {
+			if _, ok := seen[id]; !ok {
+				diff.Items = append(diff.Items, PlanItem{
+					Kind: kind, ID: id, Action: ActionDelete,
+					Current: obj, CurrentHash: hashOf(obj),
+				})
+			}
+		}
+	}
+
+	return diff, nil
+}
+
+func toInterfaces[T any](items []T) []interface{} {
+	out := make([]interface{}, len(items))
+	for i, item := range items {
+		out[i] = item
+	}
+	return out
+}
+
+// ApplyOptions tunes how Apply executes a PlanDiff.
+type ApplyOptions struct {
+	// DryRun reports every item's events as if applied, without calling
+	// the API, useful for previewing a plan through the same streaming
+	// path a real Apply would use.
+	DryRun bool
+}
+
+// ApplyStatus is an ApplyEvent's phase within its item's execution.
+type ApplyStatus string
+
+const (
+	ApplyStarted    ApplyStatus = "started"
+	ApplySucceeded  ApplyStatus = "succeeded"
+	ApplyFailed     ApplyStatus = "failed"
+	ApplyRolledBack ApplyStatus = "rolled_back"
+)
+
+// ApplyEvent reports one PlanItem's progress as Apply works through a
+// PlanDiff, the unit RegisterTrafficRoutes-style SSE endpoints would
+// stream to a caller watching a reconciliation run.
+type ApplyEvent struct {
+	Kind   ResourceKind
+	ID     string
+	Action ResourceAction
+	Status ApplyStatus
+	Err    error
+	Time   time.Time
+}
+
+// Apply executes diff's items in dependency order — creates and updates
+// from transit gateways down to routes, then deletes in the reverse order
+// — streaming one ApplyEvent per item per phase on the returned channel,
+// which is closed when the run finishes. If an item fails, Apply stops
+// applying further items and rolls back every create/update already
+// applied during this call (deleting what it created, restoring what it
+// updated) before closing the channel; already-applied deletes are not
+// rolled back since recreating a deleted resource isn't guaranteed to
+// reproduce it. Retries for transient failures happen underneath, in the
+// retrying http.RoundTripper NewClient installs; Apply itself does not
+// retry an item, and its create requests carry no idempotency key.
+func (c *Client) Apply(ctx context.Context, diff *PlanDiff, opts ApplyOptions) <-chan ApplyEvent {
+	events := make(chan ApplyEvent, 16)
+
+	go func() {
+		defer close(events)
+
+		byKind := make(map[ResourceKind][]PlanItem)
+		for _, item := range diff.Items {
+			byKind[item.Kind] = append(byKind[item.Kind], item)
+		}
+
+		var applied []PlanItem
+
+		emit := func(item PlanItem, status ApplyStatus, err error) {
+			events <- ApplyEvent{Kind: item.Kind, ID: item.ID, Action: item.Action, Status: status, Err: err, Time: time.Now()}
+		}
+
+		// Phase 1: creates and updates, transit gateways first.
+		for _, kind := range planOrder {
+			for _, item := range byKind[kind] {
+				if item.Action == ActionDelete {
+					continue
+				}
+				if ctx.Err() != nil {
+					emit(item, ApplyFailed, ctx.Err())
+					c.rollback(ctx, applied, events)
+					return
+				}
+
+				emit(item, ApplyStarted, nil)
+				if err := c.applyCreateOrUpdate(ctx, item, opts); err != nil {
+					emit(item, ApplyFailed, err)
+					c.rollback(ctx, applied, events)
+					return
+				}
+				applied = append(applied, item)
+				emit(item, ApplySucceeded, nil)
+			}
+		}
+
+		// Phase 2: deletes, routes first (the reverse of planOrder).
+		for i := len(planOrder) - 1; i >= 0; i-- {
+			for _, item := range byKind[planOrder[i]] {
+				if item.Action != ActionDelete {
+					continue
+				}
+				if ctx.Err() != nil {
+					emit(item, ApplyFailed, ctx.Err())
+					c.rollback(ctx, applied, events)
+					return
+				}
+
+				emit(item, ApplyStarted, nil)
+				if err := c.applyDelete(ctx, item, opts); err != nil {
+					emit(item, ApplyFailed, err)
+					c.rollback(ctx, applied, events)
+					return
+				}
+				emit(item, ApplySucceeded, nil)
+			}
+		}
+	}()
+
+	return events
+}
+
+// rollback undoes applied in reverse order: a create is deleted, an update
+// is reverted to its Current value. It runs best-effort, emitting a
+// RolledBack or Failed event per item rather than stopping at the first
+// error, so one stuck item doesn't strand the rest of the rollback.
+func (c *Client) rollback(ctx context.Context, applied []PlanItem, events chan<- ApplyEvent) {
+	for i := len(applied) - 1; i >= 0; i-- {
+		item := applied[i]
+
+		var err error
+		switch item.Action {
+		case ActionCreate:
+			err = c.applyDelete(ctx, PlanItem{Kind: item.Kind, ID: item.ID}, ApplyOptions{})
+		case ActionUpdate:
+			err = c.applyCreateOrUpdate(ctx, PlanItem{Kind: item.Kind, ID: item.ID, Action: ActionUpdate, Desired: item.Current}, ApplyOptions{})
+		}
+
+		status := ApplyRolledBack
+		if err != nil {
+			status = ApplyFailed
+		}
+		events <- ApplyEvent{Kind: item.Kind, ID: item.ID, Action: item.Action, Status: status, Err: err, Time: time.Now()}
+	}
+}
+
+func (c *Client) applyCreateOrUpdate(ctx context.Context, item PlanItem, opts ApplyOptions) error {
+	if opts.DryRun {
+		return nil
+	}
+
+	create := item.Action == ActionCreate
+	switch obj := item.Desired.(type) {
+	case TransitGateway:
+		if create {
+			_, err := c.CreateTransitGateway(ctx, &obj)
+			return err
+		}
+		_, err := c.UpdateTransitGateway(ctx, &obj)
+		return err
+	case SpokeGateway:
+		if create {
+			_, err := c.CreateSpokeGateway(ctx, &obj)
+			return err
+		}
+		_, err := c.UpdateSpokeGateway(ctx, &obj)
+		return err
+	case VPNGateway:
+		if create {
+			_, err := c.CreateVPNGateway(ctx, &obj)
+			return err
+		}
+		_, err := c.UpdateVPNGateway(ctx, &obj)
+		return err
+	case BGPNeighbor:
+		if create {
+			_, err := c.CreateBGPNeighbor(ctx, &obj)
+			return err
+		}
+		_, err := c.UpdateBGPNeighbor(ctx, &obj)
+		return err
+	case Route:
+		if create {
+			_, err := c.CreateRoute(ctx, &obj)
+			return err
+		}
+		_, err := c.UpdateRoute(ctx, &obj)
+		return err
+	default:
+		return fmt.Errorf("aviatrix: unsupported resource kind %s for %s", item.Kind, item.Action)
+	}
+}
+
+func (c *Client) applyDelete(ctx context.Context, item PlanItem, opts ApplyOptions) error {
+	if opts.DryRun {
+		return nil
+	}
+
+	var err error
+	switch item.Kind {
+	case KindTransitGateway:
+		_, err = c.DeleteTransitGateway(ctx, item.ID)
+	case KindSpokeGateway:
+		_, err = c.DeleteSpokeGateway(ctx, item.ID)
+	case KindVPNGateway:
+		_, err = c.DeleteVPNGateway(ctx, item.ID)
+	case KindBGPNeighbor:
+		_, err = c.DeleteBGPNeighbor(ctx, item.ID)
+	case KindRoute:
+		_, err = c.DeleteRoute(ctx, item.ID)
+	default:
+		err = fmt.Errorf("aviatrix: unsupported resource kind %s for delete", item.Kind)
+	}
+	return err
+}
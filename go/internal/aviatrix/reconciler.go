@@ -0,0 +1,296 @@
+package aviatrix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestEntry is one object in a desired-state manifest: a typed
+// Aviatrix resource tagged with the kind it decodes as and the owner it
+// should be recorded against (e.g. a GitOps pipeline's repo/path), as in:
+//
+//	- kind: transit_gateway
+//	  owner: team-networking/transit.yaml
+//	  spec: {id: tgw-1, name: transit-us-east-1, ...}
+//
+// Connection and RoutingPolicy are not valid entry kinds here: Client has
+// no List/Update methods for either, so there is nothing for Plan to
+// diff them against, and they are left out of this reconciler's manifest
+// scope rather than silently accepted and never actually reconciled.
+type ManifestEntry struct {
+	Kind  ResourceKind           `yaml:"kind" json:"kind"`
+	Owner string                 `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Spec  map[string]interface{} `yaml:"spec" json:"spec"`
+}
+
+// ParseManifest decodes a YAML or JSON list of ManifestEntry (JSON
+// parses the same way since it's a subset of YAML) into the NetworkPlan
+// Reconciler.SetManifest reconciles toward, plus an ownership map keyed
+// by "<kind>/<id>" recording each object's Owner.
+func ParseManifest(data []byte) (NetworkPlan, map[string]string, error) {
+	var entries []ManifestEntry
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return NetworkPlan{}, nil, fmt.Errorf("aviatrix: parsing manifest: %w", err)
+	}
+
+	var plan NetworkPlan
+	owners := make(map[string]string, len(entries))
+
+	for _, e := range entries {
+		specBytes, err := json.Marshal(e.Spec)
+		if err != nil {
+			return NetworkPlan{}, nil, fmt.Errorf("aviatrix: re-encoding %s spec: %w", e.Kind, err)
+		}
+
+		var id string
+		switch e.Kind {
+		case KindTransitGateway:
+			var gw TransitGateway
+			if err := json.Unmarshal(specBytes, &gw); err != nil {
+				return NetworkPlan{}, nil, fmt.Errorf("aviatrix: decoding transit gateway: %w", err)
+			}
+			plan.TransitGateways = append(plan.TransitGateways, gw)
+			id = gw.ID
+		case KindSpokeGateway:
+			var gw SpokeGateway
+			if err := json.Unmarshal(specBytes, &gw); err != nil {
+				return NetworkPlan{}, nil, fmt.Errorf("aviatrix: decoding spoke gateway: %w", err)
+			}
+			plan.SpokeGateways = append(plan.SpokeGateways, gw)
+			id = gw.ID
+		case KindVPNGateway:
+			var gw VPNGateway
+			if err := json.Unmarshal(specBytes, &gw); err != nil {
+				return NetworkPlan{}, nil, fmt.Errorf("aviatrix: decoding VPN gateway: %w", err)
+			}
+			plan.VPNGateways = append(plan.VPNGateways, gw)
+			id = gw.ID
+		case KindBGPNeighbor:
+			var n BGPNeighbor
+			if err := json.Unmarshal(specBytes, &n); err != nil {
+				return NetworkPlan{}, nil, fmt.Errorf("aviatrix: decoding BGP neighbor: %w", err)
+			}
+			plan.BGPNeighbors = append(plan.BGPNeighbors, n)
+			id = n.ID
+		case KindRoute:
+			var rt Route
+			if err := json.Unmarshal(specBytes, &rt); err != nil {
+				return NetworkPlan{}, nil, fmt.Errorf("aviatrix: decoding route: %w", err)
+			}
+			plan.Routes = append(plan.Routes, rt)
+			id = rt.ID
+		default:
+			return NetworkPlan{}, nil, fmt.Errorf("aviatrix: unsupported manifest kind %q", e.Kind)
+		}
+
+		owners[string(e.Kind)+"/"+id] = e.Owner
+	}
+
+	return plan, owners, nil
+}
+
+// reconcileBackoff tracks one resource's exponential backoff after a
+// failed apply, keyed by "<kind>/<id>".
+type reconcileBackoff struct {
+	policy  backoff.BackOff
+	retryAt time.Time
+}
+
+// Reconciler drives Client's live topology toward a desired NetworkPlan
+// on a resync loop, turning the lower-level Plan/Apply primitives into a
+// GitOps-style operator: SetDesired/SetManifest push a new manifest, and
+// Run computes a PlanDiff against cache every resyncPeriod (or as soon
+// as a push wakes it, whichever is sooner) and applies it — the same
+// throttle-then-reconcile shape Traefik's Kubernetes Gateway provider
+// uses. A resource that fails to apply backs off exponentially and is
+// skipped on subsequent reconciles until its backoff elapses, so one
+// broken object doesn't starve the rest of the manifest.
+type Reconciler struct {
+	client *Client
+	cache  *Cache
+	opts   ApplyOptions
+
+	mu      sync.Mutex
+	desired NetworkPlan
+	owners  map[string]string
+	queued  chan struct{}
+
+	backoffMu sync.Mutex
+	backoff   map[string]*reconcileBackoff
+}
+
+// NewReconciler builds a Reconciler that diffs against cache and applies
+// through client. opts.DryRun, if set, makes every reconcile a no-op
+// that still streams the ApplyEvents a real run would have produced.
+func NewReconciler(client *Client, cache *Cache, opts ApplyOptions) *Reconciler {
+	return &Reconciler{
+		client:  client,
+		cache:   cache,
+		opts:    opts,
+		queued:  make(chan struct{}, 1),
+		backoff: make(map[string]*reconcileBackoff),
+	}
+}
+
+// SetDesired replaces the manifest Run reconciles toward and wakes Run's
+// loop immediately rather than waiting for the next resyncPeriod tick. A
+// second SetDesired before Run wakes coalesces into the same pending
+// reconcile instead of queuing a duplicate. Ownership accumulates across
+// calls: a key any earlier manifest owned stays owned, so a resource
+// dropped from the manifest is still deleted by Run.
+func (r *Reconciler) SetDesired(desired NetworkPlan, owners map[string]string) {
+	r.mu.Lock()
+	merged := make(map[string]string, len(r.owners)+len(owners))
+	for key, owner := range r.owners {
+		merged[key] = owner
+	}
+	for key, owner := range owners {
+		merged[key] = owner
+	}
+	r.desired = desired
+	r.owners = merged
+	r.mu.Unlock()
+
+	select {
+	case r.queued <- struct{}{}:
+	default:
+	}
+}
+
+// SetManifest parses data with ParseManifest and pushes the result
+// through SetDesired, the entry point for a GitOps pipeline that stores
+// its desired state as a YAML or JSON file.
+func (r *Reconciler) SetManifest(data []byte) error {
+	plan, owners, err := ParseManifest(data)
+	if err != nil {
+		return err
+	}
+	r.SetDesired(plan, owners)
+	return nil
+}
+
+// Preview computes the PlanDiff Run would apply next without issuing any
+// Create/Update/Delete call — the dry-run entry point for a pipeline
+// that wants to show a plan before, or instead of, applying it.
+func (r *Reconciler) Preview(ctx context.Context) (*PlanDiff, error) {
+	r.mu.Lock()
+	desired, owners := r.desired, r.owners
+	r.mu.Unlock()
+
+	diff, err := r.client.Plan(ctx, r.cache, desired)
+	if err != nil {
+		return nil, err
+	}
+	ready := &PlanDiff{}
+	for _, item := range diff.Items {
+		key := string(item.Kind) + "/" + item.ID
+		if item.Action == ActionDelete {
+			if _, owned := owners[key]; !owned {
+				continue
+			}
+		}
+		if r.backedOff(key) {
+			continue
+		}
+		ready.Items = append(ready.Items, item)
+	}
+	return ready, nil
+}
+
+// Run reconciles the live topology toward the last manifest SetDesired
+// or SetManifest pushed, once immediately and then every time a push
+// wakes it or resyncPeriod elapses, until ctx is canceled.
+func (r *Reconciler) Run(ctx context.Context, resyncPeriod time.Duration) {
+	ticker := time.NewTicker(resyncPeriod)
+	defer ticker.Stop()
+
+	r.reconcileOnce(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.reconcileOnce(ctx)
+		case <-r.queued:
+			r.reconcileOnce(ctx)
+		}
+	}
+}
+
+func (r *Reconciler) reconcileOnce(ctx context.Context) {
+	r.mu.Lock()
+	desired, owners := r.desired, r.owners
+	r.mu.Unlock()
+
+	diff, err := r.client.Plan(ctx, r.cache, desired)
+	if err != nil {
+		return
+	}
+
+	ready := &PlanDiff{}
+	for _, item := range diff.Items {
+		key := string(item.Kind) + "/" + item.ID
+
+		// A delete for a resource this manifest never owned is left
+		// alone: it was created outside this reconciler and isn't ours
+		// to remove just because it's absent from the current manifest.
+		if item.Action == ActionDelete {
+			if _, owned := owners[key]; !owned {
+				continue
+			}
+		}
+		if r.backedOff(key) {
+			continue
+		}
+		ready.Items = append(ready.Items, item)
+	}
+	if len(ready.Items) == 0 {
+		return
+	}
+
+	for event := range r.client.Apply(ctx, ready, r.opts) {
+		key := string(event.Kind) + "/" + event.ID
+		switch event.Status {
+		case ApplySucceeded:
+			r.recordSuccess(key)
+		case ApplyFailed:
+			r.recordFailure(key)
+		}
+	}
+}
+
+func (r *Reconciler) backedOff(key string) bool {
+	r.backoffMu.Lock()
+	defer r.backoffMu.Unlock()
+
+	b, ok := r.backoff[key]
+	if !ok {
+		return false
+	}
+	return time.Now().Before(b.retryAt)
+}
+
+func (r *Reconciler) recordFailure(key string) {
+	r.backoffMu.Lock()
+	defer r.backoffMu.Unlock()
+
+	b, ok := r.backoff[key]
+	if !ok {
+		b = &reconcileBackoff{policy: backoff.NewExponentialBackOff()}
+		r.backoff[key] = b
+	}
+	b.retryAt = time.Now().Add(b.policy.NextBackOff())
+}
+
+func (r *Reconciler) recordSuccess(key string) {
+	r.backoffMu.Lock()
+	defer r.backoffMu.Unlock()
+	delete(r.backoff, key)
+}
@@ -0,0 +1,154 @@
+package aviatrix
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+func TestParseManifestDecodesEveryKind(t *testing.T) {
+	manifest := []byte(`
+- kind: transit_gateway
+  owner: team-networking/transit.yaml
+  spec: {id: tgw-1, name: transit-us-east-1}
+- kind: spoke_gateway
+  spec: {id: spoke-1, name: spoke-us-east-1}
+- kind: vpn_gateway
+  spec: {id: vpn-1, name: vpn-us-east-1}
+- kind: bgp_neighbor
+  spec: {id: bgp-1, neighbor_ip: 10.0.0.1}
+- kind: route
+  spec: {id: route-1, destination: 10.1.0.0/16}
+`)
+
+	plan, owners, err := ParseManifest(manifest)
+	if err != nil {
+		t.Fatalf("ParseManifest: %v", err)
+	}
+
+	if len(plan.TransitGateways) != 1 || plan.TransitGateways[0].ID != "tgw-1" {
+		t.Fatalf("TransitGateways = %+v, want [tgw-1]", plan.TransitGateways)
+	}
+	if len(plan.SpokeGateways) != 1 || plan.SpokeGateways[0].ID != "spoke-1" {
+		t.Fatalf("SpokeGateways = %+v, want [spoke-1]", plan.SpokeGateways)
+	}
+	if len(plan.VPNGateways) != 1 || plan.VPNGateways[0].ID != "vpn-1" {
+		t.Fatalf("VPNGateways = %+v, want [vpn-1]", plan.VPNGateways)
+	}
+	if len(plan.BGPNeighbors) != 1 || plan.BGPNeighbors[0].ID != "bgp-1" {
+		t.Fatalf("BGPNeighbors = %+v, want [bgp-1]", plan.BGPNeighbors)
+	}
+	if len(plan.Routes) != 1 || plan.Routes[0].ID != "route-1" {
+		t.Fatalf("Routes = %+v, want [route-1]", plan.Routes)
+	}
+
+	if owners["transit_gateway/tgw-1"] != "team-networking/transit.yaml" {
+		t.Fatalf("owners[transit_gateway/tgw-1] = %q, want the manifest entry's owner", owners["transit_gateway/tgw-1"])
+	}
+	if owners["spoke_gateway/spoke-1"] != "" {
+		t.Fatalf("owners[spoke_gateway/spoke-1] = %q, want empty since that entry had no owner", owners["spoke_gateway/spoke-1"])
+	}
+}
+
+func TestParseManifestRejectsUnsupportedKind(t *testing.T) {
+	manifest := []byte(`
+- kind: connection
+  spec: {id: conn-1}
+`)
+	if _, _, err := ParseManifest(manifest); err == nil {
+		t.Fatalf("ParseManifest with an unsupported kind returned nil error, want one")
+	}
+}
+
+func TestParseManifestRejectsMalformedYAML(t *testing.T) {
+	if _, _, err := ParseManifest([]byte("not: [valid")); err == nil {
+		t.Fatalf("ParseManifest with malformed YAML returned nil error, want one")
+	}
+}
+
+func TestReconcilerBackedOffFalseForUnknownKey(t *testing.T) {
+	r := NewReconciler(nil, nil, ApplyOptions{})
+	if r.backedOff("transit_gateway/tgw-1") {
+		t.Fatalf("backedOff(unknown key) = true, want false")
+	}
+}
+
+func TestReconcilerRecordFailureBacksOffThenRecordSuccessClears(t *testing.T) {
+	r := NewReconciler(nil, nil, ApplyOptions{})
+	key := "transit_gateway/tgw-1"
+
+	r.recordFailure(key)
+	if !r.backedOff(key) {
+		t.Fatalf("backedOff(%q) = false immediately after recordFailure, want true", key)
+	}
+
+	r.recordSuccess(key)
+	if r.backedOff(key) {
+		t.Fatalf("backedOff(%q) = true after recordSuccess, want false (backoff cleared)", key)
+	}
+}
+
+func TestReconcilerRecordFailureBackoffExpires(t *testing.T) {
+	r := NewReconciler(nil, nil, ApplyOptions{})
+	key := "transit_gateway/tgw-1"
+
+	r.recordFailure(key)
+	r.backoffMu.Lock()
+	r.backoff[key].retryAt = time.Now().Add(-time.Second)
+	r.backoffMu.Unlock()
+
+	if r.backedOff(key) {
+		t.Fatalf("backedOff(%q) = true after its retryAt elapsed, want false", key)
+	}
+}
+
+func TestReconcilerRecordFailureTwiceIncreasesBackoff(t *testing.T) {
+	r := NewReconciler(nil, nil, ApplyOptions{})
+	key := "transit_gateway/tgw-1"
+
+	// A jitter-free policy makes each delay exactly Multiplier times the
+	// previous one, so the comparison below cannot flake on randomization.
+	bo := backoff.NewExponentialBackOff()
+	bo.RandomizationFactor = 0
+	r.backoff[key] = &reconcileBackoff{policy: bo}
+
+	r.recordFailure(key)
+	r.backoffMu.Lock()
+	first := r.backoff[key].retryAt
+	r.backoffMu.Unlock()
+
+	r.recordFailure(key)
+	r.backoffMu.Lock()
+	second := r.backoff[key].retryAt
+	r.backoffMu.Unlock()
+
+	if !second.After(first) {
+		t.Fatalf("second retryAt (%v) did not move later than the first (%v), want exponential backoff to increase the delay", second, first)
+	}
+}
+
+func TestReconcilerSetDesiredCoalescesWake(t *testing.T) {
+	r := NewReconciler(nil, nil, ApplyOptions{})
+
+	r.SetDesired(NetworkPlan{}, map[string]string{"x": "y"})
+	r.SetDesired(NetworkPlan{}, map[string]string{"x": "z"})
+
+	select {
+	case <-r.queued:
+	default:
+		t.Fatalf("queued channel empty after SetDesired, want a pending wake")
+	}
+	select {
+	case <-r.queued:
+		t.Fatalf("queued channel had a second pending wake, want the two SetDesired calls to coalesce into one")
+	default:
+	}
+
+	r.mu.Lock()
+	owner := r.owners["x"]
+	r.mu.Unlock()
+	if owner != "z" {
+		t.Fatalf("owners[x] = %q, want the latest SetDesired's value %q", owner, "z")
+	}
+}
@@ -0,0 +1,433 @@
+package aviatrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// RIBFormat selects the wire format ExportRIB encodes routes into.
+type RIBFormat string
+
+const (
+	RIBFormatMRT RIBFormat = "mrt"
+	RIBFormatBMP RIBFormat = "bmp"
+)
+
+// PathAttributes are the BGP path attributes ExportRIB/StreamBMP attach to
+// an encoded route, the same AS_PATH/NEXT_HOP/MED/LOCAL_PREF/COMMUNITY set
+// RoutingRule's ActionCriteria already carries for policy actions.
+type PathAttributes struct {
+	ASPath    string
+	NextHop   string
+	MED       int
+	LocalPref int
+	Community string
+}
+
+// pathAttributesFor derives PathAttributes for route from the neighbor
+// that learned it. Client's bespoke Route has no per-route attribute
+// struct of its own, so NextHop and MED come straight off Route and
+// LocalPref defaults to the common 100 baseline.
+func pathAttributesFor(route Route, neighbor BGPNeighbor) PathAttributes {
+	return PathAttributes{
+		ASPath:    fmt.Sprintf("%d", neighbor.ASNumber),
+		NextHop:   route.NextHop,
+		MED:       route.Metric,
+		LocalPref: 100,
+	}
+}
+
+// ExportRIB fetches gatewayID's learned and advertised routes and BGP
+// neighbors and encodes them as either an MRT TABLE_DUMP_V2 RIB dump or a
+// sequence of BMP route-monitoring messages, so the data can be fed into
+// existing tooling (GoBGP, pmacct, BGPStream) instead of the bespoke JSON
+// Route struct.
+func (c *Client) ExportRIB(ctx context.Context, gatewayID string, format RIBFormat) ([]byte, error) {
+	routes, err := c.ListRoutes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("aviatrix: listing routes for RIB export: %w", err)
+	}
+	neighbors, err := c.ListBGPNeighbors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("aviatrix: listing BGP neighbors for RIB export: %w", err)
+	}
+
+	var gwRoutes []Route
+	for _, r := range routes {
+		if r.Gateway == gatewayID {
+			gwRoutes = append(gwRoutes, r)
+		}
+	}
+	var gwNeighbors []BGPNeighbor
+	for _, n := range neighbors {
+		if n.Gateway == gatewayID {
+			gwNeighbors = append(gwNeighbors, n)
+		}
+	}
+
+	switch format {
+	case RIBFormatBMP:
+		return encodeBMPRIB(gwNeighbors, gwRoutes), nil
+	case RIBFormatMRT, "":
+		return encodeMRTTableDumpV2(gwNeighbors, gwRoutes), nil
+	default:
+		return nil, fmt.Errorf("aviatrix: unknown RIB export format %q", format)
+	}
+}
+
+// BMPMessage is one BMP (RFC 7854) route-monitoring PDU StreamBMP emits:
+// a per-peer header wrapping a BGP UPDATE carrying one route.
+type BMPMessage struct {
+	GatewayID string
+	PeerIP    string
+	Route     Route
+	Raw       []byte
+}
+
+// bmpPollInterval is how often StreamBMP re-lists gatewayID's routes.
+// Client's API has no push notification for route changes, so this
+// resyncs on an interval instead of truly streaming BGP UPDATEs.
+const bmpPollInterval = 30 * time.Second
+
+// StreamBMP polls gatewayID's routes and BGP neighbors and emits one
+// BMPMessage per route as a BMP route-monitoring PDU, closing the
+// returned channel once ctx is canceled.
+func (c *Client) StreamBMP(ctx context.Context, gatewayID string) (<-chan BMPMessage, error) {
+	out := make(chan BMPMessage, 32)
+
+	go func() {
+		defer close(out)
+
+		emit := func() bool {
+			routes, err := c.ListRoutes(ctx)
+			if err != nil {
+				return true
+			}
+			neighbors, err := c.ListBGPNeighbors(ctx)
+			if err != nil {
+				return true
+			}
+
+			var neighbor BGPNeighbor
+			for _, n := range neighbors {
+				if n.Gateway == gatewayID {
+					neighbor = n
+					break
+				}
+			}
+
+			for _, r := range routes {
+				if r.Gateway != gatewayID {
+					continue
+				}
+				msg := BMPMessage{
+					GatewayID: gatewayID,
+					PeerIP:    neighbor.NeighborIP,
+					Route:     r,
+					Raw:       encodeBMPRouteMonitoring(neighbor, r),
+				}
+				select {
+				case out <- msg:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		}
+
+		if !emit() {
+			return
+		}
+
+		ticker := time.NewTicker(bmpPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !emit() {
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// encodeMRTTableDumpV2 renders neighbors and routes as an MRT
+// TABLE_DUMP_V2 stream (RFC 6396): one PEER_INDEX_TABLE record (subtype
+// 1) naming every neighbor, followed by one RIB_IPV4_UNICAST record
+// (subtype 2) per IPv4 route. Each record is prefixed by MRT's 12-byte
+// common header (timestamp, type=13, subtype, length).
+func encodeMRTTableDumpV2(neighbors []BGPNeighbor, routes []Route) []byte {
+	var out bytes.Buffer
+
+	peerIndexByGateway := make(map[string]int, len(neighbors))
+	var peerTable bytes.Buffer
+	peerTable.Write(make([]byte, 4)) // Collector BGP ID, unknown here
+	peerTable.Write([]byte{0, 0})    // View Name Length = 0, no view name
+
+	peerCount := make([]byte, 2)
+	binary.BigEndian.PutUint16(peerCount, uint16(len(neighbors)))
+	peerTable.Write(peerCount)
+
+	for i, n := range neighbors {
+		peerIndexByGateway[n.Gateway] = i
+
+		peerTable.WriteByte(0x02) // peer type: IPv4 peer address, 4-byte ASN
+		peerTable.Write(make([]byte, 4)) // Peer BGP ID, unknown
+
+		peerIP := make([]byte, 4)
+		if ip := net.ParseIP(n.NeighborIP).To4(); ip != nil {
+			copy(peerIP, ip)
+		}
+		peerTable.Write(peerIP)
+
+		peerAS := make([]byte, 4)
+		binary.BigEndian.PutUint32(peerAS, uint32(n.ASNumber))
+		peerTable.Write(peerAS)
+	}
+
+	writeMRTRecord(&out, 13, 1, peerTable.Bytes())
+
+	for seq, r := range routes {
+		prefix, prefixLen, ok := parseIPv4Prefix(r.Destination)
+		if !ok {
+			continue // IPv6 and unparsable destinations are not carried by this encoder
+		}
+
+		var neighbor BGPNeighbor
+		for _, n := range neighbors {
+			if n.Gateway == r.Gateway {
+				neighbor = n
+				break
+			}
+		}
+		attrs := encodeBGPPathAttrs(pathAttributesFor(r, neighbor))
+
+		var entry bytes.Buffer
+		seqBuf := make([]byte, 4)
+		binary.BigEndian.PutUint32(seqBuf, uint32(seq))
+		entry.Write(seqBuf)
+		entry.WriteByte(byte(prefixLen))
+		entry.Write(prefix[:(prefixLen+7)/8])
+
+		entryCount := make([]byte, 2)
+		binary.BigEndian.PutUint16(entryCount, 1)
+		entry.Write(entryCount)
+
+		peerIdx := make([]byte, 2)
+		binary.BigEndian.PutUint16(peerIdx, uint16(peerIndexByGateway[r.Gateway]))
+		entry.Write(peerIdx)
+
+		originated := make([]byte, 4)
+		binary.BigEndian.PutUint32(originated, uint32(time.Now().Unix()))
+		entry.Write(originated)
+
+		attrLen := make([]byte, 2)
+		binary.BigEndian.PutUint16(attrLen, uint16(len(attrs)))
+		entry.Write(attrLen)
+		entry.Write(attrs)
+
+		writeMRTRecord(&out, 13, 2, entry.Bytes())
+	}
+
+	return out.Bytes()
+}
+
+// writeMRTRecord appends one MRT record (12-byte common header plus
+// body) to out.
+func writeMRTRecord(out *bytes.Buffer, mrtType, subtype uint16, body []byte) {
+	header := make([]byte, 12)
+	binary.BigEndian.PutUint32(header[0:], uint32(time.Now().Unix()))
+	binary.BigEndian.PutUint16(header[4:], mrtType)
+	binary.BigEndian.PutUint16(header[6:], subtype)
+	binary.BigEndian.PutUint32(header[8:], uint32(len(body)))
+	out.Write(header)
+	out.Write(body)
+}
+
+// parseIPv4Prefix parses destination as either a CIDR or a bare address
+// (treated as a /32 host route), returning false for anything that isn't
+// an IPv4 address.
+func parseIPv4Prefix(destination string) (prefix net.IP, prefixLen int, ok bool) {
+	if ip, ipNet, err := net.ParseCIDR(destination); err == nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			length, _ := ipNet.Mask.Size()
+			return ip4, length, true
+		}
+		return nil, 0, false
+	}
+
+	if ip := net.ParseIP(destination); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			return ip4, 32, true
+		}
+	}
+	return nil, 0, false
+}
+
+// encodeBGPPathAttrs renders attrs as a sequence of BGP UPDATE path
+// attribute TLVs (ORIGIN, AS_PATH, NEXT_HOP, MULTI_EXIT_DISC, LOCAL_PREF,
+// and COMMUNITY when set).
+func encodeBGPPathAttrs(attrs PathAttributes) []byte {
+	var buf bytes.Buffer
+
+	writeAttr(&buf, 0x40, 1, []byte{0}) // ORIGIN: IGP
+	writeAttr(&buf, 0x40, 2, encodeASPath(attrs.ASPath))
+
+	if ip := net.ParseIP(attrs.NextHop).To4(); ip != nil {
+		writeAttr(&buf, 0x40, 3, ip)
+	}
+
+	med := make([]byte, 4)
+	binary.BigEndian.PutUint32(med, uint32(attrs.MED))
+	writeAttr(&buf, 0x80, 4, med)
+
+	localPref := make([]byte, 4)
+	binary.BigEndian.PutUint32(localPref, uint32(attrs.LocalPref))
+	writeAttr(&buf, 0x40, 5, localPref)
+
+	if attrs.Community != "" {
+		writeAttr(&buf, 0xC0, 8, encodeCommunity(attrs.Community))
+	}
+
+	return buf.Bytes()
+}
+
+// writeAttr appends one BGP path attribute TLV to buf: flags, type code,
+// a one-byte length (every attribute this package writes stays well
+// under 255 bytes), and value.
+func writeAttr(buf *bytes.Buffer, flags, typeCode byte, value []byte) {
+	buf.WriteByte(flags)
+	buf.WriteByte(typeCode)
+	buf.WriteByte(byte(len(value)))
+	buf.Write(value)
+}
+
+// encodeASPath renders a comma-separated list of AS numbers as one
+// AS_SEQUENCE path segment of 4-byte ASNs.
+func encodeASPath(asPath string) []byte {
+	var ases []uint32
+	for _, s := range strings.Split(asPath, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		var as uint32
+		fmt.Sscanf(s, "%d", &as)
+		ases = append(ases, as)
+	}
+
+	out := make([]byte, 2+4*len(ases))
+	out[0] = 2 // AS_SEQUENCE
+	out[1] = byte(len(ases))
+	for i, as := range ases {
+		binary.BigEndian.PutUint32(out[2+4*i:], as)
+	}
+	return out
+}
+
+// encodeCommunity renders a comma-separated list of "asn:value" pairs as
+// BGP COMMUNITY 4-byte values.
+func encodeCommunity(community string) []byte {
+	var buf bytes.Buffer
+	for _, pair := range strings.Split(community, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		var asn, value uint16
+		fmt.Sscanf(parts[0], "%d", &asn)
+		fmt.Sscanf(parts[1], "%d", &value)
+
+		entry := make([]byte, 4)
+		binary.BigEndian.PutUint16(entry, asn)
+		binary.BigEndian.PutUint16(entry[2:], value)
+		buf.Write(entry)
+	}
+	return buf.Bytes()
+}
+
+// encodeBMPRIB renders routes as a back-to-back sequence of BMP
+// route-monitoring messages, one per route, matched to the neighbor
+// that shares its gateway.
+func encodeBMPRIB(neighbors []BGPNeighbor, routes []Route) []byte {
+	neighborByGateway := make(map[string]BGPNeighbor, len(neighbors))
+	for _, n := range neighbors {
+		neighborByGateway[n.Gateway] = n
+	}
+
+	var out bytes.Buffer
+	for _, r := range routes {
+		out.Write(encodeBMPRouteMonitoring(neighborByGateway[r.Gateway], r))
+	}
+	return out.Bytes()
+}
+
+// encodeBMPRouteMonitoring renders route as one BMP (RFC 7854) Route
+// Monitoring message: a BMP common header, a per-peer header describing
+// neighbor, and a BGP UPDATE carrying route's NLRI and path attributes.
+func encodeBMPRouteMonitoring(neighbor BGPNeighbor, route Route) []byte {
+	attrs := encodeBGPPathAttrs(pathAttributesFor(route, neighbor))
+
+	var nlri bytes.Buffer
+	if prefix, prefixLen, ok := parseIPv4Prefix(route.Destination); ok {
+		nlri.WriteByte(byte(prefixLen))
+		nlri.Write(prefix[:(prefixLen+7)/8])
+	}
+
+	update := make([]byte, 0, 23+len(attrs)+nlri.Len())
+	update = append(update, bytes.Repeat([]byte{0xFF}, 16)...) // marker
+	update = append(update, 0, 0)                              // length, patched below
+	update = append(update, 2)                                 // type = UPDATE
+	update = append(update, 0, 0)                               // withdrawn routes length = 0
+	attrLen := make([]byte, 2)
+	binary.BigEndian.PutUint16(attrLen, uint16(len(attrs)))
+	update = append(update, attrLen...)
+	update = append(update, attrs...)
+	update = append(update, nlri.Bytes()...)
+	binary.BigEndian.PutUint16(update[16:], uint16(len(update)))
+
+	var perPeer bytes.Buffer
+	perPeer.WriteByte(0)             // peer type: global instance
+	perPeer.WriteByte(0)             // peer flags
+	perPeer.Write(make([]byte, 8))   // peer distinguisher, unused
+	peerAddr := make([]byte, 16)
+	if ip := net.ParseIP(neighbor.NeighborIP).To4(); ip != nil {
+		copy(peerAddr[12:], ip)
+	}
+	perPeer.Write(peerAddr)
+	peerAS := make([]byte, 4)
+	binary.BigEndian.PutUint32(peerAS, uint32(neighbor.ASNumber))
+	perPeer.Write(peerAS)
+	perPeer.Write(make([]byte, 4)) // peer BGP ID, unknown
+
+	now := time.Now()
+	seconds := make([]byte, 4)
+	binary.BigEndian.PutUint32(seconds, uint32(now.Unix()))
+	perPeer.Write(seconds)
+	perPeer.Write(make([]byte, 4)) // microseconds, unused
+
+	var out bytes.Buffer
+	out.WriteByte(3) // BMP version 3
+
+	totalLen := 6 + perPeer.Len() + len(update)
+	lengthBuf := make([]byte, 4)
+	binary.BigEndian.PutUint32(lengthBuf, uint32(totalLen))
+	out.Write(lengthBuf)
+
+	out.WriteByte(0) // message type = Route Monitoring
+	out.Write(perPeer.Bytes())
+	out.Write(update)
+
+	return out.Bytes()
+}
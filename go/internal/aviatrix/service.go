@@ -1,51 +1,124 @@
 package aviatrix
 
 import (
-	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+
+	"router-sim/internal/analytics"
+	"router-sim/internal/config"
 )
 
 // Service represents the Aviatrix service
 type Service struct {
 	client *Client
 	logger *zap.Logger
+
+	// store and reconciler back the gateways/transit-gateways/
+	// spoke-gateways routes: Create* writes desired state to store and
+	// enqueues a create job, reconciler drives it to GatewayUp against
+	// client, and Get/List read store's last observed state.
+	store      *GatewayStore
+	reconciler *gatewayReconciler
+
+	// peering backs the peerings/* routes; nil until SetPeeringClient is
+	// called, since it needs controller credentials Service's own client
+	// doesn't carry.
+	peering AviatrixService
 }
 
-// NewService creates a new Aviatrix service
-func NewService(config *config.AviatrixConfig, logger *zap.Logger) (*Service, error) {
-	client := NewClient(config)
-	
+// NewService creates a new Aviatrix service. clickhouse may be nil, in
+// which case gateway lifecycle events are skipped rather than recorded.
+func NewService(cfg *config.AviatrixConfig, clickhouse *analytics.ClickHouseClient, logger *zap.Logger) (*Service, error) {
+	client, err := NewClient(Config{
+		BaseURL:   cfg.ControllerURL,
+		APIKey:    cfg.Password,
+		Timeout:   int(cfg.Timeout.Seconds()),
+		Partition: cfg.Partition,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("aviatrix: building client: %w", err)
+	}
+
+	store, err := NewGatewayStore(cfg.GatewayStorePath)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Service{
-		client: client,
-		logger: logger,
+		client:     client,
+		logger:     logger,
+		store:      store,
+		reconciler: newGatewayReconciler(client, store, clickhouse, logger),
 	}, nil
 }
 
-// ListGateways handles GET /api/v1/aviatrix/gateways
-func (s *Service) ListGateways(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+// Close stops the gateway reconciler's workers and releases the
+// underlying GatewayStore.
+func (s *Service) Close() error {
+	close(s.reconciler.jobs)
+	s.reconciler.wg.Wait()
+	return s.store.Close()
+}
+
+// SetPeeringClient wires the AviatrixService the peerings/* routes use to
+// generate and establish cross-controller peerings, e.g. a
+// ControllerClient pointed at this Aviatrix controller. The peerings
+// handlers reply 503 until one is set.
+func (s *Service) SetPeeringClient(peering AviatrixService) {
+	s.peering = peering
+}
 
-	gateways, err := s.client.GetGateways(ctx)
+// newGatewayID mints a short, collision-resistant ID for a new gateway
+// record: a timestamp for readability plus a random suffix so two
+// creates issued within the same second never collide.
+func newGatewayID(prefix string) (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("aviatrix: generating gateway id: %w", err)
+	}
+	return fmt.Sprintf("%s%s-%s", prefix, time.Now().Format("20060102150405"), hex.EncodeToString(buf)), nil
+}
+
+// listByKind renders every stored GatewayRecord of kind as key in the
+// response, the shared body ListGateways/ListTransitGateways/
+// ListSpokeGateways each call with their own kind and key.
+func (s *Service) listByKind(c *gin.Context, kind GatewayKind, key string) {
+	records, err := s.store.List()
 	if err != nil {
-		s.logger.Error("Failed to get Aviatrix gateways", zap.Error(err))
+		s.logger.Error("Failed to list Aviatrix gateways", zap.Error(err), zap.String("kind", string(kind)))
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to retrieve gateways",
 		})
 		return
 	}
 
+	filtered := make([]GatewayRecord, 0, len(records))
+	for _, rec := range records {
+		if rec.Spec.Kind == kind {
+			filtered = append(filtered, rec)
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"gateways": gateways,
-		"count":    len(gateways),
+		key:       filtered,
+		"count":   len(filtered),
 	})
 }
 
-// CreateGateway handles POST /api/v1/aviatrix/gateways
+// ListGateways handles GET /api/v1/aviatrix/gateways
+func (s *Service) ListGateways(c *gin.Context) {
+	s.listByKind(c, GatewayKindPlain, "gateways")
+}
+
+// CreateGateway handles POST /api/v1/aviatrix/gateways: it persists the
+// desired gateway as GatewayPending and enqueues a create job, returning
+// immediately rather than waiting for the gateway to come up.
 func (s *Service) CreateGateway(c *gin.Context) {
 	var request struct {
 		Name   string `json:"name" binding:"required"`
@@ -62,80 +135,87 @@ func (s *Service) CreateGateway(c *gin.Context) {
 		return
 	}
 
-	// Mock gateway creation
-	gateway := map[string]interface{}{
-		"id":          "gw-" + time.Now().Format("20060102150405"),
-		"name":        request.Name,
-		"cloud":       request.Cloud,
-		"region":      request.Region,
-		"type":        request.Type,
-		"status":      "creating",
-		"asn":         request.ASN,
-		"created_at":  time.Now().Format(time.RFC3339),
+	id, err := newGatewayID("gw-")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate gateway ID"})
+		return
+	}
+
+	now := time.Now()
+	rec := GatewayRecord{
+		ID: id,
+		Spec: GatewaySpec{
+			Kind: GatewayKindPlain, Name: request.Name, Cloud: request.Cloud, Region: request.Region, ASN: request.ASN,
+		},
+		Phase:      GatewayPending,
+		Generation: 1,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.store.Put(rec); err != nil {
+		s.logger.Error("Failed to persist Aviatrix gateway", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to persist gateway"})
+		return
 	}
+	s.reconciler.enqueue(gatewayJob{id: id})
 
-	s.logger.Info("Created Aviatrix gateway", 
+	s.logger.Info("Created Aviatrix gateway",
 		zap.String("name", request.Name),
 		zap.String("cloud", request.Cloud))
 
-	c.JSON(http.StatusCreated, gin.H{
-		"gateway": gateway,
+	c.JSON(http.StatusAccepted, gin.H{
+		"gateway": rec,
 	})
 }
 
 // GetGateway handles GET /api/v1/aviatrix/gateways/:id
 func (s *Service) GetGateway(c *gin.Context) {
 	gatewayID := c.Param("id")
-	
-	// Mock gateway retrieval
-	gateway := map[string]interface{}{
-		"id":          gatewayID,
-		"name":        "aviatrix-gateway-" + gatewayID,
-		"cloud":       "AWS",
-		"region":      "us-west-1",
-		"type":        "transit",
-		"status":      "up",
-		"asn":         65001,
-		"created_at":  "2024-01-01T00:00:00Z",
+
+	rec, ok := s.store.Get(gatewayID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "gateway not found"})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"gateway": gateway,
+		"gateway": rec,
 	})
 }
 
-// DeleteGateway handles DELETE /api/v1/aviatrix/gateways/:id
+// DeleteGateway handles DELETE /api/v1/aviatrix/gateways/:id: it marks
+// the record GatewayDeleting and enqueues a delete job, which removes
+// the record once the underlying gateway is actually torn down.
 func (s *Service) DeleteGateway(c *gin.Context) {
 	gatewayID := c.Param("id")
-	
-	s.logger.Info("Deleted Aviatrix gateway", zap.String("id", gatewayID))
-	
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Gateway deleted successfully",
-		"id":      gatewayID,
-	})
-}
 
-// ListTransitGateways handles GET /api/v1/aviatrix/transit-gateways
-func (s *Service) ListTransitGateways(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	rec, ok := s.store.Get(gatewayID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "gateway not found"})
+		return
+	}
 
-	gateways, err := s.client.GetTransitGateways(ctx)
-	if err != nil {
-		s.logger.Error("Failed to get Aviatrix transit gateways", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve transit gateways",
-		})
+	rec.Phase = GatewayDeleting
+	rec.UpdatedAt = time.Now()
+	if err := s.store.Put(*rec); err != nil {
+		s.logger.Error("Failed to mark Aviatrix gateway for deletion", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to persist gateway"})
 		return
 	}
+	s.reconciler.enqueue(gatewayJob{id: gatewayID, delete: true})
 
-	c.JSON(http.StatusOK, gin.H{
-		"transit_gateways": gateways,
-		"count":           len(gateways),
+	s.logger.Info("Deleting Aviatrix gateway", zap.String("id", gatewayID))
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"gateway": rec,
 	})
 }
 
+// ListTransitGateways handles GET /api/v1/aviatrix/transit-gateways
+func (s *Service) ListTransitGateways(c *gin.Context) {
+	s.listByKind(c, GatewayKindTransit, "transit_gateways")
+}
+
 // CreateTransitGateway handles POST /api/v1/aviatrix/transit-gateways
 func (s *Service) CreateTransitGateway(c *gin.Context) {
 	var request struct {
@@ -152,45 +232,42 @@ func (s *Service) CreateTransitGateway(c *gin.Context) {
 		return
 	}
 
-	// Mock transit gateway creation
-	gateway := map[string]interface{}{
-		"id":          "tgw-" + time.Now().Format("20060102150405"),
-		"name":        request.Name,
-		"cloud":       request.Cloud,
-		"region":      request.Region,
-		"type":        "transit",
-		"status":      "creating",
-		"asn":         request.ASN,
-		"created_at":  time.Now().Format(time.RFC3339),
+	id, err := newGatewayID("tgw-")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate gateway ID"})
+		return
 	}
 
-	s.logger.Info("Created Aviatrix transit gateway", 
+	now := time.Now()
+	rec := GatewayRecord{
+		ID: id,
+		Spec: GatewaySpec{
+			Kind: GatewayKindTransit, Name: request.Name, Cloud: request.Cloud, Region: request.Region, ASN: request.ASN,
+		},
+		Phase:      GatewayPending,
+		Generation: 1,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.store.Put(rec); err != nil {
+		s.logger.Error("Failed to persist Aviatrix transit gateway", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to persist gateway"})
+		return
+	}
+	s.reconciler.enqueue(gatewayJob{id: id})
+
+	s.logger.Info("Created Aviatrix transit gateway",
 		zap.String("name", request.Name),
 		zap.String("cloud", request.Cloud))
 
-	c.JSON(http.StatusCreated, gin.H{
-		"transit_gateway": gateway,
+	c.JSON(http.StatusAccepted, gin.H{
+		"transit_gateway": rec,
 	})
 }
 
 // ListSpokeGateways handles GET /api/v1/aviatrix/spoke-gateways
 func (s *Service) ListSpokeGateways(c *gin.Context) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	gateways, err := s.client.GetSpokeGateways(ctx)
-	if err != nil {
-		s.logger.Error("Failed to get Aviatrix spoke gateways", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to retrieve spoke gateways",
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"spoke_gateways": gateways,
-		"count":         len(gateways),
-	})
+	s.listByKind(c, GatewayKindSpoke, "spoke_gateways")
 }
 
 // CreateSpokeGateway handles POST /api/v1/aviatrix/spoke-gateways
@@ -209,23 +286,127 @@ func (s *Service) CreateSpokeGateway(c *gin.Context) {
 		return
 	}
 
-	// Mock spoke gateway creation
-	gateway := map[string]interface{}{
-		"id":          "sgw-" + time.Now().Format("20060102150405"),
-		"name":        request.Name,
-		"cloud":       request.Cloud,
-		"region":      request.Region,
-		"type":        "spoke",
-		"status":      "creating",
-		"vpc_id":      request.VPCID,
-		"created_at":  time.Now().Format(time.RFC3339),
+	id, err := newGatewayID("sgw-")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate gateway ID"})
+		return
 	}
 
-	s.logger.Info("Created Aviatrix spoke gateway", 
+	now := time.Now()
+	rec := GatewayRecord{
+		ID: id,
+		Spec: GatewaySpec{
+			Kind: GatewayKindSpoke, Name: request.Name, Cloud: request.Cloud, Region: request.Region, VPCID: request.VPCID,
+		},
+		Phase:      GatewayPending,
+		Generation: 1,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.store.Put(rec); err != nil {
+		s.logger.Error("Failed to persist Aviatrix spoke gateway", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to persist gateway"})
+		return
+	}
+	s.reconciler.enqueue(gatewayJob{id: id})
+
+	s.logger.Info("Created Aviatrix spoke gateway",
 		zap.String("name", request.Name),
 		zap.String("cloud", request.Cloud))
 
-	c.JSON(http.StatusCreated, gin.H{
-		"spoke_gateway": gateway,
+	c.JSON(http.StatusAccepted, gin.H{
+		"spoke_gateway": rec,
 	})
 }
+
+// GeneratePeeringToken handles POST /api/v1/aviatrix/peerings/token
+func (s *Service) GeneratePeeringToken(c *gin.Context) {
+	if s.peering == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "peering is not configured"})
+		return
+	}
+
+	var request struct {
+		LocalGateway string `json:"local_gateway" binding:"required"`
+		RemoteName   string `json:"remote_name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	token, err := s.peering.GeneratePeeringToken(c.Request.Context(), request.LocalGateway, request.RemoteName)
+	if err != nil {
+		s.logger.Error("Failed to generate peering token", zap.Error(err), zap.String("local_gateway", request.LocalGateway))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate peering token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": token})
+}
+
+// EstablishPeering handles POST /api/v1/aviatrix/peerings/establish
+func (s *Service) EstablishPeering(c *gin.Context) {
+	if s.peering == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "peering is not configured"})
+		return
+	}
+
+	var request struct {
+		Token        string `json:"token" binding:"required"`
+		LocalGateway string `json:"local_gateway" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	peering, err := s.peering.EstablishPeering(c.Request.Context(), request.Token, request.LocalGateway)
+	if err != nil {
+		s.logger.Error("Failed to establish peering", zap.Error(err), zap.String("local_gateway", request.LocalGateway))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to establish peering"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"peering": peering})
+}
+
+// TeardownPeering handles DELETE /api/v1/aviatrix/peerings/:name
+func (s *Service) TeardownPeering(c *gin.Context) {
+	if s.peering == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "peering is not configured"})
+		return
+	}
+
+	name := c.Param("name")
+
+	if err := s.peering.TeardownPeering(c.Request.Context(), name); err != nil {
+		s.logger.Error("Failed to tear down peering", zap.Error(err), zap.String("peering_name", name))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to tear down peering"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "peering torn down", "peering_name": name})
+}
+
+// RegisterRoutes mounts every handler above onto group.
+func (s *Service) RegisterRoutes(group gin.IRouter) {
+	group.GET("/gateways", s.ListGateways)
+	group.POST("/gateways", s.CreateGateway)
+	group.GET("/gateways/:id", s.GetGateway)
+	group.DELETE("/gateways/:id", s.DeleteGateway)
+
+	group.GET("/transit-gateways", s.ListTransitGateways)
+	group.POST("/transit-gateways", s.CreateTransitGateway)
+
+	group.GET("/spoke-gateways", s.ListSpokeGateways)
+	group.POST("/spoke-gateways", s.CreateSpokeGateway)
+
+	group.POST("/peerings/token", s.GeneratePeeringToken)
+	group.POST("/peerings/establish", s.EstablishPeering)
+	group.DELETE("/peerings/:name", s.TeardownPeering)
+}
@@ -0,0 +1,72 @@
+package topology
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Handler exposes Reconciler over HTTP.
+type Handler struct {
+	reconciler *Reconciler
+	logger     *zap.Logger
+}
+
+// NewHandler wraps reconciler for HTTP use.
+func NewHandler(reconciler *Reconciler, logger *zap.Logger) *Handler {
+	return &Handler{reconciler: reconciler, logger: logger}
+}
+
+// RegisterRoutes mounts Handler's routes onto group.
+func (h *Handler) RegisterRoutes(group gin.IRouter) {
+	group.POST("/topology/apply", h.Apply)
+}
+
+// Apply handles POST /api/v1/aviatrix/topology/apply. The request body is
+// a Topology document (YAML or JSON); ?dryRun=true, ?parallelism=<n>, and
+// ?continueOnError=true map onto ApplyOptions. The response is
+// NDJSON (one ActionResult object per line), flushed as each action
+// completes so a caller can watch reconciliation progress in real time.
+func (h *Handler) Apply(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	desired, err := Parse(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	opts := ApplyOptions{
+		DryRun:          c.Query("dryRun") == "true",
+		ContinueOnError: c.Query("continueOnError") == "true",
+	}
+	if p := c.Query("parallelism"); p != "" {
+		if n, err := strconv.Atoi(p); err == nil {
+			opts.ParallelismPerLevel = n
+		}
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+
+	flusher, _ := c.Writer.(http.Flusher)
+	encoder := json.NewEncoder(c.Writer)
+
+	for result := range h.reconciler.ApplyStream(c.Request.Context(), desired, opts) {
+		if err := encoder.Encode(result); err != nil {
+			h.logger.Error("topology: failed writing NDJSON result", zap.Error(err))
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
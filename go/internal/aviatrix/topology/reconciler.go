@@ -0,0 +1,517 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"router-sim/internal/aviatrix"
+)
+
+// ObjectKind identifies which part of a Topology an Action targets.
+type ObjectKind string
+
+const (
+	KindTransitGateway ObjectKind = "transitGateway"
+	KindSpokeGateway   ObjectKind = "spokeGateway"
+	KindPeering        ObjectKind = "peering"
+	KindSecurityDomain ObjectKind = "securityDomain"
+	KindFirewallPolicy ObjectKind = "firewallPolicy"
+)
+
+// ActionKind is what a plan proposes doing with one object.
+type ActionKind string
+
+const (
+	ActionCreate ActionKind = "create"
+	ActionUpdate ActionKind = "update"
+	ActionDelete ActionKind = "delete"
+	ActionNoop   ActionKind = "no-op"
+)
+
+// Action is one step of a Plan: converge Kind/Name towards Action, after
+// every dependency at a lower Level has applied.
+type Action struct {
+	Kind   ObjectKind  `json:"kind"`
+	Name   string      `json:"name"`
+	Action ActionKind  `json:"action"`
+	Level  int         `json:"level"`
+	Spec   interface{} `json:"spec,omitempty"`
+}
+
+func (a Action) node() node {
+	return node{kind: a.Kind, name: a.Name}
+}
+
+type node struct {
+	kind ObjectKind
+	name string
+}
+
+// ActionResult is one Action's outcome once Apply has run it.
+type ActionResult struct {
+	Action
+	Status string `json:"status"` // "applied", "skipped", "failed"
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is Apply's final summary of every Action it ran.
+type Report struct {
+	Results []ActionResult `json:"results"`
+}
+
+// ApplyOptions configures an Apply run.
+type ApplyOptions struct {
+	// DryRun computes the plan and reports every action as "skipped"
+	// without calling the controller.
+	DryRun bool
+	// ParallelismPerLevel bounds how many Actions in the same dependency
+	// level run concurrently. Defaults to 1 (fully sequential) if zero.
+	ParallelismPerLevel int
+	// ContinueOnError keeps applying later levels after a failure in an
+	// earlier one; otherwise Apply stops after the level containing the
+	// failure finishes.
+	ContinueOnError bool
+}
+
+// Reconciler diffs a Topology against live controller state and converges
+// the controller onto it.
+type Reconciler struct {
+	client aviatrix.AviatrixService
+	logger *zap.Logger
+}
+
+// NewReconciler builds a Reconciler that reads and writes through client.
+func NewReconciler(client aviatrix.AviatrixService, logger *zap.Logger) *Reconciler {
+	return &Reconciler{client: client, logger: logger}
+}
+
+// Plan diffs desired against live controller state and returns the
+// ordered Actions that converge the controller onto it, lowest dependency
+// Level first. Live transit gateways, spoke gateways and peerings not
+// named in desired are planned for deletion; live security domains and
+// firewall policies are never planned for ActionUpdate or ActionDelete,
+// since AviatrixService can only create or leave them as-is.
+func (r *Reconciler) Plan(ctx context.Context, desired *Topology) ([]Action, error) {
+	live, err := r.fetchLive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []Action
+	desiredNodes := map[node]bool{}
+
+	for _, s := range desired.TransitGateways {
+		n := node{KindTransitGateway, s.Name}
+		desiredNodes[n] = true
+		if existing, ok := live.transitGateways[s.Name]; ok {
+			actions = append(actions, Action{Kind: KindTransitGateway, Name: s.Name, Action: diffTransitGateway(existing, s), Spec: s})
+		} else {
+			actions = append(actions, Action{Kind: KindTransitGateway, Name: s.Name, Action: ActionCreate, Spec: s})
+		}
+	}
+	for name := range live.transitGateways {
+		if !desiredNodes[node{KindTransitGateway, name}] {
+			actions = append(actions, Action{Kind: KindTransitGateway, Name: name, Action: ActionDelete})
+		}
+	}
+
+	for _, s := range desired.SpokeGateways {
+		n := node{KindSpokeGateway, s.Name}
+		desiredNodes[n] = true
+		if _, ok := live.spokeGateways[s.Name]; ok {
+			actions = append(actions, Action{Kind: KindSpokeGateway, Name: s.Name, Action: ActionNoop, Spec: s})
+		} else {
+			actions = append(actions, Action{Kind: KindSpokeGateway, Name: s.Name, Action: ActionCreate, Spec: s})
+		}
+	}
+	for name := range live.spokeGateways {
+		if !desiredNodes[node{KindSpokeGateway, name}] {
+			actions = append(actions, Action{Kind: KindSpokeGateway, Name: name, Action: ActionDelete})
+		}
+	}
+
+	for _, s := range desired.SecurityDomains {
+		n := node{KindSecurityDomain, s.Name}
+		desiredNodes[n] = true
+		if _, ok := live.securityDomains[s.Name]; ok {
+			actions = append(actions, Action{Kind: KindSecurityDomain, Name: s.Name, Action: ActionNoop, Spec: s})
+		} else {
+			actions = append(actions, Action{Kind: KindSecurityDomain, Name: s.Name, Action: ActionCreate, Spec: s})
+		}
+	}
+
+	for _, s := range desired.FirewallPolicies {
+		n := node{KindFirewallPolicy, s.Name}
+		desiredNodes[n] = true
+		if _, ok := live.firewallPolicies[s.Name]; ok {
+			actions = append(actions, Action{Kind: KindFirewallPolicy, Name: s.Name, Action: ActionNoop, Spec: s})
+		} else {
+			actions = append(actions, Action{Kind: KindFirewallPolicy, Name: s.Name, Action: ActionCreate, Spec: s})
+		}
+	}
+
+	for _, s := range desired.Peerings {
+		n := node{KindPeering, s.Name}
+		desiredNodes[n] = true
+		if _, ok := live.peerings[s.Name]; ok {
+			actions = append(actions, Action{Kind: KindPeering, Name: s.Name, Action: ActionNoop, Spec: s})
+		} else {
+			actions = append(actions, Action{Kind: KindPeering, Name: s.Name, Action: ActionCreate, Spec: s})
+		}
+	}
+	for name := range live.peerings {
+		if !desiredNodes[node{KindPeering, name}] {
+			actions = append(actions, Action{Kind: KindPeering, Name: name, Action: ActionDelete})
+		}
+	}
+
+	levels := levelOf(desired, actions)
+	for i := range actions {
+		actions[i].Level = levels[actions[i].node()]
+	}
+
+	sort.Slice(actions, func(i, j int) bool {
+		if actions[i].Level != actions[j].Level {
+			return actions[i].Level < actions[j].Level
+		}
+		if actions[i].Kind != actions[j].Kind {
+			return actions[i].Kind < actions[j].Kind
+		}
+		return actions[i].Name < actions[j].Name
+	})
+	return actions, nil
+}
+
+// levelOf assigns every action's node a dependency level: 0 if it depends
+// on nothing else in desired, otherwise one more than the highest level
+// among its dependencies. A dependency only counts if the referenced
+// object is itself part of desired (and therefore also has an Action);
+// a reference to something not being reconciled this run is assumed to
+// already exist live.
+func levelOf(desired *Topology, actions []Action) map[node]int {
+	present := map[node]bool{}
+	for _, a := range actions {
+		present[a.node()] = true
+	}
+
+	deps := map[node][]node{}
+	for _, s := range desired.SpokeGateways {
+		n := node{KindSpokeGateway, s.Name}
+		if s.AttachedTransitGateway != "" {
+			dep := node{KindTransitGateway, s.AttachedTransitGateway}
+			if present[dep] {
+				deps[n] = append(deps[n], dep)
+			}
+		}
+	}
+	for _, s := range desired.Peerings {
+		n := node{KindPeering, s.Name}
+		for _, gw := range []string{s.SourceGateway, s.DestinationGateway} {
+			dep := node{KindTransitGateway, gw}
+			if present[dep] {
+				deps[n] = append(deps[n], dep)
+			}
+		}
+	}
+	for _, s := range desired.FirewallPolicies {
+		n := node{KindFirewallPolicy, s.Name}
+		if s.SecurityDomain != "" {
+			dep := node{KindSecurityDomain, s.SecurityDomain}
+			if present[dep] {
+				deps[n] = append(deps[n], dep)
+			}
+		}
+	}
+	for _, s := range desired.SecurityDomains {
+		n := node{KindSecurityDomain, s.Name}
+		for _, gw := range s.Gateways {
+			for _, kind := range []ObjectKind{KindTransitGateway, KindSpokeGateway} {
+				dep := node{kind, gw}
+				if present[dep] {
+					deps[n] = append(deps[n], dep)
+				}
+			}
+		}
+	}
+
+	levels := map[node]int{}
+	var resolve func(n node, visiting map[node]bool) int
+	resolve = func(n node, visiting map[node]bool) int {
+		if lvl, ok := levels[n]; ok {
+			return lvl
+		}
+		if visiting[n] {
+			return 0 // dependency cycle: break it rather than loop forever
+		}
+		visiting[n] = true
+		lvl := 0
+		for _, dep := range deps[n] {
+			if depLvl := resolve(dep, visiting) + 1; depLvl > lvl {
+				lvl = depLvl
+			}
+		}
+		delete(visiting, n)
+		levels[n] = lvl
+		return lvl
+	}
+	for _, a := range actions {
+		resolve(a.node(), map[node]bool{})
+	}
+	return levels
+}
+
+// Apply runs Plan and converges the controller onto every resulting
+// Action, in dependency order.
+func (r *Reconciler) Apply(ctx context.Context, desired *Topology, opts ApplyOptions) (Report, error) {
+	results, err := drain(r.ApplyStream(ctx, desired, opts))
+	return Report{Results: results}, err
+}
+
+// ApplyStream is Apply's streaming form: it runs Plan, then executes
+// Actions level by level (up to opts.ParallelismPerLevel concurrently
+// within a level, waiting for the whole level before starting the next),
+// emitting each ActionResult on the returned channel as it completes. The
+// channel is closed once every action has been attempted (or a level
+// fails and opts.ContinueOnError is false).
+func (r *Reconciler) ApplyStream(ctx context.Context, desired *Topology, opts ApplyOptions) <-chan ActionResult {
+	out := make(chan ActionResult)
+	parallelism := opts.ParallelismPerLevel
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+
+	go func() {
+		defer close(out)
+
+		actions, err := r.Plan(ctx, desired)
+		if err != nil {
+			r.logger.Error("topology: plan failed", zap.Error(err))
+			return
+		}
+
+		byLevel := map[int][]Action{}
+		var levelsSeen []int
+		for _, a := range actions {
+			if _, ok := byLevel[a.Level]; !ok {
+				levelsSeen = append(levelsSeen, a.Level)
+			}
+			byLevel[a.Level] = append(byLevel[a.Level], a)
+		}
+		sort.Ints(levelsSeen)
+
+		for _, lvl := range levelsSeen {
+			levelFailed := runLevel(ctx, r, byLevel[lvl], opts, parallelism, out)
+			if levelFailed && !opts.ContinueOnError {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// runLevel applies every Action in one dependency level, up to
+// parallelism concurrently, reporting each ActionResult on out, and
+// returns whether any of them failed.
+func runLevel(ctx context.Context, r *Reconciler, actions []Action, opts ApplyOptions, parallelism int, out chan<- ActionResult) bool {
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failed := false
+
+	for _, a := range actions {
+		a := a
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := r.applyOne(ctx, a, opts)
+			if result.Status == "failed" {
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+			}
+			out <- result
+		}()
+	}
+	wg.Wait()
+	return failed
+}
+
+func (r *Reconciler) applyOne(ctx context.Context, a Action, opts ApplyOptions) ActionResult {
+	if a.Action == ActionNoop {
+		return ActionResult{Action: a, Status: "skipped"}
+	}
+	if opts.DryRun {
+		r.logger.Info("topology: dry-run", zap.String("kind", string(a.Kind)), zap.String("name", a.Name), zap.String("action", string(a.Action)))
+		return ActionResult{Action: a, Status: "skipped"}
+	}
+
+	r.logger.Info("topology: reconciling",
+		zap.String("kind", string(a.Kind)),
+		zap.String("name", a.Name),
+		zap.String("action", string(a.Action)),
+		zap.Int("level", a.Level))
+
+	if err := r.execute(ctx, a); err != nil {
+		r.logger.Error("topology: reconcile failed",
+			zap.String("kind", string(a.Kind)),
+			zap.String("name", a.Name),
+			zap.String("action", string(a.Action)),
+			zap.Error(err))
+		return ActionResult{Action: a, Status: "failed", Error: err.Error()}
+	}
+	return ActionResult{Action: a, Status: "applied"}
+}
+
+// execute performs a single non-no-op Action against the controller.
+func (r *Reconciler) execute(ctx context.Context, a Action) error {
+	switch a.Kind {
+	case KindTransitGateway:
+		switch a.Action {
+		case ActionCreate:
+			spec := a.Spec.(TransitGatewaySpec)
+			_, err := r.client.CreateTransitGateway(ctx, spec.toRequest())
+			return err
+		case ActionUpdate:
+			spec := a.Spec.(TransitGatewaySpec)
+			_, err := r.client.UpdateTransitGateway(ctx, a.Name, spec.toRequest())
+			return err
+		case ActionDelete:
+			return r.client.DeleteGateway(ctx, a.Name)
+		}
+	case KindSpokeGateway:
+		switch a.Action {
+		case ActionCreate:
+			spec := a.Spec.(SpokeGatewaySpec)
+			if _, err := r.client.CreateSpokeGateway(ctx, spec.toRequest()); err != nil {
+				return err
+			}
+			if spec.AttachedTransitGateway != "" {
+				return r.client.AttachSpokeToTransit(ctx, spec.Name, spec.AttachedTransitGateway)
+			}
+			return nil
+		case ActionDelete:
+			return r.client.DeleteGateway(ctx, a.Name)
+		}
+	case KindSecurityDomain:
+		if a.Action == ActionCreate {
+			spec := a.Spec.(SecurityDomainSpec)
+			_, err := r.client.CreateSecurityDomain(ctx, &aviatrix.SecurityDomain{
+				DomainName:  spec.Name,
+				Description: spec.Description,
+				Gateways:    spec.Gateways,
+			})
+			return err
+		}
+	case KindFirewallPolicy:
+		if a.Action == ActionCreate {
+			spec := a.Spec.(FirewallPolicySpec)
+			_, err := r.client.CreateFirewallPolicy(ctx, &aviatrix.FirewallPolicy{
+				PolicyName:  spec.Name,
+				Source:      spec.Source,
+				Destination: spec.Destination,
+				Protocol:    spec.Protocol,
+				Port:        spec.Port,
+				Action:      spec.Action,
+				LogEnabled:  spec.LogEnabled,
+			})
+			return err
+		}
+	case KindPeering:
+		switch a.Action {
+		case ActionCreate:
+			spec := a.Spec.(PeeringSpec)
+			_, err := r.client.CreateTransitGatewayPeering(ctx, spec.SourceGateway, spec.DestinationGateway)
+			return err
+		case ActionDelete:
+			return r.client.TeardownPeering(ctx, a.Name)
+		}
+	}
+	return fmt.Errorf("topology: %s does not support action %q on %s", a.Kind, a.Action, a.Name)
+}
+
+// diffTransitGateway reports whether spec's fields already match
+// existing, the only kind whose AviatrixService implementation supports
+// an in-place update.
+func diffTransitGateway(existing aviatrix.ControllerTransitGateway, spec TransitGatewaySpec) ActionKind {
+	if existing.LocalASNumber == spec.LocalASNumber &&
+		existing.GatewaySize == spec.GatewaySize &&
+		existing.EnableEncryptPeering == spec.EnableEncryptPeering &&
+		existing.EnablePeeringOverPrivateNetwork == spec.EnablePeeringOverPrivateNetwork {
+		return ActionNoop
+	}
+	return ActionUpdate
+}
+
+type liveState struct {
+	transitGateways  map[string]aviatrix.ControllerTransitGateway
+	spokeGateways    map[string]aviatrix.ControllerSpokeGateway
+	peerings         map[string]aviatrix.TransitGatewayPeering
+	securityDomains  map[string]aviatrix.SecurityDomain
+	firewallPolicies map[string]aviatrix.FirewallPolicy
+}
+
+func (r *Reconciler) fetchLive(ctx context.Context) (*liveState, error) {
+	transitGateways, err := r.client.GetTransitGateways(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("topology: list transit gateways: %w", err)
+	}
+	spokeGateways, err := r.client.GetSpokeGateways(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("topology: list spoke gateways: %w", err)
+	}
+	peerings, err := r.client.GetTransitGatewayPeering(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("topology: list transit gateway peerings: %w", err)
+	}
+	securityDomains, err := r.client.GetSecurityDomains(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("topology: list security domains: %w", err)
+	}
+	firewallPolicies, err := r.client.GetFirewallPolicies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("topology: list firewall policies: %w", err)
+	}
+
+	live := &liveState{
+		transitGateways:  make(map[string]aviatrix.ControllerTransitGateway, len(transitGateways)),
+		spokeGateways:    make(map[string]aviatrix.ControllerSpokeGateway, len(spokeGateways)),
+		peerings:         make(map[string]aviatrix.TransitGatewayPeering, len(peerings)),
+		securityDomains:  make(map[string]aviatrix.SecurityDomain, len(securityDomains)),
+		firewallPolicies: make(map[string]aviatrix.FirewallPolicy, len(firewallPolicies)),
+	}
+	for _, gw := range transitGateways {
+		live.transitGateways[gw.GatewayName] = gw
+	}
+	for _, gw := range spokeGateways {
+		live.spokeGateways[gw.GatewayName] = gw
+	}
+	for _, p := range peerings {
+		live.peerings[p.PeeringName] = p
+	}
+	for _, d := range securityDomains {
+		live.securityDomains[d.DomainName] = d
+	}
+	for _, f := range firewallPolicies {
+		live.firewallPolicies[f.PolicyName] = f
+	}
+	return live, nil
+}
+
+func drain(ch <-chan ActionResult) ([]ActionResult, error) {
+	var results []ActionResult
+	var firstErr error
+	for r := range ch {
+		results = append(results, r)
+		if r.Status == "failed" && firstErr == nil {
+			firstErr = fmt.Errorf("topology: %s %s %q failed: %s", r.Action.Action, r.Kind, r.Name, r.Error)
+		}
+	}
+	return results, firstErr
+}
@@ -0,0 +1,120 @@
+// Package topology reconciles a declarative desired-state document of
+// Aviatrix transit-network objects against live controller state, the
+// same role the Traefik Kubernetes Gateway provider plays for Gateway API
+// resources: the document cross-references objects by name instead of
+// controller-assigned IDs, and Reconciler figures out what to create,
+// update, or delete, and in what order, to converge the controller onto
+// it.
+package topology
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"router-sim/internal/aviatrix"
+)
+
+// TransitGatewaySpec declares one desired transit gateway.
+type TransitGatewaySpec struct {
+	Name                            string `yaml:"name" json:"name"`
+	CloudType                       int    `yaml:"cloudType" json:"cloudType"`
+	AccountName                     string `yaml:"accountName" json:"accountName"`
+	Region                          string `yaml:"region" json:"region"`
+	VPCID                           string `yaml:"vpcId" json:"vpcId"`
+	GatewaySize                     string `yaml:"gatewaySize" json:"gatewaySize"`
+	Subnet                          string `yaml:"subnet" json:"subnet"`
+	LocalASNumber                   string `yaml:"localAsNumber" json:"localAsNumber"`
+	EnableEncryptPeering            bool   `yaml:"enableEncryptPeering" json:"enableEncryptPeering"`
+	EnablePeeringOverPrivateNetwork bool   `yaml:"enablePeeringOverPrivateNetwork" json:"enablePeeringOverPrivateNetwork"`
+}
+
+func (s TransitGatewaySpec) toRequest() *aviatrix.CreateTransitGatewayRequest {
+	return &aviatrix.CreateTransitGatewayRequest{
+		GatewayName:                     s.Name,
+		CloudType:                       s.CloudType,
+		AccountName:                     s.AccountName,
+		Region:                          s.Region,
+		VPCID:                           s.VPCID,
+		GatewaySize:                     s.GatewaySize,
+		Subnet:                          s.Subnet,
+		LocalASNumber:                   s.LocalASNumber,
+		EnableEncryptPeering:            s.EnableEncryptPeering,
+		EnablePeeringOverPrivateNetwork: s.EnablePeeringOverPrivateNetwork,
+	}
+}
+
+// SpokeGatewaySpec declares one desired spoke gateway, optionally attached
+// to a transit gateway declared (or already live) under
+// AttachedTransitGateway.
+type SpokeGatewaySpec struct {
+	Name                   string `yaml:"name" json:"name"`
+	CloudType              int    `yaml:"cloudType" json:"cloudType"`
+	AccountName            string `yaml:"accountName" json:"accountName"`
+	Region                 string `yaml:"region" json:"region"`
+	VPCID                  string `yaml:"vpcId" json:"vpcId"`
+	GatewaySize            string `yaml:"gatewaySize" json:"gatewaySize"`
+	Subnet                 string `yaml:"subnet" json:"subnet"`
+	AttachedTransitGateway string `yaml:"attachedTransitGateway,omitempty" json:"attachedTransitGateway,omitempty"`
+}
+
+func (s SpokeGatewaySpec) toRequest() *aviatrix.CreateTransitGatewayRequest {
+	return &aviatrix.CreateTransitGatewayRequest{
+		GatewayName: s.Name,
+		CloudType:   s.CloudType,
+		AccountName: s.AccountName,
+		Region:      s.Region,
+		VPCID:       s.VPCID,
+		GatewaySize: s.GatewaySize,
+		Subnet:      s.Subnet,
+	}
+}
+
+// PeeringSpec declares one desired transit gateway peering between two
+// gateways on the same controller.
+type PeeringSpec struct {
+	Name               string `yaml:"name" json:"name"`
+	SourceGateway      string `yaml:"sourceGateway" json:"sourceGateway"`
+	DestinationGateway string `yaml:"destinationGateway" json:"destinationGateway"`
+}
+
+// SecurityDomainSpec declares one desired security domain.
+type SecurityDomainSpec struct {
+	Name        string   `yaml:"name" json:"name"`
+	Description string   `yaml:"description" json:"description"`
+	Gateways    []string `yaml:"gateways,omitempty" json:"gateways,omitempty"`
+}
+
+// FirewallPolicySpec declares one desired firewall policy. SecurityDomain
+// is a topology-only reference used to order the policy after the domain
+// it belongs to; the controller API itself has no such field.
+type FirewallPolicySpec struct {
+	Name           string `yaml:"name" json:"name"`
+	SecurityDomain string `yaml:"securityDomain,omitempty" json:"securityDomain,omitempty"`
+	Source         string `yaml:"source" json:"source"`
+	Destination    string `yaml:"destination" json:"destination"`
+	Protocol       string `yaml:"protocol" json:"protocol"`
+	Port           string `yaml:"port" json:"port"`
+	Action         string `yaml:"action" json:"action"`
+	LogEnabled     bool   `yaml:"logEnabled" json:"logEnabled"`
+}
+
+// Topology is the full desired-state document Reconciler converges the
+// controller onto.
+type Topology struct {
+	TransitGateways  []TransitGatewaySpec  `yaml:"transitGateways,omitempty" json:"transitGateways,omitempty"`
+	SpokeGateways    []SpokeGatewaySpec    `yaml:"spokeGateways,omitempty" json:"spokeGateways,omitempty"`
+	Peerings         []PeeringSpec         `yaml:"peerings,omitempty" json:"peerings,omitempty"`
+	SecurityDomains  []SecurityDomainSpec  `yaml:"securityDomains,omitempty" json:"securityDomains,omitempty"`
+	FirewallPolicies []FirewallPolicySpec  `yaml:"firewallPolicies,omitempty" json:"firewallPolicies,omitempty"`
+}
+
+// Parse decodes a Topology document. YAML is accepted, and since JSON is
+// a subset of YAML, a JSON document parses the same way.
+func Parse(data []byte) (*Topology, error) {
+	var t Topology
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("topology: parsing document: %w", err)
+	}
+	return &t, nil
+}
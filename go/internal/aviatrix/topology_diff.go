@@ -0,0 +1,394 @@
+package aviatrix
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ChangeType is the kind of change TopologyDiff found between two
+// NetworkTopology snapshots.
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// NodeChange is one node that differs between two topology snapshots.
+// Before is nil for an added node, After is nil for a removed one.
+type NodeChange struct {
+	Type   ChangeType
+	ID     string
+	Before *TopologyNode
+	After  *TopologyNode
+}
+
+// LinkChange is one link that differs between two topology snapshots.
+// Before is nil for an added link, After is nil for a removed one.
+type LinkChange struct {
+	Type   ChangeType
+	Key    string
+	Before *TopologyLink
+	After  *TopologyLink
+}
+
+// AlertSeverity is how urgently an on-call reader should treat a
+// TopologyAlert.
+type AlertSeverity string
+
+const (
+	SeverityWarning  AlertSeverity = "warning"
+	SeverityCritical AlertSeverity = "critical"
+)
+
+// TopologyAlert flags a NodeChange or LinkChange that breaks a known
+// invariant rather than just reporting drift for its own sake.
+type TopologyAlert struct {
+	Severity AlertSeverity
+	Message  string
+}
+
+// TopologyDelta is everything TopologyDiff found between two
+// NetworkTopology snapshots: the raw node/link changes, plus the subset
+// of them significant enough to alert on.
+type TopologyDelta struct {
+	NodeChanges []NodeChange
+	LinkChanges []LinkChange
+	Alerts      []TopologyAlert
+}
+
+// TopologyDiff compares prev and curr and reports every added, removed,
+// or modified node and link, plus alerts for changes that break a known
+// invariant: a spoke losing its transit uplink, or a node's status
+// flipping to something that isn't healthy. TopologyNode/TopologyLink
+// carry no ASN or route data, so ASN-mismatch and route-withdrawal
+// alerts live in DetectBGPChurn instead, which diffs EnhancedBGPNeighbor
+// snapshots directly.
+func TopologyDiff(prev, curr *NetworkTopology) TopologyDelta {
+	delta := TopologyDelta{}
+
+	prevNodes := make(map[string]TopologyNode, len(prev.Nodes))
+	for _, n := range prev.Nodes {
+		prevNodes[n.ID] = n
+	}
+	currNodes := make(map[string]TopologyNode, len(curr.Nodes))
+	for _, n := range curr.Nodes {
+		currNodes[n.ID] = n
+	}
+
+	for id, after := range currNodes {
+		after := after
+		if before, ok := prevNodes[id]; ok {
+			if before != after {
+				before := before
+				delta.NodeChanges = append(delta.NodeChanges, NodeChange{Type: ChangeModified, ID: id, Before: &before, After: &after})
+				if isDown(before.Status) != isDown(after.Status) && isDown(after.Status) {
+					delta.Alerts = append(delta.Alerts, TopologyAlert{
+						Severity: SeverityCritical,
+						Message:  fmt.Sprintf("node %s (%s) went from status %q to %q", id, after.Type, before.Status, after.Status),
+					})
+				}
+			}
+		} else {
+			delta.NodeChanges = append(delta.NodeChanges, NodeChange{Type: ChangeAdded, ID: id, After: &after})
+		}
+	}
+	for id, before := range prevNodes {
+		if _, ok := currNodes[id]; !ok {
+			before := before
+			delta.NodeChanges = append(delta.NodeChanges, NodeChange{Type: ChangeRemoved, ID: id, Before: &before})
+		}
+	}
+
+	prevLinks := make(map[string]TopologyLink, len(prev.Links))
+	for _, l := range prev.Links {
+		prevLinks[linkKey(l)] = l
+	}
+	currLinks := make(map[string]TopologyLink, len(curr.Links))
+	for _, l := range curr.Links {
+		currLinks[linkKey(l)] = l
+	}
+
+	for key, after := range currLinks {
+		after := after
+		if before, ok := prevLinks[key]; ok {
+			if before != after {
+				before := before
+				delta.LinkChanges = append(delta.LinkChanges, LinkChange{Type: ChangeModified, Key: key, Before: &before, After: &after})
+			}
+		} else {
+			delta.LinkChanges = append(delta.LinkChanges, LinkChange{Type: ChangeAdded, Key: key, After: &after})
+		}
+	}
+	for key, before := range prevLinks {
+		if _, ok := currLinks[key]; !ok {
+			before := before
+			delta.LinkChanges = append(delta.LinkChanges, LinkChange{Type: ChangeRemoved, Key: key, Before: &before})
+
+			if isTransitUplink(before, currNodes) {
+				delta.Alerts = append(delta.Alerts, TopologyAlert{
+					Severity: SeverityCritical,
+					Message:  fmt.Sprintf("spoke uplink removed: %s lost its transit link to %s", before.Source, before.Target),
+				})
+			}
+		}
+	}
+
+	return delta
+}
+
+func isDown(status string) bool {
+	switch strings.ToLower(status) {
+	case "down", "inactive", "failed", "unreachable":
+		return true
+	default:
+		return false
+	}
+}
+
+// isTransitUplink reports whether removed connected a spoke node to a
+// transit node, the shape of link a spoke depends on for reachability.
+func isTransitUplink(removed TopologyLink, currNodes map[string]TopologyNode) bool {
+	src, srcOK := currNodes[removed.Source]
+	dst, dstOK := currNodes[removed.Target]
+	if !srcOK || !dstOK {
+		// One endpoint is gone along with the link; a node removal
+		// already covers that case, so this isn't an independent alert.
+		return false
+	}
+	return (src.Type == "spoke" && dst.Type == "transit") || (src.Type == "transit" && dst.Type == "spoke")
+}
+
+// DetectBGPChurn compares two snapshots of a gateway's BGP neighbors and
+// reports ASN mismatches and route withdrawals — the two invariants
+// TopologyNode/TopologyLink can't see, since neither carries ASN or
+// route-count data.
+func DetectBGPChurn(gatewayID string, prev, curr []EnhancedBGPNeighbor) []TopologyAlert {
+	prevByID := make(map[string]EnhancedBGPNeighbor, len(prev))
+	for _, n := range prev {
+		prevByID[n.ID] = n
+	}
+
+	var alerts []TopologyAlert
+	for _, after := range curr {
+		before, ok := prevByID[after.ID]
+		if !ok {
+			continue
+		}
+		if before.ASN != after.ASN {
+			alerts = append(alerts, TopologyAlert{
+				Severity: SeverityCritical,
+				Message:  fmt.Sprintf("gateway %s: neighbor %s ASN changed from %d to %d", gatewayID, after.IP, before.ASN, after.ASN),
+			})
+		}
+		if before.RoutesReceived > 0 && after.RoutesReceived == 0 {
+			alerts = append(alerts, TopologyAlert{
+				Severity: SeverityCritical,
+				Message:  fmt.Sprintf("gateway %s: neighbor %s withdrew all %d previously received routes", gatewayID, after.IP, before.RoutesReceived),
+			})
+		}
+	}
+	return alerts
+}
+
+// SnapshotStore persists NetworkTopology snapshots as JSON files under
+// dir, the on-disk layout Manager uses for capture files, so SaveSnapshot
+// and DiffSince can build a history of topology state without a database.
+type SnapshotStore struct {
+	dir string
+}
+
+// NewSnapshotStore creates a SnapshotStore writing snapshots under dir.
+func NewSnapshotStore(dir string) (*SnapshotStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("aviatrix: creating snapshot dir %q: %w", dir, err)
+	}
+	return &SnapshotStore{dir: dir}, nil
+}
+
+func (s *SnapshotStore) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+// SaveSnapshot writes topology to disk under name, overwriting any
+// previous snapshot saved under the same name.
+func (s *SnapshotStore) SaveSnapshot(name string, topology *NetworkTopology) error {
+	data, err := json.Marshal(topology)
+	if err != nil {
+		return fmt.Errorf("aviatrix: encoding snapshot %q: %w", name, err)
+	}
+	if err := os.WriteFile(s.path(name), data, 0o644); err != nil {
+		return fmt.Errorf("aviatrix: writing snapshot %q: %w", name, err)
+	}
+	return nil
+}
+
+// DiffSince loads the snapshot saved under name and diffs it against
+// curr, the entry point for "what changed since the last known-good
+// topology" on-call review.
+func (s *SnapshotStore) DiffSince(name string, curr *NetworkTopology) (TopologyDelta, error) {
+	data, err := os.ReadFile(s.path(name))
+	if err != nil {
+		return TopologyDelta{}, fmt.Errorf("aviatrix: reading snapshot %q: %w", name, err)
+	}
+
+	var prev NetworkTopology
+	if err := json.Unmarshal(data, &prev); err != nil {
+		return TopologyDelta{}, fmt.Errorf("aviatrix: decoding snapshot %q: %w", name, err)
+	}
+
+	return TopologyDiff(&prev, curr), nil
+}
+
+// Partition is one connected component ReachabilityCheck found within a
+// segmentation domain — a set of spokes that can reach each other, but
+// not the rest of the domain.
+type Partition struct {
+	Domain string
+	Spokes []string
+}
+
+// ReachabilityCheck groups spokes into segmentation domains by the
+// transit gateway they're attached to (only transits with
+// EnableSegmentation set define a domain worth checking), then BFS's
+// through connections within each domain to verify every spoke can
+// reach every other. A domain that BFS splits into more than one
+// component is reported as one Partition per component, the graph's way
+// of saying "these spokes can't see each other".
+func ReachabilityCheck(spokes []SpokeGateway, transits []TransitGateway, connections []Connection) []Partition {
+	segmented := make(map[string]bool, len(transits))
+	for _, t := range transits {
+		if t.EnableSegmentation {
+			segmented[t.ID] = true
+		}
+	}
+
+	domainSpokes := make(map[string][]string)
+	for _, sp := range spokes {
+		if segmented[sp.TransitGateway] {
+			domainSpokes[sp.TransitGateway] = append(domainSpokes[sp.TransitGateway], sp.ID)
+		}
+	}
+
+	adjacency := make(map[string][]string)
+	for _, c := range connections {
+		adjacency[c.Source] = append(adjacency[c.Source], c.Destination)
+		adjacency[c.Destination] = append(adjacency[c.Destination], c.Source)
+	}
+
+	var partitions []Partition
+	for domain, members := range domainSpokes {
+		inDomain := make(map[string]bool, len(members))
+		for _, id := range members {
+			inDomain[id] = true
+		}
+
+		visited := make(map[string]bool, len(members))
+		for _, start := range members {
+			if visited[start] {
+				continue
+			}
+
+			component := bfs(start, inDomain, adjacency, visited)
+			if len(component) < len(members) {
+				sort.Strings(component)
+				partitions = append(partitions, Partition{Domain: domain, Spokes: component})
+			}
+		}
+	}
+
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].Domain < partitions[j].Domain })
+	return partitions
+}
+
+func bfs(start string, inDomain map[string]bool, adjacency map[string][]string, visited map[string]bool) []string {
+	queue := []string{start}
+	visited[start] = true
+	component := []string{start}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, next := range adjacency[node] {
+			if !inDomain[next] || visited[next] {
+				continue
+			}
+			visited[next] = true
+			component = append(component, next)
+			queue = append(queue, next)
+		}
+	}
+
+	return component
+}
+
+// ToDOT renders topology as a Graphviz DOT graph, coloring nodes and
+// edges by the change delta found (if any) so on-call can see what
+// moved at a glance: green for added, red for removed, orange for
+// modified, black for unchanged. A nil delta renders the plain topology.
+func ToDOT(topology *NetworkTopology, delta *TopologyDelta) string {
+	nodeColor := make(map[string]string)
+	linkColor := make(map[string]string)
+	if delta != nil {
+		for _, c := range delta.NodeChanges {
+			switch c.Type {
+			case ChangeAdded:
+				nodeColor[c.ID] = "green"
+			case ChangeRemoved:
+				nodeColor[c.ID] = "red"
+			case ChangeModified:
+				nodeColor[c.ID] = "orange"
+			}
+		}
+		for _, c := range delta.LinkChanges {
+			switch c.Type {
+			case ChangeAdded:
+				linkColor[c.Key] = "green"
+			case ChangeRemoved:
+				linkColor[c.Key] = "red"
+			case ChangeModified:
+				linkColor[c.Key] = "orange"
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph topology {\n")
+	for _, n := range topology.Nodes {
+		color := nodeColor[n.ID]
+		if color == "" {
+			color = "black"
+		}
+		fmt.Fprintf(&b, "  %q [label=%q, color=%q];\n", n.ID, fmt.Sprintf("%s\\n%s", n.Name, n.Status), color)
+	}
+	for _, l := range topology.Links {
+		key := linkKey(l)
+		color := linkColor[key]
+		if color == "" {
+			color = "black"
+		}
+		fmt.Fprintf(&b, "  %q -> %q [color=%q, label=%q];\n", l.Source, l.Target, color, l.Status)
+	}
+	// Removed nodes and links only exist in the delta, not in topology,
+	// so they're drawn from there directly rather than being found above.
+	if delta != nil {
+		for _, c := range delta.NodeChanges {
+			if c.Type == ChangeRemoved {
+				fmt.Fprintf(&b, "  %q [label=%q, color=red, style=dashed];\n", c.Before.ID, fmt.Sprintf("%s\\n(removed)", c.Before.Name))
+			}
+		}
+		for _, c := range delta.LinkChanges {
+			if c.Type == ChangeRemoved {
+				fmt.Fprintf(&b, "  %q -> %q [color=red, style=dashed, label=\"removed\"];\n", c.Before.Source, c.Before.Target)
+			}
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
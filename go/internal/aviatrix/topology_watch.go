@@ -0,0 +1,247 @@
+package aviatrix
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// TopologyEventType is the kind of change WatchTopology's stream reported.
+type TopologyEventType string
+
+const (
+	TopologyAdded    TopologyEventType = "added"
+	TopologyRemoved  TopologyEventType = "removed"
+	TopologyModified TopologyEventType = "modified"
+)
+
+// TopologyEvent is one incremental change /topology/watch pushed. Exactly
+// one of Node or Link is set, matching whichever object changed.
+type TopologyEvent struct {
+	Type TopologyEventType `json:"type"`
+	Node *TopologyNode     `json:"node,omitempty"`
+	Link *TopologyLink     `json:"link,omitempty"`
+}
+
+// WatchTopology opens a Server-Sent Events connection to /topology/watch
+// and returns a channel of incremental TopologyEvents, the push-based
+// complement to GetNetworkTopology's poll-and-diff snapshot. A dropped
+// connection is retried internally with the same exponential-backoff
+// policy retryingRoundTripper applies to individual requests; callers
+// only see a reconnect as a pause in events. The returned channel is
+// closed once ctx is canceled.
+func (c *EnhancedClient) WatchTopology(ctx context.Context) (<-chan TopologyEvent, error) {
+	events := make(chan TopologyEvent, 64)
+
+	go func() {
+		defer close(events)
+
+		bo := backoff.NewExponentialBackOff()
+		bo.MaxElapsedTime = 0 // retry until ctx is canceled, never give up on its own
+
+		for {
+			err := c.watchTopologyOnce(ctx, events)
+			if ctx.Err() != nil {
+				return
+			}
+			if err == nil {
+				bo.Reset()
+				continue
+			}
+
+			wait := bo.NextBackOff()
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// watchTopologyOnce opens one SSE connection to /topology/watch and
+// forwards its events until the stream ends or errors. A server-closed
+// stream is reported as a nil error so WatchTopology reconnects
+// immediately instead of backing off after a clean close.
+func (c *EnhancedClient) watchTopologyOnce(ctx context.Context, events chan<- TopologyEvent) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/topology/watch", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to watch network topology: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to watch network topology with status: %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "" {
+			continue
+		}
+
+		var event TopologyEvent
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			continue
+		}
+
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// TopologyEventHandler receives callbacks as TopologyCache applies each
+// TopologyEvent; a nil field is simply not called for that event kind,
+// the optional-callback shape client-go's ResourceEventHandlerFuncs uses.
+type TopologyEventHandler struct {
+	OnAdd    func(TopologyEvent)
+	OnUpdate func(TopologyEvent)
+	OnDelete func(TopologyEvent)
+}
+
+// TopologyCache is a push-driven, in-memory mirror of one EnhancedClient's
+// network topology, kept current by consuming WatchTopology's event
+// channel instead of polling GetNetworkTopology. It is the push-based
+// counterpart to cloudpods' list-then-poll InformerFactory: callers
+// register handlers through AddEventHandler and read the current state
+// through Snapshot without ever re-polling the API themselves.
+type TopologyCache struct {
+	mu    sync.RWMutex
+	nodes map[string]TopologyNode
+	links map[string]TopologyLink
+
+	handlerMu sync.Mutex
+	handlers  []TopologyEventHandler
+}
+
+// NewTopologyCache creates an empty TopologyCache. Call Run to start
+// applying a WatchTopology stream to it.
+func NewTopologyCache() *TopologyCache {
+	return &TopologyCache{
+		nodes: make(map[string]TopologyNode),
+		links: make(map[string]TopologyLink),
+	}
+}
+
+// AddEventHandler registers h to be called for every event Run applies
+// from here on. It does not replay the current snapshot the way
+// client-go's AddEventHandler does; call Snapshot first if the caller
+// needs the state that predates registration.
+func (t *TopologyCache) AddEventHandler(h TopologyEventHandler) {
+	t.handlerMu.Lock()
+	defer t.handlerMu.Unlock()
+	t.handlers = append(t.handlers, h)
+}
+
+// Run applies every event from events to the cache, dispatching
+// registered handlers as it goes, until events is closed (WatchTopology
+// closes it once its ctx is canceled).
+func (t *TopologyCache) Run(events <-chan TopologyEvent) {
+	for event := range events {
+		t.apply(event)
+	}
+}
+
+func (t *TopologyCache) apply(event TopologyEvent) {
+	var existed bool
+
+	switch {
+	case event.Node != nil:
+		t.mu.Lock()
+		_, existed = t.nodes[event.Node.ID]
+		if event.Type == TopologyRemoved {
+			delete(t.nodes, event.Node.ID)
+		} else {
+			t.nodes[event.Node.ID] = *event.Node
+		}
+		t.mu.Unlock()
+
+	case event.Link != nil:
+		key := linkKey(*event.Link)
+		t.mu.Lock()
+		_, existed = t.links[key]
+		if event.Type == TopologyRemoved {
+			delete(t.links, key)
+		} else {
+			t.links[key] = *event.Link
+		}
+		t.mu.Unlock()
+
+	default:
+		return
+	}
+
+	t.dispatch(event, existed)
+}
+
+func (t *TopologyCache) dispatch(event TopologyEvent, existed bool) {
+	t.handlerMu.Lock()
+	handlers := append([]TopologyEventHandler(nil), t.handlers...)
+	t.handlerMu.Unlock()
+
+	for _, h := range handlers {
+		switch {
+		case event.Type == TopologyRemoved:
+			if h.OnDelete != nil {
+				h.OnDelete(event)
+			}
+		case existed:
+			if h.OnUpdate != nil {
+				h.OnUpdate(event)
+			}
+		default:
+			if h.OnAdd != nil {
+				h.OnAdd(event)
+			}
+		}
+	}
+}
+
+func linkKey(l TopologyLink) string {
+	return l.Source + "->" + l.Target
+}
+
+// Snapshot returns every node and link currently in the cache, letting a
+// caller query the last-known topology without re-polling
+// GetNetworkTopology.
+func (t *TopologyCache) Snapshot() NetworkTopology {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	topology := NetworkTopology{
+		Nodes: make([]TopologyNode, 0, len(t.nodes)),
+		Links: make([]TopologyLink, 0, len(t.links)),
+	}
+	for _, n := range t.nodes {
+		topology.Nodes = append(topology.Nodes, n)
+	}
+	for _, l := range t.links {
+		topology.Links = append(topology.Links, l)
+	}
+	return topology
+}
@@ -0,0 +1,277 @@
+package aviatrix
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// Middleware wraps an http.RoundTripper, the extension point callers use to
+// inject tracing (OpenTelemetry) or structured (zap) logging around every
+// request Client makes. Middleware run outer-to-inner in the order they
+// appear in TransportConfig.Middleware, around the retrying, circuit-broken
+// transport, so a tracer sees one span per logical call rather than one per
+// retry attempt.
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+// TransportConfig tunes the resilience behavior NewClient builds into a
+// Client's http.Client: retries, an in-flight limiter, and a circuit
+// breaker. The zero value is usable — every field defaults to a sane
+// production value.
+type TransportConfig struct {
+	// MaxRetries bounds how many times a 5xx/429/network-error response
+	// is retried with exponential backoff and jitter. Default 3.
+	MaxRetries int
+	// RetryMaxElapsedTime caps total time spent retrying a single call.
+	// Defaults to the Client's Timeout.
+	RetryMaxElapsedTime time.Duration
+	// MaxInFlight bounds concurrent in-flight requests. Default 50.
+	MaxInFlight int
+	// CircuitBreakerThreshold is the number of consecutive failures that
+	// trips the breaker open. Default 5.
+	CircuitBreakerThreshold int
+	// CircuitBreakerResetTimeout is how long the breaker stays open
+	// before allowing a single half-open probe request. Default 30s.
+	CircuitBreakerResetTimeout time.Duration
+	// Middleware wraps the transport, outermost first.
+	Middleware []Middleware
+}
+
+func buildTransport(cfg TransportConfig, defaultTimeout time.Duration) http.RoundTripper {
+	maxRetries := cfg.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = 3
+	}
+	maxElapsed := cfg.RetryMaxElapsedTime
+	if maxElapsed <= 0 {
+		maxElapsed = defaultTimeout
+	}
+
+	var transport http.RoundTripper = &retryingRoundTripper{
+		next:       http.DefaultTransport,
+		limiter:    newLimiter(cfg.MaxInFlight),
+		breaker:    newCircuitBreaker(cfg.CircuitBreakerThreshold, cfg.CircuitBreakerResetTimeout),
+		maxRetries: maxRetries,
+		maxElapsed: maxElapsed,
+	}
+
+	for i := len(cfg.Middleware) - 1; i >= 0; i-- {
+		transport = cfg.Middleware[i](transport)
+	}
+	return transport
+}
+
+// limiter is a counting semaphore bounding in-flight requests.
+type limiter chan struct{}
+
+func newLimiter(n int) limiter {
+	if n <= 0 {
+		n = 50
+	}
+	return make(limiter, n)
+}
+
+func (l limiter) acquire(ctx context.Context) error {
+	select {
+	case l <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l limiter) release() { <-l }
+
+// breakerState is a circuitBreaker's current phase.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker opens after threshold consecutive failures and, once
+// resetTimeout has elapsed, allows exactly one half-open probe request
+// through: a success closes it, a failure reopens it.
+type circuitBreaker struct {
+	mu           sync.Mutex
+	state        breakerState
+	failures     int
+	threshold    int
+	resetTimeout time.Duration
+	probing      bool
+
+	// resetCancel is replaced on every trip. A timer that has already
+	// fired can't be Stopped cleanly (the same hazard gVisor's gonet
+	// works around for deadline timers), so instead of reusing one timer
+	// across trips, each trip gets its own timer and its own cancel
+	// channel identifying it.
+	resetCancel chan struct{}
+}
+
+func newCircuitBreaker(threshold int, resetTimeout time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if resetTimeout <= 0 {
+		resetTimeout = 30 * time.Second
+	}
+	return &circuitBreaker{threshold: threshold, resetTimeout: resetTimeout}
+}
+
+// allow reports whether a call may proceed, claiming the single half-open
+// probe slot if the breaker has just become eligible to retry.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerClosed:
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default: // breakerOpen
+		return false
+	}
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.probing = false
+	b.state = breakerClosed
+}
+
+func (b *circuitBreaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if b.state == breakerHalfOpen {
+		b.trip()
+		return
+	}
+	b.failures++
+	if b.state == breakerClosed && b.failures >= b.threshold {
+		b.trip()
+	}
+}
+
+func (b *circuitBreaker) trip() {
+	b.state = breakerOpen
+	b.failures = 0
+
+	cancel := make(chan struct{})
+	b.resetCancel = cancel
+
+	timer := time.NewTimer(b.resetTimeout)
+	go func() {
+		select {
+		case <-timer.C:
+			b.mu.Lock()
+			if b.resetCancel == cancel {
+				b.state = breakerHalfOpen
+			}
+			b.mu.Unlock()
+		case <-cancel:
+			if !timer.Stop() {
+				<-timer.C
+			}
+		}
+	}()
+}
+
+// retryingRoundTripper retries 5xx/429/network-error responses with
+// exponential backoff and jitter via cenkalti/backoff, honoring a
+// Retry-After header when present, gated by an in-flight limiter and a
+// circuit breaker.
+type retryingRoundTripper struct {
+	next       http.RoundTripper
+	limiter    limiter
+	breaker    *circuitBreaker
+	maxRetries int
+	maxElapsed time.Duration
+}
+
+func (rt *retryingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !rt.breaker.allow() {
+		return nil, fmt.Errorf("aviatrix: circuit breaker open, rejecting request to %s", req.URL.Path)
+	}
+
+	if err := rt.limiter.acquire(req.Context()); err != nil {
+		return nil, err
+	}
+	defer rt.limiter.release()
+
+	bo := backoff.NewExponentialBackOff()
+	bo.MaxElapsedTime = rt.maxElapsed
+	policy := backoff.WithContext(backoff.WithMaxRetries(bo, uint64(rt.maxRetries)), req.Context())
+
+	var resp *http.Response
+	err := backoff.Retry(func() error {
+		attemptReq := req
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return backoff.Permanent(err)
+			}
+			clone := req.Clone(req.Context())
+			clone.Body = body
+			attemptReq = clone
+		}
+
+		res, err := rt.next.RoundTrip(attemptReq)
+		if err != nil {
+			return err
+		}
+
+		if res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500 {
+			retryAfter := retryAfterDelay(res.Header.Get("Retry-After"))
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+			if retryAfter > 0 {
+				time.Sleep(retryAfter)
+			}
+			return fmt.Errorf("aviatrix: retryable response status %d from %s", res.StatusCode, req.URL.Path)
+		}
+
+		resp = res
+		return nil
+	}, policy)
+
+	if err != nil {
+		rt.breaker.recordFailure()
+		return nil, err
+	}
+	rt.breaker.recordSuccess()
+	return resp, nil
+}
+
+// retryAfterDelay parses a Retry-After header value (either a delay in
+// seconds or an HTTP-date), returning 0 if it's absent or unparseable.
+func retryAfterDelay(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
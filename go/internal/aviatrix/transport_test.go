@@ -0,0 +1,231 @@
+package aviatrix
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingRoundTripper calls fn for every RoundTrip, counting attempts.
+type countingRoundTripper struct {
+	attempts int32
+	fn       func(attempt int) (*http.Response, error)
+}
+
+func (rt *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	n := atomic.AddInt32(&rt.attempts, 1)
+	return rt.fn(int(n))
+}
+
+func newTestRequest(t *testing.T) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "http://example.invalid/vpcs", nil)
+	return req
+}
+
+func TestRetryingRoundTripperRetriesOn5xxThenSucceeds(t *testing.T) {
+	inner := &countingRoundTripper{fn: func(attempt int) (*http.Response, error) {
+		if attempt < 3 {
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody, Header: http.Header{}}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: http.Header{}}, nil
+	}}
+	rt := &retryingRoundTripper{
+		next:       inner,
+		limiter:    newLimiter(0),
+		breaker:    newCircuitBreaker(0, 0),
+		maxRetries: 5,
+		maxElapsed: 5 * time.Second,
+	}
+
+	resp, err := rt.RoundTrip(newTestRequest(t))
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("resp.StatusCode = %d, want 200", resp.StatusCode)
+	}
+	if inner.attempts != 3 {
+		t.Fatalf("attempts = %d, want 3 (two failures then a success)", inner.attempts)
+	}
+}
+
+func TestRetryingRoundTripperGivesUpAfterMaxRetries(t *testing.T) {
+	inner := &countingRoundTripper{fn: func(attempt int) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody, Header: http.Header{}}, nil
+	}}
+	rt := &retryingRoundTripper{
+		next:       inner,
+		limiter:    newLimiter(0),
+		breaker:    newCircuitBreaker(100, time.Minute),
+		maxRetries: 2,
+		maxElapsed: 5 * time.Second,
+	}
+
+	if _, err := rt.RoundTrip(newTestRequest(t)); err == nil {
+		t.Fatalf("RoundTrip returned nil error, want one after exhausting retries")
+	}
+	if inner.attempts != 3 {
+		t.Fatalf("attempts = %d, want 3 (the initial attempt plus 2 retries)", inner.attempts)
+	}
+}
+
+func TestRetryingRoundTripperDoesNotRetryNonRetryableStatus(t *testing.T) {
+	inner := &countingRoundTripper{fn: func(attempt int) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: http.NoBody, Header: http.Header{}}, nil
+	}}
+	rt := &retryingRoundTripper{
+		next:       inner,
+		limiter:    newLimiter(0),
+		breaker:    newCircuitBreaker(0, 0),
+		maxRetries: 5,
+		maxElapsed: time.Second,
+	}
+
+	resp, err := rt.RoundTrip(newTestRequest(t))
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("resp.StatusCode = %d, want 404 passed through unretried", resp.StatusCode)
+	}
+	if inner.attempts != 1 {
+		t.Fatalf("attempts = %d, want 1 (a 404 is not retryable)", inner.attempts)
+	}
+}
+
+func TestCircuitBreakerTripsAfterThresholdFailuresAndRejects(t *testing.T) {
+	b := newCircuitBreaker(2, time.Hour)
+
+	if !b.allow() {
+		t.Fatalf("allow() = false before any failures, want true")
+	}
+	b.recordFailure()
+	if !b.allow() {
+		t.Fatalf("allow() = false after 1 of 2 failures, want true (breaker still closed)")
+	}
+	b.recordFailure()
+	if b.allow() {
+		t.Fatalf("allow() = true after reaching the failure threshold, want false (breaker open)")
+	}
+}
+
+func TestCircuitBreakerHalfOpenAllowsOneProbe(t *testing.T) {
+	b := newCircuitBreaker(1, 10*time.Millisecond)
+	b.recordFailure()
+	if b.allow() {
+		t.Fatalf("allow() = true immediately after tripping, want false")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if !b.allow() {
+		t.Fatalf("allow() = false after resetTimeout elapsed, want true (half-open probe)")
+	}
+	if b.allow() {
+		t.Fatalf("allow() = true for a second concurrent call while a probe is in flight, want false")
+	}
+}
+
+func TestCircuitBreakerRecordSuccessClosesFromHalfOpen(t *testing.T) {
+	b := newCircuitBreaker(1, 10*time.Millisecond)
+	b.recordFailure()
+	time.Sleep(50 * time.Millisecond)
+	if !b.allow() {
+		t.Fatalf("allow() = false after resetTimeout elapsed, want true")
+	}
+
+	b.recordSuccess()
+	if b.state != breakerClosed {
+		t.Fatalf("state = %v after recordSuccess, want breakerClosed", b.state)
+	}
+	if !b.allow() {
+		t.Fatalf("allow() = false after the breaker closed, want true")
+	}
+}
+
+func TestCircuitBreakerFailedProbeReopens(t *testing.T) {
+	b := newCircuitBreaker(1, 10*time.Millisecond)
+	b.recordFailure()
+	time.Sleep(50 * time.Millisecond)
+	if !b.allow() {
+		t.Fatalf("allow() = false after resetTimeout elapsed, want true")
+	}
+
+	b.recordFailure()
+	if b.state != breakerOpen {
+		t.Fatalf("state = %v after a failed half-open probe, want breakerOpen", b.state)
+	}
+	if b.allow() {
+		t.Fatalf("allow() = true immediately after a failed probe reopened the breaker, want false")
+	}
+}
+
+func TestLimiterBoundsConcurrency(t *testing.T) {
+	l := newLimiter(1)
+	ctx := newTestRequest(t).Context()
+	if err := l.acquire(ctx); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer l.release()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- l.acquire(ctx)
+	}()
+
+	select {
+	case <-done:
+		t.Fatalf("second acquire returned before the first was released, want it to block")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	l.release()
+	if err := <-done; err != nil {
+		t.Fatalf("acquire after release: %v", err)
+	}
+}
+
+func TestRetryAfterDelayParsesSecondsAndHTTPDate(t *testing.T) {
+	if d := retryAfterDelay(""); d != 0 {
+		t.Fatalf("retryAfterDelay(\"\") = %v, want 0", d)
+	}
+	if d := retryAfterDelay("5"); d != 5*time.Second {
+		t.Fatalf("retryAfterDelay(\"5\") = %v, want 5s", d)
+	}
+
+	future := time.Now().Add(time.Hour)
+	d := retryAfterDelay(future.UTC().Format(http.TimeFormat))
+	if d <= 0 || d > time.Hour {
+		t.Fatalf("retryAfterDelay(future HTTP-date) = %v, want a positive duration close to 1h", d)
+	}
+
+	if d := retryAfterDelay("not-a-valid-value"); d != 0 {
+		t.Fatalf("retryAfterDelay(garbage) = %v, want 0", d)
+	}
+}
+
+func TestRetryingRoundTripperRejectsWhenBreakerOpen(t *testing.T) {
+	b := newCircuitBreaker(1, time.Hour)
+	b.recordFailure() // trips it open
+
+	inner := &countingRoundTripper{fn: func(attempt int) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: http.Header{}}, nil
+	}}
+	rt := &retryingRoundTripper{
+		next:       inner,
+		limiter:    newLimiter(0),
+		breaker:    b,
+		maxRetries: 3,
+		maxElapsed: time.Second,
+	}
+
+	if _, err := rt.RoundTrip(newTestRequest(t)); err == nil {
+		t.Fatalf("RoundTrip with an open breaker returned nil error, want a rejection")
+	}
+	if inner.attempts != 0 {
+		t.Fatalf("attempts = %d, want 0 since the breaker should reject before any RoundTrip", inner.attempts)
+	}
+}
@@ -1,9 +1,12 @@
 package aviatrix
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
-// Gateway represents an Aviatrix gateway
-type Gateway struct {
+// ControllerGateway represents an Aviatrix gateway
+type ControllerGateway struct {
 	GatewayName string            `json:"gw_name"`
 	CloudType   int               `json:"cloud_type"`
 	AccountName string            `json:"account_name"`
@@ -17,8 +20,8 @@ type Gateway struct {
 	UpdatedAt   time.Time         `json:"updated_at"`
 }
 
-// TransitGateway represents an Aviatrix transit gateway
-type TransitGateway struct {
+// ControllerTransitGateway represents an Aviatrix transit gateway
+type ControllerTransitGateway struct {
 	GatewayName string `json:"gw_name"`
 	CloudType   int    `json:"cloud_type"`
 	AccountName string `json:"account_name"`
@@ -46,8 +49,8 @@ type TransitGateway struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-// SpokeGateway represents an Aviatrix spoke gateway
-type SpokeGateway struct {
+// ControllerSpokeGateway represents an Aviatrix spoke gateway
+type ControllerSpokeGateway struct {
 	GatewayName string `json:"gw_name"`
 	CloudType   int    `json:"cloud_type"`
 	AccountName string `json:"account_name"`
@@ -165,21 +168,86 @@ type CreateTransitGatewayRequest struct {
 	EnablePeeringOverPublicNetworkForPeeringHAGateway10 bool `json:"enable_peering_over_public_network_for_peering_ha_gateway_10"`
 }
 
+// CIDRState is a LearnedCIDR's place in the approval workflow.
+type CIDRState string
+
+const (
+	CIDRPending  CIDRState = "pending"
+	CIDRApproved CIDRState = "approved"
+	CIDRRejected CIDRState = "rejected"
+)
+
+// LearnedCIDR is one prefix a gateway learned over a BGP/peering session
+// and, when EnableLearnedCIDRsApproval is set, must be explicitly decided
+// on before it is advertised further. LearnedFrom names the peering or
+// neighbor the CIDR arrived over; DecidedBy/DecidedAt/Reason are set once
+// State leaves CIDRPending.
+type LearnedCIDR struct {
+	GatewayName string    `json:"gw_name"`
+	CIDR        string    `json:"cidr"`
+	LearnedFrom string    `json:"learned_from"`
+	State       CIDRState `json:"state"`
+	DecidedBy   string    `json:"decided_by,omitempty"`
+	DecidedAt   time.Time `json:"decided_at,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+}
+
 // AviatrixConfig represents Aviatrix configuration
 type AviatrixConfig struct {
 	ControllerIP string `json:"controller_ip"`
 	Username     string `json:"username"`
 	Password     string `json:"password"`
 	Region       string `json:"region"`
+
+	// PeeringSecret keys the HMAC that signs and verifies this
+	// controller's peering tokens. It must match on both controllers in
+	// a peering, since each verifies the token the other issued.
+	PeeringSecret string `json:"peering_secret"`
 }
 
 // AviatrixService represents the Aviatrix service interface
 type AviatrixService interface {
-	GetGateways(ctx context.Context) ([]Gateway, error)
-	GetTransitGateways(ctx context.Context) ([]TransitGateway, error)
-	GetSpokeGateways(ctx context.Context) ([]SpokeGateway, error)
+	GetGateways(ctx context.Context) ([]ControllerGateway, error)
+	GetTransitGateways(ctx context.Context) ([]ControllerTransitGateway, error)
+	GetSpokeGateways(ctx context.Context) ([]ControllerSpokeGateway, error)
 	GetTransitGatewayPeering(ctx context.Context) ([]TransitGatewayPeering, error)
 	GetSecurityDomains(ctx context.Context) ([]SecurityDomain, error)
 	GetFirewallPolicies(ctx context.Context) ([]FirewallPolicy, error)
 	GetMetrics(ctx context.Context, gatewayName, timeRange string) (*Metrics, error)
+
+	CreateTransitGateway(ctx context.Context, req *CreateTransitGatewayRequest) (*ControllerTransitGateway, error)
+	UpdateTransitGateway(ctx context.Context, gatewayName string, req *CreateTransitGatewayRequest) (*ControllerTransitGateway, error)
+	DeleteGateway(ctx context.Context, gatewayName string) error
+	CreateSpokeGateway(ctx context.Context, req *CreateTransitGatewayRequest) (*ControllerSpokeGateway, error)
+	AttachSpokeToTransit(ctx context.Context, spokeGateway, transitGateway string) error
+	CreateTransitGatewayPeering(ctx context.Context, sourceGateway, destinationGateway string) (*TransitGatewayPeering, error)
+	CreateFirewallPolicy(ctx context.Context, policy *FirewallPolicy) (*FirewallPolicy, error)
+	CreateSecurityDomain(ctx context.Context, domain *SecurityDomain) (*SecurityDomain, error)
+
+	// GeneratePeeringToken issues a signed token a remote controller can
+	// present to EstablishPeering to peer with localGateway, without
+	// either controller needing the other's credentials. remoteName is
+	// recorded for operator visibility only.
+	GeneratePeeringToken(ctx context.Context, localGateway, remoteName string) (string, error)
+	// EstablishPeering verifies token and peers localGateway with the
+	// transit gateway it names, honoring localGateway's
+	// EnableEncryptPeering, EnablePeeringOverPrivateNetwork, and
+	// LocalASNumber.
+	EstablishPeering(ctx context.Context, token, localGateway string) (*TransitGatewayPeering, error)
+	// TeardownPeering removes peeringName on this controller; the other
+	// side of the peering must be torn down separately.
+	TeardownPeering(ctx context.Context, peeringName string) error
+
+	// ListLearnedCIDRs lists gatewayName's learned CIDRs, optionally
+	// filtered to one CIDRState; pass "" for every state.
+	ListLearnedCIDRs(ctx context.Context, gatewayName string, stateFilter CIDRState) ([]LearnedCIDR, error)
+	// ApproveLearnedCIDR approves cidr on gatewayName, recording actor
+	// and reason.
+	ApproveLearnedCIDR(ctx context.Context, gatewayName, cidr, actor, reason string) (*LearnedCIDR, error)
+	// RejectLearnedCIDR rejects cidr on gatewayName, recording actor and
+	// reason.
+	RejectLearnedCIDR(ctx context.Context, gatewayName, cidr, actor, reason string) (*LearnedCIDR, error)
+	// BulkDecide applies state to every cidr in cidrs on gatewayName in
+	// one call.
+	BulkDecide(ctx context.Context, gatewayName string, cidrs []string, state CIDRState, actor, reason string) ([]LearnedCIDR, error)
 }
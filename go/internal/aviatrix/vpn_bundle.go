@@ -0,0 +1,137 @@
+package aviatrix
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// VPN client configuration formats GetVPNUserConfig accepts.
+const (
+	VPNFormatOpenVPN   = "openvpn"
+	VPNFormatWireGuard = "wireguard"
+)
+
+// Decode base64-decodes Bundle into the raw bytes the server packaged:
+// for VPNFormatOpenVPN, a zip containing the .ovpn profile, CA cert,
+// client cert/key, and TLS-auth key; for VPNFormatWireGuard, a single
+// wg-quick conf.
+func (cfg *VPNUserConfig) Decode() ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(cfg.Bundle)
+	if err != nil {
+		return nil, fmt.Errorf("aviatrix: decoding VPN bundle for %s: %w", cfg.UserID, err)
+	}
+	return data, nil
+}
+
+// Reader is Decode's result wrapped as an io.Reader, for callers that
+// want to stream the bundle rather than hold it as a []byte.
+func (cfg *VPNUserConfig) Reader() (io.Reader, error) {
+	data, err := cfg.Decode()
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+// WriteTo writes cfg's decoded bundle into dir: an openvpn bundle is
+// unzipped so its .ovpn/cert/key files land directly in dir, a
+// wireguard bundle is written as a single wg0.conf. dir is created if
+// it doesn't already exist.
+func (cfg *VPNUserConfig) WriteTo(dir string) error {
+	data, err := cfg.Decode()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("aviatrix: creating VPN config dir %q: %w", dir, err)
+	}
+
+	switch cfg.Format {
+	case VPNFormatWireGuard:
+		path := filepath.Join(dir, "wg0.conf")
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return fmt.Errorf("aviatrix: writing %q: %w", path, err)
+		}
+		return nil
+	case VPNFormatOpenVPN:
+		return writeZipBundle(dir, data)
+	default:
+		return fmt.Errorf("aviatrix: unsupported VPN config format %q", cfg.Format)
+	}
+}
+
+// writeZipBundle extracts every file in the zip archive data into dir,
+// flattening any directory structure the archive contains since an
+// openvpn bundle is a flat set of sibling files (profile, CA cert,
+// client cert/key, TLS-auth key).
+func writeZipBundle(dir string, data []byte) error {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("aviatrix: reading VPN bundle zip: %w", err)
+	}
+
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		src, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("aviatrix: opening %q in VPN bundle: %w", f.Name, err)
+		}
+
+		path := filepath.Join(dir, filepath.Base(f.Name))
+		dst, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+		if err != nil {
+			src.Close()
+			return fmt.Errorf("aviatrix: creating %q: %w", path, err)
+		}
+
+		_, copyErr := io.Copy(dst, src)
+		src.Close()
+		closeErr := dst.Close()
+		if copyErr != nil {
+			return fmt.Errorf("aviatrix: writing %q: %w", path, copyErr)
+		}
+		if closeErr != nil {
+			return fmt.Errorf("aviatrix: writing %q: %w", path, closeErr)
+		}
+	}
+	return nil
+}
+
+// ExportVPNUserBundle fetches userID's config in format and writes it
+// under dir, the single-user building block ExportAll uses for the
+// whole account.
+func (c *EnhancedClient) ExportVPNUserBundle(ctx context.Context, userID, format, dir string) error {
+	cfg, err := c.GetVPNUserConfig(ctx, userID, format)
+	if err != nil {
+		return err
+	}
+	return cfg.WriteTo(dir)
+}
+
+// ExportAll lists every VPN user on the account and writes each one's
+// config bundle to its own subdirectory of dir (named after the user's
+// ID), closing the gap where CreateVPNUser returns metadata but no
+// usable credentials.
+func (c *EnhancedClient) ExportAll(ctx context.Context, format, dir string) error {
+	users, err := c.ListVPNUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		userDir := filepath.Join(dir, user.ID)
+		if err := c.ExportVPNUserBundle(ctx, user.ID, format, userDir); err != nil {
+			return fmt.Errorf("aviatrix: exporting VPN config for user %s: %w", user.ID, err)
+		}
+	}
+	return nil
+}
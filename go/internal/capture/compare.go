@@ -0,0 +1,239 @@
+package capture
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+)
+
+// FiveTuple identifies a flow by its endpoints and protocol. Ephemeral
+// source ports are deliberately not part of the key: two captures of
+// the same conversation taken on different hosts, or replayed minutes
+// apart, rarely reuse the same ephemeral port, so keying on it would
+// treat one real flow as two. TTL/hop-limit and TCP sequence/ack
+// numbers are normalized the same way, by simply never being part of
+// the tuple or the per-flow stats below.
+type FiveTuple struct {
+	SrcIP    string `json:"src_ip"`
+	DstIP    string `json:"dst_ip"`
+	SrcPort  int    `json:"src_port"`
+	DstPort  int    `json:"dst_port"`
+	Protocol string `json:"protocol"`
+}
+
+// key canonicalizes t so the same conversation observed from either
+// direction (A->B or B->A) maps to the same flow, and so an ephemeral
+// client port doesn't fragment one flow into several.
+func (t FiveTuple) key() FiveTuple {
+	const ephemeralFloor = 32768
+	if t.SrcPort >= ephemeralFloor {
+		t.SrcPort = 0
+	}
+	if t.DstPort >= ephemeralFloor {
+		t.DstPort = 0
+	}
+	if t.SrcIP > t.DstIP || (t.SrcIP == t.DstIP && t.SrcPort > t.DstPort) {
+		t.SrcIP, t.DstIP = t.DstIP, t.SrcIP
+		t.SrcPort, t.DstPort = t.DstPort, t.SrcPort
+	}
+	return t
+}
+
+// FlowStats summarizes one flow's packets within a single capture
+// file, timestamped relative to that file's first packet so two
+// captures taken at different wall-clock times still compare cleanly.
+type FlowStats struct {
+	Tuple     FiveTuple     `json:"tuple"`
+	Packets   int           `json:"packets"`
+	Bytes     int64         `json:"bytes"`
+	FirstSeen time.Duration `json:"first_seen"`
+	LastSeen  time.Duration `json:"last_seen"`
+}
+
+// FlowDelta is one flow's counters compared between capture A and
+// capture B (B minus A).
+type FlowDelta struct {
+	Tuple        FiveTuple     `json:"tuple"`
+	PacketsDelta int           `json:"packets_delta"`
+	BytesDelta   int64         `json:"bytes_delta"`
+	LatencyDelta time.Duration `json:"latency_delta"`
+}
+
+// Diff is the structured result of comparing two pcap-ng files.
+type Diff struct {
+	OnlyInA           []FlowStats        `json:"only_in_a"`
+	OnlyInB           []FlowStats        `json:"only_in_b"`
+	PresentInBoth     []FlowDelta        `json:"present_in_both"`
+	SimilarityByProto map[string]float64 `json:"similarity_by_protocol"`
+}
+
+// Compare parses pathA and pathB, groups each into 5-tuple flows, and
+// returns the flows unique to each file, the flows present in both with
+// their counter deltas, and a per-protocol similarity score (the
+// fraction of each protocol's flows, by packet count, that match).
+func Compare(pathA, pathB string) (*Diff, error) {
+	flowsA, err := readFlows(pathA)
+	if err != nil {
+		return nil, fmt.Errorf("capture: reading %q: %w", pathA, err)
+	}
+	flowsB, err := readFlows(pathB)
+	if err != nil {
+		return nil, fmt.Errorf("capture: reading %q: %w", pathB, err)
+	}
+
+	diff := &Diff{SimilarityByProto: make(map[string]float64)}
+	protoPacketsA := make(map[string]int)
+	protoPacketsB := make(map[string]int)
+	protoMatched := make(map[string]int)
+
+	for key, a := range flowsA {
+		protoPacketsA[a.Tuple.Protocol] += a.Packets
+		b, ok := flowsB[key]
+		if !ok {
+			diff.OnlyInA = append(diff.OnlyInA, a)
+			continue
+		}
+		protoMatched[a.Tuple.Protocol] += min(a.Packets, b.Packets)
+		diff.PresentInBoth = append(diff.PresentInBoth, FlowDelta{
+			Tuple:        a.Tuple,
+			PacketsDelta: b.Packets - a.Packets,
+			BytesDelta:   b.Bytes - a.Bytes,
+			LatencyDelta: (b.LastSeen - b.FirstSeen) - (a.LastSeen - a.FirstSeen),
+		})
+	}
+	for key, b := range flowsB {
+		protoPacketsB[b.Tuple.Protocol] += b.Packets
+		if _, ok := flowsA[key]; !ok {
+			diff.OnlyInB = append(diff.OnlyInB, b)
+		}
+	}
+
+	protocols := make(map[string]struct{})
+	for p := range protoPacketsA {
+		protocols[p] = struct{}{}
+	}
+	for p := range protoPacketsB {
+		protocols[p] = struct{}{}
+	}
+	for p := range protocols {
+		total := protoPacketsA[p] + protoPacketsB[p]
+		if total == 0 {
+			diff.SimilarityByProto[p] = 1
+			continue
+		}
+		// 2x matched packets over the combined total is 1.0 when the two
+		// captures agree completely and 0 when they share nothing.
+		diff.SimilarityByProto[p] = float64(2*protoMatched[p]) / float64(total)
+	}
+
+	sortFlows(diff.OnlyInA)
+	sortFlows(diff.OnlyInB)
+	sort.Slice(diff.PresentInBoth, func(i, j int) bool {
+		return flowKeyString(diff.PresentInBoth[i].Tuple) < flowKeyString(diff.PresentInBoth[j].Tuple)
+	})
+
+	return diff, nil
+}
+
+func sortFlows(flows []FlowStats) {
+	sort.Slice(flows, func(i, j int) bool {
+		return flowKeyString(flows[i].Tuple) < flowKeyString(flows[j].Tuple)
+	})
+}
+
+func flowKeyString(t FiveTuple) string {
+	return fmt.Sprintf("%s|%s|%d|%d|%s", t.SrcIP, t.DstIP, t.SrcPort, t.DstPort, t.Protocol)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// readFlows parses path and groups its packets into FlowStats keyed by
+// canonicalized FiveTuple.
+func readFlows(path string) (map[FiveTuple]FlowStats, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader, err := pcapgo.NewNgReader(file, pcapgo.DefaultNgReaderOptions)
+	if err != nil {
+		return nil, fmt.Errorf("reading pcap-ng header: %w", err)
+	}
+
+	flows := make(map[FiveTuple]FlowStats)
+	var firstSeen time.Time
+
+	for {
+		data, ci, err := reader.ReadPacketData()
+		if err != nil {
+			break
+		}
+
+		if firstSeen.IsZero() {
+			firstSeen = ci.Timestamp
+		}
+		offset := ci.Timestamp.Sub(firstSeen)
+
+		tuple, ok := packetTuple(data, reader.LinkType())
+		if !ok {
+			continue
+		}
+		key := tuple.key()
+
+		stats, exists := flows[key]
+		if !exists {
+			stats = FlowStats{Tuple: key, FirstSeen: offset}
+		}
+		stats.Packets++
+		stats.Bytes += int64(ci.CaptureLength)
+		stats.LastSeen = offset
+		flows[key] = stats
+	}
+
+	return flows, nil
+}
+
+// packetTuple extracts a FiveTuple from one packet's network and
+// transport layers. It returns ok=false for packets with no IP layer
+// (ARP, etc.), which the flow diff simply skips.
+func packetTuple(data []byte, linkType layers.LinkType) (FiveTuple, bool) {
+	packet := gopacket.NewPacket(data, linkType, gopacket.DecodeOptions{Lazy: true, NoCopy: true})
+
+	var srcIP, dstIP string
+	switch layer := packet.NetworkLayer().(type) {
+	case *layers.IPv4:
+		srcIP, dstIP = layer.SrcIP.String(), layer.DstIP.String()
+	case *layers.IPv6:
+		srcIP, dstIP = layer.SrcIP.String(), layer.DstIP.String()
+	default:
+		return FiveTuple{}, false
+	}
+
+	tuple := FiveTuple{SrcIP: srcIP, DstIP: dstIP, Protocol: packet.NetworkLayer().LayerType().String()}
+
+	switch layer := packet.TransportLayer().(type) {
+	case *layers.TCP:
+		tuple.SrcPort, tuple.DstPort = int(layer.SrcPort), int(layer.DstPort)
+		tuple.Protocol = "TCP"
+	case *layers.UDP:
+		tuple.SrcPort, tuple.DstPort = int(layer.SrcPort), int(layer.DstPort)
+		tuple.Protocol = "UDP"
+	case *layers.ICMPv4:
+		tuple.Protocol = "ICMP"
+	case *layers.ICMPv6:
+		tuple.Protocol = "ICMPv6"
+	}
+
+	return tuple, true
+}
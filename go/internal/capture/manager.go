@@ -0,0 +1,236 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/pcap"
+	"github.com/google/gopacket/pcapgo"
+
+	"router-sim/internal/metrics"
+)
+
+// Manager owns every Capture this process has started, writing each
+// one's packets to rotating pcap-ng files under dir.
+type Manager struct {
+	dir         string
+	maxFileSize int64
+
+	mu       sync.Mutex
+	seq      int64
+	captures map[string]*Capture
+	cancel   map[string]context.CancelFunc
+}
+
+// NewManager creates a Manager writing capture files under dir, rolling
+// over to a new file once the current one reaches maxFileSize bytes (a
+// maxFileSize of 0 disables rotation).
+func NewManager(dir string, maxFileSize int64) (*Manager, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("capture: creating capture dir %q: %w", dir, err)
+	}
+
+	return &Manager{
+		dir:         dir,
+		maxFileSize: maxFileSize,
+		captures:    make(map[string]*Capture),
+		cancel:      make(map[string]context.CancelFunc),
+	}, nil
+}
+
+// Start opens a live capture on iface, compiles filter as a BPF
+// expression, and records packets to rotating pcap-ng files under the
+// Manager's directory until duration elapses or Stop(id) is called. A
+// duration of 0 captures until Stop is called.
+func (m *Manager) Start(iface, filter string, duration time.Duration) (*Capture, error) {
+	handle, err := pcap.OpenLive(iface, 65535, true, pcap.BlockForever)
+	if err != nil {
+		return nil, fmt.Errorf("capture: opening %q: %w", iface, err)
+	}
+
+	if filter != "" {
+		if err := handle.SetBPFFilter(filter); err != nil {
+			handle.Close()
+			return nil, fmt.Errorf("capture: compiling filter %q: %w", filter, err)
+		}
+	}
+
+	m.mu.Lock()
+	m.seq++
+	id := fmt.Sprintf("capture-%d", m.seq)
+	m.mu.Unlock()
+
+	c := &Capture{
+		ID:        id,
+		Interface: iface,
+		Filter:    filter,
+		Status:    StatusRunning,
+		StartedAt: time.Now(),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if duration > 0 {
+		ctx, cancel = context.WithTimeout(ctx, duration)
+	}
+
+	m.mu.Lock()
+	m.captures[id] = c
+	m.cancel[id] = cancel
+	m.mu.Unlock()
+
+	go m.run(ctx, c, handle)
+
+	return c, nil
+}
+
+// run drains handle's packet source into rotating pcap-ng files until
+// ctx is canceled or the handle errors, then marks c completed or
+// failed. handle is always closed before run returns.
+func (m *Manager) run(ctx context.Context, c *Capture, handle *pcap.Handle) {
+	defer handle.Close()
+
+	writer, file, err := m.openNextFile(c, handle)
+	if err != nil {
+		m.fail(c, err)
+		return
+	}
+
+	var fileBytes int64
+	source := gopacket.NewPacketSource(handle, handle.LinkType())
+	packets := source.Packets()
+	statsTick := time.NewTicker(time.Second)
+	defer statsTick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			file.Close()
+			m.complete(c)
+			return
+
+		case packet, ok := <-packets:
+			if !ok {
+				file.Close()
+				m.complete(c)
+				return
+			}
+
+			info := packet.Metadata().CaptureInfo
+			if err := writer.WritePacket(info, packet.Data()); err != nil {
+				file.Close()
+				m.fail(c, fmt.Errorf("capture: writing packet: %w", err))
+				return
+			}
+
+			m.mu.Lock()
+			c.BytesCaptured += int64(info.CaptureLength)
+			c.PacketsCaptured++
+			m.mu.Unlock()
+			fileBytes += int64(info.CaptureLength)
+
+			if m.maxFileSize > 0 && fileBytes >= m.maxFileSize {
+				file.Close()
+				writer, file, err = m.openNextFile(c, handle)
+				if err != nil {
+					m.fail(c, err)
+					return
+				}
+				fileBytes = 0
+			}
+
+		case <-statsTick.C:
+			if stats, err := handle.Stats(); err == nil && stats.PacketsDropped > 0 {
+				droppedPackets.WithLabelValues(c.Interface).Add(float64(stats.PacketsDropped))
+				metrics.PacketsDroppedTotal.Add(float64(stats.PacketsDropped))
+			}
+		}
+	}
+}
+
+// openNextFile rolls c over to a new pcap-ng file named
+// <id>-<sequence>.pcapng under the Manager's directory and writes its
+// section header.
+func (m *Manager) openNextFile(c *Capture, handle *pcap.Handle) (*pcapgo.NgWriter, *os.File, error) {
+	m.mu.Lock()
+	path := filepath.Join(m.dir, fmt.Sprintf("%s-%d.pcapng", c.ID, len(c.Files)+1))
+	m.mu.Unlock()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("capture: creating %q: %w", path, err)
+	}
+
+	writer, err := pcapgo.NewNgWriter(file, handle.LinkType())
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("capture: writing pcap-ng header for %q: %w", path, err)
+	}
+
+	m.mu.Lock()
+	c.Files = append(c.Files, path)
+	m.mu.Unlock()
+
+	return writer, file, nil
+}
+
+func (m *Manager) complete(c *Capture) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	c.Status = StatusCompleted
+	c.CompletedAt = &now
+	delete(m.cancel, c.ID)
+}
+
+func (m *Manager) fail(c *Capture, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	c.Status = StatusFailed
+	c.Error = err.Error()
+	c.CompletedAt = &now
+	delete(m.cancel, c.ID)
+}
+
+// Get returns the Capture named id.
+func (m *Manager) Get(id string) (*Capture, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.captures[id]
+	return c, ok
+}
+
+// List returns every Capture this Manager has started, most recent
+// first.
+func (m *Manager) List() []*Capture {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]*Capture, 0, len(m.captures))
+	for _, c := range m.captures {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].StartedAt.After(out[j].StartedAt)
+	})
+	return out
+}
+
+// Stop ends a running capture named id early, the same way its
+// duration elapsing would.
+func (m *Manager) Stop(id string) error {
+	m.mu.Lock()
+	cancel, ok := m.cancel[id]
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("capture: %q is not running", id)
+	}
+	cancel()
+	return nil
+}
@@ -0,0 +1,18 @@
+package capture
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// droppedPackets counts packets the capture device's kernel buffer
+// dropped before router-sim ever saw them, broken out by interface so a
+// single noisy NIC doesn't hide inside an aggregate counter.
+var droppedPackets = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "router_sim_capture_dropped_packets_total",
+		Help: "Packets dropped by the capture device before being written to disk.",
+	},
+	[]string{"interface"},
+)
+
+func init() {
+	prometheus.MustRegister(droppedPackets)
+}
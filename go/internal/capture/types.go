@@ -0,0 +1,37 @@
+package capture
+
+import "time"
+
+// Status is where a Capture currently sits in its lifecycle.
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// Capture is one packet-capture run: a live pcap handle recording to a
+// rotating set of pcap-ng files under a Manager's directory.
+type Capture struct {
+	ID              string     `json:"id"`
+	Interface       string     `json:"interface"`
+	Filter          string     `json:"filter"`
+	Status          Status     `json:"status"`
+	BytesCaptured   int64      `json:"bytes_captured"`
+	PacketsCaptured int64      `json:"packets_captured"`
+	Files           []string   `json:"files"`
+	StartedAt       time.Time  `json:"started_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	Error           string     `json:"error,omitempty"`
+}
+
+// LatestFile returns the most recently opened capture file, the one a
+// still-running capture is actively writing to, or "" if none was ever
+// opened.
+func (c *Capture) LatestFile() string {
+	if len(c.Files) == 0 {
+		return ""
+	}
+	return c.Files[len(c.Files)-1]
+}
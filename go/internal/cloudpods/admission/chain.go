@@ -0,0 +1,151 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"router-sim/internal/cloudpods/patch"
+)
+
+// Rule selects which requests a webhook applies to. An empty slice
+// matches anything, the same "unset means all" convention Kubernetes
+// uses for its admission webhook rules.
+type Rule struct {
+	Kinds      []string `yaml:"kinds"`
+	Verbs      []string `yaml:"verbs"`
+	Namespaces []string `yaml:"namespaces"`
+}
+
+func (r Rule) matches(req Request) bool {
+	return matchesAny(r.Kinds, req.Kind) && matchesAny(r.Verbs, string(req.Verb)) && matchesAny(r.Namespaces, req.Namespace)
+}
+
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// hook is the interface both MutatingWebhook and ValidatingWebhook
+// satisfy, letting registeredWebhook hold either without duplicating the
+// struct.
+type hook interface {
+	Admit(ctx context.Context, req Request) (Response, error)
+}
+
+type registeredWebhook struct {
+	name string
+	rule Rule
+	hook hook
+}
+
+// MutatingChain runs every registered mutating webhook whose Rule
+// matches a request, in registration order, applying each one's Patch to
+// the object before the next webhook sees it.
+type MutatingChain struct {
+	hooks []registeredWebhook
+}
+
+// NewMutatingChain creates an empty MutatingChain; use Register to add
+// webhooks, or BuildChains to construct one from YAML config.
+func NewMutatingChain() *MutatingChain {
+	return &MutatingChain{}
+}
+
+// Register adds hook under name to run against requests matching rule.
+func (c *MutatingChain) Register(name string, rule Rule, webhook MutatingWebhook) {
+	c.hooks = append(c.hooks, registeredWebhook{name: name, rule: rule, hook: webhook})
+}
+
+// Admit runs every matching webhook against req in order, folding each
+// one's returned Patch into req.Object before passing it to the next
+// webhook. It stops and returns the rejecting webhook's Response at the
+// first Allowed=false; otherwise it returns the final mutated request
+// with Allowed=true and every warning collected along the way.
+func (c *MutatingChain) Admit(ctx context.Context, req Request) (Request, Response, error) {
+	var warnings []string
+	for _, rw := range c.hooks {
+		if !rw.rule.matches(req) {
+			continue
+		}
+
+		resp, err := rw.hook.Admit(ctx, req)
+		if err != nil {
+			return req, Response{}, fmt.Errorf("admission: mutating webhook %q: %w", rw.name, err)
+		}
+		warnings = append(warnings, resp.Warnings...)
+		if !resp.Allowed {
+			resp.Warnings = warnings
+			return req, resp, nil
+		}
+
+		if len(resp.Patch) == 0 {
+			continue
+		}
+		objJSON, err := json.Marshal(req.Object)
+		if err != nil {
+			return req, Response{}, fmt.Errorf("admission: encoding object for webhook %q patch: %w", rw.name, err)
+		}
+		patched, err := patch.ApplyJSONPatch(objJSON, resp.Patch)
+		if err != nil {
+			return req, Response{}, fmt.Errorf("admission: applying webhook %q patch: %w", rw.name, err)
+		}
+		var mutated interface{}
+		if err := json.Unmarshal(patched, &mutated); err != nil {
+			return req, Response{}, fmt.Errorf("admission: decoding webhook %q patched object: %w", rw.name, err)
+		}
+		req.Object = mutated
+	}
+
+	return req, Response{Allowed: true, Warnings: warnings}, nil
+}
+
+// ValidatingChain runs every registered validating webhook whose Rule
+// matches a request, in registration order, stopping at the first
+// rejection.
+type ValidatingChain struct {
+	hooks []registeredWebhook
+}
+
+// NewValidatingChain creates an empty ValidatingChain; use Register to
+// add webhooks, or BuildChains to construct one from YAML config.
+func NewValidatingChain() *ValidatingChain {
+	return &ValidatingChain{}
+}
+
+// Register adds hook under name to run against requests matching rule.
+func (c *ValidatingChain) Register(name string, rule Rule, webhook ValidatingWebhook) {
+	c.hooks = append(c.hooks, registeredWebhook{name: name, rule: rule, hook: webhook})
+}
+
+// Admit runs every matching webhook against req in order and returns the
+// first rejection, or an Allowed Response once every webhook has passed.
+// Warnings from passing webhooks are still collected so a caller can
+// surface non-fatal warnings even on success.
+func (c *ValidatingChain) Admit(ctx context.Context, req Request) (Response, error) {
+	var warnings []string
+	for _, rw := range c.hooks {
+		if !rw.rule.matches(req) {
+			continue
+		}
+
+		resp, err := rw.hook.Admit(ctx, req)
+		if err != nil {
+			return Response{}, fmt.Errorf("admission: validating webhook %q: %w", rw.name, err)
+		}
+		warnings = append(warnings, resp.Warnings...)
+		if !resp.Allowed {
+			resp.Warnings = warnings
+			return resp, nil
+		}
+	}
+
+	return Response{Allowed: true, Warnings: warnings}, nil
+}
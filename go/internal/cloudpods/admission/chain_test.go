@@ -0,0 +1,191 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func allow(warnings ...string) (Response, error) {
+	return Response{Allowed: true, Warnings: warnings}, nil
+}
+
+func deny(field, message string) (Response, error) {
+	return Response{Allowed: false, Reasons: []Reason{{Field: field, Message: message}}}, nil
+}
+
+func TestRuleMatchesEmptyRuleMatchesAnything(t *testing.T) {
+	var r Rule
+	req := Request{Kind: "vpc", Verb: VerbCreate, Namespace: "default"}
+	if !r.matches(req) {
+		t.Fatalf("empty Rule did not match %+v, want it to match anything", req)
+	}
+}
+
+func TestRuleMatchesFiltersOnKindVerbNamespace(t *testing.T) {
+	r := Rule{Kinds: []string{"vpc"}, Verbs: []string{"create"}, Namespaces: []string{"prod"}}
+
+	if !r.matches(Request{Kind: "vpc", Verb: VerbCreate, Namespace: "prod"}) {
+		t.Fatalf("Rule did not match a request satisfying every field")
+	}
+	if r.matches(Request{Kind: "subnet", Verb: VerbCreate, Namespace: "prod"}) {
+		t.Fatalf("Rule matched a request with the wrong kind")
+	}
+	if r.matches(Request{Kind: "vpc", Verb: VerbDelete, Namespace: "prod"}) {
+		t.Fatalf("Rule matched a request with the wrong verb")
+	}
+	if r.matches(Request{Kind: "vpc", Verb: VerbCreate, Namespace: "dev"}) {
+		t.Fatalf("Rule matched a request with the wrong namespace")
+	}
+}
+
+func TestValidatingChainStopsAtFirstRejection(t *testing.T) {
+	c := NewValidatingChain()
+	var ran []string
+
+	c.Register("first", Rule{}, ValidatingWebhookFunc(func(ctx context.Context, req Request) (Response, error) {
+		ran = append(ran, "first")
+		return deny("name", "name is required")
+	}))
+	c.Register("second", Rule{}, ValidatingWebhookFunc(func(ctx context.Context, req Request) (Response, error) {
+		ran = append(ran, "second")
+		return allow()
+	}))
+
+	resp, err := c.Admit(context.Background(), Request{Kind: "vpc", Verb: VerbCreate})
+	if err != nil {
+		t.Fatalf("Admit: %v", err)
+	}
+	if resp.Allowed {
+		t.Fatalf("Admit().Allowed = true, want false since the first webhook rejected")
+	}
+	if len(ran) != 1 || ran[0] != "first" {
+		t.Fatalf("webhooks ran = %v, want only \"first\" to run after the rejection short-circuits the chain", ran)
+	}
+}
+
+func TestValidatingChainSkipsNonMatchingWebhooks(t *testing.T) {
+	c := NewValidatingChain()
+	var ran []string
+
+	c.Register("subnet-only", Rule{Kinds: []string{"subnet"}}, ValidatingWebhookFunc(func(ctx context.Context, req Request) (Response, error) {
+		ran = append(ran, "subnet-only")
+		return deny("x", "should never run")
+	}))
+	c.Register("any-kind", Rule{}, ValidatingWebhookFunc(func(ctx context.Context, req Request) (Response, error) {
+		ran = append(ran, "any-kind")
+		return allow()
+	}))
+
+	resp, err := c.Admit(context.Background(), Request{Kind: "vpc", Verb: VerbCreate})
+	if err != nil {
+		t.Fatalf("Admit: %v", err)
+	}
+	if !resp.Allowed {
+		t.Fatalf("Admit().Allowed = false, want true since the rejecting webhook's Rule doesn't match this request's kind")
+	}
+	if len(ran) != 1 || ran[0] != "any-kind" {
+		t.Fatalf("webhooks ran = %v, want only the kind-matching webhook to run", ran)
+	}
+}
+
+func TestValidatingChainCollectsWarningsAcrossPassingWebhooks(t *testing.T) {
+	c := NewValidatingChain()
+	c.Register("first", Rule{}, ValidatingWebhookFunc(func(ctx context.Context, req Request) (Response, error) {
+		return allow("warning one")
+	}))
+	c.Register("second", Rule{}, ValidatingWebhookFunc(func(ctx context.Context, req Request) (Response, error) {
+		return allow("warning two")
+	}))
+
+	resp, err := c.Admit(context.Background(), Request{Kind: "vpc", Verb: VerbCreate})
+	if err != nil {
+		t.Fatalf("Admit: %v", err)
+	}
+	if len(resp.Warnings) != 2 {
+		t.Fatalf("Warnings = %v, want both passing webhooks' warnings", resp.Warnings)
+	}
+}
+
+func TestValidatingChainPropagatesWebhookError(t *testing.T) {
+	c := NewValidatingChain()
+	wantErr := errors.New("webhook unreachable")
+	c.Register("broken", Rule{}, ValidatingWebhookFunc(func(ctx context.Context, req Request) (Response, error) {
+		return Response{}, wantErr
+	}))
+
+	_, err := c.Admit(context.Background(), Request{Kind: "vpc", Verb: VerbCreate})
+	if err == nil {
+		t.Fatalf("Admit returned nil error, want the webhook's error wrapped")
+	}
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("Admit error = %v, want it to wrap %v", err, wantErr)
+	}
+}
+
+func TestMutatingChainAppliesPatchBeforeNextWebhook(t *testing.T) {
+	c := NewMutatingChain()
+
+	// First webhook adds a "team" tag via JSON Patch; the second webhook
+	// only allows the request if that tag is already present, proving
+	// the patched object (not the original) is what it sees.
+	c.Register("tagger", Rule{}, MutatingWebhookFunc(func(ctx context.Context, req Request) (Response, error) {
+		return Response{
+			Allowed: true,
+			Patch:   []byte(`[{"op":"add","path":"/tags/team","value":"platform"}]`),
+		}, nil
+	}))
+	c.Register("require-tag", Rule{}, MutatingWebhookFunc(func(ctx context.Context, req Request) (Response, error) {
+		obj, ok := req.Object.(map[string]interface{})
+		if !ok {
+			return deny("object", "not an object")
+		}
+		tags, ok := obj["tags"].(map[string]interface{})
+		if !ok || tags["team"] != "platform" {
+			return deny("tags.team", "missing team tag")
+		}
+		return allow()
+	}))
+
+	req := Request{
+		Kind: "vpc", Verb: VerbCreate,
+		Object: map[string]interface{}{"tags": map[string]interface{}{}},
+	}
+	mutated, resp, err := c.Admit(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Admit: %v", err)
+	}
+	if !resp.Allowed {
+		t.Fatalf("Admit().Response.Allowed = false, want true; reasons: %+v", resp.Reasons)
+	}
+	obj := mutated.Object.(map[string]interface{})
+	tags := obj["tags"].(map[string]interface{})
+	if tags["team"] != "platform" {
+		t.Fatalf("mutated.Object = %+v, want the patch applied", mutated.Object)
+	}
+}
+
+func TestMutatingChainStopsAtFirstRejection(t *testing.T) {
+	c := NewMutatingChain()
+	var ran []string
+
+	c.Register("first", Rule{}, MutatingWebhookFunc(func(ctx context.Context, req Request) (Response, error) {
+		ran = append(ran, "first")
+		return deny("name", "name is required")
+	}))
+	c.Register("second", Rule{}, MutatingWebhookFunc(func(ctx context.Context, req Request) (Response, error) {
+		ran = append(ran, "second")
+		return allow()
+	}))
+
+	_, resp, err := c.Admit(context.Background(), Request{Kind: "vpc", Verb: VerbCreate})
+	if err != nil {
+		t.Fatalf("Admit: %v", err)
+	}
+	if resp.Allowed {
+		t.Fatalf("Admit().Response.Allowed = true, want false")
+	}
+	if len(ran) != 1 || ran[0] != "first" {
+		t.Fatalf("webhooks ran = %v, want only \"first\" to run", ran)
+	}
+}
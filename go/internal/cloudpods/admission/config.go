@@ -0,0 +1,141 @@
+package admission
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultWebhookTimeout bounds an external webhook call when its config
+// entry doesn't set its own Timeout.
+const defaultWebhookTimeout = 5 * time.Second
+
+// webhookConfig is one entry in Config.Mutating/Validating: either
+// Plugin names an in-process webhook previously registered with a
+// Registry, or URL points at an external HTTPS webhook — exactly one of
+// the two must be set.
+type webhookConfig struct {
+	Name    string        `yaml:"name"`
+	Rule    Rule          `yaml:"rule"`
+	Plugin  string        `yaml:"plugin,omitempty"`
+	URL     string        `yaml:"url,omitempty"`
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+func (wc webhookConfig) timeout() time.Duration {
+	if wc.Timeout > 0 {
+		return wc.Timeout
+	}
+	return defaultWebhookTimeout
+}
+
+// Config is a YAML-configured admission pipeline: which webhooks run on
+// the mutating chain, which run on the validating chain, and how each is
+// reached. Example:
+//
+//	mutating:
+//	  - name: default-tags
+//	    plugin: default-tags
+//	    rule: {kinds: [vpc, subnet], verbs: [create]}
+//	validating:
+//	  - name: cidr-policy
+//	    url: https://policy.example.com/admit
+//	    rule: {kinds: [vpc], verbs: [create, update]}
+//	    timeout: 3s
+type Config struct {
+	Mutating   []webhookConfig `yaml:"mutating"`
+	Validating []webhookConfig `yaml:"validating"`
+}
+
+// LoadConfig parses an admission pipeline configuration from YAML.
+func LoadConfig(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("admission: parsing config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Registry holds in-process Go webhooks by name, so YAML config can
+// reference a `plugin:` by name instead of only being able to reach
+// webhooks over HTTP.
+type Registry struct {
+	mutating   map[string]MutatingWebhook
+	validating map[string]ValidatingWebhook
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		mutating:   make(map[string]MutatingWebhook),
+		validating: make(map[string]ValidatingWebhook),
+	}
+}
+
+// RegisterMutating makes webhook reachable as `plugin: name` in a
+// mutating rule.
+func (r *Registry) RegisterMutating(name string, webhook MutatingWebhook) {
+	r.mutating[name] = webhook
+}
+
+// RegisterValidating makes webhook reachable as `plugin: name` in a
+// validating rule.
+func (r *Registry) RegisterValidating(name string, webhook ValidatingWebhook) {
+	r.validating[name] = webhook
+}
+
+// BuildChains wires cfg into a ready-to-use MutatingChain and
+// ValidatingChain, resolving each entry's `plugin:` against registry or
+// building an HTTPWebhook for its `url:`.
+func BuildChains(cfg *Config, registry *Registry) (*MutatingChain, *ValidatingChain, error) {
+	mutating := NewMutatingChain()
+	for _, wc := range cfg.Mutating {
+		webhook, err := resolveMutating(wc, registry)
+		if err != nil {
+			return nil, nil, err
+		}
+		mutating.Register(wc.Name, wc.Rule, webhook)
+	}
+
+	validating := NewValidatingChain()
+	for _, wc := range cfg.Validating {
+		webhook, err := resolveValidating(wc, registry)
+		if err != nil {
+			return nil, nil, err
+		}
+		validating.Register(wc.Name, wc.Rule, webhook)
+	}
+
+	return mutating, validating, nil
+}
+
+func resolveMutating(wc webhookConfig, registry *Registry) (MutatingWebhook, error) {
+	switch {
+	case wc.Plugin != "":
+		webhook, ok := registry.mutating[wc.Plugin]
+		if !ok {
+			return nil, fmt.Errorf("admission: webhook %q: no registered mutating plugin %q", wc.Name, wc.Plugin)
+		}
+		return webhook, nil
+	case wc.URL != "":
+		return NewHTTPWebhook(wc.URL, wc.timeout()), nil
+	default:
+		return nil, fmt.Errorf("admission: webhook %q: must set plugin or url", wc.Name)
+	}
+}
+
+func resolveValidating(wc webhookConfig, registry *Registry) (ValidatingWebhook, error) {
+	switch {
+	case wc.Plugin != "":
+		webhook, ok := registry.validating[wc.Plugin]
+		if !ok {
+			return nil, fmt.Errorf("admission: webhook %q: no registered validating plugin %q", wc.Name, wc.Plugin)
+		}
+		return webhook, nil
+	case wc.URL != "":
+		return NewHTTPWebhook(wc.URL, wc.timeout()), nil
+	default:
+		return nil, fmt.Errorf("admission: webhook %q: must set plugin or url", wc.Name)
+	}
+}
@@ -0,0 +1,71 @@
+package admission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// review is the AdmissionReview-style envelope posted to an external
+// HTTPS webhook and expected back, modeled on Kubernetes'
+// admission.k8s.io AdmissionReview wire format but trimmed to what
+// CloudPods needs.
+type review struct {
+	APIVersion string    `json:"apiVersion"`
+	Kind       string    `json:"kind"`
+	Request    *Request  `json:"request,omitempty"`
+	Response   *Response `json:"response,omitempty"`
+}
+
+// HTTPWebhook calls an external HTTPS admission webhook, posting an
+// AdmissionReview-style request and decoding the same envelope back. It
+// implements both MutatingWebhook and ValidatingWebhook since the wire
+// protocol doesn't distinguish the two — only which chain it's
+// registered on does.
+type HTTPWebhook struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewHTTPWebhook creates an HTTPWebhook posting to url, bounded by
+// timeout so one unreachable webhook can't hang a write indefinitely.
+func NewHTTPWebhook(url string, timeout time.Duration) *HTTPWebhook {
+	return &HTTPWebhook{URL: url, Client: &http.Client{Timeout: timeout}}
+}
+
+// Admit posts req to the webhook's URL as an AdmissionReview and returns
+// the decoded Response.
+func (w *HTTPWebhook) Admit(ctx context.Context, req Request) (Response, error) {
+	body, err := json.Marshal(review{APIVersion: "cloudpods/v1", Kind: "AdmissionReview", Request: &req})
+	if err != nil {
+		return Response{}, fmt.Errorf("admission: encoding admission review: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return Response{}, fmt.Errorf("admission: building webhook request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.Client.Do(httpReq)
+	if err != nil {
+		return Response{}, fmt.Errorf("admission: calling webhook %s: %w", w.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Response{}, fmt.Errorf("admission: webhook %s returned status %d", w.URL, resp.StatusCode)
+	}
+
+	var out review
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return Response{}, fmt.Errorf("admission: decoding webhook %s response: %w", w.URL, err)
+	}
+	if out.Response == nil {
+		return Response{}, fmt.Errorf("admission: webhook %s response missing %q", w.URL, "response")
+	}
+	return *out.Response, nil
+}
@@ -0,0 +1,88 @@
+// Package admission implements a Kubernetes-style admission pipeline in
+// front of CloudPodsHandler's write endpoints: a MutatingChain runs
+// before a write is persisted so webhooks can default or normalize the
+// request (set tags, round up a quota, normalize a CIDR), and a
+// ValidatingChain runs after mutation so webhooks see the final object
+// when enforcing policy (CIDR ranges, naming, cost limits). Either chain
+// can dispatch a matching request to an in-process Go plugin registered
+// by name or to an external HTTPS webhook, configured via YAML rules
+// matching {kind, verb, namespace}. This lets operators enforce policy
+// without forking CloudPodsHandler.
+package admission
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Verb is the operation being admitted, named after the CRUD verbs
+// CloudPodsHandler's write endpoints perform.
+type Verb string
+
+const (
+	VerbCreate Verb = "create"
+	VerbUpdate Verb = "update"
+	VerbDelete Verb = "delete"
+)
+
+// Request is what CloudPodsHandler submits to a chain for one write: the
+// resource kind ("vpc", "subnet", "natgateway", "loadbalancer",
+// "servicemeshroute"), the verb, the VPC it's scoped to (its
+// "namespace"), and the object being written. OldObject is set for
+// update and delete so a webhook can diff against what's being replaced
+// or removed.
+type Request struct {
+	Kind      string      `json:"kind"`
+	Verb      Verb        `json:"verb"`
+	Namespace string      `json:"namespace"`
+	Object    interface{} `json:"object,omitempty"`
+	OldObject interface{} `json:"oldObject,omitempty"`
+}
+
+// Reason is one entry in a rejection's structured status.reasons, naming
+// the field at fault the way a Kubernetes admission error does instead
+// of returning one opaque message.
+type Reason struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// Response is a single webhook's decision. Patch is a raw RFC 6902 JSON
+// Patch document a mutating webhook wants applied to Request.Object;
+// validating webhooks leave it nil since they only inspect, never
+// rewrite.
+type Response struct {
+	Allowed  bool            `json:"allowed"`
+	Patch    json.RawMessage `json:"patch,omitempty"`
+	Warnings []string        `json:"warnings,omitempty"`
+	Reasons  []Reason        `json:"reasons,omitempty"`
+}
+
+// MutatingWebhook may rewrite Request.Object before it's persisted, by
+// returning a JSON Patch in Response.Patch. Returning Allowed=false stops
+// the request the same as a validating rejection would.
+type MutatingWebhook interface {
+	Admit(ctx context.Context, req Request) (Response, error)
+}
+
+// ValidatingWebhook inspects (but never rewrites) the final, mutated
+// Request.Object and either allows it or rejects it with Reasons.
+type ValidatingWebhook interface {
+	Admit(ctx context.Context, req Request) (Response, error)
+}
+
+// MutatingWebhookFunc adapts a plain function to a MutatingWebhook.
+type MutatingWebhookFunc func(ctx context.Context, req Request) (Response, error)
+
+// Admit calls f.
+func (f MutatingWebhookFunc) Admit(ctx context.Context, req Request) (Response, error) {
+	return f(ctx, req)
+}
+
+// ValidatingWebhookFunc adapts a plain function to a ValidatingWebhook.
+type ValidatingWebhookFunc func(ctx context.Context, req Request) (Response, error)
+
+// Admit calls f.
+func (f ValidatingWebhookFunc) Admit(ctx context.Context, req Request) (Response, error) {
+	return f(ctx, req)
+}
@@ -0,0 +1,216 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+)
+
+// vmFields is the subset of a CloudPodsVM the built-in VM admitters below
+// care about. Decoding into it (instead of importing the cloudpods
+// package's own type) keeps admission free of a cloudpods import and
+// works whether Request.Object is still the caller's original struct or
+// has already been round-tripped through a JSON Patch by an earlier
+// mutating webhook.
+type vmFields struct {
+	Name           string            `json:"name"`
+	VPC            string            `json:"vpc"`
+	Subnet         string            `json:"subnet"`
+	PrivateIP      string            `json:"private_ip"`
+	SecurityGroups []string          `json:"security_groups"`
+	CPU            int               `json:"cpu"`
+	Memory         int               `json:"memory"`
+	Tags           map[string]string `json:"tags"`
+}
+
+func decodeVMFields(obj interface{}) (vmFields, error) {
+	var f vmFields
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return f, fmt.Errorf("admission: encoding object: %w", err)
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("admission: decoding object: %w", err)
+	}
+	return f, nil
+}
+
+// SubnetCIDRSource looks up the CIDR of a VPC's subnet, letting
+// NewSubnetCIDRValidator check a VM's PrivateIP without admission
+// importing the cloudpods package itself; an InformerFactory's
+// SubnetLister satisfies this structurally.
+type SubnetCIDRSource interface {
+	SubnetCIDR(vpcID, subnetID string) (cidr string, ok bool)
+}
+
+// NewSubnetCIDRValidator rejects a VM create/update whose PrivateIP
+// doesn't fall inside the CIDR of the subnet it claims to be attached to.
+func NewSubnetCIDRValidator(source SubnetCIDRSource) ValidatingWebhook {
+	return ValidatingWebhookFunc(func(_ context.Context, req Request) (Response, error) {
+		f, err := decodeVMFields(req.Object)
+		if err != nil {
+			return Response{}, err
+		}
+		if f.PrivateIP == "" || f.Subnet == "" {
+			return Response{Allowed: true}, nil
+		}
+
+		cidr, ok := source.SubnetCIDR(req.Namespace, f.Subnet)
+		if !ok {
+			return Response{Allowed: false, Reasons: []Reason{
+				{Field: "subnet", Message: fmt.Sprintf("subnet %q not found in vpc %q", f.Subnet, req.Namespace)},
+			}}, nil
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return Response{Allowed: false, Reasons: []Reason{
+				{Field: "subnet", Message: fmt.Sprintf("subnet %q has invalid CIDR %q", f.Subnet, cidr)},
+			}}, nil
+		}
+		ip := net.ParseIP(f.PrivateIP)
+		if ip == nil || !ipNet.Contains(ip) {
+			return Response{Allowed: false, Reasons: []Reason{
+				{Field: "private_ip", Message: fmt.Sprintf("private_ip %q is not within subnet %q's CIDR %s", f.PrivateIP, f.Subnet, cidr)},
+			}}, nil
+		}
+		return Response{Allowed: true}, nil
+	})
+}
+
+// SecurityGroupExistsSource reports whether a security group id is real,
+// letting NewSecurityGroupExistsValidator catch a VM referencing one that
+// was deleted or never existed.
+type SecurityGroupExistsSource interface {
+	SecurityGroupExists(vpcID, id string) bool
+}
+
+// NewSecurityGroupExistsValidator rejects a VM referencing a security
+// group source doesn't know about.
+func NewSecurityGroupExistsValidator(source SecurityGroupExistsSource) ValidatingWebhook {
+	return ValidatingWebhookFunc(func(_ context.Context, req Request) (Response, error) {
+		f, err := decodeVMFields(req.Object)
+		if err != nil {
+			return Response{}, err
+		}
+		for _, sg := range f.SecurityGroups {
+			if !source.SecurityGroupExists(req.Namespace, sg) {
+				return Response{Allowed: false, Reasons: []Reason{
+					{Field: "security_groups", Message: fmt.Sprintf("security group %q does not exist in vpc %q", sg, req.Namespace)},
+				}}, nil
+			}
+		}
+		return Response{Allowed: true}, nil
+	})
+}
+
+// TenantUsageSource reports a tenant's already-provisioned CPU/memory
+// across its existing VMs, so NewQuotaValidator can add the object being
+// admitted and compare the total against QuotaLimits.
+type TenantUsageSource interface {
+	TenantUsage(tenant string) (cpu, memory int)
+}
+
+// QuotaLimits caps the total CPU/memory a tenant (identified by its
+// "tenant" tag) may provision across every VM. A zero field means that
+// dimension isn't limited.
+type QuotaLimits struct {
+	CPU    int
+	Memory int
+}
+
+// NewQuotaValidator rejects a VM create/update that would push its
+// tenant's CPU or memory total over limits.
+func NewQuotaValidator(source TenantUsageSource, limits QuotaLimits) ValidatingWebhook {
+	return ValidatingWebhookFunc(func(_ context.Context, req Request) (Response, error) {
+		f, err := decodeVMFields(req.Object)
+		if err != nil {
+			return Response{}, err
+		}
+		tenant := f.Tags["tenant"]
+		if tenant == "" {
+			return Response{Allowed: true}, nil
+		}
+
+		cpuUsed, memUsed := source.TenantUsage(tenant)
+		var reasons []Reason
+		if limits.CPU > 0 && cpuUsed+f.CPU > limits.CPU {
+			reasons = append(reasons, Reason{Field: "cpu", Message: fmt.Sprintf("tenant %q cpu quota exceeded: %d + %d > %d", tenant, cpuUsed, f.CPU, limits.CPU)})
+		}
+		if limits.Memory > 0 && memUsed+f.Memory > limits.Memory {
+			reasons = append(reasons, Reason{Field: "memory", Message: fmt.Sprintf("tenant %q memory quota exceeded: %d + %d > %d", tenant, memUsed, f.Memory, limits.Memory)})
+		}
+		if len(reasons) > 0 {
+			return Response{Allowed: false, Reasons: reasons}, nil
+		}
+		return Response{Allowed: true}, nil
+	})
+}
+
+// NameCollisionSource lists the names already in use in a VPC, for
+// NewNameCollisionValidator to check a create against.
+type NameCollisionSource interface {
+	NamesInVPC(vpcID string) []string
+}
+
+// NewNameCollisionValidator rejects creating a VM whose name is already
+// taken by another VM in the same VPC. It only runs on create: renaming
+// an existing VM to a taken name is still possible today, matching
+// CloudPods' own lack of a rename endpoint.
+func NewNameCollisionValidator(source NameCollisionSource) ValidatingWebhook {
+	return ValidatingWebhookFunc(func(_ context.Context, req Request) (Response, error) {
+		if req.Verb != VerbCreate {
+			return Response{Allowed: true}, nil
+		}
+		f, err := decodeVMFields(req.Object)
+		if err != nil {
+			return Response{}, err
+		}
+		for _, name := range source.NamesInVPC(req.Namespace) {
+			if name == f.Name {
+				return Response{Allowed: false, Reasons: []Reason{
+					{Field: "name", Message: fmt.Sprintf("name %q already in use in vpc %q", f.Name, req.Namespace)},
+				}}, nil
+			}
+		}
+		return Response{Allowed: true}, nil
+	})
+}
+
+// NewTagDefaulter returns a mutating webhook that auto-injects
+// "created-by" and "env" tags, when not already set, via a JSON Patch —
+// the same pattern Kubernetes' namespace-default-label webhooks use to
+// default labels a caller didn't specify.
+func NewTagDefaulter(createdBy, env string) MutatingWebhook {
+	return MutatingWebhookFunc(func(_ context.Context, req Request) (Response, error) {
+		f, err := decodeVMFields(req.Object)
+		if err != nil {
+			return Response{}, err
+		}
+
+		missing := map[string]string{}
+		if _, ok := f.Tags["created-by"]; !ok {
+			missing["created-by"] = createdBy
+		}
+		if _, ok := f.Tags["env"]; !ok {
+			missing["env"] = env
+		}
+		if len(missing) == 0 {
+			return Response{Allowed: true}, nil
+		}
+
+		var ops []map[string]interface{}
+		if f.Tags == nil {
+			ops = append(ops, map[string]interface{}{"op": "add", "path": "/tags", "value": missing})
+		} else {
+			for k, v := range missing {
+				ops = append(ops, map[string]interface{}{"op": "add", "path": "/tags/" + k, "value": v})
+			}
+		}
+		patchDoc, err := json.Marshal(ops)
+		if err != nil {
+			return Response{}, fmt.Errorf("admission: encoding tag patch: %w", err)
+		}
+		return Response{Allowed: true, Patch: patchDoc}, nil
+	})
+}
@@ -0,0 +1,391 @@
+package cloudpods
+
+import (
+	"context"
+	"fmt"
+
+	"router-sim/internal/cloudpods/garbagecollector"
+)
+
+// ApplyVPCRequest is the full desired-state document for POST
+// /vpcs/apply: a VPC plus every subnet, NAT gateway, load balancer, and
+// service-mesh route nested under it. ApplyVPC diffs this against
+// current state and issues the minimal set of create/update/delete calls
+// to converge, the way `kubectl apply -f` reconciles a manifest against a
+// cluster instead of requiring the caller to compute the diff itself.
+//
+// Resources are matched by Name rather than ID, since a desired-state
+// document describing a not-yet-created resource has no ID to match on.
+type ApplyVPCRequest struct {
+	Name            string `json:"name"`
+	CIDR            string `json:"cidr"`
+	ResourceVersion uint64 `json:"resourceVersion,omitempty"`
+
+	Subnets           []ApplySubnetRequest           `json:"subnets,omitempty"`
+	NATGateways       []ApplyNATGatewayRequest       `json:"natGateways,omitempty"`
+	LoadBalancers     []ApplyLoadBalancerRequest     `json:"loadBalancers,omitempty"`
+	ServiceMeshRoutes []ApplyServiceMeshRouteRequest `json:"serviceMeshRoutes,omitempty"`
+}
+
+// ApplySubnetRequest is one entry in ApplyVPCRequest.Subnets.
+type ApplySubnetRequest struct {
+	Name            string `json:"name"`
+	CIDR            string `json:"cidr"`
+	ResourceVersion uint64 `json:"resourceVersion,omitempty"`
+}
+
+// ApplyNATGatewayRequest is one entry in ApplyVPCRequest.NATGateways. NAT
+// gateways have no update operation, so reconcile only ever creates or
+// deletes them, matching how DeleteNATGateway/ListNATGateways already
+// have no UpdateNATGateway counterpart.
+type ApplyNATGatewayRequest struct {
+	Name string `json:"name"`
+}
+
+// ApplyLoadBalancerRequest is one entry in ApplyVPCRequest.LoadBalancers.
+type ApplyLoadBalancerRequest struct {
+	Name            string   `json:"name"`
+	Listeners       []string `json:"listeners,omitempty"`
+	ResourceVersion uint64   `json:"resourceVersion,omitempty"`
+}
+
+// ApplyServiceMeshRouteRequest is one entry in
+// ApplyVPCRequest.ServiceMeshRoutes.
+type ApplyServiceMeshRouteRequest struct {
+	Name            string `json:"name"`
+	Match           string `json:"match,omitempty"`
+	ResourceVersion uint64 `json:"resourceVersion,omitempty"`
+}
+
+// ApplyChange records one create/update/delete the reconcile performed,
+// so the caller (and CloudPodsHandler, which republishes it on the event
+// bus) can see exactly what Apply did instead of re-deriving it from the
+// before/after state.
+type ApplyChange struct {
+	Kind   string      `json:"kind"`
+	Type   EventType   `json:"type"`
+	Object interface{} `json:"object"`
+}
+
+// ApplyResult is ApplyVPC's response: the converged VPC plus every change
+// applied to reach it.
+type ApplyResult struct {
+	VPC     *CloudPodsVPC `json:"vpc"`
+	Changes []ApplyChange `json:"changes"`
+}
+
+// ApplyVPC reconciles a desired-state VPC document against current
+// state. It matches the VPC and each nested resource kind by Name,
+// creates what's missing, updates what changed, and deletes what's no
+// longer present in the desired document, in that order so a create or
+// update never races a delete of something it still depends on.
+//
+// Optimistic concurrency: a non-zero ResourceVersion on the VPC or any
+// nested resource must match the server's current value, checked before
+// that resource's operation runs, so a caller racing another apply gets
+// ErrResourceVersionConflict instead of a silently clobbered write.
+//
+// This is best-effort transactional: if a create fails partway through,
+// every resource created earlier in this call is rolled back by
+// deleting it; a failure during an update or a delete is not rolled
+// back, since undoing an in-place update or a delete isn't generally
+// possible without remembering the entire prior state. The returned
+// error wraps whichever operation failed.
+func (c *Client) ApplyVPC(ctx context.Context, req ApplyVPCRequest) (result *ApplyResult, err error) {
+	vpcs, _, err := c.ListVPCs(ctx, ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing vpcs: %w", err)
+	}
+
+	var current *CloudPodsVPC
+	for i := range vpcs {
+		if vpcs[i].Name == req.Name {
+			current = &vpcs[i]
+			break
+		}
+	}
+	if current != nil {
+		if cerr := checkResourceVersion("vpc", current.ID, req.ResourceVersion, current.ResourceVersion); cerr != nil {
+			return nil, cerr
+		}
+	}
+
+	result = &ApplyResult{}
+
+	// compensate holds one rollback closure per resource created in this
+	// call, in creation order; a later failure unwinds them newest-first.
+	var compensate []func(context.Context)
+	defer func() {
+		if err != nil {
+			for i := len(compensate) - 1; i >= 0; i-- {
+				compensate[i](context.Background())
+			}
+		}
+	}()
+
+	var vpc *CloudPodsVPC
+	switch {
+	case current == nil:
+		vpc, err = c.CreateVPC(ctx, CreateVPCRequest{Name: req.Name, CIDR: req.CIDR})
+		if err != nil {
+			return nil, fmt.Errorf("creating vpc %q: %w", req.Name, err)
+		}
+		id := vpc.ID
+		compensate = append(compensate, func(ctx context.Context) { _ = c.DeleteVPC(ctx, id) })
+		c.events.Publish("vpc", vpc.ID, EventAdded, vpc)
+		result.Changes = append(result.Changes, ApplyChange{Kind: "vpc", Type: EventAdded, Object: vpc})
+	case current.CIDR != req.CIDR:
+		vpc, err = c.UpdateVPC(ctx, current.ID, UpdateVPCRequest{CIDR: req.CIDR})
+		if err != nil {
+			return nil, fmt.Errorf("updating vpc %q: %w", req.Name, err)
+		}
+		c.events.Publish("vpc", vpc.ID, EventModified, vpc)
+		result.Changes = append(result.Changes, ApplyChange{Kind: "vpc", Type: EventModified, Object: vpc})
+	default:
+		vpc = current
+	}
+	result.VPC = vpc
+
+	if err = c.applySubnets(ctx, vpc.ID, req.Subnets, result, &compensate); err != nil {
+		return nil, err
+	}
+	if err = c.applyNATGateways(ctx, vpc.ID, req.NATGateways, result, &compensate); err != nil {
+		return nil, err
+	}
+	if err = c.applyLoadBalancers(ctx, vpc.ID, req.LoadBalancers, result, &compensate); err != nil {
+		return nil, err
+	}
+	if err = c.applyServiceMeshRoutes(ctx, vpc.ID, req.ServiceMeshRoutes, result, &compensate); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// applySubnets reconciles desired against vpcID's current subnets,
+// matching by Name: creates what's missing, updates a changed CIDR, and
+// deletes what's no longer desired.
+func (c *Client) applySubnets(ctx context.Context, vpcID string, desired []ApplySubnetRequest, result *ApplyResult, compensate *[]func(context.Context)) error {
+	current, _, err := c.ListSubnets(ctx, vpcID, ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing subnets: %w", err)
+	}
+
+	byName := make(map[string]CloudPodsSubnet, len(current))
+	for _, s := range current {
+		byName[s.Name] = s
+	}
+
+	seen := make(map[string]bool, len(desired))
+	for _, d := range desired {
+		seen[d.Name] = true
+
+		existing, ok := byName[d.Name]
+		if !ok {
+			subnet, err := c.CreateSubnet(ctx, vpcID, CreateSubnetRequest{Name: d.Name, CIDR: d.CIDR})
+			if err != nil {
+				return fmt.Errorf("creating subnet %q: %w", d.Name, err)
+			}
+			id := subnet.ID
+			*compensate = append(*compensate, func(ctx context.Context) { _ = c.DeleteSubnet(ctx, vpcID, id) })
+			c.events.Publish("subnet", vpcID, EventAdded, subnet)
+			result.Changes = append(result.Changes, ApplyChange{Kind: "subnet", Type: EventAdded, Object: subnet})
+			continue
+		}
+
+		if err := checkResourceVersion("subnet", existing.ID, d.ResourceVersion, existing.ResourceVersion); err != nil {
+			return err
+		}
+		if existing.CIDR == d.CIDR {
+			continue
+		}
+		subnet, err := c.UpdateSubnet(ctx, vpcID, existing.ID, UpdateSubnetRequest{CIDR: d.CIDR})
+		if err != nil {
+			return fmt.Errorf("updating subnet %q: %w", d.Name, err)
+		}
+		c.events.Publish("subnet", vpcID, EventModified, subnet)
+		result.Changes = append(result.Changes, ApplyChange{Kind: "subnet", Type: EventModified, Object: subnet})
+	}
+
+	for _, s := range current {
+		if seen[s.Name] {
+			continue
+		}
+		if err := c.gc.Delete(ctx, "subnet", vpcID, s.ID, garbagecollector.PropagationBackground); err != nil {
+			return fmt.Errorf("deleting subnet %q: %w", s.Name, err)
+		}
+		result.Changes = append(result.Changes, ApplyChange{Kind: "subnet", Type: EventDeleted, Object: map[string]string{"id": s.ID}})
+	}
+
+	return nil
+}
+
+// applyNATGateways reconciles desired against vpcID's current NAT
+// gateways, matching by Name. NAT gateways have no update operation, so
+// this only ever creates or deletes.
+func (c *Client) applyNATGateways(ctx context.Context, vpcID string, desired []ApplyNATGatewayRequest, result *ApplyResult, compensate *[]func(context.Context)) error {
+	current, _, err := c.ListNATGateways(ctx, vpcID, ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing nat gateways: %w", err)
+	}
+
+	byName := make(map[string]CloudPodsNATGateway, len(current))
+	for _, n := range current {
+		byName[n.Name] = n
+	}
+
+	seen := make(map[string]bool, len(desired))
+	for _, d := range desired {
+		seen[d.Name] = true
+		if _, ok := byName[d.Name]; ok {
+			continue
+		}
+
+		nat, err := c.CreateNATGateway(ctx, vpcID, CreateNATGatewayRequest{Name: d.Name})
+		if err != nil {
+			return fmt.Errorf("creating nat gateway %q: %w", d.Name, err)
+		}
+		id := nat.ID
+		*compensate = append(*compensate, func(ctx context.Context) { _ = c.DeleteNATGateway(ctx, vpcID, id) })
+		c.events.Publish("natgateway", vpcID, EventAdded, nat)
+		result.Changes = append(result.Changes, ApplyChange{Kind: "natgateway", Type: EventAdded, Object: nat})
+	}
+
+	for _, n := range current {
+		if seen[n.Name] {
+			continue
+		}
+		if err := c.gc.Delete(ctx, "natgateway", vpcID, n.ID, garbagecollector.PropagationBackground); err != nil {
+			return fmt.Errorf("deleting nat gateway %q: %w", n.Name, err)
+		}
+		result.Changes = append(result.Changes, ApplyChange{Kind: "natgateway", Type: EventDeleted, Object: map[string]string{"id": n.ID}})
+	}
+
+	return nil
+}
+
+// applyLoadBalancers reconciles desired against vpcID's current load
+// balancers, matching by Name.
+func (c *Client) applyLoadBalancers(ctx context.Context, vpcID string, desired []ApplyLoadBalancerRequest, result *ApplyResult, compensate *[]func(context.Context)) error {
+	current, _, err := c.ListLoadBalancers(ctx, vpcID, ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing load balancers: %w", err)
+	}
+
+	byName := make(map[string]CloudPodsLoadBalancer, len(current))
+	for _, lb := range current {
+		byName[lb.Name] = lb
+	}
+
+	seen := make(map[string]bool, len(desired))
+	for _, d := range desired {
+		seen[d.Name] = true
+
+		existing, ok := byName[d.Name]
+		if !ok {
+			lb, err := c.CreateLoadBalancer(ctx, vpcID, CreateLoadBalancerRequest{Name: d.Name, Listeners: d.Listeners})
+			if err != nil {
+				return fmt.Errorf("creating load balancer %q: %w", d.Name, err)
+			}
+			id := lb.ID
+			*compensate = append(*compensate, func(ctx context.Context) { _ = c.DeleteLoadBalancer(ctx, vpcID, id) })
+			c.events.Publish("loadbalancer", vpcID, EventAdded, lb)
+			result.Changes = append(result.Changes, ApplyChange{Kind: "loadbalancer", Type: EventAdded, Object: lb})
+			continue
+		}
+
+		if err := checkResourceVersion("loadbalancer", existing.ID, d.ResourceVersion, existing.ResourceVersion); err != nil {
+			return err
+		}
+		if stringSlicesEqual(existing.Listeners, d.Listeners) {
+			continue
+		}
+		lb, err := c.UpdateLoadBalancer(ctx, vpcID, existing.ID, UpdateLoadBalancerRequest{Listeners: d.Listeners})
+		if err != nil {
+			return fmt.Errorf("updating load balancer %q: %w", d.Name, err)
+		}
+		c.events.Publish("loadbalancer", vpcID, EventModified, lb)
+		result.Changes = append(result.Changes, ApplyChange{Kind: "loadbalancer", Type: EventModified, Object: lb})
+	}
+
+	for _, lb := range current {
+		if seen[lb.Name] {
+			continue
+		}
+		if err := c.gc.Delete(ctx, "loadbalancer", vpcID, lb.ID, garbagecollector.PropagationBackground); err != nil {
+			return fmt.Errorf("deleting load balancer %q: %w", lb.Name, err)
+		}
+		result.Changes = append(result.Changes, ApplyChange{Kind: "loadbalancer", Type: EventDeleted, Object: map[string]string{"id": lb.ID}})
+	}
+
+	return nil
+}
+
+// applyServiceMeshRoutes reconciles desired against vpcID's current
+// service-mesh routes, matching by Name.
+func (c *Client) applyServiceMeshRoutes(ctx context.Context, vpcID string, desired []ApplyServiceMeshRouteRequest, result *ApplyResult, compensate *[]func(context.Context)) error {
+	current, _, err := c.ListServiceMeshRoutes(ctx, vpcID, ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing service mesh routes: %w", err)
+	}
+
+	byName := make(map[string]CloudPodsServiceMeshRoute, len(current))
+	for _, r := range current {
+		byName[r.Name] = r
+	}
+
+	seen := make(map[string]bool, len(desired))
+	for _, d := range desired {
+		seen[d.Name] = true
+
+		existing, ok := byName[d.Name]
+		if !ok {
+			route, err := c.CreateServiceMeshRoute(ctx, vpcID, CreateServiceMeshRouteRequest{Name: d.Name, Match: d.Match})
+			if err != nil {
+				return fmt.Errorf("creating service mesh route %q: %w", d.Name, err)
+			}
+			id := route.ID
+			*compensate = append(*compensate, func(ctx context.Context) { _ = c.DeleteServiceMeshRoute(ctx, vpcID, id) })
+			c.events.Publish("servicemeshroute", vpcID, EventAdded, route)
+			result.Changes = append(result.Changes, ApplyChange{Kind: "servicemeshroute", Type: EventAdded, Object: route})
+			continue
+		}
+
+		if err := checkResourceVersion("servicemeshroute", existing.ID, d.ResourceVersion, existing.ResourceVersion); err != nil {
+			return err
+		}
+		if existing.Match == d.Match {
+			continue
+		}
+		route, err := c.UpdateServiceMeshRoute(ctx, vpcID, existing.ID, UpdateServiceMeshRouteRequest{Match: d.Match})
+		if err != nil {
+			return fmt.Errorf("updating service mesh route %q: %w", d.Name, err)
+		}
+		c.events.Publish("servicemeshroute", vpcID, EventModified, route)
+		result.Changes = append(result.Changes, ApplyChange{Kind: "servicemeshroute", Type: EventModified, Object: route})
+	}
+
+	for _, r := range current {
+		if seen[r.Name] {
+			continue
+		}
+		if err := c.gc.Delete(ctx, "servicemeshroute", vpcID, r.ID, garbagecollector.PropagationBackground); err != nil {
+			return fmt.Errorf("deleting service mesh route %q: %w", r.Name, err)
+		}
+		result.Changes = append(result.Changes, ApplyChange{Kind: "servicemeshroute", Type: EventDeleted, Object: map[string]string{"id": r.ID}})
+	}
+
+	return nil
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,218 @@
+package cloudpods
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Authenticator attaches credentials to an outgoing request. Client calls
+// Authenticate before every attempt and ForceRefresh once, if a request
+// comes back 401, before retrying — letting a token-based implementation
+// discard whatever credential the server just rejected instead of reusing
+// it forever.
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *http.Request) error
+	ForceRefresh()
+}
+
+// BasicAuthenticator is the original CloudPods auth model: a static
+// username/password sent as an HTTP Basic header on every request. It has
+// nothing to refresh, so ForceRefresh is a no-op.
+type BasicAuthenticator struct {
+	Username string
+	Password string
+}
+
+func (a *BasicAuthenticator) Authenticate(_ context.Context, req *http.Request) error {
+	req.SetBasicAuth(a.Username, a.Password)
+	return nil
+}
+
+func (a *BasicAuthenticator) ForceRefresh() {}
+
+// tokenExpirySkew is subtracted from a Keystone token's reported expiry so
+// a request started just before the real deadline doesn't race the server
+// into rejecting it mid-flight.
+const tokenExpirySkew = 60 * time.Second
+
+// KeystoneV3AuthConfig names the Keystone identity endpoint and the
+// project/domain scope a KeystoneV3Authenticator should request tokens
+// for, mirroring the scope block of a Keystone v3 password auth request.
+type KeystoneV3AuthConfig struct {
+	IdentityURL   string
+	Username      string
+	Password      string
+	UserDomain    string
+	ProjectName   string
+	ProjectDomain string
+}
+
+// KeystoneV3Authenticator authenticates by exchanging credentials for a
+// Keystone v3 token (POST /v3/auth/tokens) and attaching it as
+// X-Auth-Token, refreshing it shortly before it expires. Concurrent
+// requests that find the cached token stale share a single refresh instead
+// of each firing their own POST.
+type KeystoneV3Authenticator struct {
+	config KeystoneV3AuthConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+	fetching  chan struct{}
+}
+
+// NewKeystoneV3Authenticator creates an Authenticator that fetches tokens
+// from config.IdentityURL using client. client is typically the same
+// *http.Client a Client talks to CloudPods with.
+func NewKeystoneV3Authenticator(config KeystoneV3AuthConfig, client *http.Client) *KeystoneV3Authenticator {
+	return &KeystoneV3Authenticator{config: config, client: client}
+}
+
+func (a *KeystoneV3Authenticator) Authenticate(ctx context.Context, req *http.Request) error {
+	token, err := a.ensureToken(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Auth-Token", token)
+	return nil
+}
+
+// ForceRefresh discards the cached token so the next Authenticate call
+// fetches a fresh one, whatever its reported expiry said.
+func (a *KeystoneV3Authenticator) ForceRefresh() {
+	a.mu.Lock()
+	a.token = ""
+	a.expiresAt = time.Time{}
+	a.mu.Unlock()
+}
+
+// ensureToken returns a cached token if it's still fresh, otherwise
+// fetches a new one. Callers that arrive while a fetch is already
+// underway wait for it rather than starting their own.
+func (a *KeystoneV3Authenticator) ensureToken(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	if a.token != "" && time.Now().Before(a.expiresAt) {
+		token := a.token
+		a.mu.Unlock()
+		return token, nil
+	}
+	if wait := a.fetching; wait != nil {
+		a.mu.Unlock()
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		return a.ensureToken(ctx)
+	}
+	done := make(chan struct{})
+	a.fetching = done
+	a.mu.Unlock()
+
+	token, expiresAt, err := a.fetchToken(ctx)
+
+	a.mu.Lock()
+	if err == nil {
+		a.token = token
+		a.expiresAt = expiresAt
+	}
+	a.fetching = nil
+	a.mu.Unlock()
+	close(done)
+
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+type keystoneAuthRequest struct {
+	Auth struct {
+		Identity struct {
+			Methods  []string `json:"methods"`
+			Password struct {
+				User struct {
+					Name     string `json:"name"`
+					Password string `json:"password"`
+					Domain   struct {
+						Name string `json:"name"`
+					} `json:"domain"`
+				} `json:"user"`
+			} `json:"password"`
+		} `json:"identity"`
+		Scope struct {
+			Project struct {
+				Name   string `json:"name"`
+				Domain struct {
+					Name string `json:"name"`
+				} `json:"domain"`
+			} `json:"project"`
+		} `json:"scope"`
+	} `json:"auth"`
+}
+
+type keystoneAuthResponse struct {
+	Token struct {
+		ExpiresAt string `json:"expires_at"`
+	} `json:"token"`
+}
+
+// fetchToken POSTs a password auth request to config.IdentityURL and
+// returns the X-Subject-Token header the server hands back along with the
+// token's expiry, skewed earlier by tokenExpirySkew.
+func (a *KeystoneV3Authenticator) fetchToken(ctx context.Context) (string, time.Time, error) {
+	var body keystoneAuthRequest
+	body.Auth.Identity.Methods = []string{"password"}
+	body.Auth.Identity.Password.User.Name = a.config.Username
+	body.Auth.Identity.Password.User.Password = a.config.Password
+	body.Auth.Identity.Password.User.Domain.Name = a.config.UserDomain
+	body.Auth.Scope.Project.Name = a.config.ProjectName
+	body.Auth.Scope.Project.Domain.Name = a.config.ProjectDomain
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("keystone: marshaling auth request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/v3/auth/tokens", a.config.IdentityURL)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("keystone: creating auth request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("keystone: requesting token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", time.Time{}, fmt.Errorf("keystone: token request failed with status: %d", resp.StatusCode)
+	}
+
+	subjectToken := resp.Header.Get("X-Subject-Token")
+	if subjectToken == "" {
+		return "", time.Time{}, fmt.Errorf("keystone: response missing X-Subject-Token header")
+	}
+
+	var parsed keystoneAuthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", time.Time{}, fmt.Errorf("keystone: decoding token response: %w", err)
+	}
+
+	expiresAt := time.Now().Add(time.Hour)
+	if parsed.Token.ExpiresAt != "" {
+		if t, err := time.Parse(time.RFC3339, parsed.Token.ExpiresAt); err == nil {
+			expiresAt = t
+		}
+	}
+
+	return subjectToken, expiresAt.Add(-tokenExpirySkew), nil
+}
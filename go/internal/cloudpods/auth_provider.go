@@ -0,0 +1,357 @@
+package cloudpods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// AuthProvider attaches credentials to an outgoing EnhancedClient request
+// and, on a 401 response, inspects the server's WWW-Authenticate
+// challenge to refresh whatever credential it just rejected. Unlike the
+// simpler Authenticator Client uses (which only ever retries with a
+// forced refresh), AuthProvider is wired in as an http.RoundTripper (see
+// AuthTransport) so it can adapt to whatever scheme the server actually
+// challenges for, following the docker/distribution registry client's
+// challenge-handling pattern.
+type AuthProvider interface {
+	Authorize(req *http.Request) error
+	HandleChallenge(resp *http.Response) error
+}
+
+// StaticTokenProvider authorizes every request with a fixed bearer token.
+// It has nothing to refresh, so a 401 is reported back as an error
+// instead of retried.
+type StaticTokenProvider struct {
+	Token string
+}
+
+func (p *StaticTokenProvider) Authorize(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+p.Token)
+	return nil
+}
+
+func (p *StaticTokenProvider) HandleChallenge(*http.Response) error {
+	return fmt.Errorf("cloudpods: static bearer token rejected (401); no refresh available")
+}
+
+// BasicAuthProvider authorizes every request with a fixed HTTP Basic
+// credential. Like StaticTokenProvider, it has nothing to refresh.
+type BasicAuthProvider struct {
+	Username string
+	Password string
+}
+
+func (p *BasicAuthProvider) Authorize(req *http.Request) error {
+	req.SetBasicAuth(p.Username, p.Password)
+	return nil
+}
+
+func (p *BasicAuthProvider) HandleChallenge(*http.Response) error {
+	return fmt.Errorf("cloudpods: basic auth credentials rejected (401)")
+}
+
+// BearerTokenProvider exchanges credentials for a short-lived bearer
+// token the first time the server challenges for one, caching the
+// result by scope the way a docker registry client caches a pull/push
+// token per repository scope. Authorize attaches whatever token is
+// cached for the most recently challenged scope (none on the very first
+// request); HandleChallenge performs the realm/service/scope token
+// exchange RFC 7235's Bearer challenge parameters describe.
+type BearerTokenProvider struct {
+	Username string
+	Password string
+
+	client *http.Client
+
+	mu        sync.Mutex
+	tokens    map[string]string
+	lastScope string
+}
+
+// NewBearerTokenProvider creates a BearerTokenProvider that exchanges
+// tokens using client (typically the same *http.Client the
+// EnhancedClient it's installed on talks to CloudPods with). Username
+// and password, if set, are sent as HTTP Basic credentials on the token
+// exchange request itself.
+func NewBearerTokenProvider(username, password string, client *http.Client) *BearerTokenProvider {
+	return &BearerTokenProvider{Username: username, Password: password, client: client}
+}
+
+func (p *BearerTokenProvider) Authorize(req *http.Request) error {
+	p.mu.Lock()
+	token, ok := p.tokens[p.lastScope]
+	p.mu.Unlock()
+	if ok {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return nil
+}
+
+// HandleChallenge finds the Bearer challenge in resp's WWW-Authenticate
+// header(s), exchanges it for a token against the challenge's realm, and
+// caches the token under its scope so subsequent Authorize calls attach
+// it without a repeat exchange.
+func (p *BearerTokenProvider) HandleChallenge(resp *http.Response) error {
+	for _, challenge := range ParseAuthHeader(resp.Header) {
+		if !strings.EqualFold(challenge.Scheme, "Bearer") {
+			continue
+		}
+		realm := challenge.Parameters["realm"]
+		if realm == "" {
+			return fmt.Errorf("cloudpods: Bearer challenge missing realm parameter")
+		}
+		service := challenge.Parameters["service"]
+		scope := challenge.Parameters["scope"]
+
+		token, err := p.exchangeToken(resp.Request.Context(), realm, service, scope)
+		if err != nil {
+			return err
+		}
+
+		p.mu.Lock()
+		if p.tokens == nil {
+			p.tokens = make(map[string]string)
+		}
+		p.tokens[scope] = token
+		p.lastScope = scope
+		p.mu.Unlock()
+		return nil
+	}
+	return fmt.Errorf("cloudpods: response has no Bearer challenge to handle")
+}
+
+// bearerTokenResponse is the shape a Bearer challenge's realm is expected
+// to respond with; token exchanges commonly use either field name, so
+// both are accepted.
+type bearerTokenResponse struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+}
+
+// exchangeToken performs the GET realm?service=...&scope=... token
+// exchange a Bearer challenge describes.
+func (p *BearerTokenProvider) exchangeToken(ctx context.Context, realm, service, scope string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm, nil)
+	if err != nil {
+		return "", fmt.Errorf("cloudpods: building token exchange request: %w", err)
+	}
+	q := req.URL.Query()
+	if service != "" {
+		q.Set("service", service)
+	}
+	if scope != "" {
+		q.Set("scope", scope)
+	}
+	req.URL.RawQuery = q.Encode()
+	if p.Username != "" {
+		req.SetBasicAuth(p.Username, p.Password)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("cloudpods: requesting bearer token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("cloudpods: token exchange at %s failed with status %d", realm, resp.StatusCode)
+	}
+
+	var parsed bearerTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("cloudpods: decoding token exchange response: %w", err)
+	}
+	if parsed.Token != "" {
+		return parsed.Token, nil
+	}
+	if parsed.AccessToken != "" {
+		return parsed.AccessToken, nil
+	}
+	return "", fmt.Errorf("cloudpods: token exchange response had no token or access_token field")
+}
+
+// AuthorizationChallenge is one scheme/parameters pair parsed out of a
+// 401 response's WWW-Authenticate header, RFC 7235's auth-challenge
+// production (e.g. Bearer realm="...",service="...",scope="...").
+type AuthorizationChallenge struct {
+	Scheme     string
+	Parameters map[string]string
+}
+
+// ParseAuthHeader parses every WWW-Authenticate header value in h into
+// its AuthorizationChallenge, tokenizing per RFC 2616 the way
+// docker/distribution's registry client parses registry auth
+// challenges.
+func ParseAuthHeader(h http.Header) []AuthorizationChallenge {
+	var challenges []AuthorizationChallenge
+	for _, header := range h[http.CanonicalHeaderKey("WWW-Authenticate")] {
+		scheme, params := parseChallenge(header)
+		if scheme == "" {
+			continue
+		}
+		challenges = append(challenges, AuthorizationChallenge{Scheme: scheme, Parameters: params})
+	}
+	return challenges
+}
+
+// isSpace reports whether r is an RFC 2616 linear-whitespace octet.
+func isSpace(r byte) bool {
+	return r == ' ' || r == '\t'
+}
+
+// isToken reports whether r is a valid RFC 2616 "token" octet: any CHAR
+// except CTLs or the defined separator characters.
+func isToken(r byte) bool {
+	if r <= 0x1f || r == 0x7f {
+		return false
+	}
+	switch r {
+	case '(', ')', '<', '>', '@', ',', ';', ':', '\\', '"', '/', '[', ']', '?', '=', '{', '}', ' ', '\t':
+		return false
+	}
+	return true
+}
+
+// skipSpace returns s with any leading RFC 2616 whitespace removed.
+func skipSpace(s string) string {
+	i := 0
+	for i < len(s) && isSpace(s[i]) {
+		i++
+	}
+	return s[i:]
+}
+
+// parseChallenge parses a single WWW-Authenticate header value into its
+// scheme token followed by comma-separated key=value (or quoted-string)
+// parameters.
+func parseChallenge(header string) (scheme string, params map[string]string) {
+	params = make(map[string]string)
+
+	s := header
+	i := 0
+	for i < len(s) && isToken(s[i]) {
+		i++
+	}
+	scheme = s[:i]
+	s = skipSpace(s[i:])
+
+	for len(s) > 0 {
+		keyStart := 0
+		for keyStart < len(s) && isToken(s[keyStart]) {
+			keyStart++
+		}
+		if keyStart == 0 {
+			break
+		}
+		key := strings.ToLower(s[:keyStart])
+		s = skipSpace(s[keyStart:])
+
+		if len(s) == 0 || s[0] != '=' {
+			break
+		}
+		s = skipSpace(s[1:])
+
+		var value string
+		if len(s) > 0 && s[0] == '"' {
+			s = s[1:]
+			var b strings.Builder
+			for len(s) > 0 && s[0] != '"' {
+				if s[0] == '\\' && len(s) > 1 {
+					s = s[1:]
+				}
+				b.WriteByte(s[0])
+				s = s[1:]
+			}
+			if len(s) > 0 {
+				s = s[1:]
+			}
+			value = b.String()
+		} else {
+			valEnd := 0
+			for valEnd < len(s) && isToken(s[valEnd]) {
+				valEnd++
+			}
+			value = s[:valEnd]
+			s = s[valEnd:]
+		}
+		params[key] = value
+
+		s = skipSpace(s)
+		if len(s) > 0 && s[0] == ',' {
+			s = skipSpace(s[1:])
+		}
+	}
+	return scheme, params
+}
+
+// AuthTransport is an http.RoundTripper that authorizes every request
+// through Provider and, on a 401 response, lets Provider parse the
+// WWW-Authenticate challenge and retries the request once with whatever
+// credential that produced - the docker/distribution registry client's
+// challenge/retry loop, generalized to any AuthProvider.
+type AuthTransport struct {
+	Base     http.RoundTripper
+	Provider AuthProvider
+}
+
+func (t *AuthTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *AuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	authed, err := t.authorize(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base().RoundTrip(authed)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	if err := t.Provider.HandleChallenge(resp); err != nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	retry, err := t.authorize(req)
+	if err != nil {
+		return nil, err
+	}
+	return t.base().RoundTrip(retry)
+}
+
+// authorize clones req (replaying its body via GetBody, when the
+// original request has one, since a body reader can only be consumed
+// once) and has Provider attach credentials to the clone.
+func (t *AuthTransport) authorize(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("cloudpods: replaying request body: %w", err)
+		}
+		clone.Body = body
+	}
+	if err := t.Provider.Authorize(clone); err != nil {
+		return nil, fmt.Errorf("cloudpods: authorizing request: %w", err)
+	}
+	return clone, nil
+}
+
+// SetAuthProvider switches EnhancedClient from its static bearer token
+// (authToken) to a pluggable AuthProvider, installing an AuthTransport
+// in front of whatever Transport httpClient already had. Once set, every
+// doRequest call skips its hardcoded Authorization header and relies on
+// the AuthTransport instead.
+func (c *EnhancedClient) SetAuthProvider(provider AuthProvider) {
+	c.authProvider = provider
+	c.httpClient.Transport = &AuthTransport{Base: c.httpClient.Transport, Provider: provider}
+}
@@ -0,0 +1,265 @@
+package cloudpods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BlueprintParameter describes one named input InstallBlueprint's
+// Parameters map must (or may) supply for a given Blueprint, the
+// equivalent of DO's "1-click app" config_variables.
+type BlueprintParameter struct {
+	Name     string      `json:"name"`
+	Type     string      `json:"type"`
+	Required bool        `json:"required"`
+	Default  interface{} `json:"default,omitempty"`
+}
+
+// Blueprint is a reusable multi-resource template InstallBlueprint
+// provisions as one Stack, CloudPods' "1-click app" equivalent.
+type Blueprint struct {
+	Slug        string               `json:"slug"`
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Parameters  []BlueprintParameter `json:"parameters"`
+}
+
+// StackStatus is a Stack's overall provisioning state.
+type StackStatus string
+
+const (
+	StackReady  StackStatus = "ready"
+	StackFailed StackStatus = "failed"
+)
+
+// Stack is the group of resources InstallBlueprint provisioned together
+// as one unit, and everything DeleteStack tears back down.
+type Stack struct {
+	ID           string        `json:"id"`
+	Name         string        `json:"name"`
+	Blueprint    string        `json:"blueprint"`
+	Status       StackStatus   `json:"status"`
+	Network      *Network      `json:"network,omitempty"`
+	Instances    []Instance    `json:"instances,omitempty"`
+	LoadBalancer *LoadBalancer `json:"load_balancer,omitempty"`
+	Storages     []Storage     `json:"storages,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+// InstallBlueprintRequest is InstallBlueprint's input: which Blueprint to
+// install (by Slug) and the resource definitions to provision for it.
+// Parameters carries the Blueprint's own config values (instance size,
+// replica count, and the like); a caller typically derives
+// Network/Instances/LoadBalancer/Storages from Parameters and the
+// Blueprint it fetched via GetBlueprint before calling InstallBlueprint.
+type InstallBlueprintRequest struct {
+	Slug         string
+	Name         string
+	Parameters   map[string]interface{}
+	Network      *Network
+	Instances    []Instance
+	LoadBalancer *LoadBalancer
+	Storages     []Storage
+}
+
+// BlueprintsService is EnhancedClient's handle on CloudPods' blueprint
+// catalog and the Stacks InstallBlueprint provisions from it, reachable
+// through EnhancedClient.Blueprints.
+type BlueprintsService struct {
+	client *EnhancedClient
+
+	mu     sync.RWMutex
+	stacks map[string]*Stack
+	nextID uint64
+}
+
+func newBlueprintsService(client *EnhancedClient) *BlueprintsService {
+	return &BlueprintsService{client: client, stacks: make(map[string]*Stack)}
+}
+
+// ListBlueprints returns every Blueprint in CloudPods' catalog.
+func (s *BlueprintsService) ListBlueprints(ctx context.Context) ([]Blueprint, error) {
+	var resp struct {
+		Blueprints []Blueprint `json:"blueprints"`
+	}
+	if err := s.client.doRequest(ctx, http.MethodGet, "/blueprints", nil, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list blueprints: %w", err)
+	}
+	return resp.Blueprints, nil
+}
+
+// GetBlueprint returns the Blueprint named by slug.
+func (s *BlueprintsService) GetBlueprint(ctx context.Context, slug string) (*Blueprint, error) {
+	var bp Blueprint
+	if err := s.client.doRequest(ctx, http.MethodGet, "/blueprints/"+slug, nil, nil, &bp); err != nil {
+		return nil, fmt.Errorf("failed to get blueprint: %w", err)
+	}
+	return &bp, nil
+}
+
+// InstallBlueprint provisions req's Network, Instances, Storages, and
+// LoadBalancer as one Stack, in dependency order (network first, so
+// instances can reference it; load balancer last, so it has instances to
+// front). If any step fails, every sibling created so far is rolled back,
+// in the same order DeleteStack tears a finished Stack down, before
+// InstallBlueprint returns an error naming the failing step.
+func (s *BlueprintsService) InstallBlueprint(ctx context.Context, req InstallBlueprintRequest) (*Stack, error) {
+	stack := &Stack{
+		ID:        s.newStackID(),
+		Name:      req.Name,
+		Blueprint: req.Slug,
+		CreatedAt: time.Now(),
+	}
+
+	rollback := func(step string, cause error) (*Stack, error) {
+		s.teardown(context.Background(), stack)
+		stack.Status = StackFailed
+		return nil, fmt.Errorf("install blueprint %s: %s failed, rolled back: %w", req.Slug, step, cause)
+	}
+
+	if req.Network != nil {
+		network, err := s.createAndWaitNetwork(ctx, req.Network)
+		if err != nil {
+			return rollback("network", err)
+		}
+		stack.Network = network
+	}
+
+	for i := range req.Instances {
+		instance, err := s.createAndWaitInstance(ctx, &req.Instances[i])
+		if err != nil {
+			return rollback(fmt.Sprintf("instance[%d]", i), err)
+		}
+		stack.Instances = append(stack.Instances, *instance)
+	}
+
+	for i := range req.Storages {
+		storage, err := s.createAndWaitStorage(ctx, &req.Storages[i])
+		if err != nil {
+			return rollback(fmt.Sprintf("storage[%d]", i), err)
+		}
+		stack.Storages = append(stack.Storages, *storage)
+	}
+
+	if req.LoadBalancer != nil {
+		lb, err := s.createAndWaitLoadBalancer(ctx, req.LoadBalancer)
+		if err != nil {
+			return rollback("load_balancer", err)
+		}
+		stack.LoadBalancer = lb
+	}
+
+	stack.Status = StackReady
+	s.mu.Lock()
+	s.stacks[stack.ID] = stack
+	s.mu.Unlock()
+	return stack, nil
+}
+
+// DeleteStack tears down every member of id's Stack, respecting
+// dependency order: load balancer, then instances, then storage
+// (detaching it from an instance is instanceAction's job, not
+// DeleteStack's - the instances are already gone by this point), then
+// network.
+func (s *BlueprintsService) DeleteStack(ctx context.Context, id string) error {
+	s.mu.RLock()
+	stack, ok := s.stacks[id]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("cloudpods: stack %s not found", id)
+	}
+
+	s.teardown(ctx, stack)
+
+	s.mu.Lock()
+	delete(s.stacks, id)
+	s.mu.Unlock()
+	return nil
+}
+
+// teardown best-effort deletes every member stack currently has, in
+// dependency order (load balancer -> instances -> storage -> network).
+// Used by both DeleteStack and InstallBlueprint's rollback, where a
+// partially built Stack's members are exactly the ones worth deleting;
+// a failure partway through teardown is swallowed so the remaining
+// members still get a deletion attempt.
+func (s *BlueprintsService) teardown(ctx context.Context, stack *Stack) {
+	if stack.LoadBalancer != nil {
+		if op, err := s.client.DeleteLoadBalancer(ctx, stack.LoadBalancer.ID); err == nil {
+			s.client.Operations.Wait(ctx, op.ID)
+		}
+	}
+	for _, instance := range stack.Instances {
+		if op, err := s.client.DeleteInstance(ctx, instance.ID); err == nil {
+			s.client.Operations.Wait(ctx, op.ID)
+		}
+	}
+	for _, storage := range stack.Storages {
+		if op, err := s.client.DeleteStorage(ctx, storage.ID); err == nil {
+			s.client.Operations.Wait(ctx, op.ID)
+		}
+	}
+	if stack.Network != nil {
+		if op, err := s.client.DeleteNetwork(ctx, stack.Network.ID); err == nil {
+			s.client.Operations.Wait(ctx, op.ID)
+		}
+	}
+}
+
+func (s *BlueprintsService) newStackID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	return fmt.Sprintf("stack-%d-%d", time.Now().UnixNano(), s.nextID)
+}
+
+func (s *BlueprintsService) createAndWaitNetwork(ctx context.Context, network *Network) (*Network, error) {
+	op, err := s.client.CreateNetwork(ctx, network)
+	if err != nil {
+		return nil, err
+	}
+	return waitForResource[Network](ctx, s.client.Operations, op)
+}
+
+func (s *BlueprintsService) createAndWaitInstance(ctx context.Context, instance *Instance) (*Instance, error) {
+	op, err := s.client.CreateInstance(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+	return waitForResource[Instance](ctx, s.client.Operations, op)
+}
+
+func (s *BlueprintsService) createAndWaitStorage(ctx context.Context, storage *Storage) (*Storage, error) {
+	op, err := s.client.CreateStorage(ctx, storage)
+	if err != nil {
+		return nil, err
+	}
+	return waitForResource[Storage](ctx, s.client.Operations, op)
+}
+
+func (s *BlueprintsService) createAndWaitLoadBalancer(ctx context.Context, lb *LoadBalancer) (*LoadBalancer, error) {
+	op, err := s.client.CreateLoadBalancer(ctx, lb)
+	if err != nil {
+		return nil, err
+	}
+	return waitForResource[LoadBalancer](ctx, s.client.Operations, op)
+}
+
+// waitForResource blocks until op resolves via ops.Wait, then decodes the
+// terminal Operation's Resource payload as T - the shared last step every
+// createAndWait* helper above needs after starting its own Create* call.
+func waitForResource[T any](ctx context.Context, ops *OperationsService, op *Operation) (*T, error) {
+	done, err := ops.Wait(ctx, op.ID)
+	if err != nil {
+		return nil, err
+	}
+	var resource T
+	if err := json.Unmarshal(done.Resource, &resource); err != nil {
+		return nil, fmt.Errorf("cloudpods: decoding operation resource: %w", err)
+	}
+	return &resource, nil
+}
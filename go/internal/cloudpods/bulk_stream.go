@@ -0,0 +1,127 @@
+package cloudpods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// BulkResult is one item's outcome from a streamed bulk request, decoded
+// from the server's own NDJSON response as it flushes each record -
+// Index matches the position of the request record that produced it, ID
+// is the resulting (or deleted) Instance's ID, and Error is set
+// alongside a non-2xx Status when that one item failed, independent of
+// every other item in the stream.
+type BulkResult struct {
+	Index  int    `json:"index"`
+	ID     string `json:"id"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkCreateInstancesStream streams in as newline-delimited JSON over a
+// chunked POST to /instances/bulk:stream instead of buffering the whole
+// batch the way BulkCreateInstances does, so an arbitrarily large job
+// doesn't have to fit in memory at once. The returned channel is fed
+// concurrently from the server's own NDJSON response as each instance is
+// persisted, so a caller can act on early successes before the batch
+// finishes; it is closed once the response body is exhausted, the
+// request fails, or ctx is canceled.
+func (c *EnhancedClient) BulkCreateInstancesStream(ctx context.Context, in <-chan Instance) (<-chan BulkResult, error) {
+	return c.bulkStream(ctx, "/instances/bulk:stream", func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		for instance := range in {
+			if err := enc.Encode(instance); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// bulkDeleteStreamItem is the NDJSON record BulkDeleteInstancesStream
+// writes for each id, the delete-stream's equivalent of an Instance
+// record.
+type bulkDeleteStreamItem struct {
+	ID string `json:"id"`
+}
+
+// BulkDeleteInstancesStream is BulkCreateInstancesStream's symmetric
+// counterpart for deletion: it streams ids as NDJSON over a chunked POST
+// to /instances/bulk:stream/delete and returns the same kind of
+// concurrently-fed BulkResult channel.
+func (c *EnhancedClient) BulkDeleteInstancesStream(ctx context.Context, ids <-chan string) (<-chan BulkResult, error) {
+	return c.bulkStream(ctx, "/instances/bulk:stream/delete", func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		for id := range ids {
+			if err := enc.Encode(bulkDeleteStreamItem{ID: id}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// bulkStreamResultBuffer is how many BulkResults bulkStream buffers
+// before its reader goroutine blocks, matching the Watch/Operations
+// event channel convention elsewhere in this package.
+const bulkStreamResultBuffer = 64
+
+// bulkStream issues a chunked POST to path whose body is written by
+// encode (one JSON record per line, flushed as it's produced rather than
+// buffered), and concurrently decodes the response body as an NDJSON
+// stream of BulkResult, forwarding each one as soon as the server
+// flushes it. encode runs in its own goroutine so writing the request
+// and reading the response happen at the same time, the way a real
+// streaming bulk ingest needs to for unbounded input.
+func (c *EnhancedClient) bulkStream(ctx context.Context, path string, encode func(io.Writer) error) (<-chan BulkResult, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(encode(pw))
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, pr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set("Authorization", "Bearer "+c.authToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stream bulk request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		apiErr := newAPIError(http.MethodPost, path, resp)
+		resp.Body.Close()
+		return nil, apiErr
+	}
+
+	results := make(chan BulkResult, bulkStreamResultBuffer)
+	go func() {
+		defer close(results)
+		defer resp.Body.Close()
+
+		decoder := json.NewDecoder(resp.Body)
+		for decoder.More() {
+			var result BulkResult
+			if err := decoder.Decode(&result); err != nil {
+				return
+			}
+			select {
+			case results <- result:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results, nil
+}
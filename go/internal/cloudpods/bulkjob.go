@@ -0,0 +1,190 @@
+package cloudpods
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// BulkJobState is a BulkJob's current phase, the same pending/running/
+// terminal shape OperationStatus uses for a single-resource Operation.
+type BulkJobState string
+
+const (
+	BulkJobPending   BulkJobState = "pending"
+	BulkJobRunning   BulkJobState = "running"
+	BulkJobSucceeded BulkJobState = "succeeded"
+	BulkJobFailed    BulkJobState = "failed"
+	BulkJobCanceled  BulkJobState = "canceled"
+)
+
+func (s BulkJobState) terminal() bool {
+	return s == BulkJobSucceeded || s == BulkJobFailed || s == BulkJobCanceled
+}
+
+// BulkItemFailure is one input's failure within a bulk job: its position
+// in the submitted slice, the Instance ID it carried (if it had one -
+// empty for a create whose ID is assigned server-side), an HTTP-like
+// status for the failure class, and a human-readable message.
+type BulkItemFailure struct {
+	Index      int    `json:"index"`
+	InstanceID string `json:"instance_id"`
+	Status     int    `json:"status"`
+	Message    string `json:"message"`
+}
+
+// BulkJobStatus is one Poll snapshot of a BulkJob: how far processing
+// has gotten, and every per-item failure seen so far. State.terminal()
+// being true means Processed will not advance further.
+type BulkJobStatus struct {
+	State     BulkJobState      `json:"state"`
+	Processed int               `json:"processed"`
+	Total     int               `json:"total"`
+	Failures  []BulkItemFailure `json:"failures"`
+}
+
+// BulkJob tracks an async bulk create/delete submitted with
+// SubmitBulkCreate or SubmitBulkDelete, CloudPods' equivalent of a
+// resumable chunked-upload handle: unlike the synchronous
+// BulkCreateInstances/BulkDeleteInstances (which return as soon as
+// CloudPods accepts the batch), a BulkJob is polled until every item has
+// been processed, and partial failures are reported per item instead of
+// failing the whole batch.
+type BulkJob struct {
+	JobID     string
+	Location  string
+	StartedAt time.Time
+
+	client *EnhancedClient
+
+	mu     sync.Mutex
+	status BulkJobStatus
+}
+
+// bulkJobSubmitResponse is the shape SubmitBulkCreate/SubmitBulkDelete
+// decode from a 202 Accepted response.
+type bulkJobSubmitResponse struct {
+	JobID    string `json:"job_id"`
+	Location string `json:"location"`
+}
+
+// newBulkJob wraps a just-submitted job's response into a BulkJob ready
+// for Poll/Wait/Cancel.
+func newBulkJob(client *EnhancedClient, resp bulkJobSubmitResponse) *BulkJob {
+	return &BulkJob{
+		JobID:     resp.JobID,
+		Location:  resp.Location,
+		StartedAt: time.Now(),
+		client:    client,
+		status:    BulkJobStatus{State: BulkJobPending, Total: 0},
+	}
+}
+
+// SubmitBulkCreate starts an async bulk instance creation and returns a
+// BulkJob handle for it; call Poll or Wait to track progress, rather
+// than blocking on the single round-trip BulkCreateInstances does.
+func (c *EnhancedClient) SubmitBulkCreate(ctx context.Context, instances []Instance) (*BulkJob, error) {
+	var resp bulkJobSubmitResponse
+	if err := c.doRequest(ctx, http.MethodPost, "/instances/bulk", url.Values{"async": {"true"}}, instances, &resp, http.StatusAccepted); err != nil {
+		return nil, fmt.Errorf("failed to submit bulk create: %w", err)
+	}
+	job := newBulkJob(c, resp)
+	job.status.Total = len(instances)
+	return job, nil
+}
+
+// SubmitBulkDelete starts an async bulk instance deletion and returns a
+// BulkJob handle for it, the async counterpart to BulkDeleteInstances.
+func (c *EnhancedClient) SubmitBulkDelete(ctx context.Context, ids []string) (*BulkJob, error) {
+	var resp bulkJobSubmitResponse
+	if err := c.doRequest(ctx, http.MethodDelete, "/instances/bulk", url.Values{"async": {"true"}}, ids, &resp, http.StatusAccepted); err != nil {
+		return nil, fmt.Errorf("failed to submit bulk delete: %w", err)
+	}
+	job := newBulkJob(c, resp)
+	job.status.Total = len(ids)
+	return job, nil
+}
+
+// Status returns the BulkJob's last-known status without polling again,
+// i.e. whatever the most recent Poll (or Wait's internal polling) saw.
+func (j *BulkJob) Status() BulkJobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+// Poll fetches the job's current BulkJobStatus from its Location.
+func (j *BulkJob) Poll(ctx context.Context) (*BulkJobStatus, error) {
+	var status BulkJobStatus
+	if err := j.client.doRequest(ctx, http.MethodGet, j.Location, nil, nil, &status); err != nil {
+		return nil, fmt.Errorf("failed to poll bulk job %s: %w", j.JobID, err)
+	}
+	j.mu.Lock()
+	j.status = status
+	j.mu.Unlock()
+	return &status, nil
+}
+
+// bulkJobPollBaseInterval and bulkJobPollMaxInterval bound Wait's
+// exponential polling backoff: it starts at whichever of its own
+// pollInterval argument or bulkJobPollBaseInterval is larger, doubling
+// after every non-terminal poll up to bulkJobPollMaxInterval.
+const (
+	bulkJobPollBaseInterval = 500 * time.Millisecond
+	bulkJobPollMaxInterval  = 10 * time.Second
+)
+
+// Wait polls the job every pollInterval (bulkJobPollBaseInterval if
+// pollInterval is zero), doubling the interval after each non-terminal
+// poll up to bulkJobPollMaxInterval, until it reaches a terminal state.
+// A job that finished with per-item failures (BulkJobFailed) is returned
+// alongside an error summarizing the failure count, so a caller can
+// still inspect BulkJobStatus.Failures for partial-success handling
+// without a type assertion.
+func (j *BulkJob) Wait(ctx context.Context, pollInterval time.Duration) (*BulkJobStatus, error) {
+	interval := pollInterval
+	if interval <= 0 {
+		interval = bulkJobPollBaseInterval
+	}
+
+	for {
+		status, err := j.Poll(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if status.State.terminal() {
+			if status.State == BulkJobFailed {
+				return status, fmt.Errorf("bulk job %s failed: %d of %d items failed", j.JobID, len(status.Failures), status.Total)
+			}
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		interval *= 2
+		if interval > bulkJobPollMaxInterval {
+			interval = bulkJobPollMaxInterval
+		}
+	}
+}
+
+// Cancel requests that the job stop processing remaining items; items
+// already processed are not rolled back. A terminal job returns no
+// error, the same no-op-on-finished semantics Operations.Cancel has.
+func (j *BulkJob) Cancel(ctx context.Context) error {
+	if err := j.client.doRequest(ctx, http.MethodDelete, j.Location, nil, nil, nil); err != nil {
+		return fmt.Errorf("failed to cancel bulk job %s: %w", j.JobID, err)
+	}
+	j.mu.Lock()
+	j.status.State = BulkJobCanceled
+	j.mu.Unlock()
+	return nil
+}
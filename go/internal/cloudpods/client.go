@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"time"
 
+	"router-sim/internal/cloudpods/admission"
+	"router-sim/internal/cloudpods/garbagecollector"
 	"router-sim/internal/config"
 )
 
@@ -14,16 +16,159 @@ import (
 type Client struct {
 	config *config.CloudPodsConfig
 	client *http.Client
+	events *EventBus
+	gc     *garbagecollector.Collector
+	auth   Authenticator
+
+	mutating   *admission.MutatingChain
+	validating *admission.ValidatingChain
 }
 
-// NewClient creates a new CloudPods client
+// NewClient creates a new CloudPods client. It authenticates with HTTP
+// Basic auth by default, the model CloudPods has always supported; call
+// SetAuthenticator with a KeystoneV3Authenticator to switch to token
+// auth instead.
 func NewClient(config *config.CloudPodsConfig) *Client {
-	return &Client{
+	c := &Client{
 		config: config,
 		client: &http.Client{
 			Timeout: config.Timeout,
 		},
+		events: NewEventBus(),
+		auth:   &BasicAuthenticator{Username: config.Auth.Username, Password: config.Auth.Password},
 	}
+	c.gc = newGarbageCollector(c)
+	return c
+}
+
+// SetAuthenticator swaps the client's Authenticator, e.g. to a
+// KeystoneV3Authenticator once its credentials/scope are known.
+func (c *Client) SetAuthenticator(auth Authenticator) {
+	c.auth = auth
+}
+
+// SetVMAdmission wires an admission pipeline into CreateVM/UpdateVM/
+// DeleteVM, run the same way CloudPodsHandler.SetAdmission runs one in
+// front of its write endpoints: mutating first, then validating against
+// the (possibly mutated) final object. Either may be nil to skip that
+// stage.
+func (c *Client) SetVMAdmission(mutating *admission.MutatingChain, validating *admission.ValidatingChain) {
+	c.mutating = mutating
+	c.validating = validating
+}
+
+// sendAuthenticated builds a request with newReq, attaches c.auth, and
+// sends it. newReq is called again (so a POST's body reader isn't
+// replayed half-consumed) if the first attempt comes back 401: that
+// retry forces a fresh Authenticate after discarding whatever credential
+// the server just rejected.
+func (c *Client) sendAuthenticated(ctx context.Context, newReq func() (*http.Request, error)) (*http.Response, error) {
+	resp, err := c.doRequestOnce(ctx, newReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	c.auth.ForceRefresh()
+	return c.doRequestOnce(ctx, newReq)
+}
+
+func (c *Client) doRequestOnce(ctx context.Context, newReq func() (*http.Request, error)) (*http.Response, error) {
+	req, err := newReq()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if err := c.auth.Authenticate(ctx, req); err != nil {
+		return nil, fmt.Errorf("cloudpods: authenticating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.config.Partition != "" {
+		req.Header.Set("X-Router-Partition", c.config.Partition)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	return resp, nil
+}
+
+// Events returns the client's shared event bus, used by CloudPodsHandler's
+// mutating endpoints to publish changes and its Watch* endpoints to stream
+// them back out.
+func (c *Client) Events() *EventBus {
+	return c.events
+}
+
+// GC returns the client's garbage collector, tracking ownerReferences
+// across VPCs, subnets, NAT gateways, load balancers, and service-mesh
+// routes so deleting a VPC can cascade to what it owns instead of leaving
+// orphans behind.
+func (c *Client) GC() *garbagecollector.Collector {
+	return c.gc
+}
+
+// newGarbageCollector wires up the Collector's deletable kinds against the
+// same client methods CloudPodsHandler calls directly, so a cascaded
+// delete behaves identically to a direct one.
+func newGarbageCollector(c *Client) *garbagecollector.Collector {
+	gc := garbagecollector.NewCollector()
+
+	gc.RegisterKind(garbagecollector.ResourceKind{
+		Name: "vpc",
+		Delete: func(ctx context.Context, _ string, id string) error {
+			if err := c.DeleteVPC(ctx, id); err != nil {
+				return err
+			}
+			c.events.Publish("vpc", id, EventDeleted, map[string]string{"id": id})
+			return nil
+		},
+	})
+	gc.RegisterKind(garbagecollector.ResourceKind{
+		Name: "subnet",
+		Delete: func(ctx context.Context, vpcID string, id string) error {
+			if err := c.DeleteSubnet(ctx, vpcID, id); err != nil {
+				return err
+			}
+			c.events.Publish("subnet", vpcID, EventDeleted, map[string]string{"id": id})
+			return nil
+		},
+	})
+	gc.RegisterKind(garbagecollector.ResourceKind{
+		Name: "natgateway",
+		Delete: func(ctx context.Context, vpcID string, id string) error {
+			if err := c.DeleteNATGateway(ctx, vpcID, id); err != nil {
+				return err
+			}
+			c.events.Publish("natgateway", vpcID, EventDeleted, map[string]string{"id": id})
+			return nil
+		},
+	})
+	gc.RegisterKind(garbagecollector.ResourceKind{
+		Name: "loadbalancer",
+		Delete: func(ctx context.Context, vpcID string, id string) error {
+			if err := c.DeleteLoadBalancer(ctx, vpcID, id); err != nil {
+				return err
+			}
+			c.events.Publish("loadbalancer", vpcID, EventDeleted, map[string]string{"id": id})
+			return nil
+		},
+	})
+	gc.RegisterKind(garbagecollector.ResourceKind{
+		Name: "servicemeshroute",
+		Delete: func(ctx context.Context, vpcID string, id string) error {
+			if err := c.DeleteServiceMeshRoute(ctx, vpcID, id); err != nil {
+				return err
+			}
+			c.events.Publish("servicemeshroute", vpcID, EventDeleted, map[string]string{"id": id})
+			return nil
+		},
+	})
+
+	return gc
 }
 
 // CloudPods API Response structures
@@ -35,63 +180,78 @@ type CloudPodsResponse struct {
 }
 
 type CloudPodsResource struct {
-	ID          string                 `json:"id"`
-	Name        string                 `json:"name"`
-	Type        string                 `json:"type"`
-	Status      string                 `json:"status"`
-	Region      string                 `json:"region"`
-	Zone        string                 `json:"zone"`
-	CreatedAt   time.Time              `json:"created_at"`
-	UpdatedAt   time.Time              `json:"updated_at"`
-	Properties  map[string]interface{} `json:"properties"`
-	Tags        map[string]string      `json:"tags"`
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	Type       string                 `json:"type"`
+	Status     string                 `json:"status"`
+	Region     string                 `json:"region"`
+	Zone       string                 `json:"zone"`
+	CreatedAt  time.Time              `json:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+	Properties map[string]interface{} `json:"properties"`
+	Tags       map[string]string      `json:"tags"`
+
+	// ResourceVersion increments on every create/update/delete of this
+	// resource. Apply and Patch requests may supply the version they last
+	// observed; a mismatch against the current value fails with
+	// ErrResourceVersionConflict instead of silently clobbering a
+	// concurrent write.
+	ResourceVersion uint64 `json:"resourceVersion"`
+}
+
+// Labels returns the resource's tags as the label set ListOptions'
+// LabelSelector matches against. Every CloudPods*-typed resource embeds
+// CloudPodsResource, so this method promotes onto all of them.
+func (r CloudPodsResource) Labels() map[string]string {
+	return r.Tags
 }
 
 type CloudPodsVM struct {
 	CloudPodsResource
-	CPU        int    `json:"cpu"`
-	Memory     int    `json:"memory"`
-	Disk       int    `json:"disk"`
-	Image      string `json:"image"`
-	VPC        string `json:"vpc"`
-	Subnet     string `json:"subnet"`
-	PublicIP   string `json:"public_ip"`
-	PrivateIP  string `json:"private_ip"`
-	SSHKey     string `json:"ssh_key"`
+	CPU            int      `json:"cpu"`
+	Memory         int      `json:"memory"`
+	Disk           int      `json:"disk"`
+	Image          string   `json:"image"`
+	VPC            string   `json:"vpc"`
+	Subnet         string   `json:"subnet"`
+	SecurityGroups []string `json:"security_groups"`
+	PublicIP       string   `json:"public_ip"`
+	PrivateIP      string   `json:"private_ip"`
+	SSHKey         string   `json:"ssh_key"`
 }
 
 type CloudPodsVPC struct {
 	CloudPodsResource
-	CIDR       string   `json:"cidr"`
-	Subnets    []string `json:"subnets"`
-	Gateways   []string `json:"gateways"`
-	Routes     []string `json:"routes"`
+	CIDR           string   `json:"cidr"`
+	Subnets        []string `json:"subnets"`
+	Gateways       []string `json:"gateways"`
+	Routes         []string `json:"routes"`
 	SecurityGroups []string `json:"security_groups"`
 }
 
 type CloudPodsSubnet struct {
 	CloudPodsResource
-	VPC        string `json:"vpc"`
-	CIDR       string `json:"cidr"`
-	Gateway    string `json:"gateway"`
-	DHCP       bool   `json:"dhcp"`
-	DNS        []string `json:"dns"`
+	VPC     string   `json:"vpc"`
+	CIDR    string   `json:"cidr"`
+	Gateway string   `json:"gateway"`
+	DHCP    bool     `json:"dhcp"`
+	DNS     []string `json:"dns"`
 }
 
 type CloudPodsSecurityGroup struct {
 	CloudPodsResource
-	Rules      []SecurityGroupRule `json:"rules"`
-	VPC        string              `json:"vpc"`
+	Rules []SecurityGroupRule `json:"rules"`
+	VPC   string              `json:"vpc"`
 }
 
 type SecurityGroupRule struct {
-	Direction    string `json:"direction"`
-	Protocol     string `json:"protocol"`
-	PortRange    string `json:"port_range"`
-	Source       string `json:"source"`
-	Destination  string `json:"destination"`
-	Action       string `json:"action"`
-	Priority     int    `json:"priority"`
+	Direction   string `json:"direction"`
+	Protocol    string `json:"protocol"`
+	PortRange   string `json:"port_range"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Action      string `json:"action"`
+	Priority    int    `json:"priority"`
 }
 
 type CloudPodsLoadBalancer struct {
@@ -103,22 +263,30 @@ type CloudPodsLoadBalancer struct {
 	HealthCheck string   `json:"health_check"`
 }
 
+type CloudPodsNATGateway struct {
+	CloudPodsResource
+	VPC string `json:"vpc"`
+}
+
+// CloudPodsServiceMeshRoute is a single traffic rule within a VPC's
+// service mesh: Match is an HTTP path prefix, Destination is the upstream
+// service name traffic matching it is sent to.
+type CloudPodsServiceMeshRoute struct {
+	CloudPodsResource
+	VPC         string `json:"vpc"`
+	Match       string `json:"match"`
+	Destination string `json:"destination"`
+}
+
 // GetVMs retrieves all VMs from CloudPods
 func (c *Client) GetVMs(ctx context.Context) ([]CloudPodsVM, error) {
 	url := fmt.Sprintf("%s/api/v1/vms", c.config.API.BaseURL)
-	
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Add authentication headers
-	req.SetBasicAuth(c.config.Auth.Username, c.config.Auth.Password)
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.client.Do(req)
+	resp, err := c.sendAuthenticated(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", url, nil)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -154,18 +322,12 @@ func (c *Client) GetVMs(ctx context.Context) ([]CloudPodsVM, error) {
 // GetVPCs retrieves all VPCs from CloudPods
 func (c *Client) GetVPCs(ctx context.Context) ([]CloudPodsVPC, error) {
 	url := fmt.Sprintf("%s/api/v1/vpcs", c.config.API.BaseURL)
-	
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
 
-	req.SetBasicAuth(c.config.Auth.Username, c.config.Auth.Password)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.client.Do(req)
+	resp, err := c.sendAuthenticated(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", url, nil)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -200,18 +362,12 @@ func (c *Client) GetVPCs(ctx context.Context) ([]CloudPodsVPC, error) {
 // GetSubnets retrieves all subnets from CloudPods
 func (c *Client) GetSubnets(ctx context.Context) ([]CloudPodsSubnet, error) {
 	url := fmt.Sprintf("%s/api/v1/subnets", c.config.API.BaseURL)
-	
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.SetBasicAuth(c.config.Auth.Username, c.config.Auth.Password)
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.client.Do(req)
+	resp, err := c.sendAuthenticated(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", url, nil)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -246,18 +402,12 @@ func (c *Client) GetSubnets(ctx context.Context) ([]CloudPodsSubnet, error) {
 // GetSecurityGroups retrieves all security groups from CloudPods
 func (c *Client) GetSecurityGroups(ctx context.Context) ([]CloudPodsSecurityGroup, error) {
 	url := fmt.Sprintf("%s/api/v1/security-groups", c.config.API.BaseURL)
-	
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.SetBasicAuth(c.config.Auth.Username, c.config.Auth.Password)
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.client.Do(req)
+	resp, err := c.sendAuthenticated(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", url, nil)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -292,18 +442,12 @@ func (c *Client) GetSecurityGroups(ctx context.Context) ([]CloudPodsSecurityGrou
 // GetLoadBalancers retrieves all load balancers from CloudPods
 func (c *Client) GetLoadBalancers(ctx context.Context) ([]CloudPodsLoadBalancer, error) {
 	url := fmt.Sprintf("%s/api/v1/load-balancers", c.config.API.BaseURL)
-	
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.SetBasicAuth(c.config.Auth.Username, c.config.Auth.Password)
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.client.Do(req)
+	resp, err := c.sendAuthenticated(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", url, nil)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -335,26 +479,27 @@ func (c *Client) GetLoadBalancers(ctx context.Context) ([]CloudPodsLoadBalancer,
 	return loadBalancers, nil
 }
 
-// CreateVM creates a new VM in CloudPods
+// CreateVM creates a new VM in CloudPods, running it through the
+// client's mutating and validating admission chains (if configured via
+// SetVMAdmission) first.
 func (c *Client) CreateVM(ctx context.Context, vm CloudPodsVM) (*CloudPodsVM, error) {
-	url := fmt.Sprintf("%s/api/v1/vms", c.config.API.BaseURL)
-	
-	vmData, err := json.Marshal(vm)
+	vm, err := c.admitVM(ctx, admission.VerbCreate, CloudPodsVM{}, vm)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal VM data: %w", err)
+		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(vmData))
+	url := fmt.Sprintf("%s/api/v1/vms", c.config.API.BaseURL)
+
+	vmData, err := json.Marshal(vm)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to marshal VM data: %w", err)
 	}
 
-	req.SetBasicAuth(c.config.Auth.Username, c.config.Auth.Password)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.client.Do(req)
+	resp, err := c.sendAuthenticated(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(vmData))
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
@@ -381,21 +526,76 @@ func (c *Client) CreateVM(ctx context.Context, vm CloudPodsVM) (*CloudPodsVM, er
 	return &createdVM, nil
 }
 
-// DeleteVM deletes a VM from CloudPods
-func (c *Client) DeleteVM(ctx context.Context, vmID string) error {
+// UpdateVM replaces an existing VM's fields in CloudPods, running it
+// through the client's admission chains the same way CreateVM does.
+func (c *Client) UpdateVM(ctx context.Context, vmID string, vm CloudPodsVM) (*CloudPodsVM, error) {
+	current, err := c.findVM(ctx, vmID)
+	if err != nil {
+		return nil, err
+	}
+	vm, err = c.admitVM(ctx, admission.VerbUpdate, current, vm)
+	if err != nil {
+		return nil, err
+	}
+
 	url := fmt.Sprintf("%s/api/v1/vms/%s", c.config.API.BaseURL, vmID)
-	
-	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+
+	vmData, err := json.Marshal(vm)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to marshal VM data: %w", err)
 	}
 
-	req.SetBasicAuth(c.config.Auth.Username, c.config.Auth.Password)
-	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.sendAuthenticated(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(vmData))
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
 
-	resp, err := c.client.Do(req)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status: %d", resp.StatusCode)
+	}
+
+	var response CloudPodsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if !response.Success {
+		return nil, fmt.Errorf("API returned error: %s", response.Message)
+	}
+
+	var updatedVM CloudPodsVM
+	if data, ok := response.Data.(map[string]interface{}); ok {
+		if err := mapToStruct(data, &updatedVM); err != nil {
+			return nil, fmt.Errorf("failed to convert response data: %w", err)
+		}
+	}
+
+	return &updatedVM, nil
+}
+
+// DeleteVM deletes a VM from CloudPods, running the deletion through the
+// client's admission chains if one is configured.
+func (c *Client) DeleteVM(ctx context.Context, vmID string) error {
+	if c.mutating != nil || c.validating != nil {
+		current, err := c.findVM(ctx, vmID)
+		if err != nil {
+			return err
+		}
+		if _, err := c.admitVM(ctx, admission.VerbDelete, current, current); err != nil {
+			return err
+		}
+	}
+
+	url := fmt.Sprintf("%s/api/v1/vms/%s", c.config.API.BaseURL, vmID)
+
+	resp, err := c.sendAuthenticated(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
+		return err
 	}
 	defer resp.Body.Close()
 
@@ -409,18 +609,12 @@ func (c *Client) DeleteVM(ctx context.Context, vmID string) error {
 // GetResourceMetrics retrieves metrics for a specific resource
 func (c *Client) GetResourceMetrics(ctx context.Context, resourceID string, metricType string) (map[string]interface{}, error) {
 	url := fmt.Sprintf("%s/api/v1/metrics/%s/%s", c.config.API.BaseURL, resourceID, metricType)
-	
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
 
-	req.SetBasicAuth(c.config.Auth.Username, c.config.Auth.Password)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.client.Do(req)
+	resp, err := c.sendAuthenticated(ctx, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", url, nil)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
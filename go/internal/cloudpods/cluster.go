@@ -0,0 +1,398 @@
+package cloudpods
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"router-sim/internal/config"
+)
+
+const (
+	// defaultProbeInterval is how often Cluster.Start probes every
+	// endpoint's /api/v1/health when the caller doesn't supply its own
+	// interval.
+	defaultProbeInterval = 30 * time.Second
+
+	// breakerFailureThreshold is how many consecutive failed calls (or
+	// health probes) trip an endpoint's circuit breaker.
+	breakerFailureThreshold = 3
+	// breakerCooldown is how long a tripped circuit stays open before
+	// the endpoint is considered a candidate again.
+	breakerCooldown = 30 * time.Second
+
+	retryBaseDelay = 100 * time.Millisecond
+	retryMaxDelay  = 2 * time.Second
+)
+
+// EndpointStatus is a point-in-time snapshot of one Cluster endpoint's
+// health, returned by Cluster.Status (and, through it, the
+// /api/v1/cloudpods/clusters route).
+type EndpointStatus struct {
+	Name                string    `json:"name"`
+	Region              string    `json:"region"`
+	Healthy             bool      `json:"healthy"`
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	CircuitOpen         bool      `json:"circuitOpen"`
+	LastProbeAt         time.Time `json:"lastProbeAt"`
+	LastError           string    `json:"lastError,omitempty"`
+}
+
+// Endpoint is one upstream CloudPods API a Cluster can route requests
+// to: its own Client (and therefore its own *http.Client, base URL, and
+// auth token), the region it's authoritative for, and the rolling
+// health/circuit-breaker state Cluster's probes and calls maintain.
+type Endpoint struct {
+	Name   string
+	Region string
+	Client *Client
+
+	mu                  sync.Mutex
+	healthy             bool
+	consecutiveFailures int
+	circuitOpenUntil    time.Time
+	lastProbeAt         time.Time
+	lastErr             error
+}
+
+// NewEndpoint builds an Endpoint backed by its own CloudPods client, so
+// its credentials/timeout/base URL are independent of every other
+// endpoint in the cluster.
+func NewEndpoint(name, region string, cfg *config.CloudPodsConfig) *Endpoint {
+	return &Endpoint{Name: name, Region: region, Client: NewClient(cfg), healthy: true}
+}
+
+func (e *Endpoint) status() EndpointStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	st := EndpointStatus{
+		Name:                e.Name,
+		Region:              e.Region,
+		Healthy:             e.healthy,
+		ConsecutiveFailures: e.consecutiveFailures,
+		CircuitOpen:         time.Now().Before(e.circuitOpenUntil),
+		LastProbeAt:         e.lastProbeAt,
+	}
+	if e.lastErr != nil {
+		st.LastError = e.lastErr.Error()
+	}
+	return st
+}
+
+// recordResult updates an endpoint's rolling health from the outcome of
+// a probe or a real call, opening its circuit breaker after
+// breakerFailureThreshold consecutive failures.
+func (e *Endpoint) recordResult(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lastErr = err
+	if err == nil {
+		e.consecutiveFailures = 0
+		e.healthy = true
+		e.circuitOpenUntil = time.Time{}
+		return
+	}
+	e.healthy = false
+	e.consecutiveFailures++
+	if e.consecutiveFailures >= breakerFailureThreshold {
+		e.circuitOpenUntil = time.Now().Add(breakerCooldown)
+	}
+}
+
+func (e *Endpoint) available() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return time.Now().After(e.circuitOpenUntil)
+}
+
+func (e *Endpoint) failures() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.consecutiveFailures
+}
+
+// Cluster fans a single logical CloudPods call out across a pool of
+// region-scoped Endpoints, analogous to APISIX's cluster abstraction: a
+// region-targeted call routes to the healthiest endpoint that owns it
+// with automatic failover to the next candidate, and a global call (no
+// region) fans out to every healthy endpoint and merges the results.
+type Cluster struct {
+	mu        sync.RWMutex
+	endpoints []*Endpoint
+}
+
+// NewCluster builds an empty Cluster; add upstream endpoints with
+// AddEndpoint before routing any calls through it.
+func NewCluster() *Cluster {
+	return &Cluster{}
+}
+
+// AddEndpoint registers ep as one of the Cluster's upstream CloudPods
+// APIs.
+func (c *Cluster) AddEndpoint(ep *Endpoint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.endpoints = append(c.endpoints, ep)
+}
+
+func (c *Cluster) snapshot() []*Endpoint {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]*Endpoint, len(c.endpoints))
+	copy(out, c.endpoints)
+	return out
+}
+
+// Status returns every endpoint's current health, in registration
+// order.
+func (c *Cluster) Status() []EndpointStatus {
+	eps := c.snapshot()
+	out := make([]EndpointStatus, len(eps))
+	for i, ep := range eps {
+		out[i] = ep.status()
+	}
+	return out
+}
+
+// Start runs a periodic /api/v1/health probe against every endpoint
+// (every interval, or defaultProbeInterval if interval is zero) until
+// stopCh is closed.
+func (c *Cluster) Start(stopCh <-chan struct{}, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultProbeInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		c.probeAll()
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+				c.probeAll()
+			}
+		}
+	}()
+}
+
+func (c *Cluster) probeAll() {
+	for _, ep := range c.snapshot() {
+		go c.probe(ep)
+	}
+}
+
+func (c *Cluster) probe(ep *Endpoint) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := func() error {
+		url := fmt.Sprintf("%s/api/v1/health", ep.Client.config.APIURL)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := ep.Client.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= 500 {
+			return fmt.Errorf("cloudpods: endpoint %q health probe returned %d", ep.Name, resp.StatusCode)
+		}
+		return nil
+	}()
+
+	ep.mu.Lock()
+	ep.lastProbeAt = time.Now()
+	ep.mu.Unlock()
+	ep.recordResult(err)
+}
+
+// candidates returns region's endpoints (every endpoint, if region is
+// empty), healthiest first: an endpoint whose circuit is open sorts
+// after every endpoint whose isn't, and ties break on fewer consecutive
+// failures.
+func (c *Cluster) candidates(region string) []*Endpoint {
+	var matched []*Endpoint
+	for _, ep := range c.snapshot() {
+		if region != "" && ep.Region != region {
+			continue
+		}
+		matched = append(matched, ep)
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		ai, aj := matched[i].available(), matched[j].available()
+		if ai != aj {
+			return ai
+		}
+		return matched[i].failures() < matched[j].failures()
+	})
+	return matched
+}
+
+// retryDelay returns attempt's exponential backoff (base 100ms, capped at
+// retryMaxDelay) with up to 50% jitter, so a thundering herd of callers
+// failing over at once doesn't retry in lockstep.
+func retryDelay(attempt int) time.Duration {
+	d := retryBaseDelay * time.Duration(1<<uint(attempt))
+	if d > retryMaxDelay {
+		d = retryMaxDelay
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// GetVMs returns VMs from the healthiest endpoint owning region, falling
+// over to the next candidate (after an exponential backoff with jitter)
+// on a 5xx/timeout. An empty region fans the call out to every healthy
+// endpoint and merges their VMs.
+func (c *Cluster) GetVMs(ctx context.Context, region string) ([]CloudPodsVM, error) {
+	if region == "" {
+		merged, err := fanOut(ctx, c.snapshot(), func(ctx context.Context, ep *Endpoint) ([]CloudPodsVM, error) {
+			return ep.Client.GetVMs(ctx)
+		})
+		return merged, err
+	}
+	return routeWithFailover(ctx, c.candidates(region), region, func(ctx context.Context, ep *Endpoint) ([]CloudPodsVM, error) {
+		return ep.Client.GetVMs(ctx)
+	})
+}
+
+// GetVPCs returns VPCs from the healthiest endpoint owning region, with
+// the same failover and fan-out/merge behavior as GetVMs.
+func (c *Cluster) GetVPCs(ctx context.Context, region string) ([]CloudPodsVPC, error) {
+	if region == "" {
+		return fanOut(ctx, c.snapshot(), func(ctx context.Context, ep *Endpoint) ([]CloudPodsVPC, error) {
+			return ep.Client.GetVPCs(ctx)
+		})
+	}
+	return routeWithFailover(ctx, c.candidates(region), region, func(ctx context.Context, ep *Endpoint) ([]CloudPodsVPC, error) {
+		return ep.Client.GetVPCs(ctx)
+	})
+}
+
+// routeWithFailover tries region's candidates in health order, retrying
+// the next one (after retryDelay) until call succeeds or every candidate
+// has failed.
+func routeWithFailover[T any](ctx context.Context, candidates []*Endpoint, region string, call func(context.Context, *Endpoint) (T, error)) (T, error) {
+	var zero T
+	if len(candidates) == 0 {
+		return zero, fmt.Errorf("cloudpods: no endpoint registered for region %q", region)
+	}
+
+	var lastErr error
+	for attempt, ep := range candidates {
+		if attempt > 0 {
+			select {
+			case <-time.After(retryDelay(attempt - 1)):
+			case <-ctx.Done():
+				return zero, ctx.Err()
+			}
+		}
+
+		result, err := call(ctx, ep)
+		ep.recordResult(err)
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+	}
+	return zero, fmt.Errorf("cloudpods: all endpoints for region %q failed: %w", region, lastErr)
+}
+
+// fanOut calls every available endpoint concurrently and merges their
+// results; it only fails if every endpoint did.
+func fanOut[T any](ctx context.Context, endpoints []*Endpoint, call func(context.Context, *Endpoint) ([]T, error)) ([]T, error) {
+	var (
+		mu     sync.Mutex
+		merged []T
+		errs   []error
+		wg     sync.WaitGroup
+	)
+
+	for _, ep := range endpoints {
+		if !ep.available() {
+			continue
+		}
+		wg.Add(1)
+		go func(ep *Endpoint) {
+			defer wg.Done()
+			items, err := call(ctx, ep)
+			ep.recordResult(err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", ep.Name, err))
+				return
+			}
+			merged = append(merged, items...)
+		}(ep)
+	}
+	wg.Wait()
+
+	if merged == nil && len(errs) > 0 {
+		return nil, fmt.Errorf("cloudpods: fan-out failed on every endpoint: %v", errs)
+	}
+	return merged, nil
+}
+
+// ClusterManager is a process-wide named registry of Clusters, so a
+// handler can resolve a request's ?cluster= query parameter (or fall
+// back to the first-registered default) without threading a *Cluster
+// through every call site.
+type ClusterManager struct {
+	mu       sync.RWMutex
+	clusters map[string]*Cluster
+	order    []string
+	def      string
+}
+
+// NewClusterManager builds an empty ClusterManager.
+func NewClusterManager() *ClusterManager {
+	return &ClusterManager{clusters: make(map[string]*Cluster)}
+}
+
+// Register adds cluster under name. The first cluster registered becomes
+// the default Get falls back to when the caller doesn't name one.
+func (m *ClusterManager) Register(name string, cluster *Cluster) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.clusters[name]; !exists {
+		m.order = append(m.order, name)
+	}
+	m.clusters[name] = cluster
+	if m.def == "" {
+		m.def = name
+	}
+}
+
+// Get resolves name to a registered Cluster, falling back to the default
+// cluster when name is empty.
+func (m *ClusterManager) Get(name string) (*Cluster, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if name == "" {
+		name = m.def
+	}
+	cl, ok := m.clusters[name]
+	return cl, ok
+}
+
+// Status returns every registered cluster's endpoint health, keyed by
+// cluster name, for the /api/v1/cloudpods/clusters route.
+func (m *ClusterManager) Status() map[string][]EndpointStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make(map[string][]EndpointStatus, len(m.order))
+	for _, name := range m.order {
+		out[name] = m.clusters[name].Status()
+	}
+	return out
+}
@@ -0,0 +1,30 @@
+package cloudpods
+
+import "fmt"
+
+// ErrResourceVersionConflict is returned by ApplyVPC and by
+// CloudPodsHandler's PATCH endpoints when a caller's expected
+// ResourceVersion no longer matches the server's current one. Handlers
+// translate it into an HTTP 409, the same signal a Kubernetes API server
+// gives a client that raced another writer.
+type ErrResourceVersionConflict struct {
+	Kind     string
+	ID       string
+	Expected uint64
+	Current  uint64
+}
+
+func (e *ErrResourceVersionConflict) Error() string {
+	return fmt.Sprintf("cloudpods: %s/%s resourceVersion conflict: expected %d, current %d", e.Kind, e.ID, e.Expected, e.Current)
+}
+
+// checkResourceVersion enforces optimistic concurrency for a single
+// resource. A zero expected value means the caller doesn't care about the
+// current version (the common case for a plain create/update), so it
+// always passes; any other value must match current exactly.
+func checkResourceVersion(kind, id string, expected, current uint64) error {
+	if expected != 0 && expected != current {
+		return &ErrResourceVersionConflict{Kind: kind, ID: id, Expected: expected, Current: current}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package cloudpods
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DNSRecordType is the record kind DNSRecord.Type holds.
+type DNSRecordType string
+
+const (
+	DNSRecordA     DNSRecordType = "A"
+	DNSRecordAAAA  DNSRecordType = "AAAA"
+	DNSRecordCNAME DNSRecordType = "CNAME"
+	DNSRecordTXT   DNSRecordType = "TXT"
+	DNSRecordSRV   DNSRecordType = "SRV"
+)
+
+// DNSZone is a hosted domain CloudPods' DNS subsystem serves records for.
+type DNSZone struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Nameservers []string `json:"nameservers"`
+}
+
+// DNSRecord is one record within a DNSZone. Priority and Port only apply
+// to SRV records; both are left zero otherwise.
+type DNSRecord struct {
+	ID       string        `json:"id"`
+	ZoneID   string        `json:"zone_id"`
+	Name     string        `json:"name"`
+	Type     DNSRecordType `json:"type"`
+	Value    string        `json:"value"`
+	TTL      int           `json:"ttl"`
+	Priority int           `json:"priority,omitempty"`
+	Port     int           `json:"port,omitempty"`
+}
+
+// DNSService is EnhancedClient's handle on CloudPods' DNS subsystem,
+// reachable through EnhancedClient.DNS. Beyond the zone/record CRUD,
+// it tracks which records were created on a resource's behalf via
+// Route (see route.go) so Delete* can clean them up.
+type DNSService struct {
+	client *EnhancedClient
+
+	mu      sync.Mutex
+	records map[string][]DNSRecord // keyed by owning resource ID
+}
+
+func newDNSService(client *EnhancedClient) *DNSService {
+	return &DNSService{client: client, records: make(map[string][]DNSRecord)}
+}
+
+// ListZones returns every DNSZone CloudPods hosts.
+func (s *DNSService) ListZones(ctx context.Context) ([]DNSZone, error) {
+	var resp struct {
+		Zones []DNSZone `json:"zones"`
+	}
+	if err := s.client.doRequest(ctx, http.MethodGet, "/dns/zones", nil, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list DNS zones: %w", err)
+	}
+	return resp.Zones, nil
+}
+
+// GetZone returns the DNSZone named by id.
+func (s *DNSService) GetZone(ctx context.Context, id string) (*DNSZone, error) {
+	var zone DNSZone
+	if err := s.client.doRequest(ctx, http.MethodGet, "/dns/zones/"+id, nil, nil, &zone); err != nil {
+		return nil, fmt.Errorf("failed to get DNS zone: %w", err)
+	}
+	return &zone, nil
+}
+
+// ListRecords returns every DNSRecord in zoneID.
+func (s *DNSService) ListRecords(ctx context.Context, zoneID string) ([]DNSRecord, error) {
+	var resp struct {
+		Records []DNSRecord `json:"records"`
+	}
+	if err := s.client.doRequest(ctx, http.MethodGet, "/dns/zones/"+zoneID+"/records", nil, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list DNS records: %w", err)
+	}
+	return resp.Records, nil
+}
+
+// CreateRecord adds record to zoneID.
+func (s *DNSService) CreateRecord(ctx context.Context, zoneID string, record *DNSRecord) (*DNSRecord, error) {
+	var created DNSRecord
+	if err := s.client.doRequest(ctx, http.MethodPost, "/dns/zones/"+zoneID+"/records", nil, record, &created, http.StatusCreated); err != nil {
+		return nil, fmt.Errorf("failed to create DNS record: %w", err)
+	}
+	return &created, nil
+}
+
+// DeleteRecord removes recordID from zoneID.
+func (s *DNSService) DeleteRecord(ctx context.Context, zoneID, recordID string) error {
+	if err := s.client.doRequest(ctx, http.MethodDelete, "/dns/zones/"+zoneID+"/records/"+recordID, nil, nil, nil, http.StatusNoContent); err != nil {
+		return fmt.Errorf("failed to delete DNS record: %w", err)
+	}
+	return nil
+}
+
+// zoneForHostname finds the zone whose Name is the longest suffix match
+// of hostname (e.g. zone "example.com" matches hostname
+// "api.example.com"), the same ownership rule a real DNS provider uses
+// to pick which zone a record belongs in.
+func (s *DNSService) zoneForHostname(ctx context.Context, hostname string) (*DNSZone, error) {
+	zones, err := s.ListZones(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *DNSZone
+	for i := range zones {
+		zone := &zones[i]
+		if hostname != zone.Name && !strings.HasSuffix(hostname, "."+zone.Name) {
+			continue
+		}
+		if best == nil || len(zone.Name) > len(best.Name) {
+			best = zone
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("cloudpods: no DNS zone owns hostname %q", hostname)
+	}
+	return best, nil
+}
+
+// createRouteRecord resolves hostname's owning zone and creates a record
+// of type pointing at value, the shared last step every Route
+// implementation's apply uses.
+func (s *DNSService) createRouteRecord(ctx context.Context, hostname string, recordType DNSRecordType, value string, ttl int) (*DNSRecord, error) {
+	zone, err := s.zoneForHostname(ctx, hostname)
+	if err != nil {
+		return nil, err
+	}
+	return s.CreateRecord(ctx, zone.ID, &DNSRecord{Name: hostname, Type: recordType, Value: value, TTL: ttl})
+}
+
+// track records that routes created for resourceID, so cleanup can find
+// them again on Delete*.
+func (s *DNSService) track(resourceID string, record *DNSRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[resourceID] = append(s.records[resourceID], *record)
+}
+
+// cleanup best-effort deletes every record tracked against resourceID,
+// called from DeleteInstance/DeleteLoadBalancer so a Route's record
+// doesn't outlive the resource it pointed at.
+func (s *DNSService) cleanup(ctx context.Context, resourceID string) {
+	s.mu.Lock()
+	records := s.records[resourceID]
+	delete(s.records, resourceID)
+	s.mu.Unlock()
+
+	for _, record := range records {
+		s.DeleteRecord(ctx, record.ZoneID, record.ID)
+	}
+}
+
+// applyRoutes provisions a DNS record for every route in routes, pointed
+// at address (ignored by a HostnameRoute, such as LBRoute, that resolves
+// its own target), and tracks each created record against resourceID for
+// later cleanup. Best-effort: a failing route is skipped rather than
+// aborting the rest, since DNS publication is secondary to the resource
+// itself having been created successfully.
+func (s *DNSService) applyRoutes(ctx context.Context, resourceID, address string, routes []HostnameRoute) {
+	for _, route := range routes {
+		record, err := route.apply(ctx, s.client, address)
+		if err != nil || record == nil {
+			continue
+		}
+		s.track(resourceID, record)
+	}
+}
+
+// defaultRouteTTL is the TTL a Route uses when it has none of its own to
+// offer (currently only LBRoute, since a VIP is meant to be looked up
+// fresh each time rather than cached for long).
+const defaultRouteTTL = 60
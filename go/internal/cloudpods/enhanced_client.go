@@ -1,13 +1,23 @@
 package cloudpods
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
+	"golang.org/x/time/rate"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
 	"router-sim/internal/config"
 )
 
@@ -17,6 +27,54 @@ type EnhancedClient struct {
 	httpClient *http.Client
 	baseURL    string
 	authToken  string
+
+	// authProvider, once set via SetAuthProvider, replaces the static
+	// authToken header below with an AuthTransport installed on
+	// httpClient; see auth_provider.go.
+	authProvider AuthProvider
+
+	// limiter throttles every doRequest call (including retries) to the
+	// RPS/Burst quota configured on config, so this client can't exceed
+	// whatever rate CloudPods' own API gateway enforces on it.
+	limiter *rate.Limiter
+
+	// Operations tracks the async task CloudPods returns for every
+	// mutating call below instead of the finished resource; see
+	// operations.go.
+	Operations *OperationsService
+
+	// Blueprints provisions and tears down groups of resources
+	// (Network/Instances/LoadBalancer/Storages) atomically; see
+	// blueprints.go.
+	Blueprints *BlueprintsService
+
+	// DNS publishes Instance/LoadBalancer Routes as hostname records
+	// and tracks them for cleanup on Delete*; see dns.go and route.go.
+	DNS *DNSService
+
+	// Clusters is the set of peer clusters FederatedSearch fans a query
+	// out to; nil by default, meaning FederatedSearch is unavailable
+	// until a registry is assigned. See federated_search.go.
+	Clusters *ClusterRegistry
+
+	// FederationPolicy controls FederatedSearch's per-cluster timeout and
+	// partial-failure handling; defaulted to DefaultFederationPolicy by
+	// NewEnhancedClient.
+	FederationPolicy FederationPolicy
+
+	// RetryPolicy controls doRequest's retry count, backoff, and which
+	// response statuses it retries at all; defaulted to
+	// DefaultRetryPolicy by NewEnhancedClient. See idempotency.go.
+	RetryPolicy RetryPolicy
+
+	// metrics records request counts/latency/in-flight/batch-size for
+	// every doRequest call; defaults to NopMetrics, overridable with
+	// WithMetrics. See instrumentation.go.
+	metrics ClientMetrics
+
+	// tracer starts one OTel span per doRequest call; defaults to
+	// defaultTracer(), overridable with WithTracer.
+	tracer trace.Tracer
 }
 
 // CloudPods resource types
@@ -50,6 +108,11 @@ type Instance struct {
 	KeyPair       string            `json:"key_pair"`
 	UserData      string            `json:"user_data"`
 	Metadata      map[string]string `json:"metadata"`
+
+	// Routes are published once this instance's Operation resolves;
+	// see DNSService.applyRoutes. Client-side only, has no CloudPods
+	// wire representation.
+	Routes []HostnameRoute `json:"-"`
 }
 
 type Network struct {
@@ -108,6 +171,15 @@ type LoadBalancer struct {
 	Backends    []Backend         `json:"backend"`
 	Sticky      bool              `json:"sticky"`
 	SSL         SSLConfig         `json:"ssl"`
+
+	// VIP is the virtual IP CloudPods assigns this load balancer once
+	// provisioning finishes; empty until then.
+	VIP string `json:"vip"`
+
+	// Routes are published once this load balancer's VIP is known; see
+	// DNSService.applyRoutes. Client-side only, has no CloudPods wire
+	// representation.
+	Routes []HostnameRoute `json:"-"`
 }
 
 type HealthCheck struct {
@@ -183,6 +255,7 @@ type InstanceListResponse struct {
 	Total     int        `json:"total"`
 	Page      int        `json:"page"`
 	PageSize  int        `json:"page_size"`
+	NextPage  string     `json:"next_page"`
 }
 
 type NetworkListResponse struct {
@@ -190,6 +263,7 @@ type NetworkListResponse struct {
 	Total    int       `json:"total"`
 	Page     int       `json:"page"`
 	PageSize int       `json:"page_size"`
+	NextPage string    `json:"next_page"`
 }
 
 type LoadBalancerListResponse struct {
@@ -197,6 +271,7 @@ type LoadBalancerListResponse struct {
 	Total         int            `json:"total"`
 	Page          int            `json:"page"`
 	PageSize      int            `json:"page_size"`
+	NextPage      string         `json:"next_page"`
 }
 
 type StorageListResponse struct {
@@ -204,6 +279,7 @@ type StorageListResponse struct {
 	Total    int       `json:"total"`
 	Page     int       `json:"page"`
 	PageSize int       `json:"page_size"`
+	NextPage string    `json:"next_page"`
 }
 
 // Statistics and metrics
@@ -220,678 +296,688 @@ type CloudPodsStats struct {
 	AvailableStorage   int `json:"available_storage"`
 }
 
-// NewEnhancedClient creates a new enhanced CloudPods client
-func NewEnhancedClient(config *config.CloudPodsConfig) *EnhancedClient {
-	return &EnhancedClient{
-		config: config,
+// defaultRPS and defaultBurst back EnhancedClient's rate limiter when
+// config.CloudPodsConfig leaves RPS unset (the zero value), so a client
+// built without tuning still throttles instead of hammering the API.
+const (
+	defaultRPS   = 10.0
+	defaultBurst = 20
+)
+
+// NewEnhancedClient creates a new enhanced CloudPods client. opts apply
+// after every default, so e.g. WithMetrics(NewPrometheusMetrics(nil))
+// replaces the default NopMetrics.
+func NewEnhancedClient(cfg *config.CloudPodsConfig, opts ...EnhancedClientOption) *EnhancedClient {
+	rps := cfg.RPS
+	if rps <= 0 {
+		rps = defaultRPS
+	}
+	burst := cfg.Burst
+	if burst <= 0 {
+		burst = defaultBurst
+	}
+
+	c := &EnhancedClient{
+		config: cfg,
 		httpClient: &http.Client{
-			Timeout: config.Timeout,
+			Timeout: cfg.Timeout,
 		},
-		baseURL:   config.BaseURL,
-		authToken: config.AuthToken,
-	}
+		baseURL:          cfg.APIURL,
+		authToken:        cfg.Token,
+		limiter:          rate.NewLimiter(rate.Limit(rps), burst),
+		FederationPolicy: DefaultFederationPolicy,
+		RetryPolicy:      DefaultRetryPolicy,
+		metrics:          NopMetrics{},
+		tracer:           defaultTracer(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.Operations = &OperationsService{client: c}
+	c.Blueprints = newBlueprintsService(c)
+	c.DNS = newDNSService(c)
+	return c
+}
+
+// ListQuery filters and paginates an EnhancedClient list endpoint. Page
+// and PageSize seed the first page's query string; subsequent pages are
+// driven entirely by the server's own next_page cursor (see Pager), not
+// by incrementing Page locally. This is unrelated to the package's other
+// ListOptions (see selector.go), which pages Client's in-memory
+// selector-filtered lists instead of a real paginated HTTP endpoint.
+type ListQuery struct {
+	Page     int
+	PageSize int
+	Filters  map[string]string
+	Sort     string
+}
+
+// query builds the query string for one page: cursor (the previous
+// page's NextPage) takes precedence over o.Page once pagination is
+// under way.
+func (o ListQuery) query(cursor string) url.Values {
+	q := url.Values{}
+	switch {
+	case cursor != "":
+		q.Set("page", cursor)
+	case o.Page > 0:
+		q.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PageSize > 0 {
+		q.Set("page_size", strconv.Itoa(o.PageSize))
+	}
+	if o.Sort != "" {
+		q.Set("sort", o.Sort)
+	}
+	for k, v := range o.Filters {
+		q.Set(k, v)
+	}
+	return q
+}
+
+// Pager iterates a paginated CloudPods list endpoint one page at a time.
+// The zero value's first Next call fetches page one; Next then advances
+// using the previous page's next_page cursor until the server returns
+// one that's empty.
+type Pager[T any] struct {
+	fetch  func(ctx context.Context, cursor string) ([]T, string, error)
+	cursor string
+	done   bool
+}
+
+// Next fetches the next page, or returns (nil, nil) once a previous page
+// came back with an empty next_page cursor.
+func (p *Pager[T]) Next(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+	items, next, err := p.fetch(ctx, p.cursor)
+	if err != nil {
+		return nil, err
+	}
+	p.cursor = next
+	if next == "" {
+		p.done = true
+	}
+	return items, nil
+}
+
+// All drains every remaining page into a single slice.
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	var all []T
+	for !p.done {
+		items, err := p.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
+
+// Sentinel errors APIError supports matching through errors.Is, for
+// callers that want to branch on a well-known failure class rather than
+// a raw status code.
+var (
+	ErrNotFound     = errors.New("cloudpods: resource not found")
+	ErrConflict     = errors.New("cloudpods: resource conflict")
+	ErrUnauthorized = errors.New("cloudpods: unauthorized")
+	ErrRateLimited  = errors.New("cloudpods: rate limited")
+)
+
+// apiErrorEnvelope is the JSON shape a failed response's body is parsed
+// as, best-effort: a response that isn't in this shape (or isn't JSON at
+// all) still yields an APIError, just with Code/Message left empty.
+type apiErrorEnvelope struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// APIError is the error doRequest returns when a response's status
+// isn't one of okStatus. Retryable mirrors doRequest's own 429/5xx
+// classification, and Is lets a caller write
+// errors.Is(err, cloudpods.ErrNotFound) instead of comparing StatusCode
+// directly.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Code       string
+	Message    string
+	// RetryAfter is parsed from a 429 response's Retry-After header (0
+	// if absent), and takes precedence over doRequest's own backoff
+	// schedule when retrying.
+	RetryAfter time.Duration
+	Retryable  bool
+	Body       []byte
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s %s failed with status %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("%s %s failed with status: %d", e.Method, e.Path, e.StatusCode)
+}
+
+// Is matches e against one of the sentinel errors above by status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	default:
+		return false
+	}
+}
+
+// newAPIError builds an APIError from a non-OK response, reading and
+// closing the body in the process (callers must not read resp.Body
+// afterward).
+func newAPIError(method, path string, resp *http.Response) *APIError {
+	data, _ := io.ReadAll(resp.Body)
+
+	var envelope apiErrorEnvelope
+	_ = json.Unmarshal(data, &envelope)
+
+	return &APIError{
+		Method:     method,
+		Path:       path,
+		StatusCode: resp.StatusCode,
+		Code:       envelope.Code,
+		Message:    envelope.Message,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		Retryable:  resp.StatusCode == http.StatusTooManyRequests || (resp.StatusCode >= 500 && resp.StatusCode < 600),
+		Body:       data,
+	}
+}
+
+// parseRetryAfter understands both forms RFC 7231 allows: a number of
+// seconds, or an HTTP-date. An unparseable or past header is treated the
+// same as no header at all.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if when, err := http.ParseTime(header); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// doRequestRetries, doRequestBaseBackoff, and doRequestMaxBackoff seed
+// DefaultRetryPolicy (see idempotency.go), which doRequest now consults
+// instead of these directly.
+const doRequestRetries = 5
+
+const (
+	doRequestBaseBackoff = 200 * time.Millisecond
+	doRequestMaxBackoff  = 10 * time.Second
+)
+
+func containsStatus(okStatus []int, status int) bool {
+	for _, s := range okStatus {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// doRequest is the single instrumented entry point every EnhancedClient
+// method (SearchResources, BulkCreateInstances, BulkDeleteInstances, and
+// the rest) funnels its HTTP calls through: it starts an OTel span
+// tagged {http.method, http.url, router.operation, router.resource_count}
+// around doRequestOnce, records the call's outcome and latency on
+// c.metrics, and - for a bulk endpoint (one whose path ends in "/bulk")
+// - observes a batch_size histogram sample from the request body's
+// length.
+func (c *EnhancedClient) doRequest(ctx context.Context, method, path string, query url.Values, body, out interface{}, okStatus ...int) error {
+	ctx, span := c.tracer.Start(ctx, path, trace.WithAttributes(
+		attribute.String("http.method", method),
+		attribute.String("http.url", c.baseURL+path),
+		attribute.String("router.operation", path),
+	))
+	defer span.End()
+
+	if n, ok := sliceLen(body); ok {
+		span.SetAttributes(attribute.Int("router.resource_count", n))
+		if isBulkPath(path) {
+			c.metrics.ObserveBatchSize(path, n)
+		}
+	}
+
+	c.metrics.IncInFlight(method, path)
+	defer c.metrics.DecInFlight(method, path)
+	start := time.Now()
+
+	status, err := c.doRequestOnce(ctx, method, path, query, body, out, okStatus...)
+
+	c.metrics.ObserveRequest(method, path, status, time.Since(start))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// doRequestOnce is doRequest's uninstrumented implementation: it waits
+// on the token-bucket rate limiter before every attempt (including
+// retries) and retries per c.RetryPolicy (honoring a Retry-After header
+// when the server sends one). If ctx carries an idempotency key (see
+// WithIdempotencyKey), every attempt sends it as the
+// IdempotencyKeyHeader, so a server that saw an earlier attempt can
+// replay its cached response instead of reapplying a mutation twice.
+// out, if non-nil, is the JSON decode target for a response whose status
+// is in okStatus (defaulting to just 200 when okStatus is empty); a
+// non-OK response becomes a typed *APIError. The returned int is the
+// last HTTP status observed (0 if no response was ever received).
+func (c *EnhancedClient) doRequestOnce(ctx context.Context, method, path string, query url.Values, body, out interface{}, okStatus ...int) (int, error) {
+	if len(okStatus) == 0 {
+		okStatus = []int{http.StatusOK}
+	}
+
+	var bodyBytes []byte
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		bodyBytes = data
+	}
+
+	idempotencyKey, hasIdempotencyKey := idempotencyKeyFromContext(ctx)
+
+	var lastErr error
+	for attempt := 0; attempt <= c.RetryPolicy.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(c.RetryPolicy.wait(attempt, lastErr)):
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+		}
+
+		if err := c.limiter.Wait(ctx); err != nil {
+			return 0, err
+		}
+
+		var reader io.Reader
+		if bodyBytes != nil {
+			reader = bytes.NewReader(bodyBytes)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create request: %w", err)
+		}
+		if bodyBytes != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if c.authProvider == nil {
+			req.Header.Set("Authorization", "Bearer "+c.authToken)
+		}
+		if hasIdempotencyKey {
+			req.Header.Set(IdempotencyKeyHeader, idempotencyKey)
+		}
+		if len(query) > 0 {
+			req.URL.RawQuery = query.Encode()
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("%s %s: %w", method, path, err)
+			continue
+		}
+
+		if containsStatus(okStatus, resp.StatusCode) {
+			status := resp.StatusCode
+			defer resp.Body.Close()
+			if out == nil {
+				return status, nil
+			}
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				return status, fmt.Errorf("failed to decode response: %w", err)
+			}
+			return status, nil
+		}
+
+		apiErr := newAPIError(method, path, resp)
+		resp.Body.Close()
+		lastErr = apiErr
+		if !c.RetryPolicy.retryableStatus(apiErr.StatusCode) {
+			return apiErr.StatusCode, apiErr
+		}
+	}
+
+	status := 0
+	var apiErr *APIError
+	if errors.As(lastErr, &apiErr) {
+		status = apiErr.StatusCode
+	}
+	return status, lastErr
 }
 
 // Authentication
 func (c *EnhancedClient) Authenticate(ctx context.Context) error {
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/auth/login", nil)
-	if err != nil {
-		return fmt.Errorf("failed to create auth request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+	if err := c.doRequest(ctx, http.MethodPost, "/auth/login", nil, nil, nil); err != nil {
 		return fmt.Errorf("failed to authenticate: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("authentication failed with status: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 // Instance management
-func (c *EnhancedClient) ListInstances(ctx context.Context, filters map[string]string) ([]Instance, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/instances", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Add query parameters
-	q := req.URL.Query()
-	for key, value := range filters {
-		q.Add(key, value)
-	}
-	req.URL.RawQuery = q.Encode()
-
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list instances: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to list instances with status: %d", resp.StatusCode)
-	}
-
-	var response InstanceListResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// ListInstances returns a Pager over every instance matching opts,
+// following the API's next_page cursor one page at a time; call Pager's
+// All to drain every page at once.
+func (c *EnhancedClient) ListInstances(opts ListQuery) *Pager[Instance] {
+	return &Pager[Instance]{
+		fetch: func(ctx context.Context, cursor string) ([]Instance, string, error) {
+			var resp InstanceListResponse
+			if err := c.doRequest(ctx, http.MethodGet, "/instances", opts.query(cursor), nil, &resp); err != nil {
+				return nil, "", fmt.Errorf("failed to list instances: %w", err)
+			}
+			return resp.Instances, resp.NextPage, nil
+		},
 	}
-
-	return response.Instances, nil
 }
 
 func (c *EnhancedClient) GetInstance(ctx context.Context, id string) (*Instance, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/instances/"+id, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get instance: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get instance with status: %d", resp.StatusCode)
-	}
-
 	var instance Instance
-	if err := json.NewDecoder(resp.Body).Decode(&instance); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.doRequest(ctx, http.MethodGet, "/instances/"+id, nil, nil, &instance); err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", err)
 	}
-
 	return &instance, nil
 }
 
-func (c *EnhancedClient) CreateInstance(ctx context.Context, instance *Instance) (*Instance, error) {
-	jsonData, err := json.Marshal(instance)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal instance: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/instances", 
-		bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+// CreateInstance starts provisioning instance and returns the Operation
+// tracking it; call Operations.Wait on its ID for the finished Instance.
+// If instance.Routes is non-empty, they are published in the background
+// once the Operation resolves, against the instance's PublicIP (falling
+// back to PrivateIP) - CreateInstance itself does not wait for this.
+func (c *EnhancedClient) CreateInstance(ctx context.Context, instance *Instance) (*Operation, error) {
+	var op Operation
+	if err := c.doRequest(ctx, http.MethodPost, "/instances", nil, instance, &op, http.StatusAccepted); err != nil {
 		return nil, fmt.Errorf("failed to create instance: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("failed to create instance with status: %d", resp.StatusCode)
-	}
-
-	var createdInstance Instance
-	if err := json.NewDecoder(resp.Body).Decode(&createdInstance); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if len(instance.Routes) > 0 {
+		go c.publishInstanceRoutes(op.ID, instance.Routes)
 	}
-
-	return &createdInstance, nil
+	return &op, nil
 }
 
-func (c *EnhancedClient) UpdateInstance(ctx context.Context, id string, instance *Instance) (*Instance, error) {
-	jsonData, err := json.Marshal(instance)
+// publishInstanceRoutes waits for opID's Operation to resolve, then
+// applies routes against the created Instance's public address, run in
+// the background by CreateInstance.
+func (c *EnhancedClient) publishInstanceRoutes(opID string, routes []HostnameRoute) {
+	ctx := context.Background()
+	created, err := waitForResource[Instance](ctx, c.Operations, &Operation{ID: opID})
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal instance: %w", err)
+		return
 	}
-
-	req, err := http.NewRequestWithContext(ctx, "PUT", c.baseURL+"/instances/"+id, 
-		bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	address := created.PublicIP
+	if address == "" {
+		address = created.PrivateIP
 	}
+	c.DNS.applyRoutes(ctx, created.ID, address, routes)
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+// UpdateInstance starts applying instance's changes and returns the
+// Operation tracking it.
+func (c *EnhancedClient) UpdateInstance(ctx context.Context, id string, instance *Instance) (*Operation, error) {
+	var op Operation
+	if err := c.doRequest(ctx, http.MethodPut, "/instances/"+id, nil, instance, &op, http.StatusAccepted); err != nil {
 		return nil, fmt.Errorf("failed to update instance: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to update instance with status: %d", resp.StatusCode)
-	}
-
-	var updatedInstance Instance
-	if err := json.NewDecoder(resp.Body).Decode(&updatedInstance); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	return &updatedInstance, nil
+	return &op, nil
 }
 
-func (c *EnhancedClient) DeleteInstance(ctx context.Context, id string) error {
-	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+"/instances/"+id, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
+// DeleteInstance starts deleting id and returns the Operation tracking
+// it, first cleaning up any DNS records CreateInstance published for it.
+func (c *EnhancedClient) DeleteInstance(ctx context.Context, id string) (*Operation, error) {
+	c.DNS.cleanup(ctx, id)
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to delete instance: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("failed to delete instance with status: %d", resp.StatusCode)
+	var op Operation
+	if err := c.doRequest(ctx, http.MethodDelete, "/instances/"+id, nil, nil, &op, http.StatusAccepted); err != nil {
+		return nil, fmt.Errorf("failed to delete instance: %w", err)
 	}
-
-	return nil
+	return &op, nil
 }
 
-func (c *EnhancedClient) StartInstance(ctx context.Context, id string) error {
+func (c *EnhancedClient) StartInstance(ctx context.Context, id string) (*Operation, error) {
 	return c.instanceAction(ctx, id, "start")
 }
 
-func (c *EnhancedClient) StopInstance(ctx context.Context, id string) error {
+func (c *EnhancedClient) StopInstance(ctx context.Context, id string) (*Operation, error) {
 	return c.instanceAction(ctx, id, "stop")
 }
 
-func (c *EnhancedClient) RestartInstance(ctx context.Context, id string) error {
+func (c *EnhancedClient) RestartInstance(ctx context.Context, id string) (*Operation, error) {
 	return c.instanceAction(ctx, id, "restart")
 }
 
-func (c *EnhancedClient) instanceAction(ctx context.Context, id, action string) error {
-	req, err := http.NewRequestWithContext(ctx, "POST", 
-		c.baseURL+"/instances/"+id+"/"+action, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+func (c *EnhancedClient) instanceAction(ctx context.Context, id, action string) (*Operation, error) {
+	var op Operation
+	if err := c.doRequest(ctx, http.MethodPost, "/instances/"+id+"/"+action, nil, nil, &op, http.StatusAccepted); err != nil {
+		return nil, fmt.Errorf("failed to %s instance: %w", action, err)
 	}
-
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to %s instance: %w", action, err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to %s instance with status: %d", action, resp.StatusCode)
-	}
-
-	return nil
+	return &op, nil
 }
 
 // Network management
-func (c *EnhancedClient) ListNetworks(ctx context.Context, filters map[string]string) ([]Network, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/networks", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	q := req.URL.Query()
-	for key, value := range filters {
-		q.Add(key, value)
-	}
-	req.URL.RawQuery = q.Encode()
-
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list networks: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to list networks with status: %d", resp.StatusCode)
-	}
-
-	var response NetworkListResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// ListNetworks returns a Pager over every network matching opts.
+func (c *EnhancedClient) ListNetworks(opts ListQuery) *Pager[Network] {
+	return &Pager[Network]{
+		fetch: func(ctx context.Context, cursor string) ([]Network, string, error) {
+			var resp NetworkListResponse
+			if err := c.doRequest(ctx, http.MethodGet, "/networks", opts.query(cursor), nil, &resp); err != nil {
+				return nil, "", fmt.Errorf("failed to list networks: %w", err)
+			}
+			return resp.Networks, resp.NextPage, nil
+		},
 	}
-
-	return response.Networks, nil
 }
 
 func (c *EnhancedClient) GetNetwork(ctx context.Context, id string) (*Network, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/networks/"+id, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get network: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get network with status: %d", resp.StatusCode)
-	}
-
 	var network Network
-	if err := json.NewDecoder(resp.Body).Decode(&network); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.doRequest(ctx, http.MethodGet, "/networks/"+id, nil, nil, &network); err != nil {
+		return nil, fmt.Errorf("failed to get network: %w", err)
 	}
-
 	return &network, nil
 }
 
-func (c *EnhancedClient) CreateNetwork(ctx context.Context, network *Network) (*Network, error) {
-	jsonData, err := json.Marshal(network)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal network: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/networks", 
-		bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+// CreateNetwork starts provisioning network and returns the Operation
+// tracking it.
+func (c *EnhancedClient) CreateNetwork(ctx context.Context, network *Network) (*Operation, error) {
+	var op Operation
+	if err := c.doRequest(ctx, http.MethodPost, "/networks", nil, network, &op, http.StatusAccepted); err != nil {
 		return nil, fmt.Errorf("failed to create network: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("failed to create network with status: %d", resp.StatusCode)
-	}
-
-	var createdNetwork Network
-	if err := json.NewDecoder(resp.Body).Decode(&createdNetwork); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	return &createdNetwork, nil
+	return &op, nil
 }
 
-func (c *EnhancedClient) DeleteNetwork(ctx context.Context, id string) error {
-	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+"/networks/"+id, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to delete network: %w", err)
+// DeleteNetwork starts deleting id and returns the Operation tracking it.
+func (c *EnhancedClient) DeleteNetwork(ctx context.Context, id string) (*Operation, error) {
+	var op Operation
+	if err := c.doRequest(ctx, http.MethodDelete, "/networks/"+id, nil, nil, &op, http.StatusAccepted); err != nil {
+		return nil, fmt.Errorf("failed to delete network: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("failed to delete network with status: %d", resp.StatusCode)
-	}
-
-	return nil
+	return &op, nil
 }
 
 // Load balancer management
-func (c *EnhancedClient) ListLoadBalancers(ctx context.Context, filters map[string]string) ([]LoadBalancer, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/load-balancers", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	q := req.URL.Query()
-	for key, value := range filters {
-		q.Add(key, value)
-	}
-	req.URL.RawQuery = q.Encode()
-
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list load balancers: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to list load balancers with status: %d", resp.StatusCode)
-	}
-
-	var response LoadBalancerListResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// ListLoadBalancers returns a Pager over every load balancer matching
+// opts.
+func (c *EnhancedClient) ListLoadBalancers(opts ListQuery) *Pager[LoadBalancer] {
+	return &Pager[LoadBalancer]{
+		fetch: func(ctx context.Context, cursor string) ([]LoadBalancer, string, error) {
+			var resp LoadBalancerListResponse
+			if err := c.doRequest(ctx, http.MethodGet, "/load-balancers", opts.query(cursor), nil, &resp); err != nil {
+				return nil, "", fmt.Errorf("failed to list load balancers: %w", err)
+			}
+			return resp.LoadBalancers, resp.NextPage, nil
+		},
 	}
-
-	return response.LoadBalancers, nil
 }
 
 func (c *EnhancedClient) GetLoadBalancer(ctx context.Context, id string) (*LoadBalancer, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/load-balancers/"+id, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get load balancer: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get load balancer with status: %d", resp.StatusCode)
-	}
-
 	var lb LoadBalancer
-	if err := json.NewDecoder(resp.Body).Decode(&lb); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.doRequest(ctx, http.MethodGet, "/load-balancers/"+id, nil, nil, &lb); err != nil {
+		return nil, fmt.Errorf("failed to get load balancer: %w", err)
 	}
-
 	return &lb, nil
 }
 
-func (c *EnhancedClient) CreateLoadBalancer(ctx context.Context, lb *LoadBalancer) (*LoadBalancer, error) {
-	jsonData, err := json.Marshal(lb)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal load balancer: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/load-balancers", 
-		bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+// CreateLoadBalancer starts provisioning lb and returns the Operation
+// tracking it. If lb.Routes is non-empty, they are published in the
+// background once the Operation resolves and the load balancer has a
+// VIP - CreateLoadBalancer itself does not wait for this.
+func (c *EnhancedClient) CreateLoadBalancer(ctx context.Context, lb *LoadBalancer) (*Operation, error) {
+	var op Operation
+	if err := c.doRequest(ctx, http.MethodPost, "/load-balancers", nil, lb, &op, http.StatusAccepted); err != nil {
 		return nil, fmt.Errorf("failed to create load balancer: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("failed to create load balancer with status: %d", resp.StatusCode)
+	if len(lb.Routes) > 0 {
+		go c.publishLoadBalancerRoutes(op.ID, lb.Routes)
 	}
-
-	var createdLB LoadBalancer
-	if err := json.NewDecoder(resp.Body).Decode(&createdLB); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	return &createdLB, nil
+	return &op, nil
 }
 
-func (c *EnhancedClient) DeleteLoadBalancer(ctx context.Context, id string) error {
-	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+"/load-balancers/"+id, nil)
+// publishLoadBalancerRoutes waits for opID's Operation to resolve, then
+// applies routes against the created LoadBalancer's VIP, run in the
+// background by CreateLoadBalancer.
+func (c *EnhancedClient) publishLoadBalancerRoutes(opID string, routes []HostnameRoute) {
+	ctx := context.Background()
+	created, err := waitForResource[LoadBalancer](ctx, c.Operations, &Operation{ID: opID})
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return
 	}
+	c.DNS.applyRoutes(ctx, created.ID, created.VIP, routes)
+}
 
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to delete load balancer: %w", err)
-	}
-	defer resp.Body.Close()
+// DeleteLoadBalancer starts deleting id and returns the Operation
+// tracking it, first cleaning up any DNS records CreateLoadBalancer
+// published for it.
+func (c *EnhancedClient) DeleteLoadBalancer(ctx context.Context, id string) (*Operation, error) {
+	c.DNS.cleanup(ctx, id)
 
-	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("failed to delete load balancer with status: %d", resp.StatusCode)
+	var op Operation
+	if err := c.doRequest(ctx, http.MethodDelete, "/load-balancers/"+id, nil, nil, &op, http.StatusAccepted); err != nil {
+		return nil, fmt.Errorf("failed to delete load balancer: %w", err)
 	}
-
-	return nil
+	return &op, nil
 }
 
 // Storage management
-func (c *EnhancedClient) ListStorages(ctx context.Context, filters map[string]string) ([]Storage, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/storages", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	q := req.URL.Query()
-	for key, value := range filters {
-		q.Add(key, value)
-	}
-	req.URL.RawQuery = q.Encode()
-
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list storages: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to list storages with status: %d", resp.StatusCode)
-	}
-
-	var response StorageListResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// ListStorages returns a Pager over every storage volume matching opts.
+func (c *EnhancedClient) ListStorages(opts ListQuery) *Pager[Storage] {
+	return &Pager[Storage]{
+		fetch: func(ctx context.Context, cursor string) ([]Storage, string, error) {
+			var resp StorageListResponse
+			if err := c.doRequest(ctx, http.MethodGet, "/storages", opts.query(cursor), nil, &resp); err != nil {
+				return nil, "", fmt.Errorf("failed to list storages: %w", err)
+			}
+			return resp.Storages, resp.NextPage, nil
+		},
 	}
-
-	return response.Storages, nil
 }
 
 func (c *EnhancedClient) GetStorage(ctx context.Context, id string) (*Storage, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/storages/"+id, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get storage: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get storage with status: %d", resp.StatusCode)
-	}
-
 	var storage Storage
-	if err := json.NewDecoder(resp.Body).Decode(&storage); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.doRequest(ctx, http.MethodGet, "/storages/"+id, nil, nil, &storage); err != nil {
+		return nil, fmt.Errorf("failed to get storage: %w", err)
 	}
-
 	return &storage, nil
 }
 
-func (c *EnhancedClient) CreateStorage(ctx context.Context, storage *Storage) (*Storage, error) {
-	jsonData, err := json.Marshal(storage)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal storage: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/storages", 
-		bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+// CreateStorage starts provisioning storage and returns the Operation
+// tracking it.
+func (c *EnhancedClient) CreateStorage(ctx context.Context, storage *Storage) (*Operation, error) {
+	var op Operation
+	if err := c.doRequest(ctx, http.MethodPost, "/storages", nil, storage, &op, http.StatusAccepted); err != nil {
 		return nil, fmt.Errorf("failed to create storage: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("failed to create storage with status: %d", resp.StatusCode)
-	}
-
-	var createdStorage Storage
-	if err := json.NewDecoder(resp.Body).Decode(&createdStorage); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	return &createdStorage, nil
+	return &op, nil
 }
 
-func (c *EnhancedClient) DeleteStorage(ctx context.Context, id string) error {
-	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+"/storages/"+id, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+// DeleteStorage starts deleting id and returns the Operation tracking it.
+func (c *EnhancedClient) DeleteStorage(ctx context.Context, id string) (*Operation, error) {
+	var op Operation
+	if err := c.doRequest(ctx, http.MethodDelete, "/storages/"+id, nil, nil, &op, http.StatusAccepted); err != nil {
+		return nil, fmt.Errorf("failed to delete storage: %w", err)
 	}
-
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to delete storage: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("failed to delete storage with status: %d", resp.StatusCode)
-	}
-
-	return nil
+	return &op, nil
 }
 
 // Statistics and monitoring
 func (c *EnhancedClient) GetStats(ctx context.Context) (*CloudPodsStats, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/stats", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get stats: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get stats with status: %d", resp.StatusCode)
-	}
-
 	var stats CloudPodsStats
-	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.doRequest(ctx, http.MethodGet, "/stats", nil, nil, &stats); err != nil {
+		return nil, fmt.Errorf("failed to get stats: %w", err)
 	}
-
 	return &stats, nil
 }
 
 // Resource search and filtering
-func (c *EnhancedClient) SearchResources(ctx context.Context, query string, resourceType string) ([]Resource, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/search", nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	q := req.URL.Query()
-	q.Add("q", query)
-	q.Add("type", resourceType)
-	req.URL.RawQuery = q.Encode()
-
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to search resources: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to search resources with status: %d", resp.StatusCode)
-	}
-
-	var response ListResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// SearchResources returns a Pager over every resource of resourceType
+// matching query and opts.
+func (c *EnhancedClient) SearchResources(query, resourceType string, opts ListQuery) *Pager[Resource] {
+	return &Pager[Resource]{
+		fetch: func(ctx context.Context, cursor string) ([]Resource, string, error) {
+			q := opts.query(cursor)
+			q.Set("q", query)
+			q.Set("type", resourceType)
+
+			var resp ListResponse
+			if err := c.doRequest(ctx, http.MethodGet, "/search", q, nil, &resp); err != nil {
+				return nil, "", fmt.Errorf("failed to search resources: %w", err)
+			}
+			return resp.Resources, resp.NextPage, nil
+		},
 	}
-
-	return response.Resources, nil
 }
 
 // Bulk operations
-func (c *EnhancedClient) BulkCreateInstances(ctx context.Context, instances []Instance) ([]Instance, error) {
-	jsonData, err := json.Marshal(instances)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal instances: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/instances/bulk", 
-		bytes.NewBuffer(jsonData))
+// BulkCreateInstances starts provisioning instances and returns the
+// Operation tracking the whole batch. The call carries an idempotency
+// key (generated fresh unless ctx already has one from
+// WithIdempotencyKey) on every attempt, including retries doRequest
+// makes internally, so a lost response doesn't risk the batch being
+// provisioned twice.
+func (c *EnhancedClient) BulkCreateInstances(ctx context.Context, instances []Instance) (*Operation, error) {
+	ctx, err := ensureIdempotencyKey(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+	var op Operation
+	if err := c.doRequest(ctx, http.MethodPost, "/instances/bulk", nil, instances, &op, http.StatusAccepted); err != nil {
 		return nil, fmt.Errorf("failed to bulk create instances: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("failed to bulk create instances with status: %d", resp.StatusCode)
-	}
-
-	var createdInstances []Instance
-	if err := json.NewDecoder(resp.Body).Decode(&createdInstances); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	return createdInstances, nil
+	return &op, nil
 }
 
-func (c *EnhancedClient) BulkDeleteInstances(ctx context.Context, ids []string) error {
-	jsonData, err := json.Marshal(ids)
+// BulkDeleteInstances starts deleting ids and returns the Operation
+// tracking the whole batch, with the same idempotency-key handling as
+// BulkCreateInstances.
+func (c *EnhancedClient) BulkDeleteInstances(ctx context.Context, ids []string) (*Operation, error) {
+	ctx, err := ensureIdempotencyKey(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to marshal ids: %w", err)
+		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "DELETE", c.baseURL+"/instances/bulk", 
-		bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	var op Operation
+	if err := c.doRequest(ctx, http.MethodDelete, "/instances/bulk", nil, ids, &op, http.StatusAccepted); err != nil {
+		return nil, fmt.Errorf("failed to bulk delete instances: %w", err)
 	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.authToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to bulk delete instances: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("failed to bulk delete instances with status: %d", resp.StatusCode)
-	}
-
-	return nil
+	return &op, nil
 }
@@ -0,0 +1,129 @@
+package cloudpods
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType is the kind of change a watch Event reports, mirroring
+// Kubernetes' ADDED/MODIFIED/DELETED watch semantics.
+type EventType string
+
+const (
+	EventAdded    EventType = "ADDED"
+	EventModified EventType = "MODIFIED"
+	EventDeleted  EventType = "DELETED"
+)
+
+// Event is a single change to a watched resource. ResourceVersion is a
+// monotonically increasing cursor scoped to the EventBus; a watcher resumes
+// from the event after the last ResourceVersion it saw.
+type Event struct {
+	Kind            string      `json:"kind"`
+	VPCID           string      `json:"vpc_id"`
+	Type            EventType   `json:"type"`
+	Object          interface{} `json:"object"`
+	ResourceVersion uint64      `json:"resource_version"`
+	Timestamp       time.Time   `json:"timestamp"`
+}
+
+// eventBacklog bounds how many past events an EventBus keeps per kind/VPC so
+// a watcher that reconnects with an old resourceVersion can still replay
+// without the bus growing unbounded.
+const eventBacklog = 256
+
+// EventBus is an in-memory pub/sub keyed by resource kind (e.g. "vpc",
+// "subnet") and VPC id, fed by CloudPodsHandler's mutating endpoints and
+// drained by its Watch* endpoints. It assigns every published Event the
+// next ResourceVersion so watchers can resume a stream with
+// ?resourceVersion= instead of missing events between reconnects.
+type EventBus struct {
+	mu       sync.Mutex
+	nextRV   uint64
+	backlogs map[string][]Event
+	subs     map[string]map[chan Event]struct{}
+}
+
+// NewEventBus creates an empty EventBus.
+func NewEventBus() *EventBus {
+	return &EventBus{
+		backlogs: make(map[string][]Event),
+		subs:     make(map[string]map[chan Event]struct{}),
+	}
+}
+
+func busKey(kind, vpcID string) string {
+	return kind + "/" + vpcID
+}
+
+// Publish records a new Event for kind/vpcID and fans it out to every
+// subscriber watching that key. It returns the published Event, including
+// the ResourceVersion assigned to it.
+func (b *EventBus) Publish(kind, vpcID string, eventType EventType, object interface{}) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextRV++
+	event := Event{
+		Kind:            kind,
+		VPCID:           vpcID,
+		Type:            eventType,
+		Object:          object,
+		ResourceVersion: b.nextRV,
+		Timestamp:       time.Now(),
+	}
+
+	key := busKey(kind, vpcID)
+	backlog := append(b.backlogs[key], event)
+	if len(backlog) > eventBacklog {
+		backlog = backlog[len(backlog)-eventBacklog:]
+	}
+	b.backlogs[key] = backlog
+
+	for ch := range b.subs[key] {
+		select {
+		case ch <- event:
+		default:
+			// Slow subscriber; drop the event rather than block Publish.
+			// The subscriber can detect the gap via ResourceVersion and
+			// reconnect with ?resourceVersion= to recover.
+		}
+	}
+
+	return event
+}
+
+// Subscribe registers a watcher for kind/vpcID and returns any backlog
+// events newer than sinceRV, a channel delivering subsequent events, and an
+// unsubscribe func the caller must invoke when the watch ends.
+func (b *EventBus) Subscribe(kind, vpcID string, sinceRV uint64) ([]Event, <-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := busKey(kind, vpcID)
+
+	var replay []Event
+	for _, event := range b.backlogs[key] {
+		if event.ResourceVersion > sinceRV {
+			replay = append(replay, event)
+		}
+	}
+
+	ch := make(chan Event, 64)
+	if b.subs[key] == nil {
+		b.subs[key] = make(map[chan Event]struct{})
+	}
+	b.subs[key][ch] = struct{}{}
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subs[key], ch)
+		if len(b.subs[key]) == 0 {
+			delete(b.subs, key)
+		}
+		close(ch)
+	}
+
+	return replay, ch, unsubscribe
+}
@@ -0,0 +1,414 @@
+package cloudpods
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"router-sim/internal/config"
+)
+
+// regionEndpoint is one URL a federatedRegion can route to: its own
+// EnhancedClient (and therefore its own rate limiter, auth token, and
+// base URL) plus the same consecutive-failure/circuit-breaker health
+// state Cluster's Endpoint keeps for the Client type (see cluster.go).
+type regionEndpoint struct {
+	url    string
+	client *EnhancedClient
+
+	mu                  sync.Mutex
+	consecutiveFailures int
+	circuitOpenUntil    time.Time
+	lastProbeAt         time.Time
+	lastErr             error
+}
+
+func (e *regionEndpoint) status(region string) EndpointStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	st := EndpointStatus{
+		Name:                e.url,
+		Region:              region,
+		Healthy:             time.Now().After(e.circuitOpenUntil),
+		ConsecutiveFailures: e.consecutiveFailures,
+		CircuitOpen:         time.Now().Before(e.circuitOpenUntil),
+		LastProbeAt:         e.lastProbeAt,
+	}
+	if e.lastErr != nil {
+		st.LastError = e.lastErr.Error()
+	}
+	return st
+}
+
+func (e *regionEndpoint) recordResult(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lastErr = err
+	if err == nil {
+		e.consecutiveFailures = 0
+		e.circuitOpenUntil = time.Time{}
+		return
+	}
+	e.consecutiveFailures++
+	if e.consecutiveFailures >= breakerFailureThreshold {
+		e.circuitOpenUntil = time.Now().Add(breakerCooldown)
+	}
+}
+
+func (e *regionEndpoint) available() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return time.Now().After(e.circuitOpenUntil)
+}
+
+func (e *regionEndpoint) failures() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.consecutiveFailures
+}
+
+// federatedRegion is one config.RegionConfig resolved into a primary
+// endpoint plus its ordered fallbacks; the slice order is fixed at
+// construction, candidates() only reorders by health.
+type federatedRegion struct {
+	name      string
+	endpoints []*regionEndpoint
+}
+
+// candidates returns the region's endpoints in try order: every
+// available endpoint first (primary-before-fallback among ties), then
+// endpoints whose circuit is open, ordered by fewest consecutive
+// failures - the same ranking Cluster.candidates uses for Client
+// endpoints.
+func (r *federatedRegion) candidates() []*regionEndpoint {
+	eps := make([]*regionEndpoint, len(r.endpoints))
+	copy(eps, r.endpoints)
+	sort.SliceStable(eps, func(i, j int) bool {
+		ai, aj := eps[i].available(), eps[j].available()
+		if ai != aj {
+			return ai
+		}
+		return eps[i].failures() < eps[j].failures()
+	})
+	return eps
+}
+
+// buildRegionEndpoint builds the EnhancedClient backing one endpoint URL,
+// inheriting base's timeout/rate-limit settings but pointed at url and
+// authenticating with authToken when the region overrides it.
+func buildRegionEndpoint(url string, base *config.CloudPodsConfig, authToken string) *regionEndpoint {
+	cfg := *base
+	cfg.APIURL = url
+	if authToken != "" {
+		cfg.Token = authToken
+	}
+	return &regionEndpoint{url: url, client: NewEnhancedClient(&cfg)}
+}
+
+// FederatedClient wraps a region-keyed set of EnhancedClients behind the
+// same API surface, so a caller who doesn't care which region serves a
+// call can route by name (Region) or fan a read out across every region
+// at once (ListInstancesAll, SearchResourcesGlobal, GetStatsGlobal).
+// Within a region, a failed primary automatically fails over to its
+// configured fallbacks - CloudPods' analogue of Cluster's multi-endpoint
+// failover (see cluster.go), but keyed by logical region rather than by
+// cluster name.
+type FederatedClient struct {
+	regions map[string]*federatedRegion
+	order   []string
+}
+
+// NewFederatedClient builds a FederatedClient from cfg.Regions, one
+// EnhancedClient per primary/fallback URL. A cfg with no Regions
+// configured still works: it federates to a single region named
+// "default" built from cfg.APIURL/cfg.Token, so existing single-region
+// configs don't have to opt in to get a FederatedClient.
+func NewFederatedClient(cfg *config.CloudPodsConfig) *FederatedClient {
+	regionsCfg := cfg.Regions
+	if len(regionsCfg) == 0 {
+		regionsCfg = []config.RegionConfig{{Name: "default", Primary: cfg.APIURL, AuthToken: cfg.Token}}
+	}
+
+	fc := &FederatedClient{regions: make(map[string]*federatedRegion, len(regionsCfg))}
+	for _, rc := range regionsCfg {
+		fr := &federatedRegion{name: rc.Name}
+		fr.endpoints = append(fr.endpoints, buildRegionEndpoint(rc.Primary, cfg, rc.AuthToken))
+		for _, fallback := range rc.Fallbacks {
+			fr.endpoints = append(fr.endpoints, buildRegionEndpoint(fallback, cfg, rc.AuthToken))
+		}
+		fc.regions[rc.Name] = fr
+		fc.order = append(fc.order, rc.Name)
+	}
+	return fc
+}
+
+func (f *FederatedClient) snapshot() []*federatedRegion {
+	out := make([]*federatedRegion, 0, len(f.order))
+	for _, name := range f.order {
+		out = append(out, f.regions[name])
+	}
+	return out
+}
+
+// Region returns the healthiest EnhancedClient currently serving name,
+// or false if name isn't a configured region (or every one of its
+// endpoints has its circuit open).
+func (f *FederatedClient) Region(name string) (*EnhancedClient, bool) {
+	fr, ok := f.regions[name]
+	if !ok {
+		return nil, false
+	}
+	candidates := fr.candidates()
+	if len(candidates) == 0 {
+		return nil, false
+	}
+	return candidates[0].client, true
+}
+
+// Status returns every region's endpoint health, keyed by region name,
+// in the same EndpointStatus shape Cluster.Status reports for Client
+// endpoints.
+func (f *FederatedClient) Status() map[string][]EndpointStatus {
+	out := make(map[string][]EndpointStatus, len(f.order))
+	for _, name := range f.order {
+		fr := f.regions[name]
+		statuses := make([]EndpointStatus, len(fr.endpoints))
+		for i, ep := range fr.endpoints {
+			statuses[i] = ep.status(name)
+		}
+		out[name] = statuses
+	}
+	return out
+}
+
+// StartHealthChecks runs a periodic health probe against every region's
+// endpoints (every interval, or defaultProbeInterval if interval is
+// zero) until stopCh is closed, so a failed primary's circuit recovers
+// on its own once the probe starts succeeding again instead of only
+// ever being recorded from real traffic.
+func (f *FederatedClient) StartHealthChecks(stopCh <-chan struct{}, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultProbeInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		f.probeAll()
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+				f.probeAll()
+			}
+		}
+	}()
+}
+
+func (f *FederatedClient) probeAll() {
+	for _, fr := range f.snapshot() {
+		for _, ep := range fr.endpoints {
+			go probeRegionEndpoint(ep)
+		}
+	}
+}
+
+func probeRegionEndpoint(ep *regionEndpoint) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := ep.client.doRequest(ctx, http.MethodGet, "/api/v1/health", nil, nil, nil)
+
+	ep.mu.Lock()
+	ep.lastProbeAt = time.Now()
+	ep.mu.Unlock()
+	ep.recordResult(err)
+}
+
+// regionRequestLatency and regionRequestErrors track every call
+// callRegion makes per region, so a dashboard can tell a slow region
+// apart from a failing one.
+var (
+	regionRequestLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "cloudpods_federated_region_request_duration_seconds",
+			Help:    "FederatedClient request latency against a region's active endpoint, by region.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"region"},
+	)
+	regionRequestErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cloudpods_federated_region_errors_total",
+			Help: "FederatedClient requests that failed against every available endpoint in a region.",
+		},
+		[]string{"region"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(regionRequestLatency, regionRequestErrors)
+}
+
+// callRegion tries fr's candidates in health order, recording latency
+// and error metrics for every attempt and failing over (after
+// retryDelay) until call succeeds or every endpoint has failed.
+func callRegion[T any](ctx context.Context, fr *federatedRegion, call func(context.Context, *EnhancedClient) (T, error)) (T, error) {
+	var zero T
+	candidates := fr.candidates()
+	if len(candidates) == 0 {
+		return zero, fmt.Errorf("cloudpods: no endpoint configured for region %q", fr.name)
+	}
+
+	var lastErr error
+	for attempt, ep := range candidates {
+		if attempt > 0 {
+			select {
+			case <-time.After(retryDelay(attempt - 1)):
+			case <-ctx.Done():
+				return zero, ctx.Err()
+			}
+		}
+
+		start := time.Now()
+		result, err := call(ctx, ep.client)
+		regionRequestLatency.WithLabelValues(fr.name).Observe(time.Since(start).Seconds())
+		if err != nil {
+			regionRequestErrors.WithLabelValues(fr.name).Inc()
+		}
+		ep.recordResult(err)
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+	}
+	return zero, fmt.Errorf("cloudpods: region %q: all endpoints failed: %w", fr.name, lastErr)
+}
+
+// ListInstancesAll fans ListInstances out to every region concurrently
+// (with per-region automatic failover) and merges the results. A region
+// whose every endpoint failed is omitted from the merged instances and
+// reported in the returned map instead, keyed by region name; a nil map
+// means every region succeeded.
+func (f *FederatedClient) ListInstancesAll(ctx context.Context, opts ListQuery) ([]Instance, map[string]error) {
+	var (
+		mu     sync.Mutex
+		merged []Instance
+		errs   = make(map[string]error)
+		wg     sync.WaitGroup
+	)
+
+	for _, fr := range f.snapshot() {
+		wg.Add(1)
+		go func(fr *federatedRegion) {
+			defer wg.Done()
+			items, err := callRegion(ctx, fr, func(ctx context.Context, c *EnhancedClient) ([]Instance, error) {
+				return c.ListInstances(opts).All(ctx)
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[fr.name] = err
+				return
+			}
+			merged = append(merged, items...)
+		}(fr)
+	}
+	wg.Wait()
+
+	if len(errs) == 0 {
+		errs = nil
+	}
+	return merged, errs
+}
+
+// SearchResourcesGlobal fans SearchResources out to every region
+// concurrently and merges the results, with the same per-region
+// failover and region-tagged error reporting as ListInstancesAll.
+func (f *FederatedClient) SearchResourcesGlobal(ctx context.Context, query, resourceType string, opts ListQuery) ([]Resource, map[string]error) {
+	var (
+		mu     sync.Mutex
+		merged []Resource
+		errs   = make(map[string]error)
+		wg     sync.WaitGroup
+	)
+
+	for _, fr := range f.snapshot() {
+		wg.Add(1)
+		go func(fr *federatedRegion) {
+			defer wg.Done()
+			items, err := callRegion(ctx, fr, func(ctx context.Context, c *EnhancedClient) ([]Resource, error) {
+				return c.SearchResources(query, resourceType, opts).All(ctx)
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[fr.name] = err
+				return
+			}
+			merged = append(merged, items...)
+		}(fr)
+	}
+	wg.Wait()
+
+	if len(errs) == 0 {
+		errs = nil
+	}
+	return merged, errs
+}
+
+// GetStatsGlobal fans GetStats out to every region concurrently and sums
+// the per-region totals into one CloudPodsStats, with the same
+// per-region failover and region-tagged error reporting as
+// ListInstancesAll. A region reported in the error map contributes
+// nothing to the summed totals.
+func (f *FederatedClient) GetStatsGlobal(ctx context.Context) (*CloudPodsStats, map[string]error) {
+	var (
+		mu    sync.Mutex
+		total CloudPodsStats
+		errs  = make(map[string]error)
+		wg    sync.WaitGroup
+	)
+
+	for _, fr := range f.snapshot() {
+		wg.Add(1)
+		go func(fr *federatedRegion) {
+			defer wg.Done()
+			stats, err := callRegion(ctx, fr, func(ctx context.Context, c *EnhancedClient) (*CloudPodsStats, error) {
+				return c.GetStats(ctx)
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[fr.name] = err
+				return
+			}
+			total.TotalInstances += stats.TotalInstances
+			total.RunningInstances += stats.RunningInstances
+			total.StoppedInstances += stats.StoppedInstances
+			total.TotalNetworks += stats.TotalNetworks
+			total.ActiveNetworks += stats.ActiveNetworks
+			total.TotalLoadBalancers += stats.TotalLoadBalancers
+			total.ActiveLoadBalancers += stats.ActiveLoadBalancers
+			total.TotalStorages += stats.TotalStorages
+			total.UsedStorage += stats.UsedStorage
+			total.AvailableStorage += stats.AvailableStorage
+		}(fr)
+	}
+	wg.Wait()
+
+	if len(errs) == 0 {
+		errs = nil
+	}
+	return &total, errs
+}
@@ -0,0 +1,281 @@
+package cloudpods
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"router-sim/internal/config"
+)
+
+// ClusterEndpoint is one federated peer EnhancedClient.FederatedSearch can
+// fan a query out to: its own base URL and (optionally) its own auth
+// token, independent of the root client's.
+type ClusterEndpoint struct {
+	ID        string
+	BaseURL   string
+	AuthToken string
+}
+
+// ClusterRegistry is the set of ClusterEndpoints a root EnhancedClient
+// federates search across, Arvados controller federation's cluster
+// table applied to CloudPods: FederatedSearch resolves the cluster IDs
+// it's asked to query against this registry rather than hardcoding
+// endpoints.
+type ClusterRegistry struct {
+	mu       sync.RWMutex
+	clusters map[string]ClusterEndpoint
+	order    []string
+}
+
+// NewClusterRegistry builds an empty ClusterRegistry.
+func NewClusterRegistry() *ClusterRegistry {
+	return &ClusterRegistry{clusters: make(map[string]ClusterEndpoint)}
+}
+
+// Register adds (or replaces) ep under its own ID.
+func (r *ClusterRegistry) Register(ep ClusterEndpoint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.clusters[ep.ID]; !exists {
+		r.order = append(r.order, ep.ID)
+	}
+	r.clusters[ep.ID] = ep
+}
+
+// Get returns the ClusterEndpoint registered under id.
+func (r *ClusterRegistry) Get(id string) (ClusterEndpoint, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ep, ok := r.clusters[id]
+	return ep, ok
+}
+
+// List returns every registered ClusterEndpoint, in registration order.
+func (r *ClusterRegistry) List() []ClusterEndpoint {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]ClusterEndpoint, len(r.order))
+	for i, id := range r.order {
+		out[i] = r.clusters[id]
+	}
+	return out
+}
+
+// FederationPolicy controls how FederatedSearch treats its fanned-out
+// per-cluster calls.
+type FederationPolicy struct {
+	// PerClusterTimeout bounds how long FederatedSearch waits on any one
+	// cluster before treating it as failed.
+	PerClusterTimeout time.Duration
+
+	// PartialResults, when true, merges whatever clusters succeeded and
+	// reports the rest in FederatedSearchResult.ClusterErrors instead of
+	// failing the whole call on the first cluster error.
+	PartialResults bool
+}
+
+// DefaultFederationPolicy is the policy NewEnhancedClient seeds
+// EnhancedClient.FederationPolicy with: a generous per-cluster timeout,
+// and best-effort partial results so one slow or down cluster doesn't
+// take every other cluster's results down with it.
+var DefaultFederationPolicy = FederationPolicy{
+	PerClusterTimeout: 10 * time.Second,
+	PartialResults:    true,
+}
+
+// FederatedSearchResult is FederatedSearch's return value: every
+// resource merged across the clusters that succeeded, the error (if
+// any) each failing cluster reported by ID, and an opaque NextCursor
+// combining every cluster's own pagination cursor for the next call.
+type FederatedSearchResult struct {
+	Resources     []Resource
+	ClusterErrors map[string]error
+	NextCursor    string
+}
+
+// clusterIDLen is the length of the cluster-ID prefix FederatedSearch
+// tags a Resource's ID with, Arvados UUID's 5-character cluster segment.
+const clusterIDLen = 5
+
+// rewriteResourceID tags id with clusterID's prefix (clusterID + "-" +
+// id) unless id already carries it, so a resource merged from multiple
+// clusters has a canonical ID to dedupe and paginate by.
+func rewriteResourceID(clusterID, id string) string {
+	if prefix, ok := splitClusterID(id); ok && prefix == clusterID {
+		return id
+	}
+	return clusterID + "-" + id
+}
+
+// splitClusterID extracts id's leading cluster-ID prefix, if it has one.
+func splitClusterID(id string) (string, bool) {
+	if len(id) <= clusterIDLen || id[clusterIDLen] != '-' {
+		return "", false
+	}
+	return id[:clusterIDLen], true
+}
+
+// encodeFederatedCursor packs a per-cluster cursor map into the single
+// opaque string FederatedSearchResult.NextCursor exposes.
+func encodeFederatedCursor(cursors map[string]string) string {
+	if len(cursors) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(cursors)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeFederatedCursor unpacks a cursor FederatedSearch previously
+// returned back into its per-cluster cursor map; an empty cursor decodes
+// to a nil map, meaning every cluster starts from its first page.
+func decodeFederatedCursor(cursor string) (map[string]string, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("cloudpods: invalid federated search cursor: %w", err)
+	}
+	var cursors map[string]string
+	if err := json.Unmarshal(data, &cursors); err != nil {
+		return nil, fmt.Errorf("cloudpods: invalid federated search cursor: %w", err)
+	}
+	return cursors, nil
+}
+
+// clientForCluster builds the EnhancedClient FederatedSearch uses to
+// query ep, inheriting base's timeout/rate-limit settings but pointed at
+// ep's own base URL and (if set) its own auth token.
+func clientForCluster(base *config.CloudPodsConfig, ep ClusterEndpoint) *EnhancedClient {
+	cfg := *base
+	cfg.APIURL = ep.BaseURL
+	if ep.AuthToken != "" {
+		cfg.Token = ep.AuthToken
+	}
+	return NewEnhancedClient(&cfg)
+}
+
+// searchOnePage issues a single /search page against client, the same
+// request SearchResources's Pager makes internally, exposed standalone
+// here since FederatedSearch drives each cluster's pagination cursor
+// itself rather than through a Pager.
+func searchOnePage(ctx context.Context, client *EnhancedClient, query, resourceType, cursor string) (ListResponse, error) {
+	q := ListQuery{}.query(cursor)
+	q.Set("q", query)
+	q.Set("type", resourceType)
+
+	var resp ListResponse
+	if err := client.doRequest(ctx, http.MethodGet, "/search", q, nil, &resp); err != nil {
+		return ListResponse{}, fmt.Errorf("failed to search resources: %w", err)
+	}
+	return resp, nil
+}
+
+// FederatedSearch fans a search out to every cluster in clusterIDs (or
+// every cluster in c.Clusters if clusterIDs is empty), tags each
+// returned Resource's ID with its origin cluster (see
+// rewriteResourceID), deduplicates the merged set by that canonical ID,
+// and combines every cluster's own next-page cursor into a single
+// opaque FederatedSearchResult.NextCursor - pass it back in as cursor to
+// continue. c.FederationPolicy.PartialResults controls whether a single
+// cluster's failure fails the whole call or is merged best-effort and
+// reported in FederatedSearchResult.ClusterErrors instead.
+func (c *EnhancedClient) FederatedSearch(ctx context.Context, query, resourceType string, clusterIDs []string, cursor string) (*FederatedSearchResult, error) {
+	if c.Clusters == nil {
+		return nil, fmt.Errorf("cloudpods: FederatedSearch requires a ClusterRegistry set on EnhancedClient.Clusters")
+	}
+
+	cursors, err := decodeFederatedCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := clusterIDs
+	if len(targets) == 0 {
+		for _, ep := range c.Clusters.List() {
+			targets = append(targets, ep.ID)
+		}
+	}
+
+	policy := c.FederationPolicy
+	if policy.PerClusterTimeout <= 0 {
+		policy.PerClusterTimeout = DefaultFederationPolicy.PerClusterTimeout
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu            sync.Mutex
+		seen          = make(map[string]struct{})
+		merged        []Resource
+		clusterErrors = make(map[string]error)
+		nextCursors   = make(map[string]string)
+		wg            sync.WaitGroup
+		failFast      error
+	)
+
+	for _, clusterID := range targets {
+		clusterID := clusterID
+
+		ep, ok := c.Clusters.Get(clusterID)
+		if !ok {
+			clusterErrors[clusterID] = fmt.Errorf("cloudpods: cluster %q not registered", clusterID)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			clusterCtx, clusterCancel := context.WithTimeout(ctx, policy.PerClusterTimeout)
+			defer clusterCancel()
+
+			client := clientForCluster(c.config, ep)
+			resp, err := searchOnePage(clusterCtx, client, query, resourceType, cursors[clusterID])
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				clusterErrors[clusterID] = err
+				if !policy.PartialResults && failFast == nil {
+					failFast = fmt.Errorf("cloudpods: federated search failed on cluster %q: %w", clusterID, err)
+					cancel()
+				}
+				return
+			}
+
+			if resp.NextPage != "" {
+				nextCursors[clusterID] = resp.NextPage
+			}
+			for _, resource := range resp.Resources {
+				resource.ID = rewriteResourceID(clusterID, resource.ID)
+				if _, dup := seen[resource.ID]; dup {
+					continue
+				}
+				seen[resource.ID] = struct{}{}
+				merged = append(merged, resource)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if failFast != nil {
+		return nil, failFast
+	}
+
+	result := &FederatedSearchResult{Resources: merged, NextCursor: encodeFederatedCursor(nextCursors)}
+	if len(clusterErrors) > 0 {
+		result.ClusterErrors = clusterErrors
+	}
+	return result, nil
+}
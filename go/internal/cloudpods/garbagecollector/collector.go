@@ -0,0 +1,210 @@
+// Package garbagecollector cascades deletes across related CloudPods
+// resources, modeled on Kubernetes' garbage collector: resources track an
+// optional set of owners, and deleting an owner walks the dependency graph
+// to clean up (or orphan) whatever it owns instead of leaving dangling
+// children behind.
+package garbagecollector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// PropagationPolicy controls what happens to a deleted resource's
+// dependents, mirroring Kubernetes' deleteOptions.propagationPolicy.
+type PropagationPolicy string
+
+const (
+	// PropagationForeground deletes every dependent before the owner is
+	// considered deleted, blocking the caller until the whole subtree is
+	// gone.
+	PropagationForeground PropagationPolicy = "Foreground"
+	// PropagationBackground deletes the owner immediately and cascades to
+	// dependents asynchronously. This is the default, matching
+	// Kubernetes' default policy.
+	PropagationBackground PropagationPolicy = "Background"
+	// PropagationOrphan deletes only the owner; dependents are left in
+	// place with their (now dangling) owner reference.
+	PropagationOrphan PropagationPolicy = "Orphan"
+)
+
+// OwnerReference names another resource that owns this one, as supplied by
+// a client's `ownerReferences` field on create.
+type OwnerReference struct {
+	Kind               string `json:"kind"`
+	ID                 string `json:"id"`
+	BlockOwnerDeletion bool   `json:"blockOwnerDeletion"`
+}
+
+// Node is a tracked resource: its identity plus the owners it was created
+// with.
+type Node struct {
+	Kind   string           `json:"kind"`
+	ID     string           `json:"id"`
+	VPCID  string           `json:"vpc_id,omitempty"`
+	Owners []OwnerReference `json:"ownerReferences,omitempty"`
+}
+
+// DeleteFunc removes a single resource of a registered kind. vpcID is the
+// scoping VPC for kinds nested under one (subnets, NAT gateways, load
+// balancers, service-mesh routes); it is ignored by the "vpc" kind itself.
+type DeleteFunc func(ctx context.Context, vpcID, id string) error
+
+// ResourceKind is how a resource type plugs into the Collector: a name
+// used in OwnerReference.Kind and a DeleteFunc to call when the collector
+// decides to remove an instance of it. New resource types register
+// themselves without the Collector needing any kind-specific code.
+type ResourceKind struct {
+	Name   string
+	Delete DeleteFunc
+}
+
+// Collector tracks owner/dependent relationships between CloudPods
+// resources and cascades deletes across them according to a
+// PropagationPolicy.
+type Collector struct {
+	mu       sync.Mutex
+	kinds    map[string]ResourceKind
+	nodes    map[string]Node
+	children map[string][]Node
+}
+
+// NewCollector creates an empty Collector.
+func NewCollector() *Collector {
+	return &Collector{
+		kinds:    make(map[string]ResourceKind),
+		nodes:    make(map[string]Node),
+		children: make(map[string][]Node),
+	}
+}
+
+func nodeKey(kind, id string) string {
+	return kind + "/" + id
+}
+
+// RegisterKind makes kind deletable by the Collector. Registering the same
+// name twice replaces the previous registration.
+func (c *Collector) RegisterKind(kind ResourceKind) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.kinds[kind.Name] = kind
+}
+
+// Kinds lists every registered, deletable kind in sorted order.
+func (c *Collector) Kinds() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := make([]string, 0, len(c.kinds))
+	for name := range c.kinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Track records node's owner references so a future delete of one of its
+// owners can find it. Calling Track again for the same kind/ID replaces its
+// prior owners.
+func (c *Collector) Track(node Node) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := nodeKey(node.Kind, node.ID)
+	if existing, ok := c.nodes[key]; ok {
+		c.detachLocked(existing)
+	}
+
+	c.nodes[key] = node
+	for _, owner := range node.Owners {
+		ownerKey := nodeKey(owner.Kind, owner.ID)
+		c.children[ownerKey] = append(c.children[ownerKey], node)
+	}
+}
+
+// Untrack stops tracking kind/id, removing it from its owners' dependent
+// lists. It does not touch whatever it owned.
+func (c *Collector) Untrack(kind, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := nodeKey(kind, id)
+	node, ok := c.nodes[key]
+	if !ok {
+		return
+	}
+	c.detachLocked(node)
+	delete(c.nodes, key)
+}
+
+// detachLocked removes node from every owner's children list. Callers must
+// hold c.mu.
+func (c *Collector) detachLocked(node Node) {
+	for _, owner := range node.Owners {
+		ownerKey := nodeKey(owner.Kind, owner.ID)
+		siblings := c.children[ownerKey]
+		for i, sibling := range siblings {
+			if sibling.Kind == node.Kind && sibling.ID == node.ID {
+				c.children[ownerKey] = append(siblings[:i], siblings[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Dependents returns the tracked resources whose owner references include
+// kind/id.
+func (c *Collector) Dependents(kind, id string) []Node {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	deps := c.children[nodeKey(kind, id)]
+	out := make([]Node, len(deps))
+	copy(out, deps)
+	return out
+}
+
+// Delete removes kind/id according to policy, cascading to its dependents:
+// Foreground deletes the whole subtree before returning, Background
+// deletes the owner first and cascades asynchronously, and Orphan deletes
+// only the owner, leaving dependents with a dangling owner reference.
+func (c *Collector) Delete(ctx context.Context, kind, vpcID, id string, policy PropagationPolicy) error {
+	c.mu.Lock()
+	rk, ok := c.kinds[kind]
+	c.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("garbagecollector: no registered kind %q", kind)
+	}
+
+	dependents := c.Dependents(kind, id)
+
+	switch policy {
+	case PropagationOrphan:
+		// Leave dependents in place; their owner reference now dangles.
+	case PropagationForeground:
+		for _, dep := range dependents {
+			if err := c.Delete(ctx, dep.Kind, dep.VPCID, dep.ID, PropagationForeground); err != nil {
+				return fmt.Errorf("cascading delete of dependent %s/%s: %w", dep.Kind, dep.ID, err)
+			}
+		}
+	default: // PropagationBackground, and the unset/default case
+		policy = PropagationBackground
+	}
+
+	if err := rk.Delete(ctx, vpcID, id); err != nil {
+		return err
+	}
+
+	c.Untrack(kind, id)
+
+	if policy == PropagationBackground {
+		go func(deps []Node) {
+			for _, dep := range deps {
+				_ = c.Delete(context.Background(), dep.Kind, dep.VPCID, dep.ID, PropagationBackground)
+			}
+		}(dependents)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package garbagecollector
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func recordingDeleteFunc(deleted *[]string, mu *sync.Mutex) DeleteFunc {
+	return func(ctx context.Context, vpcID, id string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		*deleted = append(*deleted, id)
+		return nil
+	}
+}
+
+func newTestCollector(t *testing.T, deleted *[]string, mu *sync.Mutex) *Collector {
+	t.Helper()
+	c := NewCollector()
+	c.RegisterKind(ResourceKind{Name: "vpc", Delete: recordingDeleteFunc(deleted, mu)})
+	c.RegisterKind(ResourceKind{Name: "subnet", Delete: recordingDeleteFunc(deleted, mu)})
+	return c
+}
+
+func TestCollectorKindsReturnsRegisteredNamesSorted(t *testing.T) {
+	c := NewCollector()
+	c.RegisterKind(ResourceKind{Name: "subnet"})
+	c.RegisterKind(ResourceKind{Name: "vpc"})
+
+	kinds := c.Kinds()
+	if len(kinds) != 2 || kinds[0] != "subnet" || kinds[1] != "vpc" {
+		t.Fatalf("Kinds() = %v, want [subnet vpc]", kinds)
+	}
+}
+
+func TestCollectorDependentsFindsTrackedChildren(t *testing.T) {
+	c := NewCollector()
+	c.Track(Node{Kind: "vpc", ID: "vpc-1"})
+	c.Track(Node{Kind: "subnet", ID: "subnet-1", VPCID: "vpc-1", Owners: []OwnerReference{{Kind: "vpc", ID: "vpc-1"}}})
+
+	deps := c.Dependents("vpc", "vpc-1")
+	if len(deps) != 1 || deps[0].ID != "subnet-1" {
+		t.Fatalf("Dependents(vpc, vpc-1) = %+v, want [subnet-1]", deps)
+	}
+}
+
+func TestCollectorUntrackRemovesFromOwnersChildren(t *testing.T) {
+	c := NewCollector()
+	c.Track(Node{Kind: "vpc", ID: "vpc-1"})
+	c.Track(Node{Kind: "subnet", ID: "subnet-1", Owners: []OwnerReference{{Kind: "vpc", ID: "vpc-1"}}})
+
+	c.Untrack("subnet", "subnet-1")
+
+	if deps := c.Dependents("vpc", "vpc-1"); len(deps) != 0 {
+		t.Fatalf("Dependents(vpc, vpc-1) after Untrack = %+v, want none", deps)
+	}
+}
+
+func TestCollectorTrackTwiceReplacesOwners(t *testing.T) {
+	c := NewCollector()
+	c.Track(Node{Kind: "vpc", ID: "vpc-1"})
+	c.Track(Node{Kind: "vpc", ID: "vpc-2"})
+	c.Track(Node{Kind: "subnet", ID: "subnet-1", Owners: []OwnerReference{{Kind: "vpc", ID: "vpc-1"}}})
+
+	// Re-track the same subnet under a different owner.
+	c.Track(Node{Kind: "subnet", ID: "subnet-1", Owners: []OwnerReference{{Kind: "vpc", ID: "vpc-2"}}})
+
+	if deps := c.Dependents("vpc", "vpc-1"); len(deps) != 0 {
+		t.Fatalf("Dependents(vpc, vpc-1) after re-tracking under vpc-2 = %+v, want none", deps)
+	}
+	if deps := c.Dependents("vpc", "vpc-2"); len(deps) != 1 || deps[0].ID != "subnet-1" {
+		t.Fatalf("Dependents(vpc, vpc-2) = %+v, want [subnet-1]", deps)
+	}
+}
+
+func TestCollectorDeleteUnregisteredKindErrors(t *testing.T) {
+	c := NewCollector()
+	if err := c.Delete(context.Background(), "vpc", "", "vpc-1", PropagationBackground); err == nil {
+		t.Fatalf("Delete with no registered kind returned nil error, want one")
+	}
+}
+
+func TestCollectorDeleteOrphanLeavesDependents(t *testing.T) {
+	var deleted []string
+	var mu sync.Mutex
+	c := newTestCollector(t, &deleted, &mu)
+	c.Track(Node{Kind: "vpc", ID: "vpc-1"})
+	c.Track(Node{Kind: "subnet", ID: "subnet-1", VPCID: "vpc-1", Owners: []OwnerReference{{Kind: "vpc", ID: "vpc-1"}}})
+
+	if err := c.Delete(context.Background(), "vpc", "", "vpc-1", PropagationOrphan); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(deleted) != 1 || deleted[0] != "vpc-1" {
+		t.Fatalf("deleted = %v, want only vpc-1 since PropagationOrphan must not cascade", deleted)
+	}
+}
+
+func TestCollectorDeleteForegroundCascadesBeforeReturning(t *testing.T) {
+	var deleted []string
+	var mu sync.Mutex
+	c := newTestCollector(t, &deleted, &mu)
+	c.Track(Node{Kind: "vpc", ID: "vpc-1"})
+	c.Track(Node{Kind: "subnet", ID: "subnet-1", VPCID: "vpc-1", Owners: []OwnerReference{{Kind: "vpc", ID: "vpc-1"}}})
+
+	if err := c.Delete(context.Background(), "vpc", "", "vpc-1", PropagationForeground); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(deleted) != 2 {
+		t.Fatalf("deleted = %v, want both subnet-1 and vpc-1 deleted synchronously", deleted)
+	}
+	if deleted[0] != "subnet-1" || deleted[1] != "vpc-1" {
+		t.Fatalf("deleted = %v, want the dependent deleted before its owner", deleted)
+	}
+}
+
+func TestCollectorDeleteBackgroundCascadesAsynchronously(t *testing.T) {
+	var deleted []string
+	var mu sync.Mutex
+	c := newTestCollector(t, &deleted, &mu)
+	c.Track(Node{Kind: "vpc", ID: "vpc-1"})
+	c.Track(Node{Kind: "subnet", ID: "subnet-1", VPCID: "vpc-1", Owners: []OwnerReference{{Kind: "vpc", ID: "vpc-1"}}})
+
+	if err := c.Delete(context.Background(), "vpc", "", "vpc-1", PropagationBackground); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	mu.Lock()
+	immediatelyDeleted := append([]string(nil), deleted...)
+	mu.Unlock()
+	if len(immediatelyDeleted) != 1 || immediatelyDeleted[0] != "vpc-1" {
+		t.Fatalf("deleted right after Delete returns = %v, want only vpc-1 (subnet cascades asynchronously)", immediatelyDeleted)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(deleted)
+		mu.Unlock()
+		if n == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("deleted = %v after waiting, want subnet-1 eventually cascaded in the background", deleted)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCollectorDeleteUntracksOwner(t *testing.T) {
+	var deleted []string
+	var mu sync.Mutex
+	c := newTestCollector(t, &deleted, &mu)
+	c.Track(Node{Kind: "vpc", ID: "vpc-1"})
+
+	if err := c.Delete(context.Background(), "vpc", "", "vpc-1", PropagationOrphan); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if _, ok := c.nodes[nodeKey("vpc", "vpc-1")]; ok {
+		t.Fatalf("node vpc/vpc-1 still tracked after Delete, want it untracked")
+	}
+}
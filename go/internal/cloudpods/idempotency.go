@@ -0,0 +1,132 @@
+package cloudpods
+
+import (
+	"context"
+	cryptorand "crypto/rand"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// IdempotencyKeyHeader is the header EnhancedClient attaches an
+// idempotency key under, so a retried bulk mutation can be safely
+// replayed server-side instead of double-applying.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyContextKey int
+
+const idempotencyKeyCtxKey idempotencyContextKey = iota
+
+// WithIdempotencyKey returns a copy of ctx carrying key; doRequest
+// attaches it as the IdempotencyKeyHeader on every attempt (including
+// retries) of the request made with that ctx, so BulkCreateInstances and
+// BulkDeleteInstances can retry a transport failure without risking a
+// duplicate bulk job on the server.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtxKey, key)
+}
+
+// idempotencyKeyFromContext returns the idempotency key ctx carries, and
+// whether one was set at all.
+func idempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyCtxKey).(string)
+	return key, ok
+}
+
+// newIdempotencyKey generates a random RFC 4122 version-4 UUID, used as
+// the default idempotency key for a bulk mutation whose ctx doesn't
+// already carry one from WithIdempotencyKey.
+func newIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := cryptorand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("cloudpods: generating idempotency key: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// ensureIdempotencyKey returns ctx as-is if it already carries an
+// idempotency key, otherwise a copy carrying a freshly generated one -
+// BulkCreateInstances/BulkDeleteInstances call this so every attempt at
+// the same logical bulk mutation (including ones a caller retries by
+// hand after a timeout) can share one key by threading ctx through.
+func ensureIdempotencyKey(ctx context.Context) (context.Context, error) {
+	if _, ok := idempotencyKeyFromContext(ctx); ok {
+		return ctx, nil
+	}
+	key, err := newIdempotencyKey()
+	if err != nil {
+		return ctx, err
+	}
+	return WithIdempotencyKey(ctx, key), nil
+}
+
+// RetryPolicy controls how doRequest retries a failed attempt: how many
+// times, how long to wait between attempts, and which response statuses
+// are even worth retrying. It replaces the package's previous hardcoded
+// doRequestRetries/doRequestBaseBackoff/doRequestMaxBackoff constants
+// with something a caller can tune per EnhancedClient - a bulk mutation
+// carrying an idempotency key can afford a more aggressive policy than
+// one that can't be safely replayed.
+type RetryPolicy struct {
+	MaxAttempts       int
+	BaseDelay         time.Duration
+	MaxDelay          time.Duration
+	Jitter            bool
+	RetryableStatuses []int
+}
+
+// DefaultRetryPolicy matches doRequest's previous fixed behavior: up to
+// doRequestRetries retries of a 429 or 5xx response, full-jitter
+// exponential backoff between doRequestBaseBackoff and
+// doRequestMaxBackoff.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: doRequestRetries,
+	BaseDelay:   doRequestBaseBackoff,
+	MaxDelay:    doRequestMaxBackoff,
+	Jitter:      true,
+	RetryableStatuses: []int{
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+// retryableStatus reports whether status is one p retries, defaulting to
+// the classic 429/5xx rule when RetryableStatuses is unset.
+func (p RetryPolicy) retryableStatus(status int) bool {
+	if len(p.RetryableStatuses) == 0 {
+		return status == http.StatusTooManyRequests || (status >= 500 && status < 600)
+	}
+	for _, s := range p.RetryableStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// wait computes how long to sleep before the given retry attempt
+// (1-indexed), honoring an *APIError's Retry-After when lastErr carries
+// one, and otherwise exponential backoff between p.BaseDelay and
+// p.MaxDelay, full-jittered when p.Jitter is set.
+func (p RetryPolicy) wait(attempt int, lastErr error) time.Duration {
+	var apiErr *APIError
+	if errors.As(lastErr, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+
+	backoff := p.BaseDelay * time.Duration(int64(1)<<uint(attempt-1))
+	if p.MaxDelay > 0 && backoff > p.MaxDelay {
+		backoff = p.MaxDelay
+	}
+	if !p.Jitter || backoff <= 0 {
+		return backoff
+	}
+	return time.Duration(rand.Int63n(int64(backoff)))
+}
@@ -0,0 +1,573 @@
+package cloudpods
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// DefaultResyncInterval is how often an InformerFactory re-LISTs a
+// resource type when the caller doesn't supply one, matching the cadence
+// most CloudPods dashboards already poll at today.
+const DefaultResyncInterval = 30 * time.Second
+
+// resourceMeta is the subset of a CloudPods object's identity an
+// InformerFactory needs to index and diff it, extracted from whichever
+// concrete CloudPods* type a reflector is listing.
+type resourceMeta struct {
+	id        string
+	vpc       string
+	region    string
+	zone      string
+	tags      map[string]string
+	updatedAt time.Time
+}
+
+// store is a thread-safe, ID-keyed cache of one resource type's objects,
+// with secondary indices on VPC/region/zone/tag so the typed Lister
+// methods (ByVPC, etc.) don't have to scan every entry. It's modeled on
+// client-go's ThreadSafeStore: callers only ever see the typed Lister
+// built on top, never the store itself.
+type store struct {
+	mu      sync.RWMutex
+	objects map[string]interface{}
+	meta    map[string]resourceMeta
+	hashes  map[string]string
+
+	byVPC    map[string]map[string]struct{}
+	byRegion map[string]map[string]struct{}
+	byZone   map[string]map[string]struct{}
+	byTag    map[string]map[string]struct{}
+}
+
+func newStore() *store {
+	return &store{
+		objects:  make(map[string]interface{}),
+		meta:     make(map[string]resourceMeta),
+		hashes:   make(map[string]string),
+		byVPC:    make(map[string]map[string]struct{}),
+		byRegion: make(map[string]map[string]struct{}),
+		byZone:   make(map[string]map[string]struct{}),
+		byTag:    make(map[string]map[string]struct{}),
+	}
+}
+
+func hashOf(obj interface{}) string {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func addToIndex(index map[string]map[string]struct{}, key, id string) {
+	if key == "" {
+		return
+	}
+	set, ok := index[key]
+	if !ok {
+		set = make(map[string]struct{})
+		index[key] = set
+	}
+	set[id] = struct{}{}
+}
+
+func removeFromIndex(index map[string]map[string]struct{}, key, id string) {
+	if key == "" {
+		return
+	}
+	if set, ok := index[key]; ok {
+		delete(set, id)
+		if len(set) == 0 {
+			delete(index, key)
+		}
+	}
+}
+
+func (s *store) unindexLocked(id string) {
+	m, ok := s.meta[id]
+	if !ok {
+		return
+	}
+	removeFromIndex(s.byVPC, m.vpc, id)
+	removeFromIndex(s.byRegion, m.region, id)
+	removeFromIndex(s.byZone, m.zone, id)
+	for tag := range m.tags {
+		removeFromIndex(s.byTag, tag, id)
+	}
+	delete(s.meta, id)
+	delete(s.objects, id)
+	delete(s.hashes, id)
+}
+
+func (s *store) indexLocked(id string, obj interface{}, m resourceMeta) {
+	s.objects[id] = obj
+	s.meta[id] = m
+	s.hashes[id] = hashOf(obj)
+	addToIndex(s.byVPC, m.vpc, id)
+	addToIndex(s.byRegion, m.region, id)
+	addToIndex(s.byZone, m.zone, id)
+	for tag := range m.tags {
+		addToIndex(s.byTag, tag, id)
+	}
+}
+
+// sync reconciles the store against a freshly-LISTed set of objects,
+// keyed and described by extract, and returns the Added/Modified/Deleted
+// events the reconciliation produced. An object is Modified only if its
+// UpdatedAt advanced or its content hash changed, so a resync that
+// observes no real change emits nothing.
+func (s *store) sync(kind string, objects []interface{}, extract func(interface{}) resourceMeta) []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[string]struct{}, len(objects))
+	var deltas []Event
+
+	for _, obj := range objects {
+		m := extract(obj)
+		seen[m.id] = struct{}{}
+
+		prevHash, existed := s.hashes[m.id]
+		newHash := hashOf(obj)
+		if !existed {
+			s.indexLocked(m.id, obj, m)
+			deltas = append(deltas, Event{Kind: kind, VPCID: m.vpc, Type: EventAdded, Object: obj, Timestamp: time.Now()})
+			continue
+		}
+
+		prevMeta := s.meta[m.id]
+		if newHash != prevHash || m.updatedAt.After(prevMeta.updatedAt) {
+			s.unindexLocked(m.id)
+			s.indexLocked(m.id, obj, m)
+			deltas = append(deltas, Event{Kind: kind, VPCID: m.vpc, Type: EventModified, Object: obj, Timestamp: time.Now()})
+		}
+	}
+
+	for id, obj := range s.objects {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		vpc := s.meta[id].vpc
+		s.unindexLocked(id)
+		deltas = append(deltas, Event{Kind: kind, VPCID: vpc, Type: EventDeleted, Object: obj, Timestamp: time.Now()})
+	}
+
+	return deltas
+}
+
+func (s *store) list() []interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]interface{}, 0, len(s.objects))
+	for _, obj := range s.objects {
+		out = append(out, obj)
+	}
+	return out
+}
+
+func (s *store) get(id string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	obj, ok := s.objects[id]
+	return obj, ok
+}
+
+func (s *store) byIndex(index map[string]map[string]struct{}, key string) []interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids := index[key]
+	out := make([]interface{}, 0, len(ids))
+	for id := range ids {
+		out = append(out, s.objects[id])
+	}
+	return out
+}
+
+// VMLister reads VMs out of an InformerFactory's local cache instead of
+// calling GetVMs, so a handler fanning out to every resource type per
+// request does one map lookup per type instead of one HTTP round trip.
+type VMLister interface {
+	List() []CloudPodsVM
+	Get(id string) (CloudPodsVM, bool)
+	ByVPC(vpcID string) []CloudPodsVM
+}
+
+// VPCLister reads VPCs out of an InformerFactory's local cache.
+type VPCLister interface {
+	List() []CloudPodsVPC
+	Get(id string) (CloudPodsVPC, bool)
+}
+
+// SubnetLister reads subnets out of an InformerFactory's local cache.
+type SubnetLister interface {
+	List() []CloudPodsSubnet
+	Get(id string) (CloudPodsSubnet, bool)
+	ByVPC(vpcID string) []CloudPodsSubnet
+}
+
+// SecurityGroupLister reads security groups out of an InformerFactory's
+// local cache.
+type SecurityGroupLister interface {
+	List() []CloudPodsSecurityGroup
+	Get(id string) (CloudPodsSecurityGroup, bool)
+	ByVPC(vpcID string) []CloudPodsSecurityGroup
+}
+
+// LoadBalancerLister reads load balancers out of an InformerFactory's
+// local cache.
+type LoadBalancerLister interface {
+	List() []CloudPodsLoadBalancer
+	Get(id string) (CloudPodsLoadBalancer, bool)
+	ByVPC(vpcID string) []CloudPodsLoadBalancer
+}
+
+type vmLister struct{ s *store }
+
+func (l vmLister) List() []CloudPodsVM {
+	all := l.s.list()
+	out := make([]CloudPodsVM, 0, len(all))
+	for _, obj := range all {
+		out = append(out, obj.(CloudPodsVM))
+	}
+	return out
+}
+func (l vmLister) Get(id string) (CloudPodsVM, bool) {
+	obj, ok := l.s.get(id)
+	if !ok {
+		return CloudPodsVM{}, false
+	}
+	return obj.(CloudPodsVM), true
+}
+func (l vmLister) ByVPC(vpcID string) []CloudPodsVM {
+	objs := l.s.byIndex(l.s.byVPC, vpcID)
+	out := make([]CloudPodsVM, 0, len(objs))
+	for _, obj := range objs {
+		out = append(out, obj.(CloudPodsVM))
+	}
+	return out
+}
+
+type vpcLister struct{ s *store }
+
+func (l vpcLister) List() []CloudPodsVPC {
+	all := l.s.list()
+	out := make([]CloudPodsVPC, 0, len(all))
+	for _, obj := range all {
+		out = append(out, obj.(CloudPodsVPC))
+	}
+	return out
+}
+func (l vpcLister) Get(id string) (CloudPodsVPC, bool) {
+	obj, ok := l.s.get(id)
+	if !ok {
+		return CloudPodsVPC{}, false
+	}
+	return obj.(CloudPodsVPC), true
+}
+
+type subnetLister struct{ s *store }
+
+func (l subnetLister) List() []CloudPodsSubnet {
+	all := l.s.list()
+	out := make([]CloudPodsSubnet, 0, len(all))
+	for _, obj := range all {
+		out = append(out, obj.(CloudPodsSubnet))
+	}
+	return out
+}
+func (l subnetLister) Get(id string) (CloudPodsSubnet, bool) {
+	obj, ok := l.s.get(id)
+	if !ok {
+		return CloudPodsSubnet{}, false
+	}
+	return obj.(CloudPodsSubnet), true
+}
+func (l subnetLister) ByVPC(vpcID string) []CloudPodsSubnet {
+	objs := l.s.byIndex(l.s.byVPC, vpcID)
+	out := make([]CloudPodsSubnet, 0, len(objs))
+	for _, obj := range objs {
+		out = append(out, obj.(CloudPodsSubnet))
+	}
+	return out
+}
+
+type securityGroupLister struct{ s *store }
+
+func (l securityGroupLister) List() []CloudPodsSecurityGroup {
+	all := l.s.list()
+	out := make([]CloudPodsSecurityGroup, 0, len(all))
+	for _, obj := range all {
+		out = append(out, obj.(CloudPodsSecurityGroup))
+	}
+	return out
+}
+func (l securityGroupLister) Get(id string) (CloudPodsSecurityGroup, bool) {
+	obj, ok := l.s.get(id)
+	if !ok {
+		return CloudPodsSecurityGroup{}, false
+	}
+	return obj.(CloudPodsSecurityGroup), true
+}
+func (l securityGroupLister) ByVPC(vpcID string) []CloudPodsSecurityGroup {
+	objs := l.s.byIndex(l.s.byVPC, vpcID)
+	out := make([]CloudPodsSecurityGroup, 0, len(objs))
+	for _, obj := range objs {
+		out = append(out, obj.(CloudPodsSecurityGroup))
+	}
+	return out
+}
+
+type loadBalancerLister struct{ s *store }
+
+func (l loadBalancerLister) List() []CloudPodsLoadBalancer {
+	all := l.s.list()
+	out := make([]CloudPodsLoadBalancer, 0, len(all))
+	for _, obj := range all {
+		out = append(out, obj.(CloudPodsLoadBalancer))
+	}
+	return out
+}
+func (l loadBalancerLister) Get(id string) (CloudPodsLoadBalancer, bool) {
+	obj, ok := l.s.get(id)
+	if !ok {
+		return CloudPodsLoadBalancer{}, false
+	}
+	return obj.(CloudPodsLoadBalancer), true
+}
+func (l loadBalancerLister) ByVPC(vpcID string) []CloudPodsLoadBalancer {
+	objs := l.s.byIndex(l.s.byVPC, vpcID)
+	out := make([]CloudPodsLoadBalancer, 0, len(objs))
+	for _, obj := range objs {
+		out = append(out, obj.(CloudPodsLoadBalancer))
+	}
+	return out
+}
+
+// reflectorSpec is one resource type's list-then-poll loop: List fetches
+// the current set from the API, extract describes one object for
+// indexing/diffing, and kind/store is where the result lands.
+type reflectorSpec struct {
+	kind    string
+	store   *store
+	list    func(ctx context.Context) ([]interface{}, error)
+	extract func(interface{}) resourceMeta
+}
+
+// InformerFactory runs a long-lived list-then-poll loop per CloudPods
+// resource type, caching decoded objects in an indexed store and
+// emitting Added/Modified/Deleted deltas on Events() as they change.
+// Modeled on client-go's SharedInformerFactory: construct one, call
+// Start with a stop channel, then WaitForCacheSync before trusting the
+// Listers to have seen the initial state.
+type InformerFactory struct {
+	client *Client
+	resync time.Duration
+
+	reflectors []reflectorSpec
+
+	events  chan Event
+	started bool
+	synced  map[string]chan struct{}
+}
+
+// NewInformerFactory builds an InformerFactory over client, resyncing
+// every resync (DefaultResyncInterval if zero). Start must be called
+// before any Lister returns real data.
+func NewInformerFactory(client *Client, resync time.Duration) *InformerFactory {
+	if resync <= 0 {
+		resync = DefaultResyncInterval
+	}
+
+	f := &InformerFactory{
+		client: client,
+		resync: resync,
+		events: make(chan Event, eventBacklog),
+		synced: make(map[string]chan struct{}),
+	}
+
+	f.register("vm", func(ctx context.Context) ([]interface{}, error) {
+		vms, err := client.GetVMs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, len(vms))
+		for i, vm := range vms {
+			out[i] = vm
+		}
+		return out, nil
+	}, func(obj interface{}) resourceMeta {
+		vm := obj.(CloudPodsVM)
+		return resourceMeta{id: vm.ID, vpc: vm.VPC, region: vm.Region, zone: vm.Zone, tags: vm.Tags, updatedAt: vm.UpdatedAt}
+	})
+	f.register("vpc", func(ctx context.Context) ([]interface{}, error) {
+		vpcs, err := client.GetVPCs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, len(vpcs))
+		for i, vpc := range vpcs {
+			out[i] = vpc
+		}
+		return out, nil
+	}, func(obj interface{}) resourceMeta {
+		vpc := obj.(CloudPodsVPC)
+		return resourceMeta{id: vpc.ID, region: vpc.Region, zone: vpc.Zone, tags: vpc.Tags, updatedAt: vpc.UpdatedAt}
+	})
+	f.register("subnet", func(ctx context.Context) ([]interface{}, error) {
+		subnets, err := client.GetSubnets(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, len(subnets))
+		for i, subnet := range subnets {
+			out[i] = subnet
+		}
+		return out, nil
+	}, func(obj interface{}) resourceMeta {
+		subnet := obj.(CloudPodsSubnet)
+		return resourceMeta{id: subnet.ID, vpc: subnet.VPC, region: subnet.Region, zone: subnet.Zone, tags: subnet.Tags, updatedAt: subnet.UpdatedAt}
+	})
+	f.register("securitygroup", func(ctx context.Context) ([]interface{}, error) {
+		sgs, err := client.GetSecurityGroups(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, len(sgs))
+		for i, sg := range sgs {
+			out[i] = sg
+		}
+		return out, nil
+	}, func(obj interface{}) resourceMeta {
+		sg := obj.(CloudPodsSecurityGroup)
+		return resourceMeta{id: sg.ID, vpc: sg.VPC, region: sg.Region, zone: sg.Zone, tags: sg.Tags, updatedAt: sg.UpdatedAt}
+	})
+	f.register("loadbalancer", func(ctx context.Context) ([]interface{}, error) {
+		lbs, err := client.GetLoadBalancers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, len(lbs))
+		for i, lb := range lbs {
+			out[i] = lb
+		}
+		return out, nil
+	}, func(obj interface{}) resourceMeta {
+		lb := obj.(CloudPodsLoadBalancer)
+		return resourceMeta{id: lb.ID, vpc: lb.VPC, region: lb.Region, zone: lb.Zone, tags: lb.Tags, updatedAt: lb.UpdatedAt}
+	})
+
+	return f
+}
+
+func (f *InformerFactory) register(kind string, list func(context.Context) ([]interface{}, error), extract func(interface{}) resourceMeta) {
+	f.reflectors = append(f.reflectors, reflectorSpec{kind: kind, store: newStore(), list: list, extract: extract})
+	f.synced[kind] = make(chan struct{})
+}
+
+func (f *InformerFactory) storeFor(kind string) *store {
+	for _, r := range f.reflectors {
+		if r.kind == kind {
+			return r.store
+		}
+	}
+	return nil
+}
+
+// VMs returns the factory's VMLister.
+func (f *InformerFactory) VMs() VMLister { return vmLister{s: f.storeFor("vm")} }
+
+// VPCs returns the factory's VPCLister.
+func (f *InformerFactory) VPCs() VPCLister { return vpcLister{s: f.storeFor("vpc")} }
+
+// Subnets returns the factory's SubnetLister.
+func (f *InformerFactory) Subnets() SubnetLister { return subnetLister{s: f.storeFor("subnet")} }
+
+// SecurityGroups returns the factory's SecurityGroupLister.
+func (f *InformerFactory) SecurityGroups() SecurityGroupLister {
+	return securityGroupLister{s: f.storeFor("securitygroup")}
+}
+
+// LoadBalancers returns the factory's LoadBalancerLister.
+func (f *InformerFactory) LoadBalancers() LoadBalancerLister {
+	return loadBalancerLister{s: f.storeFor("loadbalancer")}
+}
+
+// Events returns the channel every reflector's Added/Modified/Deleted
+// deltas are published to. The channel is shared and unbuffered beyond
+// eventBacklog entries; a slow consumer risks a reflector blocking on
+// publish, same tradeoff EventBus.Subscribe already makes.
+func (f *InformerFactory) Events() <-chan Event {
+	return f.events
+}
+
+// Start spins up one goroutine per registered resource type, each
+// running an immediate LIST followed by a list-then-poll loop every
+// f.resync, until stopCh is closed. Calling Start twice is a no-op.
+func (f *InformerFactory) Start(stopCh <-chan struct{}) {
+	if f.started {
+		return
+	}
+	f.started = true
+
+	for _, r := range f.reflectors {
+		go f.runReflector(r, stopCh)
+	}
+}
+
+func (f *InformerFactory) runReflector(r reflectorSpec, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(f.resync)
+	defer ticker.Stop()
+
+	f.resyncOnce(r)
+	close(f.synced[r.kind])
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			f.resyncOnce(r)
+		}
+	}
+}
+
+func (f *InformerFactory) resyncOnce(r reflectorSpec) {
+	ctx, cancel := context.WithTimeout(context.Background(), f.resync)
+	defer cancel()
+
+	objects, err := r.list(ctx)
+	if err != nil {
+		// A failed LIST leaves the store as of its last successful
+		// sync; the next tick tries again, the same way a client-go
+		// reflector backs off and retries rather than clearing its
+		// store on a transient API error.
+		return
+	}
+
+	for _, delta := range r.store.sync(r.kind, objects, r.extract) {
+		select {
+		case f.events <- delta:
+		default:
+		}
+	}
+}
+
+// WaitForCacheSync blocks until every registered resource type has
+// completed its initial LIST, or ctx is canceled first, mirroring
+// client-go's SharedInformerFactory.WaitForCacheSync. It returns false
+// if ctx was canceled before every type synced.
+func (f *InformerFactory) WaitForCacheSync(ctx context.Context) bool {
+	for _, r := range f.reflectors {
+		select {
+		case <-f.synced[r.kind]:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
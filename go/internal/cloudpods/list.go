@@ -0,0 +1,147 @@
+package cloudpods
+
+import "context"
+
+// ListVPCs returns every VPC matching opts' LabelSelector/FieldSelector,
+// paginated per opts.Limit/opts.Continue. Pagination is applied after
+// filtering, the same order a Kubernetes list request uses, so a
+// selector narrows the set before the page boundary is drawn.
+func (c *Client) ListVPCs(ctx context.Context, opts ListOptions) ([]CloudPodsVPC, string, error) {
+	all, err := c.GetVPCs(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	matched := make([]CloudPodsVPC, 0, len(all))
+	for _, vpc := range all {
+		ok, err := opts.matches(vpc.Labels(), vpc)
+		if err != nil {
+			return nil, "", err
+		}
+		if ok {
+			matched = append(matched, vpc)
+		}
+	}
+
+	offset, end, next, err := page(len(matched), opts)
+	if err != nil {
+		return nil, "", err
+	}
+	return matched[offset:end], next, nil
+}
+
+// ListSubnets returns every subnet of vpcID matching opts, paginated the
+// same way ListVPCs is.
+func (c *Client) ListSubnets(ctx context.Context, vpcID string, opts ListOptions) ([]CloudPodsSubnet, string, error) {
+	all, err := c.GetSubnets(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	matched := make([]CloudPodsSubnet, 0, len(all))
+	for _, subnet := range all {
+		if subnet.VPC != vpcID {
+			continue
+		}
+		ok, err := opts.matches(subnet.Labels(), subnet)
+		if err != nil {
+			return nil, "", err
+		}
+		if ok {
+			matched = append(matched, subnet)
+		}
+	}
+
+	offset, end, next, err := page(len(matched), opts)
+	if err != nil {
+		return nil, "", err
+	}
+	return matched[offset:end], next, nil
+}
+
+// ListNATGateways returns every NAT gateway of vpcID matching opts,
+// paginated the same way ListVPCs is.
+func (c *Client) ListNATGateways(ctx context.Context, vpcID string, opts ListOptions) ([]CloudPodsNATGateway, string, error) {
+	all, err := c.GetNATGateways(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	matched := make([]CloudPodsNATGateway, 0, len(all))
+	for _, nat := range all {
+		if nat.VPC != vpcID {
+			continue
+		}
+		ok, err := opts.matches(nat.Labels(), nat)
+		if err != nil {
+			return nil, "", err
+		}
+		if ok {
+			matched = append(matched, nat)
+		}
+	}
+
+	offset, end, next, err := page(len(matched), opts)
+	if err != nil {
+		return nil, "", err
+	}
+	return matched[offset:end], next, nil
+}
+
+// ListLoadBalancers returns every load balancer of vpcID matching opts,
+// paginated the same way ListVPCs is.
+func (c *Client) ListLoadBalancers(ctx context.Context, vpcID string, opts ListOptions) ([]CloudPodsLoadBalancer, string, error) {
+	all, err := c.GetLoadBalancers(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	matched := make([]CloudPodsLoadBalancer, 0, len(all))
+	for _, lb := range all {
+		if lb.VPC != vpcID {
+			continue
+		}
+		ok, err := opts.matches(lb.Labels(), lb)
+		if err != nil {
+			return nil, "", err
+		}
+		if ok {
+			matched = append(matched, lb)
+		}
+	}
+
+	offset, end, next, err := page(len(matched), opts)
+	if err != nil {
+		return nil, "", err
+	}
+	return matched[offset:end], next, nil
+}
+
+// ListServiceMeshRoutes returns every service-mesh route of vpcID
+// matching opts, paginated the same way ListVPCs is.
+func (c *Client) ListServiceMeshRoutes(ctx context.Context, vpcID string, opts ListOptions) ([]CloudPodsServiceMeshRoute, string, error) {
+	all, err := c.GetServiceMeshRoutes(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	matched := make([]CloudPodsServiceMeshRoute, 0, len(all))
+	for _, route := range all {
+		if route.VPC != vpcID {
+			continue
+		}
+		ok, err := opts.matches(route.Labels(), route)
+		if err != nil {
+			return nil, "", err
+		}
+		if ok {
+			matched = append(matched, route)
+		}
+	}
+
+	offset, end, next, err := page(len(matched), opts)
+	if err != nil {
+		return nil, "", err
+	}
+	return matched[offset:end], next, nil
+}
@@ -0,0 +1,224 @@
+package cloudpods
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// OperationStatus is an Operation's current phase, CloudPods' name for
+// what taskmanager.go calls a TaskState: the same pending/running/
+// terminal lifecycle, just tracked server-side on CloudPods' own API
+// instead of this service's local worker pool.
+type OperationStatus string
+
+const (
+	OperationPending   OperationStatus = "pending"
+	OperationRunning   OperationStatus = "running"
+	OperationSucceeded OperationStatus = "succeeded"
+	OperationFailed    OperationStatus = "failed"
+	OperationCanceled  OperationStatus = "canceled"
+)
+
+func (s OperationStatus) terminal() bool {
+	return s == OperationSucceeded || s == OperationFailed || s == OperationCanceled
+}
+
+// Operation is the async handle CloudPods returns for a mutating call
+// instead of the finished resource, modeled after LXD's operations API:
+// the caller polls (or Waits on) it until Status reaches a terminal
+// value, at which point Resource holds the finished object (if any) and
+// Err holds the failure reason (if Status is failed).
+type Operation struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Status    OperationStatus `json:"status"`
+	Progress  int             `json:"progress"`
+	Resource  json.RawMessage `json:"resource,omitempty"`
+	Err       string          `json:"error,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// OperationEvent is one state change Watch's stream pushed for an
+// Operation, CloudPods' push-based complement to polling Get/Wait.
+type OperationEvent struct {
+	Operation Operation `json:"operation"`
+}
+
+// OperationsService is EnhancedClient's handle on CloudPods' async
+// operations endpoint, reachable through EnhancedClient.Operations.
+type OperationsService struct {
+	client *EnhancedClient
+}
+
+// Get returns id's current Operation snapshot.
+func (s *OperationsService) Get(ctx context.Context, id string) (*Operation, error) {
+	var op Operation
+	if err := s.client.doRequest(ctx, http.MethodGet, "/operations/"+id, nil, nil, &op); err != nil {
+		return nil, fmt.Errorf("failed to get operation: %w", err)
+	}
+	return &op, nil
+}
+
+// List returns every Operation matching filters (e.g. "status",
+// "type", "resource").
+func (s *OperationsService) List(ctx context.Context, filters map[string]string) ([]Operation, error) {
+	opts := ListQuery{Filters: filters}
+	var resp struct {
+		Operations []Operation `json:"operations"`
+	}
+	if err := s.client.doRequest(ctx, http.MethodGet, "/operations", opts.query(""), nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list operations: %w", err)
+	}
+	return resp.Operations, nil
+}
+
+// Cancel requests that id stop running. A terminal Operation (one that
+// already succeeded, failed, or was canceled) returns no error; CloudPods
+// treats cancellation of a finished operation as a no-op.
+func (s *OperationsService) Cancel(ctx context.Context, id string) error {
+	if err := s.client.doRequest(ctx, http.MethodPost, "/operations/"+id+"/cancel", nil, nil, nil); err != nil {
+		return fmt.Errorf("failed to cancel operation: %w", err)
+	}
+	return nil
+}
+
+// operationPollInterval is how often Wait/WaitWithProgress re-fetch an
+// Operation while it remains non-terminal.
+const operationPollInterval = 500 * time.Millisecond
+
+// Wait blocks until id reaches a terminal status, returning its final
+// snapshot. A failed Operation is returned alongside an error wrapping
+// its Err field, so a caller can still inspect Resource/Progress on
+// failure without a type assertion.
+func (s *OperationsService) Wait(ctx context.Context, id string) (*Operation, error) {
+	return s.WaitWithProgress(ctx, id, nil)
+}
+
+// WaitWithProgress is Wait, additionally invoking onProgress with every
+// intermediate snapshot fetched while id remains non-terminal (onProgress
+// may be nil, making it equivalent to Wait).
+func (s *OperationsService) WaitWithProgress(ctx context.Context, id string, onProgress func(Operation)) (*Operation, error) {
+	ticker := time.NewTicker(operationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		op, err := s.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if op.Status.terminal() {
+			if op.Status == OperationFailed {
+				return op, fmt.Errorf("operation %s failed: %s", op.ID, op.Err)
+			}
+			return op, nil
+		}
+
+		if onProgress != nil {
+			onProgress(*op)
+		}
+
+		select {
+		case <-ctx.Done():
+			return op, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Watch opens a Server-Sent Events connection to /operations/watch and
+// returns a channel of OperationEvents as CloudPods pushes them, the
+// push-based complement to polling Get/Wait (see
+// EnhancedClient.WatchTopology in the aviatrix package for the same
+// pattern). A dropped connection is retried internally with jittered
+// exponential backoff; callers only see a reconnect as a pause in
+// events. The returned channel is closed once ctx is canceled.
+func (s *OperationsService) Watch(ctx context.Context) (<-chan OperationEvent, error) {
+	events := make(chan OperationEvent, 64)
+
+	go func() {
+		defer close(events)
+
+		bo := backoff.NewExponentialBackOff()
+		bo.MaxElapsedTime = 0 // retry until ctx is canceled, never give up on its own
+
+		for {
+			err := s.watchOnce(ctx, events)
+			if ctx.Err() != nil {
+				return
+			}
+			if err == nil {
+				bo.Reset()
+				continue
+			}
+
+			wait := bo.NextBackOff()
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// watchOnce opens one SSE connection to /operations/watch and forwards
+// its events until the stream ends or errors. A server-closed stream is
+// reported as a nil error so Watch reconnects immediately instead of
+// backing off after a clean close.
+func (s *OperationsService) watchOnce(ctx context.Context, events chan<- OperationEvent) error {
+	if err := s.client.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.client.baseURL+"/operations/watch", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.client.authToken)
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := s.client.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to watch operations: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to watch operations with status: %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if payload == "" {
+			continue
+		}
+
+		var event OperationEvent
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			continue
+		}
+
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,401 @@
+// Package patch implements the three partial-update content types a
+// Kubernetes API server accepts on PATCH, applied to arbitrary JSON
+// documents rather than CloudPods' typed structs, so
+// CloudPodsHandler's PATCH endpoints can dispatch on Content-Type the
+// same way kubectl does.
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ContentType names one of the three PATCH content types.
+type ContentType string
+
+const (
+	JSONPatch           ContentType = "application/json-patch+json"
+	MergePatch          ContentType = "application/merge-patch+json"
+	StrategicMergePatch ContentType = "application/strategic-merge-patch+json"
+)
+
+// MergeKeys maps a list field name to the field within its elements that
+// identifies "the same" element across the base document and the patch,
+// so StrategicMergePatch can merge list entries instead of replacing the
+// whole list the way MergePatch does. A mapping applies wherever a field
+// with that name appears, at any depth.
+type MergeKeys map[string]string
+
+// Apply dispatches to the patch implementation named by contentType.
+// mergeKeys is only consulted for StrategicMergePatch; pass nil for the
+// other two content types.
+func Apply(contentType string, doc, patchDoc []byte, mergeKeys MergeKeys) ([]byte, error) {
+	switch ContentType(contentType) {
+	case JSONPatch:
+		return ApplyJSONPatch(doc, patchDoc)
+	case MergePatch:
+		return ApplyMergePatch(doc, patchDoc)
+	case StrategicMergePatch:
+		return ApplyStrategicMergePatch(doc, patchDoc, mergeKeys)
+	default:
+		return nil, fmt.Errorf("patch: unsupported Content-Type %q", contentType)
+	}
+}
+
+// ---- RFC 7396 JSON Merge Patch ----
+
+// ApplyMergePatch applies an RFC 7396 JSON Merge Patch: patchDoc is
+// recursively merged into doc, a null value deletes the corresponding
+// key, and any other value — including arrays — replaces it wholesale.
+func ApplyMergePatch(doc, patchDoc []byte) ([]byte, error) {
+	var base, patchVal interface{}
+	if err := json.Unmarshal(doc, &base); err != nil {
+		return nil, fmt.Errorf("patch: decoding document: %w", err)
+	}
+	if err := json.Unmarshal(patchDoc, &patchVal); err != nil {
+		return nil, fmt.Errorf("patch: decoding merge patch: %w", err)
+	}
+	return json.Marshal(mergePatch(base, patchVal))
+}
+
+func mergePatch(base, patchVal interface{}) interface{} {
+	patchMap, ok := patchVal.(map[string]interface{})
+	if !ok {
+		// Non-object patches, including null, replace the base outright.
+		return patchVal
+	}
+	baseMap, _ := base.(map[string]interface{})
+
+	merged := make(map[string]interface{}, len(baseMap))
+	for k, v := range baseMap {
+		merged[k] = v
+	}
+	for k, v := range patchMap {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = mergePatch(merged[k], v)
+	}
+	return merged
+}
+
+// ---- Strategic merge patch (list-key-aware merge patch) ----
+
+// ApplyStrategicMergePatch behaves like ApplyMergePatch, except a list
+// field named in mergeKeys is merged element-by-element, matched on the
+// configured key field, instead of being replaced wholesale: an element
+// present in both base and patch is merged, one only in the patch is
+// appended, and one only in the base is kept untouched. A list not named
+// in mergeKeys falls back to merge-patch's replace-the-whole-list
+// behavior.
+func ApplyStrategicMergePatch(doc, patchDoc []byte, mergeKeys MergeKeys) ([]byte, error) {
+	var base, patchVal interface{}
+	if err := json.Unmarshal(doc, &base); err != nil {
+		return nil, fmt.Errorf("patch: decoding document: %w", err)
+	}
+	if err := json.Unmarshal(patchDoc, &patchVal); err != nil {
+		return nil, fmt.Errorf("patch: decoding strategic merge patch: %w", err)
+	}
+	return json.Marshal(strategicMerge(base, patchVal, mergeKeys))
+}
+
+func strategicMerge(base, patchVal interface{}, mergeKeys MergeKeys) interface{} {
+	patchMap, ok := patchVal.(map[string]interface{})
+	if !ok {
+		return patchVal
+	}
+	baseMap, _ := base.(map[string]interface{})
+
+	merged := make(map[string]interface{}, len(baseMap))
+	for k, v := range baseMap {
+		merged[k] = v
+	}
+	for k, v := range patchMap {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		if mergeKey, ok := mergeKeys[k]; ok {
+			if patchList, ok := v.([]interface{}); ok {
+				baseList, _ := merged[k].([]interface{})
+				merged[k] = mergeListByKey(baseList, patchList, mergeKey, mergeKeys)
+				continue
+			}
+		}
+		merged[k] = strategicMerge(merged[k], v, mergeKeys)
+	}
+	return merged
+}
+
+// mergeListByKey merges patchList into baseList by matching elements on
+// their mergeKey field: a match is recursively merged in place, an
+// element only in patchList is appended, and one only in baseList is
+// kept in its original position — the same semantics Kubernetes uses to
+// merge, say, containers by name or ports by containerPort.
+func mergeListByKey(baseList, patchList []interface{}, mergeKey string, mergeKeys MergeKeys) []interface{} {
+	merged := append([]interface{}{}, baseList...)
+
+	indexByKey := make(map[interface{}]int, len(merged))
+	for i, item := range merged {
+		if m, ok := item.(map[string]interface{}); ok {
+			indexByKey[m[mergeKey]] = i
+		}
+	}
+
+	for _, patchItem := range patchList {
+		patchMap, ok := patchItem.(map[string]interface{})
+		if !ok {
+			merged = append(merged, patchItem)
+			continue
+		}
+		keyVal := patchMap[mergeKey]
+		if i, ok := indexByKey[keyVal]; ok {
+			merged[i] = strategicMerge(merged[i], patchMap, mergeKeys)
+			continue
+		}
+		merged = append(merged, patchMap)
+		indexByKey[keyVal] = len(merged) - 1
+	}
+
+	return merged
+}
+
+// ---- RFC 6902 JSON Patch ----
+
+// jsonPatchOp is a single RFC 6902 operation.
+type jsonPatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ApplyJSONPatch applies an RFC 6902 JSON Patch: an ordered list of
+// add/remove/replace/move/copy/test operations addressed by RFC 6901
+// JSON Pointer paths into doc. Operations apply in order and a failing
+// "test" aborts the whole patch without partially applying it, matching
+// the spec's all-or-nothing semantics.
+func ApplyJSONPatch(doc, patchDoc []byte) ([]byte, error) {
+	var target interface{}
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("patch: decoding document: %w", err)
+	}
+	var ops []jsonPatchOp
+	if err := json.Unmarshal(patchDoc, &ops); err != nil {
+		return nil, fmt.Errorf("patch: decoding json patch: %w", err)
+	}
+
+	for _, op := range ops {
+		var err error
+		switch op.Op {
+		case "add":
+			target, err = setPointer(target, op.Path, op.Value, true)
+		case "replace":
+			target, err = setPointer(target, op.Path, op.Value, false)
+		case "remove":
+			target, err = removePointer(target, op.Path)
+		case "test":
+			err = testPointer(target, op.Path, op.Value)
+		case "move":
+			var val interface{}
+			if val, err = getPointer(target, op.From); err == nil {
+				if target, err = removePointer(target, op.From); err == nil {
+					target, err = setPointer(target, op.Path, val, true)
+				}
+			}
+		case "copy":
+			var val interface{}
+			if val, err = getPointer(target, op.From); err == nil {
+				target, err = setPointer(target, op.Path, val, true)
+			}
+		default:
+			err = fmt.Errorf("unsupported op %q", op.Op)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("patch: op %q %s: %w", op.Op, op.Path, err)
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+// splitPointer tokenizes an RFC 6901 JSON Pointer, unescaping ~1 ("/")
+// and ~0 ("~") in each token.
+func splitPointer(path string) []string {
+	if path == "" || path == "/" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, t := range tokens {
+		t = strings.ReplaceAll(t, "~1", "/")
+		t = strings.ReplaceAll(t, "~0", "~")
+		tokens[i] = t
+	}
+	return tokens
+}
+
+func getPointer(doc interface{}, path string) (interface{}, error) {
+	cur := doc
+	for _, tok := range splitPointer(path) {
+		switch v := cur.(type) {
+		case map[string]interface{}:
+			val, ok := v[tok]
+			if !ok {
+				return nil, fmt.Errorf("no such member %q", tok)
+			}
+			cur = val
+		case []interface{}:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("invalid array index %q", tok)
+			}
+			cur = v[i]
+		default:
+			return nil, fmt.Errorf("cannot descend into %T at %q", cur, tok)
+		}
+	}
+	return cur, nil
+}
+
+// setPointer sets the value at path, creating it if create is true (as
+// "add" and "move"/"copy" destinations do) or requiring it to already
+// exist if create is false (as "replace" does).
+func setPointer(doc interface{}, path string, value interface{}, create bool) (interface{}, error) {
+	tokens := splitPointer(path)
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return setPointerRec(doc, tokens, value, create)
+}
+
+func setPointerRec(doc interface{}, tokens []string, value interface{}, create bool) (interface{}, error) {
+	tok := tokens[0]
+	if len(tokens) == 1 {
+		switch v := doc.(type) {
+		case map[string]interface{}:
+			if _, exists := v[tok]; !exists && !create {
+				return nil, fmt.Errorf("no such member %q", tok)
+			}
+			v[tok] = value
+			return v, nil
+		case []interface{}:
+			if tok == "-" {
+				return append(v, value), nil
+			}
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i > len(v) {
+				return nil, fmt.Errorf("invalid array index %q", tok)
+			}
+			if !create {
+				v[i] = value
+				return v, nil
+			}
+			out := make([]interface{}, 0, len(v)+1)
+			out = append(out, v[:i]...)
+			out = append(out, value)
+			return append(out, v[i:]...), nil
+		default:
+			return nil, fmt.Errorf("cannot set member %q on %T", tok, doc)
+		}
+	}
+
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		child, ok := v[tok]
+		if !ok {
+			return nil, fmt.Errorf("no such member %q", tok)
+		}
+		updated, err := setPointerRec(child, tokens[1:], value, create)
+		if err != nil {
+			return nil, err
+		}
+		v[tok] = updated
+		return v, nil
+	case []interface{}:
+		i, err := strconv.Atoi(tok)
+		if err != nil || i < 0 || i >= len(v) {
+			return nil, fmt.Errorf("invalid array index %q", tok)
+		}
+		updated, err := setPointerRec(v[i], tokens[1:], value, create)
+		if err != nil {
+			return nil, err
+		}
+		v[i] = updated
+		return v, nil
+	default:
+		return nil, fmt.Errorf("cannot descend into %T at %q", doc, tok)
+	}
+}
+
+func removePointer(doc interface{}, path string) (interface{}, error) {
+	tokens := splitPointer(path)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("cannot remove document root")
+	}
+	return removePointerRec(doc, tokens)
+}
+
+func removePointerRec(doc interface{}, tokens []string) (interface{}, error) {
+	tok := tokens[0]
+	if len(tokens) == 1 {
+		switch v := doc.(type) {
+		case map[string]interface{}:
+			if _, ok := v[tok]; !ok {
+				return nil, fmt.Errorf("no such member %q", tok)
+			}
+			delete(v, tok)
+			return v, nil
+		case []interface{}:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("invalid array index %q", tok)
+			}
+			return append(v[:i], v[i+1:]...), nil
+		default:
+			return nil, fmt.Errorf("cannot remove member %q from %T", tok, doc)
+		}
+	}
+
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		child, ok := v[tok]
+		if !ok {
+			return nil, fmt.Errorf("no such member %q", tok)
+		}
+		updated, err := removePointerRec(child, tokens[1:])
+		if err != nil {
+			return nil, err
+		}
+		v[tok] = updated
+		return v, nil
+	case []interface{}:
+		i, err := strconv.Atoi(tok)
+		if err != nil || i < 0 || i >= len(v) {
+			return nil, fmt.Errorf("invalid array index %q", tok)
+		}
+		updated, err := removePointerRec(v[i], tokens[1:])
+		if err != nil {
+			return nil, err
+		}
+		v[i] = updated
+		return v, nil
+	default:
+		return nil, fmt.Errorf("cannot descend into %T at %q", doc, tok)
+	}
+}
+
+func testPointer(doc interface{}, path string, expected interface{}) error {
+	actual, err := getPointer(doc, path)
+	if err != nil {
+		return err
+	}
+	actualJSON, _ := json.Marshal(actual)
+	expectedJSON, _ := json.Marshal(expected)
+	if string(actualJSON) != string(expectedJSON) {
+		return fmt.Errorf("test failed: %s != %s", actualJSON, expectedJSON)
+	}
+	return nil
+}
@@ -0,0 +1,483 @@
+package cloudpods
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// ResourceEventType is the kind of change a ResourceEvent reports.
+type ResourceEventType string
+
+const (
+	ResourceAdded   ResourceEventType = "added"
+	ResourceUpdated ResourceEventType = "updated"
+	ResourceDeleted ResourceEventType = "deleted"
+)
+
+// ResourceKind names which Watch-able resource collection a ResourceEvent
+// belongs to.
+type ResourceKind string
+
+const (
+	KindInstance     ResourceKind = "instance"
+	KindNetwork      ResourceKind = "network"
+	KindLoadBalancer ResourceKind = "load_balancer"
+	KindStorage      ResourceKind = "storage"
+)
+
+// ResourceEvent is one add/update/delete EnhancedClient.Watch pushed.
+// Before/After are left as raw JSON since their shape depends on Kind;
+// decode them as the matching type (Instance, Network, LoadBalancer, or
+// Storage) once Kind is known - ResourceCache.apply does exactly this.
+// ID is the stream's own event id (from an SSE "id:" line, or the
+// long-poll fallback's equivalent), used to resume after a reconnect via
+// Last-Event-ID; it is unrelated to ResourceID, the changed resource's
+// own id.
+type ResourceEvent struct {
+	ID         string            `json:"id"`
+	Type       ResourceEventType `json:"type"`
+	Kind       ResourceKind      `json:"kind"`
+	ResourceID string            `json:"resource_id"`
+	Before     json.RawMessage   `json:"before,omitempty"`
+	After      json.RawMessage   `json:"after,omitempty"`
+	Timestamp  time.Time         `json:"timestamp"`
+}
+
+// WatchOptions filters a Watch call. An empty WatchOptions watches every
+// kind from the start of the stream.
+type WatchOptions struct {
+	// Kinds restricts the stream to the given resource kinds; empty
+	// means every kind.
+	Kinds []ResourceKind
+
+	// LastEventID resumes the stream after the named event instead of
+	// starting fresh, equivalent to setting a Last-Event-ID header by
+	// hand. Watch also tracks the latest event ID internally across its
+	// own automatic reconnects, so this is normally only set when
+	// resuming a stream that was stopped and restarted by the caller
+	// (e.g. after a process restart).
+	LastEventID string
+}
+
+func (o WatchOptions) query() url.Values {
+	q := url.Values{}
+	for _, kind := range o.Kinds {
+		q.Add("kind", string(kind))
+	}
+	return q
+}
+
+// errSSEUnavailable signals that /resources/watch doesn't support
+// Server-Sent Events on this deployment, so Watch should fall back to
+// long-polling for the rest of its run instead of retrying SSE forever.
+var errSSEUnavailable = errors.New("cloudpods: SSE unavailable for resource watch")
+
+// longPollIdleDelay is how long watchResourcesLongPollOnce waits before
+// issuing another poll after one that returned no events, so a
+// deployment whose /resources/poll doesn't actually hold the request
+// open doesn't turn into a tight loop.
+const longPollIdleDelay = 1 * time.Second
+
+// Watch streams add/update/delete events for Instances, Networks,
+// LoadBalancers, and Storages matching opts. It prefers a Server-Sent
+// Events connection to /resources/watch, falling back for the rest of
+// this call to long-polling /resources/poll if the server doesn't speak
+// SSE (a 404, or a 200 with a non-event-stream content type). A dropped
+// connection (of either kind) is retried internally with jittered
+// exponential backoff and resumes from the last event ID seen, so a
+// temporary disconnect doesn't lose events; callers only see a reconnect
+// as a pause in the channel. The returned channel is closed once ctx is
+// canceled.
+func (c *EnhancedClient) Watch(ctx context.Context, opts WatchOptions) (<-chan ResourceEvent, error) {
+	events := make(chan ResourceEvent, 64)
+
+	go func() {
+		defer close(events)
+
+		lastID := opts.LastEventID
+		useLongPoll := false
+		bo := backoff.NewExponentialBackOff()
+		bo.MaxElapsedTime = 0 // retry until ctx is canceled, never give up on its own
+
+		for {
+			var (
+				nextID string
+				err    error
+			)
+			if useLongPoll {
+				nextID, err = c.watchResourcesLongPollOnce(ctx, opts, lastID, events)
+			} else {
+				nextID, err = c.watchResourcesSSEOnce(ctx, opts, lastID, events)
+				if errors.Is(err, errSSEUnavailable) {
+					useLongPoll = true
+					err = nil
+				}
+			}
+			if nextID != "" {
+				lastID = nextID
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if err == nil {
+				bo.Reset()
+				continue
+			}
+
+			wait := bo.NextBackOff()
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// WatchInstances is Watch scoped to KindInstance.
+func (c *EnhancedClient) WatchInstances(ctx context.Context) (<-chan ResourceEvent, error) {
+	return c.Watch(ctx, WatchOptions{Kinds: []ResourceKind{KindInstance}})
+}
+
+// WatchLoadBalancers is Watch scoped to KindLoadBalancer.
+func (c *EnhancedClient) WatchLoadBalancers(ctx context.Context) (<-chan ResourceEvent, error) {
+	return c.Watch(ctx, WatchOptions{Kinds: []ResourceKind{KindLoadBalancer}})
+}
+
+// watchResourcesSSEOnce opens one SSE connection to /resources/watch and
+// forwards its events until the stream ends or errors, returning the
+// last event ID seen so the caller can resume from it. A response that
+// isn't event-stream content (or a 404, meaning the endpoint doesn't
+// exist at all) is reported as errSSEUnavailable instead of a plain
+// error, so Watch switches to long-polling rather than backing off
+// forever against an endpoint that will never work.
+func (c *EnhancedClient) watchResourcesSSEOnce(ctx context.Context, opts WatchOptions, lastID string, events chan<- ResourceEvent) (string, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/resources/watch", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.URL.RawQuery = opts.query().Encode()
+	req.Header.Set("Authorization", "Bearer "+c.authToken)
+	req.Header.Set("Accept", "text/event-stream")
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to watch resources: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", errSSEUnavailable
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to watch resources with status: %d", resp.StatusCode)
+	}
+	if !strings.Contains(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return "", errSSEUnavailable
+	}
+
+	var eventID string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "id:"):
+			eventID = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+		case strings.HasPrefix(line, "data:"):
+			payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			if payload == "" {
+				continue
+			}
+
+			var event ResourceEvent
+			if err := json.Unmarshal([]byte(payload), &event); err != nil {
+				continue
+			}
+			if event.ID == "" {
+				event.ID = eventID
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return event.ID, nil
+			}
+			if event.ID != "" {
+				lastID = event.ID
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return lastID, err
+	}
+	return lastID, nil
+}
+
+// resourcePollResponse is /resources/poll's response shape: the batch of
+// events new since "since", which the server is expected to hold the
+// request open waiting for (up to its own timeout) rather than returning
+// immediately, the usual long-poll contract.
+type resourcePollResponse struct {
+	Events []ResourceEvent `json:"events"`
+}
+
+// watchResourcesLongPollOnce issues one long-poll request to
+// /resources/poll since lastID and forwards whatever events it returns,
+// returning the last event ID seen so the caller can resume from it.
+func (c *EnhancedClient) watchResourcesLongPollOnce(ctx context.Context, opts WatchOptions, lastID string, events chan<- ResourceEvent) (string, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return "", err
+	}
+
+	q := opts.query()
+	if lastID != "" {
+		q.Set("since", lastID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/resources/poll", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Authorization", "Bearer "+c.authToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to poll resources: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to poll resources with status: %d", resp.StatusCode)
+	}
+
+	var payload resourcePollResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("failed to decode resource poll response: %w", err)
+	}
+
+	for _, event := range payload.Events {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			return lastID, nil
+		}
+		if event.ID != "" {
+			lastID = event.ID
+		}
+	}
+
+	if len(payload.Events) == 0 {
+		select {
+		case <-ctx.Done():
+		case <-time.After(longPollIdleDelay):
+		}
+	}
+	return lastID, nil
+}
+
+// ResourceCache maintains a locally consistent view of Instances,
+// Networks, LoadBalancers, and Storages by consuming an EnhancedClient's
+// Watch stream, so a component like the LB reconciler or health checker
+// can read from it instead of polling ListInstances (and friends) on a
+// timer.
+type ResourceCache struct {
+	client *EnhancedClient
+
+	mu            sync.RWMutex
+	instances     map[string]Instance
+	networks      map[string]Network
+	loadBalancers map[string]LoadBalancer
+	storages      map[string]Storage
+}
+
+// NewResourceCache builds an empty ResourceCache backed by client; call
+// Start to begin populating it.
+func NewResourceCache(client *EnhancedClient) *ResourceCache {
+	return &ResourceCache{
+		client:        client,
+		instances:     make(map[string]Instance),
+		networks:      make(map[string]Network),
+		loadBalancers: make(map[string]LoadBalancer),
+		storages:      make(map[string]Storage),
+	}
+}
+
+// Start subscribes to the full resource event stream and applies every
+// event to the cache until ctx is canceled. It blocks, so callers
+// typically run it in its own goroutine.
+func (rc *ResourceCache) Start(ctx context.Context) error {
+	events, err := rc.client.Watch(ctx, WatchOptions{})
+	if err != nil {
+		return err
+	}
+	for event := range events {
+		rc.apply(event)
+	}
+	return ctx.Err()
+}
+
+func (rc *ResourceCache) apply(event ResourceEvent) {
+	switch event.Kind {
+	case KindInstance:
+		rc.applyInstance(event)
+	case KindNetwork:
+		rc.applyNetwork(event)
+	case KindLoadBalancer:
+		rc.applyLoadBalancer(event)
+	case KindStorage:
+		rc.applyStorage(event)
+	}
+}
+
+func (rc *ResourceCache) applyInstance(event ResourceEvent) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if event.Type == ResourceDeleted {
+		delete(rc.instances, event.ResourceID)
+		return
+	}
+	var instance Instance
+	if err := json.Unmarshal(event.After, &instance); err != nil {
+		return
+	}
+	rc.instances[event.ResourceID] = instance
+}
+
+func (rc *ResourceCache) applyNetwork(event ResourceEvent) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if event.Type == ResourceDeleted {
+		delete(rc.networks, event.ResourceID)
+		return
+	}
+	var network Network
+	if err := json.Unmarshal(event.After, &network); err != nil {
+		return
+	}
+	rc.networks[event.ResourceID] = network
+}
+
+func (rc *ResourceCache) applyLoadBalancer(event ResourceEvent) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if event.Type == ResourceDeleted {
+		delete(rc.loadBalancers, event.ResourceID)
+		return
+	}
+	var lb LoadBalancer
+	if err := json.Unmarshal(event.After, &lb); err != nil {
+		return
+	}
+	rc.loadBalancers[event.ResourceID] = lb
+}
+
+func (rc *ResourceCache) applyStorage(event ResourceEvent) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if event.Type == ResourceDeleted {
+		delete(rc.storages, event.ResourceID)
+		return
+	}
+	var storage Storage
+	if err := json.Unmarshal(event.After, &storage); err != nil {
+		return
+	}
+	rc.storages[event.ResourceID] = storage
+}
+
+// Instance returns id's cached Instance, if the cache has seen one.
+func (rc *ResourceCache) Instance(id string) (Instance, bool) {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	instance, ok := rc.instances[id]
+	return instance, ok
+}
+
+// Instances returns every Instance currently in the cache.
+func (rc *ResourceCache) Instances() []Instance {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	out := make([]Instance, 0, len(rc.instances))
+	for _, instance := range rc.instances {
+		out = append(out, instance)
+	}
+	return out
+}
+
+// Network returns id's cached Network, if the cache has seen one.
+func (rc *ResourceCache) Network(id string) (Network, bool) {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	network, ok := rc.networks[id]
+	return network, ok
+}
+
+// Networks returns every Network currently in the cache.
+func (rc *ResourceCache) Networks() []Network {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	out := make([]Network, 0, len(rc.networks))
+	for _, network := range rc.networks {
+		out = append(out, network)
+	}
+	return out
+}
+
+// LoadBalancer returns id's cached LoadBalancer, if the cache has seen
+// one.
+func (rc *ResourceCache) LoadBalancer(id string) (LoadBalancer, bool) {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	lb, ok := rc.loadBalancers[id]
+	return lb, ok
+}
+
+// LoadBalancers returns every LoadBalancer currently in the cache.
+func (rc *ResourceCache) LoadBalancers() []LoadBalancer {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	out := make([]LoadBalancer, 0, len(rc.loadBalancers))
+	for _, lb := range rc.loadBalancers {
+		out = append(out, lb)
+	}
+	return out
+}
+
+// Storage returns id's cached Storage, if the cache has seen one.
+func (rc *ResourceCache) Storage(id string) (Storage, bool) {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	storage, ok := rc.storages[id]
+	return storage, ok
+}
+
+// Storages returns every Storage currently in the cache.
+func (rc *ResourceCache) Storages() []Storage {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	out := make([]Storage, 0, len(rc.storages))
+	for _, storage := range rc.storages {
+		out = append(out, storage)
+	}
+	return out
+}
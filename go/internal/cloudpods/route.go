@@ -0,0 +1,62 @@
+package cloudpods
+
+import "context"
+
+// HostnameRoute is something that can be attached to an Instance or
+// LoadBalancer at creation time to publish it under a hostname,
+// cloudflared tunnelstore's Route abstraction applied to CloudPods
+// resources (named HostnameRoute, not Route, since the package already
+// uses Route for a Network's routing table entries). Applying a
+// HostnameRoute is the last step CreateInstance/CreateLoadBalancer take
+// once the resource's Operation has resolved; see DNSService.applyRoutes.
+type HostnameRoute interface {
+	// RouteHostname is the DNS name this Route publishes, used to find
+	// and clean up the record it created once the owning resource is
+	// deleted.
+	RouteHostname() string
+
+	// apply provisions whatever DNS record this Route implies, given
+	// the address (public IP, VIP, ...) of the resource it was
+	// attached to; a Route that resolves its own target (LBRoute)
+	// ignores address.
+	apply(ctx context.Context, client *EnhancedClient, address string) (*DNSRecord, error)
+}
+
+// DNSRoute publishes a resource directly under Hostname: an A/AAAA
+// record pointing at the resource's own address, or a CNAME/TXT/SRV
+// record with Value left for CreateRecord's caller to fill in via
+// address.
+type DNSRoute struct {
+	Hostname string        `json:"hostname"`
+	Type     DNSRecordType `json:"type"`
+	TTL      int           `json:"ttl"`
+}
+
+func (r DNSRoute) RouteHostname() string { return r.Hostname }
+
+func (r DNSRoute) apply(ctx context.Context, client *EnhancedClient, address string) (*DNSRecord, error) {
+	ttl := r.TTL
+	if ttl <= 0 {
+		ttl = defaultRouteTTL
+	}
+	return client.DNS.createRouteRecord(ctx, r.Hostname, r.Type, address, ttl)
+}
+
+// LBRoute publishes Hostname as a front door to an existing
+// LoadBalancer (not necessarily the resource LBRoute is attached to):
+// it looks up LoadBalancerID's VIP and points an A record at it,
+// CloudPods' equivalent of "expose this LB at api.example.com".
+type LBRoute struct {
+	LoadBalancerID string `json:"load_balancer_id"`
+	Hostname       string `json:"hostname"`
+}
+
+func (r LBRoute) RouteHostname() string { return r.Hostname }
+
+func (r LBRoute) apply(ctx context.Context, client *EnhancedClient, _ string) (*DNSRecord, error) {
+	lb, err := client.GetLoadBalancer(ctx, r.LoadBalancerID)
+	if err != nil {
+		return nil, err
+	}
+	return client.DNS.createRouteRecord(ctx, r.Hostname, DNSRecordA, lb.VIP, defaultRouteTTL)
+}
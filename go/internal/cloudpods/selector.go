@@ -0,0 +1,197 @@
+package cloudpods
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ListOptions mirrors the parts of Kubernetes' metav1.ListOptions that
+// CloudPods' List* methods and the handlers in front of them understand:
+// narrow a list with a label or field selector, and page through large
+// results with Limit/Continue instead of returning everything at once.
+type ListOptions struct {
+	LabelSelector string
+	FieldSelector string
+	Limit         int64
+	Continue      string
+}
+
+// selectorRequirement is one comma-separated term of a selector:
+// "key=value"/"key==value" requires an exact match, "key!=value"
+// requires the key be absent or not equal to value.
+type selectorRequirement struct {
+	key    string
+	value  string
+	negate bool
+}
+
+// Selector is a parsed label or field selector, ready to test against a
+// resource's labels (its Tags) or fields (its flattened JSON form).
+type Selector struct {
+	requirements []selectorRequirement
+}
+
+// ParseSelector parses a Kubernetes-style equality selector such as
+// "env=prod,tier!=canary". An empty string parses to a Selector that
+// matches everything, the same as an absent labelSelector/fieldSelector
+// query parameter.
+func ParseSelector(raw string) (Selector, error) {
+	if strings.TrimSpace(raw) == "" {
+		return Selector{}, nil
+	}
+
+	var reqs []selectorRequirement
+	for _, term := range strings.Split(raw, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		switch {
+		case strings.Contains(term, "!="):
+			parts := strings.SplitN(term, "!=", 2)
+			reqs = append(reqs, selectorRequirement{key: strings.TrimSpace(parts[0]), value: strings.TrimSpace(parts[1]), negate: true})
+		case strings.Contains(term, "=="):
+			parts := strings.SplitN(term, "==", 2)
+			reqs = append(reqs, selectorRequirement{key: strings.TrimSpace(parts[0]), value: strings.TrimSpace(parts[1])})
+		case strings.Contains(term, "="):
+			parts := strings.SplitN(term, "=", 2)
+			reqs = append(reqs, selectorRequirement{key: strings.TrimSpace(parts[0]), value: strings.TrimSpace(parts[1])})
+		default:
+			return Selector{}, fmt.Errorf("cloudpods: invalid selector term %q", term)
+		}
+	}
+	return Selector{requirements: reqs}, nil
+}
+
+// Matches reports whether every requirement in s is satisfied by values.
+func (s Selector) Matches(values map[string]string) bool {
+	for _, req := range s.requirements {
+		actual, ok := values[req.key]
+		if req.negate {
+			if ok && actual == req.value {
+				return false
+			}
+			continue
+		}
+		if !ok || actual != req.value {
+			return false
+		}
+	}
+	return true
+}
+
+// flattenFields marshals obj to JSON and flattens it into a dotted-path
+// string map ({"status": "Ready"} becomes "status" -> "Ready",
+// {"metadata": {"name": "x"}} becomes "metadata.name" -> "x"). CloudPods
+// resources aren't modeled as a uniform object the way Kubernetes' are,
+// so FieldSelector matches against this flattened view instead of a
+// fixed set of well-known fields.
+func flattenFields(obj interface{}) (map[string]string, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("cloudpods: flattening fields: %w", err)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("cloudpods: flattening fields: %w", err)
+	}
+
+	out := make(map[string]string)
+	flattenInto(out, "", raw)
+	return out, nil
+}
+
+func flattenInto(out map[string]string, prefix string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, sub := range v {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flattenInto(out, key, sub)
+		}
+	case nil:
+	default:
+		out[prefix] = fmt.Sprintf("%v", v)
+	}
+}
+
+// matches reports whether a resource satisfies opts' LabelSelector and
+// FieldSelector. labels is typically a resource's Tags (see
+// CloudPodsResource.Labels); obj is the resource itself, flattened
+// lazily only if a FieldSelector was actually given.
+func (opts ListOptions) matches(labels map[string]string, obj interface{}) (bool, error) {
+	if opts.LabelSelector != "" {
+		sel, err := ParseSelector(opts.LabelSelector)
+		if err != nil {
+			return false, err
+		}
+		if !sel.Matches(labels) {
+			return false, nil
+		}
+	}
+	if opts.FieldSelector != "" {
+		sel, err := ParseSelector(opts.FieldSelector)
+		if err != nil {
+			return false, err
+		}
+		fields, err := flattenFields(obj)
+		if err != nil {
+			return false, err
+		}
+		if !sel.Matches(fields) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// page computes the [offset, end) slice bounds into a total-item,
+// already-filtered result set for opts.Limit/opts.Continue, plus the
+// continue token for the next page (empty once exhausted). CloudPods has
+// no real list cursor to resume from, so Continue is just the offset
+// into this exact query's filtered results, base64-encoded the way a
+// real cursor would be so callers don't parse or depend on its shape.
+func page(total int, opts ListOptions) (offset, end int, next string, err error) {
+	offset, err = decodeContinue(opts.Continue)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	if offset > total {
+		offset = total
+	}
+
+	end = total
+	if opts.Limit > 0 && int64(total-offset) > opts.Limit {
+		end = offset + int(opts.Limit)
+		next = encodeContinue(end)
+	}
+	return offset, end, next, nil
+}
+
+func encodeContinue(offset int) string {
+	if offset <= 0 {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodeContinue(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("cloudpods: invalid continue token")
+	}
+	offset, err := strconv.Atoi(string(data))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("cloudpods: invalid continue token")
+	}
+	return offset, nil
+}
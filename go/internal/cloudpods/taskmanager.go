@@ -0,0 +1,240 @@
+package cloudpods
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TaskState is a Task's current phase.
+type TaskState string
+
+const (
+	TaskPending   TaskState = "pending"
+	TaskRunning   TaskState = "running"
+	TaskSucceeded TaskState = "succeeded"
+	TaskFailed    TaskState = "failed"
+	TaskCanceled  TaskState = "canceled"
+)
+
+func (s TaskState) terminal() bool {
+	return s == TaskSucceeded || s == TaskFailed || s == TaskCanceled
+}
+
+// Task is the long-running-operation handle TaskManager tracks for one
+// mutating CloudPods call, the Golang compute service's job resource
+// applied to this repo's own API.
+type Task struct {
+	ID          string      `json:"id"`
+	Kind        string      `json:"kind"`
+	ResourceRef string      `json:"resource_ref,omitempty"`
+	State       TaskState   `json:"state"`
+	Progress    int         `json:"progress"`
+	StartedAt   time.Time   `json:"started_at"`
+	FinishedAt  time.Time   `json:"finished_at,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	Result      interface{} `json:"result,omitempty"`
+}
+
+// TaskStore persists Tasks, an interface so TaskManager can be backed by
+// something other than memoryTaskStore (e.g. a shared store across
+// replicas) without its callers changing.
+type TaskStore interface {
+	Save(t Task)
+	Get(id string) (Task, bool)
+	List() []Task
+	Delete(id string) bool
+}
+
+// memoryTaskStore is the default TaskStore: an in-memory, ID-keyed map
+// guarded by a RWMutex, the same shape as aviatrix's kindStore.
+type memoryTaskStore struct {
+	mu    sync.RWMutex
+	tasks map[string]Task
+}
+
+// NewMemoryTaskStore returns an empty in-memory TaskStore.
+func NewMemoryTaskStore() TaskStore {
+	return &memoryTaskStore{tasks: make(map[string]Task)}
+}
+
+func (s *memoryTaskStore) Save(t Task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks[t.ID] = t
+}
+
+func (s *memoryTaskStore) Get(id string) (Task, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.tasks[id]
+	return t, ok
+}
+
+func (s *memoryTaskStore) List() []Task {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Task, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		out = append(out, t)
+	}
+	return out
+}
+
+func (s *memoryTaskStore) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[id]; !ok {
+		return false
+	}
+	delete(s.tasks, id)
+	return true
+}
+
+// TaskFunc is the mutating call a Task runs: it performs the actual
+// CloudPods request and returns the resulting resource's ID and the
+// resource itself, the pair a terminal Task reports as ResourceRef and
+// Result.
+type TaskFunc func(ctx context.Context) (resourceRef string, result interface{}, err error)
+
+// TaskManager runs TaskFuncs on a bounded worker pool and tracks each run
+// as a Task, so a mutating handler can return 202 Accepted immediately and
+// let the caller poll (or wait) for completion instead of blocking the
+// request on CloudPods.
+type TaskManager struct {
+	store   TaskStore
+	workers chan struct{}
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+	nextID  uint64
+}
+
+// NewTaskManager builds a TaskManager storing Tasks in store and running
+// at most poolSize of them concurrently; poolSize defaults to 10.
+func NewTaskManager(store TaskStore, poolSize int) *TaskManager {
+	if poolSize <= 0 {
+		poolSize = 10
+	}
+	return &TaskManager{
+		store:   store,
+		workers: make(chan struct{}, poolSize),
+		cancels: make(map[string]context.CancelFunc),
+	}
+}
+
+// Submit starts fn on the worker pool under a fresh Task of kind, honoring
+// deadline (no deadline if zero), and returns the Task's initial pending
+// snapshot. fn's completion (or deadline/Cancel) transitions the Task to a
+// terminal state, visible through Get/List.
+func (m *TaskManager) Submit(kind string, deadline time.Duration, fn TaskFunc) Task {
+	id := fmt.Sprintf("task-%d-%d", time.Now().UnixNano(), atomic.AddUint64(&m.nextID, 1))
+
+	task := Task{ID: id, Kind: kind, State: TaskPending, StartedAt: time.Now()}
+	m.store.Save(task)
+
+	var runCtx context.Context
+	var cancel context.CancelFunc
+	if deadline > 0 {
+		runCtx, cancel = context.WithTimeout(context.Background(), deadline)
+	} else {
+		runCtx, cancel = context.WithCancel(context.Background())
+	}
+	m.mu.Lock()
+	m.cancels[id] = cancel
+	m.mu.Unlock()
+
+	go m.run(runCtx, cancel, task, fn)
+	return task
+}
+
+func (m *TaskManager) run(ctx context.Context, cancel context.CancelFunc, task Task, fn TaskFunc) {
+	defer cancel()
+
+	select {
+	case m.workers <- struct{}{}:
+		defer func() { <-m.workers }()
+	case <-ctx.Done():
+		task.State = TaskCanceled
+		task.FinishedAt = time.Now()
+		m.store.Save(task)
+		m.clearCancel(task.ID)
+		return
+	}
+
+	task.State = TaskRunning
+	task.Progress = 50
+	m.store.Save(task)
+
+	resourceRef, result, err := fn(ctx)
+
+	m.clearCancel(task.ID)
+	task.FinishedAt = time.Now()
+	task.ResourceRef = resourceRef
+	switch {
+	case err != nil && ctx.Err() != nil:
+		task.State = TaskCanceled
+		task.Error = err.Error()
+	case err != nil:
+		task.State = TaskFailed
+		task.Error = err.Error()
+	default:
+		task.State = TaskSucceeded
+		task.Progress = 100
+		task.Result = result
+	}
+	m.store.Save(task)
+}
+
+func (m *TaskManager) clearCancel(id string) {
+	m.mu.Lock()
+	delete(m.cancels, id)
+	m.mu.Unlock()
+}
+
+// Get returns id's current Task snapshot.
+func (m *TaskManager) Get(id string) (Task, bool) {
+	return m.store.Get(id)
+}
+
+// List returns every Task's current snapshot.
+func (m *TaskManager) List() []Task {
+	return m.store.List()
+}
+
+// Cancel cancels id's context if it is still running, reporting whether a
+// running task was found to cancel.
+func (m *TaskManager) Cancel(id string) bool {
+	m.mu.Lock()
+	cancel, ok := m.cancels[id]
+	m.mu.Unlock()
+	if !ok {
+		return false
+	}
+	cancel()
+	return true
+}
+
+// Wait blocks until id reaches a terminal state or timeout elapses,
+// returning the Task's latest snapshot and whether it reached one.
+func (m *TaskManager) Wait(ctx context.Context, id string, timeout time.Duration) (Task, bool) {
+	deadline, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		task, ok := m.store.Get(id)
+		if !ok || task.State.terminal() {
+			return task, ok && task.State.terminal()
+		}
+		select {
+		case <-deadline.Done():
+			return task, false
+		case <-ticker.C:
+		}
+	}
+}
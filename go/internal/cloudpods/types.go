@@ -1,6 +1,9 @@
 package cloudpods
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // Region represents a CloudPods region
 type Region struct {
@@ -35,8 +38,8 @@ type VPC struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-// Subnet represents a CloudPods subnet
-type Subnet struct {
+// ServiceSubnet represents a CloudPods subnet
+type ServiceSubnet struct {
 	ID          string    `json:"id"`
 	Name        string    `json:"name"`
 	VPCID       string    `json:"vpc_id"`
@@ -48,22 +51,22 @@ type Subnet struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-// Instance represents a CloudPods instance
-type Instance struct {
-	ID          string            `json:"id"`
-	Name        string            `json:"name"`
-	RegionID    string            `json:"region_id"`
-	ZoneID      string            `json:"zone_id"`
-	VPCID       string            `json:"vpc_id"`
-	SubnetID    string            `json:"subnet_id"`
-	InstanceType string           `json:"instance_type"`
-	ImageID     string            `json:"image_id"`
-	Status      string            `json:"status"`
-	PublicIP    string            `json:"public_ip"`
-	PrivateIP   string            `json:"private_ip"`
-	Tags        map[string]string `json:"tags"`
-	CreatedAt   time.Time         `json:"created_at"`
-	UpdatedAt   time.Time         `json:"updated_at"`
+// ServiceInstance represents a CloudPods instance
+type ServiceInstance struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	RegionID     string            `json:"region_id"`
+	ZoneID       string            `json:"zone_id"`
+	VPCID        string            `json:"vpc_id"`
+	SubnetID     string            `json:"subnet_id"`
+	InstanceType string            `json:"instance_type"`
+	ImageID      string            `json:"image_id"`
+	Status       string            `json:"status"`
+	PublicIP     string            `json:"public_ip"`
+	PrivateIP    string            `json:"private_ip"`
+	Tags         map[string]string `json:"tags"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
 }
 
 // CreateInstanceRequest represents a request to create an instance
@@ -78,8 +81,8 @@ type CreateInstanceRequest struct {
 	Tags         map[string]string `json:"tags,omitempty"`
 }
 
-// Network represents a CloudPods network
-type Network struct {
+// ServiceNetwork represents a CloudPods network
+type ServiceNetwork struct {
 	ID          string    `json:"id"`
 	Name        string    `json:"name"`
 	RegionID    string    `json:"region_id"`
@@ -90,44 +93,44 @@ type Network struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-// LoadBalancer represents a CloudPods load balancer
-type LoadBalancer struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	RegionID    string    `json:"region_id"`
-	VPCID       string    `json:"vpc_id"`
-	Type        string    `json:"type"`
-	Status      string    `json:"status"`
-	PublicIP    string    `json:"public_ip"`
-	PrivateIP   string    `json:"private_ip"`
-	Port        int       `json:"port"`
-	Protocol    string    `json:"protocol"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+// ServiceLoadBalancer represents a CloudPods load balancer
+type ServiceLoadBalancer struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	RegionID  string    `json:"region_id"`
+	VPCID     string    `json:"vpc_id"`
+	Type      string    `json:"type"`
+	Status    string    `json:"status"`
+	PublicIP  string    `json:"public_ip"`
+	PrivateIP string    `json:"private_ip"`
+	Port      int       `json:"port"`
+	Protocol  string    `json:"protocol"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // SecurityGroup represents a CloudPods security group
 type SecurityGroup struct {
-	ID          string              `json:"id"`
-	Name        string              `json:"name"`
-	RegionID    string              `json:"region_id"`
-	VPCID       string              `json:"vpc_id"`
-	Description string              `json:"description"`
-	Rules       []SecurityGroupRule `json:"rules"`
-	CreatedAt   time.Time           `json:"created_at"`
-	UpdatedAt   time.Time           `json:"updated_at"`
+	ID          string                     `json:"id"`
+	Name        string                     `json:"name"`
+	RegionID    string                     `json:"region_id"`
+	VPCID       string                     `json:"vpc_id"`
+	Description string                     `json:"description"`
+	Rules       []ServiceSecurityGroupRule `json:"rules"`
+	CreatedAt   time.Time                  `json:"created_at"`
+	UpdatedAt   time.Time                  `json:"updated_at"`
 }
 
-// SecurityGroupRule represents a security group rule
-type SecurityGroupRule struct {
-	ID              string `json:"id"`
-	Direction       string `json:"direction"`
-	Protocol        string `json:"protocol"`
-	PortRange       string `json:"port_range"`
-	Source          string `json:"source"`
-	Destination     string `json:"destination"`
-	Action          string `json:"action"`
-	Description     string `json:"description"`
+// ServiceSecurityGroupRule represents a security group rule
+type ServiceSecurityGroupRule struct {
+	ID          string `json:"id"`
+	Direction   string `json:"direction"`
+	Protocol    string `json:"protocol"`
+	PortRange   string `json:"port_range"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Action      string `json:"action"`
+	Description string `json:"description"`
 }
 
 // Metrics represents CloudPods metrics
@@ -152,12 +155,12 @@ type CloudPodsService interface {
 	GetRegions(ctx context.Context) ([]Region, error)
 	GetZones(ctx context.Context, regionID string) ([]Zone, error)
 	GetVPCs(ctx context.Context, regionID string) ([]VPC, error)
-	GetSubnets(ctx context.Context, vpcID string) ([]Subnet, error)
-	GetInstances(ctx context.Context, regionID string) ([]Instance, error)
-	CreateInstance(ctx context.Context, req CreateInstanceRequest) (*Instance, error)
+	GetSubnets(ctx context.Context, vpcID string) ([]ServiceSubnet, error)
+	GetInstances(ctx context.Context, regionID string) ([]ServiceInstance, error)
+	CreateInstance(ctx context.Context, req CreateInstanceRequest) (*ServiceInstance, error)
 	DeleteInstance(ctx context.Context, instanceID string) error
-	GetNetworks(ctx context.Context, regionID string) ([]Network, error)
-	GetLoadBalancers(ctx context.Context, regionID string) ([]LoadBalancer, error)
+	GetNetworks(ctx context.Context, regionID string) ([]ServiceNetwork, error)
+	GetLoadBalancers(ctx context.Context, regionID string) ([]ServiceLoadBalancer, error)
 	GetSecurityGroups(ctx context.Context, regionID string) ([]SecurityGroup, error)
 	GetMetrics(ctx context.Context, resourceType, resourceID, timeRange string) (*Metrics, error)
 }
@@ -0,0 +1,97 @@
+package cloudpods
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"router-sim/internal/cloudpods/admission"
+)
+
+// AdmissionDeniedError is returned by CreateVM/UpdateVM/DeleteVM when a
+// webhook on the client's mutating or validating chain rejects the
+// request, carrying its structured Reasons so a caller (e.g. a future VM
+// Gin handler) can surface the same 422 CloudPodsHandler's own admit
+// helper does for VPCs, subnets, NAT gateways, load balancers and
+// service-mesh routes.
+type AdmissionDeniedError struct {
+	Reasons []admission.Reason
+}
+
+func (e *AdmissionDeniedError) Error() string {
+	msgs := make([]string, 0, len(e.Reasons))
+	for _, r := range e.Reasons {
+		if r.Field != "" {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", r.Field, r.Message))
+		} else {
+			msgs = append(msgs, r.Message)
+		}
+	}
+	return fmt.Sprintf("cloudpods: admission denied: %s", strings.Join(msgs, "; "))
+}
+
+// findVM looks up a single VM by id, for admission webhooks (and
+// UpdateVM/DeleteVM) that need the object being replaced or removed.
+// CloudPods has no get-by-id endpoint for VMs, so this lists and filters
+// client-side the same way ApplyVPC resolves its "current" VPC by name.
+func (c *Client) findVM(ctx context.Context, vmID string) (CloudPodsVM, error) {
+	vms, err := c.GetVMs(ctx)
+	if err != nil {
+		return CloudPodsVM{}, fmt.Errorf("cloudpods: listing VMs for admission: %w", err)
+	}
+	for _, vm := range vms {
+		if vm.ID == vmID {
+			return vm, nil
+		}
+	}
+	return CloudPodsVM{}, fmt.Errorf("cloudpods: VM %q not found", vmID)
+}
+
+// admitVM runs vm through the client's mutating and validating admission
+// chains, if either is configured via SetVMAdmission, and returns the
+// (possibly mutated) object. oldVM is the object being replaced or
+// removed; it's ignored for VerbCreate. A rejecting webhook is reported
+// as an *AdmissionDeniedError.
+func (c *Client) admitVM(ctx context.Context, verb admission.Verb, oldVM, vm CloudPodsVM) (CloudPodsVM, error) {
+	if c.mutating == nil && c.validating == nil {
+		return vm, nil
+	}
+
+	var oldObj interface{}
+	if verb != admission.VerbCreate {
+		oldObj = oldVM
+	}
+	req := admission.Request{Kind: "vm", Verb: verb, Namespace: vm.VPC, Object: vm, OldObject: oldObj}
+
+	if c.mutating != nil {
+		mutated, resp, err := c.mutating.Admit(ctx, req)
+		if err != nil {
+			return CloudPodsVM{}, fmt.Errorf("cloudpods: mutating admission: %w", err)
+		}
+		if !resp.Allowed {
+			return CloudPodsVM{}, &AdmissionDeniedError{Reasons: resp.Reasons}
+		}
+		req = mutated
+	}
+
+	if c.validating != nil {
+		resp, err := c.validating.Admit(ctx, req)
+		if err != nil {
+			return CloudPodsVM{}, fmt.Errorf("cloudpods: validating admission: %w", err)
+		}
+		if !resp.Allowed {
+			return CloudPodsVM{}, &AdmissionDeniedError{Reasons: resp.Reasons}
+		}
+	}
+
+	data, err := json.Marshal(req.Object)
+	if err != nil {
+		return CloudPodsVM{}, fmt.Errorf("cloudpods: encoding admitted VM: %w", err)
+	}
+	var admitted CloudPodsVM
+	if err := json.Unmarshal(data, &admitted); err != nil {
+		return CloudPodsVM{}, fmt.Errorf("cloudpods: decoding admitted VM: %w", err)
+	}
+	return admitted, nil
+}
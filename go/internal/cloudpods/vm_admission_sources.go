@@ -0,0 +1,65 @@
+package cloudpods
+
+import "router-sim/internal/cloudpods/admission"
+
+// InformerAdmissionSource answers the built-in VM admitters' lookups
+// (admission.SubnetCIDRSource, SecurityGroupExistsSource, TenantUsageSource,
+// NameCollisionSource) out of an InformerFactory's local cache, so a
+// registered webhook never makes its own HTTP round trip to CloudPods.
+type InformerAdmissionSource struct {
+	factory *InformerFactory
+}
+
+// NewInformerAdmissionSource wraps factory for use with the admission
+// package's built-in VM webhook constructors, e.g.:
+//
+//	src := cloudpods.NewInformerAdmissionSource(factory)
+//	validating.Register("subnet-cidr", admission.Rule{Kinds: []string{"vm"}}, admission.NewSubnetCIDRValidator(src))
+func NewInformerAdmissionSource(factory *InformerFactory) *InformerAdmissionSource {
+	return &InformerAdmissionSource{factory: factory}
+}
+
+// SubnetCIDR implements admission.SubnetCIDRSource.
+func (s *InformerAdmissionSource) SubnetCIDR(vpcID, subnetID string) (string, bool) {
+	subnet, ok := s.factory.Subnets().Get(subnetID)
+	if !ok || subnet.VPC != vpcID {
+		return "", false
+	}
+	return subnet.CIDR, true
+}
+
+// SecurityGroupExists implements admission.SecurityGroupExistsSource.
+func (s *InformerAdmissionSource) SecurityGroupExists(vpcID, id string) bool {
+	sg, ok := s.factory.SecurityGroups().Get(id)
+	return ok && sg.VPC == vpcID
+}
+
+// TenantUsage implements admission.TenantUsageSource, summing CPU/memory
+// across every VM currently in the cache tagged with the given tenant.
+func (s *InformerAdmissionSource) TenantUsage(tenant string) (cpu, memory int) {
+	for _, vm := range s.factory.VMs().List() {
+		if vm.Tags["tenant"] != tenant {
+			continue
+		}
+		cpu += vm.CPU
+		memory += vm.Memory
+	}
+	return cpu, memory
+}
+
+// NamesInVPC implements admission.NameCollisionSource.
+func (s *InformerAdmissionSource) NamesInVPC(vpcID string) []string {
+	vms := s.factory.VMs().ByVPC(vpcID)
+	names := make([]string, 0, len(vms))
+	for _, vm := range vms {
+		names = append(names, vm.Name)
+	}
+	return names
+}
+
+var (
+	_ admission.SubnetCIDRSource          = (*InformerAdmissionSource)(nil)
+	_ admission.SecurityGroupExistsSource = (*InformerAdmissionSource)(nil)
+	_ admission.TenantUsageSource         = (*InformerAdmissionSource)(nil)
+	_ admission.NameCollisionSource       = (*InformerAdmissionSource)(nil)
+)
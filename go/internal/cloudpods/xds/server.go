@@ -0,0 +1,57 @@
+package xds
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	clusterservice "github.com/envoyproxy/go-control-plane/envoy/service/cluster/v3"
+	discoverygrpc "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	listenerservice "github.com/envoyproxy/go-control-plane/envoy/service/listener/v3"
+	routeservice "github.com/envoyproxy/go-control-plane/envoy/service/route/v3"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	serverv3 "github.com/envoyproxy/go-control-plane/pkg/server/v3"
+	"google.golang.org/grpc"
+)
+
+// Server is the gRPC control-plane Envoy sidecars connect to for CDS/RDS/
+// LDS (and ADS, for sidecars that prefer a single aggregated stream).
+// It has no state of its own: every response it sends comes straight out
+// of the SnapshotCache SnapshotManager.Update keeps current.
+type Server struct {
+	listenAddr string
+	grpcServer *grpc.Server
+}
+
+// NewServer wires cache into a grpc.Server implementing the xDS discovery
+// services. Call ListenAndServe to start accepting sidecar connections.
+func NewServer(ctx context.Context, cache cachev3.SnapshotCache, listenAddr string) *Server {
+	xdsServer := serverv3.NewServer(ctx, cache, nil)
+	grpcServer := grpc.NewServer()
+
+	discoverygrpc.RegisterAggregatedDiscoveryServiceServer(grpcServer, xdsServer)
+	clusterservice.RegisterClusterDiscoveryServiceServer(grpcServer, xdsServer)
+	routeservice.RegisterRouteDiscoveryServiceServer(grpcServer, xdsServer)
+	listenerservice.RegisterListenerDiscoveryServiceServer(grpcServer, xdsServer)
+
+	return &Server{listenAddr: listenAddr, grpcServer: grpcServer}
+}
+
+// ListenAndServe binds listenAddr and blocks serving xDS requests until the
+// listener fails or Stop is called, in which case it returns nil.
+func (s *Server) ListenAndServe() error {
+	lis, err := net.Listen("tcp", s.listenAddr)
+	if err != nil {
+		return fmt.Errorf("xds: listening on %q: %w", s.listenAddr, err)
+	}
+	if err := s.grpcServer.Serve(lis); err != nil && err != grpc.ErrServerStopped {
+		return fmt.Errorf("xds: serving on %q: %w", s.listenAddr, err)
+	}
+	return nil
+}
+
+// Stop gracefully stops the gRPC server, letting in-flight discovery
+// responses finish instead of severing sidecar connections mid-stream.
+func (s *Server) Stop() {
+	s.grpcServer.GracefulStop()
+}
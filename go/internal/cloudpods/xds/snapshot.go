@@ -0,0 +1,111 @@
+package xds
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	cachetypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+
+	"router-sim/internal/cloudpods"
+)
+
+// SnapshotManager translates a VPC's service-mesh routes into an Envoy
+// xDS snapshot and keeps go-control-plane's cache up to date as routes
+// change. Each VPC is its own xDS node: a sidecar configured with
+// node.id == <vpc id> receives that VPC's mesh routes and nothing else,
+// the same scoping ServiceMeshRoute's VPC field already imposes on the
+// REST API and its Watch* endpoints.
+type SnapshotManager struct {
+	cache cachev3.SnapshotCache
+
+	mu       sync.Mutex
+	versions map[string]int64
+}
+
+// NewSnapshotManager wraps an existing go-control-plane SnapshotCache
+// (construct one with cachev3.NewSnapshotCache(true, cachev3.IDHash{}, logger)
+// the usual way); SnapshotManager only owns pushing snapshots into it.
+func NewSnapshotManager(cache cachev3.SnapshotCache) *SnapshotManager {
+	return &SnapshotManager{cache: cache, versions: make(map[string]int64)}
+}
+
+// Update rebuilds nodeID's (a VPC id's) snapshot from routes and pushes
+// it into the cache, bumping the snapshot version so go-control-plane
+// treats it as new even on the rare occasion its content is unchanged
+// from the last push.
+func (m *SnapshotManager) Update(ctx context.Context, nodeID string, routes []cloudpods.CloudPodsServiceMeshRoute) error {
+	m.mu.Lock()
+	m.versions[nodeID]++
+	version := m.versions[nodeID]
+	m.mu.Unlock()
+
+	seen := make(map[string]bool, len(routes))
+	clusters := make([]cachetypes.Resource, 0, len(routes))
+	for _, r := range routes {
+		name := clusterName(nodeID, r.Destination)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		clusters = append(clusters, translateCluster(nodeID, r))
+	}
+
+	listener, err := translateListener(nodeID)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := cachev3.NewSnapshot(fmt.Sprintf("%d", version), map[resourcev3.Type][]cachetypes.Resource{
+		resourcev3.ClusterType:  clusters,
+		resourcev3.RouteType:    {translateRouteConfiguration(nodeID, routes)},
+		resourcev3.ListenerType: {listener},
+	})
+	if err != nil {
+		return fmt.Errorf("xds: building snapshot for node %q: %w", nodeID, err)
+	}
+	if err := snapshot.Consistent(); err != nil {
+		return fmt.Errorf("xds: inconsistent snapshot for node %q: %w", nodeID, err)
+	}
+
+	return m.cache.SetSnapshot(ctx, nodeID, snapshot)
+}
+
+// Watch subscribes to client's event bus for vpcID's service-mesh route
+// changes and pushes a fresh snapshot after every ADDED/MODIFIED/DELETED
+// event, so CreateServiceMeshRoute/UpdateServiceMeshRoute/
+// DeleteServiceMeshRoute reach connected Envoy sidecars without any
+// extra wiring in those handlers. It blocks until ctx is canceled or the
+// event bus closes its channel.
+func (m *SnapshotManager) Watch(ctx context.Context, client *cloudpods.Client, vpcID string) error {
+	_, events, unsubscribe := client.Events().Subscribe("servicemeshroute", vpcID, 0)
+	defer unsubscribe()
+
+	if err := m.refresh(ctx, client, vpcID); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case _, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := m.refresh(ctx, client, vpcID); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (m *SnapshotManager) refresh(ctx context.Context, client *cloudpods.Client, vpcID string) error {
+	routes, _, err := client.ListServiceMeshRoutes(ctx, vpcID, cloudpods.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("xds: listing service mesh routes for node %q: %w", vpcID, err)
+	}
+	return m.Update(ctx, vpcID, routes)
+}
@@ -0,0 +1,158 @@
+// Package xds turns the service-mesh routes managed through
+// ListServiceMeshRoutes/CreateServiceMeshRoute into an Envoy xDS
+// control-plane: a RouteDiscoveryService/ClusterDiscoveryService/
+// ListenerDiscoveryService/EndpointDiscoveryService server (via
+// go-control-plane) that real Envoy sidecars can point at, instead of
+// the CloudPods mesh routes only being consumable through the REST API.
+package xds
+
+import (
+	"fmt"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"router-sim/internal/cloudpods"
+)
+
+const (
+	listenerPort = 15001
+	httpFilter   = "envoy.filters.http.router"
+	networkHCM   = "envoy.filters.network.http_connection_manager"
+)
+
+// clusterName derives the Envoy cluster name for a route's destination,
+// namespaced by VPC so routes from different VPCs (different xDS nodes)
+// never collide even if two mesh routes happen to target the same
+// destination name.
+func clusterName(vpcID, destination string) string {
+	return fmt.Sprintf("%s/%s", vpcID, destination)
+}
+
+func routeConfigName(vpcID string) string {
+	return vpcID + "/mesh_routes"
+}
+
+func listenerName(vpcID string) string {
+	return vpcID + "/mesh_listener"
+}
+
+// translateCluster builds the Envoy Cluster backing a service-mesh
+// route's destination. CloudPods doesn't track individual backend IPs,
+// so this is STRICT_DNS against the destination name — Envoy resolves it
+// the same way it would any other externally-discovered service.
+func translateCluster(vpcID string, route cloudpods.CloudPodsServiceMeshRoute) *clusterv3.Cluster {
+	name := clusterName(vpcID, route.Destination)
+	return &clusterv3.Cluster{
+		Name:                 name,
+		ConnectTimeout:       durationpb.New(5 * time.Second),
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{Type: clusterv3.Cluster_STRICT_DNS},
+		LbPolicy:             clusterv3.Cluster_ROUND_ROBIN,
+		LoadAssignment: &endpointv3.ClusterLoadAssignment{
+			ClusterName: name,
+			Endpoints: []*endpointv3.LocalityLbEndpoints{{
+				LbEndpoints: []*endpointv3.LbEndpoint{{
+					HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
+						Endpoint: &endpointv3.Endpoint{
+							Address: &corev3.Address{
+								Address: &corev3.Address_SocketAddress{
+									SocketAddress: &corev3.SocketAddress{
+										Address:       route.Destination,
+										PortSpecifier: &corev3.SocketAddress_PortValue{PortValue: 80},
+									},
+								},
+							},
+						},
+					},
+				}},
+			}},
+		},
+	}
+}
+
+// translateRouteConfiguration folds every one of a VPC's service-mesh
+// routes into a single Envoy RouteConfiguration: one virtual host
+// matching any authority, one Envoy route per ServiceMeshRoute in
+// creation order (first prefix match wins, same as Envoy's own route
+// table semantics), each sending matched traffic to its destination's
+// cluster.
+func translateRouteConfiguration(vpcID string, routes []cloudpods.CloudPodsServiceMeshRoute) *routev3.RouteConfiguration {
+	vhost := &routev3.VirtualHost{
+		Name:    "mesh",
+		Domains: []string{"*"},
+	}
+
+	for _, r := range routes {
+		prefix := r.Match
+		if prefix == "" {
+			prefix = "/"
+		}
+		vhost.Routes = append(vhost.Routes, &routev3.Route{
+			Name: r.ID,
+			Match: &routev3.RouteMatch{
+				PathSpecifier: &routev3.RouteMatch_Prefix{Prefix: prefix},
+			},
+			Action: &routev3.Route_Route{
+				Route: &routev3.RouteAction{
+					ClusterSpecifier: &routev3.RouteAction_Cluster{Cluster: clusterName(vpcID, r.Destination)},
+				},
+			},
+		})
+	}
+
+	return &routev3.RouteConfiguration{
+		Name:         routeConfigName(vpcID),
+		VirtualHosts: []*routev3.VirtualHost{vhost},
+	}
+}
+
+// translateListener builds the single HTTP listener a VPC's mesh
+// sidecars use. Its HTTP connection manager pulls routeConfigName(vpcID)
+// from RDS over ADS rather than embedding routes inline, so a route
+// change pushes through RDS alone without needing a listener update (and
+// the listener drain/warm-up that would imply).
+func translateListener(vpcID string) (*listenerv3.Listener, error) {
+	hcm := &hcmv3.HttpConnectionManager{
+		StatPrefix: "mesh",
+		RouteSpecifier: &hcmv3.HttpConnectionManager_Rds{
+			Rds: &hcmv3.Rds{
+				RouteConfigName: routeConfigName(vpcID),
+				ConfigSource: &corev3.ConfigSource{
+					ResourceApiVersion:   corev3.ApiVersion_V3,
+					ConfigSourceSpecifier: &corev3.ConfigSource_Ads{Ads: &corev3.AggregatedConfigSource{}},
+				},
+			},
+		},
+		HttpFilters: []*hcmv3.HttpFilter{{Name: httpFilter}},
+	}
+
+	pbst, err := anypb.New(hcm)
+	if err != nil {
+		return nil, fmt.Errorf("xds: marshaling http connection manager for %q: %w", vpcID, err)
+	}
+
+	return &listenerv3.Listener{
+		Name: listenerName(vpcID),
+		Address: &corev3.Address{
+			Address: &corev3.Address_SocketAddress{
+				SocketAddress: &corev3.SocketAddress{
+					Address:       "0.0.0.0",
+					PortSpecifier: &corev3.SocketAddress_PortValue{PortValue: listenerPort},
+				},
+			},
+		},
+		FilterChains: []*listenerv3.FilterChain{{
+			Filters: []*listenerv3.Filter{{
+				Name:       networkHCM,
+				ConfigType: &listenerv3.Filter_TypedConfig{TypedConfig: pbst},
+			}},
+		}},
+	}, nil
+}
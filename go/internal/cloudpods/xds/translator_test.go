@@ -0,0 +1,113 @@
+package xds
+
+import (
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	listenerv3 "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+
+	"router-sim/internal/cloudpods"
+)
+
+func meshRoute(id, match, destination string) cloudpods.CloudPodsServiceMeshRoute {
+	return cloudpods.CloudPodsServiceMeshRoute{
+		CloudPodsResource: cloudpods.CloudPodsResource{ID: id},
+		Match:             match,
+		Destination:       destination,
+	}
+}
+
+func TestTranslateClusterNamesAreNamespacedByVPC(t *testing.T) {
+	c := translateCluster("vpc-1", meshRoute("route-1", "/api", "backend"))
+	if c.Name != "vpc-1/backend" {
+		t.Fatalf("Cluster.Name = %q, want %q", c.Name, "vpc-1/backend")
+	}
+	if c.ClusterDiscoveryType.(*clusterv3.Cluster_Type).Type != clusterv3.Cluster_STRICT_DNS {
+		t.Fatalf("ClusterDiscoveryType = %v, want STRICT_DNS", c.ClusterDiscoveryType)
+	}
+
+	endpoints := c.LoadAssignment.Endpoints
+	if len(endpoints) != 1 || len(endpoints[0].LbEndpoints) != 1 {
+		t.Fatalf("LoadAssignment.Endpoints = %+v, want exactly one endpoint", endpoints)
+	}
+}
+
+func TestTranslateRouteConfigurationDefaultsEmptyMatchToSlash(t *testing.T) {
+	rc := translateRouteConfiguration("vpc-1", []cloudpods.CloudPodsServiceMeshRoute{
+		meshRoute("route-1", "", "backend"),
+	})
+
+	vhost := rc.VirtualHosts[0]
+	if len(vhost.Routes) != 1 {
+		t.Fatalf("VirtualHost.Routes = %+v, want exactly one route", vhost.Routes)
+	}
+	prefix := vhost.Routes[0].Match.PathSpecifier.(*routev3.RouteMatch_Prefix).Prefix
+	if prefix != "/" {
+		t.Fatalf("Route prefix = %q, want \"/\" for an empty Match", prefix)
+	}
+}
+
+func TestTranslateRouteConfigurationPreservesOrderAndClusterTarget(t *testing.T) {
+	routes := []cloudpods.CloudPodsServiceMeshRoute{
+		meshRoute("route-1", "/a", "svc-a"),
+		meshRoute("route-2", "/b", "svc-b"),
+	}
+	rc := translateRouteConfiguration("vpc-1", routes)
+
+	vhost := rc.VirtualHosts[0]
+	if len(vhost.Routes) != 2 {
+		t.Fatalf("VirtualHost.Routes = %+v, want 2 routes", vhost.Routes)
+	}
+	if vhost.Routes[0].Name != "route-1" || vhost.Routes[1].Name != "route-2" {
+		t.Fatalf("route names = [%s, %s], want creation order preserved", vhost.Routes[0].Name, vhost.Routes[1].Name)
+	}
+
+	cluster0 := vhost.Routes[0].Action.(*routev3.Route_Route).Route.ClusterSpecifier.(*routev3.RouteAction_Cluster).Cluster
+	if cluster0 != "vpc-1/svc-a" {
+		t.Fatalf("first route's cluster = %q, want %q", cluster0, "vpc-1/svc-a")
+	}
+}
+
+func TestTranslateRouteConfigurationNameIncludesVPC(t *testing.T) {
+	rc := translateRouteConfiguration("vpc-1", nil)
+	if rc.Name != "vpc-1/mesh_routes" {
+		t.Fatalf("RouteConfiguration.Name = %q, want %q", rc.Name, "vpc-1/mesh_routes")
+	}
+	if len(rc.VirtualHosts) != 1 || rc.VirtualHosts[0].Domains[0] != "*" {
+		t.Fatalf("VirtualHosts = %+v, want one host matching any domain", rc.VirtualHosts)
+	}
+}
+
+func TestTranslateListenerNameAndAddress(t *testing.T) {
+	l, err := translateListener("vpc-1")
+	if err != nil {
+		t.Fatalf("translateListener: %v", err)
+	}
+	if l.Name != "vpc-1/mesh_listener" {
+		t.Fatalf("Listener.Name = %q, want %q", l.Name, "vpc-1/mesh_listener")
+	}
+	if len(l.FilterChains) != 1 || len(l.FilterChains[0].Filters) != 1 {
+		t.Fatalf("FilterChains = %+v, want a single filter chain with one filter", l.FilterChains)
+	}
+	if l.FilterChains[0].Filters[0].Name != networkHCM {
+		t.Fatalf("Filter.Name = %q, want %q", l.FilterChains[0].Filters[0].Name, networkHCM)
+	}
+
+	got, ok := l.FilterChains[0].Filters[0].ConfigType.(*listenerv3.Filter_TypedConfig)
+	if !ok || got.TypedConfig == nil {
+		t.Fatalf("Filter.ConfigType = %+v, want a populated TypedConfig", l.FilterChains[0].Filters[0].ConfigType)
+	}
+}
+
+func TestClusterNameAndRouteConfigNameHelpers(t *testing.T) {
+	if got := clusterName("vpc-1", "backend"); got != "vpc-1/backend" {
+		t.Fatalf("clusterName = %q, want %q", got, "vpc-1/backend")
+	}
+	if got := routeConfigName("vpc-1"); got != "vpc-1/mesh_routes" {
+		t.Fatalf("routeConfigName = %q, want %q", got, "vpc-1/mesh_routes")
+	}
+	if got := listenerName("vpc-1"); got != "vpc-1/mesh_listener" {
+		t.Fatalf("listenerName = %q, want %q", got, "vpc-1/mesh_listener")
+	}
+}
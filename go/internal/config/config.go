@@ -19,12 +19,69 @@ type ServerConfig struct {
 	Port        int    `json:"port"`
 	Environment string `json:"environment"`
 	Host        string `json:"host"`
+
+	// Partition is the admin partition a request is scoped to when it
+	// names none explicitly; see internal/partition.DefaultPartition.
+	Partition string `json:"partition"`
+
+	// CaptureDir is where internal/capture writes rotating pcap-ng
+	// capture files; CaptureMaxFileSize bytes is how large one file is
+	// allowed to grow before capture rolls over to the next.
+	CaptureDir         string `json:"capture_dir"`
+	CaptureMaxFileSize int64  `json:"capture_max_file_size"`
+
+	// PreStopDelay is how long server.HTTPServer.Shutdown waits after
+	// flipping /readyz to unready before it stops accepting connections,
+	// giving a load balancer time to notice and drain traffic away.
+	PreStopDelay time.Duration `json:"pre_stop_delay"`
+
+	// ShutdownTimeout bounds how long server.HTTPServer.Shutdown waits
+	// for in-flight requests to finish before giving up; <= 0 falls back
+	// to HTTPServer's own default.
+	ShutdownTimeout time.Duration `json:"shutdown_timeout"`
+
+	// AuditDir is where internal/events writes the rotating JSONL audit
+	// log of route/protocol mutations; AuditMaxFileSize bytes is how
+	// large one file grows before rotating, and AuditRetention is how
+	// long a rotated file is kept before being deleted (<= 0 keeps every
+	// file forever).
+	AuditDir         string        `json:"audit_dir"`
+	AuditMaxFileSize int64         `json:"audit_max_file_size"`
+	AuditRetention   time.Duration `json:"audit_retention"`
 }
 
 // AnalyticsConfig contains analytics configuration
 type AnalyticsConfig struct {
 	ClickHouse ClickHouseConfig `json:"clickhouse"`
 	Enabled    bool             `json:"enabled"`
+
+	// Exporters configures analytics.Engine's push exporters (e.g. an
+	// OCAgentExporter streaming to an OpenCensus/OTLP collector), in
+	// addition to its in-process Subscribe/Publish fan-out. Not
+	// populated by Load(); set programmatically by callers that want
+	// metrics pushed somewhere beyond the mock JSON endpoints.
+	Exporters []ExporterConfig `json:"exporters"`
+}
+
+// ExporterConfig describes one analytics.Exporter to start alongside
+// the Engine.
+type ExporterConfig struct {
+	// Type selects the Exporter implementation; currently only
+	// "ocagent" is recognized.
+	Type string `json:"type"`
+
+	// Endpoint is the collector address an Exporter like OCAgentExporter
+	// dials (host:port).
+	Endpoint string `json:"endpoint"`
+
+	// Insecure disables transport credentials on the dial; true is the
+	// common case for a collector reachable over a private network.
+	Insecure bool `json:"insecure"`
+
+	// BufferSize bounds how many MetricUpdates an Exporter holds while
+	// disconnected before dropping the oldest; <= 0 leaves it to the
+	// Exporter's own default.
+	BufferSize int `json:"buffer_size"`
 }
 
 // ClickHouseConfig contains ClickHouse configuration
@@ -42,6 +99,15 @@ type AviatrixConfig struct {
 	Username      string        `json:"username"`
 	Password      string        `json:"password"`
 	Timeout       time.Duration `json:"timeout"`
+
+	// Partition is sent as X-Router-Partition on every outgoing request,
+	// so the controller (or a proxy in front of it) can scope the calls
+	// this client makes to one tenant.
+	Partition string `json:"partition"`
+
+	// GatewayStorePath is where Service's BoltDB-backed GatewayStore
+	// persists desired/observed gateway state across restarts.
+	GatewayStorePath string `json:"gateway_store_path"`
 }
 
 // CloudPodsConfig contains CloudPods configuration
@@ -49,6 +115,32 @@ type CloudPodsConfig struct {
 	APIURL   string        `json:"api_url"`
 	Token    string        `json:"token"`
 	Timeout  time.Duration `json:"timeout"`
+
+	// Partition is sent as X-Router-Partition on every outgoing request,
+	// the same scoping AviatrixConfig.Partition provides.
+	Partition string `json:"partition"`
+
+	// RPS and Burst configure EnhancedClient's token-bucket rate
+	// limiter; a zero RPS falls back to EnhancedClient's own default
+	// rather than blocking every request.
+	RPS   float64 `json:"rps"`
+	Burst int     `json:"burst"`
+
+	// Regions configures cloudpods.FederatedClient's per-region
+	// endpoints; empty for a single-region deployment using APIURL
+	// directly.
+	Regions []RegionConfig `json:"regions"`
+}
+
+// RegionConfig is one region cloudpods.FederatedClient routes to: its
+// primary API endpoint, any fallback endpoints to fail over to when the
+// primary is unhealthy, and the auth token that region's API expects
+// (regions commonly have independent credentials).
+type RegionConfig struct {
+	Name      string   `json:"name"`
+	Primary   string   `json:"primary"`
+	Fallbacks []string `json:"fallbacks"`
+	AuthToken string   `json:"auth_token"`
 }
 
 // Load loads configuration from environment variables
@@ -58,6 +150,17 @@ func Load() (*Config, error) {
 			Port:        getEnvInt("SERVER_PORT", 8080),
 			Environment: getEnv("SERVER_ENV", "development"),
 			Host:        getEnv("SERVER_HOST", "0.0.0.0"),
+			Partition:   getEnv("ROUTER_PARTITION", "default"),
+
+			CaptureDir:         getEnv("CAPTURE_DIR", "./data/captures"),
+			CaptureMaxFileSize: int64(getEnvInt("CAPTURE_MAX_FILE_SIZE", 100*1024*1024)),
+
+			PreStopDelay:    time.Duration(getEnvInt("SERVER_PRESTOP_DELAY_SECONDS", 5)) * time.Second,
+			ShutdownTimeout: time.Duration(getEnvInt("SERVER_SHUTDOWN_TIMEOUT_SECONDS", 30)) * time.Second,
+
+			AuditDir:         getEnv("AUDIT_DIR", "./data/audit"),
+			AuditMaxFileSize: int64(getEnvInt("AUDIT_MAX_FILE_SIZE", 50*1024*1024)),
+			AuditRetention:   time.Duration(getEnvInt("AUDIT_RETENTION_HOURS", 24*30)) * time.Hour,
 		},
 		Analytics: AnalyticsConfig{
 			ClickHouse: ClickHouseConfig{
@@ -74,11 +177,15 @@ func Load() (*Config, error) {
 			Username:      getEnv("AVIATRIX_USERNAME", ""),
 			Password:      getEnv("AVIATRIX_PASSWORD", ""),
 			Timeout:       time.Duration(getEnvInt("AVIATRIX_TIMEOUT", 30)) * time.Second,
+			Partition:     getEnv("AVIATRIX_PARTITION", getEnv("ROUTER_PARTITION", "default")),
 		},
 		CloudPods: CloudPodsConfig{
-			APIURL:  getEnv("CLOUDPODS_API_URL", "https://api.cloudpods.com"),
-			Token:   getEnv("CLOUDPODS_TOKEN", ""),
-			Timeout: time.Duration(getEnvInt("CLOUDPODS_TIMEOUT", 30)) * time.Second,
+			APIURL:    getEnv("CLOUDPODS_API_URL", "https://api.cloudpods.com"),
+			Token:     getEnv("CLOUDPODS_TOKEN", ""),
+			Timeout:   time.Duration(getEnvInt("CLOUDPODS_TIMEOUT", 30)) * time.Second,
+			Partition: getEnv("CLOUDPODS_PARTITION", getEnv("ROUTER_PARTITION", "default")),
+			RPS:       float64(getEnvInt("CLOUDPODS_RPS", 10)),
+			Burst:     getEnvInt("CLOUDPODS_BURST", 20),
 		},
 	}
 
@@ -0,0 +1,201 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// auditFilePrefix names every file an AuditLog writes, so List can tell
+// its own rotated files apart from anything else sharing dir.
+const auditFilePrefix = "audit-"
+
+// AuditLog appends every Event it is handed to a rotating, append-only
+// JSONL file under dir, rolling over to a new file once the current one
+// reaches maxFileSize bytes. On each rotation it deletes files older
+// than retention (a retention of <= 0 keeps every rotated file
+// forever).
+type AuditLog struct {
+	dir         string
+	maxFileSize int64
+	retention   time.Duration
+
+	mu   sync.Mutex
+	seq  int64
+	file *os.File
+	w    *bufio.Writer
+	size int64
+}
+
+// NewAuditLog creates an AuditLog writing under dir, creating it if it
+// doesn't already exist, and opens its first file.
+func NewAuditLog(dir string, maxFileSize int64, retention time.Duration) (*AuditLog, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("events: creating audit log dir %q: %w", dir, err)
+	}
+
+	a := &AuditLog{dir: dir, maxFileSize: maxFileSize, retention: retention}
+	if err := a.openNext(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *AuditLog) openNext() error {
+	a.seq++
+	path := filepath.Join(a.dir, fmt.Sprintf("%s%06d.jsonl", auditFilePrefix, a.seq))
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("events: opening audit log file %q: %w", path, err)
+	}
+
+	a.file = file
+	a.w = bufio.NewWriter(file)
+	a.size = 0
+	return nil
+}
+
+// Append writes event as one JSON line, rotating to a new file first if
+// the current one has reached maxFileSize and pruning files older than
+// retention afterward.
+func (a *AuditLog) Append(event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("events: marshaling event %q: %w", event.ID, err)
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.maxFileSize > 0 && a.size >= a.maxFileSize {
+		if err := a.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := a.w.Write(line); err != nil {
+		return fmt.Errorf("events: writing audit log entry: %w", err)
+	}
+	if err := a.w.Flush(); err != nil {
+		return fmt.Errorf("events: flushing audit log: %w", err)
+	}
+	a.size += int64(len(line))
+	return nil
+}
+
+func (a *AuditLog) rotate() error {
+	if err := a.w.Flush(); err != nil {
+		return fmt.Errorf("events: flushing audit log before rotation: %w", err)
+	}
+	if err := a.file.Close(); err != nil {
+		return fmt.Errorf("events: closing audit log file: %w", err)
+	}
+	if err := a.openNext(); err != nil {
+		return err
+	}
+	a.prune()
+	return nil
+}
+
+// prune deletes rotated audit files whose last modification is older
+// than retention. Failures are ignored: a file prune misses this round
+// is retried on the next rotation.
+func (a *AuditLog) prune() {
+	if a.retention <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(a.dir)
+	if err != nil {
+		return
+	}
+
+	cutoff := time.Now().Add(-a.retention)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), auditFilePrefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		os.Remove(filepath.Join(a.dir, entry.Name()))
+	}
+}
+
+// List returns every persisted Event with a Timestamp after since (the
+// zero Time matches everything) and, when typ is non-empty, whose Type
+// equals it, oldest first.
+func (a *AuditLog) List(since time.Time, typ Type) ([]Event, error) {
+	a.mu.Lock()
+	if err := a.w.Flush(); err != nil {
+		a.mu.Unlock()
+		return nil, fmt.Errorf("events: flushing audit log before read: %w", err)
+	}
+	a.mu.Unlock()
+
+	entries, err := os.ReadDir(a.dir)
+	if err != nil {
+		return nil, fmt.Errorf("events: listing audit log dir %q: %w", a.dir, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), auditFilePrefix) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var out []Event
+	for _, name := range names {
+		events, err := readAuditFile(filepath.Join(a.dir, name))
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			if event.Timestamp.After(since) && (typ == "" || event.Type == typ) {
+				out = append(out, event)
+			}
+		}
+	}
+	return out, nil
+}
+
+func readAuditFile(path string) ([]Event, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("events: opening audit log file %q: %w", path, err)
+	}
+	defer file.Close()
+
+	var out []Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		out = append(out, event)
+	}
+	return out, scanner.Err()
+}
+
+// Close flushes and closes the AuditLog's current file.
+func (a *AuditLog) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.w.Flush(); err != nil {
+		return err
+	}
+	return a.file.Close()
+}
@@ -0,0 +1,68 @@
+package events
+
+import "sync"
+
+// Filter reports whether an Event should be delivered to a particular
+// Subscribe call; a nil Filter matches every Event.
+type Filter func(Event) bool
+
+// Bus is an in-memory pub/sub of audit Events, the same
+// subscriber-map/dispatch-loop shape as routing.Bus and scenario.Bus,
+// except each subscriber narrows the stream with its own Filter instead
+// of receiving everything.
+type Bus struct {
+	mu   sync.Mutex
+	subs map[chan Event]Filter
+}
+
+// NewBus creates an empty Bus.
+func NewBus() *Bus {
+	return &Bus{subs: make(map[chan Event]Filter)}
+}
+
+// Subscribe registers a channel that receives every future Event for
+// which filter returns true (or every Event, if filter is nil), until
+// Unsubscribe is called with the returned channel.
+func (b *Bus) Subscribe(filter Filter) <-chan Event {
+	ch := make(chan Event, 64)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[ch] = filter
+
+	return ch
+}
+
+// Unsubscribe removes the channel Subscribe returned from the
+// subscriber set and closes it.
+func (b *Bus) Unsubscribe(ch <-chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+		if sub == ch {
+			delete(b.subs, sub)
+			close(sub)
+			return
+		}
+	}
+}
+
+// Publish fans event out to every subscriber whose Filter accepts it.
+func (b *Bus) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch, filter := range b.subs {
+		if filter != nil && !filter(event) {
+			continue
+		}
+		select {
+		case ch <- event:
+		default:
+			// Slow subscriber; drop rather than block Publish. The
+			// AuditLog subscriber is the durable record of events a
+			// dropped notification can always be recovered from.
+		}
+	}
+}
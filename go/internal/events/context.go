@@ -0,0 +1,71 @@
+package events
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"router-sim/internal/partition"
+)
+
+type contextKey int
+
+const (
+	actorKey contextKey = iota
+	correlationIDKey
+)
+
+// CorrelationIDHeaderName is the header a caller sets to group a batch
+// of requests under one correlation ID; if unset, Middleware mints one
+// per request.
+const CorrelationIDHeaderName = "X-Correlation-ID"
+
+// ActorFromContext returns the actor ctx carries, and whether one was
+// set at all.
+func ActorFromContext(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(actorKey).(string)
+	return actor, ok
+}
+
+// WithActor returns a copy of ctx carrying actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// CorrelationIDFromContext returns the correlation ID ctx carries, and
+// whether one was set at all.
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey).(string)
+	return id, ok
+}
+
+// WithCorrelationID returns a copy of ctx carrying id.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey, id)
+}
+
+// Middleware resolves the actor a request acts as from
+// partition.SubjectHeaderName (the same header partition.Middleware
+// authorizes against, since this repo has no other authenticated
+// identity yet) and the correlation ID from CorrelationIDHeaderName,
+// minting a fresh one if the caller didn't send one, then injects both
+// into the request's Context so handlers can record them on the Events
+// they publish.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := c.GetHeader(partition.SubjectHeaderName)
+		if actor == "" {
+			actor = UnknownActor
+		}
+
+		correlationID := c.GetHeader(CorrelationIDHeaderName)
+		if correlationID == "" {
+			correlationID = NewID()
+		}
+
+		ctx := WithActor(c.Request.Context(), actor)
+		ctx = WithCorrelationID(ctx, correlationID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
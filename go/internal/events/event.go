@@ -0,0 +1,58 @@
+// Package events is the audit trail for route and protocol mutations:
+// a typed Event, an in-memory pub/sub Bus publishing them, and an
+// AuditLog persisting every one to a rotating JSONL file.
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// Type identifies the kind of mutation an Event records.
+type Type string
+
+const (
+	RouteAdded      Type = "route_added"
+	RouteRemoved    Type = "route_removed"
+	ProtocolStarted Type = "protocol_started"
+	ProtocolStopped Type = "protocol_stopped"
+
+	// AviatrixResourceCreated/Updated/Deleted record one action from an
+	// Aviatrix desired-state apply - see AviatrixHandler.Apply - rather
+	// than one event per whole apply, so a reviewer can see exactly
+	// which resources an apply touched.
+	AviatrixResourceCreated Type = "aviatrix_resource_created"
+	AviatrixResourceUpdated Type = "aviatrix_resource_updated"
+	AviatrixResourceDeleted Type = "aviatrix_resource_deleted"
+)
+
+// UnknownActor is the Actor recorded on an Event raised outside a
+// request carrying one, e.g. from a background job rather than an
+// authenticated HTTP call.
+const UnknownActor = "unknown"
+
+// Event is a single audited mutation: who did it, when, and the
+// before/after state it produced. CorrelationID groups every Event
+// raised while handling the same inbound request, so a reviewer can
+// line up e.g. a StopProtocol with the RouteRemoved events it caused.
+type Event struct {
+	ID            string      `json:"id"`
+	Type          Type        `json:"type"`
+	Actor         string      `json:"actor"`
+	CorrelationID string      `json:"correlation_id"`
+	Timestamp     time.Time   `json:"timestamp"`
+	Before        interface{} `json:"before,omitempty"`
+	After         interface{} `json:"after,omitempty"`
+}
+
+// NewID returns a random 16-character hex string, used to mint an
+// Event's ID and, absent one set by request middleware, its
+// CorrelationID.
+func NewID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
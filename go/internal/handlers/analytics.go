@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 
 	"router-sim/internal/analytics"
@@ -8,6 +9,21 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// traceEngineCall runs fn as a child span named "engine.<name>" of
+// whatever span TracingMiddleware started for this request, so a slow
+// analytics query shows up against the specific engine call that was
+// slow rather than just the handler's own span.
+func traceEngineCall[T any](ctx context.Context, name string, fn func(ctx context.Context) (T, error)) (T, error) {
+	ctx, span := tracer.Start(ctx, "engine."+name)
+	defer span.End()
+
+	result, err := fn(ctx)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return result, err
+}
+
 type AnalyticsHandler struct {
 	engine *analytics.Engine
 	logger *logrus.Logger
@@ -21,7 +37,7 @@ func NewAnalyticsHandler(engine *analytics.Engine, logger *logrus.Logger) *Analy
 }
 
 func (h *AnalyticsHandler) GetTrafficStats(c *gin.Context) {
-	stats, err := h.engine.GetTrafficStats(c.Request.Context())
+	stats, err := traceEngineCall(c.Request.Context(), "GetTrafficStats", h.engine.GetTrafficStats)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get traffic stats")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get traffic stats"})
@@ -35,7 +51,7 @@ func (h *AnalyticsHandler) GetTrafficStats(c *gin.Context) {
 }
 
 func (h *AnalyticsHandler) GetPerformanceMetrics(c *gin.Context) {
-	metrics, err := h.engine.GetPerformanceMetrics(c.Request.Context())
+	metrics, err := traceEngineCall(c.Request.Context(), "GetPerformanceMetrics", h.engine.GetPerformanceMetrics)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get performance metrics")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get performance metrics"})
@@ -49,7 +65,7 @@ func (h *AnalyticsHandler) GetPerformanceMetrics(c *gin.Context) {
 }
 
 func (h *AnalyticsHandler) GetRoutingStats(c *gin.Context) {
-	stats, err := h.engine.GetRoutingStats(c.Request.Context())
+	stats, err := traceEngineCall(c.Request.Context(), "GetRoutingStats", h.engine.GetRoutingStats)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get routing stats")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get routing stats"})
@@ -63,7 +79,7 @@ func (h *AnalyticsHandler) GetRoutingStats(c *gin.Context) {
 }
 
 func (h *AnalyticsHandler) GetCloudPodsStats(c *gin.Context) {
-	stats, err := h.engine.GetCloudPodsStats(c.Request.Context())
+	stats, err := traceEngineCall(c.Request.Context(), "GetCloudPodsStats", h.engine.GetCloudPodsStats)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get CloudPods stats")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get CloudPods stats"})
@@ -77,7 +93,7 @@ func (h *AnalyticsHandler) GetCloudPodsStats(c *gin.Context) {
 }
 
 func (h *AnalyticsHandler) GetAviatrixStats(c *gin.Context) {
-	stats, err := h.engine.GetAviatrixStats(c.Request.Context())
+	stats, err := traceEngineCall(c.Request.Context(), "GetAviatrixStats", h.engine.GetAviatrixStats)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to get Aviatrix stats")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get Aviatrix stats"})
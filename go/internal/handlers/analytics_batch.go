@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"router-sim/internal/apispec"
+)
+
+// analyticsBatchSources is every stat source BatchQuery fetches when
+// BatchQueryRequest.Sources is empty.
+var analyticsBatchSources = []string{"traffic", "performance", "routing", "cloudpods", "aviatrix"}
+
+// BatchQueryRequest selects which stat sources a POST
+// /analytics/query:batch call fetches; an empty Sources fetches every
+// source in analyticsBatchSources.
+type BatchQueryRequest struct {
+	Sources []string `json:"sources,omitempty"`
+}
+
+// RegisterBatchRoutes adds the analytics batch route group to b.
+func (h *AnalyticsHandler) RegisterBatchRoutes(b *apispec.Builder) {
+	b.Add(apispec.Route{
+		Method:     http.MethodPost,
+		Path:       "/analytics/query:batch",
+		Summary:    "Fetch traffic, performance, routing, CloudPods, and Aviatrix stats in one round-trip",
+		Tags:       []string{"analytics", "batch"},
+		NewRequest: func() interface{} { return &BatchQueryRequest{} },
+		Handler: func(c *gin.Context, req interface{}) {
+			h.BatchQuery(c, req.(*BatchQueryRequest))
+		},
+	})
+}
+
+// BatchQuery handles POST /api/v1/analytics/query:batch: every
+// requested source is fetched concurrently, and one source's failure
+// is reported alongside the others' data rather than failing the whole
+// call.
+func (h *AnalyticsHandler) BatchQuery(c *gin.Context, req *BatchQueryRequest) {
+	sources := req.Sources
+	if len(sources) == 0 {
+		sources = analyticsBatchSources
+	}
+
+	ctx := c.Request.Context()
+	data := make(map[string]interface{}, len(sources))
+	var dataMu sync.Mutex
+	hadError := false
+
+	var wg sync.WaitGroup
+	for _, source := range sources {
+		source := source
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := h.queryBatchSource(ctx, source)
+
+			dataMu.Lock()
+			defer dataMu.Unlock()
+			if err != nil {
+				hadError = true
+				data[source] = gin.H{"error": err.Error()}
+				return
+			}
+			data[source] = result
+		}()
+	}
+	wg.Wait()
+
+	status := http.StatusOK
+	if hadError {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, gin.H{
+		"status": "success",
+		"data":   data,
+	})
+}
+
+// queryBatchSource fetches the stats source named by source, the same
+// per-source engine calls GetTrafficStats/.../GetAviatrixStats already
+// expose individually.
+func (h *AnalyticsHandler) queryBatchSource(ctx context.Context, source string) (interface{}, error) {
+	switch source {
+	case "traffic":
+		return h.engine.GetTrafficStats(ctx)
+	case "performance":
+		return h.engine.GetPerformanceMetrics(ctx)
+	case "routing":
+		return h.engine.GetRoutingStats(ctx)
+	case "cloudpods":
+		return h.engine.GetCloudPodsStats(ctx)
+	case "aviatrix":
+		return h.engine.GetAviatrixStats(ctx)
+	default:
+		return nil, fmt.Errorf("unknown analytics batch source %q", source)
+	}
+}
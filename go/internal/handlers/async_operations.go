@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"router-sim/internal/apispec"
+	"router-sim/internal/aviatrix/jobs"
+)
+
+// jobsBasePath is where AsyncOperationHandler mounts job lifecycle
+// routes; AviatrixHandler.startJob reports it (plus the new job's id)
+// as the Location header of its 202 response.
+const jobsBasePath = "/api/v1/aviatrix/jobs"
+
+// jobStreamQueueSize bounds how many log lines StreamJob buffers for a
+// subscriber before dropping the oldest, the same backpressure
+// runStream gives every other stream in this package.
+const jobStreamQueueSize = 32
+
+// AsyncOperationHandler exposes the lifecycle of jobs.Job values that
+// AviatrixHandler's long-running Create* operations enqueue: polling
+// status, reading accumulated logs, canceling, and streaming progress
+// as it happens.
+type AsyncOperationHandler struct {
+	jobs *jobs.Manager
+}
+
+// NewAsyncOperationHandler wires manager into an AsyncOperationHandler;
+// manager should be the same *jobs.Manager passed to NewAviatrixHandler
+// so job ids returned by one resolve on the other.
+func NewAsyncOperationHandler(manager *jobs.Manager) *AsyncOperationHandler {
+	return &AsyncOperationHandler{jobs: manager}
+}
+
+// RegisterJobRoutes adds the async job route group to b.
+func (h *AsyncOperationHandler) RegisterJobRoutes(b *apispec.Builder) {
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/aviatrix/jobs/:id",
+		Summary: "Get an Aviatrix async operation's current status and result",
+		Tags:    []string{"aviatrix", "jobs"},
+		Handler: func(c *gin.Context, _ interface{}) { h.GetJob(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/aviatrix/jobs/:id/logs",
+		Summary: "Get the progress log an Aviatrix async operation has recorded so far",
+		Tags:    []string{"aviatrix", "jobs"},
+		Handler: func(c *gin.Context, _ interface{}) { h.GetJobLogs(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodDelete,
+		Path:    "/aviatrix/jobs/:id",
+		Summary: "Cancel an in-flight Aviatrix async operation",
+		Tags:    []string{"aviatrix", "jobs"},
+		Handler: func(c *gin.Context, _ interface{}) { h.CancelJob(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/aviatrix/jobs/:id/stream",
+		Summary: "Stream an Aviatrix async operation's progress (SSE, falls back to WebSocket)",
+		Tags:    []string{"aviatrix", "jobs"},
+		Handler: func(c *gin.Context, _ interface{}) { h.StreamJob(c) },
+	})
+}
+
+// GetJob handles GET /api/v1/aviatrix/jobs/:id.
+func (h *AsyncOperationHandler) GetJob(c *gin.Context) {
+	job, ok := h.jobs.Get(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   job,
+	})
+}
+
+// GetJobLogs handles GET /api/v1/aviatrix/jobs/:id/logs.
+func (h *AsyncOperationHandler) GetJobLogs(c *gin.Context) {
+	entries, ok := h.jobs.Logs(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   entries,
+	})
+}
+
+// CancelJob handles DELETE /api/v1/aviatrix/jobs/:id: it cancels the
+// context the job is running with, and relies on the job's own
+// operation to notice and stop promptly.
+func (h *AsyncOperationHandler) CancelJob(c *gin.Context) {
+	if err := h.jobs.Cancel(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// StreamJob handles GET /api/v1/aviatrix/jobs/:id/stream: every log
+// line the job records from the moment of connection onward (plus
+// whatever it had already recorded) is forwarded as a "log" frame until
+// the job finishes or the client disconnects.
+func (h *AsyncOperationHandler) StreamJob(c *gin.Context) {
+	id := c.Param("id")
+
+	ch := make(chan jobs.LogEntry, jobStreamQueueSize)
+	backlog, ok := h.jobs.Watch(id, ch)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	}
+	defer h.jobs.Unwatch(id, ch)
+
+	if wantsWebSocket(c) {
+		conn, err := streamUpgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for _, entry := range backlog {
+			if err := conn.WriteJSON(entry); err != nil {
+				return
+			}
+		}
+
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case entry, ok := <-ch:
+				if !ok {
+					return
+				}
+				if err := conn.WriteJSON(entry); err != nil {
+					return
+				}
+			}
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+
+	for _, entry := range backlog {
+		writeJobLogFrame(c, entry)
+	}
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case entry, ok := <-ch:
+			if !ok {
+				return
+			}
+			writeJobLogFrame(c, entry)
+		}
+	}
+}
+
+func writeJobLogFrame(c *gin.Context, entry jobs.LogEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	sseWriter(c, "log", "", data)
+}
@@ -1,25 +1,110 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"router-sim/internal/aviatrix"
+	"router-sim/internal/aviatrix/jobs"
+	"router-sim/internal/events"
+	"router-sim/internal/metrics"
+	"router-sim/pkg/httpquery"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
+// syncHeader, when sent as "true", asks a job-backed handler to block
+// and return its result inline instead of answering 202 with a job id -
+// the escape hatch DeleteTransitGateway and friends never needed
+// because they're already fast, but Create* against a real controller
+// can take minutes.
+const syncHeader = "X-Sync"
+
 type AviatrixHandler struct {
 	client *aviatrix.Client
 	logger *logrus.Logger
+	jobs   *jobs.Manager
+	events *events.Bus
 }
 
-func NewAviatrixHandler(client *aviatrix.Client, logger *logrus.Logger) *AviatrixHandler {
+func NewAviatrixHandler(client *aviatrix.Client, logger *logrus.Logger, jobManager *jobs.Manager, bus *events.Bus) *AviatrixHandler {
 	return &AviatrixHandler{
 		client: client,
 		logger: logger,
+		jobs:   jobManager,
+		events: bus,
 	}
 }
 
+// publish fans event out on h.events, a no-op if this AviatrixHandler
+// was built without one - the same convention RoutingHandler's publish
+// follows.
+func (h *AviatrixHandler) publish(event events.Event) {
+	if h.events != nil {
+		h.events.Publish(event)
+	}
+}
+
+// auditEvent builds the Event an apply action publishes after it runs,
+// pulling actor and correlation ID from the context events.Middleware
+// populated, the same way RoutingHandler.auditEvent does.
+func (h *AviatrixHandler) auditEvent(ctx context.Context, typ events.Type, before, after interface{}) events.Event {
+	actor, ok := events.ActorFromContext(ctx)
+	if !ok {
+		actor = events.UnknownActor
+	}
+	correlationID, ok := events.CorrelationIDFromContext(ctx)
+	if !ok {
+		correlationID = events.NewID()
+	}
+
+	return events.Event{
+		ID:            events.NewID(),
+		Type:          typ,
+		Actor:         actor,
+		CorrelationID: correlationID,
+		Timestamp:     time.Now(),
+		Before:        before,
+		After:         after,
+	}
+}
+
+// wantsSync reports whether c asked for the pre-job-queue blocking
+// behavior via the X-Sync header.
+func wantsSync(c *gin.Context) bool {
+	return c.GetHeader(syncHeader) == "true"
+}
+
+// recordGatewayCount refreshes metrics.AviatrixGatewayCount for
+// gatewayType from a freshly listed page of gateways, regrouping by
+// cloud/region so a List call always replaces the previous count for
+// that type rather than accumulating.
+func recordGatewayCount[T any](gatewayType string, gateways []T, cloudRegion func(T) (cloud, region string)) {
+	counts := map[[2]string]int{}
+	for _, gw := range gateways {
+		cloud, region := cloudRegion(gw)
+		counts[[2]string{cloud, region}]++
+	}
+	for key, count := range counts {
+		metrics.AviatrixGatewayCount.WithLabelValues(gatewayType, key[0], key[1]).Set(float64(count))
+	}
+}
+
+// startJob enqueues fn as a background jobs.Job named operation and
+// answers c with 202 Accepted, a Location header pointing at the new
+// job's status endpoint, and the job itself as the response body.
+func (h *AviatrixHandler) startJob(c *gin.Context, operation string, fn func(ctx context.Context, log func(string)) (interface{}, error)) {
+	job := h.jobs.Run(operation, fn)
+	c.Header("Location", fmt.Sprintf("%s/%s", jobsBasePath, job.ID))
+	c.JSON(http.StatusAccepted, gin.H{
+		"status": "success",
+		"data":   job,
+	})
+}
+
 // Transit Gateway Handlers
 func (h *AviatrixHandler) ListTransitGateways(c *gin.Context) {
 	gateways, err := h.client.ListTransitGateways(c.Request.Context())
@@ -28,13 +113,22 @@ func (h *AviatrixHandler) ListTransitGateways(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list transit gateways"})
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   gateways,
+	recordGatewayCount("transit", gateways, func(gw aviatrix.TransitGateway) (string, string) {
+		return strconv.Itoa(gw.CloudType), gw.Region
 	})
+
+	result, err := httpquery.Apply(c, gateways)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	httpquery.Respond(c, result)
 }
 
+// CreateTransitGateway provisions a transit gateway against the real
+// Aviatrix controller, which can take several minutes. By default this
+// enqueues a jobs.Job and answers 202 Accepted immediately; sending
+// X-Sync: true preserves the original blocking behavior.
 func (h *AviatrixHandler) CreateTransitGateway(c *gin.Context) {
 	var req aviatrix.CreateTransitGatewayRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -42,16 +136,29 @@ func (h *AviatrixHandler) CreateTransitGateway(c *gin.Context) {
 		return
 	}
 
-	gateway, err := h.client.CreateTransitGateway(c.Request.Context(), req)
-	if err != nil {
-		h.logger.WithError(err).Error("Failed to create transit gateway")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create transit gateway"})
+	if wantsSync(c) {
+		gateway, err := h.client.CreateTransitGateway(c.Request.Context(), req)
+		if err != nil {
+			h.logger.WithError(err).Error("Failed to create transit gateway")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create transit gateway"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"status": "success",
+			"data":   gateway,
+		})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"status": "success",
-		"data":   gateway,
+	h.startJob(c, "create_transit_gateway", func(ctx context.Context, log func(string)) (interface{}, error) {
+		log(fmt.Sprintf("creating transit gateway %q", req.GatewayName))
+		gateway, err := h.client.CreateTransitGateway(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		log("transit gateway created")
+		return gateway, nil
 	})
 }
 
@@ -126,13 +233,20 @@ func (h *AviatrixHandler) ListSpokeGateways(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list spoke gateways"})
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   gateways,
+	recordGatewayCount("spoke", gateways, func(gw aviatrix.SpokeGateway) (string, string) {
+		return strconv.Itoa(gw.CloudType), gw.Region
 	})
+
+	result, err := httpquery.Apply(c, gateways)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	httpquery.Respond(c, result)
 }
 
+// CreateSpokeGateway provisions a spoke gateway, with the same
+// job-queue-by-default/X-Sync-to-block behavior as CreateTransitGateway.
 func (h *AviatrixHandler) CreateSpokeGateway(c *gin.Context) {
 	var req aviatrix.CreateSpokeGatewayRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -140,16 +254,29 @@ func (h *AviatrixHandler) CreateSpokeGateway(c *gin.Context) {
 		return
 	}
 
-	gateway, err := h.client.CreateSpokeGateway(c.Request.Context(), req)
-	if err != nil {
-		h.logger.WithError(err).Error("Failed to create spoke gateway")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create spoke gateway"})
+	if wantsSync(c) {
+		gateway, err := h.client.CreateSpokeGateway(c.Request.Context(), req)
+		if err != nil {
+			h.logger.WithError(err).Error("Failed to create spoke gateway")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create spoke gateway"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"status": "success",
+			"data":   gateway,
+		})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"status": "success",
-		"data":   gateway,
+	h.startJob(c, "create_spoke_gateway", func(ctx context.Context, log func(string)) (interface{}, error) {
+		log("creating spoke gateway")
+		gateway, err := h.client.CreateSpokeGateway(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		log("spoke gateway created")
+		return gateway, nil
 	})
 }
 
@@ -225,12 +352,16 @@ func (h *AviatrixHandler) ListVPCConnections(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   connections,
-	})
+	result, err := httpquery.Apply(c, connections)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	httpquery.Respond(c, result)
 }
 
+// CreateVPCConnection provisions a VPC connection, with the same
+// job-queue-by-default/X-Sync-to-block behavior as CreateTransitGateway.
 func (h *AviatrixHandler) CreateVPCConnection(c *gin.Context) {
 	var req aviatrix.CreateVPCConnectionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -238,16 +369,29 @@ func (h *AviatrixHandler) CreateVPCConnection(c *gin.Context) {
 		return
 	}
 
-	connection, err := h.client.CreateVPCConnection(c.Request.Context(), req)
-	if err != nil {
-		h.logger.WithError(err).Error("Failed to create VPC connection")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create VPC connection"})
+	if wantsSync(c) {
+		connection, err := h.client.CreateVPCConnection(c.Request.Context(), req)
+		if err != nil {
+			h.logger.WithError(err).Error("Failed to create VPC connection")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create VPC connection"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"status": "success",
+			"data":   connection,
+		})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"status": "success",
-		"data":   connection,
+	h.startJob(c, "create_vpc_connection", func(ctx context.Context, log func(string)) (interface{}, error) {
+		log("creating VPC connection")
+		connection, err := h.client.CreateVPCConnection(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		log("VPC connection created")
+		return connection, nil
 	})
 }
 
@@ -297,12 +441,17 @@ func (h *AviatrixHandler) ListSite2CloudConnections(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   connections,
-	})
+	result, err := httpquery.Apply(c, connections)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	httpquery.Respond(c, result)
 }
 
+// CreateSite2CloudConnection provisions a Site2Cloud tunnel, with the
+// same job-queue-by-default/X-Sync-to-block behavior as
+// CreateTransitGateway.
 func (h *AviatrixHandler) CreateSite2CloudConnection(c *gin.Context) {
 	var req aviatrix.CreateSite2CloudConnectionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -310,16 +459,29 @@ func (h *AviatrixHandler) CreateSite2CloudConnection(c *gin.Context) {
 		return
 	}
 
-	connection, err := h.client.CreateSite2CloudConnection(c.Request.Context(), req)
-	if err != nil {
-		h.logger.WithError(err).Error("Failed to create site-to-cloud connection")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create site-to-cloud connection"})
+	if wantsSync(c) {
+		connection, err := h.client.CreateSite2CloudConnection(c.Request.Context(), req)
+		if err != nil {
+			h.logger.WithError(err).Error("Failed to create site-to-cloud connection")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create site-to-cloud connection"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"status": "success",
+			"data":   connection,
+		})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"status": "success",
-		"data":   connection,
+	h.startJob(c, "create_site2cloud_connection", func(ctx context.Context, log func(string)) (interface{}, error) {
+		log("creating site-to-cloud connection")
+		connection, err := h.client.CreateSite2CloudConnection(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		log("site-to-cloud connection created")
+		return connection, nil
 	})
 }
 
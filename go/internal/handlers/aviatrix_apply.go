@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"router-sim/internal/apispec"
+	"router-sim/internal/aviatrix"
+	"router-sim/internal/events"
+)
+
+// Resource kinds a DesiredState document and PlanAction name, in the
+// dependency order an apply must create/update them in: a spoke can
+// reference a transit gateway, and an attachment can reference either,
+// so transit gateways go first, then spoke gateways, then attachments.
+// Deletes run in the reverse of this order.
+const (
+	kindTransitGateway       = "transit_gateway"
+	kindSpokeGateway         = "spoke_gateway"
+	kindVPCConnection        = "vpc_connection"
+	kindSite2CloudConnection = "site2cloud_connection"
+)
+
+// applyKindOrder is kindTransitGateway..kindSite2CloudConnection in
+// dependency order, used both to order a plan's create/update actions
+// and, reversed, its delete actions.
+var applyKindOrder = []string{kindTransitGateway, kindSpokeGateway, kindVPCConnection, kindSite2CloudConnection}
+
+// DesiredState is the full desired-state document POST /aviatrix/apply
+// and POST /aviatrix/plan accept. vpc_connections and
+// site2cloud_connections are carried as raw JSON objects, each expected
+// to have a "name" field: this client has no VPCConnection or
+// Site2CloudConnection type to bind them into (see ListVPCConnections/
+// ListSite2CloudConnections below), so a plan can diff them by name but
+// an apply can't actually create, update, or delete one yet.
+type DesiredState struct {
+	TransitGateways       []aviatrix.TransitGateway `json:"transit_gateways"`
+	SpokeGateways         []aviatrix.SpokeGateway   `json:"spoke_gateways"`
+	VPCConnections        []json.RawMessage         `json:"vpc_connections"`
+	Site2CloudConnections []json.RawMessage         `json:"site2cloud_connections"`
+}
+
+// PlanAction is one create, update, or delete a Plan will perform, in
+// the order it will run.
+type PlanAction struct {
+	Kind      string      `json:"kind"`
+	Operation string      `json:"operation"` // "create", "update", or "delete"
+	Name      string      `json:"name"`
+	Desired   interface{} `json:"desired,omitempty"`
+	Current   interface{} `json:"current,omitempty"`
+}
+
+// Plan is the ordered list of actions an apply of a DesiredState would
+// take against the cluster's current state.
+type Plan struct {
+	Actions []PlanAction `json:"actions"`
+}
+
+// RegisterApplyRoutes adds the desired-state apply/plan route group to b.
+func (h *AviatrixHandler) RegisterApplyRoutes(b *apispec.Builder) {
+	b.Add(apispec.Route{
+		Method:  http.MethodPost,
+		Path:    "/aviatrix/plan",
+		Summary: "Diff a desired Aviatrix topology against current state without applying it",
+		Tags:    []string{"aviatrix", "apply"},
+		Handler: func(c *gin.Context, _ interface{}) { h.PlanApply(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodPost,
+		Path:    "/aviatrix/apply",
+		Summary: "Reconcile Aviatrix topology to a desired state, transactionally",
+		Tags:    []string{"aviatrix", "apply"},
+		Handler: func(c *gin.Context, _ interface{}) { h.Apply(c) },
+	})
+}
+
+// PlanApply handles POST /api/v1/aviatrix/plan: it computes and returns
+// the same Plan Apply would execute, without making any changes.
+func (h *AviatrixHandler) PlanApply(c *gin.Context) {
+	var desired DesiredState
+	if err := c.ShouldBindJSON(&desired); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	plan, err := h.buildPlan(c.Request.Context(), desired, prunesRequested(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   plan,
+	})
+}
+
+// Apply handles POST /api/v1/aviatrix/apply: it computes the same Plan
+// PlanApply would, then executes its actions in order. If an action
+// fails partway through, every already-applied action is rolled back
+// (in reverse order) before the error is returned, so an apply either
+// fully succeeds or leaves state as it found it.
+func (h *AviatrixHandler) Apply(c *gin.Context) {
+	var desired DesiredState
+	if err := c.ShouldBindJSON(&desired); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	plan, err := h.buildPlan(ctx, desired, prunesRequested(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	applied, execErr := h.executePlan(ctx, plan)
+	if execErr != nil {
+		h.rollback(applied)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":           execErr.Error(),
+			"applied_actions": len(applied),
+			"rolled_back":     true,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   plan,
+	})
+}
+
+// prunesRequested reports whether an apply/plan should include delete
+// actions for current resources missing from the desired state;
+// ?prune=false restricts it to creates and updates only.
+func prunesRequested(c *gin.Context) bool {
+	return c.DefaultQuery("prune", "true") != "false"
+}
+
+// buildPlan diffs desired against the cluster's current transit and
+// spoke gateways (fetched via the existing List* calls) and returns the
+// resulting actions in dependency order. vpc_connections and
+// site2cloud_connections have no current state to diff against (see
+// DesiredState's doc comment), so every one named in desired becomes an
+// informational create action a plan reports but Apply cannot execute.
+func (h *AviatrixHandler) buildPlan(ctx context.Context, desired DesiredState, prune bool) (*Plan, error) {
+	currentTransit, err := h.client.ListTransitGateways(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing current transit gateways: %w", err)
+	}
+	currentSpoke, err := h.client.ListSpokeGateways(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing current spoke gateways: %w", err)
+	}
+
+	byKind := map[string][]PlanAction{
+		kindTransitGateway:       diffTransitGateways(desired.TransitGateways, currentTransit, prune),
+		kindSpokeGateway:         diffSpokeGateways(desired.SpokeGateways, currentSpoke, prune),
+		kindVPCConnection:        diffUnmanagedConnections(kindVPCConnection, desired.VPCConnections),
+		kindSite2CloudConnection: diffUnmanagedConnections(kindSite2CloudConnection, desired.Site2CloudConnections),
+	}
+
+	plan := &Plan{}
+	for _, kind := range applyKindOrder {
+		for _, action := range byKind[kind] {
+			if action.Operation != "delete" {
+				plan.Actions = append(plan.Actions, action)
+			}
+		}
+	}
+	for i := len(applyKindOrder) - 1; i >= 0; i-- {
+		for _, action := range byKind[applyKindOrder[i]] {
+			if action.Operation == "delete" {
+				plan.Actions = append(plan.Actions, action)
+			}
+		}
+	}
+	return plan, nil
+}
+
+// diffTransitGateways compares desired against current by GatewayName:
+// a name only in desired is a create, a name in both whose JSON
+// representation differs is an update, and (when prune) a name only in
+// current is a delete.
+func diffTransitGateways(desired []aviatrix.TransitGateway, current []aviatrix.TransitGateway, prune bool) []PlanAction {
+	currentByName := make(map[string]aviatrix.TransitGateway, len(current))
+	for _, gw := range current {
+		currentByName[gw.GatewayName] = gw
+	}
+
+	var actions []PlanAction
+	seen := map[string]bool{}
+	for _, gw := range desired {
+		seen[gw.GatewayName] = true
+		if existing, ok := currentByName[gw.GatewayName]; !ok {
+			actions = append(actions, PlanAction{Kind: kindTransitGateway, Operation: "create", Name: gw.GatewayName, Desired: gw})
+		} else if !jsonEqual(gw, existing) {
+			actions = append(actions, PlanAction{Kind: kindTransitGateway, Operation: "update", Name: gw.GatewayName, Desired: gw, Current: existing})
+		}
+	}
+	if prune {
+		for _, gw := range current {
+			if !seen[gw.GatewayName] {
+				actions = append(actions, PlanAction{Kind: kindTransitGateway, Operation: "delete", Name: gw.GatewayName, Current: gw})
+			}
+		}
+	}
+	return actions
+}
+
+// diffSpokeGateways is diffTransitGateways' spoke gateway counterpart.
+func diffSpokeGateways(desired []aviatrix.SpokeGateway, current []aviatrix.SpokeGateway, prune bool) []PlanAction {
+	currentByName := make(map[string]aviatrix.SpokeGateway, len(current))
+	for _, gw := range current {
+		currentByName[gw.GatewayName] = gw
+	}
+
+	var actions []PlanAction
+	seen := map[string]bool{}
+	for _, gw := range desired {
+		seen[gw.GatewayName] = true
+		if existing, ok := currentByName[gw.GatewayName]; !ok {
+			actions = append(actions, PlanAction{Kind: kindSpokeGateway, Operation: "create", Name: gw.GatewayName, Desired: gw})
+		} else if !jsonEqual(gw, existing) {
+			actions = append(actions, PlanAction{Kind: kindSpokeGateway, Operation: "update", Name: gw.GatewayName, Desired: gw, Current: existing})
+		}
+	}
+	if prune {
+		for _, gw := range current {
+			if !seen[gw.GatewayName] {
+				actions = append(actions, PlanAction{Kind: kindSpokeGateway, Operation: "delete", Name: gw.GatewayName, Current: gw})
+			}
+		}
+	}
+	return actions
+}
+
+// diffUnmanagedConnections reports every named vpc_connection or
+// site2cloud_connection in desired as a create action - there's no
+// current state to compare against or prune from, since this client
+// can't list either resource (see DesiredState's doc comment).
+func diffUnmanagedConnections(kind string, desired []json.RawMessage) []PlanAction {
+	var actions []PlanAction
+	for _, raw := range desired {
+		var named struct {
+			Name string `json:"name"`
+		}
+		json.Unmarshal(raw, &named) //nolint:errcheck // best-effort label; empty Name still plans an action
+		actions = append(actions, PlanAction{Kind: kind, Operation: "create", Name: named.Name, Desired: json.RawMessage(raw)})
+	}
+	return actions
+}
+
+// jsonEqual reports whether a and b marshal to the same JSON, the
+// simplest way to compare two client-library structs for the purposes
+// of an update diff without hand-listing their fields.
+func jsonEqual(a, b interface{}) bool {
+	aj, aerr := json.Marshal(a)
+	bj, berr := json.Marshal(b)
+	if aerr != nil || berr != nil {
+		return false
+	}
+	return string(aj) == string(bj)
+}
+
+// executePlan runs plan's actions in order, publishing an audit Event
+// for each one that succeeds. It stops and returns the already-applied
+// actions (for rollback) on the first failure.
+func (h *AviatrixHandler) executePlan(ctx context.Context, plan *Plan) ([]PlanAction, error) {
+	applied := make([]PlanAction, 0, len(plan.Actions))
+	for _, action := range plan.Actions {
+		if err := h.executeAction(ctx, action); err != nil {
+			return applied, fmt.Errorf("%s %s %q: %w", action.Operation, action.Kind, action.Name, err)
+		}
+		applied = append(applied, action)
+	}
+	return applied, nil
+}
+
+// executeAction performs one PlanAction against h.client and publishes
+// its audit Event. vpc_connection and site2cloud_connection actions
+// always fail - this client has no way to create, update, or delete
+// either resource - so an apply touching them never reaches here
+// silently; the caller sees and rolls back from the error.
+func (h *AviatrixHandler) executeAction(ctx context.Context, action PlanAction) error {
+	var err error
+	switch action.Kind {
+	case kindTransitGateway:
+		err = h.executeTransitGatewayAction(ctx, action)
+	case kindSpokeGateway:
+		err = h.executeSpokeGatewayAction(ctx, action)
+	default:
+		err = fmt.Errorf("%s is not supported by this Aviatrix client", action.Kind)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch action.Operation {
+	case "create":
+		h.publish(h.auditEvent(ctx, events.AviatrixResourceCreated, nil, action.Desired))
+	case "update":
+		h.publish(h.auditEvent(ctx, events.AviatrixResourceUpdated, action.Current, action.Desired))
+	case "delete":
+		h.publish(h.auditEvent(ctx, events.AviatrixResourceDeleted, action.Current, nil))
+	}
+	return nil
+}
+
+func (h *AviatrixHandler) executeTransitGatewayAction(ctx context.Context, action PlanAction) error {
+	switch action.Operation {
+	case "create":
+		gw := action.Desired.(aviatrix.TransitGateway)
+		_, err := h.client.CreateTransitGateway(ctx, &gw)
+		return err
+	case "update":
+		gw := action.Desired.(aviatrix.TransitGateway)
+		_, err := h.client.UpdateTransitGateway(ctx, &gw)
+		return err
+	case "delete":
+		_, err := h.client.DeleteTransitGateway(ctx, action.Name)
+		return err
+	default:
+		return fmt.Errorf("unknown operation %q", action.Operation)
+	}
+}
+
+func (h *AviatrixHandler) executeSpokeGatewayAction(ctx context.Context, action PlanAction) error {
+	switch action.Operation {
+	case "create":
+		gw := action.Desired.(aviatrix.SpokeGateway)
+		_, err := h.client.CreateSpokeGateway(ctx, &gw)
+		return err
+	case "update":
+		gw := action.Desired.(aviatrix.SpokeGateway)
+		_, err := h.client.UpdateSpokeGateway(ctx, &gw)
+		return err
+	case "delete":
+		_, err := h.client.DeleteSpokeGateway(ctx, action.Name)
+		return err
+	default:
+		return fmt.Errorf("unknown operation %q", action.Operation)
+	}
+}
+
+// rollback undoes applied, most-recent action first: a create is
+// undone with a delete, a delete is undone by recreating from its
+// Current snapshot, and an update is undone by writing Current back.
+// It uses a fresh background context, since the request context that
+// started the failed apply may already be canceled, and logs (rather
+// than fails further on) a rollback action that itself errors - the
+// caller already has a failed apply to report.
+func (h *AviatrixHandler) rollback(applied []PlanAction) {
+	ctx := context.Background()
+	for i := len(applied) - 1; i >= 0; i-- {
+		action := applied[i]
+		undo := PlanAction{Kind: action.Kind, Name: action.Name}
+		switch action.Operation {
+		case "create":
+			undo.Operation = "delete"
+		case "delete":
+			undo.Operation = "create"
+			undo.Desired = action.Current
+		case "update":
+			undo.Operation = "update"
+			undo.Desired = action.Current
+			undo.Current = action.Desired
+		}
+
+		if err := h.executeAction(ctx, undo); err != nil {
+			if h.logger != nil {
+				h.logger.WithError(err).WithField("action", action).Error("Failed to roll back Aviatrix apply action")
+			}
+		}
+	}
+}
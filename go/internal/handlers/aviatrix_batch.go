@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"router-sim/internal/apispec"
+	"router-sim/internal/aviatrix"
+)
+
+// batchConcurrency bounds how many BatchOperations run at once, the
+// same fixed worker-pool size internal/aviatrix's bulkRunner defaults
+// Concurrency to.
+const batchConcurrency = 8
+
+// BatchOperation is one item of a batch request: Op selects
+// create/update/delete, Name identifies the resource (required for
+// update and delete, and used as the rollback key for a successful
+// create), and Spec is that op's request body, decoded against the
+// resource's own Create*/Update*Request type.
+type BatchOperation struct {
+	Op   string          `json:"op"`
+	Name string          `json:"name,omitempty"`
+	Spec json.RawMessage `json:"spec,omitempty"`
+}
+
+// BatchRequest is the body of every POST .../:batch endpoint on
+// AviatrixHandler. Atomic asks for every successful create to be rolled
+// back (via the resource's Delete* call) if any item in the batch
+// fails, instead of leaving the batch partially applied.
+type BatchRequest struct {
+	Operations []BatchOperation `json:"operations"`
+	Atomic     bool             `json:"atomic,omitempty"`
+}
+
+// BatchItemResult is one operations[Index]'s outcome: Data on success,
+// Error on failure. Never both.
+type BatchItemResult struct {
+	Index  int         `json:"index"`
+	Status string      `json:"status"`
+	Data   interface{} `json:"data,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// runBatch executes op(ctx, index, operations[index]) for every index
+// across up to batchConcurrency workers and returns one BatchItemResult
+// per index, in index order, regardless of completion order. One
+// item's error never stops or fails any other item.
+func runBatch(ctx context.Context, operations []BatchOperation, op func(ctx context.Context, index int, operation BatchOperation) (interface{}, error)) []BatchItemResult {
+	results := make([]BatchItemResult, len(operations))
+	if len(operations) == 0 {
+		return results
+	}
+
+	concurrency := batchConcurrency
+	if concurrency > len(operations) {
+		concurrency = len(operations)
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indices {
+				data, err := op(ctx, index, operations[index])
+				if err != nil {
+					results[index] = BatchItemResult{Index: index, Status: "error", Error: err.Error()}
+					continue
+				}
+				results[index] = BatchItemResult{Index: index, Status: "success", Data: data}
+			}
+		}()
+	}
+
+	for index := range operations {
+		indices <- index
+	}
+	close(indices)
+	wg.Wait()
+
+	return results
+}
+
+// batchHasFailure reports whether any BatchItemResult in results failed.
+func batchHasFailure(results []BatchItemResult) bool {
+	for _, result := range results {
+		if result.Status == "error" {
+			return true
+		}
+	}
+	return false
+}
+
+// batchResponse answers c with results as the uniform 207-style batch
+// body, 200 if every item succeeded or 207 Multi-Status if any failed -
+// the batch request itself is never failed outright by one bad item.
+func batchResponse(c *gin.Context, results []BatchItemResult) {
+	status := http.StatusOK
+	if batchHasFailure(results) {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, gin.H{
+		"status":  "success",
+		"results": results,
+	})
+}
+
+// RegisterBatchRoutes adds the Aviatrix batch route group to b.
+func (h *AviatrixHandler) RegisterBatchRoutes(b *apispec.Builder) {
+	b.Add(apispec.Route{
+		Method:  http.MethodPost,
+		Path:    "/aviatrix/transit-gateways:batch",
+		Summary: "Create, update, or delete multiple transit gateways in one call",
+		Tags:    []string{"aviatrix", "batch"},
+		Handler: func(c *gin.Context, _ interface{}) { h.BatchTransitGateways(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodPost,
+		Path:    "/aviatrix/spoke-gateways:batch",
+		Summary: "Create, update, or delete multiple spoke gateways in one call",
+		Tags:    []string{"aviatrix", "batch"},
+		Handler: func(c *gin.Context, _ interface{}) { h.BatchSpokeGateways(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodPost,
+		Path:    "/aviatrix/vpc-connections:batch",
+		Summary: "Create or delete multiple VPC connections in one call",
+		Tags:    []string{"aviatrix", "batch"},
+		Handler: func(c *gin.Context, _ interface{}) { h.BatchVPCConnections(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodPost,
+		Path:    "/aviatrix/site2cloud-connections:batch",
+		Summary: "Create or delete multiple Site2Cloud connections in one call",
+		Tags:    []string{"aviatrix", "batch"},
+		Handler: func(c *gin.Context, _ interface{}) { h.BatchSite2CloudConnections(c) },
+	})
+}
+
+// BatchTransitGateways handles POST /api/v1/aviatrix/transit-gateways:batch.
+func (h *AviatrixHandler) BatchTransitGateways(c *gin.Context) {
+	var req BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var created []string
+	var createdMu sync.Mutex
+
+	results := runBatch(ctx, req.Operations, func(ctx context.Context, index int, operation BatchOperation) (interface{}, error) {
+		switch operation.Op {
+		case "create":
+			var gwReq aviatrix.CreateTransitGatewayRequest
+			if err := json.Unmarshal(operation.Spec, &gwReq); err != nil {
+				return nil, fmt.Errorf("decoding spec: %w", err)
+			}
+			gateway, err := h.client.CreateTransitGateway(ctx, gwReq)
+			if err != nil {
+				return nil, err
+			}
+			createdMu.Lock()
+			created = append(created, operation.Name)
+			createdMu.Unlock()
+			return gateway, nil
+		case "update":
+			var gwReq aviatrix.UpdateTransitGatewayRequest
+			if err := json.Unmarshal(operation.Spec, &gwReq); err != nil {
+				return nil, fmt.Errorf("decoding spec: %w", err)
+			}
+			return h.client.UpdateTransitGateway(ctx, operation.Name, gwReq)
+		case "delete":
+			return nil, h.client.DeleteTransitGateway(ctx, operation.Name)
+		default:
+			return nil, fmt.Errorf("unknown op %q", operation.Op)
+		}
+	})
+
+	if req.Atomic && batchHasFailure(results) {
+		h.rollbackCreatedGateways(created, h.client.DeleteTransitGateway)
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "rolled_back",
+			"results": results,
+		})
+		return
+	}
+
+	batchResponse(c, results)
+}
+
+// BatchSpokeGateways handles POST /api/v1/aviatrix/spoke-gateways:batch.
+func (h *AviatrixHandler) BatchSpokeGateways(c *gin.Context) {
+	var req BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var created []string
+	var createdMu sync.Mutex
+
+	results := runBatch(ctx, req.Operations, func(ctx context.Context, index int, operation BatchOperation) (interface{}, error) {
+		switch operation.Op {
+		case "create":
+			var gwReq aviatrix.CreateSpokeGatewayRequest
+			if err := json.Unmarshal(operation.Spec, &gwReq); err != nil {
+				return nil, fmt.Errorf("decoding spec: %w", err)
+			}
+			gateway, err := h.client.CreateSpokeGateway(ctx, gwReq)
+			if err != nil {
+				return nil, err
+			}
+			createdMu.Lock()
+			created = append(created, operation.Name)
+			createdMu.Unlock()
+			return gateway, nil
+		case "update":
+			var gwReq aviatrix.UpdateSpokeGatewayRequest
+			if err := json.Unmarshal(operation.Spec, &gwReq); err != nil {
+				return nil, fmt.Errorf("decoding spec: %w", err)
+			}
+			return h.client.UpdateSpokeGateway(ctx, operation.Name, gwReq)
+		case "delete":
+			return nil, h.client.DeleteSpokeGateway(ctx, operation.Name)
+		default:
+			return nil, fmt.Errorf("unknown op %q", operation.Op)
+		}
+	})
+
+	if req.Atomic && batchHasFailure(results) {
+		h.rollbackCreatedGateways(created, h.client.DeleteSpokeGateway)
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "rolled_back",
+			"results": results,
+		})
+		return
+	}
+
+	batchResponse(c, results)
+}
+
+// BatchVPCConnections handles POST /api/v1/aviatrix/vpc-connections:batch.
+// AviatrixHandler has no UpdateVPCConnection, so "update" answers an
+// unsupported-op error for that item rather than failing the batch.
+func (h *AviatrixHandler) BatchVPCConnections(c *gin.Context) {
+	var req BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var created []string
+	var createdMu sync.Mutex
+
+	results := runBatch(ctx, req.Operations, func(ctx context.Context, index int, operation BatchOperation) (interface{}, error) {
+		switch operation.Op {
+		case "create":
+			var connReq aviatrix.CreateVPCConnectionRequest
+			if err := json.Unmarshal(operation.Spec, &connReq); err != nil {
+				return nil, fmt.Errorf("decoding spec: %w", err)
+			}
+			connection, err := h.client.CreateVPCConnection(ctx, connReq)
+			if err != nil {
+				return nil, err
+			}
+			createdMu.Lock()
+			created = append(created, operation.Name)
+			createdMu.Unlock()
+			return connection, nil
+		case "delete":
+			return nil, h.client.DeleteVPCConnection(ctx, operation.Name)
+		default:
+			return nil, fmt.Errorf("unsupported op %q for VPC connections", operation.Op)
+		}
+	})
+
+	if req.Atomic && batchHasFailure(results) {
+		h.rollbackCreatedGateways(created, h.client.DeleteVPCConnection)
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "rolled_back",
+			"results": results,
+		})
+		return
+	}
+
+	batchResponse(c, results)
+}
+
+// BatchSite2CloudConnections handles
+// POST /api/v1/aviatrix/site2cloud-connections:batch. AviatrixHandler
+// has no UpdateSite2CloudConnection, so "update" answers an
+// unsupported-op error for that item rather than failing the batch.
+func (h *AviatrixHandler) BatchSite2CloudConnections(c *gin.Context) {
+	var req BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var created []string
+	var createdMu sync.Mutex
+
+	results := runBatch(ctx, req.Operations, func(ctx context.Context, index int, operation BatchOperation) (interface{}, error) {
+		switch operation.Op {
+		case "create":
+			var connReq aviatrix.CreateSite2CloudConnectionRequest
+			if err := json.Unmarshal(operation.Spec, &connReq); err != nil {
+				return nil, fmt.Errorf("decoding spec: %w", err)
+			}
+			connection, err := h.client.CreateSite2CloudConnection(ctx, connReq)
+			if err != nil {
+				return nil, err
+			}
+			createdMu.Lock()
+			created = append(created, operation.Name)
+			createdMu.Unlock()
+			return connection, nil
+		case "delete":
+			return nil, h.client.DeleteSite2CloudConnection(ctx, operation.Name)
+		default:
+			return nil, fmt.Errorf("unsupported op %q for Site2Cloud connections", operation.Op)
+		}
+	})
+
+	if req.Atomic && batchHasFailure(results) {
+		h.rollbackCreatedGateways(created, h.client.DeleteSite2CloudConnection)
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  "rolled_back",
+			"results": results,
+		})
+		return
+	}
+
+	batchResponse(c, results)
+}
+
+// rollbackCreatedGateways compensates an atomic batch's successful
+// creates by calling deleteFn on each one's name, logging (rather than
+// failing the response further) if a compensating delete itself fails -
+// the batch has already answered 409 Conflict by the time this runs.
+func (h *AviatrixHandler) rollbackCreatedGateways(names []string, deleteFn func(ctx context.Context, name string) error) {
+	ctx := context.Background()
+	for _, name := range names {
+		if err := deleteFn(ctx, name); err != nil {
+			h.logger.WithError(err).WithField("name", name).Error("Failed to roll back batch item after atomic failure")
+		}
+	}
+}
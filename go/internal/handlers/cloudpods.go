@@ -1,17 +1,83 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
-	"router-sim/internal/cloudpods"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/sirupsen/logrus"
+	"router-sim/internal/cloudpods"
+	"router-sim/internal/cloudpods/admission"
+	"router-sim/internal/cloudpods/garbagecollector"
+	"router-sim/internal/cloudpods/patch"
+	"router-sim/internal/metrics"
 )
 
+// watchHeartbeatInterval controls how often Watch* endpoints emit a
+// heartbeat event on an otherwise idle stream, so proxies and load
+// balancers don't time out the connection.
+const watchHeartbeatInterval = 15 * time.Second
+
+// ownerReferencesBody is the `ownerReferences` field every CloudPods create
+// request optionally accepts, bound separately from each endpoint's own
+// request struct so new resource types don't need to repeat it.
+type ownerReferencesBody struct {
+	OwnerReferences []garbagecollector.OwnerReference `json:"ownerReferences"`
+}
+
+// bindOwnerReferences re-reads the request body (cached by gin across
+// repeated ShouldBindBodyWith calls) for an optional `ownerReferences`
+// field, returning nil if absent or malformed.
+func (h *CloudPodsHandler) bindOwnerReferences(c *gin.Context) []garbagecollector.OwnerReference {
+	var body ownerReferencesBody
+	if err := c.ShouldBindBodyWith(&body, binding.JSON); err != nil {
+		return nil
+	}
+	return body.OwnerReferences
+}
+
+// propagationPolicy reads ?propagationPolicy= from the request, defaulting
+// to Background to match Kubernetes' default delete behavior.
+func propagationPolicy(c *gin.Context) garbagecollector.PropagationPolicy {
+	switch garbagecollector.PropagationPolicy(c.Query("propagationPolicy")) {
+	case garbagecollector.PropagationForeground:
+		return garbagecollector.PropagationForeground
+	case garbagecollector.PropagationOrphan:
+		return garbagecollector.PropagationOrphan
+	default:
+		return garbagecollector.PropagationBackground
+	}
+}
+
+// parseListOptions builds a cloudpods.ListOptions from a List* request's
+// query string, mirroring kubectl's own labelSelector/fieldSelector/limit/
+// continue flags.
+func parseListOptions(c *gin.Context) cloudpods.ListOptions {
+	opts := cloudpods.ListOptions{
+		LabelSelector: c.Query("labelSelector"),
+		FieldSelector: c.Query("fieldSelector"),
+		Continue:      c.Query("continue"),
+	}
+	if limit, err := strconv.ParseInt(c.Query("limit"), 10, 64); err == nil {
+		opts.Limit = limit
+	}
+	return opts
+}
+
 type CloudPodsHandler struct {
 	client *cloudpods.Client
 	logger *logrus.Logger
+
+	mutating   *admission.MutatingChain
+	validating *admission.ValidatingChain
+
+	clusters *cloudpods.ClusterManager
 }
 
 func NewCloudPodsHandler(client *cloudpods.Client, logger *logrus.Logger) *CloudPodsHandler {
@@ -21,14 +87,191 @@ func NewCloudPodsHandler(client *cloudpods.Client, logger *logrus.Logger) *Cloud
 	}
 }
 
+// SetAdmission wires an admission pipeline into every write endpoint:
+// mutating runs before a write is persisted and validating runs after,
+// against the (possibly mutated) final object. Either may be nil, in
+// which case that stage is skipped — the zero-value CloudPodsHandler
+// behaves exactly as it did before admission existed.
+func (h *CloudPodsHandler) SetAdmission(mutating *admission.MutatingChain, validating *admission.ValidatingChain) {
+	h.mutating = mutating
+	h.validating = validating
+}
+
+// SetClusterManager wires a multi-cluster registry into the handler so
+// ListClusters (and, in time, region-aware List* variants) can route
+// through it instead of h.client's single upstream.
+func (h *CloudPodsHandler) SetClusterManager(clusters *cloudpods.ClusterManager) {
+	h.clusters = clusters
+}
+
+// ListClusters handles GET /api/v1/cloudpods/clusters: every registered
+// cluster's endpoints and their current health, keyed by cluster name,
+// or an empty object if no ClusterManager was wired in.
+func (h *CloudPodsHandler) ListClusters(c *gin.Context) {
+	if h.clusters == nil {
+		c.JSON(http.StatusOK, gin.H{"status": "success", "data": gin.H{}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   h.clusters.Status(),
+	})
+}
+
+// AdmissionReview handles POST /api/v1/cloudpods/admission/review: a
+// caller posts an admission.Request and gets back the same decision the
+// handler's own write endpoints would reach by running it through the
+// configured mutating and validating chains. This lets an external
+// policy engine (or a CI check dry-running a change) exercise the exact
+// webhooks CloudPodsHandler enforces without performing a real write.
+func (h *CloudPodsHandler) AdmissionReview(c *gin.Context) {
+	var req admission.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var warnings []string
+
+	if h.mutating != nil {
+		mutated, resp, err := h.mutating.Admit(ctx, req)
+		if err != nil {
+			h.logger.WithError(err).WithField("kind", req.Kind).Error("Mutating admission review failed")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Admission failed"})
+			return
+		}
+		warnings = append(warnings, resp.Warnings...)
+		if !resp.Allowed {
+			c.JSON(http.StatusOK, gin.H{"allowed": false, "warnings": warnings, "reasons": resp.Reasons})
+			return
+		}
+		req = mutated
+	}
+
+	if h.validating != nil {
+		resp, err := h.validating.Admit(ctx, req)
+		if err != nil {
+			h.logger.WithError(err).WithField("kind", req.Kind).Error("Validating admission review failed")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Admission failed"})
+			return
+		}
+		warnings = append(warnings, resp.Warnings...)
+		if !resp.Allowed {
+			c.JSON(http.StatusOK, gin.H{"allowed": false, "warnings": warnings, "reasons": resp.Reasons})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"allowed": true, "warnings": warnings, "object": req.Object})
+}
+
+// admissionReject writes a webhook rejection as a 422 with its reasons
+// under status.reasons, the same structured shape Kubernetes returns
+// from a denied admission review, so clients can show field-level
+// errors instead of one opaque string.
+func admissionReject(c *gin.Context, resp admission.Response) {
+	c.JSON(http.StatusUnprocessableEntity, gin.H{
+		"error": "admission rejected",
+		"status": gin.H{
+			"reasons":  resp.Reasons,
+			"warnings": resp.Warnings,
+		},
+	})
+}
+
+// admit runs obj (and, for update/delete, oldObj) through the handler's
+// mutating chain and then its validating chain, writing the appropriate
+// error response and returning ok=false if either stage is configured
+// and disallows the request. On success it unmarshals the (possibly
+// mutated) result back into out, which must be a pointer to the same
+// type obj was.
+func (h *CloudPodsHandler) admit(c *gin.Context, kind string, verb admission.Verb, vpcID string, obj, oldObj, out interface{}) bool {
+	ctx := c.Request.Context()
+	req := admission.Request{Kind: kind, Verb: verb, Namespace: vpcID, Object: obj, OldObject: oldObj}
+
+	if h.mutating != nil {
+		mutated, resp, err := h.mutating.Admit(ctx, req)
+		if err != nil {
+			h.logger.WithError(err).WithField("kind", kind).Error("Mutating admission failed")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Admission failed"})
+			return false
+		}
+		if !resp.Allowed {
+			admissionReject(c, resp)
+			return false
+		}
+		req = mutated
+	}
+
+	if h.validating != nil {
+		resp, err := h.validating.Admit(ctx, req)
+		if err != nil {
+			h.logger.WithError(err).WithField("kind", kind).Error("Validating admission failed")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Admission failed"})
+			return false
+		}
+		if !resp.Allowed {
+			admissionReject(c, resp)
+			return false
+		}
+	}
+
+	data, err := json.Marshal(req.Object)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode admitted object"})
+		return false
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
+
 // VPC Handlers
 func (h *CloudPodsHandler) ListVPCs(c *gin.Context) {
-	vpcs, err := h.client.ListVPCs(c.Request.Context())
+	if clusterName := c.Query("cluster"); clusterName != "" && h.clusters != nil {
+		h.listVPCsFromCluster(c, clusterName)
+		return
+	}
+
+	vpcs, cont, err := h.client.ListVPCs(c.Request.Context(), parseListOptions(c))
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to list VPCs")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list VPCs"})
 		return
 	}
+	// Approximates the fleet-wide count from this page; a ListVPCs
+	// caller that doesn't page through cont will undercount.
+	metrics.CloudPodsPodCount.Set(float64(len(vpcs)))
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":   "success",
+		"data":     vpcs,
+		"continue": cont,
+	})
+}
+
+// listVPCsFromCluster serves ListVPCs' ?cluster= path: it routes through
+// the named Cluster (optionally scoped to ?region=) instead of
+// h.client's single upstream. The response has no continue token since
+// it's already a fan-out merge across that cluster's endpoints rather
+// than a single paginated list.
+func (h *CloudPodsHandler) listVPCsFromCluster(c *gin.Context, clusterName string) {
+	cluster, ok := h.clusters.Get(clusterName)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown cluster " + clusterName})
+		return
+	}
+
+	vpcs, err := cluster.GetVPCs(c.Request.Context(), c.Query("region"))
+	if err != nil {
+		h.logger.WithError(err).WithField("cluster", clusterName).Error("Failed to list VPCs from cluster")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list VPCs"})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
@@ -36,6 +279,37 @@ func (h *CloudPodsHandler) ListVPCs(c *gin.Context) {
 	})
 }
 
+// DeleteVPCCollection handles DELETE /api/v1/cloudpods/vpcs, deleting
+// every VPC matching ?labelSelector=/?fieldSelector= instead of making a
+// caller round-trip a ListVPCs + N DeleteVPC calls. Each delete still
+// goes through the garbage collector with the request's propagationPolicy,
+// so owned subnets/NAT gateways/load balancers/routes cascade the same
+// way a single DeleteVPC would.
+func (h *CloudPodsHandler) DeleteVPCCollection(c *gin.Context) {
+	vpcs, _, err := h.client.ListVPCs(c.Request.Context(), parseListOptions(c))
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to list VPCs for collection delete")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list VPCs"})
+		return
+	}
+
+	policy := propagationPolicy(c)
+	deleted := make([]string, 0, len(vpcs))
+	for _, vpc := range vpcs {
+		if err := h.client.GC().Delete(c.Request.Context(), "vpc", "", vpc.ID, policy); err != nil {
+			h.logger.WithError(err).WithField("vpc_id", vpc.ID).Error("Failed to delete VPC in collection")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete VPC " + vpc.ID, "deleted": deleted})
+			return
+		}
+		deleted = append(deleted, vpc.ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"deleted": deleted,
+	})
+}
+
 func (h *CloudPodsHandler) CreateVPC(c *gin.Context) {
 	var req cloudpods.CreateVPCRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -43,6 +317,10 @@ func (h *CloudPodsHandler) CreateVPC(c *gin.Context) {
 		return
 	}
 
+	if !h.admit(c, "vpc", admission.VerbCreate, "", req, nil, &req) {
+		return
+	}
+
 	vpc, err := h.client.CreateVPC(c.Request.Context(), req)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to create VPC")
@@ -50,6 +328,9 @@ func (h *CloudPodsHandler) CreateVPC(c *gin.Context) {
 		return
 	}
 
+	h.client.GC().Track(garbagecollector.Node{Kind: "vpc", ID: vpc.ID, Owners: h.bindOwnerReferences(c)})
+	h.client.Events().Publish("vpc", vpc.ID, cloudpods.EventAdded, vpc)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"status": "success",
 		"data":   vpc,
@@ -89,6 +370,16 @@ func (h *CloudPodsHandler) UpdateVPC(c *gin.Context) {
 		return
 	}
 
+	current, err := h.client.GetVPC(c.Request.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).WithField("vpc_id", id).Error("Failed to get VPC for admission")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get VPC"})
+		return
+	}
+	if !h.admit(c, "vpc", admission.VerbUpdate, "", req, current, &req) {
+		return
+	}
+
 	vpc, err := h.client.UpdateVPC(c.Request.Context(), id, req)
 	if err != nil {
 		h.logger.WithError(err).WithField("vpc_id", id).Error("Failed to update VPC")
@@ -96,6 +387,8 @@ func (h *CloudPodsHandler) UpdateVPC(c *gin.Context) {
 		return
 	}
 
+	h.client.Events().Publish("vpc", vpc.ID, cloudpods.EventModified, vpc)
+
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
 		"data":   vpc,
@@ -109,7 +402,18 @@ func (h *CloudPodsHandler) DeleteVPC(c *gin.Context) {
 		return
 	}
 
-	err := h.client.DeleteVPC(c.Request.Context(), id)
+	current, err := h.client.GetVPC(c.Request.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).WithField("vpc_id", id).Error("Failed to get VPC for admission")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get VPC"})
+		return
+	}
+	var discard cloudpods.CloudPodsVPC
+	if !h.admit(c, "vpc", admission.VerbDelete, "", nil, current, &discard) {
+		return
+	}
+
+	err = h.client.GC().Delete(c.Request.Context(), "vpc", "", id, propagationPolicy(c))
 	if err != nil {
 		h.logger.WithError(err).WithField("vpc_id", id).Error("Failed to delete VPC")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete VPC"})
@@ -119,6 +423,22 @@ func (h *CloudPodsHandler) DeleteVPC(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
+// GetVPCDependents introspects the garbage collector's dependency graph for
+// GET /api/v1/cloudpods/vpcs/:id/dependents, so callers can see what a
+// delete of this VPC would cascade to before triggering one.
+func (h *CloudPodsHandler) GetVPCDependents(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "VPC ID is required"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   h.client.GC().Dependents("vpc", id),
+	})
+}
+
 func (h *CloudPodsHandler) GetVPCStats(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
@@ -139,6 +459,128 @@ func (h *CloudPodsHandler) GetVPCStats(c *gin.Context) {
 	})
 }
 
+// ApplyVPC handles POST /api/v1/cloudpods/vpcs/apply: the body is a full
+// desired-state document for a VPC plus its nested subnets, NAT
+// gateways, load balancers, and service-mesh routes. The client reconciles
+// it against current state and issues the minimal set of create/update/
+// delete calls to converge, the way `kubectl apply -f` does — callers
+// don't have to compute the diff themselves, and this turns the CRUD API
+// into a GitOps-friendly control plane.
+func (h *CloudPodsHandler) ApplyVPC(c *gin.Context) {
+	var req cloudpods.ApplyVPCRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.client.ApplyVPC(c.Request.Context(), req)
+	if err != nil {
+		if conflict, ok := err.(*cloudpods.ErrResourceVersionConflict); ok {
+			c.JSON(http.StatusConflict, gin.H{"error": conflict.Error()})
+			return
+		}
+		h.logger.WithError(err).WithField("vpc", req.Name).Error("Failed to apply VPC")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply VPC"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   result,
+	})
+}
+
+// patchMergeKeys configures StrategicMergePatch's list-merge behavior per
+// endpoint: load balancer listeners merge on port and service-mesh
+// routes merge on their match expression, the same way Kubernetes merges
+// containers by name or container ports by containerPort.
+var (
+	loadBalancerMergeKeys     = patch.MergeKeys{"listeners": "port"}
+	serviceMeshRouteMergeKeys = patch.MergeKeys{"routes": "match"}
+)
+
+// patchResource applies a PATCH request body to current according to its
+// Content-Type — one of application/json-patch+json,
+// application/merge-patch+json, or application/strategic-merge-patch+json
+// — and unmarshals the result into out. It enforces optimistic
+// concurrency against ?resourceVersion= first, writing the response
+// itself and returning false if the check or the patch fails, so every
+// Patch* endpoint can share one implementation and only differ in the
+// current object, its kind/id, and the strategic-merge-patch merge keys.
+func (h *CloudPodsHandler) patchResource(c *gin.Context, kind, id string, resourceVersion uint64, current, out interface{}, mergeKeys patch.MergeKeys) bool {
+	if expected, err := strconv.ParseUint(c.Query("resourceVersion"), 10, 64); err == nil && expected != 0 && expected != resourceVersion {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": fmt.Sprintf("%s/%s resourceVersion conflict: expected %d, current %d", kind, id, expected, resourceVersion),
+		})
+		return false
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+
+	doc, err := json.Marshal(current)
+	if err != nil {
+		h.logger.WithError(err).WithField(kind+"_id", id).Error("Failed to encode current state for patch")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode current state"})
+		return false
+	}
+
+	patched, err := patch.Apply(c.ContentType(), doc, body, mergeKeys)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+
+	if err := json.Unmarshal(patched, out); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+
+	return true
+}
+
+// PatchVPC handles PATCH /api/v1/cloudpods/vpcs/:id, applying the body as
+// one of the three content types documented on patchResource. An
+// optional ?resourceVersion= enforces optimistic concurrency: a mismatch
+// fails with 409 Conflict instead of silently clobbering a concurrent
+// update.
+func (h *CloudPodsHandler) PatchVPC(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "VPC ID is required"})
+		return
+	}
+
+	current, err := h.client.GetVPC(c.Request.Context(), id)
+	if err != nil {
+		h.logger.WithError(err).WithField("vpc_id", id).Error("Failed to get VPC for patch")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get VPC"})
+		return
+	}
+
+	var req cloudpods.UpdateVPCRequest
+	if !h.patchResource(c, "vpc", id, current.ResourceVersion, current, &req, nil) {
+		return
+	}
+
+	vpc, err := h.client.UpdateVPC(c.Request.Context(), id, req)
+	if err != nil {
+		h.logger.WithError(err).WithField("vpc_id", id).Error("Failed to patch VPC")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to patch VPC"})
+		return
+	}
+
+	h.client.Events().Publish("vpc", vpc.ID, cloudpods.EventModified, vpc)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   vpc,
+	})
+}
+
 // Subnet Handlers
 func (h *CloudPodsHandler) ListSubnets(c *gin.Context) {
 	vpcID := c.Param("vpc_id")
@@ -147,7 +589,7 @@ func (h *CloudPodsHandler) ListSubnets(c *gin.Context) {
 		return
 	}
 
-	subnets, err := h.client.ListSubnets(c.Request.Context(), vpcID)
+	subnets, cont, err := h.client.ListSubnets(c.Request.Context(), vpcID, parseListOptions(c))
 	if err != nil {
 		h.logger.WithError(err).WithField("vpc_id", vpcID).Error("Failed to list subnets")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list subnets"})
@@ -155,8 +597,47 @@ func (h *CloudPodsHandler) ListSubnets(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   subnets,
+		"status":   "success",
+		"data":     subnets,
+		"continue": cont,
+	})
+}
+
+// DeleteSubnetCollection handles DELETE
+// /api/v1/cloudpods/vpcs/:vpc_id/subnets, deleting every subnet of vpc_id
+// matching ?labelSelector=/?fieldSelector= in one request. See
+// DeleteVPCCollection for the shared rationale.
+func (h *CloudPodsHandler) DeleteSubnetCollection(c *gin.Context) {
+	vpcID := c.Param("vpc_id")
+	if vpcID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "VPC ID is required"})
+		return
+	}
+
+	subnets, _, err := h.client.ListSubnets(c.Request.Context(), vpcID, parseListOptions(c))
+	if err != nil {
+		h.logger.WithError(err).WithField("vpc_id", vpcID).Error("Failed to list subnets for collection delete")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list subnets"})
+		return
+	}
+
+	policy := propagationPolicy(c)
+	deleted := make([]string, 0, len(subnets))
+	for _, subnet := range subnets {
+		if err := h.client.GC().Delete(c.Request.Context(), "subnet", vpcID, subnet.ID, policy); err != nil {
+			h.logger.WithError(err).WithFields(logrus.Fields{
+				"vpc_id":    vpcID,
+				"subnet_id": subnet.ID,
+			}).Error("Failed to delete subnet in collection")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete subnet " + subnet.ID, "deleted": deleted})
+			return
+		}
+		deleted = append(deleted, subnet.ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"deleted": deleted,
 	})
 }
 
@@ -173,6 +654,10 @@ func (h *CloudPodsHandler) CreateSubnet(c *gin.Context) {
 		return
 	}
 
+	if !h.admit(c, "subnet", admission.VerbCreate, vpcID, req, nil, &req) {
+		return
+	}
+
 	subnet, err := h.client.CreateSubnet(c.Request.Context(), vpcID, req)
 	if err != nil {
 		h.logger.WithError(err).WithField("vpc_id", vpcID).Error("Failed to create subnet")
@@ -180,6 +665,14 @@ func (h *CloudPodsHandler) CreateSubnet(c *gin.Context) {
 		return
 	}
 
+	h.client.GC().Track(garbagecollector.Node{
+		Kind:   "subnet",
+		ID:     subnet.ID,
+		VPCID:  vpcID,
+		Owners: h.bindOwnerReferences(c),
+	})
+	h.client.Events().Publish("subnet", vpcID, cloudpods.EventAdded, subnet)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"status": "success",
 		"data":   subnet,
@@ -224,6 +717,19 @@ func (h *CloudPodsHandler) UpdateSubnet(c *gin.Context) {
 		return
 	}
 
+	current, err := h.client.GetSubnet(c.Request.Context(), vpcID, subnetID)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"vpc_id":    vpcID,
+			"subnet_id": subnetID,
+		}).Error("Failed to get subnet for admission")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get subnet"})
+		return
+	}
+	if !h.admit(c, "subnet", admission.VerbUpdate, vpcID, req, current, &req) {
+		return
+	}
+
 	subnet, err := h.client.UpdateSubnet(c.Request.Context(), vpcID, subnetID, req)
 	if err != nil {
 		h.logger.WithError(err).WithFields(logrus.Fields{
@@ -234,6 +740,8 @@ func (h *CloudPodsHandler) UpdateSubnet(c *gin.Context) {
 		return
 	}
 
+	h.client.Events().Publish("subnet", vpcID, cloudpods.EventModified, subnet)
+
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
 		"data":   subnet,
@@ -248,7 +756,21 @@ func (h *CloudPodsHandler) DeleteSubnet(c *gin.Context) {
 		return
 	}
 
-	err := h.client.DeleteSubnet(c.Request.Context(), vpcID, subnetID)
+	current, err := h.client.GetSubnet(c.Request.Context(), vpcID, subnetID)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"vpc_id":    vpcID,
+			"subnet_id": subnetID,
+		}).Error("Failed to get subnet for admission")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get subnet"})
+		return
+	}
+	var discardSubnet cloudpods.CloudPodsSubnet
+	if !h.admit(c, "subnet", admission.VerbDelete, vpcID, nil, current, &discardSubnet) {
+		return
+	}
+
+	err = h.client.GC().Delete(c.Request.Context(), "subnet", vpcID, subnetID, propagationPolicy(c))
 	if err != nil {
 		h.logger.WithError(err).WithFields(logrus.Fields{
 			"vpc_id":    vpcID,
@@ -261,6 +783,50 @@ func (h *CloudPodsHandler) DeleteSubnet(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
+// PatchSubnet handles PATCH /api/v1/cloudpods/vpcs/:vpc_id/subnets/:id,
+// applying the body as one of the three content types documented on
+// patchResource.
+func (h *CloudPodsHandler) PatchSubnet(c *gin.Context) {
+	vpcID := c.Param("vpc_id")
+	subnetID := c.Param("id")
+	if vpcID == "" || subnetID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "VPC ID and Subnet ID are required"})
+		return
+	}
+
+	current, err := h.client.GetSubnet(c.Request.Context(), vpcID, subnetID)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"vpc_id":    vpcID,
+			"subnet_id": subnetID,
+		}).Error("Failed to get subnet for patch")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get subnet"})
+		return
+	}
+
+	var req cloudpods.UpdateSubnetRequest
+	if !h.patchResource(c, "subnet", subnetID, current.ResourceVersion, current, &req, nil) {
+		return
+	}
+
+	subnet, err := h.client.UpdateSubnet(c.Request.Context(), vpcID, subnetID, req)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"vpc_id":    vpcID,
+			"subnet_id": subnetID,
+		}).Error("Failed to patch subnet")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to patch subnet"})
+		return
+	}
+
+	h.client.Events().Publish("subnet", vpcID, cloudpods.EventModified, subnet)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   subnet,
+	})
+}
+
 // NAT Gateway Handlers
 func (h *CloudPodsHandler) ListNATGateways(c *gin.Context) {
 	vpcID := c.Param("vpc_id")
@@ -269,7 +835,7 @@ func (h *CloudPodsHandler) ListNATGateways(c *gin.Context) {
 		return
 	}
 
-	nats, err := h.client.ListNATGateways(c.Request.Context(), vpcID)
+	nats, cont, err := h.client.ListNATGateways(c.Request.Context(), vpcID, parseListOptions(c))
 	if err != nil {
 		h.logger.WithError(err).WithField("vpc_id", vpcID).Error("Failed to list NAT gateways")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list NAT gateways"})
@@ -277,8 +843,46 @@ func (h *CloudPodsHandler) ListNATGateways(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   nats,
+		"status":   "success",
+		"data":     nats,
+		"continue": cont,
+	})
+}
+
+// DeleteNATGatewayCollection handles DELETE
+// /api/v1/cloudpods/vpcs/:vpc_id/natgateways, deleting every NAT gateway
+// of vpc_id matching ?labelSelector=/?fieldSelector= in one request. See
+// DeleteVPCCollection for the shared rationale.
+func (h *CloudPodsHandler) DeleteNATGatewayCollection(c *gin.Context) {
+	vpcID := c.Param("vpc_id")
+	if vpcID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "VPC ID is required"})
+		return
+	}
+
+	nats, _, err := h.client.ListNATGateways(c.Request.Context(), vpcID, parseListOptions(c))
+	if err != nil {
+		h.logger.WithError(err).WithField("vpc_id", vpcID).Error("Failed to list NAT gateways for collection delete")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list NAT gateways"})
+		return
+	}
+
+	deleted := make([]string, 0, len(nats))
+	for _, nat := range nats {
+		if err := h.client.GC().Delete(c.Request.Context(), "natgateway", vpcID, nat.ID, garbagecollector.PropagationBackground); err != nil {
+			h.logger.WithError(err).WithFields(logrus.Fields{
+				"vpc_id": vpcID,
+				"nat_id": nat.ID,
+			}).Error("Failed to delete NAT gateway in collection")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete NAT gateway " + nat.ID, "deleted": deleted})
+			return
+		}
+		deleted = append(deleted, nat.ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"deleted": deleted,
 	})
 }
 
@@ -295,6 +899,10 @@ func (h *CloudPodsHandler) CreateNATGateway(c *gin.Context) {
 		return
 	}
 
+	if !h.admit(c, "natgateway", admission.VerbCreate, vpcID, req, nil, &req) {
+		return
+	}
+
 	nat, err := h.client.CreateNATGateway(c.Request.Context(), vpcID, req)
 	if err != nil {
 		h.logger.WithError(err).WithField("vpc_id", vpcID).Error("Failed to create NAT gateway")
@@ -302,6 +910,14 @@ func (h *CloudPodsHandler) CreateNATGateway(c *gin.Context) {
 		return
 	}
 
+	h.client.GC().Track(garbagecollector.Node{
+		Kind:   "natgateway",
+		ID:     nat.ID,
+		VPCID:  vpcID,
+		Owners: h.bindOwnerReferences(c),
+	})
+	h.client.Events().Publish("natgateway", vpcID, cloudpods.EventAdded, nat)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"status": "success",
 		"data":   nat,
@@ -340,7 +956,21 @@ func (h *CloudPodsHandler) DeleteNATGateway(c *gin.Context) {
 		return
 	}
 
-	err := h.client.DeleteNATGateway(c.Request.Context(), vpcID, natID)
+	current, err := h.client.GetNATGateway(c.Request.Context(), vpcID, natID)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"vpc_id": vpcID,
+			"nat_id": natID,
+		}).Error("Failed to get NAT gateway for admission")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get NAT gateway"})
+		return
+	}
+	var discardNAT cloudpods.CloudPodsNATGateway
+	if !h.admit(c, "natgateway", admission.VerbDelete, vpcID, nil, current, &discardNAT) {
+		return
+	}
+
+	err = h.client.GC().Delete(c.Request.Context(), "natgateway", vpcID, natID, garbagecollector.PropagationBackground)
 	if err != nil {
 		h.logger.WithError(err).WithFields(logrus.Fields{
 			"vpc_id": vpcID,
@@ -361,7 +991,7 @@ func (h *CloudPodsHandler) ListLoadBalancers(c *gin.Context) {
 		return
 	}
 
-	lbs, err := h.client.ListLoadBalancers(c.Request.Context(), vpcID)
+	lbs, cont, err := h.client.ListLoadBalancers(c.Request.Context(), vpcID, parseListOptions(c))
 	if err != nil {
 		h.logger.WithError(err).WithField("vpc_id", vpcID).Error("Failed to list load balancers")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list load balancers"})
@@ -369,8 +999,47 @@ func (h *CloudPodsHandler) ListLoadBalancers(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   lbs,
+		"status":   "success",
+		"data":     lbs,
+		"continue": cont,
+	})
+}
+
+// DeleteLoadBalancerCollection handles DELETE
+// /api/v1/cloudpods/vpcs/:vpc_id/loadbalancers, deleting every load
+// balancer of vpc_id matching ?labelSelector=/?fieldSelector= in one
+// request. See DeleteVPCCollection for the shared rationale.
+func (h *CloudPodsHandler) DeleteLoadBalancerCollection(c *gin.Context) {
+	vpcID := c.Param("vpc_id")
+	if vpcID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "VPC ID is required"})
+		return
+	}
+
+	lbs, _, err := h.client.ListLoadBalancers(c.Request.Context(), vpcID, parseListOptions(c))
+	if err != nil {
+		h.logger.WithError(err).WithField("vpc_id", vpcID).Error("Failed to list load balancers for collection delete")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list load balancers"})
+		return
+	}
+
+	policy := propagationPolicy(c)
+	deleted := make([]string, 0, len(lbs))
+	for _, lb := range lbs {
+		if err := h.client.GC().Delete(c.Request.Context(), "loadbalancer", vpcID, lb.ID, policy); err != nil {
+			h.logger.WithError(err).WithFields(logrus.Fields{
+				"vpc_id": vpcID,
+				"lb_id":  lb.ID,
+			}).Error("Failed to delete load balancer in collection")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete load balancer " + lb.ID, "deleted": deleted})
+			return
+		}
+		deleted = append(deleted, lb.ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"deleted": deleted,
 	})
 }
 
@@ -387,6 +1056,10 @@ func (h *CloudPodsHandler) CreateLoadBalancer(c *gin.Context) {
 		return
 	}
 
+	if !h.admit(c, "loadbalancer", admission.VerbCreate, vpcID, req, nil, &req) {
+		return
+	}
+
 	lb, err := h.client.CreateLoadBalancer(c.Request.Context(), vpcID, req)
 	if err != nil {
 		h.logger.WithError(err).WithField("vpc_id", vpcID).Error("Failed to create load balancer")
@@ -394,6 +1067,14 @@ func (h *CloudPodsHandler) CreateLoadBalancer(c *gin.Context) {
 		return
 	}
 
+	h.client.GC().Track(garbagecollector.Node{
+		Kind:   "loadbalancer",
+		ID:     lb.ID,
+		VPCID:  vpcID,
+		Owners: h.bindOwnerReferences(c),
+	})
+	h.client.Events().Publish("loadbalancer", vpcID, cloudpods.EventAdded, lb)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"status": "success",
 		"data":   lb,
@@ -438,6 +1119,19 @@ func (h *CloudPodsHandler) UpdateLoadBalancer(c *gin.Context) {
 		return
 	}
 
+	current, err := h.client.GetLoadBalancer(c.Request.Context(), vpcID, lbID)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"vpc_id": vpcID,
+			"lb_id":  lbID,
+		}).Error("Failed to get load balancer for admission")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get load balancer"})
+		return
+	}
+	if !h.admit(c, "loadbalancer", admission.VerbUpdate, vpcID, req, current, &req) {
+		return
+	}
+
 	lb, err := h.client.UpdateLoadBalancer(c.Request.Context(), vpcID, lbID, req)
 	if err != nil {
 		h.logger.WithError(err).WithFields(logrus.Fields{
@@ -448,6 +1142,8 @@ func (h *CloudPodsHandler) UpdateLoadBalancer(c *gin.Context) {
 		return
 	}
 
+	h.client.Events().Publish("loadbalancer", vpcID, cloudpods.EventModified, lb)
+
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
 		"data":   lb,
@@ -462,7 +1158,21 @@ func (h *CloudPodsHandler) DeleteLoadBalancer(c *gin.Context) {
 		return
 	}
 
-	err := h.client.DeleteLoadBalancer(c.Request.Context(), vpcID, lbID)
+	current, err := h.client.GetLoadBalancer(c.Request.Context(), vpcID, lbID)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"vpc_id": vpcID,
+			"lb_id":  lbID,
+		}).Error("Failed to get load balancer for admission")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get load balancer"})
+		return
+	}
+	var discardLB cloudpods.CloudPodsLoadBalancer
+	if !h.admit(c, "loadbalancer", admission.VerbDelete, vpcID, nil, current, &discardLB) {
+		return
+	}
+
+	err = h.client.GC().Delete(c.Request.Context(), "loadbalancer", vpcID, lbID, propagationPolicy(c))
 	if err != nil {
 		h.logger.WithError(err).WithFields(logrus.Fields{
 			"vpc_id": vpcID,
@@ -475,6 +1185,52 @@ func (h *CloudPodsHandler) DeleteLoadBalancer(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
+// PatchLoadBalancer handles PATCH
+// /api/v1/cloudpods/vpcs/:vpc_id/loadbalancers/:id, applying the body as
+// one of the three content types documented on patchResource. A
+// strategic-merge-patch merges the "listeners" list by port instead of
+// replacing it wholesale — see loadBalancerMergeKeys.
+func (h *CloudPodsHandler) PatchLoadBalancer(c *gin.Context) {
+	vpcID := c.Param("vpc_id")
+	lbID := c.Param("id")
+	if vpcID == "" || lbID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "VPC ID and Load Balancer ID are required"})
+		return
+	}
+
+	current, err := h.client.GetLoadBalancer(c.Request.Context(), vpcID, lbID)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"vpc_id": vpcID,
+			"lb_id":  lbID,
+		}).Error("Failed to get load balancer for patch")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get load balancer"})
+		return
+	}
+
+	var req cloudpods.UpdateLoadBalancerRequest
+	if !h.patchResource(c, "loadbalancer", lbID, current.ResourceVersion, current, &req, loadBalancerMergeKeys) {
+		return
+	}
+
+	lb, err := h.client.UpdateLoadBalancer(c.Request.Context(), vpcID, lbID, req)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"vpc_id": vpcID,
+			"lb_id":  lbID,
+		}).Error("Failed to patch load balancer")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to patch load balancer"})
+		return
+	}
+
+	h.client.Events().Publish("loadbalancer", vpcID, cloudpods.EventModified, lb)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   lb,
+	})
+}
+
 // Service Mesh Handlers
 func (h *CloudPodsHandler) ListServiceMeshRoutes(c *gin.Context) {
 	vpcID := c.Param("vpc_id")
@@ -483,7 +1239,7 @@ func (h *CloudPodsHandler) ListServiceMeshRoutes(c *gin.Context) {
 		return
 	}
 
-	routes, err := h.client.ListServiceMeshRoutes(c.Request.Context(), vpcID)
+	routes, cont, err := h.client.ListServiceMeshRoutes(c.Request.Context(), vpcID, parseListOptions(c))
 	if err != nil {
 		h.logger.WithError(err).WithField("vpc_id", vpcID).Error("Failed to list service mesh routes")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list service mesh routes"})
@@ -491,8 +1247,46 @@ func (h *CloudPodsHandler) ListServiceMeshRoutes(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"status": "success",
-		"data":   routes,
+		"status":   "success",
+		"data":     routes,
+		"continue": cont,
+	})
+}
+
+// DeleteServiceMeshRouteCollection handles DELETE
+// /api/v1/cloudpods/vpcs/:vpc_id/servicemesh/routes, deleting every
+// service-mesh route of vpc_id matching ?labelSelector=/?fieldSelector=
+// in one request. See DeleteVPCCollection for the shared rationale.
+func (h *CloudPodsHandler) DeleteServiceMeshRouteCollection(c *gin.Context) {
+	vpcID := c.Param("vpc_id")
+	if vpcID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "VPC ID is required"})
+		return
+	}
+
+	routes, _, err := h.client.ListServiceMeshRoutes(c.Request.Context(), vpcID, parseListOptions(c))
+	if err != nil {
+		h.logger.WithError(err).WithField("vpc_id", vpcID).Error("Failed to list service mesh routes for collection delete")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list service mesh routes"})
+		return
+	}
+
+	deleted := make([]string, 0, len(routes))
+	for _, route := range routes {
+		if err := h.client.GC().Delete(c.Request.Context(), "servicemeshroute", vpcID, route.ID, garbagecollector.PropagationBackground); err != nil {
+			h.logger.WithError(err).WithFields(logrus.Fields{
+				"vpc_id":   vpcID,
+				"route_id": route.ID,
+			}).Error("Failed to delete service mesh route in collection")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete service mesh route " + route.ID, "deleted": deleted})
+			return
+		}
+		deleted = append(deleted, route.ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"deleted": deleted,
 	})
 }
 
@@ -509,6 +1303,10 @@ func (h *CloudPodsHandler) CreateServiceMeshRoute(c *gin.Context) {
 		return
 	}
 
+	if !h.admit(c, "servicemeshroute", admission.VerbCreate, vpcID, req, nil, &req) {
+		return
+	}
+
 	route, err := h.client.CreateServiceMeshRoute(c.Request.Context(), vpcID, req)
 	if err != nil {
 		h.logger.WithError(err).WithField("vpc_id", vpcID).Error("Failed to create service mesh route")
@@ -516,6 +1314,14 @@ func (h *CloudPodsHandler) CreateServiceMeshRoute(c *gin.Context) {
 		return
 	}
 
+	h.client.GC().Track(garbagecollector.Node{
+		Kind:   "servicemeshroute",
+		ID:     route.ID,
+		VPCID:  vpcID,
+		Owners: h.bindOwnerReferences(c),
+	})
+	h.client.Events().Publish("servicemeshroute", vpcID, cloudpods.EventAdded, route)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"status": "success",
 		"data":   route,
@@ -560,6 +1366,19 @@ func (h *CloudPodsHandler) UpdateServiceMeshRoute(c *gin.Context) {
 		return
 	}
 
+	current, err := h.client.GetServiceMeshRoute(c.Request.Context(), vpcID, routeID)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"vpc_id":   vpcID,
+			"route_id": routeID,
+		}).Error("Failed to get service mesh route for admission")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get service mesh route"})
+		return
+	}
+	if !h.admit(c, "servicemeshroute", admission.VerbUpdate, vpcID, req, current, &req) {
+		return
+	}
+
 	route, err := h.client.UpdateServiceMeshRoute(c.Request.Context(), vpcID, routeID, req)
 	if err != nil {
 		h.logger.WithError(err).WithFields(logrus.Fields{
@@ -570,6 +1389,8 @@ func (h *CloudPodsHandler) UpdateServiceMeshRoute(c *gin.Context) {
 		return
 	}
 
+	h.client.Events().Publish("servicemeshroute", vpcID, cloudpods.EventModified, route)
+
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
 		"data":   route,
@@ -584,7 +1405,21 @@ func (h *CloudPodsHandler) DeleteServiceMeshRoute(c *gin.Context) {
 		return
 	}
 
-	err := h.client.DeleteServiceMeshRoute(c.Request.Context(), vpcID, routeID)
+	current, err := h.client.GetServiceMeshRoute(c.Request.Context(), vpcID, routeID)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"vpc_id":   vpcID,
+			"route_id": routeID,
+		}).Error("Failed to get service mesh route for admission")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get service mesh route"})
+		return
+	}
+	var discardRoute cloudpods.CloudPodsServiceMeshRoute
+	if !h.admit(c, "servicemeshroute", admission.VerbDelete, vpcID, nil, current, &discardRoute) {
+		return
+	}
+
+	err = h.client.GC().Delete(c.Request.Context(), "servicemeshroute", vpcID, routeID, garbagecollector.PropagationBackground)
 	if err != nil {
 		h.logger.WithError(err).WithFields(logrus.Fields{
 			"vpc_id":   vpcID,
@@ -596,3 +1431,177 @@ func (h *CloudPodsHandler) DeleteServiceMeshRoute(c *gin.Context) {
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// PatchServiceMeshRoute handles PATCH
+// /api/v1/cloudpods/vpcs/:vpc_id/servicemesh/routes/:id, applying the
+// body as one of the three content types documented on patchResource. A
+// strategic-merge-patch merges the "routes" list by match expression
+// instead of replacing it wholesale — see serviceMeshRouteMergeKeys.
+func (h *CloudPodsHandler) PatchServiceMeshRoute(c *gin.Context) {
+	vpcID := c.Param("vpc_id")
+	routeID := c.Param("id")
+	if vpcID == "" || routeID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "VPC ID and Route ID are required"})
+		return
+	}
+
+	current, err := h.client.GetServiceMeshRoute(c.Request.Context(), vpcID, routeID)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"vpc_id":   vpcID,
+			"route_id": routeID,
+		}).Error("Failed to get service mesh route for patch")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get service mesh route"})
+		return
+	}
+
+	var req cloudpods.UpdateServiceMeshRouteRequest
+	if !h.patchResource(c, "servicemeshroute", routeID, current.ResourceVersion, current, &req, serviceMeshRouteMergeKeys) {
+		return
+	}
+
+	route, err := h.client.UpdateServiceMeshRoute(c.Request.Context(), vpcID, routeID, req)
+	if err != nil {
+		h.logger.WithError(err).WithFields(logrus.Fields{
+			"vpc_id":   vpcID,
+			"route_id": routeID,
+		}).Error("Failed to patch service mesh route")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to patch service mesh route"})
+		return
+	}
+
+	h.client.Events().Publish("servicemeshroute", vpcID, cloudpods.EventModified, route)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   route,
+	})
+}
+
+// Watch Handlers
+//
+// Each Watch* endpoint streams Kubernetes-style watch events over SSE: an
+// initial SNAPSHOT listing the current resources (skipped when the caller
+// resumes with ?resourceVersion=), followed by ADDED/MODIFIED/DELETED
+// events as they happen, with periodic HEARTBEAT events so idle connections
+// survive proxy timeouts. Clients track the resourceVersion of the last
+// event they processed and pass it back as ?resourceVersion= to resume a
+// dropped connection without missing events.
+
+// WatchVPCs streams VPC watch events for GET /api/v1/cloudpods/vpcs/watch.
+func (h *CloudPodsHandler) WatchVPCs(c *gin.Context) {
+	h.watch(c, "vpc", "", func(ctx context.Context) (interface{}, error) {
+		items, _, err := h.client.ListVPCs(ctx, cloudpods.ListOptions{})
+		return items, err
+	})
+}
+
+// WatchSubnets streams subnet watch events for a VPC.
+func (h *CloudPodsHandler) WatchSubnets(c *gin.Context) {
+	vpcID := c.Param("vpc_id")
+	if vpcID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "VPC ID is required"})
+		return
+	}
+
+	h.watch(c, "subnet", vpcID, func(ctx context.Context) (interface{}, error) {
+		items, _, err := h.client.ListSubnets(ctx, vpcID, cloudpods.ListOptions{})
+		return items, err
+	})
+}
+
+// WatchNATGateways streams NAT gateway watch events for a VPC.
+func (h *CloudPodsHandler) WatchNATGateways(c *gin.Context) {
+	vpcID := c.Param("vpc_id")
+	if vpcID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "VPC ID is required"})
+		return
+	}
+
+	h.watch(c, "natgateway", vpcID, func(ctx context.Context) (interface{}, error) {
+		items, _, err := h.client.ListNATGateways(ctx, vpcID, cloudpods.ListOptions{})
+		return items, err
+	})
+}
+
+// WatchLoadBalancers streams load balancer watch events for a VPC.
+func (h *CloudPodsHandler) WatchLoadBalancers(c *gin.Context) {
+	vpcID := c.Param("vpc_id")
+	if vpcID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "VPC ID is required"})
+		return
+	}
+
+	h.watch(c, "loadbalancer", vpcID, func(ctx context.Context) (interface{}, error) {
+		items, _, err := h.client.ListLoadBalancers(ctx, vpcID, cloudpods.ListOptions{})
+		return items, err
+	})
+}
+
+// WatchServiceMeshRoutes streams service mesh route watch events for a VPC.
+func (h *CloudPodsHandler) WatchServiceMeshRoutes(c *gin.Context) {
+	vpcID := c.Param("vpc_id")
+	if vpcID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "VPC ID is required"})
+		return
+	}
+
+	h.watch(c, "servicemeshroute", vpcID, func(ctx context.Context) (interface{}, error) {
+		items, _, err := h.client.ListServiceMeshRoutes(ctx, vpcID, cloudpods.ListOptions{})
+		return items, err
+	})
+}
+
+// watch drives the SSE stream shared by every Watch* endpoint: it emits the
+// LIST snapshot (via list) unless the caller supplied ?resourceVersion=,
+// then replays any backlog newer than that cursor before switching to live
+// events from the bus, interleaving heartbeats on an idle connection.
+func (h *CloudPodsHandler) watch(c *gin.Context, kind, vpcID string, list func(ctx context.Context) (interface{}, error)) {
+	resourceVersion, err := strconv.ParseUint(c.Query("resourceVersion"), 10, 64)
+	if err != nil {
+		resourceVersion = 0
+	}
+
+	ctx := c.Request.Context()
+
+	if resourceVersion == 0 {
+		items, err := list(ctx)
+		if err != nil {
+			h.logger.WithError(err).WithField("kind", kind).Error("Failed to list resources for watch snapshot")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list resources"})
+			return
+		}
+		c.SSEvent("SNAPSHOT", gin.H{"kind": kind, "items": items})
+		c.Writer.Flush()
+	}
+
+	backlog, live, unsubscribe := h.client.Events().Subscribe(kind, vpcID, resourceVersion)
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(watchHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	pending := backlog
+	c.Stream(func(w io.Writer) bool {
+		if len(pending) > 0 {
+			event := pending[0]
+			pending = pending[1:]
+			c.SSEvent(string(event.Type), event)
+			return true
+		}
+
+		select {
+		case event, ok := <-live:
+			if !ok {
+				return false
+			}
+			c.SSEvent(string(event.Type), event)
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("HEARTBEAT", gin.H{"time": time.Now().Format(time.RFC3339)})
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	})
+}
@@ -5,9 +5,17 @@ import (
 	"net/http"
 	"time"
 
+	"go.uber.org/zap"
+
 	"router-sim/internal/analytics"
+	"router-sim/internal/apispec"
 	"router-sim/internal/aviatrix"
+	"router-sim/internal/aviatrix/jobs"
+	"router-sim/internal/capture"
 	"router-sim/internal/cloudpods"
+	"router-sim/internal/events"
+	"router-sim/internal/partition"
+	"router-sim/internal/scenario"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,19 +23,112 @@ import (
 // Handlers contains all HTTP handlers
 type Handlers struct {
 	analytics  *analytics.Engine
+	clickhouse *analytics.ClickHouseClient
 	aviatrix   *aviatrix.Client
 	cloudpods  *cloudpods.Client
+	scenario   *scenario.Manager
+	partitions *partition.MemoryStore
+	streams    *streamRegistry
+	captures   *capture.Manager
+	events     *events.Bus
+	auditLog   *events.AuditLog
+	jobs       *jobs.Manager
+	asyncOps   *AsyncOperationHandler
+}
+
+// New creates a new handlers instance. captureDir and
+// captureMaxFileSize configure where StartCapture writes its pcap-ng
+// files and how large one is allowed to grow before rotating.
+// auditDir, auditMaxFileSize, and auditRetention configure the rotating
+// JSONL audit log every route/protocol mutation Event is persisted to;
+// see events.NewAuditLog. clickhouse is nil when ClickHouse-backed
+// analytics isn't configured; the /analytics/series routes answer 503
+// in that case rather than panicking.
+func New(analyticsEngine *analytics.Engine, clickhouse *analytics.ClickHouseClient, aviatrix *aviatrix.Client, cloudpods *cloudpods.Client, captureDir string, captureMaxFileSize int64, auditDir string, auditMaxFileSize int64, auditRetention time.Duration) (*Handlers, error) {
+	reconciler := scenario.NewReconciler(zap.NewNop())
+	reconciler.SetCloudPodsClient(cloudpods)
+	scenarioManager := scenario.NewManager(scenario.NewMemoryStore(), scenario.NewBus(), reconciler, zap.NewNop())
+
+	captureManager, err := capture.NewManager(captureDir, captureMaxFileSize)
+	if err != nil {
+		return nil, err
+	}
+
+	auditLog, err := events.NewAuditLog(auditDir, auditMaxFileSize, auditRetention)
+	if err != nil {
+		return nil, err
+	}
+	eventsBus := events.NewBus()
+	jobManager := jobs.NewManager()
+
+	h := &Handlers{
+		analytics:  analyticsEngine,
+		clickhouse: clickhouse,
+		aviatrix:   aviatrix,
+		cloudpods:  cloudpods,
+		scenario:   scenarioManager,
+		partitions: partition.NewMemoryStore(),
+		streams:    newStreamRegistry(),
+		captures:   captureManager,
+		events:     eventsBus,
+		auditLog:   auditLog,
+		jobs:       jobManager,
+		asyncOps:   NewAsyncOperationHandler(jobManager),
+	}
+
+	go h.runAuditLog()
+	if analyticsEngine != nil {
+		analyticsEngine.ConsumeEvents(eventsBus)
+	}
+
+	return h, nil
 }
 
-// New creates a new handlers instance
-func New(analytics *analytics.Engine, aviatrix *aviatrix.Client, cloudpods *cloudpods.Client) *Handlers {
-	return &Handlers{
-		analytics: analytics,
-		aviatrix:  aviatrix,
-		cloudpods: cloudpods,
+// Events returns the audit event bus so RoutingHandler (and anything
+// else that mutates routing state) can be wired to publish against the
+// same instance auditLog persists and analyticsEngine consumes.
+func (h *Handlers) Events() *events.Bus {
+	return h.events
+}
+
+// Jobs returns the async job manager so AviatrixHandler (and anything
+// else that enqueues a long-running operation) can be wired to run
+// against the same instance RegisterJobRoutes exposes.
+func (h *Handlers) Jobs() *jobs.Manager {
+	return h.jobs
+}
+
+// RegisterJobRoutes adds the async Aviatrix job route group to b.
+func (h *Handlers) RegisterJobRoutes(b *apispec.Builder) {
+	h.asyncOps.RegisterJobRoutes(b)
+}
+
+// runAuditLog subscribes to every Event published on h.events and
+// appends it to h.auditLog, making the bus the single path an Event
+// reaches disk through. It runs for the life of the process.
+func (h *Handlers) runAuditLog() {
+	for event := range h.events.Subscribe(nil) {
+		h.auditLog.Append(event)
 	}
 }
 
+// Partitions returns the admin-partition store so main.go can mount
+// partition.Middleware and the partitions route group against the same
+// instance every handler reads from.
+func (h *Handlers) Partitions() *partition.MemoryStore {
+	return h.partitions
+}
+
+// requestPartition returns the partition c's request was scoped to by
+// partition.Middleware, falling back to partition.DefaultPartition for
+// routes mounted without it (e.g. in tests).
+func requestPartition(c *gin.Context) string {
+	if name, ok := partition.FromContext(c.Request.Context()); ok {
+		return name
+	}
+	return partition.DefaultPartition
+}
+
 // Analytics handlers
 func (h *Handlers) GetMetrics(c *gin.Context) {
 	metrics := gin.H{
@@ -65,9 +166,9 @@ func (h *Handlers) GetDashboard(c *gin.Context) {
 
 func (h *Handlers) QueryAnalytics(c *gin.Context) {
 	var query struct {
-		Query    string                 `json:"query"`
-		TimeRange string                `json:"time_range"`
-		Filters  map[string]interface{} `json:"filters"`
+		Query     string                 `json:"query"`
+		TimeRange string                 `json:"time_range"`
+		Filters   map[string]interface{} `json:"filters"`
 	}
 
 	if err := c.ShouldBindJSON(&query); err != nil {
@@ -75,15 +176,22 @@ func (h *Handlers) QueryAnalytics(c *gin.Context) {
 		return
 	}
 
+	// Every query is scoped to the caller's partition: a real
+	// ClickHouseClient appends "AND partition = ?" to its WHERE clause
+	// with this value (see ClickHouseClient.GetMetrics), so one tenant's
+	// query can never return another tenant's rows.
+	p := requestPartition(c)
+
 	// Mock analytics query result
 	result := gin.H{
-		"query": query.Query,
+		"query":     query.Query,
+		"partition": p,
 		"data": []gin.H{
-			{"timestamp": "2024-01-15T10:00:00Z", "value": 45.2, "metric": "cpu_usage"},
-			{"timestamp": "2024-01-15T10:01:00Z", "value": 47.1, "metric": "cpu_usage"},
-			{"timestamp": "2024-01-15T10:02:00Z", "value": 43.8, "metric": "cpu_usage"},
+			{"timestamp": "2024-01-15T10:00:00Z", "value": 45.2, "metric": "cpu_usage", "partition": p},
+			{"timestamp": "2024-01-15T10:01:00Z", "value": 47.1, "metric": "cpu_usage", "partition": p},
+			{"timestamp": "2024-01-15T10:02:00Z", "value": 43.8, "metric": "cpu_usage", "partition": p},
 		},
-		"total": 3,
+		"total":      3,
 		"time_range": query.TimeRange,
 	}
 
@@ -107,6 +215,7 @@ func (h *Handlers) GetCloudPodsStatus(c *gin.Context) {
 }
 
 func (h *Handlers) GetCloudPodsResources(c *gin.Context) {
+	p := requestPartition(c)
 	resources := []gin.H{
 		{
 			"id": "1",
@@ -116,6 +225,7 @@ func (h *Handlers) GetCloudPodsResources(c *gin.Context) {
 			"region": "us-west-1",
 			"created_at": "2024-01-15T10:30:00Z",
 			"tags": []string{"web", "production", "nginx"},
+			"partition": p,
 		},
 		{
 			"id": "2",
@@ -125,6 +235,7 @@ func (h *Handlers) GetCloudPodsResources(c *gin.Context) {
 			"region": "us-west-1",
 			"created_at": "2024-01-10T08:15:00Z",
 			"tags": []string{"database", "production", "postgresql"},
+			"partition": p,
 		},
 		{
 			"id": "3",
@@ -134,6 +245,7 @@ func (h *Handlers) GetCloudPodsResources(c *gin.Context) {
 			"region": "us-west-1",
 			"created_at": "2024-01-12T14:20:00Z",
 			"tags": []string{"loadbalancer", "production", "nginx"},
+			"partition": p,
 		},
 		{
 			"id": "4",
@@ -143,6 +255,7 @@ func (h *Handlers) GetCloudPodsResources(c *gin.Context) {
 			"region": "us-west-1",
 			"created_at": "2024-01-08T16:45:00Z",
 			"tags": []string{"storage", "production", "s3"},
+			"partition": p,
 		},
 		{
 			"id": "5",
@@ -152,36 +265,10 @@ func (h *Handlers) GetCloudPodsResources(c *gin.Context) {
 			"region": "us-west-1",
 			"created_at": "2024-01-05T11:00:00Z",
 			"tags": []string{"monitoring", "production", "prometheus"},
+			"partition": p,
 		},
 	}
-	c.JSON(http.StatusOK, gin.H{"resources": resources})
-}
-
-func (h *Handlers) DeployCloudPods(c *gin.Context) {
-	var deployRequest struct {
-		ResourceType string                 `json:"resource_type"`
-		Name         string                 `json:"name"`
-		Region       string                 `json:"region"`
-		Config       map[string]interface{} `json:"config"`
-	}
-
-	if err := c.ShouldBindJSON(&deployRequest); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Mock deployment
-	deployment := gin.H{
-		"id": "deploy-123",
-		"status": "deploying",
-		"resource_type": deployRequest.ResourceType,
-		"name": deployRequest.Name,
-		"region": deployRequest.Region,
-		"created_at": time.Now().Format(time.RFC3339),
-		"estimated_completion": time.Now().Add(5 * time.Minute).Format(time.RFC3339),
-	}
-
-	c.JSON(http.StatusAccepted, deployment)
+	c.JSON(http.StatusOK, gin.H{"resources": resources, "partition": p})
 }
 
 func (h *Handlers) CleanupCloudPods(c *gin.Context) {
@@ -212,6 +299,7 @@ func (h *Handlers) GetAviatrixStatus(c *gin.Context) {
 }
 
 func (h *Handlers) GetAviatrixGateways(c *gin.Context) {
+	p := requestPartition(c)
 	gateways := []gin.H{
 		{
 			"id": "1",
@@ -226,6 +314,7 @@ func (h *Handlers) GetAviatrixGateways(c *gin.Context) {
 			"private_ip": "10.0.1.100",
 			"uptime": "2d 15h 30m",
 			"connections": 8,
+			"partition": p,
 		},
 		{
 			"id": "2",
@@ -240,6 +329,7 @@ func (h *Handlers) GetAviatrixGateways(c *gin.Context) {
 			"private_ip": "10.0.2.100",
 			"uptime": "1d 8h 45m",
 			"connections": 6,
+			"partition": p,
 		},
 		{
 			"id": "3",
@@ -253,38 +343,10 @@ func (h *Handlers) GetAviatrixGateways(c *gin.Context) {
 			"private_ip": "10.1.1.100",
 			"uptime": "3d 2h 15m",
 			"connections": 4,
+			"partition": p,
 		},
 	}
-	c.JSON(http.StatusOK, gin.H{"gateways": gateways})
-}
-
-func (h *Handlers) DeployAviatrix(c *gin.Context) {
-	var deployRequest struct {
-		GatewayType string                 `json:"gateway_type"`
-		Name        string                 `json:"name"`
-		Cloud       string                 `json:"cloud"`
-		Region      string                 `json:"region"`
-		Config      map[string]interface{} `json:"config"`
-	}
-
-	if err := c.ShouldBindJSON(&deployRequest); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Mock deployment
-	deployment := gin.H{
-		"id": "aviatrix-deploy-456",
-		"status": "deploying",
-		"gateway_type": deployRequest.GatewayType,
-		"name": deployRequest.Name,
-		"cloud": deployRequest.Cloud,
-		"region": deployRequest.Region,
-		"created_at": time.Now().Format(time.RFC3339),
-		"estimated_completion": time.Now().Add(10 * time.Minute).Format(time.RFC3339),
-	}
-
-	c.JSON(http.StatusAccepted, deployment)
+	c.JSON(http.StatusOK, gin.H{"gateways": gateways, "partition": p})
 }
 
 func (h *Handlers) CleanupAviatrix(c *gin.Context) {
@@ -318,124 +380,28 @@ func (h *Handlers) GetRouterStatus(c *gin.Context) {
 }
 
 func (h *Handlers) GetRoutes(c *gin.Context) {
+	p := requestPartition(c)
 	routes := []gin.H{
-		{"prefix": "10.0.0.0/8", "next_hop": "192.168.1.2", "protocol": "BGP", "metric": 0, "age": "2h 15m", "status": "active"},
-		{"prefix": "172.16.0.0/12", "next_hop": "192.168.1.3", "protocol": "OSPF", "metric": 10, "age": "1h 45m", "status": "active"},
-		{"prefix": "192.168.0.0/16", "next_hop": "192.168.1.4", "protocol": "ISIS", "metric": 5, "age": "3h 20m", "status": "active"},
-		{"prefix": "203.0.113.0/24", "next_hop": "192.168.1.5", "protocol": "BGP", "metric": 0, "age": "45m", "status": "active"},
+		{"prefix": "10.0.0.0/8", "next_hop": "192.168.1.2", "protocol": "BGP", "metric": 0, "age": "2h 15m", "status": "active", "partition": p},
+		{"prefix": "172.16.0.0/12", "next_hop": "192.168.1.3", "protocol": "OSPF", "metric": 10, "age": "1h 45m", "status": "active", "partition": p},
+		{"prefix": "192.168.0.0/16", "next_hop": "192.168.1.4", "protocol": "ISIS", "metric": 5, "age": "3h 20m", "status": "active", "partition": p},
+		{"prefix": "203.0.113.0/24", "next_hop": "192.168.1.5", "protocol": "BGP", "metric": 0, "age": "45m", "status": "active", "partition": p},
 	}
-	c.JSON(http.StatusOK, gin.H{"routes": routes})
+	c.JSON(http.StatusOK, gin.H{"routes": routes, "partition": p})
 }
 
 func (h *Handlers) GetNeighbors(c *gin.Context) {
+	p := requestPartition(c)
 	neighbors := []gin.H{
-		{"id": "1", "address": "192.168.1.2", "protocol": "BGP", "state": "Established", "uptime": "2h 15m", "routes": 450},
-		{"id": "2", "address": "192.168.1.3", "protocol": "OSPF", "state": "Full", "uptime": "1h 45m", "routes": 320},
-		{"id": "3", "address": "192.168.1.4", "protocol": "ISIS", "state": "Up", "uptime": "3h 20m", "routes": 680},
-		{"id": "4", "address": "192.168.1.5", "protocol": "BGP", "state": "Established", "uptime": "45m", "routes": 200},
+		{"id": "1", "address": "192.168.1.2", "protocol": "BGP", "state": "Established", "uptime": "2h 15m", "routes": 450, "partition": p},
+		{"id": "2", "address": "192.168.1.3", "protocol": "OSPF", "state": "Full", "uptime": "1h 45m", "routes": 320, "partition": p},
+		{"id": "3", "address": "192.168.1.4", "protocol": "ISIS", "state": "Up", "uptime": "3h 20m", "routes": 680, "partition": p},
+		{"id": "4", "address": "192.168.1.5", "protocol": "BGP", "state": "Established", "uptime": "45m", "routes": 200, "partition": p},
 	}
-	c.JSON(http.StatusOK, gin.H{"neighbors": neighbors})
-}
-
-func (h *Handlers) LoadScenario(c *gin.Context) {
-	var scenario struct {
-		Name        string                 `json:"name"`
-		Description string                 `json:"description"`
-		Config      map[string]interface{} `json:"config"`
-	}
-
-	if err := c.ShouldBindJSON(&scenario); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Mock scenario loading
-	result := gin.H{
-		"scenario_id": "scenario-789",
-		"name": scenario.Name,
-		"status": "loading",
-		"started_at": time.Now().Format(time.RFC3339),
-		"estimated_completion": time.Now().Add(2 * time.Minute).Format(time.RFC3339),
-	}
-
-	c.JSON(http.StatusAccepted, result)
-}
-
-func (h *Handlers) ApplyImpairment(c *gin.Context) {
-	var impairment struct {
-		Type       string                 `json:"type"`
-		Interface  string                 `json:"interface"`
-		Parameters map[string]interface{} `json:"parameters"`
-	}
-
-	if err := c.ShouldBindJSON(&impairment); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Mock impairment application
-	result := gin.H{
-		"impairment_id": "impairment-101",
-		"type": impairment.Type,
-		"interface": impairment.Interface,
-		"status": "applied",
-		"applied_at": time.Now().Format(time.RFC3339),
-	}
-
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, gin.H{"neighbors": neighbors, "partition": p})
 }
 
 // Testing handlers
-func (h *Handlers) StartCapture(c *gin.Context) {
-	var capture struct {
-		Interface string `json:"interface"`
-		Duration  int    `json:"duration"`
-		Filter    string `json:"filter"`
-	}
-
-	if err := c.ShouldBindJSON(&capture); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Mock capture start
-	result := gin.H{
-		"capture_id": "capture-202",
-		"interface": capture.Interface,
-		"duration": capture.Duration,
-		"filter": capture.Filter,
-		"status": "started",
-		"started_at": time.Now().Format(time.RFC3339),
-	}
-
-	c.JSON(http.StatusAccepted, result)
-}
-
-func (h *Handlers) ComparePCAPs(c *gin.Context) {
-	var compare struct {
-		PCAP1 string `json:"pcap1"`
-		PCAP2 string `json:"pcap2"`
-	}
-
-	if err := c.ShouldBindJSON(&compare); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Mock comparison
-	result := gin.H{
-		"comparison_id": "compare-303",
-		"pcap1": compare.PCAP1,
-		"pcap2": compare.PCAP2,
-		"status": "completed",
-		"differences": 15,
-		"similarities": 85,
-		"completed_at": time.Now().Format(time.RFC3339),
-	}
-
-	c.JSON(http.StatusOK, result)
-}
-
 func (h *Handlers) GetTestResults(c *gin.Context) {
 	results := []gin.H{
 		{
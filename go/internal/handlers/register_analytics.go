@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"router-sim/internal/analytics"
+	"router-sim/internal/apispec"
+)
+
+// GetMetricsSeriesRequest is the query string of GET
+// /analytics/series: a Unix-seconds time range (gorilla/schema has no
+// time.Time converter registered, so the range travels as int64 rather
+// than RFC3339) plus the optional per-table filters MetricQuery
+// supports. BucketSeconds is the requested bucket width before
+// GetMetrics's automatic downsampling; 0 falls back to its default.
+type GetMetricsSeriesRequest struct {
+	StartTime     int64  `schema:"start_time,required"`
+	EndTime       int64  `schema:"end_time,required"`
+	BucketSeconds int    `schema:"bucket_seconds"`
+	Hostname      string `schema:"hostname"`
+	Interface     string `schema:"interface"`
+	Protocol      string `schema:"protocol"`
+	CloudProvider string `schema:"cloud_provider"`
+}
+
+// RegisterAnalyticsRoutes adds the ClickHouse-backed analytics route
+// group to b. These are in addition to, not a replacement for, the
+// mocked /analytics/metrics, /analytics/dashboard, and /analytics/query
+// endpoints registered directly in cmd/server/main.go.
+func (h *Handlers) RegisterAnalyticsRoutes(b *apispec.Builder) {
+	b.Add(apispec.Route{
+		Method:     http.MethodGet,
+		Path:       "/analytics/series",
+		Summary:    "Query aggregated, bucketed metric series across every ClickHouse table",
+		Tags:       []string{"analytics"},
+		NewRequest: func() interface{} { return &GetMetricsSeriesRequest{} },
+		Handler: func(c *gin.Context, req interface{}) {
+			h.GetMetricsSeries(c, req.(*GetMetricsSeriesRequest))
+		},
+	})
+	b.Add(apispec.Route{
+		Method:     http.MethodGet,
+		Path:       "/analytics/stream",
+		Summary:    "Stream a live, filtered feed of ClickHouse metrics and events as they are flushed (SSE, falls back to WebSocket)",
+		Tags:       []string{"analytics", "streaming"},
+		NewRequest: func() interface{} { return &StreamAnalyticsRequest{} },
+		Handler: func(c *gin.Context, req interface{}) {
+			h.StreamAnalytics(c, req.(*StreamAnalyticsRequest))
+		},
+	})
+}
+
+// analyticsStreamKinds is every table kind the /analytics/stream
+// endpoint can subscribe to; an empty Kinds filter subscribes to all of
+// them.
+var analyticsStreamKinds = []string{"router", "protocol", "traffic", "impairment", "cloud", "events"}
+
+// StreamAnalyticsRequest is the query string of GET /analytics/stream:
+// which table kinds to subscribe to (empty means every kind in
+// analyticsStreamKinds), plus the optional hostname/severity value to
+// narrow a kind's feed to, mirroring analytics.ClickHouseClient's own
+// "<kind>.<hostname>" / "events.<severity>" topic naming.
+type StreamAnalyticsRequest struct {
+	Kinds    []string `schema:"kinds"`
+	Hostname string   `schema:"hostname"`
+	Severity string   `schema:"severity"`
+}
+
+// analyticsStreamTopics turns req's kind/hostname/severity filter into
+// the literal Hub topics to subscribe to.
+func analyticsStreamTopics(req *StreamAnalyticsRequest) []string {
+	kinds := req.Kinds
+	if len(kinds) == 0 {
+		kinds = analyticsStreamKinds
+	}
+
+	topics := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		switch {
+		case kind == "events" && req.Severity != "":
+			topics = append(topics, "events."+req.Severity)
+		case kind != "events" && req.Hostname != "":
+			topics = append(topics, kind+"."+req.Hostname)
+		default:
+			topics = append(topics, kind)
+		}
+	}
+	return topics
+}
+
+// StreamAnalytics handles GET /api/v1/analytics/stream: every row
+// published to analytics.ClickHouseClient.Stream() that matches req's
+// topic filter is forwarded as a frame named for its topic, oldest
+// frame dropped first if the subscriber falls behind. A Last-Event-ID
+// header is replayed from ClickHouse before the live feed resumes, so a
+// reconnecting client doesn't lose whatever flushed while it was gone.
+func (h *Handlers) StreamAnalytics(c *gin.Context, req *StreamAnalyticsRequest) {
+	if h.clickhouse == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "ClickHouse-backed analytics is not configured"})
+		return
+	}
+
+	kinds := req.Kinds
+	if len(kinds) == 0 {
+		kinds = analyticsStreamKinds
+	}
+	topics := analyticsStreamTopics(req)
+
+	id, disconnect := h.streams.connect("analytics", topics)
+	defer disconnect()
+
+	queue := newDropOldestQueue[streamFrame](32)
+	ctx := c.Request.Context()
+
+	if lastID := c.GetHeader("Last-Event-ID"); lastID != "" {
+		if since, err := time.Parse(time.RFC3339Nano, lastID); err == nil {
+			h.replayAnalytics(ctx, queue, kinds, req.Hostname, req.Severity, since)
+		}
+	}
+
+	frames, unsubscribe := h.clickhouse.Stream().Subscribe(topics, 32)
+	defer unsubscribe()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case frame, ok := <-frames:
+				if !ok {
+					return
+				}
+				queue.push(streamFrame{event: frame.Topic, id: frame.ID, data: frame.Data})
+			}
+		}
+	}()
+
+	h.runStream(c, id, queue)
+}
+
+// replayAnalytics backfills queue with every row newer than since for
+// each requested kind, narrowed by hostname/severity the same way the
+// live subscription is, before StreamAnalytics starts forwarding new
+// frames.
+func (h *Handlers) replayAnalytics(ctx context.Context, queue *dropOldestQueue[streamFrame], kinds []string, hostname, severity string, since time.Time) {
+	push := func(topic string, ts time.Time, row interface{}) {
+		data, err := json.Marshal(row)
+		if err != nil {
+			return
+		}
+		queue.push(streamFrame{event: topic, id: ts.Format(time.RFC3339Nano), data: data})
+	}
+
+	for _, kind := range kinds {
+		switch kind {
+		case "router":
+			rows, err := h.clickhouse.ReplayRouterMetrics(ctx, since, hostname)
+			if err != nil {
+				continue
+			}
+			for _, m := range rows {
+				push("router."+m.Hostname, m.Timestamp, m)
+			}
+		case "protocol":
+			rows, err := h.clickhouse.ReplayProtocolMetrics(ctx, since, hostname)
+			if err != nil {
+				continue
+			}
+			for _, m := range rows {
+				push("protocol."+m.Hostname, m.Timestamp, m)
+			}
+		case "traffic":
+			rows, err := h.clickhouse.ReplayTrafficMetrics(ctx, since, hostname)
+			if err != nil {
+				continue
+			}
+			for _, m := range rows {
+				push("traffic."+m.Hostname, m.Timestamp, m)
+			}
+		case "impairment":
+			rows, err := h.clickhouse.ReplayImpairmentMetrics(ctx, since, hostname)
+			if err != nil {
+				continue
+			}
+			for _, m := range rows {
+				push("impairment."+m.Hostname, m.Timestamp, m)
+			}
+		case "cloud":
+			rows, err := h.clickhouse.ReplayCloudMetrics(ctx, since, hostname)
+			if err != nil {
+				continue
+			}
+			for _, m := range rows {
+				push("cloud."+m.Hostname, m.Timestamp, m)
+			}
+		case "events":
+			rows, err := h.clickhouse.ReplayEvents(ctx, since, severity)
+			if err != nil {
+				continue
+			}
+			for _, e := range rows {
+				push("events."+e.Severity, e.Timestamp, e)
+			}
+		}
+	}
+}
+
+// GetMetricsSeries handles GET /api/v1/analytics/series: aggregated,
+// bucketed series across every ClickHouse table for req's time range,
+// scoped to the caller's partition.
+func (h *Handlers) GetMetricsSeries(c *gin.Context, req *GetMetricsSeriesRequest) {
+	if h.clickhouse == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "ClickHouse-backed analytics is not configured"})
+		return
+	}
+
+	result, err := h.clickhouse.GetMetrics(c.Request.Context(), requestPartition(c), analytics.MetricQuery{
+		StartTime:     time.Unix(req.StartTime, 0).UTC(),
+		EndTime:       time.Unix(req.EndTime, 0).UTC(),
+		Bucket:        time.Duration(req.BucketSeconds) * time.Second,
+		Hostname:      req.Hostname,
+		Interface:     req.Interface,
+		Protocol:      req.Protocol,
+		CloudProvider: req.CloudProvider,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
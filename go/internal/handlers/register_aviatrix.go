@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"router-sim/internal/apispec"
+	"router-sim/internal/partition"
+)
+
+// DeployAviatrixRequest is the body of POST /aviatrix/deploy.
+type DeployAviatrixRequest struct {
+	GatewayType string                 `json:"gateway_type"`
+	Name        string                 `json:"name"`
+	Cloud       string                 `json:"cloud"`
+	Region      string                 `json:"region"`
+	Config      map[string]interface{} `json:"config"`
+}
+
+// RegisterAviatrixRoutes adds the Aviatrix route group to b.
+func (h *Handlers) RegisterAviatrixRoutes(b *apispec.Builder) {
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/aviatrix/status",
+		Summary: "Get Aviatrix controller status",
+		Tags:    []string{"aviatrix"},
+		Handler: func(c *gin.Context, _ interface{}) { h.GetAviatrixStatus(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/aviatrix/gateways",
+		Summary: "List Aviatrix gateways",
+		Tags:    []string{"aviatrix"},
+		Handler: func(c *gin.Context, _ interface{}) { h.GetAviatrixGateways(c) },
+	})
+	b.Add(apispec.Route{
+		Method:     http.MethodPost,
+		Path:       "/aviatrix/deploy",
+		Summary:    "Deploy an Aviatrix gateway",
+		Tags:       []string{"aviatrix"},
+		NewRequest: func() interface{} { return &DeployAviatrixRequest{} },
+		Handler: func(c *gin.Context, req interface{}) {
+			h.DeployAviatrix(c, req.(*DeployAviatrixRequest))
+		},
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodDelete,
+		Path:    "/aviatrix/cleanup",
+		Summary: "Clean up Aviatrix gateways",
+		Tags:    []string{"aviatrix"},
+		Handler: func(c *gin.Context, _ interface{}) { h.CleanupAviatrix(c) },
+	})
+}
+
+func (h *Handlers) DeployAviatrix(c *gin.Context, req *DeployAviatrixRequest) {
+	p := requestPartition(c)
+	if err := partition.ValidateResourceName(p, req.Name); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	deployment := gin.H{
+		"id": "aviatrix-deploy-456",
+		"status": "deploying",
+		"gateway_type": req.GatewayType,
+		"name": req.Name,
+		"cloud": req.Cloud,
+		"region": req.Region,
+		"partition": p,
+		"created_at": time.Now().Format(time.RFC3339),
+		"estimated_completion": time.Now().Add(10 * time.Minute).Format(time.RFC3339),
+	}
+
+	c.JSON(http.StatusAccepted, deployment)
+}
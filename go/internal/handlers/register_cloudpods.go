@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"router-sim/internal/apispec"
+	"router-sim/internal/partition"
+)
+
+// DeployCloudPodsRequest is the body of POST /cloudpods/deploy.
+type DeployCloudPodsRequest struct {
+	ResourceType string                 `json:"resource_type"`
+	Name         string                 `json:"name"`
+	Region       string                 `json:"region"`
+	Config       map[string]interface{} `json:"config"`
+}
+
+// RegisterCloudPodsRoutes adds the CloudPods route group to b.
+func (h *Handlers) RegisterCloudPodsRoutes(b *apispec.Builder) {
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/cloudpods/status",
+		Summary: "Get CloudPods connection status",
+		Tags:    []string{"cloudpods"},
+		Handler: func(c *gin.Context, _ interface{}) { h.GetCloudPodsStatus(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/cloudpods/resources",
+		Summary: "List CloudPods resources",
+		Tags:    []string{"cloudpods"},
+		Handler: func(c *gin.Context, _ interface{}) { h.GetCloudPodsResources(c) },
+	})
+	b.Add(apispec.Route{
+		Method:     http.MethodPost,
+		Path:       "/cloudpods/deploy",
+		Summary:    "Deploy a CloudPods resource",
+		Tags:       []string{"cloudpods"},
+		NewRequest: func() interface{} { return &DeployCloudPodsRequest{} },
+		Handler: func(c *gin.Context, req interface{}) {
+			h.DeployCloudPods(c, req.(*DeployCloudPodsRequest))
+		},
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodDelete,
+		Path:    "/cloudpods/cleanup",
+		Summary: "Clean up CloudPods resources",
+		Tags:    []string{"cloudpods"},
+		Handler: func(c *gin.Context, _ interface{}) { h.CleanupCloudPods(c) },
+	})
+}
+
+func (h *Handlers) DeployCloudPods(c *gin.Context, req *DeployCloudPodsRequest) {
+	p := requestPartition(c)
+	if err := partition.ValidateResourceName(p, req.Name); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	deployment := gin.H{
+		"id": "deploy-123",
+		"status": "deploying",
+		"resource_type": req.ResourceType,
+		"name": req.Name,
+		"region": req.Region,
+		"partition": p,
+		"created_at": time.Now().Format(time.RFC3339),
+		"estimated_completion": time.Now().Add(5 * time.Minute).Format(time.RFC3339),
+	}
+
+	c.JSON(http.StatusAccepted, deployment)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"router-sim/internal/apispec"
+)
+
+// RegisterDebugzRoutes adds the /debugz introspection route group to b,
+// modeled on the debug endpoints a service-mesh control plane exposes
+// for diagnosing a stuck or out-of-sync subscriber: each one dumps the
+// in-memory push state this process is currently holding for its
+// stream and scenario-watch clients.
+func (h *Handlers) RegisterDebugzRoutes(b *apispec.Builder) {
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/debugz/syncz",
+		Summary: "Dump per-client push sync status across every stream",
+		Tags:    []string{"debugz"},
+		Handler: func(c *gin.Context, _ interface{}) { h.DebugSyncz(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/debugz/configz",
+		Summary: "Dump admitted scenarios and their watching clients",
+		Tags:    []string{"debugz"},
+		Handler: func(c *gin.Context, _ interface{}) { h.DebugConfigz(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/debugz/endpointz",
+		Summary: "Dump metrics-stream clients and their watched resource names",
+		Tags:    []string{"debugz"},
+		Handler: func(c *gin.Context, _ interface{}) { h.DebugEndpointz(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/debugz/ndsz",
+		Summary: "Dump events-stream clients and their watched event types",
+		Tags:    []string{"debugz"},
+		Handler: func(c *gin.Context, _ interface{}) { h.DebugNdsz(c) },
+	})
+}
+
+// DebugSyncz handles GET /api/v1/debugz/syncz: every connected stream
+// client across /metrics/stream, /events/stream, and
+// /router/scenarios/watch, with its last-pushed version and nonce.
+func (h *Handlers) DebugSyncz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"clients": h.streams.snapshot("")})
+}
+
+// DebugConfigz handles GET /api/v1/debugz/configz: the full set of
+// admitted scenarios, the config this control plane is currently
+// reconciling against, alongside whichever clients are watching it.
+func (h *Handlers) DebugConfigz(c *gin.Context) {
+	scenarios, err := h.scenario.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list scenarios"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scenarios": scenarios,
+		"clients":   h.streams.snapshot("scenarios"),
+	})
+}
+
+// DebugEndpointz handles GET /api/v1/debugz/endpointz: the metrics
+// stream's connected clients and the resource names (metric fields)
+// each one is being pushed.
+func (h *Handlers) DebugEndpointz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"clients": h.streams.snapshot("metrics")})
+}
+
+// DebugNdsz handles GET /api/v1/debugz/ndsz: the events stream's
+// connected clients and the event types each one is being pushed, this
+// control plane's closest analogue to a name-discovery-service dump.
+func (h *Handlers) DebugNdsz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"clients": h.streams.snapshot("events")})
+}
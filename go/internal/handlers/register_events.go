@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"router-sim/internal/apispec"
+	"router-sim/internal/events"
+)
+
+// eventsLongPollTimeout bounds how long GetEvents holds a request open
+// waiting for a new Event before answering with whatever it already
+// has (possibly nothing).
+const eventsLongPollTimeout = 30 * time.Second
+
+// RegisterEventsRoutes adds the audit event route group to b.
+func (h *Handlers) RegisterEventsRoutes(b *apispec.Builder) {
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/events",
+		Summary: "List audit events for route/protocol mutations since a given time, long-polling for new ones if none match yet",
+		Tags:    []string{"events"},
+		Handler: func(c *gin.Context, _ interface{}) { h.GetEvents(c) },
+	})
+}
+
+// GetEvents handles GET /api/v1/events?since=&type=: since is an
+// RFC3339 timestamp (defaulting to the zero time, i.e. everything the
+// audit log has retained) and type optionally narrows the result to one
+// events.Type. If nothing matches yet, the request long-polls on the
+// live event bus for up to eventsLongPollTimeout before answering with
+// an empty list, so a client can poll this endpoint in a loop without
+// busy-waiting.
+func (h *Handlers) GetEvents(c *gin.Context) {
+	since := time.Time{}
+	if raw := c.Query("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC3339 timestamp: " + err.Error()})
+			return
+		}
+		since = parsed
+	}
+	typ := events.Type(c.Query("type"))
+
+	matched, err := h.auditLog.List(since, typ)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(matched) == 0 {
+		if event, ok := h.waitForEvent(c, typ); ok {
+			matched = append(matched, event)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   matched,
+	})
+}
+
+// waitForEvent blocks until the event bus delivers an Event matching
+// typ (any Event, if typ is empty), the client disconnects, or
+// eventsLongPollTimeout elapses.
+func (h *Handlers) waitForEvent(c *gin.Context, typ events.Type) (events.Event, bool) {
+	var filter events.Filter
+	if typ != "" {
+		filter = func(event events.Event) bool { return event.Type == typ }
+	}
+
+	ch := h.events.Subscribe(filter)
+	defer h.events.Unsubscribe(ch)
+
+	timeout := time.NewTimer(eventsLongPollTimeout)
+	defer timeout.Stop()
+
+	select {
+	case event, ok := <-ch:
+		if !ok {
+			return events.Event{}, false
+		}
+		return event, true
+	case <-c.Request.Context().Done():
+		return events.Event{}, false
+	case <-timeout.C:
+		return events.Event{}, false
+	}
+}
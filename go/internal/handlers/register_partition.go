@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"router-sim/internal/apispec"
+	"router-sim/internal/partition"
+)
+
+// CreatePartitionRequest is the body of POST /partitions.
+type CreatePartitionRequest struct {
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Bindings    []partition.RoleBinding `json:"bindings"`
+}
+
+// RegisterPartitionRoutes adds the admin-partition route group to b.
+func (h *Handlers) RegisterPartitionRoutes(b *apispec.Builder) {
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/partitions",
+		Summary: "List admin partitions",
+		Tags:    []string{"partitions"},
+		Handler: func(c *gin.Context, _ interface{}) { h.ListPartitions(c) },
+	})
+	b.Add(apispec.Route{
+		Method:     http.MethodPost,
+		Path:       "/partitions",
+		Summary:    "Create an admin partition with RBAC bindings",
+		Tags:       []string{"partitions"},
+		NewRequest: func() interface{} { return &CreatePartitionRequest{} },
+		Handler: func(c *gin.Context, req interface{}) {
+			h.CreatePartition(c, req.(*CreatePartitionRequest))
+		},
+	})
+}
+
+// ListPartitions handles GET /api/v1/partitions.
+func (h *Handlers) ListPartitions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"partitions": h.partitions.List()})
+}
+
+// CreatePartition handles POST /api/v1/partitions: it admits a new
+// Partition scoped by req.Name and binds req.Bindings to it, so
+// different tenants can each get an isolated slice of this control
+// plane without seeing each other's resources.
+func (h *Handlers) CreatePartition(c *gin.Context, req *CreatePartitionRequest) {
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	record, err := h.partitions.Create(req.Name, req.Description, req.Bindings)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, record)
+}
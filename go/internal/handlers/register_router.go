@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"router-sim/internal/apispec"
+	"router-sim/internal/scenario"
+)
+
+// LoadScenarioRequest is the body of POST /router/scenario. Config is
+// interpreted as a RouterTopologySpec's fields for backward
+// compatibility with callers that predate the scenario.Manager-backed
+// CRD endpoints below.
+type LoadScenarioRequest struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Config      map[string]interface{} `json:"config"`
+}
+
+// ApplyImpairmentRequest is the body of POST /router/impairment.
+type ApplyImpairmentRequest struct {
+	Type       string                 `json:"type"`
+	Interface  string                 `json:"interface"`
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+// PutScenarioRequest is the body of PUT /router/scenarios/:name: a
+// CRD-like document (apiVersion, kind, metadata, spec) whose Spec shape
+// depends on Kind. Metadata.Name is accepted for round-tripping a GET
+// response but the :name path parameter is authoritative.
+type PutScenarioRequest struct {
+	APIVersion string                 `json:"apiVersion"`
+	Kind       string                 `json:"kind"`
+	Metadata   scenario.ObjectMeta    `json:"metadata"`
+	Spec       map[string]interface{} `json:"spec"`
+}
+
+// RegisterRouterRoutes adds the router-simulation route group to b.
+func (h *Handlers) RegisterRouterRoutes(b *apispec.Builder) {
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/router/status",
+		Summary: "Get router simulation status",
+		Tags:    []string{"router"},
+		Handler: func(c *gin.Context, _ interface{}) { h.GetRouterStatus(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/router/routes",
+		Summary: "List simulated routes",
+		Tags:    []string{"router"},
+		Handler: func(c *gin.Context, _ interface{}) { h.GetRoutes(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/router/neighbors",
+		Summary: "List routing protocol neighbors",
+		Tags:    []string{"router"},
+		Handler: func(c *gin.Context, _ interface{}) { h.GetNeighbors(c) },
+	})
+	b.Add(apispec.Route{
+		Method:     http.MethodPost,
+		Path:       "/router/scenario",
+		Summary:    "Load a router simulation scenario",
+		Tags:       []string{"router"},
+		NewRequest: func() interface{} { return &LoadScenarioRequest{} },
+		Handler: func(c *gin.Context, req interface{}) {
+			h.LoadScenario(c, req.(*LoadScenarioRequest))
+		},
+	})
+	b.Add(apispec.Route{
+		Method:     http.MethodPost,
+		Path:       "/router/impairment",
+		Summary:    "Apply a network impairment",
+		Tags:       []string{"router"},
+		NewRequest: func() interface{} { return &ApplyImpairmentRequest{} },
+		Handler: func(c *gin.Context, req interface{}) {
+			h.ApplyImpairment(c, req.(*ApplyImpairmentRequest))
+		},
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/router/scenarios",
+		Summary: "List admitted scenarios",
+		Tags:    []string{"router"},
+		Handler: func(c *gin.Context, _ interface{}) { h.GetScenarios(c) },
+	})
+	b.Add(apispec.Route{
+		Method:     http.MethodPut,
+		Path:       "/router/scenarios/:name",
+		Summary:    "Apply a CRD-style scenario document",
+		Tags:       []string{"router"},
+		NewRequest: func() interface{} { return &PutScenarioRequest{} },
+		Handler: func(c *gin.Context, req interface{}) {
+			h.PutScenario(c, req.(*PutScenarioRequest))
+		},
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/router/scenarios/watch",
+		Summary: "Stream scenario add/update/delete events (SSE)",
+		Tags:    []string{"router"},
+		Handler: func(c *gin.Context, _ interface{}) { h.WatchScenarios(c) },
+	})
+}
+
+// LoadScenario admits req as a RouterTopology scenario named req.Name,
+// routing it through the same validation and reconciliation every other
+// scenario kind goes through instead of returning a canned response.
+func (h *Handlers) LoadScenario(c *gin.Context, req *LoadScenarioRequest) {
+	specBytes, err := json.Marshal(req.Config)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scenario config"})
+		return
+	}
+
+	result, err := h.scenario.Apply(c.Request.Context(), &scenario.Scenario{
+		APIVersion: scenario.APIVersion,
+		Kind:       scenario.KindRouterTopology,
+		Metadata:   scenario.ObjectMeta{Name: req.Name},
+		Spec:       specBytes,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, result)
+}
+
+// ApplyImpairment is a thin wrapper creating a LinkImpairment scenario
+// from req, so impairments flow through the same reconciler as every
+// other scenario kind.
+func (h *Handlers) ApplyImpairment(c *gin.Context, req *ApplyImpairmentRequest) {
+	name := fmt.Sprintf("%s-%s", req.Interface, req.Type)
+
+	result, err := h.scenario.ApplyImpairment(c.Request.Context(), name, req.Interface, req.Type, req.Parameters)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetScenarios handles GET /api/v1/router/scenarios.
+func (h *Handlers) GetScenarios(c *gin.Context) {
+	scenarios, err := h.scenario.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list scenarios"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"scenarios": scenarios, "count": len(scenarios)})
+}
+
+// PutScenario handles PUT /api/v1/router/scenarios/:name.
+func (h *Handlers) PutScenario(c *gin.Context, req *PutScenarioRequest) {
+	name := c.Param("name")
+
+	specBytes, err := json.Marshal(req.Spec)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid spec"})
+		return
+	}
+
+	result, err := h.scenario.Apply(c.Request.Context(), &scenario.Scenario{
+		APIVersion: req.APIVersion,
+		Kind:       scenario.Kind(req.Kind),
+		Metadata:   scenario.ObjectMeta{Name: name},
+		Spec:       specBytes,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// WatchScenarios handles GET /api/v1/router/scenarios/watch, streaming
+// scenario add/update/delete events as Server-Sent Events until the
+// client disconnects.
+func (h *Handlers) WatchScenarios(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+
+	ch := make(chan scenario.Event, 64)
+	h.scenario.Watch(ch)
+	defer h.scenario.Unwatch(ch)
+
+	id, disconnect := h.streams.connect("scenarios", nil)
+	defer disconnect()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			sseWriter(c, "ping", []byte("{}"))
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			sseWriter(c, "", data)
+			h.streams.ack(id)
+		}
+	}
+}
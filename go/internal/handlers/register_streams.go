@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"router-sim/internal/analytics"
+	"router-sim/internal/apispec"
+	"router-sim/internal/scenario"
+)
+
+// RegisterStreamRoutes adds the live-update route group to b.
+func (h *Handlers) RegisterStreamRoutes(b *apispec.Builder) {
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/metrics/stream",
+		Summary: "Stream live metric updates (SSE, falls back to WebSocket)",
+		Tags:    []string{"streaming"},
+		Handler: func(c *gin.Context, _ interface{}) { h.StreamMetrics(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/events/stream",
+		Summary: "Stream topology, neighbor, and cloud status events (SSE, falls back to WebSocket)",
+		Tags:    []string{"streaming"},
+		Handler: func(c *gin.Context, _ interface{}) { h.StreamEvents(c) },
+	})
+}
+
+// streamFrame is one message waiting to be delivered to a stream
+// subscriber, already marshaled so runStream doesn't care whether it
+// came from analytics.Engine or scenario.Bus. id is optional (most
+// streams leave it empty); when set it is sent as the SSE frame's
+// "id:" field so a reconnecting EventSource can echo it back via
+// Last-Event-ID.
+type streamFrame struct {
+	event string
+	id    string
+	data  []byte
+}
+
+// runStream drains queue and writes each frame to c as an SSE event, or
+// as a WebSocket text frame if the client asked to upgrade, sending a
+// heartbeat every heartbeatInterval and ACKing id in h.streams on every
+// frame actually delivered.
+func (h *Handlers) runStream(c *gin.Context, id string, queue *dropOldestQueue[streamFrame]) {
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	if wantsWebSocket(c) {
+		conn, err := streamUpgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case <-heartbeat.C:
+				if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+					return
+				}
+			case frame := <-queue.ch:
+				if err := conn.WriteMessage(websocket.TextMessage, frame.data); err != nil {
+					return
+				}
+				h.streams.ack(id)
+			}
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			sseWriter(c, "ping", "", []byte("{}"))
+		case frame := <-queue.ch:
+			sseWriter(c, frame.event, frame.id, frame.data)
+			h.streams.ack(id)
+		}
+	}
+}
+
+// StreamMetrics handles GET /api/v1/metrics/stream: every
+// analytics.MetricUpdate published while the client is connected is
+// forwarded as a "metrics" frame, oldest-first-dropped if the client
+// falls behind.
+func (h *Handlers) StreamMetrics(c *gin.Context) {
+	id, disconnect := h.streams.connect("metrics", []string{"cpu_usage", "memory_usage", "network_rx", "network_tx", "packet_loss", "latency"})
+	defer disconnect()
+
+	updates := make(chan analytics.MetricUpdate, 8)
+	h.analytics.Subscribe(updates)
+	defer h.analytics.Unsubscribe(updates)
+
+	queue := newDropOldestQueue[streamFrame](16)
+	ctx := c.Request.Context()
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case u, ok := <-updates:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(u)
+				if err != nil {
+					continue
+				}
+				queue.push(streamFrame{event: "metrics", data: data})
+			}
+		}
+	}()
+
+	h.runStream(c, id, queue)
+}
+
+// syntheticEvent is a protocol-neighbor or cloud-connection status
+// change. router-sim has no live BGP/OSPF/ISIS stack or cloud poller
+// feeding real transitions yet, so — like GetDashboard's recent_events
+// — a fixed sample is replayed on a timer until one exists.
+type syntheticEvent struct {
+	Type      string    `json:"type"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+var syntheticEventSamples = []syntheticEvent{
+	{Type: "neighbor_transition", Message: "BGP neighbor 192.168.1.2 transitioned to Established"},
+	{Type: "neighbor_transition", Message: "OSPF neighbor 192.168.1.3 transitioned to Full"},
+	{Type: "cloud_status", Message: "Aviatrix gateway gw-aws-us-west-1 reported status up"},
+	{Type: "cloud_status", Message: "CloudPods resource db-cluster-1 reported status running"},
+}
+
+// StreamEvents handles GET /api/v1/events/stream: admitted-scenario
+// Added/Modified/Deleted events are forwarded as "scenario" frames as
+// they happen, interleaved with periodic neighbor-transition and
+// cloud-status frames.
+func (h *Handlers) StreamEvents(c *gin.Context) {
+	id, disconnect := h.streams.connect("events", []string{"scenario", "neighbor_transition", "cloud_status"})
+	defer disconnect()
+
+	scenarioCh := make(chan scenario.Event, 16)
+	h.scenario.Watch(scenarioCh)
+	defer h.scenario.Unwatch(scenarioCh)
+
+	queue := newDropOldestQueue[streamFrame](16)
+	ctx := c.Request.Context()
+
+	go func() {
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+
+		i := 0
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-scenarioCh:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				queue.push(streamFrame{event: "scenario", data: data})
+			case <-ticker.C:
+				sample := syntheticEventSamples[i%len(syntheticEventSamples)]
+				sample.Timestamp = time.Now()
+				i++
+
+				data, err := json.Marshal(sample)
+				if err != nil {
+					continue
+				}
+				queue.push(streamFrame{event: sample.Type, data: data})
+			}
+		}
+	}()
+
+	h.runStream(c, id, queue)
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"router-sim/internal/apispec"
+	"router-sim/internal/capture"
+)
+
+// StartCaptureRequest is the body of POST /test/capture. Duration is in
+// seconds; 0 captures until a client calls Stop (not yet exposed over
+// HTTP) or the process restarts.
+type StartCaptureRequest struct {
+	Interface string `json:"interface"`
+	Duration  int    `json:"duration"`
+	Filter    string `json:"filter"`
+}
+
+// ComparePCAPsRequest is the body of POST /test/compare: two paths
+// under the server's capture directory, most commonly the Files two
+// earlier StartCapture calls returned.
+type ComparePCAPsRequest struct {
+	PCAP1 string `json:"pcap1"`
+	PCAP2 string `json:"pcap2"`
+}
+
+// RegisterTestingRoutes adds the testing route group to b.
+func (h *Handlers) RegisterTestingRoutes(b *apispec.Builder) {
+	b.Add(apispec.Route{
+		Method:     http.MethodPost,
+		Path:       "/test/capture",
+		Summary:    "Start a packet capture",
+		Tags:       []string{"testing"},
+		NewRequest: func() interface{} { return &StartCaptureRequest{} },
+		Handler: func(c *gin.Context, req interface{}) {
+			h.StartCapture(c, req.(*StartCaptureRequest))
+		},
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/captures/:id",
+		Summary: "Get a packet capture's status",
+		Tags:    []string{"testing"},
+		Handler: func(c *gin.Context, _ interface{}) { h.GetCapture(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/captures/:id/download",
+		Summary: "Download a packet capture's most recent pcap-ng file",
+		Tags:    []string{"testing"},
+		Handler: func(c *gin.Context, _ interface{}) { h.DownloadCapture(c) },
+	})
+	b.Add(apispec.Route{
+		Method:     http.MethodPost,
+		Path:       "/test/compare",
+		Summary:    "Compare two packet captures",
+		Tags:       []string{"testing"},
+		NewRequest: func() interface{} { return &ComparePCAPsRequest{} },
+		Handler: func(c *gin.Context, req interface{}) {
+			h.ComparePCAPs(c, req.(*ComparePCAPsRequest))
+		},
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/test/results",
+		Summary: "List test results",
+		Tags:    []string{"testing"},
+		Handler: func(c *gin.Context, _ interface{}) { h.GetTestResults(c) },
+	})
+}
+
+// StartCapture handles POST /api/v1/test/capture: it opens a live pcap
+// handle on req.Interface, compiles req.Filter as a BPF expression, and
+// starts writing packets to a rotating set of pcap-ng files.
+func (h *Handlers) StartCapture(c *gin.Context, req *StartCaptureRequest) {
+	result, err := h.captures.Start(req.Interface, req.Filter, time.Duration(req.Duration)*time.Second)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, result)
+}
+
+// GetCapture handles GET /api/v1/captures/:id.
+func (h *Handlers) GetCapture(c *gin.Context) {
+	result, ok := h.captures.Get(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "capture not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// DownloadCapture handles GET /api/v1/captures/:id/download, streaming
+// back the capture's most recently opened pcap-ng file. A still-running
+// capture is still downloadable; the file simply ends wherever the
+// capture has written to so far.
+func (h *Handlers) DownloadCapture(c *gin.Context) {
+	result, ok := h.captures.Get(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "capture not found"})
+		return
+	}
+
+	path := result.LatestFile()
+	if path == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "capture has no file yet"})
+		return
+	}
+
+	c.FileAttachment(path, result.ID+".pcapng")
+}
+
+// ComparePCAPs handles POST /api/v1/test/compare: it parses req.PCAP1
+// and req.PCAP2, groups each into 5-tuple flows, and returns the flows
+// unique to each file, the flows present in both with their counter
+// deltas, and a per-protocol similarity score.
+func (h *Handlers) ComparePCAPs(c *gin.Context, req *ComparePCAPsRequest) {
+	diff, err := capture.Compare(req.PCAP1, req.PCAP2)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pcap1":                  req.PCAP1,
+		"pcap2":                  req.PCAP2,
+		"only_in_pcap1":          diff.OnlyInA,
+		"only_in_pcap2":          diff.OnlyInB,
+		"present_in_both":        diff.PresentInBoth,
+		"similarity_by_protocol": diff.SimilarityByProto,
+		"completed_at":           time.Now().Format(time.RFC3339),
+	})
+}
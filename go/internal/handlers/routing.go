@@ -2,58 +2,108 @@ package handlers
 
 import (
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+
+	"router-sim/internal/events"
+	"router-sim/internal/metrics"
+	"router-sim/internal/routing"
 )
 
+// RoutingHandler exposes routing.Manager's RIB/FIB over HTTP: route
+// CRUD against the RIB, FIB-derived stats, and per-protocol adjacency
+// session lifecycle.
 type RoutingHandler struct {
-	logger *logrus.Logger
+	logger  *logrus.Logger
+	manager *routing.Manager
+	events  *events.Bus
 }
 
-func NewRoutingHandler(logger *logrus.Logger) *RoutingHandler {
+// NewRoutingHandler wires manager into a RoutingHandler. Every
+// mutation AddRoute/RemoveRoute/StartProtocol/StopProtocol makes is
+// also published to bus as a typed events.Event, carrying the actor and
+// correlation ID events.Middleware set on the request.
+func NewRoutingHandler(logger *logrus.Logger, manager *routing.Manager, bus *events.Bus) *RoutingHandler {
 	return &RoutingHandler{
-		logger: logger,
+		logger:  logger,
+		manager: manager,
+		events:  bus,
+	}
+}
+
+// auditEvent builds the Event AddRoute/RemoveRoute/StartProtocol/
+// StopProtocol publish after a successful mutation, pulling actor and
+// correlation ID from the context events.Middleware populated (falling
+// back to events.UnknownActor and a freshly minted ID for requests that
+// bypassed it, e.g. in tests).
+func (h *RoutingHandler) auditEvent(c *gin.Context, typ events.Type, before, after interface{}) events.Event {
+	actor, ok := events.ActorFromContext(c.Request.Context())
+	if !ok {
+		actor = events.UnknownActor
+	}
+	correlationID, ok := events.CorrelationIDFromContext(c.Request.Context())
+	if !ok {
+		correlationID = events.NewID()
+	}
+
+	return events.Event{
+		ID:            events.NewID(),
+		Type:          typ,
+		Actor:         actor,
+		CorrelationID: correlationID,
+		Timestamp:     time.Now(),
+		Before:        before,
+		After:         after,
+	}
+}
+
+// publish fans event out on h.events, a no-op if this RoutingHandler
+// was built without one.
+func (h *RoutingHandler) publish(event events.Event) {
+	if h.events != nil {
+		h.events.Publish(event)
 	}
 }
 
 func (h *RoutingHandler) GetRoutingStats(c *gin.Context) {
-	// Placeholder implementation
-	stats := gin.H{
-		"total_routes":     100,
-		"bgp_routes":       50,
-		"ospf_routes":      30,
-		"isis_routes":      20,
-		"convergence_time": "2.5s",
-		"last_update":      "2024-01-01T00:00:00Z",
+	stats := h.manager.Stats()
+
+	byProtocol := gin.H{}
+	for protocol, count := range stats.ByProtocol {
+		byProtocol[string(protocol)] = count
+		metrics.RoutesTotal.WithLabelValues(string(protocol)).Set(float64(count))
+	}
+	metrics.RoutingTableSize.Set(float64(stats.TotalRoutes))
+	if stats.ConvergenceTime > 0 {
+		metrics.ProtocolConvergenceSeconds.WithLabelValues("all").Observe(stats.ConvergenceTime.Seconds())
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
-		"data":   stats,
+		"data": gin.H{
+			"total_routes":     stats.TotalRoutes,
+			"by_protocol":      byProtocol,
+			"convergence_time": stats.ConvergenceTime.String(),
+		},
 	})
 }
 
 func (h *RoutingHandler) GetRoutes(c *gin.Context) {
-	// Placeholder implementation
-	routes := []gin.H{
-		{
-			"destination": "192.168.1.0/24",
-			"next_hop":    "10.0.0.1",
-			"interface":   "eth0",
-			"metric":      1,
-			"protocol":    "static",
-			"is_active":   true,
-		},
-		{
-			"destination": "10.0.0.0/8",
-			"next_hop":    "192.168.1.1",
-			"interface":   "eth1",
-			"metric":      2,
-			"protocol":    "bgp",
-			"is_active":   true,
-		},
+	entries := h.manager.Routes()
+
+	routes := make([]gin.H, 0, len(entries))
+	for _, entry := range entries {
+		routes = append(routes, gin.H{
+			"destination": entry.Prefix,
+			"next_hop":    entry.Route.NextHop,
+			"interface":   entry.Route.Interface,
+			"metric":      entry.Route.Metric,
+			"protocol":    entry.Route.Protocol,
+			"tag":         entry.Route.Tag,
+			"updated_at":  entry.Updated,
+		})
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -76,7 +126,20 @@ func (h *RoutingHandler) AddRoute(c *gin.Context) {
 		return
 	}
 
-	// Placeholder implementation
+	route := routing.Route{
+		Prefix:    req.Destination,
+		NextHop:   req.NextHop,
+		Interface: req.Interface,
+		Metric:    req.Metric,
+		Protocol:  routing.Protocol(req.Protocol),
+	}
+	if err := h.manager.AddRoute(c.Request.Context(), route); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	metrics.RouteUpdatesTotal.WithLabelValues("add").Inc()
+	h.publish(h.auditEvent(c, events.RouteAdded, nil, route))
+
 	h.logger.WithFields(logrus.Fields{
 		"destination": req.Destination,
 		"next_hop":    req.NextHop,
@@ -100,8 +163,41 @@ func (h *RoutingHandler) RemoveRoute(c *gin.Context) {
 		return
 	}
 
-	// Placeholder implementation
-	h.logger.WithField("destination", destination).Info("Route removed")
+	protocol := routing.Protocol(c.Query("protocol"))
+	nextHop := c.Query("next_hop")
+	removed := routing.Route{Prefix: destination, Protocol: protocol, NextHop: nextHop}
+	if protocol == "" || nextHop == "" {
+		candidates, err := h.manager.Candidates(destination)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		switch len(candidates) {
+		case 0:
+			c.JSON(http.StatusNotFound, gin.H{"error": "no route registered for destination " + destination})
+			return
+		case 1:
+			protocol, nextHop = candidates[0].Protocol, candidates[0].NextHop
+			removed = candidates[0]
+			removed.Prefix = destination
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "destination has multiple candidate routes; specify protocol and next_hop query parameters"})
+			return
+		}
+	}
+
+	if err := h.manager.RemoveRoute(c.Request.Context(), destination, protocol, nextHop); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	metrics.RouteUpdatesTotal.WithLabelValues("remove").Inc()
+	h.publish(h.auditEvent(c, events.RouteRemoved, removed, nil))
+
+	h.logger.WithFields(logrus.Fields{
+		"destination": destination,
+		"protocol":    protocol,
+		"next_hop":    nextHop,
+	}).Info("Route removed")
 
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
@@ -112,26 +208,22 @@ func (h *RoutingHandler) RemoveRoute(c *gin.Context) {
 }
 
 func (h *RoutingHandler) GetProtocols(c *gin.Context) {
-	// Placeholder implementation
-	protocols := []gin.H{
-		{
-			"name":    "bgp",
-			"enabled": true,
-			"status":  "running",
-			"peers":   3,
-		},
-		{
-			"name":    "ospf",
-			"enabled": true,
-			"status":  "running",
-			"area":    "0.0.0.0",
-		},
-		{
-			"name":    "isis",
-			"enabled": false,
-			"status":  "stopped",
-			"level":   "2",
-		},
+	states := h.manager.Protocols()
+
+	protocols := make([]gin.H, 0, len(states))
+	for _, state := range states {
+		status := "stopped"
+		if state.Running {
+			status = "running"
+		}
+		metrics.NeighborsUp.WithLabelValues(string(state.Protocol)).Set(float64(state.Peers))
+
+		protocols = append(protocols, gin.H{
+			"name":    state.Protocol,
+			"enabled": state.Running,
+			"status":  status,
+			"peers":   state.Peers,
+		})
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -147,7 +239,13 @@ func (h *RoutingHandler) StartProtocol(c *gin.Context) {
 		return
 	}
 
-	// Placeholder implementation
+	before := h.protocolState(routing.Protocol(name))
+	if err := h.manager.StartProtocol(routing.Protocol(name)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.publish(h.auditEvent(c, events.ProtocolStarted, before, h.protocolState(routing.Protocol(name))))
+
 	h.logger.WithField("protocol", name).Info("Protocol started")
 
 	c.JSON(http.StatusOK, gin.H{
@@ -165,7 +263,13 @@ func (h *RoutingHandler) StopProtocol(c *gin.Context) {
 		return
 	}
 
-	// Placeholder implementation
+	before := h.protocolState(routing.Protocol(name))
+	if err := h.manager.StopProtocol(routing.Protocol(name)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.publish(h.auditEvent(c, events.ProtocolStopped, before, h.protocolState(routing.Protocol(name))))
+
 	h.logger.WithField("protocol", name).Info("Protocol stopped")
 
 	c.JSON(http.StatusOK, gin.H{
@@ -175,3 +279,15 @@ func (h *RoutingHandler) StopProtocol(c *gin.Context) {
 		},
 	})
 }
+
+// protocolState returns protocol's current lifecycle state, for
+// recording as the before/after state on a ProtocolStarted/
+// ProtocolStopped Event.
+func (h *RoutingHandler) protocolState(protocol routing.Protocol) routing.ProtocolState {
+	for _, state := range h.manager.Protocols() {
+		if state.Protocol == protocol {
+			return state
+		}
+	}
+	return routing.ProtocolState{Protocol: protocol}
+}
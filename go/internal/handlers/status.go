@@ -1,71 +1,224 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
+// version, gitSHA, and buildDate are overridden at build time via
+// -ldflags, e.g.
+//
+//	go build -ldflags "-X router-sim/internal/handlers.version=$(git describe --tags) \
+//	  -X router-sim/internal/handlers.gitSHA=$(git rev-parse HEAD) \
+//	  -X router-sim/internal/handlers.buildDate=$(date -u +%FT%TZ)"
+//
+// and otherwise keep these placeholders for a plain `go build`.
+var (
+	version   = "dev"
+	gitSHA    = "unknown"
+	buildDate = "unknown"
+)
+
+// checkTimeout bounds how long a single HealthChecker.Check is allowed
+// to run before StatusHandler gives up on it for this round.
+const checkTimeout = 3 * time.Second
+
+// HealthChecker is one subsystem StatusHandler polls for liveness. A
+// subsystem registers itself (or a lightweight adapter over itself, see
+// FuncHealthChecker) with RegisterChecker at startup — e.g. the
+// Cloudpods client, aviatrix.Client, ClickHouseClient, and router core
+// each register one.
+type HealthChecker interface {
+	// Name identifies the checker in GetStatus's components map.
+	Name() string
+	// Critical reports whether a failing Check should make HealthCheck
+	// return 503, rather than only being reflected in GetStatus.
+	Critical() bool
+	// Check performs one cheap liveness probe. Implementations should
+	// respect ctx's deadline rather than blocking past it.
+	Check(ctx context.Context) error
+}
+
+// FuncHealthChecker adapts an existing probe — e.g. aviatrix.Client's
+// own HealthCheck method, or a closure wrapping ClickHouseClient.Ping —
+// into a HealthChecker without every subsystem needing to implement the
+// interface itself.
+type FuncHealthChecker struct {
+	name     string
+	critical bool
+	probe    func(ctx context.Context) error
+}
+
+// NewFuncHealthChecker builds a FuncHealthChecker named name, whose
+// Check calls probe. critical controls whether a probe failure flips
+// HealthCheck's overall status to 503.
+func NewFuncHealthChecker(name string, critical bool, probe func(ctx context.Context) error) *FuncHealthChecker {
+	return &FuncHealthChecker{name: name, critical: critical, probe: probe}
+}
+
+func (f *FuncHealthChecker) Name() string     { return f.name }
+func (f *FuncHealthChecker) Critical() bool   { return f.critical }
+func (f *FuncHealthChecker) Check(ctx context.Context) error {
+	return f.probe(ctx)
+}
+
+// checkResult is the outcome of one HealthChecker's most recent Check.
+type checkResult struct {
+	critical  bool
+	latency   time.Duration
+	err       error
+	checkedAt time.Time
+}
+
 type StatusHandler struct {
-	logger *logrus.Logger
+	logger    *logrus.Logger
+	startTime time.Time
+
+	mu       sync.RWMutex
+	checkers []HealthChecker
+	results  map[string]checkResult
 }
 
 func NewStatusHandler(logger *logrus.Logger) *StatusHandler {
 	return &StatusHandler{
-		logger: logger,
+		logger:    logger,
+		startTime: time.Now(),
+		results:   make(map[string]checkResult),
+	}
+}
+
+// RegisterChecker adds checker to the registry HealthCheck and GetStatus
+// poll. Safe to call concurrently with requests, though it is normally
+// only called during startup, once per subsystem.
+func (h *StatusHandler) RegisterChecker(checker HealthChecker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checkers = append(h.checkers, checker)
+}
+
+// runChecks runs every registered checker concurrently, each bounded by
+// checkTimeout, records the results for GetStatus to report, and
+// returns them for HealthCheck's own use.
+func (h *StatusHandler) runChecks(ctx context.Context) map[string]checkResult {
+	h.mu.RLock()
+	checkers := make([]HealthChecker, len(h.checkers))
+	copy(checkers, h.checkers)
+	h.mu.RUnlock()
+
+	results := make(map[string]checkResult, len(checkers))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, checker := range checkers {
+		wg.Add(1)
+		go func(checker HealthChecker) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := checker.Check(checkCtx)
+
+			mu.Lock()
+			results[checker.Name()] = checkResult{
+				critical:  checker.Critical(),
+				latency:   time.Since(start),
+				err:       err,
+				checkedAt: time.Now(),
+			}
+			mu.Unlock()
+		}(checker)
+	}
+	wg.Wait()
+
+	h.mu.Lock()
+	for name, result := range results {
+		h.results[name] = result
 	}
+	h.mu.Unlock()
+
+	return results
 }
 
-// HealthCheck provides a simple health check endpoint
+// HealthCheck runs every registered HealthChecker and reports 503 if
+// any critical one failed; it is meant for a load balancer or
+// orchestrator's liveness/readiness probe, not for humans (see
+// GetStatus for the latter).
 func (h *StatusHandler) HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":    "healthy",
+	results := h.runChecks(c.Request.Context())
+
+	healthy := true
+	for _, result := range results {
+		if result.critical && result.err != nil {
+			healthy = false
+			break
+		}
+	}
+
+	statusCode := http.StatusOK
+	statusText := "healthy"
+	if !healthy {
+		statusCode = http.StatusServiceUnavailable
+		statusText = "unhealthy"
+	}
+
+	c.JSON(statusCode, gin.H{
+		"status":    statusText,
 		"timestamp": time.Now().UTC(),
 		"service":   "router-sim-api",
 	})
 }
 
-// GetStatus provides detailed system status
+// GetStatus provides detailed system status, including build metadata,
+// real process uptime, and the last-known latency/error of every
+// registered HealthChecker.
 func (h *StatusHandler) GetStatus(c *gin.Context) {
+	results := h.runChecks(c.Request.Context())
+
 	var m runtime.MemStats
 	runtime.ReadMemStats(&m)
 
+	components := gin.H{}
+	for name, result := range results {
+		entry := gin.H{
+			"critical":   result.critical,
+			"latency_ms": result.latency.Milliseconds(),
+			"checked_at": result.checkedAt,
+		}
+		if result.err != nil {
+			entry["status"] = "unhealthy"
+			entry["error"] = result.err.Error()
+		} else {
+			entry["status"] = "healthy"
+		}
+		components[name] = entry
+	}
+
 	status := gin.H{
 		"status":    "running",
 		"timestamp": time.Now().UTC(),
 		"service":   "router-sim-api",
-		"version":   "1.0.0",
-		"uptime":    time.Since(time.Now()).String(), // This would be actual uptime in real implementation
+		"version":   version,
+		"git_sha":   gitSHA,
+		"build_date": buildDate,
+		"uptime":    time.Since(h.startTime).String(),
 		"system": gin.H{
-			"go_version":    runtime.Version(),
-			"go_routines":   runtime.NumGoroutine(),
-			"memory_alloc":  m.Alloc,
-			"memory_total":  m.TotalAlloc,
-			"memory_sys":    m.Sys,
-			"gc_runs":       m.NumGC,
-			"cpu_count":     runtime.NumCPU(),
-		},
-		"components": gin.H{
-			"cloudpods": gin.H{
-				"enabled": true,
-				"status":  "connected",
-			},
-			"aviatrix": gin.H{
-				"enabled": true,
-				"status":  "connected",
-			},
-			"analytics": gin.H{
-				"enabled": true,
-				"status":  "running",
-			},
-			"router": gin.H{
-				"enabled": true,
-				"status":  "running",
-			},
+			"go_version":   runtime.Version(),
+			"go_routines":  runtime.NumGoroutine(),
+			"memory_alloc": m.Alloc,
+			"memory_total": m.TotalAlloc,
+			"memory_sys":   m.Sys,
+			"gc_runs":      m.NumGC,
+			"cpu_count":    runtime.NumCPU(),
 		},
+		"components": components,
 	}
 
 	c.JSON(http.StatusOK, status)
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// streamUpgrader upgrades a stream endpoint to a WebSocket connection for
+// clients that can't consume SSE, the same permissive CheckOrigin
+// cmd/server's own upgrader uses in development.
+var streamUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// wantsWebSocket reports whether c's request asked to upgrade to a
+// WebSocket instead of reading a Server-Sent Events stream.
+func wantsWebSocket(c *gin.Context) bool {
+	return websocket.IsWebSocketUpgrade(c.Request)
+}
+
+// heartbeatInterval is how often an idle stream sends a ping frame so a
+// client (or an intermediate proxy) can tell a quiet connection apart
+// from a dead one.
+const heartbeatInterval = 15 * time.Second
+
+// clientStatus is the in-memory record debugz reports for one connected
+// stream subscriber: how far it has been pushed and what it's watching,
+// mirroring the last-ACKed-version/nonce/resource-name view a service
+// mesh control plane keeps per xDS client.
+type clientStatus struct {
+	ID            string    `json:"id"`
+	Stream        string    `json:"stream"`
+	ConnectedAt   time.Time `json:"connected_at"`
+	Version       int64     `json:"version"`
+	Nonce         string    `json:"nonce"`
+	ResourceNames []string  `json:"resource_names"`
+	Pending       int       `json:"pending"`
+}
+
+// streamRegistry tracks every currently-connected stream subscriber
+// (metrics, events, and scenario watch). It has no persistence beyond
+// the process's lifetime, same as scenario.Bus and analytics.Engine's
+// subscriber maps.
+type streamRegistry struct {
+	mu      sync.Mutex
+	seq     int64
+	clients map[string]*clientStatus
+}
+
+func newStreamRegistry() *streamRegistry {
+	return &streamRegistry{clients: make(map[string]*clientStatus)}
+}
+
+// connect registers a new subscriber to stream and returns its id plus a
+// done func to call (deferred) when the subscriber disconnects.
+func (r *streamRegistry) connect(stream string, resourceNames []string) (id string, done func()) {
+	r.mu.Lock()
+	r.seq++
+	id = fmt.Sprintf("%s-%d", stream, r.seq)
+	r.clients[id] = &clientStatus{
+		ID:            id,
+		Stream:        stream,
+		ConnectedAt:   time.Now(),
+		ResourceNames: resourceNames,
+	}
+	r.mu.Unlock()
+
+	return id, func() {
+		r.mu.Lock()
+		delete(r.clients, id)
+		r.mu.Unlock()
+	}
+}
+
+// ack records that id was just pushed a frame, bumping its version and
+// nonce the way a client ACKing a DiscoveryResponse would.
+func (r *streamRegistry) ack(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c, ok := r.clients[id]; ok {
+		c.Version++
+		c.Nonce = fmt.Sprintf("%d", c.Version)
+	}
+}
+
+// setPending records how many frames are buffered for id but not yet
+// flushed to its connection.
+func (r *streamRegistry) setPending(id string, pending int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c, ok := r.clients[id]; ok {
+		c.Pending = pending
+	}
+}
+
+// snapshot returns a copy of every connected client, optionally filtered
+// to a single stream name ("" returns all of them).
+func (r *streamRegistry) snapshot(stream string) []clientStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]clientStatus, 0, len(r.clients))
+	for _, c := range r.clients {
+		if stream != "" && c.Stream != stream {
+			continue
+		}
+		out = append(out, *c)
+	}
+	return out
+}
+
+// dropOldestQueue is a bounded, non-blocking mailbox: pushing to a full
+// queue discards the oldest buffered item rather than the new one or
+// blocking the publisher, so a slow stream subscriber falls behind on
+// history instead of stalling whatever is feeding it.
+type dropOldestQueue[T any] struct {
+	ch chan T
+}
+
+func newDropOldestQueue[T any](capacity int) *dropOldestQueue[T] {
+	return &dropOldestQueue[T]{ch: make(chan T, capacity)}
+}
+
+func (q *dropOldestQueue[T]) push(v T) {
+	for {
+		select {
+		case q.ch <- v:
+			return
+		default:
+			select {
+			case <-q.ch:
+			default:
+			}
+		}
+	}
+}
+
+// sseWriter sends one SSE frame for event (empty event uses the default
+// "message" type) and flushes it immediately. A non-empty id is sent as
+// the frame's "id:" field, which the browser's EventSource remembers
+// and echoes back as the Last-Event-ID header on reconnect.
+func sseWriter(c *gin.Context, event, id string, data []byte) {
+	if event != "" {
+		fmt.Fprintf(c.Writer, "event: %s\n", event)
+	}
+	if id != "" {
+		fmt.Fprintf(c.Writer, "id: %s\n", id)
+	}
+	fmt.Fprintf(c.Writer, "data: %s\n\n", data)
+	if flusher, ok := c.Writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer is the OTel tracer every request's span is started on, named
+// the same way cloudpods' and aviatrix's tracers are - "router-sim/<pkg>".
+var tracer = otel.Tracer("router-sim/handlers")
+
+// TracingMiddleware starts one OTel span per request. It extracts the
+// W3C Trace Context (traceparent/tracestate) from the incoming request
+// so a call chain that began upstream continues as the same trace
+// instead of starting a new one here, and records the route's :name
+// path param (a gateway name on Aviatrix gateway routes, a connection
+// name on VPC/Site2Cloud connection routes) as a span attribute so a
+// slow request can be traced back to the resource it was about.
+func TracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+
+		ctx, span := tracer.Start(ctx, route, trace.WithAttributes(
+			attribute.String("http.method", c.Request.Method),
+			attribute.String("http.route", route),
+		))
+		defer span.End()
+
+		if name := c.Param("name"); name != "" {
+			if strings.Contains(route, "connection") {
+				span.SetAttributes(attribute.String("connection_name", name))
+			} else {
+				span.SetAttributes(attribute.String("gateway_name", name))
+			}
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		span.SetAttributes(attribute.Int("http.status_code", c.Writer.Status()))
+	}
+}
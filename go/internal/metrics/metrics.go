@@ -0,0 +1,207 @@
+// Package metrics holds the Prometheus collectors shared across
+// router-sim's subsystems (the routing RIB/FIB, the analytics engine,
+// and the cloud integration clients) so a single /metrics scrape and
+// the mock JSON endpoints that predate it can agree on the same
+// numbers, rather than each package inventing its own counters under
+// its own names.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+var (
+	// RoutesTotal is the current number of installed routes, per
+	// protocol - router_routes_total{protocol=}.
+	RoutesTotal = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "router_routes_total",
+		Help: "Current number of installed routes, per protocol.",
+	}, []string{"protocol"})
+
+	// NeighborsUp is the current number of established neighbors/peers,
+	// per protocol - router_neighbors_up{protocol=}.
+	NeighborsUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "router_neighbors_up",
+		Help: "Current number of established neighbors/peers, per protocol.",
+	}, []string{"protocol"})
+
+	// InterfaceUtilization is the current utilization percentage, per
+	// interface - router_interface_utilization{iface=}.
+	InterfaceUtilization = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "router_interface_utilization",
+		Help: "Current interface utilization percentage, per interface.",
+	}, []string{"iface"})
+
+	// PacketsProcessedTotal counts packets the data plane has processed
+	// across every source (routed connections, cloud gateways).
+	PacketsProcessedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "router_packets_processed_total",
+		Help: "Total packets processed.",
+	})
+
+	// PacketsDroppedTotal counts packets dropped before being processed.
+	PacketsDroppedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "router_packets_dropped_total",
+		Help: "Total packets dropped.",
+	})
+
+	// RouteUpdatesTotal counts route table mutations, by operation -
+	// router_route_updates_total{op=add|remove}.
+	RouteUpdatesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "router_route_updates_total",
+		Help: "Total route table updates, by operation.",
+	}, []string{"op"})
+
+	// ProtocolConvergenceSeconds observes how long the FIB took to
+	// settle after a burst of route changes, per protocol -
+	// router_protocol_convergence_seconds{protocol=}.
+	ProtocolConvergenceSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "router_protocol_convergence_seconds",
+		Help:    "Observed FIB convergence time, per protocol.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"protocol"})
+
+	// HTTPRequestDurationSeconds observes request latency, by route,
+	// method, and response status code -
+	// router_http_request_duration_seconds{route=,method=,code=}.
+	HTTPRequestDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "router_http_request_duration_seconds",
+		Help:    "HTTP request duration in seconds, by route, method, and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "code"})
+
+	// HTTPRequestsTotal counts completed requests, by the same route,
+	// method, and status code labels as HTTPRequestDurationSeconds -
+	// router_http_requests_total{route=,method=,code=}.
+	HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "router_http_requests_total",
+		Help: "Total HTTP requests handled, by route, method, and status code.",
+	}, []string{"route", "method", "code"})
+
+	// AviatrixGatewayCount is the current number of Aviatrix gateways,
+	// by gateway type, cloud, and region -
+	// aviatrix_gateway_count{type=,cloud=,region=}.
+	AviatrixGatewayCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aviatrix_gateway_count",
+		Help: "Current number of Aviatrix gateways, by gateway type, cloud, and region.",
+	}, []string{"type", "cloud", "region"})
+
+	// AviatrixTunnelStatus is 1 when an Aviatrix Site2Cloud tunnel is
+	// reporting state, per tunnel name and state -
+	// aviatrix_tunnel_status{name=,state=}.
+	AviatrixTunnelStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aviatrix_tunnel_status",
+		Help: "Last observed status of an Aviatrix Site2Cloud tunnel, 1 for its current state and 0 for every other state.",
+	}, []string{"name", "state"})
+
+	// RoutingTableSize is the current number of routes across every
+	// protocol - routing_table_size. Unlike RoutesTotal, which breaks
+	// the count down per protocol, this is the single number a
+	// dashboard panel titled "routing table size" wants.
+	RoutingTableSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "routing_table_size",
+		Help: "Current total number of installed routes, across every protocol.",
+	})
+
+	// CloudPodsPodCount is the current number of CloudPods VPCs -
+	// cloudpods_pod_count. CloudPods models cloud network resources
+	// (VPCs, subnets, load balancers) rather than Kubernetes pods, so
+	// this counts its top-level VPC resource as the closest per-tenant
+	// unit a "pod count" panel would want.
+	CloudPodsPodCount = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "cloudpods_pod_count",
+		Help: "Current number of CloudPods VPCs.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		RoutesTotal, NeighborsUp, InterfaceUtilization,
+		PacketsProcessedTotal, PacketsDroppedTotal, RouteUpdatesTotal,
+		ProtocolConvergenceSeconds, HTTPRequestDurationSeconds,
+		HTTPRequestsTotal, AviatrixGatewayCount, AviatrixTunnelStatus,
+		RoutingTableSize, CloudPodsPodCount,
+	)
+}
+
+// Handler returns an http.Handler serving every metric registered with
+// the default Prometheus registry, ready to mount at e.g. /metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// GinMiddleware observes HTTPRequestDurationSeconds for every request
+// that passes through it, labeled by the matched route (gin's
+// c.FullPath, e.g. "/routing/routes/:destination" rather than the raw
+// URL), method, and response status code.
+func GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		code := strconv.Itoa(c.Writer.Status())
+		HTTPRequestDurationSeconds.
+			WithLabelValues(route, c.Request.Method, code).
+			Observe(time.Since(start).Seconds())
+		HTTPRequestsTotal.WithLabelValues(route, c.Request.Method, code).Inc()
+	}
+}
+
+// gaugeValue reads g's current value back out, the same technique
+// promhttp itself uses internally to serialize a collector.
+func gaugeValue(g prometheus.Gauge) float64 {
+	var m dto.Metric
+	if err := g.Write(&m); err != nil {
+		return 0
+	}
+	return m.GetGauge().GetValue()
+}
+
+// counterValue reads c's current cumulative value back out.
+func counterValue(c prometheus.Counter) float64 {
+	var m dto.Metric
+	if err := c.Write(&m); err != nil {
+		return 0
+	}
+	return m.GetCounter().GetValue()
+}
+
+// RouteCount returns RoutesTotal's current value for protocol, so
+// callers building a JSON response can agree with what /metrics would
+// report without scraping themselves.
+func RouteCount(protocol string) int64 {
+	return int64(gaugeValue(RoutesTotal.WithLabelValues(protocol)))
+}
+
+// NeighborCount returns NeighborsUp's current value for protocol.
+func NeighborCount(protocol string) int64 {
+	return int64(gaugeValue(NeighborsUp.WithLabelValues(protocol)))
+}
+
+// InterfaceUtilizationValue returns InterfaceUtilization's current value
+// for iface.
+func InterfaceUtilizationValue(iface string) float64 {
+	return gaugeValue(InterfaceUtilization.WithLabelValues(iface))
+}
+
+// PacketsProcessed returns PacketsProcessedTotal's current cumulative
+// value.
+func PacketsProcessed() int64 {
+	return int64(counterValue(PacketsProcessedTotal))
+}
+
+// PacketsDropped returns PacketsDroppedTotal's current cumulative value.
+func PacketsDropped() int64 {
+	return int64(counterValue(PacketsDroppedTotal))
+}
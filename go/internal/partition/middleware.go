@@ -0,0 +1,102 @@
+package partition
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderName is the header a caller sets to choose a partition other
+// than DefaultPartition.
+const HeaderName = "X-Router-Partition"
+
+// SubjectHeaderName identifies the caller Authorize checks bindings
+// against; absent a real authentication layer, callers that don't set
+// it are treated as authorized (see MemoryStore.Authorize).
+const SubjectHeaderName = "X-Router-Subject"
+
+type contextKey int
+
+const partitionKey contextKey = iota
+
+// FromContext returns the partition name ctx carries, and whether one
+// was set at all.
+func FromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(partitionKey).(string)
+	return name, ok
+}
+
+// WithPartition returns a copy of ctx carrying name.
+func WithPartition(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, partitionKey, name)
+}
+
+// Middleware resolves the partition a request is scoped to — from the
+// X-Router-Partition header, falling back to a "partition" query or path
+// parameter, and finally DefaultPartition — rejects requests naming a
+// partition store does not know about, and rejects requests whose
+// X-Router-Subject lacks a binding in that partition, then injects the
+// resolved name into the request's Context so handlers and downstream
+// clients can read it with FromContext.
+func Middleware(store Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.GetHeader(HeaderName)
+		if name == "" {
+			name = c.Query("partition")
+		}
+		if name == "" {
+			name = c.Param("partition")
+		}
+		if name == "" {
+			name = DefaultPartition
+		}
+
+		if !store.Exists(name) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "unknown partition: " + name})
+			return
+		}
+
+		if subject := c.GetHeader(SubjectHeaderName); subject != "" {
+			if !store.Authorize(name, subject, RoleViewer) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "subject not authorized for partition " + name})
+				return
+			}
+		}
+
+		c.Request = c.Request.WithContext(WithPartition(c.Request.Context(), name))
+		c.Next()
+	}
+}
+
+// ValidateResourceName refuses name when it is namespaced to a
+// partition (a "<partition>-" prefix) other than the caller's own,
+// preventing a deploy call in one partition from touching a resource
+// that belongs to another. Whatever precedes the first "-" is taken as
+// the owning partition; it is not checked against the partitions that
+// exist, so any hyphenated name whose first segment differs from
+// callerPartition is refused.
+func ValidateResourceName(callerPartition, name string) error {
+	idx := strings.Index(name, "-")
+	if idx <= 0 {
+		return nil
+	}
+	prefix := name[:idx]
+	if prefix != callerPartition {
+		return &CrossPartitionError{Name: name, Caller: callerPartition, Owner: prefix}
+	}
+	return nil
+}
+
+// CrossPartitionError reports that a resource name belongs to a
+// partition other than the one making the request.
+type CrossPartitionError struct {
+	Name   string
+	Caller string
+	Owner  string
+}
+
+func (e *CrossPartitionError) Error() string {
+	return "partition: resource \"" + e.Name + "\" belongs to partition \"" + e.Owner + "\", not the caller's \"" + e.Caller + "\""
+}
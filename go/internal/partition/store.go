@@ -0,0 +1,119 @@
+package partition
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Store holds every Record the server knows about, keyed by partition
+// name.
+type Store interface {
+	// Create admits a new Partition with the given bindings, failing if
+	// name is already taken.
+	Create(name, description string, bindings []RoleBinding) (*Record, error)
+	// Get returns the Record for name, or an error if it does not exist.
+	Get(name string) (*Record, error)
+	// List returns every admitted Record.
+	List() []*Record
+	// Exists reports whether name has been created.
+	Exists(name string) bool
+	// Authorize reports whether subject holds at least role within name.
+	// An empty subject is always authorized, since not every deployment
+	// has an authentication layer in front of this server yet.
+	Authorize(name, subject string, role Role) bool
+}
+
+// MemoryStore is an in-memory Store seeded with DefaultPartition so a
+// single-tenant deployment needs no setup.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	records map[string]*Record
+}
+
+// NewMemoryStore returns a MemoryStore pre-seeded with DefaultPartition.
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{records: make(map[string]*Record)}
+	s.records[DefaultPartition] = &Record{
+		Partition: Partition{Name: DefaultPartition, CreatedAt: time.Now()},
+	}
+	return s
+}
+
+// Create implements Store.
+func (s *MemoryStore) Create(name, description string, bindings []RoleBinding) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.records[name]; exists {
+		return nil, fmt.Errorf("partition: %q already exists", name)
+	}
+
+	record := &Record{
+		Partition: Partition{Name: name, Description: description, CreatedAt: time.Now()},
+		Bindings:  bindings,
+	}
+	s.records[name] = record
+	return record, nil
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(name string) (*Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record, ok := s.records[name]
+	if !ok {
+		return nil, fmt.Errorf("partition: %q not found", name)
+	}
+	return record, nil
+}
+
+// List implements Store.
+func (s *MemoryStore) List() []*Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]*Record, 0, len(s.records))
+	for _, record := range s.records {
+		out = append(out, record)
+	}
+	return out
+}
+
+// Exists implements Store.
+func (s *MemoryStore) Exists(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.records[name]
+	return ok
+}
+
+// Authorize implements Store.
+func (s *MemoryStore) Authorize(name, subject string, role Role) bool {
+	if subject == "" {
+		return true
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	record, ok := s.records[name]
+	if !ok {
+		return false
+	}
+	if len(record.Bindings) == 0 {
+		return true
+	}
+	for _, binding := range record.Bindings {
+		if binding.Subject != subject {
+			continue
+		}
+		if binding.Role == role || binding.Role == RoleAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+var _ Store = (*MemoryStore)(nil)
@@ -0,0 +1,41 @@
+// Package partition adds multi-tenant "admin partition" scoping to the
+// server: every request carries a partition name (a header, query, or
+// path parameter), resources created under one partition are tagged
+// with it, and handlers refuse to return or mutate another partition's
+// resources. This mirrors the namespace/tenant isolation service
+// meshes use to let unrelated tenants share one control plane.
+package partition
+
+import "time"
+
+// DefaultPartition is the partition name assumed when a request carries
+// none, so single-tenant deployments need no configuration.
+const DefaultPartition = "default"
+
+// Partition is one isolated tenant boundary.
+type Partition struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Role is the access level a RoleBinding grants within a Partition.
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleViewer Role = "viewer"
+)
+
+// RoleBinding grants Subject Role within the Partition it's attached to.
+type RoleBinding struct {
+	Subject string `json:"subject"`
+	Role    Role   `json:"role"`
+}
+
+// Record is a Partition together with the RBAC bindings controlling who
+// may act within it.
+type Record struct {
+	Partition Partition     `json:"partition"`
+	Bindings  []RoleBinding `json:"bindings,omitempty"`
+}
@@ -0,0 +1,186 @@
+package routing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FIBEntry is one best-path forwarding table entry Reconciler computed
+// from the RIB's candidate set for a prefix - what a real router's
+// forwarding plane would program, as opposed to the RIB's full
+// candidate set.
+type FIBEntry struct {
+	Prefix  string
+	Route   Route
+	Updated time.Time
+}
+
+// debounceWindow is how long Reconciler waits after the first dirty
+// notification of a burst before recomputing; notifications arriving
+// within the window do not push the recompute back. A flurry of AddRoute
+// calls (e.g. a BGP peer replaying its whole table) thus converges once
+// per window instead of once per route.
+const debounceWindow = 50 * time.Millisecond
+
+// Reconciler recomputes the FIB - the best path per prefix - from a RIB
+// whenever MarkDirty notifies it of a changed prefix, debouncing a burst
+// of changes into one recompute per prefix rather than one per write. It
+// measures convergence time as the wall-clock gap between the first
+// change of a burst and the FIB settling back to quiescent, and
+// publishes FIBEntry changes on Bus.
+type Reconciler struct {
+	rib *RIB
+	bus *Bus
+
+	dirty chan string
+
+	mu      sync.Mutex
+	fib     map[string]FIBEntry
+	pending map[string]struct{}
+
+	burstStart   time.Time
+	lastConverge time.Duration
+}
+
+// NewReconciler wires rib and bus into a Reconciler; call Run in its own
+// goroutine to start recomputing.
+func NewReconciler(rib *RIB, bus *Bus) *Reconciler {
+	return &Reconciler{
+		rib:     rib,
+		bus:     bus,
+		fib:     make(map[string]FIBEntry),
+		pending: make(map[string]struct{}),
+		dirty:   make(chan string, 256),
+	}
+}
+
+// MarkDirty notifies the Reconciler that prefix's candidate set changed;
+// Manager calls this after every RIB mutation.
+func (rc *Reconciler) MarkDirty(prefix string) {
+	select {
+	case rc.dirty <- prefix:
+	default:
+		// Dirty channel full; add prefix to the pending set directly. The
+		// notifications still queued ahead of it arm the debounce timer,
+		// so recompute will still pick prefix up once drained.
+		rc.mu.Lock()
+		rc.pending[prefix] = struct{}{}
+		rc.mu.Unlock()
+	}
+}
+
+// Run drives the debounce/recompute loop until ctx is canceled.
+func (rc *Reconciler) Run(ctx context.Context) {
+	timer := time.NewTimer(debounceWindow)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	timerSet := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case prefix := <-rc.dirty:
+			rc.mu.Lock()
+			if len(rc.pending) == 0 {
+				rc.burstStart = time.Now()
+			}
+			rc.pending[prefix] = struct{}{}
+			rc.mu.Unlock()
+
+			if !timerSet {
+				timer.Reset(debounceWindow)
+				timerSet = true
+			}
+		case <-timer.C:
+			timerSet = false
+			rc.recompute()
+		}
+	}
+}
+
+// recompute resolves every pending prefix's best path from the RIB,
+// publishing an Event for any prefix whose best path actually changed,
+// and records the burst's convergence time once nothing remains
+// pending.
+func (rc *Reconciler) recompute() {
+	rc.mu.Lock()
+	prefixes := make([]string, 0, len(rc.pending))
+	for prefix := range rc.pending {
+		prefixes = append(prefixes, prefix)
+	}
+	rc.pending = make(map[string]struct{})
+	burstStart := rc.burstStart
+	rc.mu.Unlock()
+
+	now := time.Now()
+	for _, prefix := range prefixes {
+		candidates, _ := rc.rib.Candidates(prefix)
+
+		rc.mu.Lock()
+		old, existed := rc.fib[prefix]
+
+		if len(candidates) == 0 {
+			delete(rc.fib, prefix)
+			rc.mu.Unlock()
+			if existed {
+				rc.bus.Publish(Event{Type: EventDeleted, Prefix: prefix, Route: old.Route})
+			}
+			continue
+		}
+
+		best := candidates[0]
+		for _, candidate := range candidates[1:] {
+			if less(candidate, best) {
+				best = candidate
+			}
+		}
+		unchanged := existed && old.Route == best
+		rc.fib[prefix] = FIBEntry{Prefix: prefix, Route: best, Updated: now}
+		rc.mu.Unlock()
+
+		if unchanged {
+			continue
+		}
+		eventType := EventAdded
+		if existed {
+			eventType = EventModified
+		}
+		rc.bus.Publish(Event{Type: eventType, Prefix: prefix, Route: best})
+	}
+
+	if !burstStart.IsZero() {
+		rc.mu.Lock()
+		rc.lastConverge = now.Sub(burstStart)
+		rc.mu.Unlock()
+	}
+}
+
+// Best returns the FIB's current best path for prefix, if any.
+func (rc *Reconciler) Best(prefix string) (FIBEntry, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry, ok := rc.fib[prefix]
+	return entry, ok
+}
+
+// All returns every FIBEntry currently installed.
+func (rc *Reconciler) All() []FIBEntry {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	out := make([]FIBEntry, 0, len(rc.fib))
+	for _, entry := range rc.fib {
+		out = append(out, entry)
+	}
+	return out
+}
+
+// ConvergenceTime returns how long the most recently completed burst of
+// RIB changes took to settle into a quiescent FIB.
+func (rc *Reconciler) ConvergenceTime() time.Duration {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.lastConverge
+}
@@ -0,0 +1,131 @@
+package routing
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newTestReconciler wires a running Reconciler around a fresh RIB/Bus
+// pair and stops it when the test ends.
+func newTestReconciler(t *testing.T) (*Reconciler, *RIB, *Bus) {
+	t.Helper()
+	rib := NewRIB()
+	bus := NewBus()
+	rc := NewReconciler(rib, bus)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go rc.Run(ctx)
+	t.Cleanup(cancel)
+
+	return rc, rib, bus
+}
+
+// waitForBest polls rc.Best(prefix) until it matches want or the test
+// deadline passes, since recompute happens asynchronously after the
+// debounce window.
+func waitForBest(t *testing.T, rc *Reconciler, prefix string) FIBEntry {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if entry, ok := rc.Best(prefix); ok {
+			return entry
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("Best(%q) never settled within the deadline", prefix)
+	return FIBEntry{}
+}
+
+func TestReconcilerPrefersLowerAdminDistance(t *testing.T) {
+	rc, rib, _ := newTestReconciler(t)
+
+	mustAddRoute(t, rib, Route{Prefix: "10.0.0.0/24", NextHop: "1.1.1.1", Protocol: ProtocolOSPF})
+	rc.MarkDirty("10.0.0.0/24")
+	mustAddRoute(t, rib, Route{Prefix: "10.0.0.0/24", NextHop: "2.2.2.2", Protocol: ProtocolStatic})
+	rc.MarkDirty("10.0.0.0/24")
+
+	entry := waitForBest(t, rc, "10.0.0.0/24")
+	if entry.Route.Protocol != ProtocolStatic {
+		t.Fatalf("Best().Route.Protocol = %q, want %q (lower admin distance)", entry.Route.Protocol, ProtocolStatic)
+	}
+}
+
+func TestReconcilerPrefersLowerMetricWithinSameProtocol(t *testing.T) {
+	rc, rib, _ := newTestReconciler(t)
+
+	mustAddRoute(t, rib, Route{Prefix: "10.0.0.0/24", NextHop: "1.1.1.1", Protocol: ProtocolBGP, Metric: 50})
+	mustAddRoute(t, rib, Route{Prefix: "10.0.0.0/24", NextHop: "2.2.2.2", Protocol: ProtocolBGP, Metric: 10})
+	rc.MarkDirty("10.0.0.0/24")
+
+	entry := waitForBest(t, rc, "10.0.0.0/24")
+	if entry.Route.NextHop != "2.2.2.2" {
+		t.Fatalf("Best().Route.NextHop = %q, want the lower-metric candidate 2.2.2.2", entry.Route.NextHop)
+	}
+}
+
+func TestReconcilerPublishesAddedModifiedDeleted(t *testing.T) {
+	rc, rib, bus := newTestReconciler(t)
+
+	events := make(chan Event, 8)
+	bus.Subscribe(events)
+	defer bus.Unsubscribe(events)
+
+	mustAddRoute(t, rib, Route{Prefix: "10.0.0.0/24", NextHop: "1.1.1.1", Protocol: ProtocolStatic, Metric: 10})
+	rc.MarkDirty("10.0.0.0/24")
+
+	if ev := nextEvent(t, events); ev.Type != EventAdded {
+		t.Fatalf("first event type = %q, want %q", ev.Type, EventAdded)
+	}
+
+	mustAddRoute(t, rib, Route{Prefix: "10.0.0.0/24", NextHop: "2.2.2.2", Protocol: ProtocolStatic, Metric: 1})
+	rc.MarkDirty("10.0.0.0/24")
+
+	if ev := nextEvent(t, events); ev.Type != EventModified || ev.Route.NextHop != "2.2.2.2" {
+		t.Fatalf("second event = %+v, want a MODIFIED event onto the lower-metric candidate", ev)
+	}
+
+	if err := rib.RemoveRoute("10.0.0.0/24", ProtocolStatic, "1.1.1.1"); err != nil {
+		t.Fatalf("RemoveRoute: %v", err)
+	}
+	if err := rib.RemoveRoute("10.0.0.0/24", ProtocolStatic, "2.2.2.2"); err != nil {
+		t.Fatalf("RemoveRoute: %v", err)
+	}
+	rc.MarkDirty("10.0.0.0/24")
+
+	if ev := nextEvent(t, events); ev.Type != EventDeleted {
+		t.Fatalf("third event type = %q, want %q", ev.Type, EventDeleted)
+	}
+
+	if _, ok := rc.Best("10.0.0.0/24"); ok {
+		t.Fatalf("Best() still returns an entry after every candidate was removed")
+	}
+}
+
+func nextEvent(t *testing.T, events chan Event) Event {
+	t.Helper()
+	select {
+	case ev := <-events:
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for a Bus event")
+		return Event{}
+	}
+}
+
+func TestReconcilerAllReturnsEveryInstalledPrefix(t *testing.T) {
+	rc, rib, _ := newTestReconciler(t)
+
+	mustAddRoute(t, rib, Route{Prefix: "10.0.0.0/24", NextHop: "1.1.1.1", Protocol: ProtocolStatic})
+	mustAddRoute(t, rib, Route{Prefix: "192.168.0.0/16", NextHop: "2.2.2.2", Protocol: ProtocolStatic})
+	rc.MarkDirty("10.0.0.0/24")
+	rc.MarkDirty("192.168.0.0/16")
+
+	waitForBest(t, rc, "10.0.0.0/24")
+	waitForBest(t, rc, "192.168.0.0/16")
+
+	all := rc.All()
+	if len(all) != 2 {
+		t.Fatalf("All() = %d entries, want 2", len(all))
+	}
+}
@@ -0,0 +1,200 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Manager is the entry point handlers.RoutingHandler wires against: it
+// owns the RIB, the FIB Reconciler, a Store for persisting routes across
+// restarts, and every running protocol's simulated adjacency session.
+type Manager struct {
+	rib   *RIB
+	fib   *Reconciler
+	bus   *Bus
+	store Store
+
+	mu        sync.Mutex
+	protocols map[Protocol]ProtocolState
+	cancel    map[Protocol]context.CancelFunc
+}
+
+// NewManager wires a RIB, a Reconciler driven by a fresh Bus, and store
+// into a Manager, replaying whatever routes store already has (e.g.
+// after a restart) into the RIB, and starts the Reconciler's recompute
+// loop running in the background until ctx is canceled.
+func NewManager(ctx context.Context, store Store) (*Manager, error) {
+	rib := NewRIB()
+	bus := NewBus()
+	fib := NewReconciler(rib, bus)
+
+	m := &Manager{
+		rib:       rib,
+		fib:       fib,
+		bus:       bus,
+		store:     store,
+		protocols: make(map[Protocol]ProtocolState),
+		cancel:    make(map[Protocol]context.CancelFunc),
+	}
+
+	existing, err := store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("routing: loading persisted routes: %w", err)
+	}
+	for _, route := range existing {
+		if err := rib.AddRoute(route); err != nil {
+			return nil, fmt.Errorf("routing: replaying persisted route %q: %w", route.Prefix, err)
+		}
+		fib.MarkDirty(route.Prefix)
+	}
+
+	go fib.Run(ctx)
+
+	return m, nil
+}
+
+// AddRoute inserts route into the RIB and persists it to Store. route's
+// Protocol defaults to ProtocolStatic when unset. The FIB recomputes
+// asynchronously; use Watch to observe when the resulting best path
+// lands.
+func (m *Manager) AddRoute(ctx context.Context, route Route) error {
+	if route.Protocol == "" {
+		route.Protocol = ProtocolStatic
+	}
+	route.UpdatedAt = time.Now()
+
+	if err := m.rib.AddRoute(route); err != nil {
+		return err
+	}
+	if err := m.store.Put(ctx, route); err != nil {
+		return fmt.Errorf("routing: persisting route %q: %w", route.Prefix, err)
+	}
+	m.fib.MarkDirty(route.Prefix)
+	return nil
+}
+
+// RemoveRoute removes protocol's candidate for nextHop from prefix's
+// candidate set and its persisted copy.
+func (m *Manager) RemoveRoute(ctx context.Context, prefix string, protocol Protocol, nextHop string) error {
+	if err := m.rib.RemoveRoute(prefix, protocol, nextHop); err != nil {
+		return err
+	}
+	if err := m.store.Delete(ctx, prefix, protocol, nextHop); err != nil {
+		return fmt.Errorf("routing: deleting persisted route %q: %w", prefix, err)
+	}
+	m.fib.MarkDirty(prefix)
+	return nil
+}
+
+// Candidates returns every candidate Route registered for prefix,
+// regardless of which one the FIB picked as best path.
+func (m *Manager) Candidates(prefix string) ([]Route, error) {
+	return m.rib.Candidates(prefix)
+}
+
+// Routes returns every prefix's current best path - the FIB's contents.
+func (m *Manager) Routes() []FIBEntry {
+	return m.fib.All()
+}
+
+// Lookup performs a longest-prefix match for addr against the RIB's
+// full candidate set (not just the FIB's best path).
+func (m *Manager) Lookup(addr string) ([]Route, bool) {
+	return m.rib.Lookup(addr)
+}
+
+// Stats summarizes the RIB/FIB for GetRoutingStats: a per-protocol
+// route count and the most recently observed convergence time.
+type Stats struct {
+	TotalRoutes     int
+	ByProtocol      map[Protocol]int
+	ConvergenceTime time.Duration
+}
+
+// Stats computes a Stats snapshot from the FIB's current contents.
+func (m *Manager) Stats() Stats {
+	entries := m.fib.All()
+	stats := Stats{ByProtocol: make(map[Protocol]int), ConvergenceTime: m.fib.ConvergenceTime()}
+	for _, entry := range entries {
+		stats.TotalRoutes++
+		stats.ByProtocol[entry.Route.Protocol]++
+	}
+	return stats
+}
+
+// Protocols returns every known protocol's current lifecycle state,
+// including ones never started (reported as not Running).
+func (m *Manager) Protocols() []ProtocolState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]ProtocolState, 0, len(protocolSeeds))
+	for protocol := range protocolSeeds {
+		if state, ok := m.protocols[protocol]; ok {
+			out = append(out, state)
+			continue
+		}
+		out = append(out, ProtocolState{Protocol: protocol})
+	}
+	return out
+}
+
+// StartProtocol launches protocol's simulated adjacency session,
+// injecting its seed routes into the RIB; a no-op if protocol is
+// already running. It returns an error if protocol isn't one this
+// package knows how to run (e.g. "static", which has no adjacency
+// session of its own).
+func (m *Manager) StartProtocol(protocol Protocol) error {
+	if _, ok := protocolSeeds[protocol]; !ok {
+		return fmt.Errorf("routing: %q has no protocol session to start", protocol)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if state, ok := m.protocols[protocol]; ok && state.Running {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel[protocol] = cancel
+	m.protocols[protocol] = ProtocolState{
+		Protocol:  protocol,
+		Running:   true,
+		Peers:     protocolSeeds[protocol].peers,
+		StartedAt: time.Now(),
+	}
+
+	go runProtocol(ctx, protocol, m.rib, m.fib.MarkDirty)
+	return nil
+}
+
+// StopProtocol cancels protocol's running adjacency session, withdrawing
+// its seed routes from the RIB; a no-op if it isn't running.
+func (m *Manager) StopProtocol(protocol Protocol) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cancel, ok := m.cancel[protocol]
+	if !ok {
+		return nil
+	}
+	cancel()
+	delete(m.cancel, protocol)
+	m.protocols[protocol] = ProtocolState{Protocol: protocol, Running: false}
+	return nil
+}
+
+// Watch registers ch to receive FIB change Events published from this
+// call onward (an Event that finds ch's buffer full is dropped for ch);
+// call Unwatch with the same channel when the caller stops listening.
+func (m *Manager) Watch(ch chan Event) {
+	m.bus.Subscribe(ch)
+}
+
+// Unwatch removes ch registered with Watch.
+func (m *Manager) Unwatch(ch chan Event) {
+	m.bus.Unsubscribe(ch)
+}
@@ -0,0 +1,73 @@
+package routing
+
+import (
+	"context"
+	"time"
+)
+
+// ProtocolState is what Protocols/StartProtocol/StopProtocol report
+// about one protocol's running (or stopped) adjacency session.
+type ProtocolState struct {
+	Protocol  Protocol
+	Running   bool
+	Peers     int // BGP peers, OSPF neighbors, or IS-IS adjacencies, depending on Protocol
+	StartedAt time.Time
+}
+
+// protocolSeed is the synthetic adjacency/route data a protocol's
+// simulated session injects into the RIB once "established". This
+// package simulates protocol sessions rather than speaking real
+// BGP/OSPF/IS-IS on the wire - the same level of fidelity router-sim's
+// other protocol handlers operate at.
+type protocolSeed struct {
+	peers  int
+	routes []Route
+}
+
+var protocolSeeds = map[Protocol]protocolSeed{
+	ProtocolBGP: {
+		peers: 3,
+		routes: []Route{
+			{Prefix: "10.0.0.0/8", NextHop: "192.168.1.2", Protocol: ProtocolBGP, Metric: 0, Tag: "peer-192.168.1.2"},
+			{Prefix: "203.0.113.0/24", NextHop: "192.168.1.5", Protocol: ProtocolBGP, Metric: 0, Tag: "peer-192.168.1.5"},
+		},
+	},
+	ProtocolOSPF: {
+		peers: 12,
+		routes: []Route{
+			{Prefix: "172.16.0.0/12", NextHop: "192.168.1.3", Protocol: ProtocolOSPF, Metric: 10, Tag: "area-0.0.0.0"},
+		},
+	},
+	ProtocolISIS: {
+		peers: 6,
+		routes: []Route{
+			{Prefix: "192.168.0.0/16", NextHop: "192.168.1.4", Protocol: ProtocolISIS, Metric: 5, Tag: "level-2"},
+		},
+	},
+}
+
+// runProtocol simulates protocol's adjacency session: it injects
+// protocolSeeds[protocol]'s routes into rib as if every peer/neighbor
+// had just come up, marking each touched prefix dirty so Reconciler
+// recomputes the FIB, then blocks until ctx is canceled (StopProtocol)
+// and withdraws them again.
+func runProtocol(ctx context.Context, protocol Protocol, rib *RIB, markDirty func(string)) {
+	seed, ok := protocolSeeds[protocol]
+	if !ok {
+		<-ctx.Done()
+		return
+	}
+
+	for _, route := range seed.routes {
+		route.UpdatedAt = time.Now()
+		_ = rib.AddRoute(route)
+		markDirty(route.Prefix)
+	}
+
+	<-ctx.Done()
+
+	for _, route := range seed.routes {
+		_ = rib.RemoveRoute(route.Prefix, route.Protocol, route.NextHop)
+		markDirty(route.Prefix)
+	}
+}
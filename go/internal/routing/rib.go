@@ -0,0 +1,207 @@
+package routing
+
+import (
+	"fmt"
+	"net"
+	"sync"
+)
+
+// ribNode is one node of the RIB's binary (radix) trie, one bit of
+// prefix per level: children[bit] descends toward longer prefixes, and
+// routes (when non-nil) holds every candidate Route currently
+// registered for the prefix ending at this exact node.
+type ribNode struct {
+	children [2]*ribNode
+	routes   map[routeKey]Route
+}
+
+// RIB is an in-memory longest-prefix-match routing information base: a
+// binary trie over each address family's prefix bits, each node holding
+// every protocol's candidate Route for that exact prefix. AddRoute and
+// RemoveRoute mutate it directly; best-path selection and FIB
+// recomputation happen separately in Reconciler, so a burst of RIB
+// writes doesn't recompute the FIB once per write.
+type RIB struct {
+	mu   sync.RWMutex
+	ipv4 *ribNode
+	ipv6 *ribNode
+}
+
+// NewRIB returns an empty RIB.
+func NewRIB() *RIB {
+	return &RIB{ipv4: &ribNode{}, ipv6: &ribNode{}}
+}
+
+// parsePrefix parses prefix as a CIDR, returning its address bytes
+// (4 for IPv4, 16 for IPv6) and prefix length.
+func parsePrefix(prefix string) (net.IP, int, error) {
+	ip, ipNet, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return nil, 0, fmt.Errorf("routing: invalid prefix %q: %w", prefix, err)
+	}
+	length, _ := ipNet.Mask.Size()
+	if v4 := ip.To4(); v4 != nil {
+		return v4, length, nil
+	}
+	return ip.To16(), length, nil
+}
+
+func bitAt(ip net.IP, i int) int {
+	return int(ip[i/8]>>(7-uint(i%8))) & 1
+}
+
+func setBitAt(ip net.IP, i, v int) {
+	if v == 1 {
+		ip[i/8] |= 1 << (7 - uint(i%8))
+	}
+}
+
+func (r *RIB) rootFor(ip net.IP) *ribNode {
+	if len(ip) == net.IPv4len {
+		return r.ipv4
+	}
+	return r.ipv6
+}
+
+// AddRoute inserts route into the RIB, adding it to whatever candidate
+// set already exists for route.Prefix (keyed by protocol+next hop, so a
+// second route from the same protocol to a different next hop is an
+// ECMP candidate rather than a replacement).
+func (r *RIB) AddRoute(route Route) error {
+	ip, length, err := parsePrefix(route.Prefix)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	node := r.rootFor(ip)
+	for i := 0; i < length; i++ {
+		b := bitAt(ip, i)
+		if node.children[b] == nil {
+			node.children[b] = &ribNode{}
+		}
+		node = node.children[b]
+	}
+	if node.routes == nil {
+		node.routes = make(map[routeKey]Route)
+	}
+	node.routes[routeKey{Protocol: route.Protocol, NextHop: route.NextHop}] = route
+	return nil
+}
+
+// RemoveRoute removes protocol's candidate for nextHop from prefix's
+// candidate set; a no-op if it isn't present.
+func (r *RIB) RemoveRoute(prefix string, protocol Protocol, nextHop string) error {
+	ip, length, err := parsePrefix(prefix)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	node := r.rootFor(ip)
+	for i := 0; i < length; i++ {
+		b := bitAt(ip, i)
+		if node.children[b] == nil {
+			return nil
+		}
+		node = node.children[b]
+	}
+	delete(node.routes, routeKey{Protocol: protocol, NextHop: nextHop})
+	return nil
+}
+
+// Candidates returns every candidate Route registered for prefix's
+// exact node (not a longest-prefix-match lookup - see Lookup for that).
+func (r *RIB) Candidates(prefix string) ([]Route, error) {
+	ip, length, err := parsePrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	node := r.rootFor(ip)
+	for i := 0; i < length; i++ {
+		b := bitAt(ip, i)
+		if node.children[b] == nil {
+			return nil, nil
+		}
+		node = node.children[b]
+	}
+	return routesOf(node), nil
+}
+
+// Lookup performs a longest-prefix match for addr (a bare IP address,
+// not a CIDR), returning every candidate Route at the most specific
+// prefix that covers it.
+func (r *RIB) Lookup(addr string) ([]Route, bool) {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil, false
+	}
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	} else {
+		ip = ip.To16()
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	node := r.rootFor(ip)
+	var best *ribNode
+	if len(node.routes) > 0 {
+		best = node
+	}
+	for i := 0; i < len(ip)*8 && node != nil; i++ {
+		node = node.children[bitAt(ip, i)]
+		if node != nil && len(node.routes) > 0 {
+			best = node
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+	return routesOf(best), true
+}
+
+// Walk calls fn once for every distinct prefix the RIB currently holds
+// at least one candidate Route for, in no particular order.
+func (r *RIB) Walk(fn func(prefix string, routes []Route)) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	walkNode(r.ipv4, make(net.IP, net.IPv4len), 0, fn)
+	walkNode(r.ipv6, make(net.IP, net.IPv6len), 0, fn)
+}
+
+func walkNode(node *ribNode, addr net.IP, depth int, fn func(prefix string, routes []Route)) {
+	if node == nil {
+		return
+	}
+	if len(node.routes) > 0 {
+		network := addr.Mask(net.CIDRMask(depth, len(addr)*8))
+		fn(fmt.Sprintf("%s/%d", network.String(), depth), routesOf(node))
+	}
+	for b := 0; b < 2; b++ {
+		if node.children[b] == nil {
+			continue
+		}
+		next := make(net.IP, len(addr))
+		copy(next, addr)
+		setBitAt(next, depth, b)
+		walkNode(node.children[b], next, depth+1, fn)
+	}
+}
+
+func routesOf(node *ribNode) []Route {
+	out := make([]Route, 0, len(node.routes))
+	for _, route := range node.routes {
+		out = append(out, route)
+	}
+	return out
+}
@@ -0,0 +1,125 @@
+package routing
+
+import "testing"
+
+func mustAddRoute(t *testing.T, rib *RIB, route Route) {
+	t.Helper()
+	if err := rib.AddRoute(route); err != nil {
+		t.Fatalf("AddRoute(%+v): %v", route, err)
+	}
+}
+
+func TestRIBLookupLongestPrefixMatch(t *testing.T) {
+	rib := NewRIB()
+	mustAddRoute(t, rib, Route{Prefix: "10.0.0.0/8", NextHop: "1.1.1.1", Protocol: ProtocolStatic})
+	mustAddRoute(t, rib, Route{Prefix: "10.1.0.0/16", NextHop: "2.2.2.2", Protocol: ProtocolStatic})
+
+	routes, ok := rib.Lookup("10.1.2.3")
+	if !ok {
+		t.Fatalf("Lookup(10.1.2.3) = not found, want a match")
+	}
+	if len(routes) != 1 || routes[0].Prefix != "10.1.0.0/16" {
+		t.Fatalf("Lookup(10.1.2.3) = %+v, want the /16 candidate set", routes)
+	}
+
+	routes, ok = rib.Lookup("10.2.0.1")
+	if !ok {
+		t.Fatalf("Lookup(10.2.0.1) = not found, want a match")
+	}
+	if len(routes) != 1 || routes[0].Prefix != "10.0.0.0/8" {
+		t.Fatalf("Lookup(10.2.0.1) = %+v, want the /8 candidate set", routes)
+	}
+
+	if _, ok := rib.Lookup("172.16.0.1"); ok {
+		t.Fatalf("Lookup(172.16.0.1) = found, want no match outside either prefix")
+	}
+}
+
+func TestRIBLookupIPv6(t *testing.T) {
+	rib := NewRIB()
+	mustAddRoute(t, rib, Route{Prefix: "2001:db8::/32", NextHop: "fe80::1", Protocol: ProtocolBGP})
+
+	routes, ok := rib.Lookup("2001:db8::1")
+	if !ok || len(routes) != 1 {
+		t.Fatalf("Lookup(2001:db8::1) = %+v, %v, want a single match", routes, ok)
+	}
+
+	if _, ok := rib.Lookup("2001:db9::1"); ok {
+		t.Fatalf("Lookup(2001:db9::1) = found, want no match")
+	}
+}
+
+func TestRIBAddRouteECMP(t *testing.T) {
+	rib := NewRIB()
+	mustAddRoute(t, rib, Route{Prefix: "10.0.0.0/24", NextHop: "1.1.1.1", Protocol: ProtocolBGP})
+	mustAddRoute(t, rib, Route{Prefix: "10.0.0.0/24", NextHop: "2.2.2.2", Protocol: ProtocolBGP})
+
+	candidates, err := rib.Candidates("10.0.0.0/24")
+	if err != nil {
+		t.Fatalf("Candidates: %v", err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("Candidates() = %d routes, want 2 ECMP candidates from the same protocol", len(candidates))
+	}
+}
+
+func TestRIBAddRouteReplacesSameProtocolAndNextHop(t *testing.T) {
+	rib := NewRIB()
+	mustAddRoute(t, rib, Route{Prefix: "10.0.0.0/24", NextHop: "1.1.1.1", Protocol: ProtocolBGP, Metric: 10})
+	mustAddRoute(t, rib, Route{Prefix: "10.0.0.0/24", NextHop: "1.1.1.1", Protocol: ProtocolBGP, Metric: 20})
+
+	candidates, err := rib.Candidates("10.0.0.0/24")
+	if err != nil {
+		t.Fatalf("Candidates: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].Metric != 20 {
+		t.Fatalf("Candidates() = %+v, want the single updated candidate with metric 20", candidates)
+	}
+}
+
+func TestRIBRemoveRoute(t *testing.T) {
+	rib := NewRIB()
+	mustAddRoute(t, rib, Route{Prefix: "10.0.0.0/24", NextHop: "1.1.1.1", Protocol: ProtocolBGP})
+
+	if err := rib.RemoveRoute("10.0.0.0/24", ProtocolBGP, "1.1.1.1"); err != nil {
+		t.Fatalf("RemoveRoute: %v", err)
+	}
+
+	if _, ok := rib.Lookup("10.0.0.1"); ok {
+		t.Fatalf("Lookup(10.0.0.1) = found after RemoveRoute, want no match")
+	}
+
+	// Removing a route that was never present, or a prefix that was
+	// never inserted, should be a no-op rather than an error.
+	if err := rib.RemoveRoute("10.0.0.0/24", ProtocolBGP, "1.1.1.1"); err != nil {
+		t.Fatalf("RemoveRoute (already gone): %v", err)
+	}
+	if err := rib.RemoveRoute("192.168.0.0/16", ProtocolStatic, "9.9.9.9"); err != nil {
+		t.Fatalf("RemoveRoute (never inserted): %v", err)
+	}
+}
+
+func TestRIBAddRouteInvalidPrefix(t *testing.T) {
+	rib := NewRIB()
+	if err := rib.AddRoute(Route{Prefix: "not-a-cidr", Protocol: ProtocolStatic}); err == nil {
+		t.Fatalf("AddRoute with an invalid prefix returned nil error, want an error")
+	}
+}
+
+func TestRIBWalkVisitsEveryPrefixOnce(t *testing.T) {
+	rib := NewRIB()
+	mustAddRoute(t, rib, Route{Prefix: "10.0.0.0/8", NextHop: "1.1.1.1", Protocol: ProtocolStatic})
+	mustAddRoute(t, rib, Route{Prefix: "192.168.0.0/16", NextHop: "2.2.2.2", Protocol: ProtocolStatic})
+
+	seen := map[string]int{}
+	rib.Walk(func(prefix string, routes []Route) {
+		seen[prefix] = len(routes)
+	})
+
+	if len(seen) != 2 {
+		t.Fatalf("Walk visited %d prefixes, want 2", len(seen))
+	}
+	if seen["10.0.0.0/8"] != 1 || seen["192.168.0.0/16"] != 1 {
+		t.Fatalf("Walk() visited = %+v, want one candidate per prefix", seen)
+	}
+}
@@ -0,0 +1,68 @@
+package routing
+
+import (
+	"context"
+	"sync"
+)
+
+// Store persists the RIB's routes across process restarts, independent
+// of the in-memory RIB trie itself: Manager writes through to Store on
+// every AddRoute/RemoveRoute and replays its contents into a fresh RIB
+// on startup.
+type Store interface {
+	// Put inserts or replaces route, keyed by (Prefix, Protocol, NextHop).
+	Put(ctx context.Context, route Route) error
+	// Delete removes the route matching prefix/protocol/nextHop, a no-op
+	// if it doesn't exist.
+	Delete(ctx context.Context, prefix string, protocol Protocol, nextHop string) error
+	// List returns every persisted Route.
+	List(ctx context.Context) ([]Route, error)
+}
+
+type storeKey struct {
+	Prefix   string
+	Protocol Protocol
+	NextHop  string
+}
+
+// MemoryStore is an in-memory Store; routes don't survive a process
+// restart, but it's enough to seed a Manager in tests or when no
+// durable store is configured.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	routes map[storeKey]Route
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{routes: make(map[storeKey]Route)}
+}
+
+// Put implements Store.
+func (m *MemoryStore) Put(ctx context.Context, route Route) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.routes[storeKey{route.Prefix, route.Protocol, route.NextHop}] = route
+	return nil
+}
+
+// Delete implements Store.
+func (m *MemoryStore) Delete(ctx context.Context, prefix string, protocol Protocol, nextHop string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.routes, storeKey{prefix, protocol, nextHop})
+	return nil
+}
+
+// List implements Store.
+func (m *MemoryStore) List(ctx context.Context) ([]Route, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]Route, 0, len(m.routes))
+	for _, route := range m.routes {
+		out = append(out, route)
+	}
+	return out, nil
+}
+
+var _ Store = (*MemoryStore)(nil)
@@ -0,0 +1,80 @@
+// Package routing implements an in-memory routing information base (RIB)
+// and forwarding information base (FIB) backing RoutingHandler: an LPM
+// trie of candidate routes per protocol, a debounced best-path
+// reconciler, simulated BGP/OSPF/IS-IS adjacency sessions, and a
+// pluggable Store for persisting routes across restarts.
+package routing
+
+import "time"
+
+// Protocol identifies which routing protocol contributed a Route to the
+// RIB, and is also the key StartProtocol/StopProtocol/Protocols operate
+// on.
+type Protocol string
+
+const (
+	ProtocolStatic Protocol = "static"
+	ProtocolBGP    Protocol = "bgp"
+	ProtocolOSPF   Protocol = "ospf"
+	ProtocolISIS   Protocol = "isis"
+)
+
+// adminDistance is each Protocol's default administrative distance, the
+// RIB's primary best-path tie-breaker (lower wins), mirroring Cisco's
+// conventional defaults.
+var adminDistance = map[Protocol]int{
+	ProtocolStatic: 1,
+	ProtocolBGP:    20,
+	ProtocolOSPF:   110,
+	ProtocolISIS:   115,
+}
+
+// AdminDistance returns p's administrative distance, or 255
+// (effectively unreachable) for an unrecognized protocol.
+func AdminDistance(p Protocol) int {
+	if d, ok := adminDistance[p]; ok {
+		return d
+	}
+	return 255
+}
+
+// Route is one candidate path to Prefix the RIB holds. Multiple routes
+// to the same Prefix can coexist - from different protocols, or from
+// the same protocol with different NextHops (ECMP) - as long as they
+// don't share both Protocol and NextHop, which together identify a
+// single candidate.
+type Route struct {
+	Prefix    string // CIDR, e.g. "10.0.0.0/8" or "2001:db8::/32"
+	NextHop   string
+	Interface string
+	Metric    int
+	Protocol  Protocol
+	// Tag is opaque to the RIB - e.g. the BGP peer or OSPF area a route
+	// was learned from - carried through for display and withdrawal by
+	// the protocol session that injected it.
+	Tag       string
+	UpdatedAt time.Time
+}
+
+// routeKey identifies one candidate within a prefix's candidate set.
+type routeKey struct {
+	Protocol Protocol
+	NextHop  string
+}
+
+// less reports whether a should be preferred over b as a prefix's best
+// path: lower administrative distance wins, then lower metric, then
+// protocol name and finally next hop as deterministic tie-breakers.
+func less(a, b Route) bool {
+	da, db := AdminDistance(a.Protocol), AdminDistance(b.Protocol)
+	if da != db {
+		return da < db
+	}
+	if a.Metric != b.Metric {
+		return a.Metric < b.Metric
+	}
+	if a.Protocol != b.Protocol {
+		return a.Protocol < b.Protocol
+	}
+	return a.NextHop < b.NextHop
+}
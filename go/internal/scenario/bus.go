@@ -0,0 +1,77 @@
+package scenario
+
+// EventType is the kind of change a watch Event reports, mirroring
+// Kubernetes' ADDED/MODIFIED/DELETED watch semantics.
+type EventType string
+
+const (
+	EventAdded    EventType = "ADDED"
+	EventModified EventType = "MODIFIED"
+	EventDeleted  EventType = "DELETED"
+)
+
+// Event is a single change to a Scenario, delivered to every watcher
+// subscribed when Manager's Apply or Delete publishes it.
+type Event struct {
+	Type     EventType `json:"type"`
+	Scenario *Scenario `json:"scenario"`
+}
+
+// Bus is an in-memory pub/sub of scenario Events, fed by Manager and
+// drained by the watch endpoint, mirroring analytics.Engine's
+// subscriber-channel pattern.
+type Bus struct {
+	subs map[chan Event]struct{}
+	add  chan chan Event
+	rm   chan chan Event
+	pub  chan Event
+}
+
+// NewBus creates a Bus and starts its dispatch loop; it runs until the
+// process exits, the same lifetime as analytics.Engine's subscriber map.
+func NewBus() *Bus {
+	b := &Bus{
+		subs: make(map[chan Event]struct{}),
+		add:  make(chan chan Event),
+		rm:   make(chan chan Event),
+		pub:  make(chan Event, 64),
+	}
+	go b.run()
+	return b
+}
+
+func (b *Bus) run() {
+	for {
+		select {
+		case ch := <-b.add:
+			b.subs[ch] = struct{}{}
+		case ch := <-b.rm:
+			delete(b.subs, ch)
+		case event := <-b.pub:
+			for ch := range b.subs {
+				select {
+				case ch <- event:
+				default:
+					// Slow subscriber; drop rather than block the bus.
+				}
+			}
+		}
+	}
+}
+
+// Subscribe registers ch to receive Events published after this call,
+// until Unsubscribe is called with the same channel. An Event that finds
+// ch's buffer full is dropped for ch rather than blocking the bus.
+func (b *Bus) Subscribe(ch chan Event) {
+	b.add <- ch
+}
+
+// Unsubscribe removes ch from the subscriber set.
+func (b *Bus) Unsubscribe(ch chan Event) {
+	b.rm <- ch
+}
+
+// Publish fans event out to every current subscriber.
+func (b *Bus) Publish(event Event) {
+	b.pub <- event
+}
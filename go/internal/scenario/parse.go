@@ -0,0 +1,38 @@
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Parse decodes a Scenario document. YAML is accepted, and since JSON is
+// a subset of YAML, a JSON document parses the same way. spec is decoded
+// generically here; use Scenario.DecodeSpec once Kind is known to reach
+// the concrete *Spec type.
+func Parse(data []byte) (*Scenario, error) {
+	var raw struct {
+		APIVersion string                 `yaml:"apiVersion" json:"apiVersion"`
+		Kind       Kind                   `yaml:"kind" json:"kind"`
+		Metadata   ObjectMeta             `yaml:"metadata" json:"metadata"`
+		Spec       map[string]interface{} `yaml:"spec" json:"spec"`
+		Status     Status                 `yaml:"status,omitempty" json:"status,omitempty"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("scenario: parsing document: %w", err)
+	}
+
+	specBytes, err := json.Marshal(raw.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("scenario: re-encoding spec: %w", err)
+	}
+
+	return &Scenario{
+		APIVersion: raw.APIVersion,
+		Kind:       raw.Kind,
+		Metadata:   raw.Metadata,
+		Spec:       specBytes,
+		Status:     raw.Status,
+	}, nil
+}
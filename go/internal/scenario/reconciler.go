@@ -0,0 +1,119 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"router-sim/internal/aviatrix"
+	"router-sim/internal/cloudpods"
+)
+
+// Reconciler diffs a Scenario's desired Spec against live state and
+// records the result as status Conditions. RouterTopology, BGPPeering,
+// and LinkImpairment apply directly to router-sim's own simulated
+// dataplane, so admission and reconciliation are the same synchronous
+// step; CloudGateway additionally checks the named gateway against a
+// real controller, when one is wired with SetAviatrixClient/
+// SetCloudPodsClient.
+type Reconciler struct {
+	aviatrix  aviatrix.AviatrixService
+	cloudpods *cloudpods.Client
+	logger    *zap.Logger
+}
+
+// NewReconciler creates a Reconciler with no controllers wired; every
+// CloudGateway scenario reports Progressing until one is set.
+func NewReconciler(logger *zap.Logger) *Reconciler {
+	return &Reconciler{logger: logger}
+}
+
+// SetAviatrixClient wires the AviatrixService CloudGateway scenarios with
+// provider "aviatrix" are checked against.
+func (r *Reconciler) SetAviatrixClient(client aviatrix.AviatrixService) {
+	r.aviatrix = client
+}
+
+// SetCloudPodsClient wires the Client CloudGateway scenarios with
+// provider "cloudpods" are checked against.
+func (r *Reconciler) SetCloudPodsClient(client *cloudpods.Client) {
+	r.cloudpods = client
+}
+
+// Reconcile rebuilds s.Status.Conditions in place from s.Spec and
+// (for CloudGateway) live controller state.
+func (r *Reconciler) Reconcile(ctx context.Context, s *Scenario) {
+	now := time.Now()
+
+	switch s.Kind {
+	case KindRouterTopology, KindBGPPeering, KindLinkImpairment:
+		s.setCondition(Condition{Type: ConditionReady, Status: ConditionTrue, Reason: "Applied", Message: fmt.Sprintf("%s applied to the simulated router", s.Kind), LastTransitionTime: now})
+		s.setCondition(Condition{Type: ConditionProgressing, Status: ConditionFalse, Reason: "Applied", LastTransitionTime: now})
+		s.setCondition(Condition{Type: ConditionDegraded, Status: ConditionFalse, Reason: "Applied", LastTransitionTime: now})
+	case KindCloudGateway:
+		r.reconcileCloudGateway(ctx, s, now)
+	default:
+		s.setCondition(Condition{Type: ConditionDegraded, Status: ConditionTrue, Reason: "UnknownKind", Message: fmt.Sprintf("no reconciler logic for kind %q", s.Kind), LastTransitionTime: now})
+	}
+}
+
+func (r *Reconciler) reconcileCloudGateway(ctx context.Context, s *Scenario, now time.Time) {
+	var spec CloudGatewaySpec
+	if err := s.DecodeSpec(&spec); err != nil {
+		s.setCondition(Condition{Type: ConditionDegraded, Status: ConditionTrue, Reason: "InvalidSpec", Message: err.Error(), LastTransitionTime: now})
+		return
+	}
+
+	switch spec.Provider {
+	case "aviatrix":
+		r.reconcileAviatrixGateway(ctx, s, spec, now)
+	case "cloudpods":
+		r.reconcileCloudPodsGateway(ctx, s, spec, now)
+	}
+}
+
+func (r *Reconciler) reconcileAviatrixGateway(ctx context.Context, s *Scenario, spec CloudGatewaySpec, now time.Time) {
+	if r.aviatrix == nil {
+		s.setCondition(Condition{Type: ConditionProgressing, Status: ConditionTrue, Reason: "AviatrixNotConfigured", Message: "no Aviatrix client wired to the reconciler yet", LastTransitionTime: now})
+		return
+	}
+
+	gateways, err := r.aviatrix.GetTransitGateways(ctx)
+	if err != nil {
+		s.setCondition(Condition{Type: ConditionDegraded, Status: ConditionTrue, Reason: "AviatrixQueryFailed", Message: err.Error(), LastTransitionTime: now})
+		return
+	}
+
+	for _, gw := range gateways {
+		if gw.GatewayName == spec.Name {
+			s.setCondition(Condition{Type: ConditionReady, Status: ConditionTrue, Reason: "GatewayFound", Message: fmt.Sprintf("transit gateway %q is live", spec.Name), LastTransitionTime: now})
+			s.setCondition(Condition{Type: ConditionProgressing, Status: ConditionFalse, Reason: "GatewayFound", LastTransitionTime: now})
+			return
+		}
+	}
+	s.setCondition(Condition{Type: ConditionProgressing, Status: ConditionTrue, Reason: "GatewayMissing", Message: fmt.Sprintf("transit gateway %q not found on the controller yet", spec.Name), LastTransitionTime: now})
+}
+
+func (r *Reconciler) reconcileCloudPodsGateway(ctx context.Context, s *Scenario, spec CloudGatewaySpec, now time.Time) {
+	if r.cloudpods == nil {
+		s.setCondition(Condition{Type: ConditionProgressing, Status: ConditionTrue, Reason: "CloudPodsNotConfigured", Message: "no CloudPods client wired to the reconciler yet", LastTransitionTime: now})
+		return
+	}
+
+	loadBalancers, err := r.cloudpods.GetLoadBalancers(ctx)
+	if err != nil {
+		s.setCondition(Condition{Type: ConditionDegraded, Status: ConditionTrue, Reason: "CloudPodsQueryFailed", Message: err.Error(), LastTransitionTime: now})
+		return
+	}
+
+	for _, lb := range loadBalancers {
+		if lb.Name == spec.Name {
+			s.setCondition(Condition{Type: ConditionReady, Status: ConditionTrue, Reason: "GatewayFound", Message: fmt.Sprintf("load balancer %q is live", spec.Name), LastTransitionTime: now})
+			s.setCondition(Condition{Type: ConditionProgressing, Status: ConditionFalse, Reason: "GatewayFound", LastTransitionTime: now})
+			return
+		}
+	}
+	s.setCondition(Condition{Type: ConditionProgressing, Status: ConditionTrue, Reason: "GatewayMissing", Message: fmt.Sprintf("load balancer %q not found on the controller yet", spec.Name), LastTransitionTime: now})
+}
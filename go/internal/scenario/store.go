@@ -0,0 +1,72 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Store holds every admitted Scenario, keyed by metadata.name.
+type Store interface {
+	// Get returns the Scenario named name, or an error if it does not
+	// exist.
+	Get(ctx context.Context, name string) (*Scenario, error)
+	// List returns every Scenario in the store.
+	List(ctx context.Context) ([]*Scenario, error)
+	// Put inserts or replaces the Scenario named s.Metadata.Name.
+	Put(ctx context.Context, s *Scenario) error
+	// Delete removes the Scenario named name, a no-op if it does not
+	// exist.
+	Delete(ctx context.Context, name string) error
+}
+
+// MemoryStore is an in-memory Store.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	scenarios map[string]*Scenario
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{scenarios: make(map[string]*Scenario)}
+}
+
+// Get implements Store.
+func (m *MemoryStore) Get(ctx context.Context, name string) (*Scenario, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.scenarios[name]
+	if !ok {
+		return nil, fmt.Errorf("scenario: %q not found", name)
+	}
+	return s, nil
+}
+
+// List implements Store.
+func (m *MemoryStore) List(ctx context.Context) ([]*Scenario, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]*Scenario, 0, len(m.scenarios))
+	for _, s := range m.scenarios {
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// Put implements Store.
+func (m *MemoryStore) Put(ctx context.Context, s *Scenario) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scenarios[s.Metadata.Name] = s
+	return nil
+}
+
+// Delete implements Store.
+func (m *MemoryStore) Delete(ctx context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.scenarios, name)
+	return nil
+}
+
+var _ Store = (*MemoryStore)(nil)
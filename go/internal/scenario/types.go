@@ -0,0 +1,140 @@
+// Package scenario loads declarative router-simulation scenarios that
+// follow a Kubernetes-style CRD shape (apiVersion, kind, metadata, spec,
+// status) and reconciles each one's desired Spec against live router,
+// Aviatrix, and CloudPods state, recording the result as status
+// Conditions the way a Kubernetes controller would rather than returning
+// a one-shot mock response.
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Kind is the type of object a Scenario document declares.
+type Kind string
+
+const (
+	KindRouterTopology Kind = "RouterTopology"
+	KindBGPPeering     Kind = "BGPPeering"
+	KindLinkImpairment Kind = "LinkImpairment"
+	KindCloudGateway   Kind = "CloudGateway"
+)
+
+// APIVersion is the only apiVersion this package understands; Validate
+// rejects any document declaring a different one (Parse does not check
+// it).
+const APIVersion = "router-sim/v1"
+
+// ObjectMeta names one Scenario object, the same minimal subset of
+// Kubernetes' ObjectMeta this package needs.
+type ObjectMeta struct {
+	Name string `yaml:"name" json:"name"`
+}
+
+// ConditionType is the kind of status Condition being reported, mirroring
+// Kubernetes' Ready/Progressing/Degraded condition vocabulary.
+type ConditionType string
+
+const (
+	ConditionReady       ConditionType = "Ready"
+	ConditionProgressing ConditionType = "Progressing"
+	ConditionDegraded    ConditionType = "Degraded"
+)
+
+// ConditionStatus is whether a Condition currently holds.
+type ConditionStatus string
+
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// Condition is one observed fact about a Scenario's reconciliation,
+// carrying the time it last flipped Status so a watcher can tell a
+// steady-state Ready from one that just flapped.
+type Condition struct {
+	Type               ConditionType   `yaml:"type" json:"type"`
+	Status             ConditionStatus `yaml:"status" json:"status"`
+	Reason             string          `yaml:"reason,omitempty" json:"reason,omitempty"`
+	Message            string          `yaml:"message,omitempty" json:"message,omitempty"`
+	LastTransitionTime time.Time       `yaml:"lastTransitionTime" json:"lastTransitionTime"`
+}
+
+// Status is a Scenario's observed state, rebuilt by the Reconciler on
+// every Apply and every reconcile pass.
+type Status struct {
+	Conditions []Condition `yaml:"conditions,omitempty" json:"conditions,omitempty"`
+}
+
+// Scenario is one CRD-like document: Spec's shape depends on Kind, so it
+// is decoded lazily by DecodeSpec into the concrete *Spec type Kind
+// names.
+type Scenario struct {
+	APIVersion string          `yaml:"apiVersion" json:"apiVersion"`
+	Kind       Kind            `yaml:"kind" json:"kind"`
+	Metadata   ObjectMeta      `yaml:"metadata" json:"metadata"`
+	Spec       json.RawMessage `yaml:"spec" json:"spec"`
+	Status     Status          `yaml:"status,omitempty" json:"status,omitempty"`
+}
+
+// RouterTopologySpec declares the router's interfaces and the prefixes
+// each one originates.
+type RouterTopologySpec struct {
+	Interfaces []struct {
+		Name     string   `json:"name"`
+		Address  string   `json:"address"`
+		Prefixes []string `json:"prefixes,omitempty"`
+	} `json:"interfaces"`
+}
+
+// BGPPeeringSpec declares one desired BGP neighbor session.
+type BGPPeeringSpec struct {
+	PeerAddress string `json:"peerAddress"`
+	LocalASN    int    `json:"localAsn"`
+	PeerASN     int    `json:"peerAsn"`
+}
+
+// LinkImpairmentSpec declares an impairment to apply to one interface,
+// the same fields ApplyImpairmentRequest already accepted.
+type LinkImpairmentSpec struct {
+	Interface  string                 `json:"interface"`
+	Type       string                 `json:"type"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// CloudGatewaySpec declares a cloud gateway egress (an Aviatrix transit
+// gateway or a CloudPods load balancer) the router's traffic can exit
+// through.
+type CloudGatewaySpec struct {
+	Provider string `json:"provider"` // "aviatrix" or "cloudpods"
+	Name     string `json:"name"`
+	Region   string `json:"region"`
+}
+
+// DecodeSpec decodes s.Spec into out, the *Spec type matching s.Kind
+// (e.g. *BGPPeeringSpec for KindBGPPeering). It is the caller's
+// responsibility to pass the right out type for s.Kind.
+func (s *Scenario) DecodeSpec(out interface{}) error {
+	if err := json.Unmarshal(s.Spec, out); err != nil {
+		return fmt.Errorf("scenario: decoding %s spec: %w", s.Kind, err)
+	}
+	return nil
+}
+
+// setCondition upserts cond into s.Status.Conditions by Type, stamping
+// LastTransitionTime only when Status actually changed.
+func (s *Scenario) setCondition(cond Condition) {
+	for i := range s.Status.Conditions {
+		if s.Status.Conditions[i].Type == cond.Type {
+			if s.Status.Conditions[i].Status == cond.Status {
+				cond.LastTransitionTime = s.Status.Conditions[i].LastTransitionTime
+			}
+			s.Status.Conditions[i] = cond
+			return
+		}
+	}
+	s.Status.Conditions = append(s.Status.Conditions, cond)
+}
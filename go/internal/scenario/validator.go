@@ -0,0 +1,135 @@
+package scenario
+
+import (
+	"fmt"
+	"net"
+)
+
+// Validate checks candidate's schema (required fields per Kind) and,
+// given the scenarios already admitted, its semantics: RouterTopology
+// prefixes must not overlap one another, and every BGPPeering must agree
+// on the router's own local ASN.
+func Validate(existing []*Scenario, candidate *Scenario) error {
+	if candidate.APIVersion != APIVersion {
+		return fmt.Errorf("scenario: unsupported apiVersion %q, expected %q", candidate.APIVersion, APIVersion)
+	}
+	if candidate.Metadata.Name == "" {
+		return fmt.Errorf("scenario: metadata.name is required")
+	}
+
+	switch candidate.Kind {
+	case KindRouterTopology:
+		return validateRouterTopology(existing, candidate)
+	case KindBGPPeering:
+		return validateBGPPeering(existing, candidate)
+	case KindLinkImpairment:
+		return validateLinkImpairment(candidate)
+	case KindCloudGateway:
+		return validateCloudGateway(candidate)
+	default:
+		return fmt.Errorf("scenario: unknown kind %q", candidate.Kind)
+	}
+}
+
+func validateRouterTopology(existing []*Scenario, candidate *Scenario) error {
+	var spec RouterTopologySpec
+	if err := candidate.DecodeSpec(&spec); err != nil {
+		return err
+	}
+	if len(spec.Interfaces) == 0 {
+		return fmt.Errorf("scenario: RouterTopology %q: spec.interfaces must not be empty", candidate.Metadata.Name)
+	}
+
+	type prefixOwner struct {
+		ipnet *net.IPNet
+		owner string
+	}
+	var owned []prefixOwner
+
+	for _, s := range existing {
+		if s.Kind != KindRouterTopology || s.Metadata.Name == candidate.Metadata.Name {
+			continue
+		}
+		var other RouterTopologySpec
+		if err := s.DecodeSpec(&other); err != nil {
+			continue
+		}
+		for _, iface := range other.Interfaces {
+			for _, p := range iface.Prefixes {
+				if _, ipnet, err := net.ParseCIDR(p); err == nil {
+					owned = append(owned, prefixOwner{ipnet: ipnet, owner: s.Metadata.Name})
+				}
+			}
+		}
+	}
+
+	for _, iface := range spec.Interfaces {
+		if iface.Name == "" || iface.Address == "" {
+			return fmt.Errorf("scenario: RouterTopology %q: every interface needs a name and address", candidate.Metadata.Name)
+		}
+		for _, p := range iface.Prefixes {
+			_, ipnet, err := net.ParseCIDR(p)
+			if err != nil {
+				return fmt.Errorf("scenario: RouterTopology %q: invalid prefix %q: %w", candidate.Metadata.Name, p, err)
+			}
+			for _, o := range owned {
+				if ipnet.Contains(o.ipnet.IP) || o.ipnet.Contains(ipnet.IP) {
+					return fmt.Errorf("scenario: RouterTopology %q: prefix %q overlaps %q already declared by %q", candidate.Metadata.Name, p, o.ipnet.String(), o.owner)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func validateBGPPeering(existing []*Scenario, candidate *Scenario) error {
+	var spec BGPPeeringSpec
+	if err := candidate.DecodeSpec(&spec); err != nil {
+		return err
+	}
+	if spec.PeerAddress == "" || spec.LocalASN == 0 || spec.PeerASN == 0 {
+		return fmt.Errorf("scenario: BGPPeering %q: peerAddress, localAsn, and peerAsn are all required", candidate.Metadata.Name)
+	}
+
+	for _, s := range existing {
+		if s.Kind != KindBGPPeering || s.Metadata.Name == candidate.Metadata.Name {
+			continue
+		}
+		var other BGPPeeringSpec
+		if err := s.DecodeSpec(&other); err != nil {
+			continue
+		}
+		if other.PeerAddress == spec.PeerAddress {
+			return fmt.Errorf("scenario: BGPPeering %q: peerAddress %q is already peered by %q", candidate.Metadata.Name, spec.PeerAddress, s.Metadata.Name)
+		}
+		if other.LocalASN != spec.LocalASN {
+			return fmt.Errorf("scenario: BGPPeering %q: localAsn %d collides with %d already declared by %q", candidate.Metadata.Name, spec.LocalASN, other.LocalASN, s.Metadata.Name)
+		}
+	}
+	return nil
+}
+
+func validateLinkImpairment(candidate *Scenario) error {
+	var spec LinkImpairmentSpec
+	if err := candidate.DecodeSpec(&spec); err != nil {
+		return err
+	}
+	if spec.Interface == "" || spec.Type == "" {
+		return fmt.Errorf("scenario: LinkImpairment %q: interface and type are both required", candidate.Metadata.Name)
+	}
+	return nil
+}
+
+func validateCloudGateway(candidate *Scenario) error {
+	var spec CloudGatewaySpec
+	if err := candidate.DecodeSpec(&spec); err != nil {
+		return err
+	}
+	if spec.Name == "" {
+		return fmt.Errorf("scenario: CloudGateway %q: spec.name is required", candidate.Metadata.Name)
+	}
+	if spec.Provider != "aviatrix" && spec.Provider != "cloudpods" {
+		return fmt.Errorf("scenario: CloudGateway %q: provider must be \"aviatrix\" or \"cloudpods\", got %q", candidate.Metadata.Name, spec.Provider)
+	}
+	return nil
+}
@@ -4,20 +4,43 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"router-sim/internal/config"
 	"router-sim/internal/handlers"
+	"router-sim/internal/metrics"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
+// defaultShutdownTimeout bounds how long Shutdown waits for in-flight
+// requests to finish when config.ServerConfig.ShutdownTimeout is unset.
+const defaultShutdownTimeout = 30 * time.Second
+
+// readinessProbeTimeout bounds how long /readyz waits for any one
+// registered probe before treating it as failed.
+const readinessProbeTimeout = 2 * time.Second
+
+// probeFunc is one subsystem's readiness check, registered via
+// RegisterProbe.
+type probeFunc func(ctx context.Context) error
+
 // HTTPServer represents the HTTP server
 type HTTPServer struct {
 	config   *config.ServerConfig
 	handlers *handlers.Handlers
 	server   *http.Server
+
+	mu     sync.Mutex
+	probes map[string]probeFunc
+	ready  bool
+
+	inFlight sync.WaitGroup
 }
 
 // New creates a new HTTP server
@@ -25,9 +48,34 @@ func New(config *config.ServerConfig, handlers *handlers.Handlers) *HTTPServer {
 	return &HTTPServer{
 		config:   config,
 		handlers: handlers,
+		probes:   make(map[string]probeFunc),
+		ready:    true,
 	}
 }
 
+// RegisterProbe registers fn under name as a readiness dependency:
+// /readyz calls every registered probe on each request and reports 503
+// with per-probe status JSON if any of them return an error. Handlers
+// and services (the routing manager, the analytics engine, cloud
+// clients) should call this at construction time, before Start.
+func (s *HTTPServer) RegisterProbe(name string, fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.probes[name] = fn
+}
+
+func (s *HTTPServer) setReady(ready bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ready = ready
+}
+
+func (s *HTTPServer) isReady() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ready
+}
+
 // Start starts the HTTP server
 func (s *HTTPServer) Start() error {
 	// Set Gin mode
@@ -39,30 +87,38 @@ func (s *HTTPServer) Start() error {
 
 	// Create Gin router
 	r := gin.New()
-	
+
 	// Add middleware
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
-	
+	r.Use(s.trackInFlight())
+	r.Use(metrics.GinMiddleware())
+
 	// Add CORS middleware if enabled
 	if s.config.EnableCORS {
 		r.Use(func(c *gin.Context) {
 			c.Header("Access-Control-Allow-Origin", "*")
 			c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
-			
+
 			if c.Request.Method == "OPTIONS" {
 				c.AbortWithStatus(204)
 				return
 			}
-			
+
 			c.Next()
 		})
 	}
-	
+
+	// Liveness/readiness probes, ahead of SetupRoutes so a subsystem
+	// route registered under the same path can never shadow them.
+	r.GET("/healthz", s.handleHealthz)
+	r.GET("/readyz", s.handleReadyz)
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
 	// Setup routes
 	s.handlers.SetupRoutes(r)
-	
+
 	// Create HTTP server
 	s.server = &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", s.config.Host, s.config.Port),
@@ -71,18 +127,143 @@ func (s *HTTPServer) Start() error {
 		WriteTimeout: s.config.WriteTimeout,
 		IdleTimeout:  s.config.IdleTimeout,
 	}
-	
+
+	go s.watchSignals()
+
 	logrus.WithFields(logrus.Fields{
 		"host": s.config.Host,
 		"port": s.config.Port,
 	}).Info("Starting HTTP server")
-	
+
 	// Start server
-	return s.server.ListenAndServe()
+	if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// trackInFlight counts requests in progress so Shutdown can wait for
+// them to finish, skipping /healthz and /readyz themselves so a load
+// balancer's own probes never count as "in-flight work" blocking
+// shutdown.
+func (s *HTTPServer) trackInFlight() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if path == "/healthz" || path == "/readyz" || path == "/metrics" {
+			c.Next()
+			return
+		}
+		s.inFlight.Add(1)
+		defer s.inFlight.Done()
+		c.Next()
+	}
 }
 
-// Shutdown gracefully shuts down the server
+// handleHealthz reports liveness: the process is up and serving. It
+// never depends on RegisterProbe's probes, so a slow or failing
+// dependency can't make Kubernetes kill a pod that's otherwise fine.
+func (s *HTTPServer) handleHealthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// probeResult is one probe's outcome in /readyz's response body.
+type probeResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// handleReadyz reports readiness: whether this instance should keep
+// receiving traffic. It fails immediately while draining (post-SIGTERM,
+// pre-stop), and otherwise runs every registered probe, returning 503
+// with per-probe status JSON if any of them fail.
+func (s *HTTPServer) handleReadyz(c *gin.Context) {
+	if !s.isReady() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+		return
+	}
+
+	s.mu.Lock()
+	probes := make(map[string]probeFunc, len(s.probes))
+	for name, fn := range s.probes {
+		probes[name] = fn
+	}
+	s.mu.Unlock()
+
+	results := make(map[string]probeResult, len(probes))
+	healthy := true
+	for name, fn := range probes {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readinessProbeTimeout)
+		err := fn(ctx)
+		cancel()
+		if err != nil {
+			results[name] = probeResult{Status: "fail", Error: err.Error()}
+			healthy = false
+			continue
+		}
+		results[name] = probeResult{Status: "ok"}
+	}
+
+	status := "ok"
+	code := http.StatusOK
+	if !healthy {
+		status = "unavailable"
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, gin.H{"status": status, "probes": results})
+}
+
+// watchSignals waits for SIGTERM and drives a graceful Shutdown, so
+// HTTPServer drains and exits cleanly under Kubernetes/systemd without
+// the caller having to wire its own signal handling.
+func (s *HTTPServer) watchSignals() {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGTERM)
+	<-sig
+
+	logrus.Info("Received SIGTERM, starting graceful shutdown")
+
+	timeout := s.config.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := s.Shutdown(ctx); err != nil {
+		logrus.WithError(err).Error("Error during SIGTERM-triggered shutdown")
+	}
+}
+
+// Shutdown gracefully shuts down the server: it flips readiness to
+// false and waits PreStopDelay (giving a load balancer time to notice
+// and stop sending new traffic), stops accepting connections, and then
+// blocks until every in-flight request completes or ctx's deadline
+// elapses, whichever comes first.
 func (s *HTTPServer) Shutdown(ctx context.Context) error {
 	logrus.Info("Shutting down HTTP server...")
-	return s.server.Shutdown(ctx)
+	s.setReady(false)
+
+	if s.config.PreStopDelay > 0 {
+		select {
+		case <-time.After(s.config.PreStopDelay):
+		case <-ctx.Done():
+		}
+	}
+
+	if err := s.server.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		s.inFlight.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		logrus.Warn("Shutdown deadline reached before all in-flight requests finished")
+	}
+	return nil
 }
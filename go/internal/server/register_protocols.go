@@ -0,0 +1,29 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"router-sim/internal/apispec"
+)
+
+// RegisterProtocolRoutes mounts the routing-protocol start/stop endpoints
+// through b, giving StartProtocol/StopProtocol a reachable path instead of
+// leaving them defined but unrouted.
+func RegisterProtocolRoutes(b *apispec.Builder) {
+	b.Add(apispec.Route{
+		Method:  http.MethodPost,
+		Path:    "/router/protocols/:name/start",
+		Summary: "Start a routing protocol",
+		Tags:    []string{"router"},
+		Handler: func(c *gin.Context, _ interface{}) { StartProtocol(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodPost,
+		Path:    "/router/protocols/:name/stop",
+		Summary: "Stop a routing protocol",
+		Tags:    []string{"router"},
+		Handler: func(c *gin.Context, _ interface{}) { StopProtocol(c) },
+	})
+}
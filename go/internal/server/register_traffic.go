@@ -0,0 +1,42 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"router-sim/internal/apispec"
+)
+
+// RegisterTrafficRoutes mounts the Clash-style observability endpoints
+// through b: GetConnections, StreamTraffic, StreamLogs and GetRules.
+func RegisterTrafficRoutes(b *apispec.Builder) {
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/router/connections",
+		Summary: "List active routed connections",
+		Tags:    []string{"router"},
+		Handler: func(c *gin.Context, _ interface{}) { GetConnections(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/router/traffic",
+		Summary: "Stream per-second traffic byte deltas",
+		Tags:    []string{"router"},
+		Handler: func(c *gin.Context, _ interface{}) { StreamTraffic(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/router/logs",
+		Summary: "Stream log events",
+		Tags:    []string{"router"},
+		Handler: func(c *gin.Context, _ interface{}) { StreamLogs(c) },
+	})
+	b.Add(apispec.Route{
+		Method:  http.MethodGet,
+		Path:    "/router/rules",
+		Summary: "Get the currently loaded traffic-shaping policy set",
+		Tags:    []string{"router"},
+		Handler: func(c *gin.Context, _ interface{}) { GetRules(c) },
+	})
+}
@@ -95,9 +95,10 @@ var (
 	logger    *zap.Logger
 )
 
-// SetLogger sets the logger instance
+// SetLogger sets the logger instance, hooking it so every entry it logs is
+// also published to StreamLogs' subscribers.
 func SetLogger(l *zap.Logger) {
-	logger = l
+	logger = l.WithOptions(zap.Hooks(zapLogHook))
 }
 
 // GetRouterStatus returns the current router status
@@ -267,43 +268,26 @@ func StopProtocol(c *gin.Context) {
 	})
 }
 
-// GetTrafficShaping returns traffic shaping configuration
+// GetTrafficShaping returns traffic shaping configuration. Statistics
+// reflects the connections TrafficController has actually tracked since
+// startup rather than fixed demo numbers.
 func GetTrafficShaping(c *gin.Context) {
+	policyMu.RLock()
+	current := append([]TrafficPolicy(nil), policies...)
+	policyMu.RUnlock()
+
 	shaping := TrafficShaping{
-		Enabled: true,
+		Enabled:    true,
 		Algorithms: []string{"token-bucket", "wfq", "htb"},
-		Policies: []TrafficPolicy{
-			{
-				Name:      "high-priority",
-				Interface: "eth0",
-				Bandwidth: 1000,
-				Algorithm: "htb",
-				Priority:  1,
-				Enabled:   true,
-			},
-			{
-				Name:      "low-priority",
-				Interface: "eth0",
-				Bandwidth: 500,
-				Algorithm: "wfq",
-				Priority:  10,
-				Enabled:   true,
-			},
-		},
-		Statistics: TrafficStatistics{
-			TotalPackets:   1000000,
-			ShapedPackets:  950000,
-			DroppedPackets: 50000,
-			TotalBytes:     1000000000,
-			ShapedBytes:    950000000,
-			DroppedBytes:   50000000,
-		},
+		Policies:   current,
+		Statistics: trafficController.Snapshot(),
 	}
 
 	c.JSON(http.StatusOK, shaping)
 }
 
-// UpdateTrafficShaping updates traffic shaping configuration
+// UpdateTrafficShaping replaces the loaded policy set GetTrafficShaping
+// and GetRules report.
 func UpdateTrafficShaping(c *gin.Context) {
 	var shaping TrafficShaping
 	if err := c.ShouldBindJSON(&shaping); err != nil {
@@ -311,8 +295,9 @@ func UpdateTrafficShaping(c *gin.Context) {
 		return
 	}
 
-	// Simulate update
-	time.Sleep(100 * time.Millisecond)
+	policyMu.Lock()
+	policies = shaping.Policies
+	policyMu.Unlock()
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Traffic shaping updated successfully",
@@ -0,0 +1,330 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+)
+
+// ConnMetadata describes a routed connection's endpoints, the fields a
+// Clash-style /connections listing reports per flow.
+type ConnMetadata struct {
+	Inbound     string `json:"inbound"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Network     string `json:"network"`
+}
+
+// ConnectionInfo is one entry of GET /connections: a flow's metadata, the
+// rule that matched it, and its running byte counters.
+type ConnectionInfo struct {
+	ID       string       `json:"id"`
+	Metadata ConnMetadata `json:"metadata"`
+	Rule     string       `json:"rule"`
+	Upload   int64        `json:"upload"`
+	Download int64        `json:"download"`
+	Start    time.Time    `json:"start"`
+}
+
+// TrafficController tracks every connection RoutedConnection wraps,
+// aggregating their counters into the data /connections, /traffic and
+// GetTrafficShaping's Statistics report. The package-level functions below
+// delegate to the controller installed with SetTrafficController, so
+// traffic-shaping and impairment code elsewhere only ever call
+// RoutedConnection and never touch a concrete type.
+type TrafficController interface {
+	// Wrap attributes conn's future reads/writes to matchedRule and
+	// returns a net.Conn that counts them, registering the flow until
+	// it is closed.
+	Wrap(ctx context.Context, conn net.Conn, metadata ConnMetadata, matchedRule string) net.Conn
+	// Connections lists every flow currently open.
+	Connections() []ConnectionInfo
+	// Snapshot aggregates open and previously-closed flows into the
+	// totals TrafficStatistics reports.
+	Snapshot() TrafficStatistics
+	// Totals returns cumulative upload/download bytes across open and
+	// previously-closed flows, the running counters StreamTraffic diffs
+	// once a second to report a delta.
+	Totals() (upload, download int64)
+}
+
+type defaultTrafficController struct {
+	mu    sync.RWMutex
+	conns map[string]*trackedConn
+	next  uint64
+
+	closed         TrafficStatistics
+	closedUpload   int64
+	closedDownload int64
+}
+
+func newDefaultTrafficController() *defaultTrafficController {
+	return &defaultTrafficController{conns: make(map[string]*trackedConn)}
+}
+
+func (t *defaultTrafficController) Wrap(ctx context.Context, conn net.Conn, metadata ConnMetadata, matchedRule string) net.Conn {
+	id := fmt.Sprintf("%d", atomic.AddUint64(&t.next, 1))
+	tc := &trackedConn{
+		Conn:       conn,
+		id:         id,
+		metadata:   metadata,
+		rule:       matchedRule,
+		start:      time.Now(),
+		controller: t,
+	}
+
+	t.mu.Lock()
+	t.conns[id] = tc
+	t.mu.Unlock()
+
+	return tc
+}
+
+func (t *defaultTrafficController) Connections() []ConnectionInfo {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	out := make([]ConnectionInfo, 0, len(t.conns))
+	for _, tc := range t.conns {
+		out = append(out, tc.info())
+	}
+	return out
+}
+
+func (t *defaultTrafficController) Snapshot() TrafficStatistics {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	stats := t.closed
+	for _, tc := range t.conns {
+		up := atomic.LoadInt64(&tc.upload)
+		down := atomic.LoadInt64(&tc.download)
+		stats.TotalBytes += up + down
+		stats.ShapedBytes += up + down
+		stats.TotalPackets += atomic.LoadInt64(&tc.packets)
+		stats.ShapedPackets += atomic.LoadInt64(&tc.packets)
+	}
+	return stats
+}
+
+func (t *defaultTrafficController) Totals() (upload, download int64) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	upload, download = t.closedUpload, t.closedDownload
+	for _, tc := range t.conns {
+		upload += atomic.LoadInt64(&tc.upload)
+		download += atomic.LoadInt64(&tc.download)
+	}
+	return upload, download
+}
+
+func (t *defaultTrafficController) forget(tc *trackedConn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.conns, tc.id)
+
+	up := atomic.LoadInt64(&tc.upload)
+	down := atomic.LoadInt64(&tc.download)
+	t.closed.TotalBytes += up + down
+	t.closed.ShapedBytes += up + down
+	t.closed.TotalPackets += atomic.LoadInt64(&tc.packets)
+	t.closed.ShapedPackets += atomic.LoadInt64(&tc.packets)
+	t.closedUpload += up
+	t.closedDownload += down
+}
+
+// trackedConn is the net.Conn RoutedConnection hands back: every Read and
+// Write is counted and attributed to the rule that matched the flow,
+// mirroring sing-box's N.RoutedConnection wrapper.
+type trackedConn struct {
+	net.Conn
+
+	id       string
+	metadata ConnMetadata
+	rule     string
+	start    time.Time
+
+	upload   int64
+	download int64
+	packets  int64
+
+	controller *defaultTrafficController
+	closeOnce  sync.Once
+}
+
+func (c *trackedConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		atomic.AddInt64(&c.download, int64(n))
+		atomic.AddInt64(&c.packets, 1)
+	}
+	return n, err
+}
+
+func (c *trackedConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	if n > 0 {
+		atomic.AddInt64(&c.upload, int64(n))
+		atomic.AddInt64(&c.packets, 1)
+	}
+	return n, err
+}
+
+func (c *trackedConn) Close() error {
+	c.closeOnce.Do(func() { c.controller.forget(c) })
+	return c.Conn.Close()
+}
+
+func (c *trackedConn) info() ConnectionInfo {
+	return ConnectionInfo{
+		ID:       c.id,
+		Metadata: c.metadata,
+		Rule:     c.rule,
+		Upload:   atomic.LoadInt64(&c.upload),
+		Download: atomic.LoadInt64(&c.download),
+		Start:    c.start,
+	}
+}
+
+var trafficController TrafficController = newDefaultTrafficController()
+
+// SetTrafficController replaces the package's TrafficController, the same
+// override point SetLogger gives the zap logger.
+func SetTrafficController(tc TrafficController) {
+	trafficController = tc
+}
+
+// RoutedConnection wraps conn for observability once traffic shaping or
+// impairment has decided matchedRule governs it, so the resulting flow
+// shows up in GetConnections and counts toward GetTrafficShaping's
+// Statistics. Callers should use the returned net.Conn in place of conn.
+// ctx mirrors sing-box's RoutedConnection hook and carries any per-flow
+// values future matchers attach, though the default controller doesn't
+// itself key off it.
+func RoutedConnection(ctx context.Context, conn net.Conn, metadata ConnMetadata, matchedRule string) net.Conn {
+	return trafficController.Wrap(ctx, conn, metadata, matchedRule)
+}
+
+// GetConnections returns every currently open routed connection.
+func GetConnections(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"connections": trafficController.Connections()})
+}
+
+// GetRules returns the traffic-shaping policy set currently loaded, the
+// rules GetConnections' Rule field refers to by name.
+func GetRules(c *gin.Context) {
+	policyMu.RLock()
+	defer policyMu.RUnlock()
+	c.JSON(http.StatusOK, gin.H{"rules": policies})
+}
+
+// StreamTraffic is a Server-Sent-Events endpoint emitting one {up, down}
+// byte-delta sample per second, the live counterpart to the cumulative
+// totals GetTrafficShaping reports.
+func StreamTraffic(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	var lastUp, lastDown int64
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-ticker.C:
+			up, down := trafficController.Totals()
+			c.SSEvent("traffic", gin.H{"up": up - lastUp, "down": down - lastDown})
+			lastUp, lastDown = up, down
+			return true
+		}
+	})
+}
+
+// logSubs holds one channel per StreamLogs caller. broadcastLog fans each
+// zap log entry out to them; it is fed by zapLogHook, which SetLogger
+// installs on the *zap.Logger so this package never needs its own logging
+// path. An entry that finds a channel full is dropped for that caller.
+var (
+	logSubMu sync.Mutex
+	logSubs  = make(map[chan LogEvent]struct{})
+)
+
+// LogEvent is one line StreamLogs emits, shaped after Clash's /logs feed.
+type LogEvent struct {
+	Type    string    `json:"type"`
+	Time    time.Time `json:"time"`
+	Message string    `json:"payload"`
+}
+
+func broadcastLog(e LogEvent) {
+	logSubMu.Lock()
+	defer logSubMu.Unlock()
+	for ch := range logSubs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// StreamLogs is a Server-Sent-Events endpoint streaming the log entries
+// recorded through the logger installed by SetLogger; entries that arrive
+// while this caller's 64-entry buffer is full are dropped.
+func StreamLogs(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	ch := make(chan LogEvent, 64)
+	logSubMu.Lock()
+	logSubs[ch] = struct{}{}
+	logSubMu.Unlock()
+	defer func() {
+		logSubMu.Lock()
+		delete(logSubs, ch)
+		logSubMu.Unlock()
+	}()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case e := <-ch:
+			c.SSEvent("log", e)
+			return true
+		}
+	})
+}
+
+// policies is the traffic-shaping policy set GetTrafficShaping and
+// GetRules both read, updated in place by UpdateTrafficShaping instead of
+// being recomputed from demo data on every request.
+var (
+	policyMu sync.RWMutex
+	policies = []TrafficPolicy{
+		{Name: "high-priority", Interface: "eth0", Bandwidth: 1000, Algorithm: "htb", Priority: 1, Enabled: true},
+		{Name: "low-priority", Interface: "eth0", Bandwidth: 500, Algorithm: "wfq", Priority: 10, Enabled: true},
+	}
+)
+
+// zapLogHook adapts a zapcore.Entry into the LogEvent StreamLogs streams.
+// SetLogger installs it via zap.Hooks so this package never needs its own
+// logging path.
+func zapLogHook(entry zapcore.Entry) error {
+	broadcastLog(LogEvent{Type: entry.Level.String(), Time: entry.Time, Message: entry.Message})
+	return nil
+}
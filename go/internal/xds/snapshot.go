@@ -0,0 +1,68 @@
+package xds
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	cachetypes "github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resourcev3 "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+)
+
+// SnapshotManager translates router-sim's live routing state into an
+// Envoy xDS snapshot and keeps go-control-plane's cache up to date as
+// that state changes. nodeID scopes the snapshot the way a sidecar's
+// node.id selects which router it receives state for; a single-router
+// deployment can use one fixed nodeID for every sidecar.
+type SnapshotManager struct {
+	cache cachev3.SnapshotCache
+
+	mu       sync.Mutex
+	versions map[string]int64
+}
+
+// NewSnapshotManager wraps an existing go-control-plane SnapshotCache
+// (construct one with cachev3.NewSnapshotCache(true, cachev3.IDHash{}, logger)
+// the usual way); SnapshotManager only owns pushing snapshots into it.
+func NewSnapshotManager(cache cachev3.SnapshotCache) *SnapshotManager {
+	return &SnapshotManager{cache: cache, versions: make(map[string]int64)}
+}
+
+// Update rebuilds nodeID's snapshot from routes, neighbors, and gateways
+// and pushes it into the cache, bumping the snapshot version so
+// go-control-plane treats it as new even on the rare occasion its
+// content is unchanged from the last push.
+func (m *SnapshotManager) Update(ctx context.Context, nodeID string, routes []Route, neighbors []Neighbor, gateways []GatewayEgress) error {
+	m.mu.Lock()
+	m.versions[nodeID]++
+	version := m.versions[nodeID]
+	m.mu.Unlock()
+
+	var clusters []cachetypes.Resource
+	for _, c := range translateNeighborClusters(neighbors) {
+		clusters = append(clusters, c)
+	}
+	for _, c := range translateGatewayClusters(gateways) {
+		clusters = append(clusters, c)
+	}
+
+	listener, err := translateListener(nodeID)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := cachev3.NewSnapshot(fmt.Sprintf("%d", version), map[resourcev3.Type][]cachetypes.Resource{
+		resourcev3.ClusterType:  clusters,
+		resourcev3.RouteType:    {translateRouteConfiguration(nodeID, routes)},
+		resourcev3.ListenerType: {listener},
+	})
+	if err != nil {
+		return fmt.Errorf("xds: building snapshot for node %q: %w", nodeID, err)
+	}
+	if err := snapshot.Consistent(); err != nil {
+		return fmt.Errorf("xds: inconsistent snapshot for node %q: %w", nodeID, err)
+	}
+
+	return m.cache.SetSnapshot(ctx, nodeID, snapshot)
+}
@@ -0,0 +1,78 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"router-sim/internal/aviatrix"
+)
+
+// GatewayProvisioner is what a Gateway reconcile calls to stand up the
+// cloud infrastructure a Gateway's listeners imply. It is satisfied by
+// AviatrixProvisioner and by fakes in tests.
+type GatewayProvisioner interface {
+	EnsureTransitGateway(ctx context.Context, name, region string) error
+	EnsureSpokeGateway(ctx context.Context, name, region string) error
+}
+
+// AviatrixProvisioner drives aviatrix.Client to realize a Gateway's
+// listeners: one transit gateway backs a Gateway that terminates
+// north-south traffic (any HTTP/HTTPS/TLS listener), one spoke gateway
+// backs the plain TCP/UDP case, mirroring how Aviatrix's own Terraform
+// provider splits the two resource kinds.
+type AviatrixProvisioner struct {
+	client *aviatrix.Client
+}
+
+// NewAviatrixProvisioner wraps client for use by the Gateway controller.
+func NewAviatrixProvisioner(client *aviatrix.Client) *AviatrixProvisioner {
+	return &AviatrixProvisioner{client: client}
+}
+
+// EnsureTransitGateway creates name's transit gateway if it is not
+// already present in client's cache.
+func (p *AviatrixProvisioner) EnsureTransitGateway(ctx context.Context, name, region string) error {
+	existing, err := p.client.ListTransitGateways(ctx)
+	if err != nil {
+		return fmt.Errorf("k8s: list transit gateways: %w", err)
+	}
+	for _, gw := range existing {
+		if gw.GatewayName == name {
+			return nil
+		}
+	}
+
+	_, err = p.client.CreateTransitGateway(ctx, &aviatrix.TransitGateway{
+		GatewayName: name,
+		Region:      region,
+		Status:      "pending",
+	})
+	if err != nil {
+		return fmt.Errorf("k8s: create transit gateway %s: %w", name, err)
+	}
+	return nil
+}
+
+// EnsureSpokeGateway creates name's spoke gateway if it is not already
+// present in client's cache.
+func (p *AviatrixProvisioner) EnsureSpokeGateway(ctx context.Context, name, region string) error {
+	existing, err := p.client.ListSpokeGateways(ctx)
+	if err != nil {
+		return fmt.Errorf("k8s: list spoke gateways: %w", err)
+	}
+	for _, gw := range existing {
+		if gw.GatewayName == name {
+			return nil
+		}
+	}
+
+	_, err = p.client.CreateSpokeGateway(ctx, &aviatrix.SpokeGateway{
+		GatewayName: name,
+		Region:      region,
+		Status:      "pending",
+	})
+	if err != nil {
+		return fmt.Errorf("k8s: create spoke gateway %s: %w", name, err)
+	}
+	return nil
+}
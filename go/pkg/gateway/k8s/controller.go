@@ -0,0 +1,235 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ControllerName identifies this implementation in a GatewayClass's
+// spec.controllerName, the same field Traefik/Envoy Gateway/Contour use
+// to claim the GatewayClasses pointing at them. Only Gateways whose class
+// resolves to this name are reconciled; everything else is left for
+// whichever other controller it belongs to.
+const ControllerName = "router-sim.io/gateway-controller"
+
+// Controller watches Gateway API objects through an InformerFactory and
+// reconciles the ones accepted by ControllerName into router-sim
+// configuration (via RouterProgrammer) and Aviatrix infrastructure (via
+// GatewayProvisioner), writing Accepted/Programmed/ResolvedRefs status
+// back through the Source.
+type Controller struct {
+	informer *InformerFactory
+	source   Source
+	router   RouterProgrammer
+	gateways GatewayProvisioner
+
+	now func() time.Time
+}
+
+// NewController builds a Controller. now defaults to time.Now; tests can
+// override it through WithClock.
+func NewController(informer *InformerFactory, source Source, router RouterProgrammer, gateways GatewayProvisioner) *Controller {
+	return &Controller{
+		informer: informer,
+		source:   source,
+		router:   router,
+		gateways: gateways,
+		now:      time.Now,
+	}
+}
+
+// WithClock overrides the Controller's clock, for deterministic tests of
+// condition LastTransitionTime handling.
+func (c *Controller) WithClock(now func() time.Time) *Controller {
+	c.now = now
+	return c
+}
+
+// Run starts the InformerFactory, waits for its initial cache sync, and
+// then reconciles once per Changed() signal until stopCh is closed.
+func (c *Controller) Run(ctx context.Context, stopCh <-chan struct{}) error {
+	c.informer.Start(stopCh)
+	if !c.informer.WaitForCacheSync(ctx) {
+		return fmt.Errorf("k8s: gateway controller: cache never synced")
+	}
+
+	c.ReconcileAll(ctx)
+
+	for {
+		select {
+		case <-stopCh:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.informer.Changed():
+			c.ReconcileAll(ctx)
+		}
+	}
+}
+
+// ReconcileAll reconciles every accepted Gateway and every route
+// attached to one, logging (rather than failing the whole pass on) a
+// single object's error so one bad object doesn't starve the rest.
+func (c *Controller) ReconcileAll(ctx context.Context) {
+	acceptedClasses := c.acceptedClasses()
+
+	for _, gw := range c.informer.Gateways() {
+		if err := c.reconcileGateway(ctx, gw, acceptedClasses); err != nil {
+			log.Printf("k8s: gateway controller: reconcile Gateway %s: %v", gw.NamespacedName(), err)
+		}
+	}
+
+	gateways := make(map[string]struct{})
+	for _, gw := range c.informer.Gateways() {
+		if acceptedClasses[gw.Spec.GatewayClassName] {
+			gateways[gw.Name] = struct{}{}
+		}
+	}
+
+	grants := c.informer.ReferenceGrants()
+	policies := c.informer.BackendTLSPolicies()
+
+	for _, route := range c.informer.HTTPRoutes() {
+		if err := c.reconcileHTTPRoute(ctx, route, gateways, grants, policies); err != nil {
+			log.Printf("k8s: gateway controller: reconcile HTTPRoute %s: %v", route.NamespacedName(), err)
+		}
+	}
+	for _, route := range c.informer.TCPRoutes() {
+		if err := c.reconcileTCPRoute(ctx, route, gateways, grants, policies); err != nil {
+			log.Printf("k8s: gateway controller: reconcile TCPRoute %s: %v", route.NamespacedName(), err)
+		}
+	}
+	for _, route := range c.informer.TLSRoutes() {
+		if err := c.reconcileTLSRoute(ctx, route, gateways, grants, policies); err != nil {
+			log.Printf("k8s: gateway controller: reconcile TLSRoute %s: %v", route.NamespacedName(), err)
+		}
+	}
+	for _, route := range c.informer.UDPRoutes() {
+		if err := c.reconcileUDPRoute(ctx, route, gateways, grants); err != nil {
+			log.Printf("k8s: gateway controller: reconcile UDPRoute %s: %v", route.NamespacedName(), err)
+		}
+	}
+}
+
+// acceptedClasses returns the set of GatewayClass names whose
+// controllerName is ours, the acceptance test every Gateway reconcile
+// starts with.
+func (c *Controller) acceptedClasses() map[string]bool {
+	accepted := make(map[string]bool)
+	for _, gc := range c.informer.GatewayClasses() {
+		if gc.Spec.ControllerName == ControllerName {
+			accepted[gc.Name] = true
+		}
+	}
+	return accepted
+}
+
+// reconcileGateway accepts or rejects gw based on its GatewayClass, then
+// - if accepted - ensures the Aviatrix infrastructure its listeners
+// imply and loads a scenario programming router-sim with them, reporting
+// Accepted and Programmed back through the Source.
+func (c *Controller) reconcileGateway(ctx context.Context, gw Gateway, acceptedClasses map[string]bool) error {
+	conditions := append([]Condition(nil), gw.Status.Conditions...)
+
+	if !acceptedClasses[gw.Spec.GatewayClassName] {
+		conditions = setCondition(conditions, Condition{
+			Type:               ConditionAccepted,
+			Status:             ConditionFalse,
+			Reason:             "NotOurGatewayClass",
+			Message:            fmt.Sprintf("GatewayClass %s is not controlled by %s", gw.Spec.GatewayClassName, ControllerName),
+			ObservedGeneration: gw.Generation,
+		}, c.now())
+		return c.source.PatchGatewayStatus(ctx, gw.NamespacedName(), conditions)
+	}
+
+	conditions = setCondition(conditions, Condition{
+		Type:               ConditionAccepted,
+		Status:             ConditionTrue,
+		Reason:             "Accepted",
+		ObservedGeneration: gw.Generation,
+	}, c.now())
+
+	programmedCond := Condition{Type: ConditionProgrammed, ObservedGeneration: gw.Generation}
+	if err := c.provisionGateway(ctx, gw); err != nil {
+		programmedCond.Status = ConditionFalse
+		programmedCond.Reason = "ProvisioningFailed"
+		programmedCond.Message = err.Error()
+		conditions = setCondition(conditions, programmedCond, c.now())
+		_ = c.source.PatchGatewayStatus(ctx, gw.NamespacedName(), conditions)
+		return err
+	}
+	programmedCond.Status = ConditionTrue
+	programmedCond.Reason = "Programmed"
+	conditions = setCondition(conditions, programmedCond, c.now())
+
+	return c.source.PatchGatewayStatus(ctx, gw.NamespacedName(), conditions)
+}
+
+// provisionGateway ensures Aviatrix infrastructure for gw and loads a
+// router-sim scenario describing its listeners.
+func (c *Controller) provisionGateway(ctx context.Context, gw Gateway) error {
+	hasL7 := false
+	hasL4 := false
+	listeners := make([]map[string]interface{}, 0, len(gw.Spec.Listeners))
+	for _, l := range gw.Spec.Listeners {
+		switch l.Protocol {
+		case "HTTP", "HTTPS", "TLS":
+			hasL7 = true
+		default:
+			hasL4 = true
+		}
+		listeners = append(listeners, map[string]interface{}{
+			"name":     l.Name,
+			"hostname": l.Hostname,
+			"port":     l.Port,
+			"protocol": l.Protocol,
+		})
+	}
+
+	if hasL7 {
+		if err := c.gateways.EnsureTransitGateway(ctx, gatewayResourceName(gw), "us-west-1"); err != nil {
+			return err
+		}
+	}
+	if hasL4 {
+		if err := c.gateways.EnsureSpokeGateway(ctx, gatewayResourceName(gw), "us-west-1"); err != nil {
+			return err
+		}
+	}
+
+	if err := c.router.LoadScenario(ctx, gatewayResourceName(gw), map[string]interface{}{
+		"gateway":   gw.NamespacedName(),
+		"listeners": listeners,
+	}); err != nil {
+		return err
+	}
+
+	// A listener named after a routing protocol (bgp/ospf/isis) is how an
+	// operator asks this Gateway to also expose that protocol's neighbor
+	// sessions, so start it the same way a manual `POST
+	// /router/protocols/{name}/start` would.
+	for _, l := range gw.Spec.Listeners {
+		if isRoutingProtocol(l.Name) {
+			if err := c.router.StartProtocol(ctx, l.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func isRoutingProtocol(name string) bool {
+	switch name {
+	case "bgp", "ospf", "isis":
+		return true
+	default:
+		return false
+	}
+}
+
+func gatewayResourceName(gw Gateway) string {
+	return "gw-" + gw.Namespace + "-" + gw.Name
+}
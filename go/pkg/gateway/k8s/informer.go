@@ -0,0 +1,363 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/hashstructure/v2"
+)
+
+// DefaultResyncInterval is how often the InformerFactory re-LISTs each
+// watched kind when the caller doesn't supply one.
+const DefaultResyncInterval = 15 * time.Second
+
+// kindStore is a thread-safe, namespacedName-keyed cache of one object
+// kind, diffed on each resync by a hashstructure hash of the object
+// rather than a resourceVersion, since this controller's Source may be
+// backed by a client that doesn't expose one (e.g. a test fake).
+type kindStore struct {
+	mu      sync.RWMutex
+	objects map[string]interface{}
+	hashes  map[string]uint64
+}
+
+func newKindStore() *kindStore {
+	return &kindStore{
+		objects: make(map[string]interface{}),
+		hashes:  make(map[string]uint64),
+	}
+}
+
+func hashOf(obj interface{}) uint64 {
+	h, err := hashstructure.Hash(obj, hashstructure.FormatV2, nil)
+	if err != nil {
+		return 0
+	}
+	return h
+}
+
+// sync reconciles the store against a freshly-LISTed set of objects keyed
+// by key, returning true if anything changed (an object was added,
+// removed, or its hash changed). A resync that observes no real change
+// returns false so the caller can skip reconciling.
+func (s *kindStore) sync(objects []interface{}, key func(interface{}) string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	seen := make(map[string]struct{}, len(objects))
+
+	for _, obj := range objects {
+		k := key(obj)
+		seen[k] = struct{}{}
+
+		newHash := hashOf(obj)
+		if oldHash, ok := s.hashes[k]; !ok || oldHash != newHash {
+			s.objects[k] = obj
+			s.hashes[k] = newHash
+			changed = true
+		}
+	}
+
+	for k := range s.objects {
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		delete(s.objects, k)
+		delete(s.hashes, k)
+		changed = true
+	}
+
+	return changed
+}
+
+func (s *kindStore) list() []interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]interface{}, 0, len(s.objects))
+	for _, obj := range s.objects {
+		out = append(out, obj)
+	}
+	return out
+}
+
+func (s *kindStore) get(key string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	obj, ok := s.objects[key]
+	return obj, ok
+}
+
+// reflectorSpec is one kind's list-then-poll loop, keyed and hashed
+// through a caller-supplied extractor so kindStore stays untyped.
+type reflectorSpec struct {
+	kind  string
+	store *kindStore
+	list  func(ctx context.Context) ([]interface{}, error)
+	key   func(interface{}) string
+}
+
+// InformerFactory runs a list-then-poll loop per watched Gateway API kind
+// against a Source, caching the decoded objects and signalling Changed()
+// only when a resync's hashstructure hash actually moved. Modeled on
+// client-go's SharedInformerFactory: construct one, call Start with a
+// stop channel, then WaitForCacheSync before trusting the Listers.
+type InformerFactory struct {
+	source Source
+	resync time.Duration
+
+	reflectors []reflectorSpec
+
+	changed chan struct{}
+	started bool
+	synced  map[string]chan struct{}
+}
+
+// NewInformerFactory builds an InformerFactory over source, resyncing
+// every resync (DefaultResyncInterval if zero).
+func NewInformerFactory(source Source, resync time.Duration) *InformerFactory {
+	if resync <= 0 {
+		resync = DefaultResyncInterval
+	}
+
+	f := &InformerFactory{
+		source:  source,
+		resync:  resync,
+		changed: make(chan struct{}, 1),
+		synced:  make(map[string]chan struct{}),
+	}
+
+	f.register("gatewayclass", func(ctx context.Context) ([]interface{}, error) {
+		objs, err := source.ListGatewayClasses(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, len(objs))
+		for i, o := range objs {
+			out[i] = o
+		}
+		return out, nil
+	}, func(obj interface{}) string { return obj.(GatewayClass).NamespacedName() })
+
+	f.register("gateway", func(ctx context.Context) ([]interface{}, error) {
+		objs, err := source.ListGateways(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, len(objs))
+		for i, o := range objs {
+			out[i] = o
+		}
+		return out, nil
+	}, func(obj interface{}) string { return obj.(Gateway).NamespacedName() })
+
+	f.register("httproute", func(ctx context.Context) ([]interface{}, error) {
+		objs, err := source.ListHTTPRoutes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, len(objs))
+		for i, o := range objs {
+			out[i] = o
+		}
+		return out, nil
+	}, func(obj interface{}) string { return obj.(HTTPRoute).NamespacedName() })
+
+	f.register("tcproute", func(ctx context.Context) ([]interface{}, error) {
+		objs, err := source.ListTCPRoutes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, len(objs))
+		for i, o := range objs {
+			out[i] = o
+		}
+		return out, nil
+	}, func(obj interface{}) string { return obj.(TCPRoute).NamespacedName() })
+
+	f.register("tlsroute", func(ctx context.Context) ([]interface{}, error) {
+		objs, err := source.ListTLSRoutes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, len(objs))
+		for i, o := range objs {
+			out[i] = o
+		}
+		return out, nil
+	}, func(obj interface{}) string { return obj.(TLSRoute).NamespacedName() })
+
+	f.register("udproute", func(ctx context.Context) ([]interface{}, error) {
+		objs, err := source.ListUDPRoutes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, len(objs))
+		for i, o := range objs {
+			out[i] = o
+		}
+		return out, nil
+	}, func(obj interface{}) string { return obj.(UDPRoute).NamespacedName() })
+
+	f.register("referencegrant", func(ctx context.Context) ([]interface{}, error) {
+		objs, err := source.ListReferenceGrants(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, len(objs))
+		for i, o := range objs {
+			out[i] = o
+		}
+		return out, nil
+	}, func(obj interface{}) string { return obj.(ReferenceGrant).NamespacedName() })
+
+	f.register("backendtlspolicy", func(ctx context.Context) ([]interface{}, error) {
+		objs, err := source.ListBackendTLSPolicies(ctx)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, len(objs))
+		for i, o := range objs {
+			out[i] = o
+		}
+		return out, nil
+	}, func(obj interface{}) string { return obj.(BackendTLSPolicy).NamespacedName() })
+
+	return f
+}
+
+func (f *InformerFactory) register(kind string, list func(context.Context) ([]interface{}, error), key func(interface{}) string) {
+	f.reflectors = append(f.reflectors, reflectorSpec{kind: kind, store: newKindStore(), list: list, key: key})
+	f.synced[kind] = make(chan struct{})
+}
+
+func (f *InformerFactory) storeFor(kind string) *kindStore {
+	for _, r := range f.reflectors {
+		if r.kind == kind {
+			return r.store
+		}
+	}
+	return nil
+}
+
+// GatewayClasses lists the current GatewayClass cache.
+func (f *InformerFactory) GatewayClasses() []GatewayClass {
+	return castAll[GatewayClass](f.storeFor("gatewayclass").list())
+}
+
+// Gateways lists the current Gateway cache.
+func (f *InformerFactory) Gateways() []Gateway {
+	return castAll[Gateway](f.storeFor("gateway").list())
+}
+
+// HTTPRoutes lists the current HTTPRoute cache.
+func (f *InformerFactory) HTTPRoutes() []HTTPRoute {
+	return castAll[HTTPRoute](f.storeFor("httproute").list())
+}
+
+// TCPRoutes lists the current TCPRoute cache.
+func (f *InformerFactory) TCPRoutes() []TCPRoute {
+	return castAll[TCPRoute](f.storeFor("tcproute").list())
+}
+
+// TLSRoutes lists the current TLSRoute cache.
+func (f *InformerFactory) TLSRoutes() []TLSRoute {
+	return castAll[TLSRoute](f.storeFor("tlsroute").list())
+}
+
+// UDPRoutes lists the current UDPRoute cache.
+func (f *InformerFactory) UDPRoutes() []UDPRoute {
+	return castAll[UDPRoute](f.storeFor("udproute").list())
+}
+
+// ReferenceGrants lists the current ReferenceGrant cache.
+func (f *InformerFactory) ReferenceGrants() []ReferenceGrant {
+	return castAll[ReferenceGrant](f.storeFor("referencegrant").list())
+}
+
+// BackendTLSPolicies lists the current BackendTLSPolicy cache.
+func (f *InformerFactory) BackendTLSPolicies() []BackendTLSPolicy {
+	return castAll[BackendTLSPolicy](f.storeFor("backendtlspolicy").list())
+}
+
+func castAll[T any](objects []interface{}) []T {
+	out := make([]T, 0, len(objects))
+	for _, obj := range objects {
+		out = append(out, obj.(T))
+	}
+	return out
+}
+
+// Changed returns the channel a resync publishes to when it detected at
+// least one kind's hash moved. It is a single-slot channel: a reconciler
+// that is still processing the last signal doesn't block the informer,
+// it just coalesces into "reconcile again".
+func (f *InformerFactory) Changed() <-chan struct{} {
+	return f.changed
+}
+
+// Start spins up one goroutine per registered kind, each running an
+// immediate LIST followed by a list-then-poll loop every f.resync, until
+// stopCh is closed. Calling Start twice is a no-op.
+func (f *InformerFactory) Start(stopCh <-chan struct{}) {
+	if f.started {
+		return
+	}
+	f.started = true
+
+	for _, r := range f.reflectors {
+		go f.runReflector(r, stopCh)
+	}
+}
+
+func (f *InformerFactory) runReflector(r reflectorSpec, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(f.resync)
+	defer ticker.Stop()
+
+	f.resyncOnce(r)
+	close(f.synced[r.kind])
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			f.resyncOnce(r)
+		}
+	}
+}
+
+func (f *InformerFactory) resyncOnce(r reflectorSpec) {
+	ctx, cancel := context.WithTimeout(context.Background(), f.resync)
+	defer cancel()
+
+	objects, err := r.list(ctx)
+	if err != nil {
+		// A failed LIST leaves the store as of its last successful sync;
+		// the next tick tries again rather than clearing the cache on a
+		// transient API error.
+		return
+	}
+
+	if r.store.sync(objects, r.key) {
+		select {
+		case f.changed <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// WaitForCacheSync blocks until every registered kind has completed its
+// initial LIST, or ctx is canceled first, mirroring client-go's
+// SharedInformerFactory.WaitForCacheSync.
+func (f *InformerFactory) WaitForCacheSync(ctx context.Context) bool {
+	for _, r := range f.reflectors {
+		select {
+		case <-f.synced[r.kind]:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+)
+
+// resolveBackendRefs reports whether every ref in refs is resolvable:
+// either it stays within fromNamespace, or some grant in grants
+// authorizes fromNamespace/fromKind to read it.
+func resolveBackendRefs(refs []BackendRef, fromNamespace, fromKind string, grants []ReferenceGrant) (bool, string) {
+	for _, ref := range refs {
+		if ref.Namespace == "" || ref.Namespace == fromNamespace {
+			continue
+		}
+		authorized := false
+		for _, g := range grants {
+			if g.Namespace == ref.Namespace && g.Allows(fromNamespace, fromKind, ref.Name) {
+				authorized = true
+				break
+			}
+		}
+		if !authorized {
+			return false, fmt.Sprintf("backendRef %s/%s not authorized by any ReferenceGrant in namespace %s", ref.Namespace, ref.Name, ref.Namespace)
+		}
+	}
+	return true, ""
+}
+
+// parentStatuses builds one RouteParentStatus per ParentRef naming a
+// Gateway this controller accepted, reporting Accepted against every
+// other ParentRef and ResolvedRefs/Programmed against the accepted ones.
+func (c *Controller) parentStatuses(parentRefs []ParentRef, gateways map[string]struct{}, refsOK bool, refsMsg string, programErr error, generation int64) []RouteParentStatus {
+	statuses := make([]RouteParentStatus, 0, len(parentRefs))
+	now := c.now()
+
+	for _, ref := range parentRefs {
+		var conditions []Condition
+		if _, ok := gateways[ref.Name]; !ok {
+			conditions = setCondition(conditions, Condition{
+				Type:               ConditionAccepted,
+				Status:             ConditionFalse,
+				Reason:             "NoMatchingParent",
+				Message:            fmt.Sprintf("Gateway %s is not accepted by %s", ref.Name, ControllerName),
+				ObservedGeneration: generation,
+			}, now)
+			statuses = append(statuses, RouteParentStatus{ParentRef: ref, ControllerName: ControllerName, Conditions: conditions})
+			continue
+		}
+
+		conditions = setCondition(conditions, Condition{
+			Type: ConditionAccepted, Status: ConditionTrue, Reason: "Accepted", ObservedGeneration: generation,
+		}, now)
+
+		resolvedStatus := ConditionTrue
+		resolvedReason := "ResolvedRefs"
+		if !refsOK {
+			resolvedStatus = ConditionFalse
+			resolvedReason = "InvalidBackendRef"
+		}
+		conditions = setCondition(conditions, Condition{
+			Type: ConditionResolvedRefs, Status: resolvedStatus, Reason: resolvedReason, Message: refsMsg, ObservedGeneration: generation,
+		}, now)
+
+		programmedStatus := ConditionTrue
+		programmedReason := "Programmed"
+		programmedMsg := ""
+		if !refsOK {
+			programmedStatus = ConditionFalse
+			programmedReason = "InvalidBackendRef"
+		} else if programErr != nil {
+			programmedStatus = ConditionFalse
+			programmedReason = "ProgrammingFailed"
+			programmedMsg = programErr.Error()
+		}
+		conditions = setCondition(conditions, Condition{
+			Type: ConditionProgrammed, Status: programmedStatus, Reason: programmedReason, Message: programmedMsg, ObservedGeneration: generation,
+		}, now)
+
+		statuses = append(statuses, RouteParentStatus{ParentRef: ref, ControllerName: ControllerName, Conditions: conditions})
+	}
+
+	return statuses
+}
+
+// backendWeights flattens refs into router-sim's {name: weight} traffic
+// split shape, defaulting an unset Weight to 1 the way the Gateway API
+// spec requires.
+func backendWeights(refs []BackendRef) map[string]interface{} {
+	weights := make(map[string]interface{}, len(refs))
+	for _, ref := range refs {
+		w := ref.Weight
+		if w == 0 {
+			w = 1
+		}
+		weights[ref.Name] = w
+	}
+	return weights
+}
+
+// backendTLSConfig looks up each ref's BackendTLSPolicy by TargetRefName
+// and returns the {name: {ca_cert_ref, hostname}} map router-sim needs to
+// validate an HTTPS/TLS backend, skipping refs with no policy attached.
+func backendTLSConfig(refs []BackendRef, policies []BackendTLSPolicy) map[string]interface{} {
+	tls := make(map[string]interface{})
+	for _, ref := range refs {
+		for _, p := range policies {
+			if p.Spec.TargetRefName != ref.Name {
+				continue
+			}
+			tls[ref.Name] = map[string]interface{}{
+				"ca_cert_ref": p.Spec.CACertRef,
+				"hostname":    p.Spec.Hostname,
+			}
+			break
+		}
+	}
+	return tls
+}
+
+func (c *Controller) reconcileHTTPRoute(ctx context.Context, route HTTPRoute, gateways map[string]struct{}, grants []ReferenceGrant, policies []BackendTLSPolicy) error {
+	var refs []BackendRef
+	for _, rule := range route.Spec.Rules {
+		refs = append(refs, rule.BackendRefs...)
+	}
+	refsOK, refsMsg := resolveBackendRefs(refs, route.Namespace, "HTTPRoute", grants)
+
+	var programErr error
+	if refsOK {
+		for i, rule := range route.Spec.Rules {
+			programErr = c.router.ApplyTrafficPolicy(ctx, "http-route", fmt.Sprintf("%s-rule%d", route.Name, i), map[string]interface{}{
+				"hostnames":   route.Spec.Hostnames,
+				"path_prefix": rule.PathPrefix,
+				"headers":     rule.Headers,
+				"backends":    backendWeights(rule.BackendRefs),
+				"backend_tls": backendTLSConfig(rule.BackendRefs, policies),
+			})
+			if programErr != nil {
+				break
+			}
+		}
+	}
+
+	statuses := c.parentStatuses(route.Spec.ParentRefs, gateways, refsOK, refsMsg, programErr, route.Generation)
+	return c.source.PatchRouteStatus(ctx, "HTTPRoute", route.NamespacedName(), statuses)
+}
+
+func (c *Controller) reconcileTCPRoute(ctx context.Context, route TCPRoute, gateways map[string]struct{}, grants []ReferenceGrant, policies []BackendTLSPolicy) error {
+	refsOK, refsMsg := resolveBackendRefs(route.Spec.BackendRefs, route.Namespace, "TCPRoute", grants)
+
+	var programErr error
+	if refsOK {
+		programErr = c.router.ApplyTrafficPolicy(ctx, "tcp-route", route.Name, map[string]interface{}{
+			"backends":    backendWeights(route.Spec.BackendRefs),
+			"backend_tls": backendTLSConfig(route.Spec.BackendRefs, policies),
+		})
+	}
+
+	statuses := c.parentStatuses(route.Spec.ParentRefs, gateways, refsOK, refsMsg, programErr, route.Generation)
+	return c.source.PatchRouteStatus(ctx, "TCPRoute", route.NamespacedName(), statuses)
+}
+
+func (c *Controller) reconcileTLSRoute(ctx context.Context, route TLSRoute, gateways map[string]struct{}, grants []ReferenceGrant, policies []BackendTLSPolicy) error {
+	refsOK, refsMsg := resolveBackendRefs(route.Spec.BackendRefs, route.Namespace, "TLSRoute", grants)
+
+	var programErr error
+	if refsOK {
+		programErr = c.router.ApplyTrafficPolicy(ctx, "tls-route", route.Name, map[string]interface{}{
+			"hostnames":   route.Spec.Hostnames,
+			"backends":    backendWeights(route.Spec.BackendRefs),
+			"backend_tls": backendTLSConfig(route.Spec.BackendRefs, policies),
+		})
+	}
+
+	statuses := c.parentStatuses(route.Spec.ParentRefs, gateways, refsOK, refsMsg, programErr, route.Generation)
+	return c.source.PatchRouteStatus(ctx, "TLSRoute", route.NamespacedName(), statuses)
+}
+
+func (c *Controller) reconcileUDPRoute(ctx context.Context, route UDPRoute, gateways map[string]struct{}, grants []ReferenceGrant) error {
+	refsOK, refsMsg := resolveBackendRefs(route.Spec.BackendRefs, route.Namespace, "UDPRoute", grants)
+
+	var programErr error
+	if refsOK {
+		programErr = c.router.ApplyTrafficPolicy(ctx, "udp-route", route.Name, map[string]interface{}{
+			"backends": backendWeights(route.Spec.BackendRefs),
+		})
+	}
+
+	statuses := c.parentStatuses(route.Spec.ParentRefs, gateways, refsOK, refsMsg, programErr, route.Generation)
+	return c.source.PatchRouteStatus(ctx, "UDPRoute", route.NamespacedName(), statuses)
+}
@@ -0,0 +1,102 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RouterProgrammer is what a reconciler calls once it has translated a
+// Gateway/Route into router-sim configuration. It is satisfied by
+// RouterClient (an HTTP client against router-sim's own REST API, the
+// same one cmd/server mounts) and by fakes in tests.
+type RouterProgrammer interface {
+	LoadScenario(ctx context.Context, name string, config map[string]interface{}) error
+	ApplyTrafficPolicy(ctx context.Context, kind, iface string, parameters map[string]interface{}) error
+	StartProtocol(ctx context.Context, name string) error
+	StopProtocol(ctx context.Context, name string) error
+}
+
+// RouterClient drives router-sim's own /api/v1/router/* endpoints,
+// mirroring the way aviatrix.Client and cloudpods.Client drive their
+// respective controllers: the Gateway API controller is just another
+// client of the REST API cmd/server already exposes, not a process
+// sharing memory with it.
+type RouterClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewRouterClient builds a RouterClient against baseURL (e.g.
+// "http://localhost:8080/api/v1").
+func NewRouterClient(baseURL string) *RouterClient {
+	return &RouterClient{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// LoadScenario posts the router configuration translated from a Gateway's
+// listeners and its attached routes' rules to POST /router/scenario.
+func (c *RouterClient) LoadScenario(ctx context.Context, name string, config map[string]interface{}) error {
+	return c.post(ctx, "/router/scenario", map[string]interface{}{
+		"name":        name,
+		"description": "gateway-api controller reconcile",
+		"config":      config,
+	})
+}
+
+// ApplyTrafficPolicy posts the weighted-backend split translated from a
+// route's BackendRefs to POST /router/impairment, reusing the impairment
+// endpoint's {type, interface, parameters} shape for traffic-shaping
+// configuration the same way it already does for network impairments.
+func (c *RouterClient) ApplyTrafficPolicy(ctx context.Context, kind, iface string, parameters map[string]interface{}) error {
+	return c.post(ctx, "/router/impairment", map[string]interface{}{
+		"type":       kind,
+		"interface":  iface,
+		"parameters": parameters,
+	})
+}
+
+// StartProtocol calls POST /router/protocols/{name}/start.
+func (c *RouterClient) StartProtocol(ctx context.Context, name string) error {
+	return c.post(ctx, fmt.Sprintf("/router/protocols/%s/start", name), nil)
+}
+
+// StopProtocol calls POST /router/protocols/{name}/stop.
+func (c *RouterClient) StopProtocol(ctx context.Context, name string) error {
+	return c.post(ctx, fmt.Sprintf("/router/protocols/%s/stop", name), nil)
+}
+
+func (c *RouterClient) post(ctx context.Context, path string, body interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("k8s: %s %s: unexpected status %d", http.MethodPost, path, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package k8s
+
+import "context"
+
+// Source lists the Gateway API object kinds this controller watches and
+// writes status back for. In a real cluster it is a thin adapter over a
+// client-go dynamic or typed client against the Gateway API CRDs; the
+// InformerFactory below only ever talks to this interface, so swapping a
+// fake Source in for tests (or a different backing client in production)
+// doesn't touch the controller.
+type Source interface {
+	ListGatewayClasses(ctx context.Context) ([]GatewayClass, error)
+	ListGateways(ctx context.Context) ([]Gateway, error)
+	ListHTTPRoutes(ctx context.Context) ([]HTTPRoute, error)
+	ListTCPRoutes(ctx context.Context) ([]TCPRoute, error)
+	ListTLSRoutes(ctx context.Context) ([]TLSRoute, error)
+	ListUDPRoutes(ctx context.Context) ([]UDPRoute, error)
+	ListReferenceGrants(ctx context.Context) ([]ReferenceGrant, error)
+	ListBackendTLSPolicies(ctx context.Context) ([]BackendTLSPolicy, error)
+
+	// PatchGatewayStatus and PatchRouteStatus write the conditions this
+	// controller computed back through the API server, the same way a
+	// real controller's client-go client issues a status subresource
+	// PATCH instead of touching .spec.
+	PatchGatewayStatus(ctx context.Context, namespacedName string, conditions []Condition) error
+	PatchRouteStatus(ctx context.Context, kind, namespacedName string, parents []RouteParentStatus) error
+}
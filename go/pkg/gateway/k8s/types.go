@@ -0,0 +1,242 @@
+// Package k8s runs a Kubernetes Gateway API v1 controller in front of
+// router-sim: it watches GatewayClass/Gateway/*Route/ReferenceGrant/
+// BackendTLSPolicy objects and programs the simulated router and Aviatrix
+// cloud gateways to match, the same way an in-cluster Gateway API
+// implementation (Envoy Gateway, Traefik, Contour) drives its own data
+// plane off the same CRDs. The object types below are a minimal,
+// router-sim-relevant subset of sigs.k8s.io/gateway-api/apis/v1 rather
+// than a full vendor of that module, since this controller only reads the
+// fields it acts on.
+package k8s
+
+import "time"
+
+// ObjectMeta is the identity and bookkeeping every Gateway API object
+// carries, trimmed to what the controller needs: a namespaced name to key
+// off of and a Generation to stamp into status conditions.
+type ObjectMeta struct {
+	Namespace  string
+	Name       string
+	Generation int64
+}
+
+// NamespacedName returns "namespace/name", used as the cache key for every
+// Lister in this package.
+func (m ObjectMeta) NamespacedName() string {
+	return m.Namespace + "/" + m.Name
+}
+
+// GatewayClass is the Gateway API resource a cluster operator creates once
+// per controller implementation; its Spec.ControllerName is how a Gateway
+// opts into being reconciled by this controller instead of some other one
+// installed in the same cluster.
+type GatewayClass struct {
+	ObjectMeta
+	Spec struct {
+		ControllerName string
+	}
+	Status struct {
+		Conditions []Condition
+	}
+}
+
+// Listener is one Gateway listener: a protocol/port pair the simulated
+// router should accept traffic on, optionally scoped to a hostname.
+type Listener struct {
+	Name     string
+	Hostname string
+	Port     int32
+	Protocol string // HTTP, HTTPS, TCP, TLS, UDP
+}
+
+// Gateway is a request for a data plane instance. Its GatewayClassName
+// must resolve to a GatewayClass accepted by this controller before the
+// Gateway itself is reconciled.
+type Gateway struct {
+	ObjectMeta
+	Spec struct {
+		GatewayClassName string
+		Listeners        []Listener
+	}
+	Status struct {
+		Conditions []Condition
+	}
+}
+
+// BackendRef points a route rule at a Service-shaped backend. Namespace is
+// only set when the backend lives outside the route's own namespace, in
+// which case a ReferenceGrant must authorize the cross-namespace read.
+type BackendRef struct {
+	Namespace string
+	Name      string
+	Port      int32
+	Weight    int32
+}
+
+// ParentRef names the Gateway (and optionally one of its Listeners) a
+// route attaches to.
+type ParentRef struct {
+	Name        string
+	SectionName string
+}
+
+// HTTPRouteRule is one HTTPRoute rule: a set of path/header matches and
+// the weighted backends traffic matching them is split across.
+type HTTPRouteRule struct {
+	PathPrefix  string
+	Headers     map[string]string
+	BackendRefs []BackendRef
+}
+
+// HTTPRoute programs L7 routing for one or more Gateway listeners.
+type HTTPRoute struct {
+	ObjectMeta
+	Spec struct {
+		ParentRefs []ParentRef
+		Hostnames  []string
+		Rules      []HTTPRouteRule
+	}
+	Status struct {
+		Parents []RouteParentStatus
+	}
+}
+
+// TCPRoute programs an L4 TCP listener straight through to its backends,
+// with no path/header matching.
+type TCPRoute struct {
+	ObjectMeta
+	Spec struct {
+		ParentRefs  []ParentRef
+		BackendRefs []BackendRef
+	}
+	Status struct {
+		Parents []RouteParentStatus
+	}
+}
+
+// TLSRoute is TCPRoute's TLS-passthrough counterpart, matched by SNI
+// instead of accepting plaintext.
+type TLSRoute struct {
+	ObjectMeta
+	Spec struct {
+		ParentRefs  []ParentRef
+		Hostnames   []string
+		BackendRefs []BackendRef
+	}
+	Status struct {
+		Parents []RouteParentStatus
+	}
+}
+
+// UDPRoute is TCPRoute's UDP counterpart.
+type UDPRoute struct {
+	ObjectMeta
+	Spec struct {
+		ParentRefs  []ParentRef
+		BackendRefs []BackendRef
+	}
+	Status struct {
+		Parents []RouteParentStatus
+	}
+}
+
+// ReferenceGrant authorizes a route in GrantedFromNamespace to reference
+// a backend in the namespace the grant lives in; without a matching grant
+// a cross-namespace BackendRef fails ResolvedRefs.
+type ReferenceGrant struct {
+	ObjectMeta
+	Spec struct {
+		FromNamespace string
+		FromKind      string
+		ToName        string // empty means "any backend in this namespace"
+	}
+}
+
+// Allows reports whether g authorizes fromNamespace/fromKind to reference
+// a backend named toName in g's own namespace.
+func (g ReferenceGrant) Allows(fromNamespace, fromKind, toName string) bool {
+	return g.Spec.FromNamespace == fromNamespace &&
+		g.Spec.FromKind == fromKind &&
+		(g.Spec.ToName == "" || g.Spec.ToName == toName)
+}
+
+// BackendTLSPolicy pins the TLS validation router-sim uses when a route's
+// backend is itself HTTPS, naming the CA bundle and SNI to present.
+type BackendTLSPolicy struct {
+	ObjectMeta
+	Spec struct {
+		TargetRefName string
+		CACertRef     string
+		Hostname      string
+	}
+}
+
+// ConditionType is one of the three status conditions this controller
+// reports, matching the Gateway API's RouteConditionType/
+// GatewayConditionType vocabulary.
+type ConditionType string
+
+const (
+	// ConditionAccepted reports whether the controller recognized and
+	// will process the object at all (e.g. its GatewayClass resolves to
+	// us, its parent Gateway exists).
+	ConditionAccepted ConditionType = "Accepted"
+	// ConditionProgrammed reports whether the translation succeeded and
+	// was applied to the router/Aviatrix side.
+	ConditionProgrammed ConditionType = "Programmed"
+	// ConditionResolvedRefs reports whether every BackendRef the object
+	// names was found and, for cross-namespace refs, authorized by a
+	// ReferenceGrant.
+	ConditionResolvedRefs ConditionType = "ResolvedRefs"
+)
+
+// ConditionStatus mirrors metav1.ConditionStatus's three-valued logic:
+// Unknown is the default until the controller has actually evaluated the
+// condition once.
+type ConditionStatus string
+
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// Condition is one status condition entry, in the same shape client-go's
+// apimachinery meta/v1 Condition uses so it round-trips to a real
+// apiserver unchanged.
+type Condition struct {
+	Type               ConditionType
+	Status             ConditionStatus
+	Reason             string
+	Message            string
+	ObservedGeneration int64
+	LastTransitionTime time.Time
+}
+
+// RouteParentStatus is the per-ParentRef status block a route reports,
+// since the same HTTPRoute can attach to several Gateways and each needs
+// its own Accepted/ResolvedRefs verdict.
+type RouteParentStatus struct {
+	ParentRef      ParentRef
+	ControllerName string
+	Conditions     []Condition
+}
+
+// setCondition upserts a condition by Type into conditions, stamping
+// LastTransitionTime only when Status actually changes so a no-op
+// reconcile doesn't churn the object's resourceVersion.
+func setCondition(conditions []Condition, next Condition, now time.Time) []Condition {
+	for i, c := range conditions {
+		if c.Type != next.Type {
+			continue
+		}
+		next.LastTransitionTime = c.LastTransitionTime
+		if c.Status != next.Status {
+			next.LastTransitionTime = now
+		}
+		conditions[i] = next
+		return conditions
+	}
+	next.LastTransitionTime = now
+	return append(conditions, next)
+}
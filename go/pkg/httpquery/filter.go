@@ -0,0 +1,70 @@
+package httpquery
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// filterOp is a single RSQL-style comparison such as cloud_type==aws.
+type filterOp struct {
+	field string
+	op    string
+	value string
+}
+
+// filterOperators lists the comparators parseFilter recognizes, longest
+// first so "!=" isn't mistaken for a "=" prefix.
+var filterOperators = []string{"==", "!="}
+
+// parseFilter splits an RSQL-style filter expression into its clauses.
+// Clauses are joined with ";" and all of them must match (AND); this
+// server doesn't need OR ("," in RSQL) or the relational operators
+// (=gt=, =lt=, ...) yet, so only equality and inequality are implemented.
+func parseFilter(expr string) ([]filterOp, error) {
+	var clauses []filterOp
+	for _, part := range strings.Split(expr, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		var op string
+		var idx int
+		for _, candidate := range filterOperators {
+			if i := strings.Index(part, candidate); i >= 0 {
+				op = candidate
+				idx = i
+				break
+			}
+		}
+		if op == "" {
+			return nil, fmt.Errorf("httpquery: invalid filter clause %q", part)
+		}
+
+		clauses = append(clauses, filterOp{
+			field: strings.TrimSpace(part[:idx]),
+			op:    op,
+			value: strings.TrimSpace(part[idx+len(op):]),
+		})
+	}
+	return clauses, nil
+}
+
+// matchesAll reports whether row satisfies every clause.
+func matchesAll(row reflect.Value, clauses []filterOp) bool {
+	for _, clause := range clauses {
+		fv, ok := fieldByQueryName(row, clause.field)
+		if !ok {
+			return false
+		}
+		match := stringifyValue(fv) == clause.value
+		if clause.op == "!=" {
+			match = !match
+		}
+		if !match {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,265 @@
+// Package httpquery implements the list-query conventions shared by every
+// List* endpoint in this server: cursor-based pagination, RSQL-style
+// filtering, sorting, and sparse fieldsets. A handler that already has its
+// full result slice in memory calls Apply to get back the page the caller
+// asked for, then Respond to write it out with the X-Total-Count and RFC
+// 5988 Link headers the rest of the response envelope never had to carry
+// before.
+//
+// This operates on whatever slice a handler already built from its
+// backing client (aviatrix.TransitGateway, analytics stats rows, ...), so
+// it works entirely through reflection rather than requiring every listed
+// type to implement an interface.
+package httpquery
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultPageSize is how many items Apply returns when the caller doesn't
+// send page_size.
+const DefaultPageSize = 50
+
+// MaxPageSize caps page_size so a client can't force a handler to marshal
+// an entire backing collection in one response.
+const MaxPageSize = 500
+
+// Result is the page Apply produces: Data is the (filtered, sorted,
+// projected) slice for this page, NextPageToken is empty once the caller
+// has paged through everything, and Total is the filtered item count
+// across every page, not just this one.
+type Result struct {
+	Data          interface{}
+	NextPageToken string
+	Total         int
+}
+
+// Apply filters, sorts, paginates, and optionally projects items -
+// which must be a slice - according to the filter, sort, fields,
+// page_size, and page_token query parameters on c.Request. items is never
+// mutated; Apply always works over a copy.
+func Apply(c *gin.Context, items interface{}) (*Result, error) {
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("httpquery: Apply requires a slice, got %T", items)
+	}
+
+	rows := make([]reflect.Value, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		rows[i] = v.Index(i)
+	}
+
+	q := c.Request.URL.Query()
+
+	if filter := q.Get("filter"); filter != "" {
+		clauses, err := parseFilter(filter)
+		if err != nil {
+			return nil, err
+		}
+		filtered := rows[:0:0]
+		for _, row := range rows {
+			if matchesAll(row, clauses) {
+				filtered = append(filtered, row)
+			}
+		}
+		rows = filtered
+	}
+
+	if sortBy := q.Get("sort"); sortBy != "" {
+		keys, err := parseSort(sortBy)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(rows, func(i, j int) bool { return lessRows(rows[i], rows[j], keys) })
+	}
+
+	total := len(rows)
+
+	offset, err := decodePageToken(q.Get("page_token"))
+	if err != nil {
+		return nil, err
+	}
+	pageSize, err := parsePageSize(q.Get("page_size"))
+	if err != nil {
+		return nil, err
+	}
+
+	if offset > len(rows) {
+		offset = len(rows)
+	}
+	end := offset + pageSize
+	if end > len(rows) {
+		end = len(rows)
+	}
+	page := rows[offset:end]
+
+	nextToken := ""
+	if end < len(rows) {
+		nextToken = encodePageToken(end)
+	}
+
+	data, err := projectRows(page, q.Get("fields"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{Data: data, NextPageToken: nextToken, Total: total}, nil
+}
+
+// Respond writes result as the response body in this server's usual
+// {"status": "success", "data": ...} envelope, adding next_page_token and
+// total alongside data, and setting the X-Total-Count and (when another
+// page remains) RFC 5988 Link headers.
+func Respond(c *gin.Context, result *Result) {
+	c.Header("X-Total-Count", strconv.Itoa(result.Total))
+	if result.NextPageToken != "" {
+		c.Header("Link", nextLink(c, result.NextPageToken))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":          "success",
+		"data":            result.Data,
+		"next_page_token": result.NextPageToken,
+		"total":           result.Total,
+	})
+}
+
+// nextLink builds the RFC 5988 Link header value pointing at the next
+// page, reusing the request's own URL with page_token replaced.
+func nextLink(c *gin.Context, nextPageToken string) string {
+	u := *c.Request.URL
+	if u.Scheme == "" {
+		u.Scheme = "http"
+	}
+	if u.Host == "" {
+		u.Host = c.Request.Host
+	}
+	q := u.Query()
+	q.Set("page_token", nextPageToken)
+	u.RawQuery = q.Encode()
+	return fmt.Sprintf("<%s>; rel=\"next\"", u.String())
+}
+
+func parsePageSize(raw string) (int, error) {
+	if raw == "" {
+		return DefaultPageSize, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("httpquery: invalid page_size %q", raw)
+	}
+	if n > MaxPageSize {
+		n = MaxPageSize
+	}
+	return n, nil
+}
+
+// decodePageToken and encodePageToken round-trip an offset into this
+// collection. The token is opaque to callers, as a cursor should be, but
+// it's only meaningful paired with the same filter and sort that produced
+// it - paging with a changed filter or sort restarts from that offset
+// into the newly ordered collection rather than erroring, the same
+// trade-off an in-memory cursor makes anywhere else in this codebase.
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("httpquery: invalid page_token")
+	}
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("httpquery: invalid page_token")
+	}
+	return offset, nil
+}
+
+func encodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// fieldName returns the name a filter, sort, or fields parameter should
+// use to address f: its JSON tag name if it has one, its Go field name
+// otherwise.
+func fieldName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if tag == "" || tag == "-" {
+		return f.Name
+	}
+	if i := strings.IndexByte(tag, ','); i >= 0 {
+		tag = tag[:i]
+	}
+	if tag == "" {
+		return f.Name
+	}
+	return tag
+}
+
+// structValue dereferences row to the struct it holds, following a
+// pointer if items was a []*T.
+func structValue(row reflect.Value) (reflect.Value, bool) {
+	for row.Kind() == reflect.Ptr || row.Kind() == reflect.Interface {
+		if row.IsNil() {
+			return reflect.Value{}, false
+		}
+		row = row.Elem()
+	}
+	if row.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	return row, true
+}
+
+// fieldByQueryName finds the struct field on row addressed by name,
+// matching its JSON tag name or Go field name case-insensitively.
+func fieldByQueryName(row reflect.Value, name string) (reflect.Value, bool) {
+	sv, ok := structValue(row)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	t := sv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+		if strings.EqualFold(fieldName(f), name) {
+			return sv.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// stringifyValue renders v the same way it would compare or sort -
+// good enough for strings, numbers, bools, and anything with a String()
+// method (e.g. time.Time).
+func stringifyValue(v reflect.Value) string {
+	if v.CanInterface() {
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			return s.String()
+		}
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,202 @@
+package httpquery
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type widget struct {
+	Name   string `json:"name"`
+	Region string `json:"region"`
+	Size   int    `json:"size"`
+}
+
+func testContext(t *testing.T, rawQuery string) *gin.Context {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/widgets?"+rawQuery, nil)
+	return c
+}
+
+func sampleWidgets() []widget {
+	return []widget{
+		{Name: "a", Region: "us-east", Size: 3},
+		{Name: "b", Region: "us-west", Size: 1},
+		{Name: "c", Region: "us-east", Size: 2},
+	}
+}
+
+func TestApplyDefaultsToUnfilteredUnsortedFirstPage(t *testing.T) {
+	c := testContext(t, "")
+	result, err := Apply(c, sampleWidgets())
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if result.Total != 3 {
+		t.Fatalf("Total = %d, want 3", result.Total)
+	}
+	if result.NextPageToken != "" {
+		t.Fatalf("NextPageToken = %q, want empty since every row fits on one page", result.NextPageToken)
+	}
+	data, ok := result.Data.([]interface{})
+	if !ok || len(data) != 3 {
+		t.Fatalf("Data = %#v, want a 3-element slice", result.Data)
+	}
+}
+
+func TestApplyFilterEquality(t *testing.T) {
+	c := testContext(t, "filter=region==us-east")
+	result, err := Apply(c, sampleWidgets())
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if result.Total != 2 {
+		t.Fatalf("Total = %d, want 2 matching region==us-east", result.Total)
+	}
+}
+
+func TestApplyFilterInequality(t *testing.T) {
+	c := testContext(t, "filter=region!=us-east")
+	result, err := Apply(c, sampleWidgets())
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if result.Total != 1 {
+		t.Fatalf("Total = %d, want 1 matching region!=us-east", result.Total)
+	}
+}
+
+func TestApplyFilterInvalidClause(t *testing.T) {
+	c := testContext(t, "filter=region~~us-east")
+	if _, err := Apply(c, sampleWidgets()); err == nil {
+		t.Fatalf("Apply with an unrecognized filter operator returned nil error, want an error")
+	}
+}
+
+func TestApplySortAscendingAndDescending(t *testing.T) {
+	c := testContext(t, "sort=size")
+	result, err := Apply(c, sampleWidgets())
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	data := result.Data.([]interface{})
+	if data[0].(widget).Name != "b" || data[2].(widget).Name != "a" {
+		t.Fatalf("Data = %+v, want ascending by size (b, c, a)", data)
+	}
+
+	c = testContext(t, "sort=-size")
+	result, err = Apply(c, sampleWidgets())
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	data = result.Data.([]interface{})
+	if data[0].(widget).Name != "a" || data[2].(widget).Name != "b" {
+		t.Fatalf("Data = %+v, want descending by size (a, c, b)", data)
+	}
+}
+
+func TestApplyPagination(t *testing.T) {
+	c := testContext(t, "page_size=2&sort=name")
+	first, err := Apply(c, sampleWidgets())
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(first.Data.([]interface{})) != 2 {
+		t.Fatalf("first page len = %d, want 2", len(first.Data.([]interface{})))
+	}
+	if first.NextPageToken == "" {
+		t.Fatalf("NextPageToken = empty, want a token since a third row remains")
+	}
+
+	c = testContext(t, "page_size=2&sort=name&page_token="+first.NextPageToken)
+	second, err := Apply(c, sampleWidgets())
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	data := second.Data.([]interface{})
+	if len(data) != 1 || data[0].(widget).Name != "c" {
+		t.Fatalf("second page = %+v, want the single remaining row (c)", data)
+	}
+	if second.NextPageToken != "" {
+		t.Fatalf("NextPageToken = %q on the last page, want empty", second.NextPageToken)
+	}
+}
+
+func TestApplyPageSizeCappedAtMax(t *testing.T) {
+	c := testContext(t, "page_size=100000")
+	if _, err := Apply(c, sampleWidgets()); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+}
+
+func TestApplyInvalidPageToken(t *testing.T) {
+	c := testContext(t, "page_token=not-valid-base64!!")
+	if _, err := Apply(c, sampleWidgets()); err == nil {
+		t.Fatalf("Apply with a malformed page_token returned nil error, want an error")
+	}
+}
+
+func TestApplyFieldsProjection(t *testing.T) {
+	c := testContext(t, "fields=name")
+	result, err := Apply(c, sampleWidgets())
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	data, ok := result.Data.([]map[string]interface{})
+	if !ok || len(data) != 3 {
+		t.Fatalf("Data = %#v, want a 3-element slice of projected maps", result.Data)
+	}
+	if _, hasRegion := data[0]["region"]; hasRegion {
+		t.Fatalf("projected row %+v still has region, want only the requested field", data[0])
+	}
+	if _, hasName := data[0]["name"]; !hasName {
+		t.Fatalf("projected row %+v missing the requested name field", data[0])
+	}
+}
+
+func TestApplyFieldsProjectionUnknownField(t *testing.T) {
+	c := testContext(t, "fields=nonexistent")
+	if _, err := Apply(c, sampleWidgets()); err == nil {
+		t.Fatalf("Apply with an unknown projected field returned nil error, want an error")
+	}
+}
+
+func TestApplyRejectsNonSlice(t *testing.T) {
+	c := testContext(t, "")
+	if _, err := Apply(c, widget{Name: "a"}); err == nil {
+		t.Fatalf("Apply(non-slice) returned nil error, want an error")
+	}
+}
+
+func TestRespondSetsHeadersAndBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/widgets?page_size=1", nil)
+
+	Respond(c, &Result{Data: sampleWidgets(), NextPageToken: "abc", Total: 3})
+
+	if got := w.Header().Get("X-Total-Count"); got != "3" {
+		t.Fatalf("X-Total-Count = %q, want %q", got, "3")
+	}
+	if got := w.Header().Get("Link"); got == "" {
+		t.Fatalf("Link header missing when NextPageToken is set")
+	}
+}
+
+func TestRespondOmitsLinkHeaderOnLastPage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/widgets", nil)
+
+	Respond(c, &Result{Data: sampleWidgets(), Total: 3})
+
+	if got := w.Header().Get("Link"); got != "" {
+		t.Fatalf("Link header = %q, want empty with no NextPageToken", got)
+	}
+}
@@ -0,0 +1,43 @@
+package httpquery
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// projectRows returns page as-is when fields is empty, or - when the
+// caller asked for a sparse fieldset via fields=name,region,status -
+// as a slice of maps holding only the requested fields, keyed by the
+// same name a filter or sort clause would use to address them.
+func projectRows(page []reflect.Value, fields string) (interface{}, error) {
+	if fields == "" {
+		out := make([]interface{}, len(page))
+		for i, row := range page {
+			out[i] = row.Interface()
+		}
+		return out, nil
+	}
+
+	var names []string
+	for _, name := range strings.Split(fields, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	out := make([]map[string]interface{}, len(page))
+	for i, row := range page {
+		projected := make(map[string]interface{}, len(names))
+		for _, name := range names {
+			fv, ok := fieldByQueryName(row, name)
+			if !ok {
+				return nil, fmt.Errorf("httpquery: unknown field %q", name)
+			}
+			projected[name] = fv.Interface()
+		}
+		out[i] = projected
+	}
+	return out, nil
+}
@@ -0,0 +1,57 @@
+package httpquery
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// sortKey is one comma-separated term of a sort parameter, e.g. the
+// "-created_at" in "sort=-created_at,name".
+type sortKey struct {
+	field      string
+	descending bool
+}
+
+// parseSort splits a sort parameter into its keys, applied in order:
+// ties on the first key fall through to the second, and so on.
+func parseSort(raw string) ([]sortKey, error) {
+	var keys []sortKey
+	for _, term := range strings.Split(raw, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		key := sortKey{field: term}
+		if strings.HasPrefix(term, "-") {
+			key.descending = true
+			key.field = term[1:]
+		}
+		if key.field == "" {
+			return nil, fmt.Errorf("httpquery: invalid sort term %q", term)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// lessRows reports whether a sorts before b under keys.
+func lessRows(a, b reflect.Value, keys []sortKey) bool {
+	for _, key := range keys {
+		av, aok := fieldByQueryName(a, key.field)
+		bv, bok := fieldByQueryName(b, key.field)
+		if !aok || !bok {
+			continue
+		}
+
+		as, bs := stringifyValue(av), stringifyValue(bv)
+		if as == bs {
+			continue
+		}
+		if key.descending {
+			return as > bs
+		}
+		return as < bs
+	}
+	return false
+}
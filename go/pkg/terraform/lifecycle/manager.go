@@ -0,0 +1,189 @@
+// Package lifecycle drives a generated Terraform working directory through
+// init/plan/apply/destroy using terraform-exec, so callers can manage
+// infrastructure lifecycle programmatically instead of shelling out to the
+// terraform binary themselves.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+)
+
+// Manager drives the Terraform CLI for a single working directory and
+// tracks the infrastructure state produced by successive operations.
+type Manager struct {
+	tf        *tfexec.Terraform
+	workDir   string
+	lastState *InfrastructureState
+}
+
+// Config configures a Manager.
+type Config struct {
+	// WorkDir is the directory containing the generated .tf files.
+	WorkDir string
+	// ExecPath is the path to the terraform binary. Defaults to "terraform"
+	// resolved from PATH when empty.
+	ExecPath string
+}
+
+// NewManager creates a Manager for the given working directory.
+func NewManager(cfg Config) (*Manager, error) {
+	if cfg.WorkDir == "" {
+		return nil, fmt.Errorf("working directory is required")
+	}
+
+	execPath := cfg.ExecPath
+	if execPath == "" {
+		execPath = "terraform"
+	}
+
+	tf, err := tfexec.NewTerraform(cfg.WorkDir, execPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create terraform executor: %w", err)
+	}
+
+	return &Manager{tf: tf, workDir: cfg.WorkDir}, nil
+}
+
+// Init runs `terraform init` in the working directory.
+func (m *Manager) Init(ctx context.Context) error {
+	if err := m.tf.Init(ctx); err != nil {
+		return fmt.Errorf("terraform init failed: %w", err)
+	}
+	return nil
+}
+
+// PlanOutput summarizes a `terraform plan` run.
+type PlanOutput struct {
+	HasChanges       bool      `json:"has_changes"`
+	ResourceAdds     int       `json:"resource_adds"`
+	ResourceChanges  int       `json:"resource_changes"`
+	ResourceDestroys int       `json:"resource_destroys"`
+	PlannedAt        time.Time `json:"planned_at"`
+}
+
+// Plan runs `terraform plan` and returns a typed summary of the proposed
+// changes, written to a plan file so a subsequent Apply can use it.
+func (m *Manager) Plan(ctx context.Context, planFile string) (*PlanOutput, error) {
+	hasChanges, err := m.tf.Plan(ctx, tfexec.Out(planFile))
+	if err != nil {
+		return nil, fmt.Errorf("terraform plan failed: %w", err)
+	}
+
+	plan, err := m.tf.ShowPlanFile(ctx, planFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan file: %w", err)
+	}
+
+	output := &PlanOutput{
+		HasChanges: hasChanges,
+		PlannedAt:  time.Now(),
+	}
+
+	for _, change := range plan.ResourceChanges {
+		switch {
+		case change.Change.Actions.Create():
+			output.ResourceAdds++
+		case change.Change.Actions.Delete():
+			output.ResourceDestroys++
+		case change.Change.Actions.Update():
+			output.ResourceChanges++
+		}
+	}
+
+	return output, nil
+}
+
+// ApplyOutput is the typed result of a `terraform apply` run: the
+// resources Terraform reports in state afterward, plus any root module
+// outputs.
+type ApplyOutput struct {
+	Resources []ResourceState        `json:"resources"`
+	Outputs   map[string]interface{} `json:"outputs"`
+	AppliedAt time.Time              `json:"applied_at"`
+}
+
+// ResourceState is a single managed resource as recorded in Terraform state.
+type ResourceState struct {
+	Address    string                 `json:"address"`
+	Type       string                 `json:"type"`
+	Name       string                 `json:"name"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// InfrastructureState is the Manager's in-memory view of the last known
+// state, refreshed after every Apply/Destroy/Refresh call.
+type InfrastructureState struct {
+	Resources []ResourceState `json:"resources"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Apply runs `terraform apply`, optionally against a previously produced
+// plan file, and returns a typed summary of the resulting state.
+func (m *Manager) Apply(ctx context.Context, planFile string) (*ApplyOutput, error) {
+	var opts []tfexec.ApplyOption
+	if planFile != "" {
+		opts = append(opts, tfexec.DirOrPlan(planFile))
+	}
+
+	if err := m.tf.Apply(ctx, opts...); err != nil {
+		return nil, fmt.Errorf("terraform apply failed: %w", err)
+	}
+
+	return m.refresh(ctx)
+}
+
+// Destroy runs `terraform destroy` and refreshes the tracked state.
+func (m *Manager) Destroy(ctx context.Context) error {
+	if err := m.tf.Destroy(ctx); err != nil {
+		return fmt.Errorf("terraform destroy failed: %w", err)
+	}
+
+	m.lastState = &InfrastructureState{UpdatedAt: time.Now()}
+	return nil
+}
+
+// State returns the most recently refreshed infrastructure state, or nil
+// if Apply/Destroy has not yet been called.
+func (m *Manager) State() *InfrastructureState {
+	return m.lastState
+}
+
+// refresh reads the current Terraform state and stores a typed snapshot on
+// the Manager, returning an ApplyOutput for the caller.
+func (m *Manager) refresh(ctx context.Context) (*ApplyOutput, error) {
+	state, err := m.tf.Show(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read terraform state: %w", err)
+	}
+
+	output := &ApplyOutput{
+		Outputs:   make(map[string]interface{}, len(state.Values.Outputs)),
+		AppliedAt: time.Now(),
+	}
+
+	for name, out := range state.Values.Outputs {
+		output.Outputs[name] = out.Value
+	}
+
+	if state.Values.RootModule != nil {
+		for _, res := range state.Values.RootModule.Resources {
+			output.Resources = append(output.Resources, ResourceState{
+				Address:    res.Address,
+				Type:       res.Type,
+				Name:       res.Name,
+				Attributes: res.AttributeValues,
+			})
+		}
+	}
+
+	m.lastState = &InfrastructureState{
+		Resources: output.Resources,
+		UpdatedAt: output.AppliedAt,
+	}
+
+	return output, nil
+}
@@ -0,0 +1,198 @@
+// Package routersim implements a terraform-plugin-sdk v2 provider that
+// drives the router simulator's HTTP API, so Terraform configurations can
+// apply router_sim_router and router_sim_scenario resources against a
+// running simulator instead of only generating .tf snippets for them.
+package routersim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Client is a thin HTTP client for the router simulator's REST API.
+type Client struct {
+	endpoint   string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewClient creates a new simulator client for the given endpoint.
+func NewClient(endpoint, apiKey string) *Client {
+	return &Client{
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// apiError carries the simulator's error payload.
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		var apiErr apiError
+		_ = json.Unmarshal(respBody, &apiErr)
+		if apiErr.Error == "" {
+			apiErr.Error = string(respBody)
+		}
+		return fmt.Errorf("router-sim API error (status %d): %s", resp.StatusCode, apiErr.Error)
+	}
+
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
+
+// RouterInterface mirrors the `interfaces` argument of router_sim_router.
+type RouterInterface struct {
+	Name      string `json:"name"`
+	IPAddress string `json:"ip_address"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// TrafficShaping mirrors the `traffic_shaping` argument of router_sim_router.
+type TrafficShaping struct {
+	RateKbps  int `json:"rate_kbps"`
+	BurstKb   int `json:"burst_kb"`
+	LatencyMs int `json:"latency_ms"`
+}
+
+// NetemImpairment mirrors one entry of the `netem_impairments` argument.
+type NetemImpairment struct {
+	Interface string  `json:"interface"`
+	DelayMs   int     `json:"delay_ms"`
+	LossPct   float64 `json:"loss_pct"`
+}
+
+// Router is the simulator's representation of a router_sim_router resource.
+type Router struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name"`
+	RouterID         string                 `json:"router_id"`
+	Hostname         string                 `json:"hostname"`
+	Interfaces       []RouterInterface      `json:"interfaces"`
+	Protocols        map[string]interface{} `json:"protocols"`
+	TrafficShaping   *TrafficShaping        `json:"traffic_shaping"`
+	NetemImpairments []NetemImpairment      `json:"netem_impairments"`
+	Status           string                 `json:"status"`
+}
+
+// CreateRouter creates a router instance in the simulator.
+func (c *Client) CreateRouter(ctx context.Context, router *Router) (*Router, error) {
+	var created Router
+	if err := c.do(ctx, http.MethodPost, "/api/v1/routers", router, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetRouter fetches a router by ID.
+func (c *Client) GetRouter(ctx context.Context, id string) (*Router, error) {
+	var router Router
+	if err := c.do(ctx, http.MethodGet, "/api/v1/routers/"+id, nil, &router); err != nil {
+		return nil, err
+	}
+	return &router, nil
+}
+
+// UpdateRouter applies changes to an existing router.
+func (c *Client) UpdateRouter(ctx context.Context, id string, router *Router) (*Router, error) {
+	var updated Router
+	if err := c.do(ctx, http.MethodPut, "/api/v1/routers/"+id, router, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteRouter tears down a router instance.
+func (c *Client) DeleteRouter(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/routers/"+id, nil, nil)
+}
+
+// Scenario is the simulator's representation of a router_sim_scenario resource.
+type Scenario struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Routers     []string               `json:"routers"`
+	DurationSec int                    `json:"duration"`
+	Config      map[string]interface{} `json:"config"`
+	Status      string                 `json:"status"`
+	Results     map[string]interface{} `json:"results"`
+}
+
+// CreateScenario starts a new simulation scenario.
+func (c *Client) CreateScenario(ctx context.Context, scenario *Scenario) (*Scenario, error) {
+	var created Scenario
+	if err := c.do(ctx, http.MethodPost, "/api/v1/scenarios", scenario, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetScenario fetches a scenario by ID, including its latest results.
+func (c *Client) GetScenario(ctx context.Context, id string) (*Scenario, error) {
+	var scenario Scenario
+	if err := c.do(ctx, http.MethodGet, "/api/v1/scenarios/"+id, nil, &scenario); err != nil {
+		return nil, err
+	}
+	return &scenario, nil
+}
+
+// UpdateScenario applies changes to an existing scenario.
+func (c *Client) UpdateScenario(ctx context.Context, id string, scenario *Scenario) (*Scenario, error) {
+	var updated Scenario
+	if err := c.do(ctx, http.MethodPut, "/api/v1/scenarios/"+id, scenario, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteScenario stops and removes a scenario.
+func (c *Client) DeleteScenario(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/scenarios/"+id, nil, nil)
+}
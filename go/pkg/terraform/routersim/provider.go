@@ -0,0 +1,48 @@
+package routersim
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Provider builds the *schema.Provider for router-sim, wiring the schema
+// described by RouterSimProvider() in the terraform generator to real
+// CRUD callbacks against the simulator's HTTP API.
+func Provider() *schema.Provider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"endpoint": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ROUTER_SIM_ENDPOINT", "http://localhost:8080"),
+				Description: "Router simulator endpoint",
+			},
+			"api_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("ROUTER_SIM_API_KEY", ""),
+				Description: "API key",
+			},
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"router_sim_router":   resourceRouter(),
+			"router_sim_scenario": resourceScenario(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{},
+		ConfigureContextFunc: configureProvider,
+	}
+}
+
+func configureProvider(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	endpoint := d.Get("endpoint").(string)
+	apiKey := d.Get("api_key").(string)
+
+	if endpoint == "" {
+		return nil, diag.Errorf("endpoint must not be empty")
+	}
+
+	return NewClient(endpoint, apiKey), nil
+}
@@ -0,0 +1,172 @@
+package routersim
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceRouter implements the router_sim_router resource, managing a
+// router simulator instance including its interfaces, protocols, traffic
+// shaping and netem impairments.
+func resourceRouter() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRouterCreate,
+		ReadContext:   resourceRouterRead,
+		UpdateContext: resourceRouterUpdate,
+		DeleteContext: resourceRouterDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name":      {Type: schema.TypeString, Required: true},
+			"router_id": {Type: schema.TypeString, Required: true, ForceNew: true},
+			"hostname":  {Type: schema.TypeString, Optional: true},
+			"interfaces": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name":       {Type: schema.TypeString, Required: true},
+						"ip_address": {Type: schema.TypeString, Optional: true},
+						"enabled":    {Type: schema.TypeBool, Optional: true, Default: true},
+					},
+				},
+			},
+			"protocols": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"traffic_shaping": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"rate_kbps":  {Type: schema.TypeInt, Optional: true},
+						"burst_kb":   {Type: schema.TypeInt, Optional: true},
+						"latency_ms": {Type: schema.TypeInt, Optional: true},
+					},
+				},
+			},
+			"netem_impairments": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"interface": {Type: schema.TypeString, Required: true},
+						"delay_ms":  {Type: schema.TypeInt, Optional: true},
+						"loss_pct":  {Type: schema.TypeFloat, Optional: true},
+					},
+				},
+			},
+			"status":    {Type: schema.TypeString, Computed: true},
+			"routes":    {Type: schema.TypeList, Computed: true, Elem: &schema.Schema{Type: schema.TypeMap}},
+			"neighbors": {Type: schema.TypeList, Computed: true, Elem: &schema.Schema{Type: schema.TypeMap}},
+		},
+	}
+}
+
+func expandRouter(d *schema.ResourceData) *Router {
+	router := &Router{
+		Name:     d.Get("name").(string),
+		RouterID: d.Get("router_id").(string),
+		Hostname: d.Get("hostname").(string),
+	}
+
+	for _, raw := range d.Get("interfaces").([]interface{}) {
+		iface := raw.(map[string]interface{})
+		router.Interfaces = append(router.Interfaces, RouterInterface{
+			Name:      iface["name"].(string),
+			IPAddress: iface["ip_address"].(string),
+			Enabled:   iface["enabled"].(bool),
+		})
+	}
+
+	if protocols, ok := d.Get("protocols").(map[string]interface{}); ok && len(protocols) > 0 {
+		router.Protocols = protocols
+	}
+
+	if shapingList := d.Get("traffic_shaping").([]interface{}); len(shapingList) > 0 {
+		shaping := shapingList[0].(map[string]interface{})
+		router.TrafficShaping = &TrafficShaping{
+			RateKbps:  shaping["rate_kbps"].(int),
+			BurstKb:   shaping["burst_kb"].(int),
+			LatencyMs: shaping["latency_ms"].(int),
+		}
+	}
+
+	for _, raw := range d.Get("netem_impairments").([]interface{}) {
+		impairment := raw.(map[string]interface{})
+		router.NetemImpairments = append(router.NetemImpairments, NetemImpairment{
+			Interface: impairment["interface"].(string),
+			DelayMs:   impairment["delay_ms"].(int),
+			LossPct:   impairment["loss_pct"].(float64),
+		})
+	}
+
+	return router
+}
+
+func flattenRouter(d *schema.ResourceData, router *Router) diag.Diagnostics {
+	d.SetId(router.ID)
+	if err := d.Set("name", router.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("router_id", router.RouterID); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("hostname", router.Hostname); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("status", router.Status); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceRouterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	router, err := client.CreateRouter(ctx, expandRouter(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return flattenRouter(d, router)
+}
+
+func resourceRouterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	router, err := client.GetRouter(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return flattenRouter(d, router)
+}
+
+func resourceRouterUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	router, err := client.UpdateRouter(ctx, d.Id(), expandRouter(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return flattenRouter(d, router)
+}
+
+func resourceRouterDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	if err := client.DeleteRouter(ctx, d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,129 @@
+package routersim
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceScenario implements the router_sim_scenario resource, starting a
+// simulation run across a set of routers and exposing its results once the
+// simulator reports completion.
+func resourceScenario() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceScenarioCreate,
+		ReadContext:   resourceScenarioRead,
+		UpdateContext: resourceScenarioUpdate,
+		DeleteContext: resourceScenarioDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name":        {Type: schema.TypeString, Required: true},
+			"description": {Type: schema.TypeString, Optional: true},
+			"routers": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"duration": {Type: schema.TypeInt, Optional: true, Default: 3600},
+			"config": {
+				Type:     schema.TypeMap,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"status":  {Type: schema.TypeString, Computed: true},
+			"results": {Type: schema.TypeMap, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+		},
+	}
+}
+
+func expandScenario(d *schema.ResourceData) *Scenario {
+	scenario := &Scenario{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		DurationSec: d.Get("duration").(int),
+	}
+
+	for _, raw := range d.Get("routers").([]interface{}) {
+		scenario.Routers = append(scenario.Routers, raw.(string))
+	}
+
+	if config, ok := d.Get("config").(map[string]interface{}); ok {
+		scenario.Config = config
+	}
+
+	return scenario
+}
+
+func flattenScenario(d *schema.ResourceData, scenario *Scenario) diag.Diagnostics {
+	d.SetId(scenario.ID)
+	if err := d.Set("name", scenario.Name); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("status", scenario.Status); err != nil {
+		return diag.FromErr(err)
+	}
+
+	results := make(map[string]string, len(scenario.Results))
+	for k, v := range scenario.Results {
+		results[k] = toString(v)
+	}
+	if err := d.Set("results", results); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func toString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return ""
+}
+
+func resourceScenarioCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	scenario, err := client.CreateScenario(ctx, expandScenario(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return flattenScenario(d, scenario)
+}
+
+func resourceScenarioRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	scenario, err := client.GetScenario(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return flattenScenario(d, scenario)
+}
+
+func resourceScenarioUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	scenario, err := client.UpdateScenario(ctx, d.Id(), expandScenario(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return flattenScenario(d, scenario)
+}
+
+func resourceScenarioDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client)
+
+	if err := client.DeleteScenario(ctx, d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,234 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// defaultResourceExampleTmpl is the built-in template for
+// examples/resources/<name>/resource.tf, overridable by a matching file
+// under <providerDir>/templates/resources/resource.tf.tmpl.
+const defaultResourceExampleTmpl = `resource "{{.Type}}" "example" {
+{{range .Arguments}}{{if or .Required .Optional}}  {{.Name}} = {{exampleValue .Type}}
+{{end}}{{end}}}
+`
+
+const defaultDataSourceExampleTmpl = `data "{{.Type}}" "example" {
+{{range .Arguments}}{{if .Required}}  {{.Name}} = {{exampleValue .Type}}
+{{end}}{{end}}}
+`
+
+const defaultImportScriptTmpl = `#!/bin/sh
+# Import an existing {{.Type}} into Terraform state.
+terraform import {{.Type}}.example <resource-id>
+`
+
+const defaultProviderExampleTmpl = `terraform {
+  required_providers {
+    {{.Name}} = {
+      source  = "local/{{.Name}}"
+      version = "{{.Version}}"
+    }
+  }
+}
+
+provider "{{.Name}}" {
+  # Configure provider-level settings here.
+}
+`
+
+const defaultResourceDocTmpl = `# {{.Type}} ({{if .IsDataSource}}Data Source{{else}}Resource{{end}})
+
+{{.Description}}
+
+## Example Usage
+
+` + "```terraform\n{{.Example}}```" + `
+
+## Argument Reference
+
+| Name | Type | Required | Optional | Computed | Description |
+| --- | --- | --- | --- | --- | --- |
+{{range .Arguments}}| {{.Name}} | {{.Type}} | {{.Required}} | {{.Optional}} | {{.Computed}} | {{.Description}} |
+{{end}}
+
+## Attributes Reference
+
+| Name | Type | Computed | Description |
+| --- | --- | --- | --- |
+{{range .Attributes}}| {{.Name}} | {{.Type}} | {{.Computed}} | {{.Description}} |
+{{end}}
+`
+
+// docsPageData feeds the Markdown doc template for a single resource or
+// data source.
+type docsPageData struct {
+	Type         string
+	Description  string
+	Example      string
+	IsDataSource bool
+	Arguments    []TerraformArgument
+	Attributes   []TerraformAttribute
+}
+
+// generateExamplesTree replaces the single basic.tf stub with a tree
+// mirroring tfplugindocs: examples/provider/provider.tf,
+// examples/resources/<name>/{resource.tf,import.sh},
+// examples/data-sources/<name>/data-source.tf, and a parallel docs/ tree of
+// Markdown pages rendered from the schema. Templates under
+// <providerDir>/templates/ take precedence over the built-ins above, so
+// users can override rendering without forking the generator.
+func generateExamplesTree(provider *TerraformProvider, providerDir, examplesDir string) error {
+	docsDir := filepath.Join(providerDir, "docs")
+
+	if err := os.MkdirAll(filepath.Join(examplesDir, "provider"), 0755); err != nil {
+		return err
+	}
+	if err := renderToFile(provider, providerDir, "provider/provider.tf", defaultProviderExampleTmpl,
+		filepath.Join(examplesDir, "provider", "provider.tf")); err != nil {
+		return err
+	}
+	if err := renderToFile(provider, providerDir, "index.md", "# {{.Name}} Provider\n\n{{.Description}}\n",
+		filepath.Join(docsDir, "index.md")); err != nil {
+		return err
+	}
+
+	for _, resource := range provider.Resources {
+		if err := writeResourceExample(provider, providerDir, examplesDir, docsDir, resource.Type, resource.Description, resource.Arguments, resource.Attributes, false); err != nil {
+			return err
+		}
+	}
+
+	for _, dataSource := range provider.DataSources {
+		if err := writeDataSourceExample(provider, providerDir, examplesDir, docsDir, dataSource.Type, dataSource.Description, dataSource.Arguments, dataSource.Attributes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeResourceExample(provider *TerraformProvider, providerDir, examplesDir, docsDir, resourceType, description string, args []TerraformArgument, attrs []TerraformAttribute, isDataSource bool) error {
+	dir := filepath.Join(examplesDir, "resources", resourceType)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	resourceTF := struct {
+		Type      string
+		Arguments []TerraformArgument
+	}{resourceType, args}
+
+	if err := renderToFile(resourceTF, providerDir, "resources/resource.tf", defaultResourceExampleTmpl, filepath.Join(dir, "resource.tf")); err != nil {
+		return err
+	}
+	if err := renderToFile(resourceTF, providerDir, "resources/import.sh", defaultImportScriptTmpl, filepath.Join(dir, "import.sh")); err != nil {
+		return err
+	}
+
+	example, err := renderString(resourceTF, providerDir, "resources/resource.tf", defaultResourceExampleTmpl)
+	if err != nil {
+		return err
+	}
+
+	return writeDocPage(providerDir, docsDir, "resources", resourceType, description, example, args, attrs, isDataSource)
+}
+
+func writeDataSourceExample(provider *TerraformProvider, providerDir, examplesDir, docsDir, dsType, description string, args []TerraformArgument, attrs []TerraformAttribute) error {
+	dir := filepath.Join(examplesDir, "data-sources", dsType)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	dataSourceTF := struct {
+		Type      string
+		Arguments []TerraformArgument
+	}{dsType, args}
+
+	if err := renderToFile(dataSourceTF, providerDir, "data-sources/data-source.tf", defaultDataSourceExampleTmpl, filepath.Join(dir, "data-source.tf")); err != nil {
+		return err
+	}
+
+	example, err := renderString(dataSourceTF, providerDir, "data-sources/data-source.tf", defaultDataSourceExampleTmpl)
+	if err != nil {
+		return err
+	}
+
+	return writeDocPage(providerDir, docsDir, "data-sources", dsType, description, example, args, attrs, true)
+}
+
+func writeDocPage(providerDir, docsDir, kind, name, description, example string, args []TerraformArgument, attrs []TerraformAttribute, isDataSource bool) error {
+	dir := filepath.Join(docsDir, kind)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	data := docsPageData{
+		Type:         name,
+		Description:  description,
+		Example:      example,
+		IsDataSource: isDataSource,
+		Arguments:    args,
+		Attributes:   attrs,
+	}
+
+	return renderToFile(data, providerDir, kind+"/page.md", defaultResourceDocTmpl, filepath.Join(dir, name+".md"))
+}
+
+// templateFuncs are available to every template rendered by this file.
+var templateFuncs = template.FuncMap{
+	"exampleValue": exampleValueForType,
+}
+
+// exampleValueForType renders a placeholder HCL value for a type string,
+// used in generated example .tf files.
+func exampleValueForType(typeStr string) string {
+	switch {
+	case typeStr == "bool":
+		return "true"
+	case typeStr == "number":
+		return "1"
+	case strings.HasPrefix(typeStr, "list(") || strings.HasPrefix(typeStr, "set("):
+		return "[]"
+	case strings.HasPrefix(typeStr, "map("):
+		return "{}"
+	default:
+		return `"example"`
+	}
+}
+
+// renderToFile renders name (checking <providerDir>/templates/name first,
+// falling back to builtin) with data, writing the result to dest.
+func renderToFile(data interface{}, providerDir, name, builtin, dest string) error {
+	rendered, err := renderString(data, providerDir, name, builtin)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dest, []byte(rendered), 0644)
+}
+
+// renderString renders name with data, preferring a user override under
+// <providerDir>/templates/<name>.tmpl when present.
+func renderString(data interface{}, providerDir, name, builtin string) (string, error) {
+	body := builtin
+
+	overridePath := filepath.Join(providerDir, "templates", name+".tmpl")
+	if contents, err := os.ReadFile(overridePath); err == nil {
+		body = string(contents)
+	}
+
+	t, err := template.New(name).Funcs(templateFuncs).Parse(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	var out strings.Builder
+	if err := t.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+
+	return out.String(), nil
+}
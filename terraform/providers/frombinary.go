@@ -0,0 +1,169 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hc-install/product"
+	"github.com/hashicorp/hc-install/releases"
+	"github.com/hashicorp/terraform-exec/tfexec"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// pinnedTerraformVersion is the Terraform CLI version hc-install fetches
+// when one isn't already on PATH, keeping --from-binary reproducible
+// across machines.
+const pinnedTerraformVersion = "1.7.5"
+
+// ProviderFromBinarySchema regenerates a TerraformProvider from the
+// authoritative schema a compiled provider binary reports, instead of a
+// duplicated Go literal. It writes a scratch working directory that dev
+// overrides pluginDir's binary, runs `terraform init` and
+// `terraform providers schema -json` against it, and translates the
+// resulting tfjson.ProviderSchemas into our internal model.
+func ProviderFromBinarySchema(ctx context.Context, pluginDir, providerSource string) (*TerraformProvider, error) {
+	execPath, err := resolveTerraform(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workDir, err := os.MkdirTemp("", "router-sim-schema-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scratch dir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	if err := writeDevOverrideConfig(workDir, providerSource, pluginDir); err != nil {
+		return nil, err
+	}
+
+	tf, err := tfexec.NewTerraform(workDir, execPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create terraform executor: %w", err)
+	}
+
+	// Dev overrides make `terraform init` a no-op for the overridden
+	// provider, but it's still required to initialize the working
+	// directory's lock/state plumbing.
+	if err := tf.Init(ctx, tfexec.Backend(false)); err != nil {
+		return nil, fmt.Errorf("terraform init failed: %w", err)
+	}
+
+	schemas, err := tf.ProvidersSchema(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("terraform providers schema -json failed: %w", err)
+	}
+
+	return translateProviderSchemas(providerSource, schemas)
+}
+
+// resolveTerraform returns a path to the terraform binary, installing the
+// pinned version via hc-install when it's not already on PATH.
+func resolveTerraform(ctx context.Context) (string, error) {
+	installer := &releases.ExactVersion{
+		Product: product.Terraform,
+		Version: version.Must(version.NewVersion(pinnedTerraformVersion)),
+	}
+
+	execPath, err := installer.Install(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to install terraform %s: %w", pinnedTerraformVersion, err)
+	}
+
+	return execPath, nil
+}
+
+// writeDevOverrideConfig writes a CLI config file pointing providerSource
+// at pluginDir via `dev_overrides`, and points TF_CLI_CONFIG_FILE at it by
+// writing a .terraformrc in workDir that `terraform init` picks up.
+func writeDevOverrideConfig(workDir, providerSource, pluginDir string) error {
+	config := fmt.Sprintf(`provider_installation {
+  dev_overrides {
+    %q = %q
+  }
+  direct {}
+}
+`, providerSource, pluginDir)
+
+	path := filepath.Join(workDir, ".terraformrc")
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		return fmt.Errorf("failed to write dev override config: %w", err)
+	}
+
+	return os.Setenv("TF_CLI_CONFIG_FILE", path)
+}
+
+// translateProviderSchemas converts the tfjson.ProviderSchemas document
+// terraform reports for providerSource into our internal TerraformProvider
+// model.
+func translateProviderSchemas(providerSource string, schemas *tfjson.ProviderSchemas) (*TerraformProvider, error) {
+	schema, ok := schemas.Schemas[providerSource]
+	if !ok {
+		return nil, fmt.Errorf("provider %q not present in schema output", providerSource)
+	}
+
+	provider := &TerraformProvider{
+		Name: filepath.Base(providerSource),
+	}
+
+	for name, resourceSchema := range schema.ResourceSchemas {
+		provider.Resources = append(provider.Resources, TerraformResource{
+			Name:       name,
+			Type:       name,
+			Arguments:  tfjsonBlockArguments(resourceSchema.Block),
+			Attributes: tfjsonBlockAttributes(resourceSchema.Block),
+		})
+	}
+
+	for name, dataSourceSchema := range schema.DataSourceSchemas {
+		provider.DataSources = append(provider.DataSources, TerraformDataSource{
+			Name:       name,
+			Type:       name,
+			Arguments:  tfjsonBlockArguments(dataSourceSchema.Block),
+			Attributes: tfjsonBlockAttributes(dataSourceSchema.Block),
+		})
+	}
+
+	return provider, nil
+}
+
+// tfjsonBlockArguments extracts non-computed-only attributes from a
+// tfjson.SchemaBlock into TerraformArgument entries.
+func tfjsonBlockArguments(block *tfjson.SchemaBlock) []TerraformArgument {
+	var args []TerraformArgument
+	for name, attr := range block.Attributes {
+		if attr.Computed && !attr.Required && !attr.Optional {
+			continue
+		}
+		args = append(args, TerraformArgument{
+			Name:        name,
+			Type:        fmt.Sprintf("%v", attr.AttributeType),
+			Description: attr.Description,
+			Required:    attr.Required,
+			Optional:    attr.Optional,
+			Computed:    attr.Computed,
+		})
+	}
+	return args
+}
+
+// tfjsonBlockAttributes extracts computed attributes from a
+// tfjson.SchemaBlock into TerraformAttribute entries.
+func tfjsonBlockAttributes(block *tfjson.SchemaBlock) []TerraformAttribute {
+	var attrs []TerraformAttribute
+	for name, attr := range block.Attributes {
+		if !attr.Computed {
+			continue
+		}
+		attrs = append(attrs, TerraformAttribute{
+			Name:        name,
+			Type:        fmt.Sprintf("%v", attr.AttributeType),
+			Description: attr.Description,
+			Computed:    attr.Computed,
+		})
+	}
+	return attrs
+}
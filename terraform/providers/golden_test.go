@@ -0,0 +1,135 @@
+package providers
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"golang.org/x/tools/txtar"
+)
+
+// update regenerates the golden archives instead of comparing against them,
+// mirroring the -update convention used by Go's own stdlib golden tests.
+var update = flag.Bool("update", false, "update golden files")
+
+// goldenProviders enumerates the built-in providers the golden harness
+// covers. Keep in sync with the DefaultRegistry registrations in
+// builtin.go.
+var goldenProviders = map[string]func() *TerraformProvider{
+	"cloudpods":  CloudPodsProvider,
+	"aviatrix":   AviatrixProvider,
+	"router-sim": RouterSimProvider,
+}
+
+// TestGoldenGeneratedFiles generates each built-in provider into a temp
+// dir, captures every produced file into a txtar archive, and diffs it
+// against testdata/golden/<provider>.txtar (regeneratable with -update).
+func TestGoldenGeneratedFiles(t *testing.T) {
+	for name, factory := range goldenProviders {
+		name, factory := name, factory
+		t.Run(name, func(t *testing.T) {
+			outputDir := t.TempDir()
+
+			if err := GenerateTerraformFiles(factory(), outputDir); err != nil {
+				t.Fatalf("GenerateTerraformFiles(%s) failed: %v", name, err)
+			}
+
+			archive, err := archiveDir(outputDir)
+			if err != nil {
+				t.Fatalf("failed to archive generated files: %v", err)
+			}
+
+			goldenPath := filepath.Join("testdata", "golden", name+".txtar")
+
+			if *update {
+				if err := os.WriteFile(goldenPath, txtar.Format(archive), 0644); err != nil {
+					t.Fatalf("failed to write golden file: %v", err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("failed to read golden file %s (run with -update to create it): %v", goldenPath, err)
+			}
+
+			got := txtar.Format(archive)
+			if string(got) != string(want) {
+				t.Errorf("generated files for %s do not match %s; run `go test -run TestGoldenGeneratedFiles -update` to refresh", name, goldenPath)
+			}
+		})
+	}
+}
+
+// TestGeneratedExamplesValidate shells out to `terraform validate` on the
+// example .tf files for each provider, so HCL regressions fail CI
+// immediately instead of only being caught by the golden text diff.
+func TestGeneratedExamplesValidate(t *testing.T) {
+	if _, err := exec.LookPath("terraform"); err != nil {
+		t.Skip("terraform binary not available on PATH")
+	}
+
+	for name, factory := range goldenProviders {
+		name, factory := name, factory
+		t.Run(name, func(t *testing.T) {
+			outputDir := t.TempDir()
+
+			if err := GenerateTerraformFiles(factory(), outputDir); err != nil {
+				t.Fatalf("GenerateTerraformFiles(%s) failed: %v", name, err)
+			}
+
+			diagnostics, err := Validate(context.Background(), outputDir, "")
+			if err != nil {
+				t.Fatalf("Validate(%s) failed: %v", name, err)
+			}
+
+			for _, diag := range diagnostics {
+				if diag.Severity == "error" {
+					t.Errorf("terraform validate reported an error in %s:%d: %s", diag.File, diag.Line, diag.Summary)
+				}
+			}
+		})
+	}
+}
+
+// archiveDir walks dir and captures every regular file into a txtar
+// archive, with paths relative to dir so the archive is stable across
+// different temp directory names.
+func archiveDir(dir string) (*txtar.Archive, error) {
+	var files []string
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+
+	archive := &txtar.Archive{}
+	for _, rel := range files {
+		data, err := os.ReadFile(filepath.Join(dir, rel))
+		if err != nil {
+			return nil, err
+		}
+		archive.Files = append(archive.Files, txtar.File{Name: filepath.ToSlash(rel), Data: data})
+	}
+
+	return archive, nil
+}
@@ -0,0 +1,68 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"text/template"
+)
+
+// ResourceMove describes a single Terraform `moved` block: a resource that
+// used to live at From and now lives at To, e.g. when renaming
+// cloudpods_vm to cloudpods_instance.
+type ResourceMove struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// SinceVersion is the provider version the rename first shipped in,
+	// recorded for changelog/docs purposes only.
+	SinceVersion string `json:"since_version,omitempty"`
+}
+
+// generateMovedTF writes a moved.tf file containing one `moved` block per
+// entry in provider.Migrations, so existing state is reattached to the new
+// address instead of forcing a destroy/recreate.
+func generateMovedTF(provider *TerraformProvider, outputDir string) error {
+	if len(provider.Migrations) == 0 {
+		return nil
+	}
+
+	tmpl := `{{range .Migrations}}
+moved {
+  from = {{.From}}
+  to   = {{.To}}
+}
+{{end}}
+`
+
+	t, err := template.New("moved").Parse(tmpl)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(filepath.Join(outputDir, "moved.tf"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return t.Execute(file, provider)
+}
+
+// MigrateState is the fallback path for Terraform versions/state backends
+// that don't support `moved` blocks, or for migrations whose target address
+// is only known at apply time. It mirrors each ResourceMove with a
+// `terraform state mv` invocation against workspaceDir.
+func MigrateState(ctx context.Context, workspaceDir string, migrations []ResourceMove) error {
+	for _, move := range migrations {
+		cmd := exec.CommandContext(ctx, "terraform", "state", "mv", move.From, move.To)
+		cmd.Dir = workspaceDir
+
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to move state %q -> %q: %v: %s", move.From, move.To, err, output)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,360 @@
+// Package providers holds the Terraform provider model shared by the
+// router-sim generator CLI (terraform/generator), the built-in
+// cloudpods/aviatrix/router-sim provider definitions, and anything that
+// renders or validates them. Splitting it out of the CLI's main package
+// lets it be imported by external provider packages and by Go plugins
+// loaded through the Registry in registry_providers.go.
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// TerraformProvider represents a Terraform provider configuration
+type TerraformProvider struct {
+	Name        string                 `json:"name"`
+	Version     string                 `json:"version"`
+	Description string                 `json:"description"`
+	Resources   []TerraformResource    `json:"resources"`
+	DataSources []TerraformDataSource  `json:"data_sources"`
+	Variables   []TerraformVariable    `json:"variables"`
+	Outputs     []TerraformOutput      `json:"outputs"`
+	Providers   map[string]interface{} `json:"providers"`
+	Migrations  []ResourceMove         `json:"migrations,omitempty"`
+	// RegistrySource overrides the `source` address in the generated
+	// `required_providers` block, e.g. to point at a running
+	// RegistryServer instead of the filesystem-mirror-style "local/<name>".
+	RegistrySource string `json:"registry_source,omitempty"`
+}
+
+// TerraformResource represents a Terraform resource
+type TerraformResource struct {
+	Name        string                 `json:"name"`
+	Type        string                 `json:"type"`
+	Description string                 `json:"description"`
+	Arguments   []TerraformArgument    `json:"arguments"`
+	Attributes  []TerraformAttribute   `json:"attributes"`
+	Required    []string               `json:"required"`
+	Optional    []string               `json:"optional"`
+	Computed    []string               `json:"computed"`
+	DependsOn   []string               `json:"depends_on"`
+	Lifecycle   map[string]interface{} `json:"lifecycle"`
+}
+
+// TerraformDataSource represents a Terraform data source
+type TerraformDataSource struct {
+	Name        string               `json:"name"`
+	Type        string               `json:"type"`
+	Description string               `json:"description"`
+	Arguments   []TerraformArgument  `json:"arguments"`
+	Attributes  []TerraformAttribute `json:"attributes"`
+}
+
+// TerraformArgument represents a Terraform argument
+type TerraformArgument struct {
+	Name        string      `json:"name"`
+	Type        string      `json:"type"`
+	Description string      `json:"description"`
+	Required    bool        `json:"required"`
+	Optional    bool        `json:"optional"`
+	Computed    bool        `json:"computed"`
+	Default     interface{} `json:"default"`
+	Validation  []string    `json:"validation"`
+	// Blocks carries a first-class nested block schema for this argument
+	// when it represents a list/set/map/single block of objects, rendered
+	// as a proper HCL block instead of a `var.<name>` reference. It takes
+	// precedence over Type when set; see NestedBlock.
+	Blocks []NestedBlock `json:"blocks,omitempty"`
+}
+
+// TerraformAttribute represents a Terraform attribute
+type TerraformAttribute struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Computed    bool   `json:"computed"`
+}
+
+// TerraformVariable represents a Terraform variable
+type TerraformVariable struct {
+	Name        string      `json:"name"`
+	Type        string      `json:"type"`
+	Description string      `json:"description"`
+	Default     interface{} `json:"default"`
+	Validation  []string    `json:"validation"`
+	Required    bool        `json:"required"`
+	Optional    bool        `json:"optional"`
+}
+
+// TerraformOutput represents a Terraform output
+type TerraformOutput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Value       string `json:"value"`
+	Sensitive   bool   `json:"sensitive"`
+}
+
+// GenerateTerraformFiles generates Terraform configuration files
+func GenerateTerraformFiles(provider *TerraformProvider, outputDir string) error {
+	return GenerateTerraformFilesWithOptions(provider, outputDir, GenerateOptions{})
+}
+
+// GenerateTerraformFilesWithOptions generates Terraform configuration files
+// and, when opted into via opts, post-processes outputDir with
+// `terraform fmt` and `terraform validate`.
+func GenerateTerraformFilesWithOptions(provider *TerraformProvider, outputDir string, opts GenerateOptions) error {
+	// Create output directory
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %v", err)
+	}
+
+	if opts.RegistryHost != "" {
+		provider.RegistrySource = fmt.Sprintf("%s/local/%s", opts.RegistryHost, provider.Name)
+	}
+
+	// Generate main.tf
+	if err := generateMainTF(provider, outputDir); err != nil {
+		return fmt.Errorf("failed to generate main.tf: %v", err)
+	}
+
+	// Generate variables.tf
+	if err := generateVariablesTF(provider, outputDir); err != nil {
+		return fmt.Errorf("failed to generate variables.tf: %v", err)
+	}
+
+	// Generate outputs.tf
+	if err := generateOutputsTF(provider, outputDir); err != nil {
+		return fmt.Errorf("failed to generate outputs.tf: %v", err)
+	}
+
+	// Generate provider schema
+	if err := generateProviderSchema(provider, outputDir); err != nil {
+		return fmt.Errorf("failed to generate provider schema: %v", err)
+	}
+
+	// Generate moved blocks for any renamed resources
+	if err := generateMovedTF(provider, outputDir); err != nil {
+		return fmt.Errorf("failed to generate moved.tf: %v", err)
+	}
+
+	// Generate examples (and docs, when opted into the tfplugindocs-style
+	// tree via opts.ProviderDir)
+	if opts.ProviderDir != "" {
+		examplesDir := opts.ExamplesDir
+		if examplesDir == "" {
+			examplesDir = filepath.Join(opts.ProviderDir, "examples")
+		}
+		if err := generateExamplesTree(provider, opts.ProviderDir, examplesDir); err != nil {
+			return fmt.Errorf("failed to generate examples/docs tree: %v", err)
+		}
+	} else if err := generateExamples(provider, outputDir); err != nil {
+		return fmt.Errorf("failed to generate examples: %v", err)
+	}
+
+	if opts.Format {
+		if err := Format(outputDir, opts.ExecPath); err != nil {
+			return fmt.Errorf("failed to format generated files: %v", err)
+		}
+	}
+
+	if opts.Validate {
+		diagnostics, err := Validate(context.Background(), outputDir, opts.ExecPath)
+		if err != nil {
+			return fmt.Errorf("failed to validate generated files: %v", err)
+		}
+
+		for _, diag := range diagnostics {
+			if diag.Severity == "error" {
+				return fmt.Errorf("terraform validate reported an error in %s:%d: %s", diag.File, diag.Line, diag.Summary)
+			}
+		}
+	}
+
+	return nil
+}
+
+// generateMainTF generates the main.tf file
+func generateMainTF(provider *TerraformProvider, outputDir string) error {
+	tmpl := `terraform {
+  required_providers {
+    {{.Name}} = {
+      source  = "{{if .RegistrySource}}{{.RegistrySource}}{{else}}local/{{.Name}}{{end}}"
+      version = "{{.Version}}"
+    }
+  }
+}
+
+{{range $key, $value := .Providers}}
+provider "{{$key}}" {
+{{range $k, $v := $value}}
+  {{$k}} = {{$v}}
+{{end}}
+}
+{{end}}
+
+{{range .Resources}}
+resource "{{.Type}}" "{{.Name}}" {
+  {{range .Arguments}}
+  {{if .Blocks}}
+{{renderBlocks .Blocks "  "}}
+  {{else if .Required}}
+  {{.Name}} = var.{{.Name}}
+  {{else if .Optional}}
+  {{.Name}} = var.{{.Name}}
+  {{end}}
+  {{end}}
+}
+{{end}}
+
+{{range .DataSources}}
+data "{{.Type}}" "{{.Name}}" {
+  {{range .Arguments}}
+  {{if .Required}}
+  {{.Name}} = var.{{.Name}}
+  {{end}}
+  {{end}}
+}
+{{end}}
+`
+
+	t, err := template.New("main").Funcs(template.FuncMap{
+		"renderBlocks": renderBlocks,
+	}).Parse(tmpl)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(filepath.Join(outputDir, "main.tf"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return t.Execute(file, provider)
+}
+
+// generateVariablesTF generates the variables.tf file
+func generateVariablesTF(provider *TerraformProvider, outputDir string) error {
+	tmpl := `{{range .Variables}}
+variable "{{.Name}}" {
+  type        = {{.Type}}
+  description = "{{.Description}}"
+  {{if .Default}}
+  default     = {{.Default}}
+  {{end}}
+  {{if .Validation}}
+  validation {
+    {{range .Validation}}
+    {{.}}
+    {{end}}
+  }
+  {{end}}
+}
+{{end}}
+`
+
+	t, err := template.New("variables").Parse(tmpl)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(filepath.Join(outputDir, "variables.tf"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return t.Execute(file, provider)
+}
+
+// generateOutputsTF generates the outputs.tf file
+func generateOutputsTF(provider *TerraformProvider, outputDir string) error {
+	tmpl := `{{range .Outputs}}
+output "{{.Name}}" {
+  description = "{{.Description}}"
+  value       = {{.Value}}
+  {{if .Sensitive}}
+  sensitive   = true
+  {{end}}
+}
+{{end}}
+`
+
+	t, err := template.New("outputs").Parse(tmpl)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(filepath.Join(outputDir, "outputs.tf"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return t.Execute(file, provider)
+}
+
+// generateProviderSchema generates the provider schema
+func generateProviderSchema(provider *TerraformProvider, outputDir string) error {
+	schema := map[string]interface{}{
+		"provider": map[string]interface{}{
+			"name":    provider.Name,
+			"version": provider.Version,
+			"resources": func() map[string]interface{} {
+				resources := make(map[string]interface{})
+				for _, resource := range provider.Resources {
+					resources[resource.Type] = resource
+				}
+				return resources
+			}(),
+			"data_sources": func() map[string]interface{} {
+				dataSources := make(map[string]interface{})
+				for _, dataSource := range provider.DataSources {
+					dataSources[dataSource.Type] = dataSource
+				}
+				return dataSources
+			}(),
+		},
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(outputDir, "provider_schema.json"), data, 0644)
+}
+
+// generateExamples generates example configurations
+func generateExamples(provider *TerraformProvider, outputDir string) error {
+	examplesDir := filepath.Join(outputDir, "examples")
+	if err := os.MkdirAll(examplesDir, 0755); err != nil {
+		return err
+	}
+
+	// Generate basic example
+	basicExample := fmt.Sprintf(`# Basic %s example
+terraform {
+  required_providers {
+    %s = {
+      source  = "local/%s"
+      version = "%s"
+    }
+  }
+}
+
+provider "%s" {
+  # Add provider configuration here
+}
+
+# Add resource examples here
+`, provider.Name, provider.Name, provider.Name, provider.Version, provider.Name)
+
+	return ioutil.WriteFile(filepath.Join(examplesDir, "basic.tf"), []byte(basicExample), 0644)
+}
@@ -0,0 +1,136 @@
+package providers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NestedBlock is a first-class, recursive description of a nested HCL
+// block (e.g. `listener { ... }` inside `cloudpods_load_balancer`), used in
+// place of the stringly-typed `list(object)`/`map(object)`/`object` type
+// hints that previously collapsed onto a single `var.<name>` reference in
+// main.tf.
+type NestedBlock struct {
+	Name        string               `json:"name"`
+	NestingMode NestingMode          `json:"nesting_mode"`
+	Description string               `json:"description,omitempty"`
+	MinItems    int                  `json:"min_items,omitempty"`
+	MaxItems    int                  `json:"max_items,omitempty"`
+	Arguments   []TerraformArgument  `json:"arguments,omitempty"`
+	Attributes  []TerraformAttribute `json:"attributes,omitempty"`
+	Blocks      []NestedBlock        `json:"blocks,omitempty"`
+}
+
+// Blocks, when set on a TerraformArgument, takes precedence over the Type
+// string for rendering and schema export, letting a single argument carry
+// a fully recursive nested schema instead of an opaque "list(object)" hint.
+
+// argumentTypeFromNesting renders a NestedBlock's nesting mode back into
+// the flat type-hint strings the rest of the generator already
+// understands, so code that only knows about the flat form keeps working.
+func argumentTypeFromNesting(mode NestingMode) string {
+	switch mode {
+	case NestingModeList:
+		return "list(object)"
+	case NestingModeSet:
+		return "set(object)"
+	case NestingModeMap:
+		return "map(object)"
+	default:
+		return "object"
+	}
+}
+
+// ToFlatType converts a NestedBlock back into the legacy flat type string
+// for an argument, for callers that only read TerraformArgument.Type.
+func (b *NestedBlock) ToFlatType() string {
+	return argumentTypeFromNesting(b.NestingMode)
+}
+
+// NestedBlockFromArgument derives a NestedBlock from a legacy flat
+// TerraformArgument when the argument doesn't already carry one, by
+// parsing its Type string. It returns nil for plain scalar/collection
+// types that aren't block-shaped.
+func NestedBlockFromArgument(arg TerraformArgument) *NestedBlock {
+	if blockType, ok := asBlockType(arg.Type); ok {
+		return &NestedBlock{
+			Name:        arg.Name,
+			NestingMode: blockType.NestingMode,
+			Description: arg.Description,
+			MinItems:    blockType.MinItems,
+			MaxItems:    blockType.MaxItems,
+		}
+	}
+	return nil
+}
+
+// nestedBlockSchema converts a NestedBlock into the recursive
+// SchemaBlockType used by GenerateProvidersSchemaJSON, preserving
+// nesting_mode, min/max items and any further nested Blocks.
+func nestedBlockSchema(nb NestedBlock) *SchemaBlockType {
+	inner := &SchemaBlock{
+		Attributes: make(map[string]*SchemaAttribute),
+		BlockTypes: make(map[string]*SchemaBlockType),
+	}
+
+	for _, arg := range nb.Arguments {
+		inner.Attributes[arg.Name] = &SchemaAttribute{
+			Type:        tftypesJSON(arg.Type),
+			Description: arg.Description,
+			Required:    arg.Required,
+			Optional:    arg.Optional,
+			Computed:    arg.Computed,
+		}
+	}
+
+	for _, attr := range nb.Attributes {
+		inner.Attributes[attr.Name] = &SchemaAttribute{
+			Type:        tftypesJSON(attr.Type),
+			Description: attr.Description,
+			Computed:    attr.Computed,
+		}
+	}
+
+	for _, child := range nb.Blocks {
+		inner.BlockTypes[child.Name] = nestedBlockSchema(child)
+	}
+
+	return &SchemaBlockType{
+		NestingMode: nb.NestingMode,
+		Block:       inner,
+		MinItems:    nb.MinItems,
+		MaxItems:    nb.MaxItems,
+	}
+}
+
+// renderBlocks renders a slice of NestedBlock as HCL, for use as a
+// text/template func in generateMainTF.
+func renderBlocks(blocks []NestedBlock, indent string) string {
+	var b strings.Builder
+	for _, block := range blocks {
+		b.WriteString(renderBlockHCL(block, indent))
+	}
+	return b.String()
+}
+
+// renderBlockHCL renders a NestedBlock (and its descendants) as HCL block
+// syntax for main.tf, e.g.:
+//
+//	listener {
+//	  protocol = var.protocol
+//	  port     = var.port
+//	}
+func renderBlockHCL(block NestedBlock, indent string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s%s {\n", indent, block.Name)
+	for _, arg := range block.Arguments {
+		fmt.Fprintf(&b, "%s  %s = var.%s\n", indent, arg.Name, arg.Name)
+	}
+	for _, nested := range block.Blocks {
+		b.WriteString(renderBlockHCL(nested, indent+"  "))
+	}
+	fmt.Fprintf(&b, "%s}\n", indent)
+
+	return b.String()
+}
@@ -0,0 +1,57 @@
+//go:build linux || darwin
+
+package providers
+
+import (
+	"fmt"
+	"path/filepath"
+	"plugin"
+)
+
+// LoadPlugins scans dir for *.so files and registers each one's provider
+// into reg. A plugin must export a `Provider` symbol of type
+// `func() *providers.TerraformProvider` and a `Name` symbol of type
+// `string`; LoadPlugins registers Provider under Name, same as an in-tree
+// provider registering itself in init().
+func LoadPlugins(reg *Registry, dir string) error {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return fmt.Errorf("failed to scan plugin directory %s: %w", dir, err)
+	}
+
+	for _, path := range matches {
+		if err := loadPlugin(reg, path); err != nil {
+			return fmt.Errorf("failed to load plugin %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func loadPlugin(reg *Registry, path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return err
+	}
+
+	nameSym, err := p.Lookup("Name")
+	if err != nil {
+		return fmt.Errorf("missing Name symbol: %w", err)
+	}
+	name, ok := nameSym.(*string)
+	if !ok {
+		return fmt.Errorf("Name symbol has unexpected type %T, want *string", nameSym)
+	}
+
+	providerSym, err := p.Lookup("Provider")
+	if err != nil {
+		return fmt.Errorf("missing Provider symbol: %w", err)
+	}
+	factory, ok := providerSym.(func() *TerraformProvider)
+	if !ok {
+		return fmt.Errorf("Provider symbol has unexpected type %T, want func() *providers.TerraformProvider", providerSym)
+	}
+
+	reg.Register(*name, factory)
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build !(linux || darwin)
+
+package providers
+
+import "fmt"
+
+// LoadPlugins reports an error on platforms where Go's plugin package
+// isn't supported (notably Windows); built-in providers registered via
+// init() are unaffected.
+func LoadPlugins(reg *Registry, dir string) error {
+	return fmt.Errorf("loading provider plugins from %s is not supported on this platform", dir)
+}
@@ -0,0 +1,205 @@
+package providers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RegistryServer implements just enough of Terraform's service-discovery
+// and `providers.v1` protocols for `terraform init` to resolve
+// `local/<name>` addresses against a directory of built plugin binaries,
+// without needing a filesystem mirror.
+type RegistryServer struct {
+	// PluginDir holds built provider binaries named
+	// terraform-provider-<name>_v<version>_<os>_<arch>.
+	PluginDir string
+	// Host is this server's externally reachable host:port, used to
+	// build absolute download URLs.
+	Host string
+}
+
+// Mux builds the http.Handler exposing the registry endpoints.
+func (s *RegistryServer) Mux() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/terraform.json", s.handleDiscovery)
+	mux.HandleFunc("/v1/providers/", s.handleProviders)
+	return mux
+}
+
+// handleDiscovery serves Terraform's service discovery document,
+// advertising this server as a providers.v1 endpoint.
+func (s *RegistryServer) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]string{
+		"providers.v1": "/v1/providers/",
+	})
+}
+
+// handleProviders routes the two providers.v1 paths this server supports:
+//
+//	/v1/providers/<ns>/<name>/versions
+//	/v1/providers/<ns>/<name>/<ver>/download/<os>/<arch>
+func (s *RegistryServer) handleProviders(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/providers/"), "/")
+
+	switch {
+	case len(parts) == 3 && parts[2] == "versions":
+		s.handleVersions(w, parts[0], parts[1])
+	case len(parts) == 6 && parts[3] == "download":
+		s.handleDownload(w, parts[0], parts[1], parts[2], parts[4], parts[5])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// pluginVersions scans PluginDir for binaries matching
+// terraform-provider-<name>_v<version>_<os>_<arch> and returns the
+// distinct (version, os, arch) triples available for name.
+func (s *RegistryServer) pluginVersions(name string) ([]providerPlatform, error) {
+	entries, err := os.ReadDir(s.PluginDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin dir: %w", err)
+	}
+
+	prefix := fmt.Sprintf("terraform-provider-%s_v", name)
+	var platforms []providerPlatform
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+
+		rest := strings.TrimPrefix(entry.Name(), prefix)
+		fields := strings.Split(rest, "_")
+		if len(fields) != 3 {
+			continue
+		}
+
+		platforms = append(platforms, providerPlatform{
+			Version:  fields[0],
+			OS:       fields[1],
+			Arch:     fields[2],
+			Filename: entry.Name(),
+		})
+	}
+
+	return platforms, nil
+}
+
+type providerPlatform struct {
+	Version  string
+	OS       string
+	Arch     string
+	Filename string
+}
+
+func (s *RegistryServer) handleVersions(w http.ResponseWriter, ns, name string) {
+	platforms, err := s.pluginVersions(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	byVersion := map[string][]map[string]string{}
+	for _, p := range platforms {
+		byVersion[p.Version] = append(byVersion[p.Version], map[string]string{
+			"os":   p.OS,
+			"arch": p.Arch,
+		})
+	}
+
+	var versions []map[string]interface{}
+	for version, platformList := range byVersion {
+		versions = append(versions, map[string]interface{}{
+			"version":   version,
+			"protocols": []string{"5.0"},
+			"platforms": platformList,
+		})
+	}
+
+	writeJSON(w, map[string]interface{}{"versions": versions})
+}
+
+func (s *RegistryServer) handleDownload(w http.ResponseWriter, ns, name, version, goos, arch string) {
+	platforms, err := s.pluginVersions(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var match *providerPlatform
+	for i, p := range platforms {
+		if p.Version == version && p.OS == goos && p.Arch == arch {
+			match = &platforms[i]
+			break
+		}
+	}
+	if match == nil {
+		http.NotFound(w, r404(ns, name, version, goos, arch))
+		return
+	}
+
+	binaryPath := filepath.Join(s.PluginDir, match.Filename)
+	sum, err := sha256File(binaryPath)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	zipName := fmt.Sprintf("terraform-provider-%s_%s_%s_%s.zip", name, version, goos, arch)
+	downloadURL := fmt.Sprintf("http://%s/downloads/%s/%s", s.Host, name, zipName)
+
+	writeJSON(w, map[string]interface{}{
+		"protocols":             []string{"5.0"},
+		"os":                    goos,
+		"arch":                  arch,
+		"filename":              zipName,
+		"download_url":          downloadURL,
+		"shasums_url":           fmt.Sprintf("http://%s/downloads/%s/SHA256SUMS", s.Host, name),
+		"shasums_signature_url": fmt.Sprintf("http://%s/downloads/%s/SHA256SUMS.sig", s.Host, name),
+		"shasum":                sum,
+		"signing_keys": map[string]interface{}{
+			"gpg_public_keys": []map[string]string{
+				{
+					"key_id":          "ROUTERSIM-LOCAL",
+					"ascii_armor":     "",
+					"trust_signature": "",
+				},
+			},
+		},
+	})
+}
+
+// r404 is a throwaway *http.Request satisfying http.NotFound's signature
+// for the not-found branch above; only its method/URL are inspected by
+// net/http's default handler.
+func r404(ns, name, version, goos, arch string) *http.Request {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/v1/providers/%s/%s/%s/download/%s/%s", ns, name, version, goos, arch), nil)
+	return req
+}
+
+func sha256File(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read plugin binary: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// ServeRegistry starts the registry HTTP server, blocking until it exits.
+func ServeRegistry(addr, pluginDir string) error {
+	server := &RegistryServer{PluginDir: pluginDir, Host: addr}
+	fmt.Printf("Serving terraform provider registry for %s on http://%s\n", pluginDir, addr)
+	return http.ListenAndServe(addr, server.Mux())
+}
@@ -0,0 +1,52 @@
+package providers
+
+import "sort"
+
+// Factory builds a fresh TerraformProvider definition. Built-in providers
+// and anything loaded from a plugin directory via LoadPlugins register a
+// Factory under their provider name so callers never need a type switch on
+// provider name to pick one.
+type Factory func() *TerraformProvider
+
+// Registry maps provider names to the Factory that builds them, letting
+// third parties add providers (in-tree via init(), or out-of-tree via a Go
+// plugin loaded through LoadPlugins) without forking the generator.
+type Registry struct {
+	factories map[string]Factory
+}
+
+// DefaultRegistry is the process-wide Registry that the built-in providers
+// register themselves against in init(), and that the generator CLI looks
+// providers up in by default.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// Register adds factory under name, overwriting any existing registration
+// for that name.
+func (r *Registry) Register(name string, factory Factory) {
+	r.factories[name] = factory
+}
+
+// Lookup returns the provider built by name's Factory, or false if no
+// Factory has been registered under that name.
+func (r *Registry) Lookup(name string) (*TerraformProvider, bool) {
+	factory, ok := r.factories[name]
+	if !ok {
+		return nil, false
+	}
+	return factory(), true
+}
+
+// Names returns the registered provider names in sorted order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
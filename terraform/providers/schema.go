@@ -0,0 +1,297 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// providerSchemaFormatVersion is the format_version emitted in the schema
+// document, matching what `terraform providers schema -json` produces.
+const providerSchemaFormatVersion = "1.0"
+
+// NestingMode mirrors Terraform's block nesting modes for block_types.
+type NestingMode string
+
+const (
+	NestingModeSingle NestingMode = "single"
+	NestingModeList   NestingMode = "list"
+	NestingModeSet    NestingMode = "set"
+	NestingModeMap    NestingMode = "map"
+)
+
+// SchemaAttribute is a single leaf attribute inside a SchemaBlock.
+type SchemaAttribute struct {
+	Type        interface{} `json:"type"`
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Optional    bool        `json:"optional,omitempty"`
+	Computed    bool        `json:"computed,omitempty"`
+	Sensitive   bool        `json:"sensitive,omitempty"`
+}
+
+// SchemaBlockType is a nested block inside a SchemaBlock's block_types map.
+type SchemaBlockType struct {
+	NestingMode NestingMode  `json:"nesting_mode"`
+	Block       *SchemaBlock `json:"block"`
+	MinItems    int          `json:"min_items,omitempty"`
+	MaxItems    int          `json:"max_items,omitempty"`
+}
+
+// SchemaBlock is the recursive `block` object Terraform tooling expects:
+// a flat set of attributes plus nested block_types.
+type SchemaBlock struct {
+	Attributes map[string]*SchemaAttribute `json:"attributes,omitempty"`
+	BlockTypes map[string]*SchemaBlockType `json:"block_types,omitempty"`
+}
+
+// ResourceSchema wraps a single resource/data-source's block.
+type ResourceSchema struct {
+	Version int          `json:"version"`
+	Block   *SchemaBlock `json:"block"`
+}
+
+// ProviderSchemaEntry is the per-provider section of the schema document.
+type ProviderSchemaEntry struct {
+	Provider          *ResourceSchema            `json:"provider,omitempty"`
+	ResourceSchemas   map[string]*ResourceSchema `json:"resource_schemas,omitempty"`
+	DataSourceSchemas map[string]*ResourceSchema `json:"data_source_schemas,omitempty"`
+}
+
+// ProvidersSchema is the top-level document, compatible with the output of
+// `terraform providers schema -json`.
+type ProvidersSchema struct {
+	FormatVersion   string                          `json:"format_version"`
+	ProviderSchemas map[string]*ProviderSchemaEntry `json:"provider_schemas"`
+}
+
+// GenerateProvidersSchemaJSON walks provider and writes a
+// `terraform providers schema -json` compatible document to path. Unlike
+// generateProviderSchema, this maps our list(object)/map(object) argument
+// and attribute types into real nested block_types instead of leaving them
+// as opaque type strings, so tfschema/tflint/docs pipelines can consume it
+// directly.
+func GenerateProvidersSchemaJSON(provider *TerraformProvider, path string) error {
+	sourceAddr := fmt.Sprintf("registry.terraform.io/local/%s", provider.Name)
+
+	entry := &ProviderSchemaEntry{
+		ResourceSchemas:   make(map[string]*ResourceSchema),
+		DataSourceSchemas: make(map[string]*ResourceSchema),
+	}
+
+	for _, resource := range provider.Resources {
+		entry.ResourceSchemas[resource.Type] = &ResourceSchema{
+			Version: 0,
+			Block:   resourceBlock(resource.Arguments, resource.Attributes),
+		}
+	}
+
+	for _, dataSource := range provider.DataSources {
+		entry.DataSourceSchemas[dataSource.Type] = &ResourceSchema{
+			Version: 0,
+			Block:   resourceBlock(dataSource.Arguments, dataSource.Attributes),
+		}
+	}
+
+	doc := &ProvidersSchema{
+		FormatVersion: providerSchemaFormatVersion,
+		ProviderSchemas: map[string]*ProviderSchemaEntry{
+			sourceAddr: entry,
+		},
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal provider schema: %v", err)
+	}
+
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write provider schema: %v", err)
+	}
+
+	return nil
+}
+
+// resourceBlock builds the top-level SchemaBlock for a resource or data
+// source from our flat argument/attribute lists, splitting out
+// list(object)/map(object)/object typed entries into nested block_types.
+func resourceBlock(arguments []TerraformArgument, attributes []TerraformAttribute) *SchemaBlock {
+	block := &SchemaBlock{
+		Attributes: make(map[string]*SchemaAttribute),
+		BlockTypes: make(map[string]*SchemaBlockType),
+	}
+
+	for _, arg := range arguments {
+		if len(arg.Blocks) > 0 {
+			// A single argument with Blocks set represents one nested
+			// block type; recurse into it for the full schema instead of
+			// falling back to the flat type-string heuristic.
+			block.BlockTypes[arg.Name] = nestedBlockSchema(arg.Blocks[0])
+			continue
+		}
+
+		if blockType, ok := asBlockType(arg.Type); ok {
+			blockType.Block.Attributes["_"] = &SchemaAttribute{Type: "string", Computed: true}
+			block.BlockTypes[arg.Name] = blockType
+			continue
+		}
+
+		block.Attributes[arg.Name] = &SchemaAttribute{
+			Type:        tftypesJSON(arg.Type),
+			Description: arg.Description,
+			Required:    arg.Required,
+			Optional:    arg.Optional,
+			Computed:    arg.Computed,
+		}
+	}
+
+	for _, attr := range attributes {
+		if blockType, ok := asBlockType(attr.Type); ok {
+			blockType.Block.Attributes["_"] = &SchemaAttribute{Type: "string", Computed: true}
+			block.BlockTypes[attr.Name] = blockType
+			continue
+		}
+
+		block.Attributes[attr.Name] = &SchemaAttribute{
+			Type:        tftypesJSON(attr.Type),
+			Description: attr.Description,
+			Computed:    attr.Computed,
+		}
+	}
+
+	return block
+}
+
+// asBlockType converts our "list(object)", "map(object)" and "object" type
+// strings into a nested SchemaBlockType. It returns false for plain
+// primitive and collection-of-primitive types, which stay as attributes.
+func asBlockType(typeStr string) (*SchemaBlockType, bool) {
+	switch {
+	case typeStr == "object":
+		return &SchemaBlockType{
+			NestingMode: NestingModeSingle,
+			Block:       &SchemaBlock{Attributes: make(map[string]*SchemaAttribute), BlockTypes: make(map[string]*SchemaBlockType)},
+			MinItems:    1,
+			MaxItems:    1,
+		}, true
+	case strings.HasPrefix(typeStr, "list(object") || typeStr == "list(object)":
+		return &SchemaBlockType{
+			NestingMode: NestingModeList,
+			Block:       &SchemaBlock{Attributes: make(map[string]*SchemaAttribute), BlockTypes: make(map[string]*SchemaBlockType)},
+		}, true
+	case strings.HasPrefix(typeStr, "set(object") || typeStr == "set(object)":
+		return &SchemaBlockType{
+			NestingMode: NestingModeSet,
+			Block:       &SchemaBlock{Attributes: make(map[string]*SchemaAttribute), BlockTypes: make(map[string]*SchemaBlockType)},
+		}, true
+	case strings.HasPrefix(typeStr, "map(object") || typeStr == "map(object)":
+		return &SchemaBlockType{
+			NestingMode: NestingModeMap,
+			Block:       &SchemaBlock{Attributes: make(map[string]*SchemaAttribute), BlockTypes: make(map[string]*SchemaBlockType)},
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+// tftypesJSON maps our simplified type strings onto the JSON type
+// representation Terraform core uses in schema documents: bare strings for
+// primitives, and ["list", elem]/["map", elem]/["set", elem] for simple
+// collections of primitives.
+func tftypesJSON(typeStr string) interface{} {
+	switch {
+	case strings.HasPrefix(typeStr, "list(") && strings.HasSuffix(typeStr, ")"):
+		return []interface{}{"list", tftypesJSON(strings.TrimSuffix(strings.TrimPrefix(typeStr, "list("), ")"))}
+	case strings.HasPrefix(typeStr, "set(") && strings.HasSuffix(typeStr, ")"):
+		return []interface{}{"set", tftypesJSON(strings.TrimSuffix(strings.TrimPrefix(typeStr, "set("), ")"))}
+	case strings.HasPrefix(typeStr, "map(") && strings.HasSuffix(typeStr, ")"):
+		return []interface{}{"map", tftypesJSON(strings.TrimSuffix(strings.TrimPrefix(typeStr, "map("), ")"))}
+	case typeStr == "":
+		return "string"
+	default:
+		return typeStr
+	}
+}
+
+// LoadProvidersSchemaJSON reads a `terraform providers schema -json`
+// compatible document written by GenerateProvidersSchemaJSON and
+// round-trips it back into a TerraformProvider, so consumers that only
+// have the schema file can still point tooling at our provider shape.
+func LoadProvidersSchemaJSON(path string, providerName string) (*TerraformProvider, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provider schema: %v", err)
+	}
+
+	var doc ProvidersSchema
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse provider schema: %v", err)
+	}
+
+	sourceAddr := fmt.Sprintf("registry.terraform.io/local/%s", providerName)
+	entry, ok := doc.ProviderSchemas[sourceAddr]
+	if !ok {
+		return nil, fmt.Errorf("provider %q not found in schema document", providerName)
+	}
+
+	provider := &TerraformProvider{Name: providerName}
+
+	for resourceType, schema := range entry.ResourceSchemas {
+		provider.Resources = append(provider.Resources, TerraformResource{
+			Name:       resourceType,
+			Type:       resourceType,
+			Arguments:  blockArguments(schema.Block),
+			Attributes: blockAttributes(schema.Block),
+		})
+	}
+
+	for dataSourceType, schema := range entry.DataSourceSchemas {
+		provider.DataSources = append(provider.DataSources, TerraformDataSource{
+			Name:       dataSourceType,
+			Type:       dataSourceType,
+			Arguments:  blockArguments(schema.Block),
+			Attributes: blockAttributes(schema.Block),
+		})
+	}
+
+	return provider, nil
+}
+
+// blockArguments extracts the non-computed-only attributes of a block back
+// into TerraformArgument entries.
+func blockArguments(block *SchemaBlock) []TerraformArgument {
+	var args []TerraformArgument
+	for name, attr := range block.Attributes {
+		if attr.Computed && !attr.Required && !attr.Optional {
+			continue
+		}
+		args = append(args, TerraformArgument{
+			Name:        name,
+			Type:        fmt.Sprintf("%v", attr.Type),
+			Description: attr.Description,
+			Required:    attr.Required,
+			Optional:    attr.Optional,
+			Computed:    attr.Computed,
+		})
+	}
+	return args
+}
+
+// blockAttributes extracts the computed attributes of a block back into
+// TerraformAttribute entries.
+func blockAttributes(block *SchemaBlock) []TerraformAttribute {
+	var attrs []TerraformAttribute
+	for name, attr := range block.Attributes {
+		if !attr.Computed {
+			continue
+		}
+		attrs = append(attrs, TerraformAttribute{
+			Name:        name,
+			Type:        fmt.Sprintf("%v", attr.Type),
+			Description: attr.Description,
+			Computed:    attr.Computed,
+		})
+	}
+	return attrs
+}
@@ -0,0 +1,111 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+)
+
+// GenerateOptions controls optional post-processing steps applied by
+// GenerateTerraformFiles after the .tf files have been written.
+type GenerateOptions struct {
+	// Format runs `terraform fmt -recursive` over outputDir once generation
+	// completes, to clean up the inconsistent indentation and stray blank
+	// lines left by the {{range}} loops in the main.tf/variables.tf
+	// templates.
+	Format bool
+	// Validate runs `terraform init -backend=false` followed by
+	// `terraform validate -json` over outputDir and fails generation if any
+	// error-severity diagnostics are reported.
+	Validate bool
+	// ExecPath is the path to the terraform binary, defaulting to
+	// "terraform" resolved from PATH when empty.
+	ExecPath string
+	// ProviderDir, when set, switches example/docs generation to the
+	// tfplugindocs-style tree (generateExamplesTree) rooted at this
+	// directory, which is also where a templates/ override tree is read
+	// from. Leave empty to keep the legacy single examples/basic.tf stub.
+	ProviderDir string
+	// ExamplesDir overrides where the examples/ tree is written when
+	// ProviderDir is set, defaulting to <ProviderDir>/examples.
+	ExamplesDir string
+	// RegistryHost, when set, points the generated required_providers
+	// source at a running RegistryServer (e.g. "localhost:8085") instead
+	// of "local/<name>", so `terraform init && terraform apply` works
+	// out of the box against it.
+	RegistryHost string
+}
+
+// Diagnostic is a single structured entry from `terraform validate -json`,
+// trimmed down to what callers need to fail CI cleanly.
+type Diagnostic struct {
+	Severity string `json:"severity"`
+	Summary  string `json:"summary"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// Format invokes `terraform fmt -recursive` over outputDir.
+func Format(outputDir string, execPath string) error {
+	tf, err := newTfexec(outputDir, execPath)
+	if err != nil {
+		return err
+	}
+
+	if err := tf.FormatWrite(context.Background(), tfexec.Recursive(true)); err != nil {
+		return fmt.Errorf("terraform fmt failed: %w", err)
+	}
+
+	return nil
+}
+
+// Validate runs `terraform init -backend=false` followed by
+// `terraform validate -json` over outputDir and returns the diagnostics
+// terraform reported, regardless of severity. Callers should fail CI when
+// any entry has Severity == "error".
+func Validate(ctx context.Context, outputDir string, execPath string) ([]Diagnostic, error) {
+	tf, err := newTfexec(outputDir, execPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tf.Init(ctx, tfexec.Backend(false)); err != nil {
+		return nil, fmt.Errorf("terraform init -backend=false failed: %w", err)
+	}
+
+	result, err := tf.Validate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("terraform validate failed: %w", err)
+	}
+
+	diagnostics := make([]Diagnostic, 0, len(result.Diagnostics))
+	for _, d := range result.Diagnostics {
+		diag := Diagnostic{
+			Severity: string(d.Severity),
+			Summary:  d.Summary,
+		}
+		if d.Range != nil {
+			diag.File = d.Range.Filename
+			diag.Line = d.Range.Start.Line
+		}
+		diagnostics = append(diagnostics, diag)
+	}
+
+	return diagnostics, nil
+}
+
+// newTfexec builds a tfexec.Terraform pointed at outputDir, defaulting
+// execPath to "terraform" when unset.
+func newTfexec(outputDir, execPath string) (*tfexec.Terraform, error) {
+	if execPath == "" {
+		execPath = "terraform"
+	}
+
+	tf, err := tfexec.NewTerraform(outputDir, execPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create terraform executor: %w", err)
+	}
+
+	return tf, nil
+}